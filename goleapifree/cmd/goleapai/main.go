@@ -0,0 +1,14 @@
+// Command goleapai is the operator CLI for the goleapifree gateway.
+package main
+
+import (
+	"os"
+
+	"github.com/biodoia/goleapifree/internal/cli"
+)
+
+func main() {
+	if err := cli.NewRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
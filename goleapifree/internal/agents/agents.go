@@ -0,0 +1,61 @@
+// Package agents implements the gateway's built-in task agents: small
+// multi-step pipelines (translate, code, summarize) that run on top of
+// the provider adapters with verification loops around the raw model
+// calls.
+package agents
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// ChatFunc issues one chat completion. Agents depend on this narrow
+// function instead of a concrete adapter so the router, retries and
+// tests can all slot in.
+type ChatFunc func(ctx context.Context, req providers.ChatRequest) (*providers.ChatResponse, error)
+
+// PromptRegistry stores per-tenant prompt assets (templates, glossaries,
+// style guides) keyed by name. Tenant-specific entries shadow the ""
+// (global) tenant.
+type PromptRegistry struct {
+	mu      sync.RWMutex
+	entries map[string]string // tenant + "\x00" + name -> content
+}
+
+// NewPromptRegistry returns an empty registry.
+func NewPromptRegistry() *PromptRegistry {
+	return &PromptRegistry{entries: make(map[string]string)}
+}
+
+// Set stores an asset for a tenant; tenant "" is the global fallback.
+func (r *PromptRegistry) Set(tenant, name, content string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[tenant+"\x00"+name] = content
+}
+
+// Get resolves an asset for a tenant, falling back to the global entry.
+func (r *PromptRegistry) Get(tenant, name string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if v, ok := r.entries[tenant+"\x00"+name]; ok {
+		return v, true
+	}
+	v, ok := r.entries["\x00"+name]
+	return v, ok
+}
+
+// completion extracts the assistant text from a response, with a uniform
+// error for empty results.
+func completion(resp *providers.ChatResponse, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	if resp == nil || resp.Content == "" {
+		return "", fmt.Errorf("agents: empty completion")
+	}
+	return resp.Content, nil
+}
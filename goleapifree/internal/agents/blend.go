@@ -0,0 +1,141 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// Blender is the creative-writing agent: two voice models each draft a
+// continuation of the text so far, and a director model weaves the pair
+// into one passage per segment. The blend recipe — who wrote what, in
+// which round — travels with the result so users can see the mix.
+type Blender struct {
+	Chat ChatFunc
+	// VoiceA and VoiceB are the two models drafting continuations.
+	VoiceA string
+	VoiceB string
+	// Director is the model that blends each pair of drafts.
+	Director string
+	// Segments is how many draft/blend rounds compose the result;
+	// defaults to 1.
+	Segments int
+	// Style, when set, is appended to every voice prompt (genre, tone,
+	// point of view).
+	Style string
+	// Stop, when set, is checked after every blended segment; a triggered
+	// condition ends the run with the segments written so far.
+	Stop *StopChecker
+}
+
+// BlendSegment is one round of the recipe: both drafts and the passage
+// the director made of them.
+type BlendSegment struct {
+	Index   int    `json:"index"`
+	DraftA  string `json:"draft_a"`
+	DraftB  string `json:"draft_b"`
+	Blended string `json:"blended"`
+}
+
+// BlendRecipe documents how a blended result was assembled.
+type BlendRecipe struct {
+	VoiceA   string         `json:"voice_a"`
+	VoiceB   string         `json:"voice_b"`
+	Director string         `json:"director"`
+	Segments []BlendSegment `json:"segments"`
+}
+
+// BlendResult is the final text together with its recipe.
+type BlendResult struct {
+	Text   string      `json:"text"`
+	Recipe BlendRecipe `json:"recipe"`
+	// Stopped is set when a stop condition cut the run short; Text holds
+	// the segments blended up to that point.
+	Stopped *Stop `json:"stopped,omitempty"`
+}
+
+// Blend continues the prompt for the configured number of segments. Each
+// segment asks both voices for a continuation of everything written so
+// far, then has the director interleave the pair; later segments build on
+// the blended text, not the raw drafts.
+func (b *Blender) Blend(ctx context.Context, prompt string) (*BlendResult, error) {
+	if b.VoiceA == "" || b.VoiceB == "" || b.Director == "" {
+		return nil, fmt.Errorf("agents: blender needs two voice models and a director")
+	}
+	segments := b.Segments
+	if segments == 0 {
+		segments = 1
+	}
+	result := &BlendResult{Recipe: BlendRecipe{VoiceA: b.VoiceA, VoiceB: b.VoiceB, Director: b.Director}}
+	story := prompt
+	for i := 0; i < segments; i++ {
+		draftA, tokA, err := b.continueOnce(ctx, b.VoiceA, story)
+		if err != nil {
+			return nil, err
+		}
+		draftB, tokB, err := b.continueOnce(ctx, b.VoiceB, story)
+		if err != nil {
+			return nil, err
+		}
+		blended, tokDir, err := b.direct(ctx, story, draftA, draftB)
+		if err != nil {
+			return nil, err
+		}
+		result.Recipe.Segments = append(result.Recipe.Segments, BlendSegment{
+			Index: i, DraftA: draftA, DraftB: draftB, Blended: blended,
+		})
+		story += "\n\n" + blended
+		if result.Text != "" {
+			result.Text += "\n\n"
+		}
+		result.Text += blended
+		if stop := b.Stop.Check(ctx, blended, tokA+tokB+tokDir); stop != nil {
+			result.Stopped = stop
+			return result, nil
+		}
+	}
+	return result, nil
+}
+
+// continueOnce asks one voice model for a continuation.
+func (b *Blender) continueOnce(ctx context.Context, model, story string) (string, int64, error) {
+	sys := "Continue the user's text in your own voice. Write one passage and reply with the continuation only."
+	if b.Style != "" {
+		sys += "\nStyle notes:\n" + b.Style
+	}
+	return b.chatOnce(ctx, providers.ChatRequest{
+		Model: model,
+		Messages: []providers.Message{
+			{Role: "system", Content: sys},
+			{Role: "user", Content: story},
+		},
+	})
+}
+
+// direct has the director blend two drafts into one passage.
+func (b *Blender) direct(ctx context.Context, story, draftA, draftB string) (string, int64, error) {
+	var user strings.Builder
+	user.WriteString("Text so far:\n" + story)
+	user.WriteString("\n\nContinuation A:\n" + draftA)
+	user.WriteString("\n\nContinuation B:\n" + draftB)
+	return b.chatOnce(ctx, providers.ChatRequest{
+		Model: b.Director,
+		Messages: []providers.Message{
+			{Role: "system", Content: "You direct a two-writer collaboration. Blend continuation A and continuation B into one seamless passage, interleaving the strongest lines of each and smoothing the seams. Reply with the blended passage only."},
+			{Role: "user", Content: user.String()},
+		},
+	})
+}
+
+// chatOnce issues one completion, keeping the token count for the stop
+// checker's cumulative budget.
+func (b *Blender) chatOnce(ctx context.Context, req providers.ChatRequest) (string, int64, error) {
+	resp, err := b.Chat(ctx, req)
+	text, err := completion(resp, err)
+	if err != nil {
+		return "", 0, err
+	}
+	return text, int64(resp.Usage.TotalTokens), nil
+}
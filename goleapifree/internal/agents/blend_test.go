@@ -0,0 +1,90 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// blendChat answers per model so the test can tell voices and director
+// apart, numbering calls to each.
+type blendChat struct {
+	calls    map[string]int
+	lastUser map[string]string
+}
+
+func (c *blendChat) fn(_ context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+	if c.calls == nil {
+		c.calls = make(map[string]int)
+		c.lastUser = make(map[string]string)
+	}
+	c.calls[req.Model]++
+	c.lastUser[req.Model] = req.Messages[1].Content
+	return &providers.ChatResponse{Content: fmt.Sprintf("%s-%d", req.Model, c.calls[req.Model])}, nil
+}
+
+func TestBlendExposesRecipe(t *testing.T) {
+	chat := &blendChat{}
+	b := &Blender{Chat: chat.fn, VoiceA: "va", VoiceB: "vb", Director: "dir", Segments: 2}
+	res, err := b.Blend(context.Background(), "Once upon a time")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Text != "dir-1\n\ndir-2" {
+		t.Fatalf("text = %q", res.Text)
+	}
+	r := res.Recipe
+	if r.VoiceA != "va" || r.VoiceB != "vb" || r.Director != "dir" || len(r.Segments) != 2 {
+		t.Fatalf("recipe = %+v", r)
+	}
+	if r.Segments[0].DraftA != "va-1" || r.Segments[0].DraftB != "vb-1" || r.Segments[0].Blended != "dir-1" {
+		t.Fatalf("segment 0 = %+v", r.Segments[0])
+	}
+
+	// The director sees both drafts; round two builds on the blend, not
+	// on either raw draft.
+	if dirPrompt := chat.lastUser["dir"]; !strings.Contains(dirPrompt, "va-2") || !strings.Contains(dirPrompt, "vb-2") {
+		t.Fatalf("director prompt = %q", dirPrompt)
+	}
+	if voicePrompt := chat.lastUser["va"]; !strings.Contains(voicePrompt, "dir-1") || strings.Contains(voicePrompt, "va-1") {
+		t.Fatalf("round-two voice prompt = %q", voicePrompt)
+	}
+}
+
+func TestBlendStyleReachesVoices(t *testing.T) {
+	var voiceSys string
+	chat := func(_ context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+		if req.Model == "va" {
+			voiceSys = req.Messages[0].Content
+		}
+		return &providers.ChatResponse{Content: "x"}, nil
+	}
+	b := &Blender{Chat: chat, VoiceA: "va", VoiceB: "vb", Director: "dir", Style: "noir, first person"}
+	if _, err := b.Blend(context.Background(), "p"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(voiceSys, "noir, first person") {
+		t.Fatalf("voice system prompt = %q", voiceSys)
+	}
+}
+
+func TestBlendValidatesModelsAndPropagatesErrors(t *testing.T) {
+	b := &Blender{Chat: (&blendChat{}).fn, VoiceA: "va", Director: "dir"}
+	if _, err := b.Blend(context.Background(), "p"); err == nil {
+		t.Fatal("missing voice accepted")
+	}
+	boom := errors.New("boom")
+	b = &Blender{
+		Chat: func(context.Context, providers.ChatRequest) (*providers.ChatResponse, error) {
+			return nil, boom
+		},
+		VoiceA: "va", VoiceB: "vb", Director: "dir",
+	}
+	if _, err := b.Blend(context.Background(), "p"); !errors.Is(err, boom) {
+		t.Fatalf("err = %v", err)
+	}
+}
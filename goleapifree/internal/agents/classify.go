@@ -0,0 +1,181 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Embedder produces a vector for a text. The live implementation calls
+// an embeddings endpoint; tests inject deterministic fakes.
+type Embedder func(ctx context.Context, text string) ([]float64, error)
+
+// Classification is the analyzer's verdict for one request.
+type Classification struct {
+	Task       string  `json:"task"`
+	Confidence float64 `json:"confidence"`
+	// Method is "embeddings" or "keywords", so telemetry can track how
+	// often the fallback path fires.
+	Method string `json:"method"`
+}
+
+// Misclassification is one captured correction, kept for retraining.
+type Misclassification struct {
+	Text      string    `json:"text"`
+	Predicted string    `json:"predicted"`
+	Actual    string    `json:"actual"`
+	At        time.Time `json:"at"`
+}
+
+// Classifier decides which task type (translate, code, summarize, chat,
+// ...) a request is, using few-shot centroids in embedding space with a
+// keyword fallback below the confidence threshold.
+type Classifier struct {
+	Embed Embedder
+	// Threshold is the minimum cosine similarity before the embeddings
+	// verdict is trusted; defaults to 0.6.
+	Threshold float64
+	// Keywords is the legacy fallback: task -> trigger words.
+	Keywords map[string][]string
+
+	mu        sync.Mutex
+	centroids map[string][]float64
+	counts    map[string]int
+	feedback  []Misclassification
+}
+
+// Train folds labelled examples into the per-task centroids. It can be
+// called incrementally; each call refines the running mean.
+func (c *Classifier) Train(ctx context.Context, examples map[string][]string) error {
+	if c.Embed == nil {
+		return errors.New("agents: classifier has no embedder")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.centroids == nil {
+		c.centroids = make(map[string][]float64)
+		c.counts = make(map[string]int)
+	}
+	for task, texts := range examples {
+		for _, text := range texts {
+			vec, err := c.Embed(ctx, text)
+			if err != nil {
+				return err
+			}
+			centroid := c.centroids[task]
+			if centroid == nil {
+				centroid = make([]float64, len(vec))
+				c.centroids[task] = centroid
+			}
+			c.counts[task]++
+			n := float64(c.counts[task])
+			for i := range centroid {
+				centroid[i] += (vec[i] - centroid[i]) / n
+			}
+		}
+	}
+	return nil
+}
+
+// Classify scores text against the centroids; when the best similarity
+// is below the threshold (or embedding fails) it falls back to keywords.
+func (c *Classifier) Classify(ctx context.Context, text string) Classification {
+	if c.Embed != nil {
+		if cls, ok := c.classifyEmbeddings(ctx, text); ok {
+			return cls
+		}
+	}
+	return c.classifyKeywords(text)
+}
+
+func (c *Classifier) classifyEmbeddings(ctx context.Context, text string) (Classification, bool) {
+	vec, err := c.Embed(ctx, text)
+	if err != nil {
+		return Classification{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	best, bestSim := "", -1.0
+	for task, centroid := range c.centroids {
+		if sim := cosine(vec, centroid); sim > bestSim {
+			best, bestSim = task, sim
+		}
+	}
+	threshold := c.Threshold
+	if threshold == 0 {
+		threshold = 0.6
+	}
+	if best == "" || bestSim < threshold {
+		return Classification{}, false
+	}
+	return Classification{Task: best, Confidence: bestSim, Method: "embeddings"}, true
+}
+
+func (c *Classifier) classifyKeywords(text string) Classification {
+	lower := strings.ToLower(text)
+	best, bestHits := "chat", 0
+	for task, words := range c.Keywords {
+		hits := 0
+		for _, w := range words {
+			if strings.Contains(lower, w) {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = task, hits
+		}
+	}
+	conf := 0.0
+	if bestHits > 0 {
+		conf = math.Min(0.5+0.1*float64(bestHits), 0.9)
+	}
+	return Classification{Task: best, Confidence: conf, Method: "keywords"}
+}
+
+// RecordMisclassification captures a correction and immediately folds
+// the corrected example into the centroids when possible.
+func (c *Classifier) RecordMisclassification(ctx context.Context, text, predicted, actual string) {
+	c.recordFeedback(Misclassification{
+		Text: text, Predicted: predicted, Actual: actual, At: time.Now(),
+	})
+	if c.Embed != nil {
+		_ = c.Train(ctx, map[string][]string{actual: {text}})
+	}
+}
+
+// recordFeedback appends one correction under the classifier's lock;
+// the feedback loop uses it to log overrides without retraining inline.
+func (c *Classifier) recordFeedback(m Misclassification) {
+	c.mu.Lock()
+	c.feedback = append(c.feedback, m)
+	c.mu.Unlock()
+}
+
+// Feedback returns the captured corrections.
+func (c *Classifier) Feedback() []Misclassification {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]Misclassification, len(c.feedback))
+	copy(out, c.feedback)
+	return out
+}
+
+// cosine is the cosine similarity between equal-length vectors.
+func cosine(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,79 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// axisEmbed embeds texts onto fixed axes per topic so centroids are
+// deterministic: code-ish texts on x, translation-ish on y.
+func axisEmbed(_ context.Context, text string) ([]float64, error) {
+	lower := strings.ToLower(text)
+	vec := []float64{0.01, 0.01}
+	if strings.Contains(lower, "function") || strings.Contains(lower, "compile") {
+		vec[0] = 1
+	}
+	if strings.Contains(lower, "translate") || strings.Contains(lower, "french") {
+		vec[1] = 1
+	}
+	return vec, nil
+}
+
+func trainedClassifier(t *testing.T) *Classifier {
+	t.Helper()
+	c := &Classifier{Embed: axisEmbed}
+	err := c.Train(context.Background(), map[string][]string{
+		"code":      {"write a function", "compile this"},
+		"translate": {"translate to french", "translate this text"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestClassifyByEmbeddings(t *testing.T) {
+	c := trainedClassifier(t)
+	got := c.Classify(context.Background(), "please compile my function")
+	if got.Task != "code" || got.Method != "embeddings" || got.Confidence < 0.6 {
+		t.Fatalf("classification = %+v", got)
+	}
+}
+
+func TestLowConfidenceFallsBackToKeywords(t *testing.T) {
+	c := trainedClassifier(t)
+	c.Threshold = 0.99
+	c.Keywords = map[string][]string{"translate": {"other language"}}
+	// Ambiguous text: its embedding sits between both centroids, below
+	// the tightened threshold.
+	got := c.Classify(context.Background(), "say it in another other language")
+	if got.Method != "keywords" || got.Task != "translate" {
+		t.Fatalf("classification = %+v", got)
+	}
+}
+
+func TestEmbedderErrorFallsBack(t *testing.T) {
+	c := &Classifier{
+		Embed:    func(context.Context, string) ([]float64, error) { return nil, errors.New("down") },
+		Keywords: map[string][]string{"code": {"function"}},
+	}
+	got := c.Classify(context.Background(), "a function please")
+	if got.Method != "keywords" || got.Task != "code" {
+		t.Fatalf("classification = %+v", got)
+	}
+}
+
+func TestMisclassificationFeedbackRetrains(t *testing.T) {
+	c := trainedClassifier(t)
+	c.RecordMisclassification(context.Background(), "translate to french please", "code", "translate")
+	fb := c.Feedback()
+	if len(fb) != 1 || fb[0].Actual != "translate" {
+		t.Fatalf("feedback = %+v", fb)
+	}
+	got := c.Classify(context.Background(), "translate to french please")
+	if got.Task != "translate" {
+		t.Fatalf("classification after feedback = %+v", got)
+	}
+}
@@ -0,0 +1,103 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// Runner executes one code snippet in a sandbox and returns its combined
+// output. A non-nil error means compilation or tests failed; the output
+// then carries the compiler/test diagnostics fed back to the model.
+// Deployments wire a container or WASM-based runner; tests inject fakes.
+type Runner func(ctx context.Context, language, code string) (output string, err error)
+
+// codeFenceRe extracts the first fenced code block from a completion.
+var codeFenceRe = regexp.MustCompile("(?s)```([a-zA-Z0-9]*)\n(.*?)```")
+
+// Coder is the coding agent: generate, execute in a sandbox, feed
+// diagnostics back, and iterate until the snippet runs clean or the
+// round budget is exhausted.
+type Coder struct {
+	Chat  ChatFunc
+	Run   Runner
+	Model string
+	// MaxRounds caps generate-verify iterations; defaults to 3.
+	MaxRounds int
+}
+
+// CodeResult is the final snippet plus its verification trail.
+type CodeResult struct {
+	Language string `json:"language"`
+	Code     string `json:"code"`
+	// Verified is true when the last sandbox run passed.
+	Verified bool `json:"verified"`
+	// Rounds is how many generate-verify iterations ran.
+	Rounds int `json:"rounds"`
+	// LastOutput is the sandbox output of the final run, diagnostics
+	// included when verification never passed.
+	LastOutput string `json:"last_output,omitempty"`
+}
+
+// Generate produces code for the instruction and verifies it in the
+// sandbox. Unsupported languages (no runner diagnostics possible) are
+// returned unverified after the first generation.
+func (c *Coder) Generate(ctx context.Context, instruction, language string) (*CodeResult, error) {
+	maxRounds := c.MaxRounds
+	if maxRounds == 0 {
+		maxRounds = 3
+	}
+
+	prompt := fmt.Sprintf("Write %s code for the following task. Reply with a single fenced code block and nothing else.", language)
+	messages := []providers.Message{
+		{Role: "system", Content: prompt},
+		{Role: "user", Content: instruction},
+	}
+
+	res := &CodeResult{Language: language}
+	for round := 1; round <= maxRounds; round++ {
+		res.Rounds = round
+		reply, err := completion(c.Chat(ctx, providers.ChatRequest{Model: c.Model, Messages: messages}))
+		if err != nil {
+			return nil, err
+		}
+		lang, code, ok := extractCode(reply)
+		if !ok {
+			// No fence at all: treat the whole reply as code.
+			lang, code = language, reply
+		}
+		res.Code = code
+		if lang != "" {
+			res.Language = lang
+		}
+
+		if c.Run == nil {
+			return res, nil
+		}
+		output, runErr := c.Run(ctx, res.Language, code)
+		res.LastOutput = output
+		if runErr == nil {
+			res.Verified = true
+			return res, nil
+		}
+		// Feed the diagnostics back and try again.
+		messages = append(messages,
+			providers.Message{Role: "assistant", Content: reply},
+			providers.Message{Role: "user", Content: fmt.Sprintf(
+				"The code failed to compile or pass tests. Fix it.\nDiagnostics:\n%s", output)},
+		)
+	}
+	return res, nil
+}
+
+// extractCode pulls the first fenced block from a completion.
+func extractCode(reply string) (language, code string, ok bool) {
+	m := codeFenceRe.FindStringSubmatch(reply)
+	if m == nil {
+		return "", "", false
+	}
+	return strings.ToLower(m[1]), strings.TrimRight(m[2], "\n"), true
+}
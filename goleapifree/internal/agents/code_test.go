@@ -0,0 +1,74 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+func TestCoderIteratesOnDiagnostics(t *testing.T) {
+	replies := []string{
+		"```go\nfunc main() { fmt.Println(1) }\n```", // missing import
+		"```go\npackage main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(1) }\n```",
+	}
+	var lastPrompt string
+	calls := 0
+	chat := func(_ context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+		lastPrompt = req.Messages[len(req.Messages)-1].Content
+		out := replies[min(calls, len(replies)-1)]
+		calls++
+		return &providers.ChatResponse{Content: out}, nil
+	}
+	run := func(_ context.Context, _, code string) (string, error) {
+		if !strings.Contains(code, "import") {
+			return "main.go:1: undefined: fmt", errors.New("compile failed")
+		}
+		return "ok", nil
+	}
+
+	c := &Coder{Chat: chat, Run: run, Model: "m"}
+	res, err := c.Generate(context.Background(), "print 1", "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Verified || res.Rounds != 2 {
+		t.Fatalf("result = %+v", res)
+	}
+	if !strings.Contains(lastPrompt, "undefined: fmt") {
+		t.Fatalf("diagnostics not fed back, last prompt: %q", lastPrompt)
+	}
+}
+
+func TestCoderGivesUpAfterMaxRounds(t *testing.T) {
+	chat := func(_ context.Context, _ providers.ChatRequest) (*providers.ChatResponse, error) {
+		return &providers.ChatResponse{Content: "```python\nbroken(\n```"}, nil
+	}
+	run := func(_ context.Context, _, _ string) (string, error) {
+		return "SyntaxError", errors.New("run failed")
+	}
+	c := &Coder{Chat: chat, Run: run, Model: "m", MaxRounds: 2}
+	res, err := c.Generate(context.Background(), "x", "python")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Verified || res.Rounds != 2 || res.LastOutput != "SyntaxError" {
+		t.Fatalf("result = %+v", res)
+	}
+}
+
+func TestCoderWithoutRunnerReturnsUnverified(t *testing.T) {
+	chat := func(_ context.Context, _ providers.ChatRequest) (*providers.ChatResponse, error) {
+		return &providers.ChatResponse{Content: "```rust\nfn main() {}\n```"}, nil
+	}
+	c := &Coder{Chat: chat, Model: "m"}
+	res, err := c.Generate(context.Background(), "x", "rust")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Verified || res.Code != "fn main() {}" || res.Language != "rust" {
+		t.Fatalf("result = %+v", res)
+	}
+}
@@ -0,0 +1,195 @@
+package agents
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrAgentNotFound is returned for unknown agent names.
+var ErrAgentNotFound = errors.New("agents: agent config not found")
+
+// Config is one agent's tunable definition, previously compiled in.
+type Config struct {
+	Name         string  `json:"name"`
+	SystemPrompt string  `json:"system_prompt"`
+	Model        string  `json:"model"`
+	Temperature  float64 `json:"temperature"`
+	// Stop declares user-defined conditions that abort the chain early;
+	// nil means the chain always runs to completion.
+	Stop      *StopConditions `json:"stop,omitempty"`
+	Version   int64           `json:"version"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// ConfigStore persists agent configs; the SQL implementation backs
+// production, tests use the registry without a store.
+type ConfigStore interface {
+	LoadAll(ctx context.Context) ([]Config, error)
+	Save(ctx context.Context, cfg Config) error
+}
+
+// ConfigRegistry is the in-memory view the orchestrator reads on every
+// chain run; updates through Apply propagate instantly and notify
+// watchers, so edits in the admin API take effect without a restart.
+type ConfigRegistry struct {
+	Store ConfigStore
+
+	mu       sync.RWMutex
+	configs  map[string]Config
+	watchers []func(Config)
+}
+
+// NewConfigRegistry creates an empty registry; call Load to hydrate it
+// from the store.
+func NewConfigRegistry(store ConfigStore) *ConfigRegistry {
+	return &ConfigRegistry{Store: store, configs: make(map[string]Config)}
+}
+
+// Load hydrates the registry from the store.
+func (r *ConfigRegistry) Load(ctx context.Context) error {
+	if r.Store == nil {
+		return nil
+	}
+	configs, err := r.Store.LoadAll(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	for _, cfg := range configs {
+		r.configs[cfg.Name] = cfg
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// Get returns one agent's current config.
+func (r *ConfigRegistry) Get(name string) (Config, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cfg, ok := r.configs[name]
+	if !ok {
+		return Config{}, ErrAgentNotFound
+	}
+	return cfg, nil
+}
+
+// List returns all configs sorted by name.
+func (r *ConfigRegistry) List() []Config {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Config, 0, len(r.configs))
+	for _, cfg := range r.configs {
+		out = append(out, cfg)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Apply updates an agent's config: version bumped, persisted when a
+// store is attached, and watchers notified synchronously so in-flight
+// orchestrators pick it up on their next step.
+func (r *ConfigRegistry) Apply(ctx context.Context, cfg Config) (Config, error) {
+	r.mu.Lock()
+	if current, ok := r.configs[cfg.Name]; ok {
+		cfg.Version = current.Version + 1
+	} else {
+		cfg.Version = 1
+	}
+	cfg.UpdatedAt = time.Now()
+	r.configs[cfg.Name] = cfg
+	watchers := make([]func(Config), len(r.watchers))
+	copy(watchers, r.watchers)
+	r.mu.Unlock()
+
+	if r.Store != nil {
+		if err := r.Store.Save(ctx, cfg); err != nil {
+			return Config{}, err
+		}
+	}
+	for _, w := range watchers {
+		w(cfg)
+	}
+	return cfg, nil
+}
+
+// Watch registers a callback invoked on every applied change.
+func (r *ConfigRegistry) Watch(fn func(Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.watchers = append(r.watchers, fn)
+}
+
+// SQLConfigStore keeps agent configs in an agent_configs table.
+type SQLConfigStore struct{ db *sql.DB }
+
+// NewSQLConfigStore creates the table if needed.
+func NewSQLConfigStore(ctx context.Context, db *sql.DB) (*SQLConfigStore, error) {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS agent_configs (
+		name TEXT PRIMARY KEY,
+		system_prompt TEXT NOT NULL,
+		model TEXT NOT NULL,
+		temperature REAL NOT NULL,
+		stop TEXT NOT NULL DEFAULT '',
+		version INTEGER NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLConfigStore{db: db}, nil
+}
+
+// LoadAll implements ConfigStore.
+func (s *SQLConfigStore) LoadAll(ctx context.Context) ([]Config, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT name, system_prompt, model, temperature, stop, version, updated_at FROM agent_configs`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Config
+	for rows.Next() {
+		var cfg Config
+		var stop string
+		if err := rows.Scan(&cfg.Name, &cfg.SystemPrompt, &cfg.Model, &cfg.Temperature, &stop, &cfg.Version, &cfg.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if stop != "" {
+			cfg.Stop = &StopConditions{}
+			if err := json.Unmarshal([]byte(stop), cfg.Stop); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, cfg)
+	}
+	return out, rows.Err()
+}
+
+// Save implements ConfigStore with an upsert.
+func (s *SQLConfigStore) Save(ctx context.Context, cfg Config) error {
+	var stop string
+	if cfg.Stop != nil {
+		raw, err := json.Marshal(cfg.Stop)
+		if err != nil {
+			return err
+		}
+		stop = string(raw)
+	}
+	_, err := s.db.ExecContext(ctx, `INSERT INTO agent_configs
+		(name, system_prompt, model, temperature, stop, version, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (name) DO UPDATE SET
+			system_prompt = EXCLUDED.system_prompt,
+			model = EXCLUDED.model,
+			temperature = EXCLUDED.temperature,
+			stop = EXCLUDED.stop,
+			version = EXCLUDED.version,
+			updated_at = EXCLUDED.updated_at`,
+		cfg.Name, cfg.SystemPrompt, cfg.Model, cfg.Temperature, stop, cfg.Version, cfg.UpdatedAt)
+	return err
+}
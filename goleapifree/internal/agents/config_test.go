@@ -0,0 +1,63 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestConfigRegistryApplyAndWatch(t *testing.T) {
+	r := NewConfigRegistry(nil)
+	var seen []Config
+	r.Watch(func(cfg Config) { seen = append(seen, cfg) })
+
+	first, err := r.Apply(context.Background(), Config{Name: "translate", Model: "gpt-4o-mini", Temperature: 0.2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Version != 1 {
+		t.Fatalf("version = %d, want 1", first.Version)
+	}
+	second, _ := r.Apply(context.Background(), Config{Name: "translate", Model: "gpt-4o", Temperature: 0.2})
+	if second.Version != 2 {
+		t.Fatalf("version = %d, want 2", second.Version)
+	}
+	if len(seen) != 2 || seen[1].Model != "gpt-4o" {
+		t.Fatalf("watcher notifications = %+v", seen)
+	}
+
+	got, err := r.Get("translate")
+	if err != nil || got.Model != "gpt-4o" {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+	if _, err := r.Get("ghost"); !errors.Is(err, ErrAgentNotFound) {
+		t.Fatalf("err = %v, want ErrAgentNotFound", err)
+	}
+}
+
+// memStore verifies the registry persists through its store.
+type memStore struct{ saved []Config }
+
+func (m *memStore) LoadAll(context.Context) ([]Config, error) { return m.saved, nil }
+func (m *memStore) Save(_ context.Context, cfg Config) error {
+	m.saved = append(m.saved, cfg)
+	return nil
+}
+
+func TestConfigRegistryLoadsFromStore(t *testing.T) {
+	store := &memStore{saved: []Config{{Name: "code", Model: "m", Version: 3}}}
+	r := NewConfigRegistry(store)
+	if err := r.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.Get("code")
+	if err != nil || got.Version != 3 {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+	if _, err := r.Apply(context.Background(), Config{Name: "code", Model: "m2"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.saved) != 2 || store.saved[1].Version != 4 {
+		t.Fatalf("store.saved = %+v", store.saved)
+	}
+}
@@ -0,0 +1,178 @@
+package agents
+
+// Dev-mode hot reloading: watch the chains/ and templates/ directories
+// and push edits into the live registries without a restart. Definitions
+// that fail validation are reported through the error callback and the
+// previous good version stays active, so a typo mid-edit never takes the
+// gateway down.
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DevWatcher polls a chains directory (YAML agent configs) and a
+// templates directory (prompt assets) for changes. Polling keeps the
+// implementation dependency-free and is plenty for a dev loop.
+type DevWatcher struct {
+	// ChainsDir holds one YAML Config per file; empty disables it.
+	ChainsDir string
+	// TemplatesDir holds prompt assets named after their file (less the
+	// extension), loaded into the global tenant; empty disables it.
+	TemplatesDir string
+	// Interval between polls; defaults to two seconds.
+	Interval time.Duration
+	// OnError receives validation and read failures; defaults to
+	// log.Printf. The TUI hooks in here to show them inline.
+	OnError func(path string, err error)
+
+	Configs *ConfigRegistry
+	Prompts *PromptRegistry
+
+	mu   sync.Mutex
+	seen map[string]time.Time // path -> mod time last applied
+}
+
+// Run polls until ctx is done. An initial load happens immediately.
+func (w *DevWatcher) Run(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	w.Reload(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.Reload(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Reload applies every new or changed definition once, returning how
+// many files were applied. Exported so tests and the CLI can force a
+// pass without waiting for the ticker.
+func (w *DevWatcher) Reload(ctx context.Context) int {
+	applied := 0
+	if w.ChainsDir != "" && w.Configs != nil {
+		applied += w.scan(w.ChainsDir, func(path string, raw []byte) error {
+			return w.applyChain(ctx, path, raw)
+		})
+	}
+	if w.TemplatesDir != "" && w.Prompts != nil {
+		applied += w.scan(w.TemplatesDir, func(path string, raw []byte) error {
+			name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			w.Prompts.Set("", name, string(raw))
+			return nil
+		})
+	}
+	return applied
+}
+
+// scan visits every regular file in dir, applying those whose mod time
+// moved since the last pass.
+func (w *DevWatcher) scan(dir string, apply func(path string, raw []byte) error) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		w.report(dir, err)
+		return 0
+	}
+	applied := 0
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		info, err := e.Info()
+		if err != nil {
+			w.report(path, err)
+			continue
+		}
+		w.mu.Lock()
+		if w.seen == nil {
+			w.seen = make(map[string]time.Time)
+		}
+		last, ok := w.seen[path]
+		w.mu.Unlock()
+		if ok && !info.ModTime().After(last) {
+			continue
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			w.report(path, err)
+			continue
+		}
+		if err := apply(path, raw); err != nil {
+			// Leave the file marked unseen so a later save retries it.
+			w.report(path, err)
+			continue
+		}
+		w.mu.Lock()
+		w.seen[path] = info.ModTime()
+		w.mu.Unlock()
+		applied++
+	}
+	return applied
+}
+
+// applyChain parses, validates and applies one agent config file.
+func (w *DevWatcher) applyChain(ctx context.Context, path string, raw []byte) error {
+	var spec struct {
+		Name         string          `yaml:"name"`
+		SystemPrompt string          `yaml:"system_prompt"`
+		Model        string          `yaml:"model"`
+		Temperature  float64         `yaml:"temperature"`
+		Stop         *StopConditions `yaml:"stop"`
+	}
+	if err := yaml.Unmarshal(raw, &spec); err != nil {
+		return fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+	}
+	cfg := Config{
+		Name:         spec.Name,
+		SystemPrompt: spec.SystemPrompt,
+		Model:        spec.Model,
+		Temperature:  spec.Temperature,
+		Stop:         spec.Stop,
+	}
+	if cfg.Name == "" {
+		cfg.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := validateChain(cfg); err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	_, err := w.Configs.Apply(ctx, cfg)
+	return err
+}
+
+// validateChain rejects definitions that would break chain runs.
+func validateChain(cfg Config) error {
+	if cfg.Model == "" {
+		return fmt.Errorf("agents: model is required")
+	}
+	if cfg.Temperature < 0 || cfg.Temperature > 2 {
+		return fmt.Errorf("agents: temperature %.2f out of range [0, 2]", cfg.Temperature)
+	}
+	if cfg.Stop != nil {
+		return cfg.Stop.Validate()
+	}
+	return nil
+}
+
+func (w *DevWatcher) report(path string, err error) {
+	if w.OnError != nil {
+		w.OnError(path, err)
+		return
+	}
+	log.Printf("agents: dev reload %s: %v", path, err)
+}
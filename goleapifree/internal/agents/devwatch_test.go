@@ -0,0 +1,100 @@
+package agents
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDevWatcherLoadsChainsAndTemplates(t *testing.T) {
+	chains := t.TempDir()
+	templates := t.TempDir()
+	writeFile(t, filepath.Join(chains, "summarizer.yaml"),
+		"model: gpt-4\nsystem_prompt: summarize things\ntemperature: 0.3\n")
+	writeFile(t, filepath.Join(templates, "greeting.txt"), "hello {{.Name}}")
+
+	w := &DevWatcher{
+		ChainsDir:    chains,
+		TemplatesDir: templates,
+		Configs:      NewConfigRegistry(nil),
+		Prompts:      NewPromptRegistry(),
+	}
+	if got := w.Reload(context.Background()); got != 2 {
+		t.Fatalf("applied = %d, want 2", got)
+	}
+
+	cfg, err := w.Configs.Get("summarizer")
+	if err != nil || cfg.Model != "gpt-4" || cfg.SystemPrompt != "summarize things" {
+		t.Fatalf("config = %+v, %v", cfg, err)
+	}
+	if got, ok := w.Prompts.Get("tenant-x", "greeting"); !ok || got != "hello {{.Name}}" {
+		t.Fatalf("template = %q, %v", got, ok)
+	}
+}
+
+func TestDevWatcherPicksUpEdits(t *testing.T) {
+	chains := t.TempDir()
+	path := filepath.Join(chains, "coder.yaml")
+	writeFile(t, path, "model: gpt-4\n")
+
+	w := &DevWatcher{ChainsDir: chains, Configs: NewConfigRegistry(nil)}
+	w.Reload(context.Background())
+	if got := w.Reload(context.Background()); got != 0 {
+		t.Fatalf("unchanged file re-applied %d times", got)
+	}
+
+	// Edits need a newer mod time; set it explicitly so the test does
+	// not depend on filesystem timestamp resolution.
+	writeFile(t, path, "model: claude-3-haiku\n")
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Reload(context.Background()); got != 1 {
+		t.Fatalf("edit applied %d times, want 1", got)
+	}
+	cfg, _ := w.Configs.Get("coder")
+	if cfg.Model != "claude-3-haiku" || cfg.Version != 2 {
+		t.Fatalf("config = %+v", cfg)
+	}
+}
+
+func TestDevWatcherKeepsLastGoodVersionOnError(t *testing.T) {
+	chains := t.TempDir()
+	path := filepath.Join(chains, "guard.yaml")
+	writeFile(t, path, "model: gpt-4\ntemperature: 0.1\n")
+
+	var reported []error
+	w := &DevWatcher{
+		ChainsDir: chains,
+		Configs:   NewConfigRegistry(nil),
+		OnError:   func(_ string, err error) { reported = append(reported, err) },
+	}
+	w.Reload(context.Background())
+
+	// A broken edit must be reported, not applied and not crash.
+	writeFile(t, path, "model: gpt-4\ntemperature: 9.5\n")
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Reload(context.Background()); got != 0 {
+		t.Fatalf("invalid edit applied %d times", got)
+	}
+	if len(reported) != 1 {
+		t.Fatalf("reported errors = %v", reported)
+	}
+	cfg, _ := w.Configs.Get("guard")
+	if cfg.Temperature != 0.1 {
+		t.Fatalf("broken edit replaced good config: %+v", cfg)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
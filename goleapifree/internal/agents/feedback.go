@@ -0,0 +1,122 @@
+package agents
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxAccuracyHistory bounds the retained accuracy points; with a daily
+// retrain that is well over a year of history.
+const maxAccuracyHistory = 512
+
+// AccuracyPoint is one retrain period's classifier accuracy: how many
+// auto-selections the analyzer made, how many users overrode, and how
+// many corrected examples were folded back into the centroids.
+type AccuracyPoint struct {
+	At          time.Time `json:"at"`
+	Predictions int       `json:"predictions"`
+	Overrides   int       `json:"overrides"`
+	Accuracy    float64   `json:"accuracy"`
+	Retrained   int       `json:"retrained"`
+}
+
+// FeedbackLoop turns user overrides into classifier training data. Every
+// auto-selection is counted; when a user picks a different agent than
+// the analyzer chose, the request text becomes a labeled example queued
+// for the next retrain. Retrain runs on the scheduler and records one
+// accuracy point per period, so drift shows up as a falling curve.
+type FeedbackLoop struct {
+	Classifier *Classifier
+
+	now func() time.Time
+
+	mu          sync.Mutex
+	pending     map[string][]string
+	predictions int
+	overrides   int
+	history     []AccuracyPoint
+}
+
+// NewFeedbackLoop wraps a classifier.
+func NewFeedbackLoop(c *Classifier) *FeedbackLoop {
+	return &FeedbackLoop{
+		Classifier: c,
+		now:        time.Now,
+		pending:    make(map[string][]string),
+	}
+}
+
+// RecordPrediction counts one auto-selection the user accepted as-is.
+// The dispatch path calls it for every classified request; overrides
+// are subtracted via RecordOverride.
+func (f *FeedbackLoop) RecordPrediction() {
+	f.mu.Lock()
+	f.predictions++
+	f.mu.Unlock()
+}
+
+// RecordOverride captures a user picking a different agent than the
+// analyzer selected. The text is queued as a labeled example for the
+// chosen task; the correction also lands in the classifier's
+// misclassification log for the dashboard.
+func (f *FeedbackLoop) RecordOverride(text, predicted, chosen string) {
+	f.mu.Lock()
+	f.predictions++
+	f.overrides++
+	f.pending[chosen] = append(f.pending[chosen], text)
+	f.mu.Unlock()
+	f.Classifier.recordFeedback(Misclassification{
+		Text: text, Predicted: predicted, Actual: chosen, At: f.now(),
+	})
+}
+
+// Retrain folds the queued overrides into the classifier centroids and
+// closes out one accuracy period. Its signature matches the scheduler's
+// JobFunc. A failed retrain requeues the examples so no label is lost.
+func (f *FeedbackLoop) Retrain(ctx context.Context) error {
+	f.mu.Lock()
+	pending := f.pending
+	predictions, overrides := f.predictions, f.overrides
+	f.pending = make(map[string][]string)
+	f.predictions, f.overrides = 0, 0
+	f.mu.Unlock()
+
+	retrained := 0
+	for _, texts := range pending {
+		retrained += len(texts)
+	}
+	if retrained > 0 {
+		if err := f.Classifier.Train(ctx, pending); err != nil {
+			f.mu.Lock()
+			for task, texts := range pending {
+				f.pending[task] = append(texts, f.pending[task]...)
+			}
+			f.predictions += predictions
+			f.overrides += overrides
+			f.mu.Unlock()
+			return err
+		}
+	}
+
+	point := AccuracyPoint{At: f.now(), Predictions: predictions, Overrides: overrides, Retrained: retrained}
+	if predictions > 0 {
+		point.Accuracy = float64(predictions-overrides) / float64(predictions)
+	}
+	f.mu.Lock()
+	f.history = append(f.history, point)
+	if len(f.history) > maxAccuracyHistory {
+		f.history = f.history[len(f.history)-maxAccuracyHistory:]
+	}
+	f.mu.Unlock()
+	return nil
+}
+
+// History returns the recorded accuracy points, oldest first.
+func (f *FeedbackLoop) History() []AccuracyPoint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]AccuracyPoint, len(f.history))
+	copy(out, f.history)
+	return out
+}
@@ -0,0 +1,74 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFeedbackLoopRetrains(t *testing.T) {
+	c := trainedClassifier(t)
+	f := NewFeedbackLoop(c)
+	at := time.Date(2026, 9, 1, 9, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return at }
+
+	for i := 0; i < 8; i++ {
+		f.RecordPrediction()
+	}
+	f.RecordOverride("translate to french please", "code", "translate")
+	f.RecordOverride("translate this text now", "code", "translate")
+
+	if err := f.Retrain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	hist := f.History()
+	if len(hist) != 1 {
+		t.Fatalf("history = %+v", hist)
+	}
+	p := hist[0]
+	if p.Predictions != 10 || p.Overrides != 2 || p.Retrained != 2 || p.Accuracy != 0.8 {
+		t.Fatalf("point = %+v", p)
+	}
+	if fb := c.Feedback(); len(fb) != 2 || fb[0].Actual != "translate" {
+		t.Fatalf("feedback = %+v", fb)
+	}
+
+	// The overrides became training data: the misrouted text now
+	// classifies as the task the users chose.
+	got := c.Classify(context.Background(), "translate to french please")
+	if got.Task != "translate" {
+		t.Fatalf("classification after retrain = %+v", got)
+	}
+
+	// A second period with no traffic still records a point.
+	if err := f.Retrain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if hist := f.History(); len(hist) != 2 || hist[1].Predictions != 0 {
+		t.Fatalf("history = %+v", hist)
+	}
+}
+
+func TestFeedbackLoopRequeuesOnError(t *testing.T) {
+	c := &Classifier{Embed: func(context.Context, string) ([]float64, error) {
+		return nil, errors.New("embedder down")
+	}}
+	f := NewFeedbackLoop(c)
+	f.RecordOverride("some text", "chat", "code")
+	if err := f.Retrain(context.Background()); err == nil {
+		t.Fatal("failed retrain reported success")
+	}
+	if len(f.History()) != 0 {
+		t.Fatal("failed retrain recorded an accuracy point")
+	}
+
+	// The embedder recovers; the requeued example trains on the next run.
+	c.Embed = axisEmbed
+	if err := f.Retrain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if hist := f.History(); len(hist) != 1 || hist[0].Retrained != 1 || hist[0].Overrides != 1 {
+		t.Fatalf("history = %+v", hist)
+	}
+}
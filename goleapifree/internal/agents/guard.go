@@ -0,0 +1,98 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// GuardVerdict is the guard agent's assessment of a response.
+type GuardVerdict struct {
+	Pass       bool    `json:"pass"`
+	Confidence float64 `json:"confidence"`
+	Reason     string  `json:"reason,omitempty"`
+	// Regenerated is true when the delivered output came from the one
+	// permitted regeneration.
+	Regenerated bool `json:"regenerated"`
+}
+
+// Guard is the optional final pipeline step: a cheap model reviews the
+// response against the original instruction and policy rules, may
+// request a single regeneration, and annotates the result.
+type Guard struct {
+	Chat  ChatFunc
+	Model string
+	// Rules are the policy lines the reviewer enforces in addition to
+	// instruction fidelity.
+	Rules string
+}
+
+// guardPrompt asks for a strict JSON verdict so parsing stays trivial on
+// cheap models.
+const guardPrompt = `You review an assistant's response before delivery.
+Check that the response answers the user's instruction and violates none
+of the policy rules. Reply with JSON only:
+{"pass": true|false, "confidence": 0.0-1.0, "reason": "short reason when failing"}`
+
+// Review runs one guard evaluation.
+func (g *Guard) Review(ctx context.Context, instruction, output string) (GuardVerdict, error) {
+	var sys strings.Builder
+	sys.WriteString(guardPrompt)
+	if g.Rules != "" {
+		sys.WriteString("\nPolicy rules:\n" + g.Rules)
+	}
+	reply, err := completion(g.Chat(ctx, providers.ChatRequest{
+		Model: g.Model,
+		Messages: []providers.Message{
+			{Role: "system", Content: sys.String()},
+			{Role: "user", Content: "Instruction:\n" + instruction + "\n\nResponse:\n" + output},
+		},
+	}))
+	if err != nil {
+		return GuardVerdict{}, err
+	}
+	return parseVerdict(reply), nil
+}
+
+// Deliver reviews output and, when the guard rejects it, asks the
+// generator for one regeneration before delivering whichever came last.
+// The returned verdict reflects the delivered output.
+func (g *Guard) Deliver(ctx context.Context, instruction, output string,
+	regenerate func(ctx context.Context, feedback string) (string, error)) (string, GuardVerdict, error) {
+
+	verdict, err := g.Review(ctx, instruction, output)
+	if err != nil {
+		return output, verdict, err
+	}
+	if verdict.Pass || regenerate == nil {
+		return output, verdict, nil
+	}
+
+	redone, err := regenerate(ctx, verdict.Reason)
+	if err != nil {
+		// Regeneration failed; deliver the original with its verdict.
+		return output, verdict, nil
+	}
+	verdict, err = g.Review(ctx, instruction, redone)
+	if err != nil {
+		return redone, verdict, err
+	}
+	verdict.Regenerated = true
+	return redone, verdict, nil
+}
+
+// parseVerdict reads the reviewer's JSON, tolerating fenced or prefixed
+// replies; unparseable replies fail closed with low confidence.
+func parseVerdict(reply string) GuardVerdict {
+	start := strings.IndexByte(reply, '{')
+	end := strings.LastIndexByte(reply, '}')
+	if start >= 0 && end > start {
+		var v GuardVerdict
+		if json.Unmarshal([]byte(reply[start:end+1]), &v) == nil {
+			return v
+		}
+	}
+	return GuardVerdict{Pass: false, Confidence: 0, Reason: "unparseable guard verdict"}
+}
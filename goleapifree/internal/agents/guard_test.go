@@ -0,0 +1,60 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// verdictChat fails responses containing "bad" and passes the rest.
+func verdictChat(_ context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+	body := req.Messages[1].Content
+	if strings.Contains(body, "bad") {
+		return &providers.ChatResponse{Content: `{"pass": false, "confidence": 0.9, "reason": "off-topic"}`}, nil
+	}
+	return &providers.ChatResponse{Content: `{"pass": true, "confidence": 0.95}`}, nil
+}
+
+func TestDeliverPassesCleanOutput(t *testing.T) {
+	g := &Guard{Chat: verdictChat, Model: "cheap"}
+	out, v, err := g.Deliver(context.Background(), "greet", "hello", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "hello" || !v.Pass || v.Regenerated {
+		t.Fatalf("out = %q, verdict = %+v", out, v)
+	}
+}
+
+func TestDeliverRegeneratesOnce(t *testing.T) {
+	g := &Guard{Chat: verdictChat, Model: "cheap", Rules: "stay on topic"}
+	var feedback string
+	regen := func(_ context.Context, reason string) (string, error) {
+		feedback = reason
+		return "a good reply", nil
+	}
+	out, v, err := g.Deliver(context.Background(), "greet", "a bad reply", regen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "a good reply" || !v.Pass || !v.Regenerated {
+		t.Fatalf("out = %q, verdict = %+v", out, v)
+	}
+	if feedback != "off-topic" {
+		t.Fatalf("regeneration feedback = %q", feedback)
+	}
+}
+
+func TestParseVerdictFailsClosed(t *testing.T) {
+	v := parseVerdict("I think it looks fine!")
+	if v.Pass || v.Confidence != 0 {
+		t.Fatalf("verdict = %+v", v)
+	}
+	// Fenced JSON still parses.
+	v = parseVerdict("```json\n{\"pass\": true, \"confidence\": 0.8}\n```")
+	if !v.Pass || v.Confidence != 0.8 {
+		t.Fatalf("verdict = %+v", v)
+	}
+}
@@ -0,0 +1,103 @@
+package agents
+
+import (
+	"strings"
+)
+
+// Programming language detection for coding traffic. A fenced code
+// block's info string is authoritative when present; otherwise a
+// keyword heuristic scores the text, which is enough to separate the
+// mainstream languages the routing preferences actually distinguish.
+
+// fenceAliases normalizes code fence info strings to canonical names.
+var fenceAliases = map[string]string{
+	"golang":  "go",
+	"py":      "python",
+	"python3": "python",
+	"js":      "javascript",
+	"jsx":     "javascript",
+	"ts":      "typescript",
+	"tsx":     "typescript",
+	"rs":      "rust",
+	"c++":     "cpp",
+	"sh":      "shell",
+	"bash":    "shell",
+	"zsh":     "shell",
+}
+
+// languageMarkers are the per-language keyword heuristics; two hits are
+// required so prose mentioning a single keyword does not tag.
+var languageMarkers = map[string][]string{
+	"go":         {"func ", "package ", ":= ", "chan ", "go func"},
+	"python":     {"def ", "elif ", "self.", "import ", "__init__"},
+	"javascript": {"function ", "const ", "=> ", "console.log", "require("},
+	"typescript": {"interface ", ": string", ": number", "export type"},
+	"rust":       {"fn ", "let mut ", "impl ", "::<", "match "},
+	"java":       {"public class", "public static", "void ", "System.out"},
+	"c":          {"#include", "printf(", "int main", "->"},
+	"shell":      {"#!/bin", "echo ", "grep ", "| "},
+}
+
+// DetectLanguage returns the programming language of the code in text,
+// or "" when none is apparent. Fence tags win over heuristics.
+func DetectLanguage(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "```") {
+			continue
+		}
+		tag := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, "```")))
+		if tag == "" {
+			continue
+		}
+		if canonical, ok := fenceAliases[tag]; ok {
+			return canonical
+		}
+		if _, ok := languageMarkers[tag]; ok {
+			return tag
+		}
+	}
+
+	best, bestHits := "", 1 // require at least two marker hits
+	for lang, markers := range languageMarkers {
+		hits := 0
+		for _, marker := range markers {
+			if strings.Contains(text, marker) {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = lang, hits
+		}
+	}
+	return best
+}
+
+// LanguagePrefs maps a detected language to the providers that
+// benchmark well on it, best first. It is alias-level routing
+// configuration; the wiring turns it into the router's Prior for
+// requests tagged with a language.
+type LanguagePrefs map[string][]string
+
+// Prior converts the preference list for one language into a router
+// prior: the first preferred provider scores 1, each later one a step
+// less, unlisted providers a neutral 0.5. It returns nil when the
+// language has no preferences, so callers fall back to the default
+// prior.
+func (p LanguagePrefs) Prior(lang string) func(providerID string) float64 {
+	preferred := p[lang]
+	if len(preferred) == 0 {
+		return nil
+	}
+	scores := make(map[string]float64, len(preferred))
+	step := 0.4 / float64(len(preferred))
+	for i, id := range preferred {
+		scores[id] = 1 - float64(i)*step
+	}
+	return func(providerID string) float64 {
+		if score, ok := scores[providerID]; ok {
+			return score
+		}
+		return 0.5
+	}
+}
@@ -0,0 +1,48 @@
+package agents
+
+import "testing"
+
+func TestDetectLanguageFenceTag(t *testing.T) {
+	for _, tc := range []struct{ text, want string }{
+		{"fix this:\n```go\nfunc main() {}\n```", "go"},
+		{"```py\nprint(1)\n```", "python"},
+		{"```ts\nexport type A = {}\n```", "typescript"},
+		{"```\nsomething untagged\n```", ""},
+	} {
+		if got := DetectLanguage(tc.text); got != tc.want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestDetectLanguageHeuristics(t *testing.T) {
+	for _, tc := range []struct{ text, want string }{
+		{"package main\n\nfunc main() {\n\tch := make(chan int)\n}", "go"},
+		{"def handler(self):\n    import os\n    self.done = True", "python"},
+		{"what is a goroutine?", ""}, // one keyword is not code
+	} {
+		if got := DetectLanguage(tc.text); got != tc.want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestLanguagePrefsPrior(t *testing.T) {
+	prefs := LanguagePrefs{"go": {"deepseek", "openai"}}
+	prior := prefs.Prior("go")
+	if prior == nil {
+		t.Fatal("no prior for configured language")
+	}
+	if prior("deepseek") != 1 {
+		t.Fatalf("first preference = %v", prior("deepseek"))
+	}
+	if !(prior("deepseek") > prior("openai") && prior("openai") > prior("mistral")) {
+		t.Fatalf("ordering broken: %v %v %v", prior("deepseek"), prior("openai"), prior("mistral"))
+	}
+	if prior("mistral") != 0.5 {
+		t.Fatalf("unlisted provider = %v", prior("mistral"))
+	}
+	if prefs.Prior("rust") != nil {
+		t.Fatal("unconfigured language returned a prior")
+	}
+}
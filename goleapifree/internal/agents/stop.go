@@ -0,0 +1,116 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// Stop condition names recorded on a triggered stop.
+const (
+	StopPattern   = "pattern"
+	StopMaxTokens = "max_tokens"
+	StopJudge     = "judge"
+)
+
+// StopConditions are user-defined guards a chain config declares; the
+// pipeline checks them after every step and aborts early when one
+// triggers, returning the partial result.
+type StopConditions struct {
+	// MaxTotalTokens aborts once the run's cumulative tokens exceed it;
+	// 0 means unlimited.
+	MaxTotalTokens int64 `json:"max_total_tokens,omitempty" yaml:"max_total_tokens"`
+	// Patterns are regexes matched against each step's output.
+	Patterns []string `json:"patterns,omitempty" yaml:"patterns"`
+	// JudgePrompt, when set, has JudgeModel review each step's output;
+	// a STOP verdict aborts the run.
+	JudgePrompt string `json:"judge_prompt,omitempty" yaml:"judge_prompt"`
+	JudgeModel  string `json:"judge_model,omitempty" yaml:"judge_model"`
+}
+
+// Validate rejects conditions that would break chain runs.
+func (c *StopConditions) Validate() error {
+	if c.MaxTotalTokens < 0 {
+		return fmt.Errorf("agents: max_total_tokens must not be negative")
+	}
+	for _, p := range c.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("agents: stop pattern %q: %w", p, err)
+		}
+	}
+	if c.JudgePrompt != "" && c.JudgeModel == "" {
+		return fmt.Errorf("agents: judge_prompt requires judge_model")
+	}
+	return nil
+}
+
+// Stop records which condition aborted a run, carried with the partial
+// result.
+type Stop struct {
+	Condition string `json:"condition"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// StopChecker evaluates one run's conditions step by step, accumulating
+// the token count.
+type StopChecker struct {
+	conds    StopConditions
+	patterns []*regexp.Regexp
+	chat     ChatFunc
+	tokens   int64
+}
+
+// NewStopChecker compiles the conditions; chat backs the judge and may be
+// nil when no judge is configured.
+func NewStopChecker(conds StopConditions, chat ChatFunc) (*StopChecker, error) {
+	if err := conds.Validate(); err != nil {
+		return nil, err
+	}
+	c := &StopChecker{conds: conds, chat: chat}
+	for _, p := range conds.Patterns {
+		c.patterns = append(c.patterns, regexp.MustCompile(p))
+	}
+	return c, nil
+}
+
+// Check evaluates the conditions against one step's output, returning the
+// triggered stop or nil. A failing judge call never aborts the run; the
+// guard logs and waives that check rather than turning a guard outage
+// into a chain failure.
+func (c *StopChecker) Check(ctx context.Context, output string, stepTokens int64) *Stop {
+	if c == nil {
+		return nil
+	}
+	c.tokens += stepTokens
+	if c.conds.MaxTotalTokens > 0 && c.tokens > c.conds.MaxTotalTokens {
+		return &Stop{
+			Condition: StopMaxTokens,
+			Detail:    fmt.Sprintf("%d cumulative tokens over the %d cap", c.tokens, c.conds.MaxTotalTokens),
+		}
+	}
+	for _, re := range c.patterns {
+		if m := re.FindString(output); m != "" {
+			return &Stop{Condition: StopPattern, Detail: fmt.Sprintf("%s matched %q", re, m)}
+		}
+	}
+	if c.conds.JudgePrompt != "" && c.chat != nil {
+		verdict, err := completion(c.chat(ctx, providers.ChatRequest{
+			Model: c.conds.JudgeModel,
+			Messages: []providers.Message{
+				{Role: "system", Content: c.conds.JudgePrompt +
+					"\nReply with exactly STOP to abort the pipeline, or CONTINUE."},
+				{Role: "user", Content: output},
+			},
+		}))
+		if err != nil {
+			log.Printf("agents: stop judge: %v", err)
+		} else if strings.HasPrefix(strings.TrimSpace(verdict), "STOP") {
+			return &Stop{Condition: StopJudge, Detail: strings.TrimSpace(verdict)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+func TestStopOnPattern(t *testing.T) {
+	c, err := NewStopChecker(StopConditions{Patterns: []string{`(?i)the end\.`}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stop := c.Check(context.Background(), "and so it began", 10); stop != nil {
+		t.Fatalf("stopped early: %+v", stop)
+	}
+	stop := c.Check(context.Background(), "they lived happily. The End.", 10)
+	if stop == nil || stop.Condition != StopPattern || !strings.Contains(stop.Detail, "The End.") {
+		t.Fatalf("stop = %+v", stop)
+	}
+}
+
+func TestStopOnCumulativeTokens(t *testing.T) {
+	c, err := NewStopChecker(StopConditions{MaxTotalTokens: 100}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stop := c.Check(context.Background(), "a", 60); stop != nil {
+		t.Fatalf("stopped under budget: %+v", stop)
+	}
+	stop := c.Check(context.Background(), "b", 60)
+	if stop == nil || stop.Condition != StopMaxTokens {
+		t.Fatalf("stop = %+v", stop)
+	}
+}
+
+func TestStopOnJudgeVerdict(t *testing.T) {
+	verdicts := []string{"CONTINUE", "STOP: output repeats itself"}
+	var judged []string
+	chat := func(_ context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+		judged = append(judged, req.Messages[1].Content)
+		out := verdicts[len(judged)-1]
+		return &providers.ChatResponse{Content: out}, nil
+	}
+	c, err := NewStopChecker(StopConditions{JudgePrompt: "Stop on loops.", JudgeModel: "cheap"}, chat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stop := c.Check(context.Background(), "fresh text", 0); stop != nil {
+		t.Fatalf("stopped on CONTINUE: %+v", stop)
+	}
+	stop := c.Check(context.Background(), "same text again", 0)
+	if stop == nil || stop.Condition != StopJudge || !strings.Contains(stop.Detail, "repeats") {
+		t.Fatalf("stop = %+v", stop)
+	}
+	if judged[1] != "same text again" {
+		t.Fatalf("judge saw %q", judged[1])
+	}
+}
+
+func TestJudgeFailureDoesNotAbort(t *testing.T) {
+	chat := func(context.Context, providers.ChatRequest) (*providers.ChatResponse, error) {
+		return nil, errors.New("judge down")
+	}
+	c, err := NewStopChecker(StopConditions{JudgePrompt: "p", JudgeModel: "m"}, chat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stop := c.Check(context.Background(), "text", 0); stop != nil {
+		t.Fatalf("judge outage aborted the run: %+v", stop)
+	}
+}
+
+func TestStopConditionsValidate(t *testing.T) {
+	if err := (&StopConditions{Patterns: []string{"("}}).Validate(); err == nil {
+		t.Fatal("bad regex accepted")
+	}
+	if err := (&StopConditions{JudgePrompt: "p"}).Validate(); err == nil {
+		t.Fatal("judge prompt without model accepted")
+	}
+	if err := (&StopConditions{MaxTotalTokens: -1}).Validate(); err == nil {
+		t.Fatal("negative token cap accepted")
+	}
+	if err := validateChain(Config{Model: "m", Stop: &StopConditions{Patterns: []string{"("}}}); err == nil {
+		t.Fatal("validateChain ignored stop conditions")
+	}
+}
+
+func TestBlendReturnsPartialResultOnStop(t *testing.T) {
+	chat := &blendChat{}
+	stop, err := NewStopChecker(StopConditions{Patterns: []string{`dir-2`}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Blender{Chat: chat.fn, VoiceA: "va", VoiceB: "vb", Director: "dir", Segments: 4, Stop: stop}
+	res, err := b.Blend(context.Background(), "Once upon a time")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Stopped == nil || res.Stopped.Condition != StopPattern {
+		t.Fatalf("stopped = %+v", res.Stopped)
+	}
+	if res.Text != "dir-1\n\ndir-2" || len(res.Recipe.Segments) != 2 {
+		t.Fatalf("partial result = %q, %d segments", res.Text, len(res.Recipe.Segments))
+	}
+}
@@ -0,0 +1,208 @@
+package agents
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Step is the telemetry for one chain step: which provider served it,
+// what it cost and how long it took, including retries.
+type Step struct {
+	Name      string    `json:"name"`
+	Provider  string    `json:"provider"`
+	Model     string    `json:"model"`
+	Tokens    int64     `json:"tokens"`
+	Cost      float64   `json:"cost"`
+	LatencyMs float64   `json:"latency_ms"`
+	Retries   int       `json:"retries"`
+	StartedAt time.Time `json:"started_at"`
+	// Violations are the tool policy breaches observed while this step
+	// executed (see ToolSession).
+	Violations []ToolViolation `json:"violations,omitempty"`
+}
+
+// ChainRun is one complete agent pipeline execution.
+type ChainRun struct {
+	ID        string    `json:"id"`
+	Agent     string    `json:"agent"`
+	Tenant    string    `json:"tenant,omitempty"`
+	Steps     []Step    `json:"steps"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	Failed    bool      `json:"failed"`
+	// Stopped records the stop condition that aborted the run early; nil
+	// when the chain ran to completion. A stopped run is not a failed
+	// one — its partial steps are valid output.
+	Stopped *Stop `json:"stopped,omitempty"`
+}
+
+// Totals sums the run's cost and tokens for list views.
+func (r *ChainRun) Totals() (tokens int64, cost float64) {
+	for _, s := range r.Steps {
+		tokens += s.Tokens
+		cost += s.Cost
+	}
+	return tokens, cost
+}
+
+// WaterfallRow positions one step on the WebUI waterfall: offset and
+// width in milliseconds relative to the run start.
+type WaterfallRow struct {
+	Name     string  `json:"name"`
+	OffsetMs float64 `json:"offset_ms"`
+	WidthMs  float64 `json:"width_ms"`
+	Cost     float64 `json:"cost"`
+	Retries  int     `json:"retries"`
+}
+
+// Waterfall derives the chart rows from the run's step timings.
+func (r *ChainRun) Waterfall() []WaterfallRow {
+	out := make([]WaterfallRow, 0, len(r.Steps))
+	for _, s := range r.Steps {
+		out = append(out, WaterfallRow{
+			Name:     s.Name,
+			OffsetMs: float64(s.StartedAt.Sub(r.StartedAt).Microseconds()) / 1000,
+			WidthMs:  s.LatencyMs,
+			Cost:     s.Cost,
+			Retries:  s.Retries,
+		})
+	}
+	return out
+}
+
+// RunRecorder accumulates one chain run and persists it on Finish.
+type RunRecorder struct {
+	Store RunStore
+
+	mu  sync.Mutex
+	run ChainRun
+}
+
+// NewRunRecorder opens a run for an agent.
+func NewRunRecorder(store RunStore, agent, tenant string) *RunRecorder {
+	return &RunRecorder{
+		Store: store,
+		run: ChainRun{
+			ID: uuid.NewString(), Agent: agent, Tenant: tenant, StartedAt: time.Now(),
+		},
+	}
+}
+
+// RecordStep appends one completed step.
+func (r *RunRecorder) RecordStep(s Step) {
+	if s.StartedAt.IsZero() {
+		s.StartedAt = time.Now()
+	}
+	r.mu.Lock()
+	r.run.Steps = append(r.run.Steps, s)
+	r.mu.Unlock()
+}
+
+// RecordStop marks the run as aborted early by a stop condition.
+func (r *RunRecorder) RecordStop(s Stop) {
+	r.mu.Lock()
+	r.run.Stopped = &s
+	r.mu.Unlock()
+}
+
+// Finish closes the run and persists it when a store is attached.
+func (r *RunRecorder) Finish(ctx context.Context, failed bool) (ChainRun, error) {
+	r.mu.Lock()
+	r.run.EndedAt = time.Now()
+	r.run.Failed = failed
+	run := r.run
+	r.mu.Unlock()
+	if r.Store != nil {
+		if err := r.Store.SaveRun(ctx, run); err != nil {
+			return run, err
+		}
+	}
+	return run, nil
+}
+
+// RunStore persists chain runs.
+type RunStore interface {
+	SaveRun(ctx context.Context, run ChainRun) error
+	RecentRuns(ctx context.Context, limit int) ([]ChainRun, error)
+}
+
+// SQLRunStore keeps runs in a chain_runs table, steps serialized as JSON
+// alongside the queryable totals.
+type SQLRunStore struct{ db *sql.DB }
+
+// NewSQLRunStore creates the table if needed.
+func NewSQLRunStore(ctx context.Context, db *sql.DB) (*SQLRunStore, error) {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS chain_runs (
+		id TEXT PRIMARY KEY,
+		agent TEXT NOT NULL,
+		tenant TEXT NOT NULL DEFAULT '',
+		steps TEXT NOT NULL,
+		tokens BIGINT NOT NULL,
+		cost DOUBLE PRECISION NOT NULL,
+		started_at TIMESTAMP NOT NULL,
+		ended_at TIMESTAMP NOT NULL,
+		failed BOOLEAN NOT NULL,
+		stopped TEXT NOT NULL DEFAULT ''
+	)`)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLRunStore{db: db}, nil
+}
+
+// SaveRun implements RunStore.
+func (s *SQLRunStore) SaveRun(ctx context.Context, run ChainRun) error {
+	steps, err := json.Marshal(run.Steps)
+	if err != nil {
+		return err
+	}
+	var stopped string
+	if run.Stopped != nil {
+		raw, err := json.Marshal(run.Stopped)
+		if err != nil {
+			return err
+		}
+		stopped = string(raw)
+	}
+	tokens, cost := run.Totals()
+	_, err = s.db.ExecContext(ctx, `INSERT INTO chain_runs
+		(id, agent, tenant, steps, tokens, cost, started_at, ended_at, failed, stopped)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		run.ID, run.Agent, run.Tenant, string(steps), tokens, cost,
+		run.StartedAt, run.EndedAt, run.Failed, stopped)
+	return err
+}
+
+// RecentRuns implements RunStore, newest first.
+func (s *SQLRunStore) RecentRuns(ctx context.Context, limit int) ([]ChainRun, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, agent, tenant, steps, started_at, ended_at, failed, stopped
+		FROM chain_runs ORDER BY started_at DESC LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ChainRun
+	for rows.Next() {
+		var run ChainRun
+		var steps, stopped string
+		if err := rows.Scan(&run.ID, &run.Agent, &run.Tenant, &steps, &run.StartedAt, &run.EndedAt, &run.Failed, &stopped); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(steps), &run.Steps); err != nil {
+			return nil, err
+		}
+		if stopped != "" {
+			run.Stopped = &Stop{}
+			if err := json.Unmarshal([]byte(stopped), run.Stopped); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, run)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,58 @@
+package agents
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type memRunStore struct{ runs []ChainRun }
+
+func (m *memRunStore) SaveRun(_ context.Context, run ChainRun) error {
+	m.runs = append(m.runs, run)
+	return nil
+}
+
+func (m *memRunStore) RecentRuns(context.Context, int) ([]ChainRun, error) {
+	return m.runs, nil
+}
+
+func TestRunRecorderPersistsOnFinish(t *testing.T) {
+	store := &memRunStore{}
+	rec := NewRunRecorder(store, "translate", "acme")
+	rec.RecordStep(Step{Name: "draft", Provider: "openrouter", Tokens: 120, Cost: 0.002, LatencyMs: 900})
+	rec.RecordStep(Step{Name: "refine", Provider: "openrouter", Tokens: 80, Cost: 0.001, LatencyMs: 400, Retries: 1})
+
+	run, err := rec.Finish(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.runs) != 1 || store.runs[0].ID != run.ID {
+		t.Fatalf("store.runs = %+v", store.runs)
+	}
+	tokens, cost := run.Totals()
+	if tokens != 200 || cost != 0.003 {
+		t.Fatalf("totals = %d, %v", tokens, cost)
+	}
+}
+
+func TestWaterfallOffsets(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	run := ChainRun{
+		StartedAt: start,
+		Steps: []Step{
+			{Name: "a", StartedAt: start, LatencyMs: 100},
+			{Name: "b", StartedAt: start.Add(150 * time.Millisecond), LatencyMs: 50, Retries: 2},
+		},
+	}
+	rows := run.Waterfall()
+	if len(rows) != 2 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	if rows[0].OffsetMs != 0 || rows[1].OffsetMs != 150 {
+		t.Fatalf("offsets = %v, %v", rows[0].OffsetMs, rows[1].OffsetMs)
+	}
+	if rows[1].WidthMs != 50 || rows[1].Retries != 2 {
+		t.Fatalf("row = %+v", rows[1])
+	}
+}
@@ -0,0 +1,185 @@
+package agents
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tool execution policy: the sandbox binary runs outside this process
+// (see Runner), so enforcement happens at the seams the gateway does
+// control — the wall-clock budget via the invocation context, the
+// environment handed to the sandbox, and the host/path checks the
+// sandbox calls back into before touching network or filesystem. Every
+// denial is recorded as a violation and lands in the run's telemetry.
+
+// defaultToolDuration caps tools that declare no budget of their own.
+const defaultToolDuration = 30 * time.Second
+
+// ToolPolicy constrains one tool's sandbox execution. The zero policy
+// is deny-by-default: no network, no filesystem, no environment.
+type ToolPolicy struct {
+	// AllowedHosts are the only hosts the tool may reach; empty means
+	// no network at all.
+	AllowedHosts []string `json:"allowed_hosts,omitempty"`
+	// ReadOnlyPaths are directory prefixes the tool may read. Writes
+	// are never permitted.
+	ReadOnlyPaths []string `json:"read_only_paths,omitempty"`
+	// MaxDuration bounds one execution; 0 uses defaultToolDuration.
+	MaxDuration time.Duration `json:"max_duration,omitempty"`
+	// EnvAllowlist names the environment variables passed through.
+	EnvAllowlist []string `json:"env_allowlist,omitempty"`
+}
+
+// ToolViolation is one policy breach observed during an execution.
+type ToolViolation struct {
+	Tool   string    `json:"tool"`
+	Kind   string    `json:"kind"` // "network", "filesystem", "env", "timeout"
+	Detail string    `json:"detail"`
+	At     time.Time `json:"at"`
+}
+
+// ToolPolicies is the per-tool policy registry.
+type ToolPolicies struct {
+	mu     sync.RWMutex
+	byTool map[string]ToolPolicy
+}
+
+// NewToolPolicies returns an empty registry; unknown tools get the
+// deny-by-default zero policy.
+func NewToolPolicies() *ToolPolicies {
+	return &ToolPolicies{byTool: make(map[string]ToolPolicy)}
+}
+
+// Set registers or replaces the policy for a tool.
+func (p *ToolPolicies) Set(tool string, policy ToolPolicy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.byTool[tool] = policy
+}
+
+// Get returns the tool's policy, or the zero policy when none is set.
+func (p *ToolPolicies) Get(tool string) ToolPolicy {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.byTool[tool]
+}
+
+// Session opens the enforcement session for one tool invocation.
+func (p *ToolPolicies) Session(tool string) *ToolSession {
+	return &ToolSession{
+		tool:   tool,
+		policy: p.Get(tool),
+		now:    time.Now,
+		OnViolation: func(v ToolViolation) {
+			log.Printf("agents: tool %s: %s policy violation: %s", v.Tool, v.Kind, v.Detail)
+		},
+	}
+}
+
+// ToolSession enforces one invocation's policy and accumulates its
+// violations for the chain telemetry.
+type ToolSession struct {
+	tool   string
+	policy ToolPolicy
+	now    func() time.Time
+	// OnViolation fires once per recorded violation, after it is
+	// stored; the default logs it.
+	OnViolation func(ToolViolation)
+
+	mu         sync.Mutex
+	violations []ToolViolation
+}
+
+// Context derives the execution context carrying the tool's time
+// budget. The caller passes it to the sandbox and must cancel it.
+func (s *ToolSession) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	d := s.policy.MaxDuration
+	if d <= 0 {
+		d = defaultToolDuration
+	}
+	return context.WithTimeout(parent, d)
+}
+
+// AllowHost reports whether the tool may reach a host; a denial is
+// recorded as a network violation.
+func (s *ToolSession) AllowHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range s.policy.AllowedHosts {
+		if strings.ToLower(h) == host {
+			return true
+		}
+	}
+	s.record("network", "connection to "+host+" denied")
+	return false
+}
+
+// AllowRead reports whether the tool may read a path: it must fall
+// under one of the policy's read-only prefixes.
+func (s *ToolSession) AllowRead(path string) bool {
+	cleaned := filepath.Clean(path)
+	for _, prefix := range s.policy.ReadOnlyPaths {
+		prefix = filepath.Clean(prefix)
+		if cleaned == prefix || strings.HasPrefix(cleaned, prefix+string(filepath.Separator)) {
+			return true
+		}
+	}
+	s.record("filesystem", "read of "+path+" denied")
+	return false
+}
+
+// AllowWrite always denies: tool filesystem access is read-only by
+// design, and attempts are worth surfacing.
+func (s *ToolSession) AllowWrite(path string) bool {
+	s.record("filesystem", "write to "+path+" denied (tool filesystem is read-only)")
+	return false
+}
+
+// FilterEnv reduces "KEY=value" pairs to the allowlisted keys; every
+// dropped key is recorded so leaky tool configurations get noticed.
+func (s *ToolSession) FilterEnv(env []string) []string {
+	allowed := make(map[string]bool, len(s.policy.EnvAllowlist))
+	for _, k := range s.policy.EnvAllowlist {
+		allowed[k] = true
+	}
+	out := make([]string, 0, len(env))
+	for _, kv := range env {
+		key := kv
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			key = kv[:i]
+		}
+		if allowed[key] {
+			out = append(out, kv)
+			continue
+		}
+		s.record("env", "variable "+key+" not in allowlist")
+	}
+	return out
+}
+
+// Finish closes the session: a context that ran out of its time budget
+// is recorded as a timeout violation. It returns every violation of the
+// invocation, ready to attach to the telemetry step.
+func (s *ToolSession) Finish(ctx context.Context) []ToolViolation {
+	if ctx.Err() == context.DeadlineExceeded {
+		s.record("timeout", "execution exceeded the tool's time budget")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]ToolViolation, len(s.violations))
+	copy(out, s.violations)
+	return out
+}
+
+func (s *ToolSession) record(kind, detail string) {
+	v := ToolViolation{Tool: s.tool, Kind: kind, Detail: detail, At: s.now()}
+	s.mu.Lock()
+	s.violations = append(s.violations, v)
+	s.mu.Unlock()
+	if s.OnViolation != nil {
+		s.OnViolation(v)
+	}
+}
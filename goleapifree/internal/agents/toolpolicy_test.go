@@ -0,0 +1,97 @@
+package agents
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestSession(policy ToolPolicy) *ToolSession {
+	p := NewToolPolicies()
+	p.Set("fetch", policy)
+	s := p.Session("fetch")
+	s.OnViolation = func(ToolViolation) {} // keep test output quiet
+	return s
+}
+
+func TestToolSessionNetworkPolicy(t *testing.T) {
+	s := newTestSession(ToolPolicy{AllowedHosts: []string{"api.example.com"}})
+	if !s.AllowHost("API.Example.Com") {
+		t.Fatal("allowlisted host denied")
+	}
+	if s.AllowHost("169.254.169.254") {
+		t.Fatal("unlisted host allowed")
+	}
+	violations := s.Finish(context.Background())
+	if len(violations) != 1 || violations[0].Kind != "network" || violations[0].Tool != "fetch" {
+		t.Fatalf("violations = %+v", violations)
+	}
+}
+
+func TestToolSessionFilesystemPolicy(t *testing.T) {
+	s := newTestSession(ToolPolicy{ReadOnlyPaths: []string{"/data/docs"}})
+	if !s.AllowRead("/data/docs/report.txt") {
+		t.Fatal("read under allowed prefix denied")
+	}
+	if s.AllowRead("/data/docs/../secrets") {
+		t.Fatal("path traversal escaped the prefix")
+	}
+	if s.AllowRead("/data/docsish/file") {
+		t.Fatal("sibling prefix treated as allowed")
+	}
+	if s.AllowWrite("/data/docs/report.txt") {
+		t.Fatal("write allowed despite read-only filesystem")
+	}
+	for _, v := range s.Finish(context.Background()) {
+		if v.Kind != "filesystem" {
+			t.Fatalf("violation kind = %q", v.Kind)
+		}
+	}
+}
+
+func TestToolSessionEnvFiltering(t *testing.T) {
+	s := newTestSession(ToolPolicy{EnvAllowlist: []string{"LANG"}})
+	got := s.FilterEnv([]string{"LANG=C", "AWS_SECRET_ACCESS_KEY=abc", "PATH=/bin"})
+	if len(got) != 1 || got[0] != "LANG=C" {
+		t.Fatalf("filtered env = %v", got)
+	}
+	violations := s.Finish(context.Background())
+	if len(violations) != 2 || violations[0].Kind != "env" {
+		t.Fatalf("violations = %+v", violations)
+	}
+}
+
+func TestToolSessionTimeBudget(t *testing.T) {
+	s := newTestSession(ToolPolicy{MaxDuration: time.Millisecond})
+	ctx, cancel := s.Context(context.Background())
+	defer cancel()
+	<-ctx.Done()
+	violations := s.Finish(ctx)
+	if len(violations) != 1 || violations[0].Kind != "timeout" {
+		t.Fatalf("violations = %+v", violations)
+	}
+
+	// Default budget applies when the policy declares none.
+	s = newTestSession(ToolPolicy{})
+	ctx, cancel = s.Context(context.Background())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > defaultToolDuration {
+		t.Fatalf("default deadline = %v", deadline)
+	}
+	if got := s.Finish(ctx); len(got) != 0 {
+		t.Fatalf("unexpected violations: %+v", got)
+	}
+}
+
+func TestUnknownToolDeniesEverything(t *testing.T) {
+	p := NewToolPolicies()
+	s := p.Session("unregistered")
+	s.OnViolation = func(ToolViolation) {}
+	if s.AllowHost("example.com") || s.AllowRead("/tmp/x") {
+		t.Fatal("zero policy granted access")
+	}
+	if got := s.FilterEnv([]string{"HOME=/root"}); len(got) != 0 {
+		t.Fatalf("zero policy passed env: %v", got)
+	}
+}
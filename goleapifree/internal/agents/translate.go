@@ -0,0 +1,151 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// Glossary asset names in the prompt registry. Glossaries are stored as
+// "term = translation" lines; style guides are free text.
+const (
+	AssetGlossary   = "translate.glossary"
+	AssetStyleGuide = "translate.style"
+)
+
+// Translator is the translation agent: one translation pass followed by
+// glossary enforcement and a back-translation check, refining up to
+// MaxRefine times when either fails.
+type Translator struct {
+	Chat  ChatFunc
+	Reg   *PromptRegistry
+	Model string
+	// MaxRefine caps refinement rounds; defaults to 2.
+	MaxRefine int
+	// BackTranslationOverlap is the minimum word overlap between the
+	// source and the back-translation before a refine is triggered;
+	// defaults to 0.5.
+	BackTranslationOverlap float64
+}
+
+// Translate renders text from src to dst for a tenant, applying the
+// tenant's glossary and style guide when present.
+func (t *Translator) Translate(ctx context.Context, tenant, text, src, dst string) (string, error) {
+	maxRefine := t.MaxRefine
+	if maxRefine == 0 {
+		maxRefine = 2
+	}
+	glossary := t.glossary(tenant)
+
+	out, err := t.translateOnce(ctx, tenant, text, src, dst, "")
+	if err != nil {
+		return "", err
+	}
+	for round := 0; round < maxRefine; round++ {
+		problems := t.check(ctx, text, out, src, dst, glossary)
+		if len(problems) == 0 {
+			return out, nil
+		}
+		out, err = t.translateOnce(ctx, tenant, text, src, dst,
+			"The previous attempt had these problems, fix them:\n- "+strings.Join(problems, "\n- "))
+		if err != nil {
+			return "", err
+		}
+	}
+	return out, nil
+}
+
+// translateOnce performs one translation pass.
+func (t *Translator) translateOnce(ctx context.Context, tenant, text, src, dst, feedback string) (string, error) {
+	var sys strings.Builder
+	fmt.Fprintf(&sys, "Translate the user's text from %s to %s. Reply with the translation only.", src, dst)
+	if style, ok := t.Reg.Get(tenant, AssetStyleGuide); ok {
+		sys.WriteString("\nStyle guide:\n" + style)
+	}
+	if gl, ok := t.Reg.Get(tenant, AssetGlossary); ok {
+		sys.WriteString("\nUse these exact term translations:\n" + gl)
+	}
+	if feedback != "" {
+		sys.WriteString("\n" + feedback)
+	}
+	return completion(t.Chat(ctx, providers.ChatRequest{
+		Model: t.Model,
+		Messages: []providers.Message{
+			{Role: "system", Content: sys.String()},
+			{Role: "user", Content: text},
+		},
+	}))
+}
+
+// check validates glossary usage and the back-translation, returning the
+// problems the refine step must fix.
+func (t *Translator) check(ctx context.Context, source, out, src, dst string, glossary map[string]string) []string {
+	var problems []string
+	lowerSource, lowerOut := strings.ToLower(source), strings.ToLower(out)
+	for term, want := range glossary {
+		if strings.Contains(lowerSource, strings.ToLower(term)) &&
+			!strings.Contains(lowerOut, strings.ToLower(want)) {
+			problems = append(problems, fmt.Sprintf("glossary term %q must be translated as %q", term, want))
+		}
+	}
+
+	back, err := completion(t.Chat(ctx, providers.ChatRequest{
+		Model: t.Model,
+		Messages: []providers.Message{
+			{Role: "system", Content: fmt.Sprintf("Translate the user's text from %s to %s. Reply with the translation only.", dst, src)},
+			{Role: "user", Content: out},
+		},
+	}))
+	if err == nil {
+		threshold := t.BackTranslationOverlap
+		if threshold == 0 {
+			threshold = 0.5
+		}
+		if wordOverlap(source, back) < threshold {
+			problems = append(problems, "back-translation diverges from the source, meaning was lost")
+		}
+	}
+	return problems
+}
+
+// glossary parses the tenant's "term = translation" lines.
+func (t *Translator) glossary(tenant string) map[string]string {
+	raw, ok := t.Reg.Get(tenant, AssetGlossary)
+	if !ok {
+		return nil
+	}
+	out := make(map[string]string)
+	for _, line := range strings.Split(raw, "\n") {
+		term, want, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		term, want = strings.TrimSpace(term), strings.TrimSpace(want)
+		if term != "" && want != "" {
+			out[term] = want
+		}
+	}
+	return out
+}
+
+// wordOverlap is the fraction of source words present in the candidate,
+// a cheap semantic-drift signal for the back-translation check.
+func wordOverlap(source, candidate string) float64 {
+	sourceWords := strings.Fields(strings.ToLower(source))
+	if len(sourceWords) == 0 {
+		return 1
+	}
+	candSet := make(map[string]bool)
+	for _, w := range strings.Fields(strings.ToLower(candidate)) {
+		candSet[strings.Trim(w, ".,!?;:")] = true
+	}
+	hits := 0
+	for _, w := range sourceWords {
+		if candSet[strings.Trim(w, ".,!?;:")] {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(sourceWords))
+}
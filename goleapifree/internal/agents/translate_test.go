@@ -0,0 +1,84 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// scriptedChat distinguishes forward and back translations by the system
+// prompt and returns canned outputs, counting forward calls.
+type scriptedChat struct {
+	forward []string // successive forward-translation outputs
+	back    string
+	calls   int
+}
+
+func (s *scriptedChat) fn(_ context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+	sys := req.Messages[0].Content
+	if strings.Contains(sys, "from it to en") {
+		return &providers.ChatResponse{Content: s.back}, nil
+	}
+	out := s.forward[min(s.calls, len(s.forward)-1)]
+	s.calls++
+	return &providers.ChatResponse{Content: out}, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func TestGlossaryEnforcementTriggersRefine(t *testing.T) {
+	reg := NewPromptRegistry()
+	reg.Set("acme", AssetGlossary, "invoice = fattura")
+
+	chat := &scriptedChat{
+		// First pass misses the glossary term, second honors it.
+		forward: []string{"manda la ricevuta", "manda la fattura"},
+		back:    "send the invoice",
+	}
+	tr := &Translator{Chat: chat.fn, Reg: reg, Model: "m"}
+	out, err := tr.Translate(context.Background(), "acme", "send the invoice", "en", "it")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "manda la fattura" {
+		t.Fatalf("out = %q", out)
+	}
+	if chat.calls != 2 {
+		t.Fatalf("forward calls = %d, want a refine round", chat.calls)
+	}
+}
+
+func TestBackTranslationDivergenceTriggersRefine(t *testing.T) {
+	reg := NewPromptRegistry()
+	chat := &scriptedChat{
+		forward: []string{"qualcosa di completamente diverso"},
+		back:    "entirely unrelated words here",
+	}
+	tr := &Translator{Chat: chat.fn, Reg: reg, Model: "m", MaxRefine: 1}
+	if _, err := tr.Translate(context.Background(), "acme", "send the invoice today", "en", "it"); err != nil {
+		t.Fatal(err)
+	}
+	if chat.calls != 2 {
+		t.Fatalf("forward calls = %d, want a refine round", chat.calls)
+	}
+}
+
+func TestTenantGlossaryShadowsGlobal(t *testing.T) {
+	reg := NewPromptRegistry()
+	reg.Set("", AssetGlossary, "invoice = ricevuta")
+	reg.Set("acme", AssetGlossary, "invoice = fattura")
+	tr := &Translator{Reg: reg}
+	if got := tr.glossary("acme")["invoice"]; got != "fattura" {
+		t.Fatalf("tenant glossary = %q", got)
+	}
+	if got := tr.glossary("other")["invoice"]; got != "ricevuta" {
+		t.Fatalf("global fallback = %q", got)
+	}
+}
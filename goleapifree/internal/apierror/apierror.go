@@ -0,0 +1,150 @@
+// Package apierror defines the unified error envelope returned by the
+// public API, the admin API and the SDK: a machine-readable code, a human
+// message, a retryable hint, an optional provider error passthrough, and
+// a docs link.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Code identifies an error category across all API surfaces.
+type Code string
+
+// Error codes. Codes are stable API; add, never rename.
+const (
+	CodeInvalidRequest   Code = "invalid_request"
+	CodeUnauthorized     Code = "unauthorized"
+	CodeForbidden        Code = "forbidden"
+	CodeNotFound         Code = "not_found"
+	CodeRateLimited      Code = "rate_limited"
+	CodeOverloaded       Code = "overloaded"
+	CodeProviderError    Code = "provider_error"
+	CodeNoProvider       Code = "no_provider_available"
+	CodeGuardrailBlocked Code = "guardrail_blocked"
+	CodeInternal         Code = "internal_error"
+)
+
+// docsBase is the prefix for per-code documentation links.
+const docsBase = "https://github.com/biodoia/goleapifree/blob/main/docs/errors.md#"
+
+// Error is the internal error type carrying envelope fields. It wraps an
+// optional cause for logs while the envelope stays client-safe.
+type Error struct {
+	Code      Code   `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+	// ProviderError passes the upstream provider's error body through
+	// verbatim when translating provider failures.
+	ProviderError json.RawMessage `json:"provider_error,omitempty"`
+	DocsURL       string          `json:"docs_url"`
+
+	status int
+	cause  error
+}
+
+// New creates an envelope error.
+func New(code Code, message string) *Error {
+	return &Error{
+		Code:      code,
+		Message:   message,
+		Retryable: code == CodeRateLimited || code == CodeOverloaded || code == CodeProviderError,
+		DocsURL:   docsBase + string(code),
+		status:    defaultStatus(code),
+	}
+}
+
+// Newf creates an envelope error with a formatted message.
+func Newf(code Code, format string, args ...any) *Error {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// WithCause attaches an internal cause (not serialized).
+func (e *Error) WithCause(err error) *Error {
+	e.cause = err
+	return e
+}
+
+// WithProviderError attaches the upstream body passthrough.
+func (e *Error) WithProviderError(raw []byte) *Error {
+	e.ProviderError = raw
+	return e
+}
+
+// WithStatus overrides the HTTP status.
+func (e *Error) WithStatus(status int) *Error {
+	e.status = status
+	return e
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap exposes the cause to errors.Is/As.
+func (e *Error) Unwrap() error { return e.cause }
+
+// Status returns the HTTP status for the envelope.
+func (e *Error) Status() int { return e.status }
+
+func defaultStatus(code Code) int {
+	switch code {
+	case CodeInvalidRequest:
+		return fiber.StatusBadRequest
+	case CodeUnauthorized:
+		return fiber.StatusUnauthorized
+	case CodeForbidden, CodeGuardrailBlocked:
+		return fiber.StatusForbidden
+	case CodeNotFound:
+		return fiber.StatusNotFound
+	case CodeRateLimited, CodeOverloaded:
+		return fiber.StatusTooManyRequests
+	case CodeProviderError, CodeNoProvider:
+		return fiber.StatusBadGateway
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// Handler is the Fiber error handler emitting the envelope for every
+// error that escapes a route. Plain fiber errors and unknown errors are
+// mapped onto the closest code.
+func Handler(c *fiber.Ctx, err error) error {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			apiErr = New(codeForStatus(fiberErr.Code), fiberErr.Message).WithStatus(fiberErr.Code)
+		} else {
+			apiErr = New(CodeInternal, "internal error").WithCause(err)
+		}
+	}
+	return c.Status(apiErr.Status()).JSON(fiber.Map{"error": apiErr})
+}
+
+func codeForStatus(status int) Code {
+	switch status {
+	case fiber.StatusBadRequest:
+		return CodeInvalidRequest
+	case fiber.StatusUnauthorized:
+		return CodeUnauthorized
+	case fiber.StatusForbidden:
+		return CodeForbidden
+	case fiber.StatusNotFound:
+		return CodeNotFound
+	case fiber.StatusTooManyRequests:
+		return CodeRateLimited
+	case fiber.StatusBadGateway, fiber.StatusServiceUnavailable, fiber.StatusNotImplemented:
+		return CodeProviderError
+	default:
+		return CodeInternal
+	}
+}
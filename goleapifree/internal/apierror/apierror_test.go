@@ -0,0 +1,66 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func fetchError(t *testing.T, handler fiber.Handler) (int, map[string]any) {
+	t.Helper()
+	app := fiber.New(fiber.Config{ErrorHandler: Handler})
+	app.Get("/", handler)
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	var parsed struct {
+		Error map[string]any `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		t.Fatalf("non-envelope body: %s", body)
+	}
+	return resp.StatusCode, parsed.Error
+}
+
+func TestEnvelopeFields(t *testing.T) {
+	status, env := fetchError(t, func(c *fiber.Ctx) error {
+		return New(CodeRateLimited, "slow down").
+			WithProviderError([]byte(`{"upstream":"limit"}`))
+	})
+	if status != fiber.StatusTooManyRequests {
+		t.Fatalf("status = %d", status)
+	}
+	if env["code"] != "rate_limited" || env["retryable"] != true {
+		t.Fatalf("envelope = %v", env)
+	}
+	if env["provider_error"] == nil || env["docs_url"] == nil {
+		t.Fatalf("missing passthrough/docs fields: %v", env)
+	}
+}
+
+func TestPlainErrorsAreMapped(t *testing.T) {
+	status, env := fetchError(t, func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusNotFound, "nope")
+	})
+	if status != fiber.StatusNotFound || env["code"] != "not_found" {
+		t.Fatalf("status %d env %v", status, env)
+	}
+
+	status, env = fetchError(t, func(c *fiber.Ctx) error {
+		return errors.New("boom")
+	})
+	if status != fiber.StatusInternalServerError || env["code"] != "internal_error" {
+		t.Fatalf("status %d env %v", status, env)
+	}
+	// Internal causes must never leak to clients.
+	if env["message"] == "boom" {
+		t.Fatal("internal error message leaked")
+	}
+}
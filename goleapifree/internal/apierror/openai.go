@@ -0,0 +1,130 @@
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// OpenAIError is the error object OpenAI SDKs expect. Provider failures
+// are translated into this shape before reaching clients so failover
+// between providers never changes the error contract mid-conversation.
+type OpenAIError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Param   string `json:"param,omitempty"`
+	Code    string `json:"code,omitempty"`
+	// XProviderError is a vendor extension preserving the upstream
+	// provider's original error body for debugging.
+	XProviderError json.RawMessage `json:"x_provider_error,omitempty"`
+}
+
+// TranslateProvider converts an upstream error response into the OpenAI
+// error shape. providerType selects the parser; unknown providers fall
+// back to a status-based translation with the raw body preserved.
+func TranslateProvider(providerType string, status int, body []byte) *OpenAIError {
+	out := &OpenAIError{
+		Type:           typeForStatus(status),
+		Code:           codeStringForStatus(status),
+		XProviderError: preserveRaw(body),
+	}
+	switch providerType {
+	case "anthropic", "bedrock":
+		var parsed struct {
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(body, &parsed) == nil && parsed.Error.Message != "" {
+			out.Message = parsed.Error.Message
+			if parsed.Error.Type == "overloaded_error" {
+				out.Type, out.Code = "server_error", "overloaded"
+			}
+			return out
+		}
+	case "vertex", "google":
+		var parsed struct {
+			Error struct {
+				Message string `json:"message"`
+				Status  string `json:"status"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(body, &parsed) == nil && parsed.Error.Message != "" {
+			out.Message = parsed.Error.Message
+			if parsed.Error.Status == "RESOURCE_EXHAUSTED" {
+				out.Type, out.Code = "rate_limit_error", "rate_limit_exceeded"
+			}
+			return out
+		}
+	default:
+		// OpenAI-compatible providers (openai, openrouter, groq, ...)
+		// already use the target shape; re-parse to normalize.
+		var parsed struct {
+			Error struct {
+				Message string `json:"message"`
+				Type    string `json:"type"`
+				Param   string `json:"param"`
+				Code    string `json:"code"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(body, &parsed) == nil && parsed.Error.Message != "" {
+			out.Message = parsed.Error.Message
+			if parsed.Error.Type != "" {
+				out.Type = parsed.Error.Type
+			}
+			out.Param = parsed.Error.Param
+			if parsed.Error.Code != "" {
+				out.Code = parsed.Error.Code
+			}
+			return out
+		}
+	}
+	// Unparseable body: synthesize a message from the status.
+	out.Message = http.StatusText(status)
+	if out.Message == "" {
+		out.Message = "provider error"
+	}
+	return out
+}
+
+// typeForStatus maps HTTP statuses to OpenAI error types.
+func typeForStatus(status int) string {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return "authentication_error"
+	case status == http.StatusNotFound:
+		return "invalid_request_error"
+	case status == http.StatusTooManyRequests:
+		return "rate_limit_error"
+	case status >= 500:
+		return "server_error"
+	case status >= 400:
+		return "invalid_request_error"
+	default:
+		return "server_error"
+	}
+}
+
+func codeStringForStatus(status int) string {
+	switch status {
+	case http.StatusTooManyRequests:
+		return "rate_limit_exceeded"
+	case http.StatusUnauthorized:
+		return "invalid_api_key"
+	default:
+		return ""
+	}
+}
+
+// preserveRaw keeps the body as-is when it is valid JSON, otherwise wraps
+// it in a JSON string so the envelope stays well-formed.
+func preserveRaw(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return body
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
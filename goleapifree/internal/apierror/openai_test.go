@@ -0,0 +1,43 @@
+package apierror
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranslateAnthropicOverloaded(t *testing.T) {
+	body := []byte(`{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`)
+	e := TranslateProvider("anthropic", 529, body)
+	if e.Type != "server_error" || e.Code != "overloaded" || e.Message != "Overloaded" {
+		t.Fatalf("translated = %+v", e)
+	}
+	if string(e.XProviderError) != string(body) {
+		t.Fatal("original body not preserved")
+	}
+}
+
+func TestTranslateVertexQuota(t *testing.T) {
+	body := []byte(`{"error":{"code":429,"message":"Quota exceeded","status":"RESOURCE_EXHAUSTED"}}`)
+	e := TranslateProvider("vertex", 429, body)
+	if e.Type != "rate_limit_error" || e.Code != "rate_limit_exceeded" {
+		t.Fatalf("translated = %+v", e)
+	}
+}
+
+func TestTranslateOpenAIPassthrough(t *testing.T) {
+	body := []byte(`{"error":{"message":"bad param","type":"invalid_request_error","param":"temperature","code":"invalid_value"}}`)
+	e := TranslateProvider("openrouter", 400, body)
+	if e.Param != "temperature" || e.Code != "invalid_value" {
+		t.Fatalf("translated = %+v", e)
+	}
+}
+
+func TestTranslateUnparseableBody(t *testing.T) {
+	e := TranslateProvider("openai", 503, []byte("<html>gateway timeout</html>"))
+	if e.Type != "server_error" || e.Message == "" {
+		t.Fatalf("translated = %+v", e)
+	}
+	if !strings.Contains(string(e.XProviderError), "gateway timeout") {
+		t.Fatalf("raw body lost: %s", e.XProviderError)
+	}
+}
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Impersonation limits.
+const (
+	// maxImpersonation caps a session regardless of what was requested;
+	// reproducing a user issue should not need longer.
+	maxImpersonation = time.Hour
+	// maxAuditEntries bounds the in-memory audit trail.
+	maxAuditEntries = 10000
+)
+
+// Impersonation errors.
+var (
+	ErrSessionNotFound   = errors.New("auth: impersonation session not found")
+	ErrAlreadyActive     = errors.New("auth: admin already has an active impersonation session")
+	ErrSelfImpersonation = errors.New("auth: cannot impersonate yourself")
+)
+
+// ImpersonationSession is one admin temporarily acting as a user.
+type ImpersonationSession struct {
+	ID        string     `json:"id"`
+	Admin     string     `json:"admin"`
+	Target    string     `json:"target"`
+	Reason    string     `json:"reason"`
+	StartedAt time.Time  `json:"started_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	// Requests counts how many requests ran under the session.
+	Requests int `json:"requests"`
+}
+
+// AuditEntry is one impersonation event: session start, stop, expiry.
+type AuditEntry struct {
+	At      time.Time `json:"at"`
+	Admin   string    `json:"admin"`
+	Target  string    `json:"target"`
+	Session string    `json:"session"`
+	Action  string    `json:"action"`
+	Detail  string    `json:"detail,omitempty"`
+}
+
+// Impersonator lets admins temporarily act as a specific user to
+// reproduce reported issues. The effective identity inherits the
+// target's team (and with it quotas, providers and flags) via the
+// Lookup hook; sessions are time-limited and every transition lands in
+// the audit trail.
+type Impersonator struct {
+	// Lookup resolves a target user to their full identity; the wiring
+	// binds it to the user directory. nil yields a bare identity with
+	// just the user name set.
+	Lookup func(user string) (*Identity, bool)
+
+	now func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*ImpersonationSession // keyed by admin
+	audit    []AuditEntry
+}
+
+// NewImpersonator creates an impersonator with an empty audit trail.
+func NewImpersonator() *Impersonator {
+	return &Impersonator{
+		now:      time.Now,
+		sessions: make(map[string]*ImpersonationSession),
+	}
+}
+
+// Start opens a session for admin acting as target. The duration is
+// capped at one hour; 0 takes the cap. One active session per admin.
+func (imp *Impersonator) Start(admin, target, reason string, d time.Duration) (ImpersonationSession, error) {
+	if admin == target {
+		return ImpersonationSession{}, ErrSelfImpersonation
+	}
+	if d <= 0 || d > maxImpersonation {
+		d = maxImpersonation
+	}
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	if s, ok := imp.sessions[admin]; ok && imp.activeLocked(s) {
+		return ImpersonationSession{}, ErrAlreadyActive
+	}
+	now := imp.now()
+	s := &ImpersonationSession{
+		ID:        uuid.NewString(),
+		Admin:     admin,
+		Target:    target,
+		Reason:    reason,
+		StartedAt: now,
+		ExpiresAt: now.Add(d),
+	}
+	imp.sessions[admin] = s
+	imp.recordLocked(s, "start", reason)
+	return *s, nil
+}
+
+// Stop ends the admin's active session.
+func (imp *Impersonator) Stop(admin string) error {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	s, ok := imp.sessions[admin]
+	if !ok || !imp.activeLocked(s) {
+		return ErrSessionNotFound
+	}
+	now := imp.now()
+	s.EndedAt = &now
+	imp.recordLocked(s, "stop", fmt.Sprintf("%d requests", s.Requests))
+	return nil
+}
+
+// Effective returns the identity a request should run as: the target's
+// identity while the actor has an active session, the actor otherwise.
+// The returned identity carries the admin in Impersonator so accounting
+// and logs can tell the two apart.
+func (imp *Impersonator) Effective(actor *Identity) *Identity {
+	if actor == nil {
+		return nil
+	}
+	imp.mu.Lock()
+	s, ok := imp.sessions[actor.User]
+	if !ok || !imp.activeLocked(s) {
+		imp.mu.Unlock()
+		return actor
+	}
+	s.Requests++
+	target := s.Target
+	imp.mu.Unlock()
+
+	effective := &Identity{User: target}
+	if imp.Lookup != nil {
+		if id, ok := imp.Lookup(target); ok {
+			clone := *id
+			effective = &clone
+		}
+	}
+	effective.Impersonator = actor.User
+	return effective
+}
+
+// Middleware swaps the resolved identity for the impersonation target.
+// It must run after the proxy auth middleware.
+func (imp *Impersonator) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if id, ok := IdentityFrom(c); ok {
+			if effective := imp.Effective(id); effective != id {
+				c.Locals(identityKey, effective)
+			}
+		}
+		return c.Next()
+	}
+}
+
+// activeLocked reports whether a session is live, closing it with an
+// audit entry on first sight after expiry. Callers hold imp.mu.
+func (imp *Impersonator) activeLocked(s *ImpersonationSession) bool {
+	if s.EndedAt != nil {
+		return false
+	}
+	if now := imp.now(); now.After(s.ExpiresAt) {
+		s.EndedAt = &now
+		imp.recordLocked(s, "expired", fmt.Sprintf("%d requests", s.Requests))
+		return false
+	}
+	return true
+}
+
+// recordLocked appends one audit entry. Callers hold imp.mu.
+func (imp *Impersonator) recordLocked(s *ImpersonationSession, action, detail string) {
+	imp.audit = append(imp.audit, AuditEntry{
+		At: imp.now(), Admin: s.Admin, Target: s.Target,
+		Session: s.ID, Action: action, Detail: detail,
+	})
+	if len(imp.audit) > maxAuditEntries {
+		imp.audit = imp.audit[len(imp.audit)-maxAuditEntries:]
+	}
+}
+
+// Audit returns the impersonation audit trail, oldest first.
+func (imp *Impersonator) Audit() []AuditEntry {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	out := make([]AuditEntry, len(imp.audit))
+	copy(out, imp.audit)
+	return out
+}
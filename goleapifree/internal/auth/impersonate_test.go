@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func testImpersonator() (*Impersonator, *time.Time) {
+	imp := NewImpersonator()
+	at := time.Date(2026, 9, 1, 14, 0, 0, 0, time.UTC)
+	imp.now = func() time.Time { return at }
+	return imp, &at
+}
+
+func TestImpersonationLifecycle(t *testing.T) {
+	imp, _ := testImpersonator()
+	imp.Lookup = func(user string) (*Identity, bool) {
+		if user == "sam" {
+			return &Identity{User: "sam", Team: "research"}, true
+		}
+		return nil, false
+	}
+
+	s, err := imp.Start("admin", "sam", "ticket #812", 30*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ExpiresAt.Sub(s.StartedAt) != 30*time.Minute {
+		t.Fatalf("session = %+v", s)
+	}
+	if _, err := imp.Start("admin", "kim", "", 0); !errors.Is(err, ErrAlreadyActive) {
+		t.Fatalf("second session: %v", err)
+	}
+
+	// Requests run as the target, tagged with the real admin.
+	eff := imp.Effective(&Identity{User: "admin", Team: "ops"})
+	if eff.User != "sam" || eff.Team != "research" || eff.Impersonator != "admin" {
+		t.Fatalf("effective = %+v", eff)
+	}
+	// Other users are untouched.
+	other := &Identity{User: "kim"}
+	if imp.Effective(other) != other {
+		t.Fatal("bystander identity swapped")
+	}
+
+	if err := imp.Stop("admin"); err != nil {
+		t.Fatal(err)
+	}
+	if eff := imp.Effective(&Identity{User: "admin"}); eff.User != "admin" {
+		t.Fatalf("identity still swapped after stop: %+v", eff)
+	}
+
+	audit := imp.Audit()
+	if len(audit) != 2 || audit[0].Action != "start" || audit[1].Action != "stop" {
+		t.Fatalf("audit = %+v", audit)
+	}
+	if audit[1].Detail != "1 requests" {
+		t.Fatalf("stop detail = %q", audit[1].Detail)
+	}
+}
+
+func TestImpersonationExpires(t *testing.T) {
+	imp, at := testImpersonator()
+	if _, err := imp.Start("admin", "sam", "", 10*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	*at = at.Add(11 * time.Minute)
+	if eff := imp.Effective(&Identity{User: "admin"}); eff.User != "admin" {
+		t.Fatalf("expired session still active: %+v", eff)
+	}
+	audit := imp.Audit()
+	if len(audit) != 2 || audit[1].Action != "expired" {
+		t.Fatalf("audit = %+v", audit)
+	}
+	// The admin can start a fresh session after expiry.
+	if _, err := imp.Start("admin", "sam", "", 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImpersonationGuards(t *testing.T) {
+	imp, _ := testImpersonator()
+	if _, err := imp.Start("admin", "admin", "", 0); !errors.Is(err, ErrSelfImpersonation) {
+		t.Fatalf("self impersonation: %v", err)
+	}
+	if err := imp.Stop("admin"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("stop without session: %v", err)
+	}
+	if imp.Effective(nil) != nil {
+		t.Fatal("nil identity not passed through")
+	}
+}
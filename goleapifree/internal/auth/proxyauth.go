@@ -0,0 +1,139 @@
+// Package auth resolves caller identity. Deployments behind an SSO
+// proxy (oauth2-proxy, Pomerium, ...) already authenticate users there;
+// this package maps the proxy's trusted headers onto gateway users and
+// teams so no duplicate user management is needed.
+package auth
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Default header names, matching what oauth2-proxy forwards.
+const (
+	DefaultUserHeader   = "X-Forwarded-User"
+	DefaultEmailHeader  = "X-Forwarded-Email"
+	DefaultGroupsHeader = "X-Auth-Request-Groups"
+)
+
+// identityKey is the fiber locals key the middleware stores under.
+const identityKey = "auth.identity"
+
+// Identity is the resolved caller.
+type Identity struct {
+	User   string   `json:"user"`
+	Email  string   `json:"email,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+	// Team is the gateway team derived from the group mapping; it is
+	// what accounting and quotas key on (the stats "tenant").
+	Team string `json:"team,omitempty"`
+	// Impersonator names the admin actually behind the request when it
+	// runs under an impersonation session.
+	Impersonator string `json:"impersonator,omitempty"`
+}
+
+// ProxyAuth parses identity headers set by an upstream auth proxy.
+// Headers are only honored when the request arrives from a trusted
+// proxy address — otherwise any client could forge them.
+type ProxyAuth struct {
+	UserHeader   string
+	EmailHeader  string
+	GroupsHeader string
+	// TeamForGroup maps SSO group names to gateway teams; the first
+	// group with a mapping wins. Unmapped users fall to DefaultTeam.
+	TeamForGroup map[string]string
+	DefaultTeam  string
+
+	trusted []*net.IPNet
+}
+
+// New creates a ProxyAuth with oauth2-proxy's default header names,
+// trusting the given proxy CIDRs (e.g. "10.0.0.0/8", "127.0.0.1/32").
+func New(trustedCIDRs ...string) (*ProxyAuth, error) {
+	a := &ProxyAuth{
+		UserHeader:   DefaultUserHeader,
+		EmailHeader:  DefaultEmailHeader,
+		GroupsHeader: DefaultGroupsHeader,
+		TeamForGroup: make(map[string]string),
+	}
+	for _, cidr := range trustedCIDRs {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("auth: invalid trusted CIDR %q: %w", cidr, err)
+		}
+		a.trusted = append(a.trusted, ipnet)
+	}
+	return a, nil
+}
+
+// Trusted reports whether remoteIP belongs to a trusted proxy.
+func (a *ProxyAuth) Trusted(remoteIP string) bool {
+	ip := net.ParseIP(remoteIP)
+	if ip == nil {
+		return false
+	}
+	for _, ipnet := range a.trusted {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve builds an Identity from a header lookup, or nil when the
+// request came from an untrusted address or carries no user header.
+func (a *ProxyAuth) Resolve(remoteIP string, header func(string) string) *Identity {
+	if !a.Trusted(remoteIP) {
+		return nil
+	}
+	user := strings.TrimSpace(header(a.UserHeader))
+	if user == "" {
+		return nil
+	}
+	id := &Identity{
+		User:   user,
+		Email:  strings.TrimSpace(header(a.EmailHeader)),
+		Groups: splitGroups(header(a.GroupsHeader)),
+		Team:   a.DefaultTeam,
+	}
+	for _, g := range id.Groups {
+		if team, ok := a.TeamForGroup[g]; ok {
+			id.Team = team
+			break
+		}
+	}
+	return id
+}
+
+// Middleware attaches the resolved identity to the request context.
+// Requests without one pass through untouched; whether anonymous access
+// is allowed stays a per-route decision.
+func (a *ProxyAuth) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if id := a.Resolve(c.IP(), func(name string) string { return c.Get(name) }); id != nil {
+			c.Locals(identityKey, id)
+		}
+		return c.Next()
+	}
+}
+
+// IdentityFrom returns the identity the middleware resolved, if any.
+func IdentityFrom(c *fiber.Ctx) (*Identity, bool) {
+	id, ok := c.Locals(identityKey).(*Identity)
+	return id, ok
+}
+
+// splitGroups parses oauth2-proxy's group list, which may be comma or
+// space separated depending on provider configuration.
+func splitGroups(raw string) []string {
+	var out []string
+	for _, part := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if g := strings.TrimSpace(part); g != "" {
+			out = append(out, g)
+		}
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func headers(m map[string]string) func(string) string {
+	return func(name string) string { return m[name] }
+}
+
+func TestResolveMapsGroupsToTeam(t *testing.T) {
+	a, err := New("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.TeamForGroup["eng-platform"] = "platform"
+	a.DefaultTeam = "everyone"
+
+	id := a.Resolve("10.1.2.3", headers(map[string]string{
+		DefaultUserHeader:   "alice",
+		DefaultEmailHeader:  "alice@example.com",
+		DefaultGroupsHeader: "ops, eng-platform",
+	}))
+	if id == nil {
+		t.Fatal("expected identity")
+	}
+	if id.User != "alice" || id.Email != "alice@example.com" || id.Team != "platform" {
+		t.Fatalf("identity = %+v", id)
+	}
+	if !reflect.DeepEqual(id.Groups, []string{"ops", "eng-platform"}) {
+		t.Fatalf("groups = %v", id.Groups)
+	}
+
+	// Unmapped groups fall back to the default team.
+	id = a.Resolve("10.1.2.3", headers(map[string]string{
+		DefaultUserHeader:   "bob",
+		DefaultGroupsHeader: "marketing",
+	}))
+	if id.Team != "everyone" {
+		t.Fatalf("team = %q, want default", id.Team)
+	}
+}
+
+func TestUntrustedSourceIsIgnored(t *testing.T) {
+	a, err := New("127.0.0.1/32")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := headers(map[string]string{DefaultUserHeader: "mallory"})
+
+	// Headers from outside the trusted range must not mint identities.
+	if id := a.Resolve("203.0.113.7", h); id != nil {
+		t.Fatalf("identity = %+v, want nil", id)
+	}
+	if id := a.Resolve("not-an-ip", h); id != nil {
+		t.Fatalf("identity = %+v, want nil", id)
+	}
+	if id := a.Resolve("127.0.0.1", h); id == nil || id.User != "mallory" {
+		t.Fatalf("identity = %+v", id)
+	}
+	// A trusted proxy without a user header means anonymous.
+	if id := a.Resolve("127.0.0.1", headers(nil)); id != nil {
+		t.Fatalf("identity = %+v, want nil", id)
+	}
+}
+
+func TestNewRejectsBadCIDR(t *testing.T) {
+	if _, err := New("10.0.0.0"); err == nil {
+		t.Fatal("expected error for bare IP")
+	}
+}
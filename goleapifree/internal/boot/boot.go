@@ -0,0 +1,268 @@
+// Package boot orders subsystem startup and keeps optional subsystems
+// from taking the gateway down with them. Components declare their
+// dependencies and whether they are required; required failures abort
+// startup, optional ones (Redis-backed queues, notifiers, ...) retry
+// in the background while the gateway serves in partial degradation,
+// visible on /readyz.
+package boot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultRetryInterval paces background retries of failed optional
+// components.
+const defaultRetryInterval = 15 * time.Second
+
+// Component states.
+const (
+	StatusPending  = "pending"
+	StatusReady    = "ready"
+	StatusDegraded = "degraded" // optional component down, retrying
+)
+
+// Component is one subsystem with its initialization function.
+type Component struct {
+	Name string
+	// Required components abort Start when Init fails; optional ones
+	// degrade and retry.
+	Required bool
+	// DependsOn lists components that must be ready before Init runs.
+	DependsOn []string
+	Init      func(ctx context.Context) error
+}
+
+// ComponentStatus is one component's state for /readyz.
+type ComponentStatus struct {
+	Name     string    `json:"name"`
+	Status   string    `json:"status"`
+	Required bool      `json:"required"`
+	Error    string    `json:"error,omitempty"`
+	Since    time.Time `json:"since"`
+}
+
+type componentState struct {
+	component Component
+	status    string
+	err       error
+	since     time.Time
+}
+
+// Supervisor initializes registered components in dependency order.
+type Supervisor struct {
+	// RetryInterval paces optional-component retries; 0 applies the
+	// default.
+	RetryInterval time.Duration
+
+	now func() time.Time
+
+	mu    sync.Mutex
+	order []string
+	state map[string]*componentState
+}
+
+// NewSupervisor creates an empty supervisor.
+func NewSupervisor() *Supervisor {
+	return &Supervisor{
+		now:   time.Now,
+		state: make(map[string]*componentState),
+	}
+}
+
+// Register adds a component; duplicate names are a wiring bug.
+func (s *Supervisor) Register(c Component) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, dup := s.state[c.Name]; dup {
+		return fmt.Errorf("boot: component %q registered twice", c.Name)
+	}
+	s.order = append(s.order, c.Name)
+	s.state[c.Name] = &componentState{component: c, status: StatusPending, since: s.now()}
+	return nil
+}
+
+// Start initializes every component in dependency order. A required
+// component failing aborts with its error; optional failures mark the
+// component degraded and schedule retries on the given context.
+func (s *Supervisor) Start(ctx context.Context) error {
+	ordered, err := s.resolve()
+	if err != nil {
+		return err
+	}
+	for _, name := range ordered {
+		s.mu.Lock()
+		st := s.state[name]
+		blocked := s.dependencyDownLocked(st.component)
+		s.mu.Unlock()
+
+		if blocked != "" {
+			// A dependency is degraded; this component cannot come up
+			// yet. Required components only reach here behind optional
+			// dependencies, which is a wiring bug worth failing loudly.
+			if st.component.Required {
+				return fmt.Errorf("boot: required component %q depends on degraded %q", name, blocked)
+			}
+			s.markDegraded(name, fmt.Errorf("waiting for %s", blocked))
+			go s.retry(ctx, name)
+			continue
+		}
+		if err := st.component.Init(ctx); err != nil {
+			if st.component.Required {
+				return fmt.Errorf("boot: required component %q: %w", name, err)
+			}
+			s.markDegraded(name, err)
+			go s.retry(ctx, name)
+			continue
+		}
+		s.mark(name, StatusReady, nil)
+	}
+	return nil
+}
+
+// Ready reports whether every required component is up; optional
+// degradation does not block readiness.
+func (s *Supervisor) Ready() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range s.state {
+		if st.component.Required && st.status != StatusReady {
+			return false
+		}
+	}
+	return true
+}
+
+// Statuses returns every component's state in registration order.
+func (s *Supervisor) Statuses() []ComponentStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]ComponentStatus, 0, len(s.order))
+	for _, name := range s.order {
+		st := s.state[name]
+		cs := ComponentStatus{
+			Name:     name,
+			Status:   st.status,
+			Required: st.component.Required,
+			Since:    st.since,
+		}
+		if st.err != nil {
+			cs.Error = st.err.Error()
+		}
+		out = append(out, cs)
+	}
+	return out
+}
+
+// retry re-runs a degraded optional component until it comes up or the
+// context ends, waiting out degraded dependencies between attempts.
+func (s *Supervisor) retry(ctx context.Context, name string) {
+	interval := s.RetryInterval
+	if interval <= 0 {
+		interval = defaultRetryInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		s.mu.Lock()
+		st := s.state[name]
+		blocked := s.dependencyDownLocked(st.component)
+		s.mu.Unlock()
+		if blocked != "" {
+			continue
+		}
+		if err := st.component.Init(ctx); err != nil {
+			s.markDegraded(name, err)
+			continue
+		}
+		s.mark(name, StatusReady, nil)
+		return
+	}
+}
+
+func (s *Supervisor) mark(name, status string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.state[name]
+	st.status = status
+	st.err = err
+	st.since = s.now()
+}
+
+func (s *Supervisor) markDegraded(name string, err error) {
+	s.mark(name, StatusDegraded, err)
+}
+
+// dependencyDownLocked returns the name of the first dependency that
+// is not ready, or "". Callers hold s.mu.
+func (s *Supervisor) dependencyDownLocked(c Component) string {
+	for _, dep := range c.DependsOn {
+		if s.state[dep].status != StatusReady {
+			return dep
+		}
+	}
+	return ""
+}
+
+// resolve topologically sorts the components, detecting unknown and
+// cyclic dependencies. Ties break by registration order so startup is
+// deterministic.
+func (s *Supervisor) resolve() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	indegree := make(map[string]int, len(s.state))
+	dependents := make(map[string][]string)
+	for _, name := range s.order {
+		for _, dep := range s.state[name].component.DependsOn {
+			if _, known := s.state[dep]; !known {
+				return nil, fmt.Errorf("boot: component %q depends on unknown %q", name, dep)
+			}
+			indegree[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	queue := make([]string, 0, len(s.order))
+	for _, name := range s.order {
+		if indegree[name] == 0 {
+			queue = append(queue, name)
+		}
+	}
+	var ordered []string
+	for len(queue) > 0 {
+		sort.SliceStable(queue, func(i, j int) bool {
+			return indexOf(s.order, queue[i]) < indexOf(s.order, queue[j])
+		})
+		name := queue[0]
+		queue = queue[1:]
+		ordered = append(ordered, name)
+		for _, next := range dependents[name] {
+			indegree[next]--
+			if indegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	if len(ordered) != len(s.order) {
+		return nil, fmt.Errorf("boot: dependency cycle among components")
+	}
+	return ordered, nil
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return len(names)
+}
@@ -0,0 +1,120 @@
+package boot
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStartRespectsDependencyOrder(t *testing.T) {
+	s := NewSupervisor()
+	var order []string
+	init := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+	// Registered out of order on purpose.
+	s.Register(Component{Name: "scheduler", Required: true, DependsOn: []string{"router"}, Init: init("scheduler")})
+	s.Register(Component{Name: "router", Required: true, DependsOn: []string{"config"}, Init: init("router")})
+	s.Register(Component{Name: "config", Required: true, Init: init("config")})
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != 3 || order[0] != "config" || order[1] != "router" || order[2] != "scheduler" {
+		t.Fatalf("order = %v", order)
+	}
+	if !s.Ready() {
+		t.Fatal("not ready after clean start")
+	}
+}
+
+func TestRequiredFailureAborts(t *testing.T) {
+	s := NewSupervisor()
+	boom := errors.New("no database")
+	s.Register(Component{Name: "store", Required: true, Init: func(context.Context) error { return boom }})
+	if err := s.Start(context.Background()); !errors.Is(err, boom) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestOptionalFailureDegradesAndRetries(t *testing.T) {
+	s := NewSupervisor()
+	s.RetryInterval = 10 * time.Millisecond
+	var attempts atomic.Int32
+	s.Register(Component{Name: "router", Required: true, Init: func(context.Context) error { return nil }})
+	s.Register(Component{Name: "redis", Init: func(context.Context) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Ready() {
+		t.Fatal("optional failure blocked readiness")
+	}
+	statuses := s.Statuses()
+	if statuses[1].Status != StatusDegraded || statuses[1].Error == "" {
+		t.Fatalf("redis status = %+v", statuses[1])
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.Statuses()[1].Status != StatusReady {
+		if time.Now().After(deadline) {
+			t.Fatalf("retry never recovered: %+v", s.Statuses()[1])
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDependencyValidation(t *testing.T) {
+	s := NewSupervisor()
+	s.Register(Component{Name: "a", DependsOn: []string{"ghost"}, Init: func(context.Context) error { return nil }})
+	if err := s.Start(context.Background()); err == nil {
+		t.Fatal("unknown dependency accepted")
+	}
+
+	s = NewSupervisor()
+	s.Register(Component{Name: "a", DependsOn: []string{"b"}, Init: func(context.Context) error { return nil }})
+	s.Register(Component{Name: "b", DependsOn: []string{"a"}, Init: func(context.Context) error { return nil }})
+	if err := s.Start(context.Background()); err == nil {
+		t.Fatal("cycle accepted")
+	}
+
+	s = NewSupervisor()
+	s.Register(Component{Name: "a", Init: func(context.Context) error { return nil }})
+	if err := s.Register(Component{Name: "a", Init: func(context.Context) error { return nil }}); err == nil {
+		t.Fatal("duplicate name accepted")
+	}
+}
+
+func TestOptionalBehindDegradedDependencyWaits(t *testing.T) {
+	s := NewSupervisor()
+	s.RetryInterval = time.Hour // retries must not fire in this test
+	s.Register(Component{Name: "redis", Init: func(context.Context) error { return errors.New("down") }})
+	called := false
+	s.Register(Component{Name: "queue", DependsOn: []string{"redis"}, Init: func(context.Context) error {
+		called = true
+		return nil
+	}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if called {
+		t.Fatal("component initialized behind a degraded dependency")
+	}
+	if st := s.Statuses()[1]; st.Status != StatusDegraded {
+		t.Fatalf("queue status = %+v", st)
+	}
+}
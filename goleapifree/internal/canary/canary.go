@@ -0,0 +1,278 @@
+//go:build !edge
+
+// Package canary stages routing configuration changes: a proposed alias
+// change serves only a slice of traffic first, its error/latency/cost is
+// compared against the control slice with the experiments z-test, and
+// the change auto-promotes into the router or auto-reverts.
+package canary
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/experiments"
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/router"
+)
+
+// Rollout states.
+const (
+	StateCanary   = "canary"
+	StatePromoted = "promoted"
+	StateReverted = "reverted"
+)
+
+// Traffic slices.
+const (
+	SliceCanary  = "canary"
+	SliceControl = "control"
+)
+
+// Errors returned by the manager.
+var (
+	ErrNotFound = errors.New("canary: rollout not found")
+	ErrFinished = errors.New("canary: rollout already decided")
+)
+
+// Change is a proposed alias update and the canary parameters it rolls
+// out under.
+type Change struct {
+	ID      string               `json:"id"`
+	Alias   string               `json:"alias"`
+	Targets []models.AliasTarget `json:"targets"`
+	// Percent of traffic assigned to the canary slice, in (0, 100).
+	Percent float64 `json:"percent"`
+	// MinSamples per slice before a promote decision; defaults to 100.
+	MinSamples int64 `json:"min_samples,omitempty"`
+	// Tolerance is the allowed relative latency/cost overhead of the
+	// canary over the control; defaults to 0.2 (20%).
+	Tolerance float64 `json:"tolerance,omitempty"`
+}
+
+type sliceStats struct {
+	Samples    int64   `json:"samples"`
+	Errors     int64   `json:"errors"`
+	LatencySum float64 `json:"-"`
+	CostSum    float64 `json:"-"`
+}
+
+func (s sliceStats) avgLatency() float64 {
+	if s.Samples == 0 {
+		return 0
+	}
+	return s.LatencySum / float64(s.Samples)
+}
+
+func (s sliceStats) avgCost() float64 {
+	if s.Samples == 0 {
+		return 0
+	}
+	return s.CostSum / float64(s.Samples)
+}
+
+// Rollout is one staged change with its per-slice observations.
+type Rollout struct {
+	Change
+	State     string     `json:"state"`
+	Reason    string     `json:"reason,omitempty"`
+	Control   sliceStats `json:"control"`
+	Canary    sliceStats `json:"canary"`
+	StartedAt time.Time  `json:"started_at"`
+	DecidedAt time.Time  `json:"decided_at,omitempty"`
+}
+
+// Manager owns active rollouts and applies decisions to the router.
+type Manager struct {
+	Router *router.Router
+	// Gate, when set, can veto a promotion (e.g. the evals regression
+	// gate); a vetoed rollout reverts with the gate's reason.
+	Gate func(alias string) error
+
+	mu       sync.Mutex
+	rollouts map[string]*Rollout
+}
+
+// NewManager creates a manager applying decisions to r.
+func NewManager(r *router.Router) *Manager {
+	return &Manager{Router: r, rollouts: make(map[string]*Rollout)}
+}
+
+// Propose stages a change. The alias keeps serving its current targets
+// to the control slice; only the canary slice sees the new ones.
+func (m *Manager) Propose(ch Change) (*Rollout, error) {
+	if ch.ID == "" || ch.Alias == "" || len(ch.Targets) == 0 {
+		return nil, errors.New("canary: change needs id, alias and targets")
+	}
+	if ch.Percent <= 0 || ch.Percent >= 100 {
+		return nil, errors.New("canary: percent must be in (0, 100)")
+	}
+	if ch.MinSamples <= 0 {
+		ch.MinSamples = 100
+	}
+	if ch.Tolerance <= 0 {
+		ch.Tolerance = 0.2
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.rollouts[ch.ID]; exists {
+		return nil, fmt.Errorf("canary: rollout %q already exists", ch.ID)
+	}
+	ro := &Rollout{Change: ch, State: StateCanary, StartedAt: time.Now()}
+	m.rollouts[ch.ID] = ro
+	copied := *ro
+	return &copied, nil
+}
+
+// Assign buckets a request key into a slice, deterministically, so the
+// same caller stays in the same slice for the rollout's lifetime.
+func (m *Manager) Assign(id, key string) (string, error) {
+	m.mu.Lock()
+	ro, ok := m.rollouts[id]
+	m.mu.Unlock()
+	if !ok {
+		return "", ErrNotFound
+	}
+	if ro.State != StateCanary {
+		return SliceControl, nil
+	}
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	if float64(h.Sum32()%10000) < ro.Percent*100 {
+		return SliceCanary, nil
+	}
+	return SliceControl, nil
+}
+
+// TargetsFor resolves the targets a request should route to: the staged
+// ones for the canary slice of an active rollout on this alias, nil
+// otherwise (meaning: use the router's current config).
+func (m *Manager) TargetsFor(alias, key string) []models.AliasTarget {
+	m.mu.Lock()
+	var active *Rollout
+	for _, ro := range m.rollouts {
+		if ro.Alias == alias && ro.State == StateCanary {
+			active = ro
+			break
+		}
+	}
+	m.mu.Unlock()
+	if active == nil {
+		return nil
+	}
+	if slice, _ := m.Assign(active.ID, key); slice == SliceCanary {
+		out := make([]models.AliasTarget, len(active.Targets))
+		copy(out, active.Targets)
+		return out
+	}
+	return nil
+}
+
+// Observe records one request outcome for a slice and evaluates the
+// rollout once both slices have enough samples. It returns the rollout's
+// state after the observation.
+func (m *Manager) Observe(id, slice string, errored bool, latencyMs, cost float64) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ro, ok := m.rollouts[id]
+	if !ok {
+		return "", ErrNotFound
+	}
+	if ro.State != StateCanary {
+		return ro.State, ErrFinished
+	}
+	s := &ro.Control
+	if slice == SliceCanary {
+		s = &ro.Canary
+	}
+	s.Samples++
+	if errored {
+		s.Errors++
+	}
+	s.LatencySum += latencyMs
+	s.CostSum += cost
+
+	m.evaluateLocked(ro)
+	return ro.State, nil
+}
+
+// Status returns a rollout by ID.
+func (m *Manager) Status(id string) (*Rollout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ro, ok := m.rollouts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *ro
+	return &copied, nil
+}
+
+// Revert manually abandons a rollout.
+func (m *Manager) Revert(id, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ro, ok := m.rollouts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if ro.State != StateCanary {
+		return ErrFinished
+	}
+	m.decideLocked(ro, StateReverted, reason)
+	return nil
+}
+
+// evaluateLocked applies the decision rules. Caller holds m.mu.
+//
+// Revert fires as soon as the canary is significantly worse on errors
+// (two-proportion z-test at 5%) or exceeds the latency/cost tolerance
+// with minimum data. Promote waits for MinSamples on both slices with
+// no such finding, then runs the optional gate.
+func (m *Manager) evaluateLocked(ro *Rollout) {
+	exp := &experiments.Experiment{Variants: []experiments.Variant{
+		{Name: SliceControl, Samples: ro.Control.Samples, Conversions: ro.Control.Errors},
+		{Name: SliceCanary, Samples: ro.Canary.Samples, Conversions: ro.Canary.Errors},
+	}}
+	canaryWorse := ro.Canary.Samples > 0 && ro.Control.Samples > 0 &&
+		float64(ro.Canary.Errors)/float64(ro.Canary.Samples) > float64(ro.Control.Errors)/float64(ro.Control.Samples)
+	if canaryWorse && exp.PValue() < 0.05 {
+		m.decideLocked(ro, StateReverted, "canary error rate significantly above control")
+		return
+	}
+
+	enough := ro.Control.Samples >= ro.MinSamples && ro.Canary.Samples >= ro.MinSamples
+	if !enough {
+		return
+	}
+	if ro.Control.avgLatency() > 0 && ro.Canary.avgLatency() > ro.Control.avgLatency()*(1+ro.Tolerance) {
+		m.decideLocked(ro, StateReverted, "canary latency above tolerance")
+		return
+	}
+	if ro.Control.avgCost() > 0 && ro.Canary.avgCost() > ro.Control.avgCost()*(1+ro.Tolerance) {
+		m.decideLocked(ro, StateReverted, "canary cost above tolerance")
+		return
+	}
+	if m.Gate != nil {
+		if err := m.Gate(ro.Alias); err != nil {
+			m.decideLocked(ro, StateReverted, err.Error())
+			return
+		}
+	}
+	m.decideLocked(ro, StatePromoted, "canary matched or beat control")
+}
+
+// decideLocked finalizes a rollout; promotion rewrites the alias in the
+// router so the whole fleet picks up the new targets. Caller holds m.mu.
+func (m *Manager) decideLocked(ro *Rollout, state, reason string) {
+	ro.State = state
+	ro.Reason = reason
+	ro.DecidedAt = time.Now()
+	if state == StatePromoted && m.Router != nil {
+		m.Router.SetAlias(&models.ModelAlias{Alias: ro.Alias, Targets: ro.Targets})
+	}
+}
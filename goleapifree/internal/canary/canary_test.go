@@ -0,0 +1,149 @@
+//go:build !edge
+
+package canary
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/router"
+)
+
+func newChange(percent float64) Change {
+	return Change{
+		ID: "ch1", Alias: "default", Percent: percent, MinSamples: 50,
+		Targets: []models.AliasTarget{{ProviderID: "p2", Model: "new-model", Weight: 1}},
+	}
+}
+
+func TestHealthyCanaryPromotes(t *testing.T) {
+	r := router.New()
+	r.AddProvider(&models.Provider{ID: "p2", Enabled: true})
+	r.AddAccount(&models.Account{ID: "a1", ProviderID: "p2", Enabled: true})
+	m := NewManager(r)
+	if _, err := m.Propose(newChange(10)); err != nil {
+		t.Fatal(err)
+	}
+
+	state := ""
+	for i := 0; i < 50; i++ {
+		if state, _ = m.Observe("ch1", SliceControl, false, 100, 0.01); state != StateCanary {
+			break
+		}
+		state, _ = m.Observe("ch1", SliceCanary, false, 100, 0.01)
+	}
+	if state != StatePromoted {
+		t.Fatalf("state = %s, want promoted", state)
+	}
+	// Promotion rewrote the alias in the router.
+	cands := r.Candidates("default")
+	if len(cands) != 1 || cands[0].Model != "new-model" {
+		t.Fatalf("candidates = %+v", cands)
+	}
+}
+
+func TestFailingCanaryReverts(t *testing.T) {
+	r := router.New()
+	m := NewManager(r)
+	if _, err := m.Propose(newChange(10)); err != nil {
+		t.Fatal(err)
+	}
+
+	state := ""
+	for i := 0; i < 200 && state != StateReverted; i++ {
+		m.Observe("ch1", SliceControl, false, 100, 0.01)
+		state, _ = m.Observe("ch1", SliceCanary, true, 100, 0.01)
+	}
+	if state != StateReverted {
+		t.Fatalf("state = %s, want reverted", state)
+	}
+	ro, _ := m.Status("ch1")
+	if ro.Reason == "" {
+		t.Fatal("revert should record a reason")
+	}
+	// A reverted rollout never touched the router.
+	if cands := r.Candidates("default"); len(cands) != 0 {
+		t.Fatalf("candidates = %+v", cands)
+	}
+	if _, err := m.Observe("ch1", SliceControl, false, 1, 0); !errors.Is(err, ErrFinished) {
+		t.Fatalf("err = %v, want ErrFinished", err)
+	}
+}
+
+func TestSlowCanaryReverts(t *testing.T) {
+	m := NewManager(router.New())
+	if _, err := m.Propose(newChange(10)); err != nil {
+		t.Fatal(err)
+	}
+	state := ""
+	for i := 0; i < 50; i++ {
+		m.Observe("ch1", SliceControl, false, 100, 0.01)
+		state, _ = m.Observe("ch1", SliceCanary, false, 200, 0.01)
+	}
+	if state != StateReverted {
+		t.Fatalf("state = %s, want reverted", state)
+	}
+}
+
+func TestGateVetoesPromotion(t *testing.T) {
+	m := NewManager(router.New())
+	m.Gate = func(alias string) error { return errors.New("eval suite regressed") }
+	if _, err := m.Propose(newChange(10)); err != nil {
+		t.Fatal(err)
+	}
+	state := ""
+	for i := 0; i < 50; i++ {
+		m.Observe("ch1", SliceControl, false, 100, 0.01)
+		state, _ = m.Observe("ch1", SliceCanary, false, 100, 0.01)
+	}
+	if state != StateReverted {
+		t.Fatalf("state = %s, want reverted by gate", state)
+	}
+	ro, _ := m.Status("ch1")
+	if ro.Reason != "eval suite regressed" {
+		t.Fatalf("reason = %q", ro.Reason)
+	}
+}
+
+func TestAssignmentIsDeterministicAndSplit(t *testing.T) {
+	m := NewManager(router.New())
+	if _, err := m.Propose(newChange(20)); err != nil {
+		t.Fatal(err)
+	}
+	canary := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("tenant-%d", i)
+		s1, err := m.Assign("ch1", key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s2, _ := m.Assign("ch1", key)
+		if s1 != s2 {
+			t.Fatalf("assignment for %q flapped: %s vs %s", key, s1, s2)
+		}
+		if s1 == SliceCanary {
+			canary++
+		}
+	}
+	if canary < 150 || canary > 250 {
+		t.Fatalf("canary share = %d/1000, want ~200", canary)
+	}
+	// TargetsFor hands the staged targets only to the canary slice.
+	sawCanary, sawControl := false, false
+	for i := 0; i < 100; i++ {
+		targets := m.TargetsFor("default", fmt.Sprintf("tenant-%d", i))
+		if targets != nil {
+			sawCanary = true
+			if targets[0].Model != "new-model" {
+				t.Fatalf("targets = %+v", targets)
+			}
+		} else {
+			sawControl = true
+		}
+	}
+	if !sawCanary || !sawControl {
+		t.Fatalf("expected both slices, canary=%v control=%v", sawCanary, sawControl)
+	}
+}
@@ -0,0 +1,197 @@
+// Package capabilities maintains a machine-readable schema of what
+// each provider model actually supports: accepted parameters and their
+// ranges, tool and image limits. Schemas come from probing, not
+// provider docs — free tiers routinely diverge from the documentation.
+// The normalization layer validates requests against them and client
+// SDKs fetch them from /v1/models/:id/capabilities.
+package capabilities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrUnknownModel is returned for models never probed.
+var ErrUnknownModel = errors.New("capabilities: model not probed")
+
+// maxCountProbe bounds the doubling search for tool/image limits.
+const maxCountProbe = 128
+
+// ParamSpec describes one accepted request parameter.
+type ParamSpec struct {
+	Type string   `json:"type"` // "number", "integer", "boolean", "array"
+	Min  *float64 `json:"min,omitempty"`
+	Max  *float64 `json:"max,omitempty"`
+}
+
+// Schema is one model's probed capability surface.
+type Schema struct {
+	Model    string `json:"model"`
+	Provider string `json:"provider"`
+	// Params maps accepted parameter names to their specs; parameters
+	// the provider rejected are absent.
+	Params    map[string]ParamSpec `json:"params"`
+	MaxTools  int                  `json:"max_tools"`
+	MaxImages int                  `json:"max_images"`
+	ProbedAt  time.Time            `json:"probed_at"`
+}
+
+// Violation is one request parameter the target model cannot honor.
+type Violation struct {
+	Param  string `json:"param"`
+	Reason string `json:"reason"`
+}
+
+// Validate checks request parameters against the schema. It returns
+// one violation per offending parameter; an empty slice means the
+// request is within the model's surface.
+func (s Schema) Validate(params map[string]float64) []Violation {
+	var out []Violation
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		spec, ok := s.Params[name]
+		if !ok {
+			out = append(out, Violation{Param: name, Reason: "not supported by this model"})
+			continue
+		}
+		value := params[name]
+		if spec.Min != nil && value < *spec.Min {
+			out = append(out, Violation{Param: name, Reason: fmt.Sprintf("below minimum %g", *spec.Min)})
+		}
+		if spec.Max != nil && value > *spec.Max {
+			out = append(out, Violation{Param: name, Reason: fmt.Sprintf("above maximum %g", *spec.Max)})
+		}
+	}
+	return out
+}
+
+// paramProbe is one candidate parameter with the value sent to test
+// acceptance and the spec recorded when the provider takes it.
+type paramProbe struct {
+	name  string
+	value any
+	spec  ParamSpec
+}
+
+func f(v float64) *float64 { return &v }
+
+// paramProbes is the candidate surface, the superset of what the
+// OpenAI-compatible dialects accept.
+var paramProbes = []paramProbe{
+	{"temperature", 1.5, ParamSpec{Type: "number", Min: f(0), Max: f(2)}},
+	{"top_p", 0.5, ParamSpec{Type: "number", Min: f(0), Max: f(1)}},
+	{"max_tokens", 16, ParamSpec{Type: "integer", Min: f(1)}},
+	{"frequency_penalty", 0.5, ParamSpec{Type: "number", Min: f(-2), Max: f(2)}},
+	{"presence_penalty", 0.5, ParamSpec{Type: "number", Min: f(-2), Max: f(2)}},
+	{"seed", 7, ParamSpec{Type: "integer"}},
+	{"stop", []string{"\n"}, ParamSpec{Type: "array"}},
+	{"logprobs", true, ParamSpec{Type: "boolean"}},
+}
+
+// Prober discovers a model's schema by sending minimal requests.
+type Prober struct {
+	// Try sends a cheap request carrying the given extra parameters
+	// and returns an error when the provider rejects them. The wiring
+	// builds it from the provider's adapter.
+	Try func(ctx context.Context, providerID, model string, params map[string]any) error
+
+	now func() time.Time
+}
+
+// NewProber creates a prober around a Try function.
+func NewProber(try func(ctx context.Context, providerID, model string, params map[string]any) error) *Prober {
+	return &Prober{Try: try, now: time.Now}
+}
+
+// Probe determines the schema for one (provider, model) pair. Each
+// parameter is tested in isolation so one rejection cannot mask
+// another.
+func (p *Prober) Probe(ctx context.Context, providerID, model string) (Schema, error) {
+	if err := p.Try(ctx, providerID, model, nil); err != nil {
+		return Schema{}, fmt.Errorf("capabilities: baseline probe failed: %w", err)
+	}
+	schema := Schema{
+		Model:    model,
+		Provider: providerID,
+		Params:   make(map[string]ParamSpec),
+		ProbedAt: p.now(),
+	}
+	for _, probe := range paramProbes {
+		if ctx.Err() != nil {
+			return Schema{}, ctx.Err()
+		}
+		if p.Try(ctx, providerID, model, map[string]any{probe.name: probe.value}) == nil {
+			schema.Params[probe.name] = probe.spec
+		}
+	}
+	schema.MaxTools = p.probeCount(ctx, providerID, model, "tools")
+	schema.MaxImages = p.probeCount(ctx, providerID, model, "images")
+	return schema, nil
+}
+
+// probeCount finds the highest accepted count for a repeatable feature
+// by doubling until rejection, then keeping the last accepted value.
+// The count travels to Try as a synthetic "<feature>_count" parameter
+// the wiring expands into that many stub tools or images.
+func (p *Prober) probeCount(ctx context.Context, providerID, model, feature string) int {
+	accepted := 0
+	for n := 1; n <= maxCountProbe; n *= 2 {
+		if ctx.Err() != nil {
+			return accepted
+		}
+		if p.Try(ctx, providerID, model, map[string]any{feature + "_count": n}) != nil {
+			break
+		}
+		accepted = n
+	}
+	return accepted
+}
+
+// Registry stores probed schemas for lookup and validation.
+type Registry struct {
+	mu      sync.RWMutex
+	schemas map[string]Schema // model -> schema
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{schemas: make(map[string]Schema)}
+}
+
+// Set stores or replaces a schema.
+func (r *Registry) Set(s Schema) {
+	r.mu.Lock()
+	r.schemas[s.Model] = s
+	r.mu.Unlock()
+}
+
+// Get returns the schema for a model.
+func (r *Registry) Get(model string) (Schema, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.schemas[model]
+	if !ok {
+		return Schema{}, ErrUnknownModel
+	}
+	return s, nil
+}
+
+// Models lists probed model names, sorted.
+func (r *Registry) Models() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]string, 0, len(r.schemas))
+	for model := range r.schemas {
+		out = append(out, model)
+	}
+	sort.Strings(out)
+	return out
+}
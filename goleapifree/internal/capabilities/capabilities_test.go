@@ -0,0 +1,95 @@
+package capabilities
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeTry accepts temperature and max_tokens, rejects everything else,
+// and allows up to 4 tools and no images.
+func fakeTry(_ context.Context, _, _ string, params map[string]any) error {
+	if len(params) == 0 {
+		return nil
+	}
+	for name, value := range params {
+		switch name {
+		case "temperature", "max_tokens":
+			return nil
+		case "tools_count":
+			if value.(int) <= 4 {
+				return nil
+			}
+		}
+	}
+	return errors.New("unsupported parameter")
+}
+
+func TestProbe(t *testing.T) {
+	p := NewProber(fakeTry)
+	schema, err := p.Probe(context.Background(), "openai", "fast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := schema.Params["temperature"]; !ok {
+		t.Fatalf("temperature missing: %+v", schema.Params)
+	}
+	if _, ok := schema.Params["logprobs"]; ok {
+		t.Fatal("rejected parameter recorded as supported")
+	}
+	if schema.MaxTools != 4 {
+		t.Fatalf("MaxTools = %d", schema.MaxTools)
+	}
+	if schema.MaxImages != 0 {
+		t.Fatalf("MaxImages = %d", schema.MaxImages)
+	}
+	if schema.ProbedAt.IsZero() {
+		t.Fatal("ProbedAt not set")
+	}
+}
+
+func TestProbeBaselineFailure(t *testing.T) {
+	p := NewProber(func(context.Context, string, string, map[string]any) error {
+		return errors.New("model offline")
+	})
+	if _, err := p.Probe(context.Background(), "openai", "fast"); err == nil {
+		t.Fatal("baseline failure not surfaced")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema := Schema{Params: map[string]ParamSpec{
+		"temperature": {Type: "number", Min: f(0), Max: f(2)},
+		"max_tokens":  {Type: "integer", Min: f(1)},
+	}}
+	violations := schema.Validate(map[string]float64{
+		"temperature": 3,
+		"max_tokens":  100,
+		"top_p":       0.5,
+	})
+	if len(violations) != 2 {
+		t.Fatalf("violations = %+v", violations)
+	}
+	if violations[0].Param != "temperature" || violations[1].Param != "top_p" {
+		t.Fatalf("violations = %+v", violations)
+	}
+	if v := schema.Validate(map[string]float64{"temperature": 0.7}); len(v) != 0 {
+		t.Fatalf("clean request flagged: %+v", v)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	if _, err := r.Get("ghost"); !errors.Is(err, ErrUnknownModel) {
+		t.Fatalf("err = %v", err)
+	}
+	r.Set(Schema{Model: "fast", Provider: "openai"})
+	r.Set(Schema{Model: "smart", Provider: "openai"})
+	if s, err := r.Get("fast"); err != nil || s.Provider != "openai" {
+		t.Fatalf("Get = %+v, %v", s, err)
+	}
+	models := r.Models()
+	if len(models) != 2 || models[0] != "fast" {
+		t.Fatalf("Models = %v", models)
+	}
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// askUsage is the accounting a completion response carries; cost is the
+// gateway's addition to the OpenAI shape.
+type askUsage struct {
+	TotalTokens int     `json:"total_tokens"`
+	Cost        float64 `json:"cost"`
+}
+
+func newAskCmd() *cobra.Command {
+	var (
+		gatewayAddr string
+		model       string
+		system      string
+		maxTokens   int
+		temperature float64
+		noStream    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "ask <prompt>",
+		Short: "Send a prompt through the gateway and print the completion",
+		Long: "Send one prompt through a running gateway's OpenAI-compatible endpoint, " +
+			"streaming the completion to stdout with token and cost accounting at the " +
+			"end — handy for scripting and smoke tests.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAsk(cmd, gatewayAddr, askRequest{
+				Model:       model,
+				System:      system,
+				Prompt:      strings.Join(args, " "),
+				MaxTokens:   maxTokens,
+				Temperature: temperature,
+				Stream:      !noStream,
+			})
+		},
+	}
+	cmd.Flags().StringVar(&gatewayAddr, "gateway", "http://localhost:8080", "gateway base URL")
+	cmd.Flags().StringVarP(&model, "model", "m", "default", "model or alias to route through")
+	cmd.Flags().StringVar(&system, "system", "", "optional system prompt")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "completion token cap (0 = provider default)")
+	cmd.Flags().Float64Var(&temperature, "temperature", 0, "sampling temperature")
+	cmd.Flags().BoolVar(&noStream, "no-stream", false, "wait for the full completion instead of streaming")
+	return cmd
+}
+
+// askRequest collects the flags into one completion request.
+type askRequest struct {
+	Model       string
+	System      string
+	Prompt      string
+	MaxTokens   int
+	Temperature float64
+	Stream      bool
+	// Quiet suppresses the stderr accounting line; pipe mode sets it so
+	// cron jobs capturing stderr stay silent on success.
+	Quiet bool
+}
+
+// runAsk performs the completion round-trip, writing content to stdout
+// and the accounting summary to stderr so pipelines stay clean.
+func runAsk(cmd *cobra.Command, gatewayAddr string, req askRequest) error {
+	messages := []map[string]string{}
+	if req.System != "" {
+		messages = append(messages, map[string]string{"role": "system", "content": req.System})
+	}
+	messages = append(messages, map[string]string{"role": "user", "content": req.Prompt})
+	body, err := json.Marshal(map[string]any{
+		"model":       req.Model,
+		"messages":    messages,
+		"max_tokens":  req.MaxTokens,
+		"temperature": req.Temperature,
+		"stream":      req.Stream,
+	})
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Post(gatewayAddr+"/v1/chat/completions", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("connecting to gateway at %s: %w", gatewayAddr, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("gateway: %s: %s", resp.Status, msg)
+	}
+
+	var usage askUsage
+	if req.Stream {
+		usage, err = streamAsk(cmd.OutOrStdout(), resp.Body)
+	} else {
+		usage, err = printAsk(cmd.OutOrStdout(), resp.Body)
+	}
+	if err != nil {
+		return err
+	}
+	if !req.Quiet {
+		fmt.Fprintf(cmd.ErrOrStderr(), "\n%d tokens, $%.4f, %.1fs\n",
+			usage.TotalTokens, usage.Cost, time.Since(start).Seconds())
+	}
+	return nil
+}
+
+// streamAsk relays SSE deltas to out as they arrive, returning the usage
+// from the final chunk that carries it.
+func streamAsk(out io.Writer, body io.Reader) (askUsage, error) {
+	var usage askUsage
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Usage *askUsage `json:"usage"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return usage, fmt.Errorf("parsing stream chunk: %w", err)
+		}
+		if len(chunk.Choices) > 0 {
+			fmt.Fprint(out, chunk.Choices[0].Delta.Content)
+		}
+		if chunk.Usage != nil {
+			usage = *chunk.Usage
+		}
+	}
+	fmt.Fprintln(out)
+	return usage, scanner.Err()
+}
+
+// printAsk handles the non-streaming shape.
+func printAsk(out io.Writer, body io.Reader) (askUsage, error) {
+	var parsed struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage askUsage `json:"usage"`
+	}
+	if err := json.NewDecoder(body).Decode(&parsed); err != nil {
+		return askUsage{}, err
+	}
+	if len(parsed.Choices) == 0 {
+		return askUsage{}, fmt.Errorf("gateway returned no choices")
+	}
+	fmt.Fprintln(out, parsed.Choices[0].Message.Content)
+	return parsed.Usage, nil
+}
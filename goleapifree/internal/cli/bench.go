@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/providers"
+	"github.com/biodoia/goleapifree/internal/proxy"
+	"github.com/biodoia/goleapifree/internal/ratelimit"
+	"github.com/biodoia/goleapifree/internal/router"
+)
+
+// benchResult is one benchmark's outcome as stored in the baseline file.
+type benchResult struct {
+	NsPerOp     float64 `json:"ns_per_op"`
+	AllocsPerOp int64   `json:"allocs_per_op"`
+}
+
+// benchBaseline is the on-disk baseline: results keyed by benchmark
+// name, tagged with the version that produced them.
+type benchBaseline struct {
+	Version string                 `json:"version"`
+	SavedAt time.Time              `json:"saved_at"`
+	Results map[string]benchResult `json:"results"`
+}
+
+// microBenchmarks exercises the gateway hot paths the request latency
+// budget depends on. They run in-process so results are comparable
+// across versions without a load generator.
+var microBenchmarks = map[string]func(b *testing.B){
+	"router.route":              benchRoute,
+	"ratelimit.hierarchy":       benchHierarchy,
+	"proxy.rewrite_model":       benchRewrite,
+	"providers.openrouter_body": benchOpenRouterBody,
+}
+
+func newDebugBenchCmd() *cobra.Command {
+	var (
+		baselinePath string
+		save         bool
+		live         bool
+		maxRegress   float64
+	)
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Run hot-path benchmarks and compare against a baseline",
+		Long: "Run in-process benchmarks of routing selection, limiters, body rewriting " +
+			"and adapter request building, optionally probing a running gateway's admin " +
+			"round-trip latency. Results are compared against a stored baseline so " +
+			"performance regressions show up before a release.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results := make(map[string]benchResult, len(microBenchmarks))
+			names := make([]string, 0, len(microBenchmarks))
+			for name := range microBenchmarks {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				r := testing.Benchmark(microBenchmarks[name])
+				results[name] = benchResult{
+					NsPerOp:     float64(r.NsPerOp()),
+					AllocsPerOp: r.AllocsPerOp(),
+				}
+			}
+			if live {
+				r := testing.Benchmark(benchLiveAdmin)
+				results["live.admin_roundtrip"] = benchResult{NsPerOp: float64(r.NsPerOp())}
+			}
+
+			baseline, err := loadBenchBaseline(baselinePath)
+			if err != nil {
+				return err
+			}
+			regressed := printBenchResults(cmd, results, baseline, maxRegress)
+
+			if save {
+				out := benchBaseline{Version: buildVersion(), SavedAt: time.Now(), Results: results}
+				raw, err := json.MarshalIndent(out, "", "  ")
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(baselinePath, raw, 0o644); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "baseline saved to %s\n", baselinePath)
+				return nil
+			}
+			if len(regressed) > 0 {
+				return fmt.Errorf("benchmarks regressed beyond %.0f%%: %v", maxRegress, regressed)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&baselinePath, "baseline", ".goleapai-bench.json", "baseline file to compare against")
+	cmd.Flags().BoolVar(&save, "save", false, "store this run as the new baseline")
+	cmd.Flags().BoolVar(&live, "live", false, "also measure admin round-trip latency against --server")
+	cmd.Flags().Float64Var(&maxRegress, "max-regress", 20, "fail when any benchmark is this many percent slower than the baseline")
+	return cmd
+}
+
+func loadBenchBaseline(path string) (*benchBaseline, error) {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var b benchBaseline
+	if err := json.Unmarshal(raw, &b); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+	return &b, nil
+}
+
+// printBenchResults writes one line per benchmark with the baseline
+// delta, returning the names that regressed beyond maxRegress percent.
+func printBenchResults(cmd *cobra.Command, results map[string]benchResult, baseline *benchBaseline, maxRegress float64) []string {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var regressed []string
+	for _, name := range names {
+		r := results[name]
+		line := fmt.Sprintf("%-28s %12.0f ns/op %6d allocs/op", name, r.NsPerOp, r.AllocsPerOp)
+		if baseline != nil {
+			if base, ok := baseline.Results[name]; ok && base.NsPerOp > 0 {
+				delta := (r.NsPerOp - base.NsPerOp) / base.NsPerOp * 100
+				line += fmt.Sprintf("  %+6.1f%% vs %s", delta, baseline.Version)
+				if delta > maxRegress {
+					regressed = append(regressed, name)
+				}
+			}
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), line)
+	}
+	return regressed
+}
+
+func buildVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "devel"
+}
+
+func benchRoute(b *testing.B) {
+	r := router.New()
+	targets := make([]models.AliasTarget, 0, 5)
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("p%d", i)
+		r.AddProvider(&models.Provider{ID: id, Enabled: true})
+		r.AddAccount(&models.Account{ID: id + "-a", ProviderID: id, Enabled: true})
+		targets = append(targets, models.AliasTarget{ProviderID: id, Model: "m", Weight: 1})
+	}
+	r.SetAlias(&models.ModelAlias{Alias: "bench", Targets: targets})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c, err := r.Route("bench")
+		if err != nil {
+			b.Fatal(err)
+		}
+		r.Release(c)
+	}
+}
+
+func benchHierarchy(b *testing.B) {
+	h := ratelimit.NewHierarchy(
+		ratelimit.Level{Name: "global", Limiter: ratelimit.NewSlidingWindow(1<<30, time.Minute), KeyFunc: func(string) string { return "global" }},
+		ratelimit.Level{Name: "user", Limiter: ratelimit.NewSlidingWindow(1<<30, time.Minute)},
+	)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !h.Allow("user-1") {
+			b.Fatal("limiter rejected under no contention")
+		}
+	}
+}
+
+func benchRewrite(b *testing.B) {
+	body := []byte(`{"model":"gpt-4","messages":[{"role":"user","content":"benchmark request"}],"temperature":0.2}`)
+	buf := make([]byte, 0, len(body)+32)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := proxy.AppendRewriteModel(buf[:0], body, "llama-3-70b"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchOpenRouterBody(b *testing.B) {
+	o := &providers.OpenRouter{APIKey: "k", Preferences: json.RawMessage(`{"order":["groq"]}`)}
+	req := providers.ChatRequest{
+		Model:       "meta-llama/llama-3-8b",
+		Messages:    []providers.Message{{Role: "user", Content: "benchmark request"}},
+		MaxTokens:   256,
+		Temperature: 0.2,
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := o.RequestBody(req, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchLiveAdmin measures a full admin API round-trip against the
+// gateway at --server, capturing what a CLI or dashboard user sees.
+func benchLiveAdmin(b *testing.B) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	for i := 0; i < b.N; i++ {
+		resp, err := client.Get(serverAddr + "/admin/providers")
+		if err != nil {
+			b.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/biodoia/goleapifree/internal/guardrails"
+)
+
+func newDebugCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Debugging and rule development tools",
+	}
+	cmd.AddCommand(newDebugGuardrailsCmd())
+	cmd.AddCommand(newDebugBenchCmd())
+	cmd.AddCommand(newDebugProfileCmd())
+	cmd.AddCommand(newDebugVarsCmd())
+	return cmd
+}
+
+func newDebugGuardrailsCmd() *cobra.Command {
+	var (
+		rulesFile string
+		alias     string
+		tenant    string
+		phase     string
+	)
+	cmd := &cobra.Command{
+		Use:   "guardrails [text]",
+		Short: "Evaluate guardrail rules against sample text",
+		Long:  "Evaluate a YAML rule file against sample text (argument or stdin) and print every violation, so rules can be developed without sending real traffic.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(rulesFile)
+			if err != nil {
+				return err
+			}
+			rs, err := guardrails.Parse(data)
+			if err != nil {
+				return err
+			}
+			var text string
+			if len(args) == 1 {
+				text = args[0]
+			} else {
+				stdin, err := readAllStdin(cmd)
+				if err != nil {
+					return err
+				}
+				text = stdin
+			}
+			violations := rs.Evaluate(guardrails.Phase(phase), alias, tenant, text)
+			if len(violations) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "no violations")
+				return nil
+			}
+			for _, v := range violations {
+				action := "log"
+				if v.Block {
+					action = "BLOCK"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s [%s/%s]: %s\n", v.Rule, v.Phase, action, v.Message)
+			}
+			if guardrails.ShouldBlock(violations) {
+				return fmt.Errorf("blocking violations found")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&rulesFile, "rules", "guardrails.yaml", "path to the YAML rule file")
+	cmd.Flags().StringVar(&alias, "alias", "", "model alias to evaluate as")
+	cmd.Flags().StringVar(&tenant, "tenant", "", "tenant to evaluate as")
+	cmd.Flags().StringVar(&phase, "phase", "input", "rule phase: input|output")
+	return cmd
+}
+
+func readAllStdin(cmd *cobra.Command) (string, error) {
+	data, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return "", fmt.Errorf("reading stdin: %w", err)
+	}
+	return string(data), nil
+}
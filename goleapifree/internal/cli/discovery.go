@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/biodoia/goleapifree/internal/discovery"
+)
+
+func newDiscoveryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "discovery",
+		Short: "List discovered provider candidates",
+	}
+	p := AddOutputFlags(cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var candidates []discovery.Candidate
+		if err := adminGet("/admin/discovery", &candidates); err != nil {
+			return err
+		}
+		ids := make([]string, len(candidates))
+		for i, c := range candidates {
+			ids[i] = c.Name
+		}
+		return p.Print(cmd, candidates, ids, func(w io.Writer) {
+			fmt.Fprintln(w, "NAME\tBASE URL\tSOURCE\tHEALTHY\tLAST SEEN")
+			for _, c := range candidates {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n",
+					c.Name, c.BaseURL, c.Source, c.Healthy, c.LastSeen.Format("2006-01-02 15:04"))
+			}
+		})
+	}
+	return cmd
+}
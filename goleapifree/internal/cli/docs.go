@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+func newCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:       "completion [bash|zsh|fish]",
+		Short:     "Generate shell completion scripts",
+		Long:      "Generate a completion script for your shell. Source it from your shell profile, e.g.\n  source <(goleapai completion bash)",
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(cmd.OutOrStdout(), true)
+			case "zsh":
+				return root.GenZshCompletion(cmd.OutOrStdout())
+			case "fish":
+				return root.GenFishCompletion(cmd.OutOrStdout(), true)
+			}
+			return nil
+		},
+	}
+}
+
+func newDocsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate documentation",
+	}
+	cmd.AddCommand(newDocsGenerateCmd())
+	return cmd
+}
+
+func newDocsGenerateCmd() *cobra.Command {
+	var dir string
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Write man pages and markdown docs for all goleapai commands",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manDir := filepath.Join(dir, "man")
+			mdDir := filepath.Join(dir, "md")
+			for _, d := range []string{manDir, mdDir} {
+				if err := os.MkdirAll(d, 0o755); err != nil {
+					return err
+				}
+			}
+			root := cmd.Root()
+			// DisableAutoGenTag keeps generated pages reproducible.
+			root.DisableAutoGenTag = true
+			header := &doc.GenManHeader{Title: "GOLEAPAI", Section: "1", Source: "goleapifree"}
+			if err := doc.GenManTree(root, header, manDir); err != nil {
+				return fmt.Errorf("generating man pages: %w", err)
+			}
+			if err := doc.GenMarkdownTree(root, mdDir); err != nil {
+				return fmt.Errorf("generating markdown docs: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "wrote man pages to %s and markdown to %s\n", manDir, mdDir)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", "./docs", "output directory")
+	return cmd
+}
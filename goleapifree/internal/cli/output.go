@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output flag values shared by every listing command.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// Printer renders command results in the user-selected format so every
+// subcommand is scriptable the same way.
+type Printer struct {
+	Format string
+	Quiet  bool
+	Out    io.Writer
+}
+
+// AddOutputFlags registers --output and --quiet on a command and returns
+// the printer bound to them. Call in each command's constructor.
+func AddOutputFlags(cmd *cobra.Command) *Printer {
+	p := &Printer{}
+	cmd.Flags().StringVarP(&p.Format, "output", "o", OutputTable, "output format: table|json|yaml")
+	cmd.Flags().BoolVarP(&p.Quiet, "quiet", "q", false, "only print primary identifiers")
+	return p
+}
+
+// Print renders v. In table mode the table callback is invoked with a
+// tabwriter; in quiet mode only the ids are printed, one per line.
+func (p *Printer) Print(cmd *cobra.Command, v any, ids []string, table func(w io.Writer)) error {
+	out := p.Out
+	if out == nil {
+		out = cmd.OutOrStdout()
+	}
+	if p.Quiet {
+		for _, id := range ids {
+			fmt.Fprintln(out, id)
+		}
+		return nil
+	}
+	switch p.Format {
+	case OutputJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case OutputYAML:
+		enc := yaml.NewEncoder(out)
+		defer enc.Close()
+		return enc.Encode(v)
+	case OutputTable, "":
+		w := tabwriter.NewWriter(out, 2, 4, 2, ' ', 0)
+		table(w)
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q (want table|json|yaml)", p.Format)
+	}
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+type row struct {
+	ID   string `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+}
+
+func render(t *testing.T, p *Printer, v any, ids []string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	p.Out = &buf
+	err := p.Print(&cobra.Command{}, v, ids, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME")
+		for _, r := range v.([]row) {
+			fmt.Fprintf(w, "%s\t%s\n", r.ID, r.Name)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestPrinterFormats(t *testing.T) {
+	rows := []row{{ID: "p1", Name: "groq"}}
+	ids := []string{"p1"}
+
+	if out := render(t, &Printer{Format: OutputTable}, rows, ids); !strings.Contains(out, "ID") || !strings.Contains(out, "groq") {
+		t.Fatalf("table output: %q", out)
+	}
+	if out := render(t, &Printer{Format: OutputJSON}, rows, ids); !strings.Contains(out, `"id": "p1"`) {
+		t.Fatalf("json output: %q", out)
+	}
+	if out := render(t, &Printer{Format: OutputYAML}, rows, ids); !strings.Contains(out, "id: p1") {
+		t.Fatalf("yaml output: %q", out)
+	}
+	if out := render(t, &Printer{Quiet: true}, rows, ids); out != "p1\n" {
+		t.Fatalf("quiet output: %q", out)
+	}
+}
+
+func TestPrinterRejectsUnknownFormat(t *testing.T) {
+	p := &Printer{Format: "xml", Out: io.Discard}
+	err := p.Print(&cobra.Command{}, nil, nil, func(io.Writer) {})
+	if err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newPipeCmd() *cobra.Command {
+	var (
+		gatewayAddr string
+		model       string
+		system      string
+		maxTokens   int
+		verbose     bool
+	)
+	cmd := &cobra.Command{
+		Use:   "pipe",
+		Short: "Read a prompt from stdin and write the completion to stdout",
+		Long: "Read stdin, send it through the gateway, and write the completion to " +
+			"stdout — nothing else. Failures exit non-zero with the reason on stderr, " +
+			"so the gateway slots into Unix pipelines and cron jobs:\n\n" +
+			"  git log --since=yesterday | goleapai pipe --model fast --system \"summarize\"",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return fmt.Errorf("reading stdin: %w", err)
+			}
+			if strings.TrimSpace(string(input)) == "" {
+				return fmt.Errorf("stdin is empty; pipe something in")
+			}
+			return runAsk(cmd, gatewayAddr, askRequest{
+				Model:     model,
+				System:    system,
+				Prompt:    string(input),
+				MaxTokens: maxTokens,
+				Stream:    true,
+				Quiet:     !verbose,
+			})
+		},
+	}
+	cmd.Flags().StringVar(&gatewayAddr, "gateway", "http://localhost:8080", "gateway base URL")
+	cmd.Flags().StringVarP(&model, "model", "m", "default", "model or alias to route through")
+	cmd.Flags().StringVar(&system, "system", "", "optional system prompt")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "completion token cap (0 = provider default)")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "print token and cost accounting to stderr")
+	return cmd
+}
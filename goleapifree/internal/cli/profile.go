@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// profileKinds maps CLI profile names to pprof endpoint paths on the
+// gateway's debug listener.
+var profileKinds = map[string]string{
+	"cpu":       "/debug/pprof/profile",
+	"heap":      "/debug/pprof/heap",
+	"allocs":    "/debug/pprof/allocs",
+	"goroutine": "/debug/pprof/goroutine",
+	"block":     "/debug/pprof/block",
+	"mutex":     "/debug/pprof/mutex",
+	"trace":     "/debug/pprof/trace",
+}
+
+func newDebugProfileCmd() *cobra.Command {
+	var (
+		debugAddr string
+		token     string
+		seconds   int
+		output    string
+	)
+	cmd := &cobra.Command{
+		Use:   "profile [cpu|heap|allocs|goroutine|block|mutex|trace]",
+		Short: "Fetch a profile from a running gateway",
+		Long: "Fetch a pprof profile from the gateway's debug listener and write it to a " +
+			"file for `go tool pprof`. Profiling the live process captures real traffic, " +
+			"which a profile of the CLI itself never could.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind := args[0]
+			path, ok := profileKinds[kind]
+			if !ok {
+				return fmt.Errorf("unknown profile kind %q", kind)
+			}
+			target := debugAddr + path
+			if kind == "cpu" || kind == "trace" {
+				target += "?seconds=" + url.QueryEscape(strconv.Itoa(seconds))
+				fmt.Fprintf(cmd.OutOrStdout(), "capturing %s profile for %ds...\n", kind, seconds)
+			}
+			if output == "" {
+				output = kind + ".pprof"
+			}
+			data, err := debugGet(target, token, time.Duration(seconds+30)*time.Second)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(output, data, 0o644); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "wrote %d bytes to %s\n", len(data), output)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&debugAddr, "debug-server", "http://localhost:9091", "gateway debug listener base URL")
+	cmd.Flags().StringVar(&token, "token", os.Getenv("GOLEAPAI_DEBUG_TOKEN"), "debug bearer token (defaults to $GOLEAPAI_DEBUG_TOKEN)")
+	cmd.Flags().IntVar(&seconds, "seconds", 30, "capture duration for cpu and trace profiles")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "output file (defaults to <kind>.pprof)")
+	return cmd
+}
+
+func newDebugVarsCmd() *cobra.Command {
+	var (
+		debugAddr string
+		token     string
+	)
+	cmd := &cobra.Command{
+		Use:   "vars",
+		Short: "Show runtime metrics from a running gateway",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := debugGet(debugAddr+"/debug/vars", token, 10*time.Second)
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(append(data, '\n'))
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&debugAddr, "debug-server", "http://localhost:9091", "gateway debug listener base URL")
+	cmd.Flags().StringVar(&token, "token", os.Getenv("GOLEAPAI_DEBUG_TOKEN"), "debug bearer token (defaults to $GOLEAPAI_DEBUG_TOKEN)")
+	return cmd
+}
+
+// debugGet fetches from the debug listener with the bearer token set.
+func debugGet(target, token string, timeout time.Duration) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to debug listener at %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("debug listener %s: %s: %s", target, resp.Status, body)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+func newProvidersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "providers",
+		Short: "List configured providers",
+	}
+	p := AddOutputFlags(cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var providers []models.Provider
+		if err := adminGet("/admin/providers", &providers); err != nil {
+			return err
+		}
+		ids := make([]string, len(providers))
+		for i, pr := range providers {
+			ids[i] = pr.ID
+		}
+		return p.Print(cmd, providers, ids, func(w io.Writer) {
+			fmt.Fprintln(w, "ID\tNAME\tTYPE\tBASE URL\tENABLED\tPRIORITY")
+			for _, pr := range providers {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%d\n",
+					pr.ID, pr.Name, pr.Type, pr.BaseURL, pr.Enabled, pr.Priority)
+			}
+		})
+	}
+	return cmd
+}
@@ -0,0 +1,69 @@
+// Package cli implements the goleapai command tree.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// serverAddr is the admin API base URL shared by subcommands that talk to a
+// running gateway.
+var serverAddr string
+
+// NewRootCmd builds the goleapai root command.
+func NewRootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:          "goleapai",
+		Short:        "goleapifree gateway CLI",
+		Long:         "goleapai manages and inspects a running goleapifree gateway.",
+		SilenceUsage: true,
+	}
+	root.PersistentFlags().StringVar(&serverAddr, "server", "http://localhost:9090", "admin API base URL")
+	root.AddCommand(newAskCmd())
+	root.AddCommand(newPipeCmd())
+	root.AddCommand(newStatsCmd())
+	root.AddCommand(newProvidersCmd())
+	root.AddCommand(newDiscoveryCmd())
+	root.AddCommand(newConfigCmd())
+	root.AddCommand(newDebugCmd())
+	root.AddCommand(newCompletionCmd())
+	root.AddCommand(newDocsCmd())
+	return root
+}
+
+// adminGet fetches a JSON document from the admin API into out.
+func adminGet(path string, out any) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(serverAddr + path)
+	if err != nil {
+		return fmt.Errorf("connecting to gateway at %s: %w", serverAddr, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("admin API %s: %s: %s", path, resp.Status, body)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// adminPost sends a request body to the admin API and decodes the JSON
+// reply into out.
+func adminPost(path string, body []byte, out any) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(serverAddr+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("connecting to gateway at %s: %w", serverAddr, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		reply, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("admin API %s: %s: %s", path, resp.Status, reply)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
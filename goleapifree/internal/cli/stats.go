@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/biodoia/goleapifree/internal/stats"
+)
+
+func newStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Usage statistics and reports",
+	}
+	cmd.AddCommand(newStatsCapacityCmd())
+	return cmd
+}
+
+func newStatsCapacityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capacity",
+		Short: "Report when each provider's free tier will be exhausted this month",
+	}
+	p := AddOutputFlags(cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var report stats.CapacityReport
+		if err := adminGet("/admin/stats/capacity", &report); err != nil {
+			return err
+		}
+		ids := make([]string, 0, len(report.Providers))
+		for _, pr := range report.Providers {
+			if pr.WillExhaust {
+				ids = append(ids, pr.Provider)
+			}
+		}
+		return p.Print(cmd, report, ids, func(w io.Writer) {
+			fmt.Fprintln(w, "PROVIDER\tQUOTA\tUSED\t%\tRATE/DAY\tFORECAST\tEXHAUSTS")
+			for _, pr := range report.Providers {
+				exhausts := "-"
+				if pr.ExhaustedAt != nil {
+					exhausts = pr.ExhaustedAt.Format("Jan 02 15:04")
+				}
+				fmt.Fprintf(w, "%s\t%d\t%d\t%.1f%%\t%.0f\t%.0f\t%s\n",
+					pr.Provider, pr.MonthlyQuota, pr.UsedThisMonth, pr.PercentOfQuota,
+					pr.DailyRate, pr.ForecastMonth, exhausts)
+			}
+			if len(report.AliasesAtRisk) > 0 {
+				fmt.Fprintf(w, "\nAliases at risk: %s\n", strings.Join(report.AliasesAtRisk, ", "))
+			}
+		})
+	}
+	return cmd
+}
@@ -0,0 +1,189 @@
+// Package configdiff compares gateway configuration between environments
+// for promotion workflows: export the running configuration from one
+// gateway as a snapshot, diff it against another, and review the
+// structured result before applying anything. The diff covers the
+// DB-stored routing state (providers and aliases), not just the static
+// config file.
+package configdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+// Entry kinds and operations. Operations read from the promotion's point
+// of view: applying the snapshot to the running gateway would add,
+// remove or change the entry.
+const (
+	KindProvider = "provider"
+	KindAlias    = "alias"
+
+	OpAdded   = "added"   // in the snapshot, missing from the running config
+	OpRemoved = "removed" // running, absent from the snapshot
+	OpChanged = "changed"
+)
+
+// volatileFields differ between environments by construction (versions
+// count local edits, timestamps record local history) and never block a
+// promotion, so the diff ignores them.
+var volatileFields = map[string]bool{
+	"version":    true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// Snapshot is the portable configuration document exchanged between
+// environments.
+type Snapshot struct {
+	Providers []*models.Provider   `json:"providers"`
+	Aliases   []*models.ModelAlias `json:"aliases"`
+}
+
+// FieldChange is one differing field on a changed entry.
+type FieldChange struct {
+	Field    string `json:"field"`
+	Running  any    `json:"running"`
+	Snapshot any    `json:"snapshot"`
+}
+
+// Entry is one added, removed or changed configuration object.
+type Entry struct {
+	Kind   string        `json:"kind"`
+	ID     string        `json:"id"`
+	Op     string        `json:"op"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// Diff is the full comparison result.
+type Diff struct {
+	InSync  bool    `json:"in_sync"`
+	Entries []Entry `json:"entries"`
+}
+
+// Parse reads a snapshot from JSON or YAML. YAML documents are decoded
+// generically and re-encoded as JSON first, so the models' json tags
+// name the fields in both formats.
+func Parse(data []byte) (Snapshot, error) {
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err == nil {
+		return snap, nil
+	}
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return Snapshot{}, fmt.Errorf("snapshot is neither JSON nor YAML: %w", err)
+	}
+	buf, err := json.Marshal(raw)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if err := json.Unmarshal(buf, &snap); err != nil {
+		return Snapshot{}, fmt.Errorf("invalid snapshot document: %w", err)
+	}
+	return snap, nil
+}
+
+// Compare diffs the running configuration against a snapshot. Entries
+// come out sorted by kind then ID, so the same pair of configurations
+// always produces the same document.
+func Compare(running, snapshot Snapshot) Diff {
+	var d Diff
+	d.Entries = append(d.Entries, compareSet(KindProvider,
+		providerMaps(running.Providers), providerMaps(snapshot.Providers))...)
+	d.Entries = append(d.Entries, compareSet(KindAlias,
+		aliasMaps(running.Aliases), aliasMaps(snapshot.Aliases))...)
+	d.InSync = len(d.Entries) == 0
+	return d
+}
+
+func providerMaps(providers []*models.Provider) map[string]map[string]any {
+	out := make(map[string]map[string]any, len(providers))
+	for _, p := range providers {
+		out[p.ID] = asMap(p)
+	}
+	return out
+}
+
+func aliasMaps(aliases []*models.ModelAlias) map[string]map[string]any {
+	out := make(map[string]map[string]any, len(aliases))
+	for _, a := range aliases {
+		out[a.Alias] = asMap(a)
+	}
+	return out
+}
+
+// asMap flattens an entity through its JSON encoding so field names and
+// omitted defaults match what the admin API serves.
+func asMap(v any) map[string]any {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]any
+	_ = json.Unmarshal(buf, &m)
+	return m
+}
+
+func compareSet(kind string, running, snapshot map[string]map[string]any) []Entry {
+	ids := make([]string, 0, len(running)+len(snapshot))
+	seen := make(map[string]bool, len(running)+len(snapshot))
+	for id := range running {
+		ids, seen[id] = append(ids, id), true
+	}
+	for id := range snapshot {
+		if !seen[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var entries []Entry
+	for _, id := range ids {
+		run, inRun := running[id]
+		snap, inSnap := snapshot[id]
+		switch {
+		case !inRun:
+			entries = append(entries, Entry{Kind: kind, ID: id, Op: OpAdded})
+		case !inSnap:
+			entries = append(entries, Entry{Kind: kind, ID: id, Op: OpRemoved})
+		default:
+			if fields := compareFields(run, snap); len(fields) > 0 {
+				entries = append(entries, Entry{Kind: kind, ID: id, Op: OpChanged, Fields: fields})
+			}
+		}
+	}
+	return entries
+}
+
+func compareFields(running, snapshot map[string]any) []FieldChange {
+	names := make([]string, 0, len(running)+len(snapshot))
+	seen := make(map[string]bool, len(running)+len(snapshot))
+	for name := range running {
+		if !volatileFields[name] {
+			names, seen[name] = append(names, name), true
+		}
+	}
+	for name := range snapshot {
+		if !volatileFields[name] && !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []FieldChange
+	for _, name := range names {
+		if !reflect.DeepEqual(running[name], snapshot[name]) {
+			changes = append(changes, FieldChange{
+				Field:    name,
+				Running:  running[name],
+				Snapshot: snapshot[name],
+			})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,75 @@
+package configdiff
+
+import (
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+func TestCompare(t *testing.T) {
+	running := Snapshot{
+		Providers: []*models.Provider{
+			{ID: "openai", Type: "openai", Priority: 10, Enabled: true, Version: 7},
+			{ID: "staging-only", Type: "openai", Enabled: true},
+		},
+		Aliases: []*models.ModelAlias{
+			{Alias: "gpt-4", Targets: []models.AliasTarget{{ProviderID: "openai", Model: "gpt-4o"}}},
+		},
+	}
+	snapshot := Snapshot{
+		Providers: []*models.Provider{
+			{ID: "openai", Type: "openai", Priority: 50, Enabled: true, Version: 3},
+			{ID: "prod-only", Type: "anthropic", Enabled: true},
+		},
+		Aliases: []*models.ModelAlias{
+			{Alias: "gpt-4", Targets: []models.AliasTarget{{ProviderID: "openai", Model: "gpt-4o"}}},
+		},
+	}
+
+	d := Compare(running, snapshot)
+	if d.InSync {
+		t.Fatal("diff reported in sync")
+	}
+	if len(d.Entries) != 3 {
+		t.Fatalf("entries = %+v", d.Entries)
+	}
+	// Sorted by kind then ID: openai changed, prod-only added, staging-only removed.
+	if e := d.Entries[0]; e.ID != "openai" || e.Op != OpChanged {
+		t.Fatalf("entry 0 = %+v", e)
+	}
+	if len(d.Entries[0].Fields) != 1 || d.Entries[0].Fields[0].Field != "priority" {
+		t.Fatalf("changed fields = %+v", d.Entries[0].Fields)
+	}
+	if e := d.Entries[1]; e.ID != "prod-only" || e.Op != OpAdded {
+		t.Fatalf("entry 1 = %+v", e)
+	}
+	if e := d.Entries[2]; e.ID != "staging-only" || e.Op != OpRemoved {
+		t.Fatalf("entry 2 = %+v", e)
+	}
+}
+
+func TestCompareIgnoresVolatileFields(t *testing.T) {
+	running := Snapshot{Providers: []*models.Provider{{ID: "p", Type: "openai", Version: 42}}}
+	snapshot := Snapshot{Providers: []*models.Provider{{ID: "p", Type: "openai", Version: 1}}}
+	if d := Compare(running, snapshot); !d.InSync {
+		t.Fatalf("version-only difference reported: %+v", d.Entries)
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	jsonDoc := []byte(`{"providers":[{"id":"p","base_url":"http://x","type":"openai"}]}`)
+	snap, err := Parse(jsonDoc)
+	if err != nil || len(snap.Providers) != 1 || snap.Providers[0].BaseURL != "http://x" {
+		t.Fatalf("json: %+v, %v", snap, err)
+	}
+
+	yamlDoc := []byte("providers:\n  - id: p\n    base_url: http://x\n    type: openai\n")
+	snap, err = Parse(yamlDoc)
+	if err != nil || len(snap.Providers) != 1 || snap.Providers[0].BaseURL != "http://x" {
+		t.Fatalf("yaml: %+v, %v", snap, err)
+	}
+
+	if _, err := Parse([]byte("{nonsense")); err == nil {
+		t.Fatal("malformed document accepted")
+	}
+}
@@ -0,0 +1,218 @@
+// Package datasets manages versioned JSONL prompt/response sets. Uploads
+// are validated and stored immutably in the archive; evaluation runs,
+// what-if simulations and fine-tuning jobs reference them as
+// "name@version" (or just "name" for the latest version).
+package datasets
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/storage"
+)
+
+// ErrNotFound is returned for unknown dataset names or versions.
+var ErrNotFound = errors.New("datasets: not found")
+
+// Record is one prompt/response pair. Response may be empty for
+// prompt-only evaluation sets; Expected holds an assertion target.
+type Record struct {
+	Prompt   string            `json:"prompt"`
+	Response string            `json:"response,omitempty"`
+	Expected string            `json:"expected,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Dataset describes one immutable version of a named set.
+type Dataset struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	Records   int       `json:"records"`
+	Bytes     int64     `json:"bytes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Ref renders the canonical "name@version" reference.
+func (d Dataset) Ref() string { return fmt.Sprintf("%s@%d", d.Name, d.Version) }
+
+// Store keeps dataset blobs in the archive and a manifest alongside.
+// Versions are append-only: re-uploading a name creates the next version.
+type Store struct {
+	archive storage.Archive
+
+	mu       sync.Mutex
+	manifest map[string][]Dataset // name -> versions, ascending
+}
+
+const manifestKey = "datasets/manifest.json"
+
+// NewStore wraps the archive, loading the manifest if one exists.
+func NewStore(archive storage.Archive) (*Store, error) {
+	s := &Store{archive: archive, manifest: make(map[string][]Dataset)}
+	obj, err := archive.Get(manifestKey)
+	if err != nil {
+		return s, nil // first run: empty manifest
+	}
+	defer obj.Close()
+	if err := json.NewDecoder(obj).Decode(&s.manifest); err != nil {
+		return nil, fmt.Errorf("datasets: corrupt manifest: %w", err)
+	}
+	return s, nil
+}
+
+// Upload validates a JSONL body and stores it as the next version of
+// name. Every line must be a JSON object with a non-empty prompt.
+func (s *Store) Upload(name string, r io.Reader) (*Dataset, error) {
+	if name == "" || strings.ContainsAny(name, "@/ ") {
+		return nil, errors.New("datasets: name must be non-empty without '@', '/' or spaces")
+	}
+	var buf strings.Builder
+	count := 0
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			return nil, fmt.Errorf("datasets: line %d: %w", line, err)
+		}
+		if rec.Prompt == "" {
+			return nil, fmt.Errorf("datasets: line %d: prompt is required", line)
+		}
+		buf.WriteString(text)
+		buf.WriteByte('\n')
+		count++
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("datasets: empty dataset")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := Dataset{
+		Name: name, Version: len(s.manifest[name]) + 1,
+		Records: count, Bytes: int64(buf.Len()), CreatedAt: time.Now(),
+	}
+	if err := s.archive.Put(s.blobKey(d.Name, d.Version), strings.NewReader(buf.String())); err != nil {
+		return nil, err
+	}
+	s.manifest[name] = append(s.manifest[name], d)
+	if err := s.flushLocked(); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Resolve parses a "name" or "name@version" reference and returns the
+// matching dataset, defaulting to the latest version.
+func (s *Store) Resolve(ref string) (*Dataset, error) {
+	name, version := ref, 0
+	if i := strings.IndexByte(ref, '@'); i >= 0 {
+		name = ref[:i]
+		v, err := strconv.Atoi(ref[i+1:])
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("datasets: invalid version in ref %q", ref)
+		}
+		version = v
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions := s.manifest[name]
+	if len(versions) == 0 {
+		return nil, ErrNotFound
+	}
+	if version == 0 {
+		version = len(versions)
+	}
+	if version > len(versions) {
+		return nil, ErrNotFound
+	}
+	d := versions[version-1]
+	return &d, nil
+}
+
+// Records loads and decodes every record of a reference.
+func (s *Store) Records(ref string) ([]Record, error) {
+	d, err := s.Resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := s.archive.Get(s.blobKey(d.Name, d.Version))
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	var out []Record
+	sc := bufio.NewScanner(obj)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var rec Record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, err
+		}
+		out = append(out, rec)
+	}
+	return out, sc.Err()
+}
+
+// Open returns the raw JSONL body of a reference, for consumers that
+// stream it onward (e.g. fine-tuning uploads).
+func (s *Store) Open(ref string) (io.ReadCloser, error) {
+	d, err := s.Resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+	return s.archive.Get(s.blobKey(d.Name, d.Version))
+}
+
+// List returns the latest version of every dataset, sorted by name.
+func (s *Store) List() []Dataset {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Dataset, 0, len(s.manifest))
+	for _, versions := range s.manifest {
+		out = append(out, versions[len(versions)-1])
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Versions returns every version of one dataset, oldest first.
+func (s *Store) Versions(name string) ([]Dataset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions := s.manifest[name]
+	if len(versions) == 0 {
+		return nil, ErrNotFound
+	}
+	out := make([]Dataset, len(versions))
+	copy(out, versions)
+	return out, nil
+}
+
+func (s *Store) blobKey(name string, version int) string {
+	return fmt.Sprintf("datasets/%s/v%d.jsonl", name, version)
+}
+
+// flushLocked persists the manifest. Caller holds s.mu.
+func (s *Store) flushLocked() error {
+	data, err := json.MarshalIndent(s.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return s.archive.Put(manifestKey, strings.NewReader(string(data)))
+}
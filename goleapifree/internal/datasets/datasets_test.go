@@ -0,0 +1,90 @@
+package datasets
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/storage"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	a, err := storage.NewFileArchive(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewStore(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+const body = `{"prompt":"hi","expected":"hello"}
+{"prompt":"2+2?","expected":"4"}
+`
+
+func TestUploadVersioningAndResolve(t *testing.T) {
+	s := newTestStore(t)
+	d1, err := s.Upload("greetings", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d1.Version != 1 || d1.Records != 2 {
+		t.Fatalf("dataset = %+v", d1)
+	}
+	d2, err := s.Upload("greetings", strings.NewReader(body))
+	if err != nil || d2.Version != 2 {
+		t.Fatalf("dataset = %+v, %v", d2, err)
+	}
+
+	// Bare name resolves to the latest version; explicit pins stick.
+	if d, _ := s.Resolve("greetings"); d.Version != 2 {
+		t.Fatalf("latest = %+v", d)
+	}
+	if d, _ := s.Resolve("greetings@1"); d.Version != 1 {
+		t.Fatalf("pinned = %+v", d)
+	}
+	if _, err := s.Resolve("greetings@9"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+
+	recs, err := s.Records("greetings@1")
+	if err != nil || len(recs) != 2 || recs[1].Expected != "4" {
+		t.Fatalf("records = %+v, %v", recs, err)
+	}
+}
+
+func TestUploadRejectsInvalidJSONL(t *testing.T) {
+	s := newTestStore(t)
+	if _, err := s.Upload("bad", strings.NewReader("{\"prompt\":\"ok\"}\nnot json\n")); err == nil {
+		t.Fatal("expected error for malformed line")
+	}
+	if _, err := s.Upload("bad", strings.NewReader("{\"response\":\"no prompt\"}\n")); err == nil {
+		t.Fatal("expected error for missing prompt")
+	}
+	if _, err := s.Upload("bad name", strings.NewReader(body)); err == nil {
+		t.Fatal("expected error for invalid name")
+	}
+}
+
+func TestManifestSurvivesReopen(t *testing.T) {
+	a, err := storage.NewFileArchive(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _ := NewStore(a)
+	if _, err := s.Upload("greetings", strings.NewReader(body)); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewStore(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := reopened.Resolve("greetings")
+	if err != nil || d.Records != 2 {
+		t.Fatalf("dataset = %+v, %v", d, err)
+	}
+}
@@ -0,0 +1,58 @@
+// Package discovery tracks provider candidates found by probes and
+// announcements before an operator promotes them to configured providers.
+package discovery
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Candidate is a discovered provider endpoint that is not yet configured.
+type Candidate struct {
+	Name     string    `json:"name"`
+	BaseURL  string    `json:"base_url"`
+	Source   string    `json:"source"` // "probe", "announce", "manual"
+	LastSeen time.Time `json:"last_seen"`
+	Healthy  bool      `json:"healthy"`
+}
+
+// Registry is the in-memory set of discovered candidates.
+type Registry struct {
+	mu   sync.RWMutex
+	byID map[string]*Candidate
+}
+
+// NewRegistry returns an empty discovery registry.
+func NewRegistry() *Registry {
+	return &Registry{byID: make(map[string]*Candidate)}
+}
+
+// Observe records a sighting of a candidate, creating or refreshing it.
+func (r *Registry) Observe(c Candidate) {
+	if c.LastSeen.IsZero() {
+		c.LastSeen = time.Now()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byID[c.Name] = &c
+}
+
+// List returns all candidates sorted by name.
+func (r *Registry) List() []Candidate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Candidate, 0, len(r.byID))
+	for _, c := range r.byID {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Remove drops a candidate, e.g. after promotion or staleness pruning.
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.byID, name)
+}
@@ -0,0 +1,6 @@
+// Package edition declares which optional subsystems this binary was
+// compiled with. The default ("full") edition includes everything; the
+// "edge" edition (built with -tags edge) drops the WebUI, experiments
+// and mobile surfaces and links SQLite only, so the gateway fits on
+// small ARM devices fronting a local Ollama.
+package edition
@@ -0,0 +1,16 @@
+//go:build edge
+
+package edition
+
+// Name identifies the build edition.
+const Name = "edge"
+
+// Subsystem availability in this edition.
+const (
+	WebUI       = false
+	Experiments = false
+	Mobile      = false
+)
+
+// StorageDrivers lists the storage backends linked into this edition.
+var StorageDrivers = []string{"sqlite"}
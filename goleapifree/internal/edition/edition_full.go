@@ -0,0 +1,16 @@
+//go:build !edge
+
+package edition
+
+// Name identifies the build edition.
+const Name = "full"
+
+// Subsystem availability in this edition.
+const (
+	WebUI       = true
+	Experiments = true
+	Mobile      = true
+)
+
+// StorageDrivers lists the storage backends linked into this edition.
+var StorageDrivers = []string{"sqlite", "postgres", "redis"}
@@ -0,0 +1,324 @@
+// Package envelope implements per-tenant envelope encryption for stored
+// conversations and archive blobs. Every tenant gets its own data key,
+// wrapped by a single master key; rotating a tenant key only re-wraps
+// future writes, and a background re-encryption job migrates old
+// ciphertext forward without blocking anything.
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// KeySize is the required master and data key length (AES-256).
+const KeySize = 32
+
+// Keyring errors.
+var (
+	ErrBadMasterKey = errors.New("envelope: master key must be 32 bytes")
+	ErrKeyNotFound  = errors.New("envelope: data key version not found")
+)
+
+// sealed is the stored ciphertext format. Everything needed to decrypt
+// (except the keys) travels with the blob, so storage backends stay
+// oblivious to tenants and rotations.
+type sealed struct {
+	Tenant     string `json:"tenant"`
+	KeyVersion int    `json:"key_version"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// tenantKey is one wrapped data key generation.
+type tenantKey struct {
+	version int
+	wrapped []byte // data key sealed under the master key
+}
+
+// Keyring manages the master key and the per-tenant data keys.
+type Keyring struct {
+	mu     sync.Mutex
+	master []byte
+	keys   map[string][]tenantKey // tenant -> generations, oldest first
+}
+
+// NewKeyring creates a keyring from a 32-byte master key. Tenants get
+// their first data key lazily on first write.
+func NewKeyring(master []byte) (*Keyring, error) {
+	if len(master) != KeySize {
+		return nil, ErrBadMasterKey
+	}
+	return &Keyring{
+		master: append([]byte(nil), master...),
+		keys:   make(map[string][]tenantKey),
+	}, nil
+}
+
+// Seal encrypts plaintext under the tenant's current data key and
+// returns a self-describing envelope.
+func (k *Keyring) Seal(tenant string, plaintext []byte) ([]byte, error) {
+	k.mu.Lock()
+	current, err := k.currentLocked(tenant)
+	if err != nil {
+		k.mu.Unlock()
+		return nil, err
+	}
+	dataKey, err := aesOpen(k.master, current.wrapped)
+	k.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("envelope: unwrapping data key: %w", err)
+	}
+
+	nonce, ct, err := aesSealSplit(dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sealed{
+		Tenant:     tenant,
+		KeyVersion: current.version,
+		Nonce:      nonce,
+		Ciphertext: ct,
+	})
+}
+
+// Open decrypts an envelope produced by Seal, under whichever data key
+// generation it names; rotated-away generations stay readable.
+func (k *Keyring) Open(data []byte) ([]byte, error) {
+	env, dataKey, err := k.unwrap(data)
+	if err != nil {
+		return nil, err
+	}
+	return aesOpenSplit(dataKey, env.Nonce, env.Ciphertext)
+}
+
+// RotateTenant mints a new data key generation for the tenant and
+// returns its version. Existing envelopes keep decrypting; new writes
+// use the new key; the re-encryption job migrates old blobs.
+func (k *Keyring) RotateTenant(tenant string) (int, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	next, err := k.mintLocked(tenant)
+	if err != nil {
+		return 0, err
+	}
+	return next.version, nil
+}
+
+// RotateMaster re-wraps every data key under a new master key. Data
+// keys themselves do not change, so no stored blob needs touching.
+func (k *Keyring) RotateMaster(master []byte) error {
+	if len(master) != KeySize {
+		return ErrBadMasterKey
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	rewrapped := make(map[string][]tenantKey, len(k.keys))
+	for tenant, gens := range k.keys {
+		out := make([]tenantKey, len(gens))
+		for i, gen := range gens {
+			dataKey, err := aesOpen(k.master, gen.wrapped)
+			if err != nil {
+				return fmt.Errorf("envelope: unwrapping %s v%d: %w", tenant, gen.version, err)
+			}
+			wrapped, err := aesSeal(master, dataKey)
+			if err != nil {
+				return err
+			}
+			out[i] = tenantKey{version: gen.version, wrapped: wrapped}
+		}
+		rewrapped[tenant] = out
+	}
+	k.master = append([]byte(nil), master...)
+	k.keys = rewrapped
+	return nil
+}
+
+// ReEncrypt moves an envelope to the tenant's current key generation.
+// It reports changed=false (returning the input untouched) when the
+// envelope is already current, so the job can skip the write.
+func (k *Keyring) ReEncrypt(data []byte) (out []byte, changed bool, err error) {
+	env, dataKey, err := k.unwrap(data)
+	if err != nil {
+		return nil, false, err
+	}
+	k.mu.Lock()
+	current, err := k.currentLocked(env.Tenant)
+	k.mu.Unlock()
+	if err != nil {
+		return nil, false, err
+	}
+	if env.KeyVersion == current.version {
+		return data, false, nil
+	}
+	plaintext, err := aesOpenSplit(dataKey, env.Nonce, env.Ciphertext)
+	if err != nil {
+		return nil, false, err
+	}
+	resealed, err := k.Seal(env.Tenant, plaintext)
+	if err != nil {
+		return nil, false, err
+	}
+	return resealed, true, nil
+}
+
+// Versions returns the tenant's key generation versions, oldest first.
+func (k *Keyring) Versions(tenant string) []int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	out := make([]int, 0, len(k.keys[tenant]))
+	for _, gen := range k.keys[tenant] {
+		out = append(out, gen.version)
+	}
+	return out
+}
+
+// unwrap parses an envelope and unwraps the data key it names.
+func (k *Keyring) unwrap(data []byte) (sealed, []byte, error) {
+	var env sealed
+	if err := json.Unmarshal(data, &env); err != nil {
+		return sealed{}, nil, fmt.Errorf("envelope: malformed envelope: %w", err)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, gen := range k.keys[env.Tenant] {
+		if gen.version != env.KeyVersion {
+			continue
+		}
+		dataKey, err := aesOpen(k.master, gen.wrapped)
+		if err != nil {
+			return sealed{}, nil, fmt.Errorf("envelope: unwrapping data key: %w", err)
+		}
+		return env, dataKey, nil
+	}
+	return sealed{}, nil, ErrKeyNotFound
+}
+
+// currentLocked returns the tenant's newest key generation, minting the
+// first one for tenants never written before. Callers hold k.mu.
+func (k *Keyring) currentLocked(tenant string) (tenantKey, error) {
+	gens := k.keys[tenant]
+	if len(gens) == 0 {
+		return k.mintLocked(tenant)
+	}
+	return gens[len(gens)-1], nil
+}
+
+// mintLocked generates and wraps a fresh data key generation.
+func (k *Keyring) mintLocked(tenant string) (tenantKey, error) {
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return tenantKey{}, err
+	}
+	wrapped, err := aesSeal(k.master, dataKey)
+	if err != nil {
+		return tenantKey{}, err
+	}
+	gens := k.keys[tenant]
+	next := tenantKey{version: len(gens) + 1, wrapped: wrapped}
+	k.keys[tenant] = append(gens, next)
+	return next, nil
+}
+
+// ReEncryptor is the background job migrating stored envelopes to each
+// tenant's current key generation after a rotation.
+type ReEncryptor struct {
+	Keyring *Keyring
+	// List names the stored envelopes; Load and Store move their bytes.
+	// The conversation store and the archive each wire their own.
+	List  func(ctx context.Context) ([]string, error)
+	Load  func(ctx context.Context, key string) ([]byte, error)
+	Store func(ctx context.Context, key string, data []byte) error
+}
+
+// Run re-encrypts every listed envelope that is behind its tenant's
+// current key. It keeps going past per-blob failures and reports them
+// together, so one corrupt blob cannot stall a rotation. Run matches
+// scheduler.JobFunc.
+func (r *ReEncryptor) Run(ctx context.Context) error {
+	keys, err := r.List(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		data, err := r.Load(ctx, key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+		out, changed, err := r.Keyring.ReEncrypt(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if err := r.Store(ctx, key, out); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// aesSeal encrypts plaintext under key with AES-256-GCM, prepending
+// the nonce to the ciphertext.
+func aesSeal(key, plaintext []byte) ([]byte, error) {
+	nonce, ct, err := aesSealSplit(key, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return append(nonce, ct...), nil
+}
+
+// aesOpen reverses aesSeal.
+func aesOpen(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("envelope: ciphertext too short")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+// aesSealSplit encrypts plaintext, returning nonce and ciphertext
+// separately for the JSON envelope.
+func aesSealSplit(key, plaintext []byte) (nonce, ciphertext []byte, err error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return nonce, gcm.Seal(nil, nonce, plaintext, nil), nil
+}
+
+// aesOpenSplit reverses aesSealSplit.
+func aesOpenSplit(key, nonce, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
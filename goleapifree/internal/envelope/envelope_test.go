@@ -0,0 +1,132 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func testMaster(b byte) []byte {
+	master := make([]byte, KeySize)
+	for i := range master {
+		master[i] = b
+	}
+	return master
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	k, err := NewKeyring(testMaster(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := k.Seal("acme", []byte("conversation body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(env, []byte("conversation body")) {
+		t.Fatal("plaintext visible in envelope")
+	}
+	out, err := k.Open(env)
+	if err != nil || string(out) != "conversation body" {
+		t.Fatalf("Open = %q, %v", out, err)
+	}
+
+	// A different tenant's envelopes use a different data key.
+	other, err := k.Seal("globex", []byte("conversation body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(env, other) {
+		t.Fatal("tenants share ciphertext")
+	}
+
+	if _, err := NewKeyring([]byte("short")); err != ErrBadMasterKey {
+		t.Fatalf("short master key: %v", err)
+	}
+}
+
+func TestTenantRotation(t *testing.T) {
+	k, _ := NewKeyring(testMaster(1))
+	old, err := k.Seal("acme", []byte("before rotation"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	version, err := k.RotateTenant("acme")
+	if err != nil || version != 2 {
+		t.Fatalf("RotateTenant = %d, %v", version, err)
+	}
+	// Old envelopes stay readable after rotation.
+	if out, err := k.Open(old); err != nil || string(out) != "before rotation" {
+		t.Fatalf("Open old = %q, %v", out, err)
+	}
+
+	migrated, changed, err := k.ReEncrypt(old)
+	if err != nil || !changed {
+		t.Fatalf("ReEncrypt = changed %v, %v", changed, err)
+	}
+	if out, _ := k.Open(migrated); string(out) != "before rotation" {
+		t.Fatalf("migrated = %q", out)
+	}
+	if _, changed, _ := k.ReEncrypt(migrated); changed {
+		t.Fatal("current envelope re-encrypted again")
+	}
+}
+
+func TestMasterRotation(t *testing.T) {
+	k, _ := NewKeyring(testMaster(1))
+	env, _ := k.Seal("acme", []byte("payload"))
+	if err := k.RotateMaster(testMaster(2)); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := k.Open(env); err != nil || string(out) != "payload" {
+		t.Fatalf("Open after master rotation = %q, %v", out, err)
+	}
+	if err := k.RotateMaster([]byte("short")); err != ErrBadMasterKey {
+		t.Fatalf("short master: %v", err)
+	}
+}
+
+func TestReEncryptorJob(t *testing.T) {
+	k, _ := NewKeyring(testMaster(1))
+	store := map[string][]byte{}
+	for _, name := range []string{"a", "b"} {
+		env, _ := k.Seal("acme", []byte("blob-"+name))
+		store[name] = env
+	}
+	if _, err := k.RotateTenant("acme"); err != nil {
+		t.Fatal(err)
+	}
+	writes := 0
+	job := &ReEncryptor{
+		Keyring: k,
+		List: func(context.Context) ([]string, error) {
+			return []string{"a", "b"}, nil
+		},
+		Load: func(_ context.Context, key string) ([]byte, error) {
+			return store[key], nil
+		},
+		Store: func(_ context.Context, key string, data []byte) error {
+			store[key] = data
+			writes++
+			return nil
+		},
+	}
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if writes != 2 {
+		t.Fatalf("writes = %d", writes)
+	}
+	for name, env := range store {
+		if out, err := k.Open(env); err != nil || string(out) != "blob-"+name {
+			t.Fatalf("%s = %q, %v", name, out, err)
+		}
+	}
+	// A second run finds everything current and writes nothing.
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if writes != 2 {
+		t.Fatalf("idempotent run wrote %d times", writes-2)
+	}
+}
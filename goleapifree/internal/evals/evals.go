@@ -0,0 +1,261 @@
+// Package evals runs regression suites against model aliases: each suite
+// replays a dataset through an alias and checks assertions on the
+// completions. Pass/fail history per alias gates automated rollouts —
+// a routing change that regresses a suite must not auto-promote.
+package evals
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/datasets"
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// Assertion kinds.
+const (
+	AssertContains = "contains" // completion must contain Value
+	AssertJSON     = "json"     // completion must parse as JSON
+	AssertJudge    = "judge"    // judge score must be >= MinScore
+)
+
+// Errors returned by the runner.
+var (
+	ErrSuiteNotFound = errors.New("evals: suite not found")
+	ErrRegressed     = errors.New("evals: alias has a failing suite")
+)
+
+// Assertion is one check applied to every completion of a suite. For
+// "contains", Value may be empty, in which case the record's expected
+// field is used instead.
+type Assertion struct {
+	Type     string  `json:"type"`
+	Value    string  `json:"value,omitempty"`
+	MinScore float64 `json:"min_score,omitempty"`
+}
+
+// Suite binds a dataset to an alias and its assertions.
+type Suite struct {
+	Name       string      `json:"name"`
+	Alias      string      `json:"alias"`
+	Dataset    string      `json:"dataset"` // "name" or "name@version" ref
+	Assertions []Assertion `json:"assertions"`
+}
+
+// CaseResult is the outcome for one dataset record.
+type CaseResult struct {
+	Prompt     string `json:"prompt"`
+	Completion string `json:"completion,omitempty"`
+	Pass       bool   `json:"pass"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// Result is one suite run.
+type Result struct {
+	Suite     string       `json:"suite"`
+	Alias     string       `json:"alias"`
+	Dataset   string       `json:"dataset"` // pinned ref the run used
+	Pass      bool         `json:"pass"`
+	Passed    int          `json:"passed"`
+	Failed    int          `json:"failed"`
+	Cases     []CaseResult `json:"cases"`
+	StartedAt time.Time    `json:"started_at"`
+	Duration  string       `json:"duration"`
+}
+
+// Judge scores a completion against a record, in [0, 1]. Typically an
+// LLM-as-judge call; tests inject fakes.
+type Judge func(ctx context.Context, prompt, expected, completion string) (float64, error)
+
+// Runner holds registered suites and their run history.
+type Runner struct {
+	Datasets *datasets.Store
+	// Complete issues one completion through the given alias.
+	Complete func(ctx context.Context, alias string, req providers.ChatRequest) (*providers.ChatResponse, error)
+	Judge    Judge
+
+	mu     sync.Mutex
+	suites map[string]Suite
+	// history keeps the latest result per suite; that is what gates
+	// rollouts, so older runs need not be retained here.
+	history map[string]*Result
+}
+
+// NewRunner creates a runner over the dataset store.
+func NewRunner(store *datasets.Store) *Runner {
+	return &Runner{
+		Datasets: store,
+		suites:   make(map[string]Suite),
+		history:  make(map[string]*Result),
+	}
+}
+
+// Register adds or replaces a suite.
+func (r *Runner) Register(s Suite) error {
+	if s.Name == "" || s.Alias == "" || s.Dataset == "" || len(s.Assertions) == 0 {
+		return errors.New("evals: suite needs name, alias, dataset and assertions")
+	}
+	for _, a := range s.Assertions {
+		switch a.Type {
+		case AssertContains, AssertJSON:
+		case AssertJudge:
+			if a.MinScore <= 0 || a.MinScore > 1 {
+				return fmt.Errorf("evals: judge assertion needs min_score in (0, 1]")
+			}
+		default:
+			return fmt.Errorf("evals: unknown assertion type %q", a.Type)
+		}
+	}
+	r.mu.Lock()
+	r.suites[s.Name] = s
+	r.mu.Unlock()
+	return nil
+}
+
+// Suites lists registered suites.
+func (r *Runner) Suites() []Suite {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Suite, 0, len(r.suites))
+	for _, s := range r.suites {
+		out = append(out, s)
+	}
+	return out
+}
+
+// Run executes one suite by name and records the result.
+func (r *Runner) Run(ctx context.Context, name string) (*Result, error) {
+	r.mu.Lock()
+	s, ok := r.suites[name]
+	r.mu.Unlock()
+	if !ok {
+		return nil, ErrSuiteNotFound
+	}
+
+	d, err := r.Datasets.Resolve(s.Dataset)
+	if err != nil {
+		return nil, err
+	}
+	records, err := r.Datasets.Records(d.Ref())
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	res := &Result{Suite: s.Name, Alias: s.Alias, Dataset: d.Ref(), StartedAt: start}
+	for _, rec := range records {
+		cr := r.runCase(ctx, s, rec)
+		if cr.Pass {
+			res.Passed++
+		} else {
+			res.Failed++
+		}
+		res.Cases = append(res.Cases, cr)
+	}
+	res.Pass = res.Failed == 0
+	res.Duration = time.Since(start).String()
+
+	r.mu.Lock()
+	r.history[s.Name] = res
+	r.mu.Unlock()
+	return res, nil
+}
+
+// RunForAlias runs every suite bound to an alias, for routing-change
+// hooks that revalidate before promoting new config.
+func (r *Runner) RunForAlias(ctx context.Context, alias string) ([]*Result, error) {
+	r.mu.Lock()
+	var names []string
+	for name, s := range r.suites {
+		if s.Alias == alias {
+			names = append(names, name)
+		}
+	}
+	r.mu.Unlock()
+	var out []*Result
+	for _, name := range names {
+		res, err := r.Run(ctx, name)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+// Gate reports whether an alias is clear for automated rollout: every
+// suite bound to it must have a passing latest run. Suites that have
+// never run do not block.
+func (r *Runner) Gate(alias string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, s := range r.suites {
+		if s.Alias != alias {
+			continue
+		}
+		if res, ok := r.history[name]; ok && !res.Pass {
+			return fmt.Errorf("%w: suite %q failed %d/%d cases", ErrRegressed, name, res.Failed, res.Passed+res.Failed)
+		}
+	}
+	return nil
+}
+
+// LastResult returns the latest run of a suite, if any.
+func (r *Runner) LastResult(name string) (*Result, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, ok := r.history[name]
+	return res, ok
+}
+
+func (r *Runner) runCase(ctx context.Context, s Suite, rec datasets.Record) CaseResult {
+	cr := CaseResult{Prompt: rec.Prompt}
+	resp, err := r.Complete(ctx, s.Alias, providers.ChatRequest{
+		Messages: []providers.Message{{Role: "user", Content: rec.Prompt}},
+	})
+	if err != nil {
+		cr.Reason = "completion failed: " + err.Error()
+		return cr
+	}
+	cr.Completion = resp.Content
+
+	for _, a := range s.Assertions {
+		switch a.Type {
+		case AssertContains:
+			want := a.Value
+			if want == "" {
+				want = rec.Expected
+			}
+			if !strings.Contains(strings.ToLower(cr.Completion), strings.ToLower(want)) {
+				cr.Reason = fmt.Sprintf("missing %q", want)
+				return cr
+			}
+		case AssertJSON:
+			if !json.Valid([]byte(cr.Completion)) {
+				cr.Reason = "not valid JSON"
+				return cr
+			}
+		case AssertJudge:
+			if r.Judge == nil {
+				cr.Reason = "judge not configured"
+				return cr
+			}
+			score, err := r.Judge(ctx, rec.Prompt, rec.Expected, cr.Completion)
+			if err != nil {
+				cr.Reason = "judge failed: " + err.Error()
+				return cr
+			}
+			if score < a.MinScore {
+				cr.Reason = fmt.Sprintf("judge score %.2f < %.2f", score, a.MinScore)
+				return cr
+			}
+		}
+	}
+	cr.Pass = true
+	return cr
+}
@@ -0,0 +1,112 @@
+package evals
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/datasets"
+	"github.com/biodoia/goleapifree/internal/providers"
+	"github.com/biodoia/goleapifree/internal/storage"
+)
+
+func newRunner(t *testing.T, complete func(prompt string) string) *Runner {
+	t.Helper()
+	a, err := storage.NewFileArchive(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, _ := datasets.NewStore(a)
+	if _, err := store.Upload("qa", strings.NewReader(
+		`{"prompt":"2+2?","expected":"4"}`+"\n"+`{"prompt":"capital of France?","expected":"Paris"}`+"\n",
+	)); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRunner(store)
+	r.Complete = func(_ context.Context, alias string, req providers.ChatRequest) (*providers.ChatResponse, error) {
+		return &providers.ChatResponse{Content: complete(req.Messages[0].Content)}, nil
+	}
+	return r
+}
+
+func TestContainsAssertionAndGate(t *testing.T) {
+	answers := map[string]string{"2+2?": "The answer is 4.", "capital of France?": "Paris, of course."}
+	r := newRunner(t, func(p string) string { return answers[p] })
+	suite := Suite{Name: "smoke", Alias: "default", Dataset: "qa",
+		Assertions: []Assertion{{Type: AssertContains}}}
+	if err := r.Register(suite); err != nil {
+		t.Fatal(err)
+	}
+
+	// A never-run suite does not block rollouts.
+	if err := r.Gate("default"); err != nil {
+		t.Fatalf("gate before run: %v", err)
+	}
+
+	res, err := r.Run(context.Background(), "smoke")
+	if err != nil || !res.Pass || res.Passed != 2 {
+		t.Fatalf("result = %+v, %v", res, err)
+	}
+	if res.Dataset != "qa@1" {
+		t.Fatalf("dataset pinned to %q", res.Dataset)
+	}
+	if err := r.Gate("default"); err != nil {
+		t.Fatalf("gate after pass: %v", err)
+	}
+
+	// Regress one answer: the gate must now block.
+	answers["2+2?"] = "five"
+	if res, _ := r.Run(context.Background(), "smoke"); res.Pass || res.Failed != 1 {
+		t.Fatalf("result = %+v", res)
+	}
+	if err := r.Gate("default"); !errors.Is(err, ErrRegressed) {
+		t.Fatalf("gate = %v, want ErrRegressed", err)
+	}
+}
+
+func TestJSONAndJudgeAssertions(t *testing.T) {
+	r := newRunner(t, func(string) string { return `{"ok":true}` })
+	r.Judge = func(_ context.Context, _, _, completion string) (float64, error) {
+		if strings.Contains(completion, "ok") {
+			return 0.9, nil
+		}
+		return 0.1, nil
+	}
+	suite := Suite{Name: "shape", Alias: "json-alias", Dataset: "qa", Assertions: []Assertion{
+		{Type: AssertJSON},
+		{Type: AssertJudge, MinScore: 0.8},
+	}}
+	if err := r.Register(suite); err != nil {
+		t.Fatal(err)
+	}
+	res, err := r.Run(context.Background(), "shape")
+	if err != nil || !res.Pass {
+		t.Fatalf("result = %+v, %v", res, err)
+	}
+
+	// Raise the bar past the judge's score: every case fails.
+	suite.Assertions[1].MinScore = 0.95
+	if err := r.Register(suite); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := r.Run(context.Background(), "shape"); res.Pass {
+		t.Fatalf("result = %+v", res)
+	}
+}
+
+func TestRegisterValidation(t *testing.T) {
+	r := NewRunner(nil)
+	if err := r.Register(Suite{Name: "x"}); err == nil {
+		t.Fatal("expected error for incomplete suite")
+	}
+	bad := Suite{Name: "x", Alias: "a", Dataset: "d",
+		Assertions: []Assertion{{Type: "nope"}}}
+	if err := r.Register(bad); err == nil {
+		t.Fatal("expected error for unknown assertion type")
+	}
+	if _, err := r.Run(context.Background(), "missing"); !errors.Is(err, ErrSuiteNotFound) {
+		t.Fatalf("err = %v, want ErrSuiteNotFound", err)
+	}
+}
@@ -0,0 +1,190 @@
+// Package executions implements the async execution API: heavy chain or
+// batch requests return an execution ID immediately, run on the job
+// queue, and complete via polling or a callback URL.
+package executions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/biodoia/goleapifree/internal/jobs"
+)
+
+// JobType is the queue job type execution work is enqueued under.
+const JobType = "execution"
+
+// Execution states. Transitions: queued -> running -> succeeded|failed,
+// and queued|running -> cancelled.
+const (
+	StateQueued    = "queued"
+	StateRunning   = "running"
+	StateSucceeded = "succeeded"
+	StateFailed    = "failed"
+	StateCancelled = "cancelled"
+)
+
+// Errors returned by the manager.
+var (
+	ErrNotFound      = errors.New("executions: not found")
+	ErrNotCancelable = errors.New("executions: already finished")
+)
+
+// Execution is one async request and its lifecycle.
+type Execution struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"` // "chain", "batch", ...
+	// Payload is the original request body, handed to the runner.
+	Payload json.RawMessage `json:"payload,omitempty"`
+	State   string          `json:"state"`
+	// Result holds the runner's output once succeeded.
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	// CallbackURL, when set, receives a POST with the finished
+	// execution instead of requiring the client to poll.
+	CallbackURL string    `json:"callback_url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	FinishedAt  time.Time `json:"finished_at,omitempty"`
+}
+
+// Runner executes one payload and returns the result.
+type Runner func(ctx context.Context, kind string, payload json.RawMessage) (json.RawMessage, error)
+
+// Manager owns execution state and bridges to the job queue.
+type Manager struct {
+	Queue  jobs.Queue
+	Run    Runner
+	Client *http.Client
+
+	mu         sync.Mutex
+	executions map[string]*Execution
+}
+
+// NewManager creates a manager enqueuing on q and executing with run.
+func NewManager(q jobs.Queue, run Runner) *Manager {
+	return &Manager{
+		Queue:      q,
+		Run:        run,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+		executions: make(map[string]*Execution),
+	}
+}
+
+// Submit records an execution and enqueues its job, returning the
+// immediately-pollable execution.
+func (m *Manager) Submit(ctx context.Context, kind string, payload json.RawMessage, callbackURL string) (*Execution, error) {
+	e := &Execution{
+		ID: uuid.NewString(), Kind: kind, Payload: payload,
+		State: StateQueued, CallbackURL: callbackURL, CreatedAt: time.Now(),
+	}
+	m.mu.Lock()
+	m.executions[e.ID] = e
+	m.mu.Unlock()
+
+	err := m.Queue.Enqueue(ctx, &jobs.Job{
+		ID: e.ID, Type: JobType, Payload: json.RawMessage(fmt.Sprintf("%q", e.ID)), MaxRetries: 1,
+	})
+	if err != nil {
+		m.mu.Lock()
+		delete(m.executions, e.ID)
+		m.mu.Unlock()
+		return nil, err
+	}
+	return e, nil
+}
+
+// Get returns an execution by ID, without the payload (poll responses
+// stay small).
+func (m *Manager) Get(id string) (*Execution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.executions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *e
+	copied.Payload = nil
+	return &copied, nil
+}
+
+// Cancel marks a queued or running execution cancelled. A running
+// runner observes it through its context-free state check on completion;
+// its result is then discarded.
+func (m *Manager) Cancel(id string) (*Execution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.executions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if e.State != StateQueued && e.State != StateRunning {
+		return nil, ErrNotCancelable
+	}
+	e.State = StateCancelled
+	e.FinishedAt = time.Now()
+	copied := *e
+	return &copied, nil
+}
+
+// Handler returns the jobs.Pool handler driving the state machine.
+func (m *Manager) Handler() jobs.Handler {
+	return func(ctx context.Context, job *jobs.Job) error {
+		var id string
+		if err := json.Unmarshal(job.Payload, &id); err != nil {
+			return err
+		}
+		m.mu.Lock()
+		e, ok := m.executions[id]
+		if !ok || e.State == StateCancelled {
+			m.mu.Unlock()
+			return nil // cancelled before it started: ack and drop
+		}
+		e.State = StateRunning
+		payload, kind := e.Payload, e.Kind
+		m.mu.Unlock()
+
+		result, runErr := m.Run(ctx, kind, payload)
+
+		m.mu.Lock()
+		if e.State == StateCancelled {
+			m.mu.Unlock()
+			return nil // cancelled mid-run: discard the result
+		}
+		if runErr != nil {
+			e.State = StateFailed
+			e.Error = runErr.Error()
+		} else {
+			e.State = StateSucceeded
+			e.Result = result
+		}
+		e.FinishedAt = time.Now()
+		finished := *e
+		m.mu.Unlock()
+
+		m.notify(&finished)
+		return nil
+	}
+}
+
+// notify POSTs the finished execution to its callback URL, if any.
+// Delivery is best-effort; clients can always poll.
+func (m *Manager) notify(e *Execution) {
+	if e.CallbackURL == "" {
+		return
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	resp, err := m.Client.Post(e.CallbackURL, "application/json", bytes.NewReader(body))
+	if err == nil {
+		resp.Body.Close()
+	}
+}
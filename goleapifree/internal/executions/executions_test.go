@@ -0,0 +1,109 @@
+package executions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/jobs"
+)
+
+// drain runs every queued job through the manager's handler.
+func drain(t *testing.T, q jobs.Queue, m *Manager) {
+	t.Helper()
+	h := m.Handler()
+	for {
+		job, err := q.Dequeue(context.Background(), time.Minute)
+		if errors.Is(err, jobs.ErrEmpty) {
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := h(context.Background(), job); err != nil {
+			t.Fatal(err)
+		}
+		if err := q.Ack(context.Background(), job.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSubmitRunPoll(t *testing.T) {
+	q := jobs.NewMemoryQueue()
+	m := NewManager(q, func(_ context.Context, kind string, payload json.RawMessage) (json.RawMessage, error) {
+		return json.RawMessage(`{"echo":` + string(payload) + `}`), nil
+	})
+
+	e, err := m.Submit(context.Background(), "chain", json.RawMessage(`{"x":1}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := m.Get(e.ID); got.State != StateQueued {
+		t.Fatalf("state = %s, want queued", got.State)
+	}
+
+	drain(t, q, m)
+	got, err := m.Get(e.ID)
+	if err != nil || got.State != StateSucceeded {
+		t.Fatalf("state = %+v, %v", got, err)
+	}
+	if string(got.Result) != `{"echo":{"x":1}}` {
+		t.Fatalf("result = %s", got.Result)
+	}
+}
+
+func TestFailureAndCancellation(t *testing.T) {
+	q := jobs.NewMemoryQueue()
+	m := NewManager(q, func(context.Context, string, json.RawMessage) (json.RawMessage, error) {
+		return nil, errors.New("boom")
+	})
+
+	failing, _ := m.Submit(context.Background(), "chain", json.RawMessage(`1`), "")
+	cancelled, _ := m.Submit(context.Background(), "chain", json.RawMessage(`2`), "")
+	if _, err := m.Cancel(cancelled.ID); err != nil {
+		t.Fatal(err)
+	}
+	drain(t, q, m)
+
+	if got, _ := m.Get(failing.ID); got.State != StateFailed || got.Error != "boom" {
+		t.Fatalf("failing = %+v", got)
+	}
+	// Cancelled before running: the runner result must not overwrite it.
+	if got, _ := m.Get(cancelled.ID); got.State != StateCancelled {
+		t.Fatalf("cancelled = %+v", got)
+	}
+	if _, err := m.Cancel(failing.ID); !errors.Is(err, ErrNotCancelable) {
+		t.Fatalf("err = %v, want ErrNotCancelable", err)
+	}
+}
+
+func TestCallbackDelivery(t *testing.T) {
+	received := make(chan Execution, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e Execution
+		json.NewDecoder(r.Body).Decode(&e)
+		received <- e
+	}))
+	defer srv.Close()
+
+	q := jobs.NewMemoryQueue()
+	m := NewManager(q, func(context.Context, string, json.RawMessage) (json.RawMessage, error) {
+		return json.RawMessage(`"done"`), nil
+	})
+	e, _ := m.Submit(context.Background(), "batch", json.RawMessage(`{}`), srv.URL)
+	drain(t, q, m)
+
+	select {
+	case got := <-received:
+		if got.ID != e.ID || got.State != StateSucceeded {
+			t.Fatalf("callback = %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("callback not delivered")
+	}
+}
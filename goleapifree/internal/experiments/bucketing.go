@@ -0,0 +1,119 @@
+//go:build !edge
+
+package experiments
+
+import (
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"os"
+	"sync"
+)
+
+// BucketStore persists variant assignments so a user keeps their
+// variant across gateway restarts and across every device tied to the
+// same user ID.
+type BucketStore interface {
+	// Load returns all persisted assignments.
+	Load() (map[string]string, error)
+	// Save persists one assignment.
+	Save(key, variant string) error
+}
+
+// Bucketing assigns users to experiment variants. New users hash onto a
+// variant; once assigned, the persisted record wins over the hash, so
+// changing an experiment's variant list never reshuffles existing users.
+type Bucketing struct {
+	store BucketStore
+
+	mu          sync.Mutex
+	assignments map[string]string // experimentID + "\x00" + user -> variant
+}
+
+// NewBucketing creates a bucketing layer over a store; a nil store
+// keeps assignments in memory only.
+func NewBucketing(store BucketStore) (*Bucketing, error) {
+	b := &Bucketing{store: store, assignments: make(map[string]string)}
+	if store != nil {
+		loaded, err := store.Load()
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range loaded {
+			b.assignments[k] = v
+		}
+	}
+	return b, nil
+}
+
+// Assign returns the user's variant for an experiment, creating and
+// persisting the assignment on first sight.
+func (b *Bucketing) Assign(e *Experiment, user string) (string, error) {
+	if len(e.Variants) == 0 {
+		return "", errors.New("experiments: no variants to assign")
+	}
+	key := e.ID + "\x00" + user
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if v, ok := b.assignments[key]; ok {
+		return v, nil
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	v := e.Variants[int(h.Sum32())%len(e.Variants)].Name
+	b.assignments[key] = v
+	if b.store != nil {
+		if err := b.store.Save(key, v); err != nil {
+			return "", err
+		}
+	}
+	return v, nil
+}
+
+// FileBucketStore persists assignments as a JSON file, the same way
+// the WebUI stores saved queries.
+type FileBucketStore struct {
+	path string
+
+	mu   sync.Mutex
+	data map[string]string
+}
+
+// NewFileBucketStore opens (or will create) the file at path.
+func NewFileBucketStore(path string) (*FileBucketStore, error) {
+	s := &FileBucketStore{path: path, data: make(map[string]string)}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Load implements BucketStore.
+func (s *FileBucketStore) Load() (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]string, len(s.data))
+	for k, v := range s.data {
+		out[k] = v
+	}
+	return out, nil
+}
+
+// Save implements BucketStore.
+func (s *FileBucketStore) Save(key, variant string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = variant
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, raw, 0o644)
+}
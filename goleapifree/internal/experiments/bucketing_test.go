@@ -0,0 +1,75 @@
+//go:build !edge
+
+package experiments
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func bucketingExperiment() *Experiment {
+	return &Experiment{ID: "exp1", Variants: []Variant{{Name: "control"}, {Name: "treatment"}}}
+}
+
+func TestAssignIsStableAndSplits(t *testing.T) {
+	b, err := NewBucketing(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := bucketingExperiment()
+
+	seen := map[string]int{}
+	for i := 0; i < 500; i++ {
+		user := fmt.Sprintf("user-%d", i)
+		v1, err := b.Assign(e, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v2, _ := b.Assign(e, user)
+		if v1 != v2 {
+			t.Fatalf("assignment for %s flapped", user)
+		}
+		seen[v1]++
+	}
+	if seen["control"] < 150 || seen["treatment"] < 150 {
+		t.Fatalf("split = %v", seen)
+	}
+}
+
+func TestAssignmentsSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "buckets.json")
+	store, err := NewFileBucketStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewBucketing(store)
+	e := bucketingExperiment()
+	original, err := b.Assign(e, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a restart with a changed variant list: the persisted
+	// assignment must win over any re-hash.
+	reopenedStore, err := NewFileBucketStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := NewBucketing(reopenedStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reordered := &Experiment{ID: "exp1", Variants: []Variant{{Name: "treatment"}, {Name: "control"}, {Name: "third"}}}
+	got, err := reopened.Assign(reordered, "alice")
+	if err != nil || got != original {
+		t.Fatalf("assignment = %q, %v, want %q", got, err, original)
+	}
+}
+
+func TestAssignRejectsEmptyVariantList(t *testing.T) {
+	b, _ := NewBucketing(nil)
+	if _, err := b.Assign(&Experiment{ID: "e"}, "alice"); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,181 @@
+//go:build !edge
+
+// Package experiments runs A/B experiments over routing and prompt
+// variants and evaluates their outcomes with a two-proportion z-test.
+package experiments
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status is an experiment's lifecycle state.
+type Status string
+
+// Experiment lifecycle states.
+const (
+	StatusDraft     Status = "draft"
+	StatusRunning   Status = "running"
+	StatusPaused    Status = "paused"
+	StatusStopped   Status = "stopped"
+	StatusRolledOut Status = "rolled_out"
+)
+
+// Variant is one arm of an experiment with its observed outcomes.
+type Variant struct {
+	Name        string `json:"name"`
+	Samples     int64  `json:"samples"`
+	Conversions int64  `json:"conversions"`
+}
+
+// Rate returns the variant's conversion rate.
+func (v Variant) Rate() float64 {
+	if v.Samples == 0 {
+		return 0
+	}
+	return float64(v.Conversions) / float64(v.Samples)
+}
+
+// Experiment is one A/B test. The first variant is the control.
+type Experiment struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Status    Status    `json:"status"`
+	Variants  []Variant `json:"variants"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PValue runs a two-proportion z-test of the best variant against the
+// control. It returns 1 when there is not enough data to test.
+func (e *Experiment) PValue() float64 {
+	if len(e.Variants) < 2 {
+		return 1
+	}
+	control := e.Variants[0]
+	best := e.bestVariant()
+	if control.Samples == 0 || best.Samples == 0 || best.Name == control.Name {
+		return 1
+	}
+	p1, p2 := control.Rate(), best.Rate()
+	n1, n2 := float64(control.Samples), float64(best.Samples)
+	pooled := (float64(control.Conversions) + float64(best.Conversions)) / (n1 + n2)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/n1 + 1/n2))
+	if se == 0 {
+		return 1
+	}
+	z := math.Abs(p2-p1) / se
+	// Two-tailed p-value from the normal CDF.
+	return 2 * (1 - 0.5*math.Erfc(-z/math.Sqrt2))
+}
+
+// RecommendedWinner names the variant to roll out, or "" while the result
+// is not significant at the 5% level.
+func (e *Experiment) RecommendedWinner() string {
+	if e.PValue() >= 0.05 {
+		return ""
+	}
+	return e.bestVariant().Name
+}
+
+func (e *Experiment) bestVariant() Variant {
+	best := e.Variants[0]
+	for _, v := range e.Variants[1:] {
+		if v.Rate() > best.Rate() {
+			best = v
+		}
+	}
+	return best
+}
+
+// ErrNotFound is returned for operations on unknown experiment IDs.
+var ErrNotFound = errors.New("experiments: not found")
+
+// Manager owns the experiment registry and lifecycle transitions.
+type Manager struct {
+	mu   sync.RWMutex
+	byID map[string]*Experiment
+}
+
+// NewManager returns an empty experiment registry.
+func NewManager() *Manager {
+	return &Manager{byID: make(map[string]*Experiment)}
+}
+
+// Create registers a new draft experiment.
+func (m *Manager) Create(id, name string, variants ...string) (*Experiment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.byID[id]; exists {
+		return nil, fmt.Errorf("experiments: %q already exists", id)
+	}
+	e := &Experiment{ID: id, Name: name, Status: StatusDraft, CreatedAt: time.Now()}
+	for _, v := range variants {
+		e.Variants = append(e.Variants, Variant{Name: v})
+	}
+	m.byID[id] = e
+	return e, nil
+}
+
+// List returns all experiments ordered by creation time.
+func (m *Manager) List() []*Experiment {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]*Experiment, 0, len(m.byID))
+	for _, e := range m.byID {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// Get returns one experiment by ID.
+func (m *Manager) Get(id string) (*Experiment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.byID[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return e, nil
+}
+
+// Record adds one observation to a variant.
+func (m *Manager) Record(id, variant string, converted bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.byID[id]
+	if !ok {
+		return ErrNotFound
+	}
+	for i := range e.Variants {
+		if e.Variants[i].Name == variant {
+			e.Variants[i].Samples++
+			if converted {
+				e.Variants[i].Conversions++
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("experiments: %q has no variant %q", id, variant)
+}
+
+// Start, Pause, Stop and Rollout transition an experiment's status.
+func (m *Manager) Start(id string) error   { return m.setStatus(id, StatusRunning) }
+func (m *Manager) Pause(id string) error   { return m.setStatus(id, StatusPaused) }
+func (m *Manager) Stop(id string) error    { return m.setStatus(id, StatusStopped) }
+func (m *Manager) Rollout(id string) error { return m.setStatus(id, StatusRolledOut) }
+
+func (m *Manager) setStatus(id string, s Status) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.byID[id]
+	if !ok {
+		return ErrNotFound
+	}
+	e.Status = s
+	return nil
+}
@@ -0,0 +1,49 @@
+//go:build !edge
+
+package experiments
+
+import "testing"
+
+func TestPValueSignificantDifference(t *testing.T) {
+	m := NewManager()
+	e, err := m.Create("exp1", "router-weights", "control", "aggressive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		_ = m.Record("exp1", "control", i%10 == 0)   // ~10%
+		_ = m.Record("exp1", "aggressive", i%4 == 0) // ~25%
+	}
+	if p := e.PValue(); p >= 0.05 {
+		t.Fatalf("p = %f, want < 0.05 for a large effect", p)
+	}
+	if w := e.RecommendedWinner(); w != "aggressive" {
+		t.Fatalf("winner = %q, want aggressive", w)
+	}
+}
+
+func TestNoWinnerWithoutData(t *testing.T) {
+	m := NewManager()
+	e, _ := m.Create("exp2", "empty", "a", "b")
+	if p := e.PValue(); p != 1 {
+		t.Fatalf("p = %f, want 1 with no data", p)
+	}
+	if w := e.RecommendedWinner(); w != "" {
+		t.Fatalf("winner = %q, want none", w)
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	m := NewManager()
+	_, _ = m.Create("exp3", "x", "a", "b")
+	if err := m.Start("exp3"); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := m.Get("exp3")
+	if e.Status != StatusRunning {
+		t.Fatalf("status = %s, want running", e.Status)
+	}
+	if err := m.Start("missing"); err != ErrNotFound {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
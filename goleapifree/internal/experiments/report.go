@@ -0,0 +1,111 @@
+//go:build !edge
+
+package experiments
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSV renders the experiment's variant statistics as CSV, with the
+// control in the first data row.
+func (e *Experiment) CSV() string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Write([]string{"variant", "samples", "conversions", "rate"})
+	for _, v := range e.Variants {
+		w.Write([]string{
+			v.Name,
+			strconv.FormatInt(v.Samples, 10),
+			strconv.FormatInt(v.Conversions, 10),
+			strconv.FormatFloat(v.Rate(), 'f', 4, 64),
+		})
+	}
+	w.Flush()
+	return b.String()
+}
+
+// Markdown renders a stakeholder-facing report: methodology, sample
+// sizes and the conclusion the z-test supports.
+func (e *Experiment) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Experiment report: %s\n\n", e.Name)
+	fmt.Fprintf(&b, "ID `%s`, status %s, created %s.\n\n", e.ID, e.Status, e.CreatedAt.Format(time.RFC3339))
+
+	b.WriteString("## Results\n\n")
+	b.WriteString("| Variant | Samples | Conversions | Rate |\n")
+	b.WriteString("|---------|---------|-------------|------|\n")
+	for i, v := range e.Variants {
+		name := v.Name
+		if i == 0 {
+			name += " (control)"
+		}
+		fmt.Fprintf(&b, "| %s | %d | %d | %.2f%% |\n", name, v.Samples, v.Conversions, v.Rate()*100)
+	}
+
+	b.WriteString("\n## Methodology\n\n")
+	b.WriteString("The best-performing variant is compared against the control with a ")
+	b.WriteString("two-proportion z-test (two-tailed); results are called significant ")
+	b.WriteString("at the 5% level.\n\n")
+
+	b.WriteString("## Conclusion\n\n")
+	p := e.PValue()
+	if winner := e.RecommendedWinner(); winner != "" {
+		fmt.Fprintf(&b, "**%s** outperforms the control (p = %.4f). Recommended for rollout.\n", winner, p)
+	} else {
+		fmt.Fprintf(&b, "No significant difference between variants yet (p = %.4f). ", p)
+		b.WriteString("Keep the experiment running or accept the control.\n")
+	}
+	return b.String()
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!doctype html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Experiment report: {{.Name}}</title></head>
+<body>
+<h1>Experiment report: {{.Name}}</h1>
+<p>ID <code>{{.ID}}</code>, status {{.Status}}.</p>
+<h2>Results</h2>
+<table>
+<tr><th scope="col">Variant</th><th scope="col">Samples</th><th scope="col">Conversions</th><th scope="col">Rate</th></tr>
+{{range $i, $v := .Variants}}<tr><td>{{$v.Name}}{{if eq $i 0}} (control){{end}}</td><td>{{$v.Samples}}</td><td>{{$v.Conversions}}</td><td>{{printf "%.2f%%" $v.RatePct}}</td></tr>
+{{end}}</table>
+<h2>Methodology</h2>
+<p>Two-proportion z-test of the best variant against the control, two-tailed, significance at the 5% level.</p>
+<h2>Conclusion</h2>
+<p>{{.Conclusion}}</p>
+</body>
+</html>
+`))
+
+// HTML renders the same report as a standalone HTML document.
+func (e *Experiment) HTML() (string, error) {
+	type variantView struct {
+		Name                 string
+		Samples, Conversions int64
+		RatePct              float64
+	}
+	data := struct {
+		ID, Name   string
+		Status     Status
+		Variants   []variantView
+		Conclusion string
+	}{ID: e.ID, Name: e.Name, Status: e.Status}
+	for _, v := range e.Variants {
+		data.Variants = append(data.Variants, variantView{v.Name, v.Samples, v.Conversions, v.Rate() * 100})
+	}
+	if winner := e.RecommendedWinner(); winner != "" {
+		data.Conclusion = fmt.Sprintf("%s outperforms the control (p = %.4f). Recommended for rollout.", winner, e.PValue())
+	} else {
+		data.Conclusion = fmt.Sprintf("No significant difference between variants yet (p = %.4f).", e.PValue())
+	}
+	var b strings.Builder
+	if err := reportTemplate.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,61 @@
+//go:build !edge
+
+package experiments
+
+import (
+	"strings"
+	"testing"
+)
+
+func reportExperiment() *Experiment {
+	return &Experiment{
+		ID: "exp1", Name: "prompt rewrite", Status: StatusRunning,
+		Variants: []Variant{
+			{Name: "control", Samples: 1000, Conversions: 100},
+			{Name: "rewrite", Samples: 1000, Conversions: 160},
+		},
+	}
+}
+
+func TestCSVExport(t *testing.T) {
+	got := reportExperiment().CSV()
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 3 || lines[0] != "variant,samples,conversions,rate" {
+		t.Fatalf("csv = %q", got)
+	}
+	if lines[1] != "control,1000,100,0.1000" || lines[2] != "rewrite,1000,160,0.1600" {
+		t.Fatalf("csv rows = %q", lines[1:])
+	}
+}
+
+func TestMarkdownReportStatesConclusion(t *testing.T) {
+	e := reportExperiment()
+	md := e.Markdown()
+	for _, want := range []string{"# Experiment report: prompt rewrite", "control (control)", "two-proportion z-test", "**rewrite** outperforms the control"} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	// Without significance the report must not recommend a winner.
+	e.Variants[1].Conversions = 101
+	md = e.Markdown()
+	if !strings.Contains(md, "No significant difference") {
+		t.Fatalf("markdown = %s", md)
+	}
+}
+
+func TestHTMLReportEscapesAndRenders(t *testing.T) {
+	e := reportExperiment()
+	e.Name = "a <b> test"
+	html, err := e.HTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, "a &lt;b&gt; test") {
+		t.Fatalf("name not escaped:\n%s", html)
+	}
+	if !strings.Contains(html, "<td>rewrite</td>") || !strings.Contains(html, "Recommended for rollout") {
+		t.Fatalf("html = %s", html)
+	}
+}
@@ -0,0 +1,142 @@
+// Package export renders recorded work — playground sessions and agent
+// chain runs — as shareable documents: Markdown for docs and chat, and
+// Jupyter-style notebooks (.ipynb) for reproducible experiments.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/agents"
+	"github.com/biodoia/goleapifree/internal/playground"
+)
+
+// notebook is the minimal nbformat 4 document shape.
+type notebook struct {
+	Cells         []cell         `json:"cells"`
+	Metadata      map[string]any `json:"metadata"`
+	NBFormat      int            `json:"nbformat"`
+	NBFormatMinor int            `json:"nbformat_minor"`
+}
+
+type cell struct {
+	CellType string         `json:"cell_type"`
+	Metadata map[string]any `json:"metadata"`
+	Source   []string       `json:"source"`
+	// Outputs and ExecutionCount only apply to code cells.
+	Outputs        []output `json:"outputs,omitempty"`
+	ExecutionCount *int     `json:"execution_count,omitempty"`
+}
+
+type output struct {
+	OutputType string              `json:"output_type"`
+	Data       map[string][]string `json:"data"`
+	Metadata   map[string]any      `json:"metadata"`
+}
+
+// SessionMarkdown renders a playground session as Markdown.
+func SessionMarkdown(s *playground.Session) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Playground session: %s\n\n", s.Name)
+	fmt.Fprintf(&b, "Recorded %s, %d exchanges.\n\n", s.CreatedAt.Format(time.RFC3339), len(s.Entries))
+	for i, e := range s.Entries {
+		fmt.Fprintf(&b, "## Exchange %d\n\n", i+1)
+		fmt.Fprintf(&b, "**Model:** %s", e.Params.Model)
+		if e.ProviderID != "" {
+			fmt.Fprintf(&b, " (routed to %s/%s)", e.ProviderID, e.ResolvedModel)
+		}
+		b.WriteString("\n\n")
+		fmt.Fprintf(&b, "**Prompt:**\n\n```\n%s\n```\n\n", e.Prompt)
+		fmt.Fprintf(&b, "**Response:**\n\n%s\n\n", e.Response)
+	}
+	return b.String()
+}
+
+// SessionNotebook renders a playground session as an .ipynb document:
+// prompts become code cells with the response attached as output, so
+// the notebook replays naturally in prompt-engineering tooling.
+func SessionNotebook(s *playground.Session) ([]byte, error) {
+	nb := newNotebook(fmt.Sprintf("Playground session: %s", s.Name))
+	for i, e := range s.Entries {
+		meta := map[string]any{
+			"model":       e.Params.Model,
+			"provider_id": e.ProviderID,
+			"at":          e.At.Format(time.RFC3339),
+		}
+		count := i + 1
+		nb.Cells = append(nb.Cells, cell{
+			CellType:       "code",
+			Metadata:       meta,
+			Source:         sourceLines(e.Prompt),
+			ExecutionCount: &count,
+			Outputs: []output{{
+				OutputType: "execute_result",
+				Data:       map[string][]string{"text/plain": sourceLines(e.Response)},
+				Metadata:   map[string]any{},
+			}},
+		})
+	}
+	return json.MarshalIndent(nb, "", " ")
+}
+
+// RunMarkdown renders an agent chain run as Markdown, including the
+// per-step cost table.
+func RunMarkdown(r *agents.ChainRun) string {
+	tokens, cost := r.Totals()
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Chain run %s (%s)\n\n", r.ID, r.Agent)
+	status := "succeeded"
+	if r.Failed {
+		status = "failed"
+	}
+	fmt.Fprintf(&b, "Started %s, %s. Total: %d tokens, $%.4f.\n\n",
+		r.StartedAt.Format(time.RFC3339), status, tokens, cost)
+	b.WriteString("| Step | Provider | Model | Tokens | Cost | Latency | Retries |\n")
+	b.WriteString("|------|----------|-------|--------|------|---------|--------|\n")
+	for _, s := range r.Steps {
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | $%.4f | %.0fms | %d |\n",
+			s.Name, s.Provider, s.Model, s.Tokens, s.Cost, s.LatencyMs, s.Retries)
+	}
+	return b.String()
+}
+
+// RunNotebook renders a chain run as an .ipynb document with one
+// markdown cell per step carrying its telemetry.
+func RunNotebook(r *agents.ChainRun) ([]byte, error) {
+	nb := newNotebook(fmt.Sprintf("Chain run %s (%s)", r.ID, r.Agent))
+	for _, s := range r.Steps {
+		body := fmt.Sprintf("## %s\n\nprovider: %s, model: %s, tokens: %d, cost: $%.4f, latency: %.0fms",
+			s.Name, s.Provider, s.Model, s.Tokens, s.Cost, s.LatencyMs)
+		nb.Cells = append(nb.Cells, cell{
+			CellType: "markdown",
+			Metadata: map[string]any{"started_at": s.StartedAt.Format(time.RFC3339)},
+			Source:   sourceLines(body),
+		})
+	}
+	return json.MarshalIndent(nb, "", " ")
+}
+
+func newNotebook(title string) *notebook {
+	return &notebook{
+		Cells: []cell{{
+			CellType: "markdown",
+			Metadata: map[string]any{},
+			Source:   sourceLines("# " + title),
+		}},
+		Metadata:      map[string]any{"generator": "goleapai"},
+		NBFormat:      4,
+		NBFormatMinor: 5,
+	}
+}
+
+// sourceLines splits text the way nbformat expects: one array element
+// per line, newlines preserved on all but the last.
+func sourceLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
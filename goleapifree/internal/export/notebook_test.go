@@ -0,0 +1,95 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/agents"
+	"github.com/biodoia/goleapifree/internal/playground"
+)
+
+func testSession() *playground.Session {
+	return &playground.Session{
+		ID: "s1", Name: "tuning", CreatedAt: time.Unix(1_700_000_000, 0).UTC(),
+		Entries: []playground.Entry{{
+			Prompt:        "summarize this",
+			Params:        playground.Params{Model: "default"},
+			ProviderID:    "p1",
+			ResolvedModel: "gpt-4o-mini",
+			Response:      "a summary\nwith two lines",
+		}},
+	}
+}
+
+func TestSessionMarkdown(t *testing.T) {
+	md := SessionMarkdown(testSession())
+	for _, want := range []string{"# Playground session: tuning", "routed to p1/gpt-4o-mini", "summarize this", "a summary"} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestSessionNotebookIsValidNBFormat(t *testing.T) {
+	data, err := SessionNotebook(testSession())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nb struct {
+		NBFormat int `json:"nbformat"`
+		Cells    []struct {
+			CellType string   `json:"cell_type"`
+			Source   []string `json:"source"`
+			Outputs  []struct {
+				Data map[string][]string `json:"data"`
+			} `json:"outputs"`
+		} `json:"cells"`
+	}
+	if err := json.Unmarshal(data, &nb); err != nil {
+		t.Fatal(err)
+	}
+	if nb.NBFormat != 4 || len(nb.Cells) != 2 {
+		t.Fatalf("notebook = %+v", nb)
+	}
+	code := nb.Cells[1]
+	if code.CellType != "code" || code.Source[0] != "summarize this" {
+		t.Fatalf("cell = %+v", code)
+	}
+	// Multi-line output keeps nbformat's line conventions.
+	text := code.Outputs[0].Data["text/plain"]
+	if len(text) != 2 || text[0] != "a summary\n" || text[1] != "with two lines" {
+		t.Fatalf("output lines = %q", text)
+	}
+}
+
+func TestRunExports(t *testing.T) {
+	run := &agents.ChainRun{
+		ID: "r1", Agent: "translate", StartedAt: time.Unix(1_700_000_000, 0).UTC(),
+		Steps: []agents.Step{
+			{Name: "draft", Provider: "p1", Model: "m1", Tokens: 100, Cost: 0.01, LatencyMs: 200},
+			{Name: "refine", Provider: "p2", Model: "m2", Tokens: 50, Cost: 0.02, LatencyMs: 300, Retries: 1},
+		},
+	}
+	md := RunMarkdown(run)
+	for _, want := range []string{"Chain run r1 (translate)", "150 tokens, $0.0300", "| draft | p1 |", "| refine | p2 |"} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	data, err := RunNotebook(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nb struct {
+		Cells []json.RawMessage `json:"cells"`
+	}
+	if err := json.Unmarshal(data, &nb); err != nil {
+		t.Fatal(err)
+	}
+	if len(nb.Cells) != 3 { // title + one per step
+		t.Fatalf("cells = %d", len(nb.Cells))
+	}
+}
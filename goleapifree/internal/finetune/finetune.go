@@ -0,0 +1,169 @@
+// Package finetune proxies OpenAI-compatible fine-tuning jobs to
+// providers that support them, tracks job status locally, and registers
+// finished models into the routing table under a fresh alias.
+package finetune
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/biodoia/goleapifree/internal/datasets"
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/router"
+)
+
+// Job statuses, mirroring the OpenAI job lifecycle.
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// ErrNotFound is returned for unknown job IDs.
+var ErrNotFound = errors.New("finetune: job not found")
+
+// Job is one tracked fine-tuning job.
+type Job struct {
+	// ID is the gateway's job ID; ProviderJobID is the upstream one.
+	ID            string `json:"id"`
+	ProviderJobID string `json:"provider_job_id"`
+	ProviderID    string `json:"provider_id"`
+	BaseModel     string `json:"model"`
+	TrainingFile  string `json:"training_file"`
+	Status        string `json:"status"`
+	// FineTunedModel is set once the job succeeds.
+	FineTunedModel string `json:"fine_tuned_model,omitempty"`
+	// Alias is the routing alias the finished model was registered as.
+	Alias     string    `json:"alias,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Upstream is the provider-side fine-tuning API. The live implementation
+// speaks the OpenAI fine_tuning endpoints; tests inject fakes.
+type Upstream interface {
+	// CreateJob starts a job and returns the provider's job ID.
+	CreateJob(ctx context.Context, baseModel, trainingFile string) (string, error)
+	// GetJob returns the provider's status and, when finished, the
+	// fine-tuned model ID.
+	GetJob(ctx context.Context, providerJobID string) (status, fineTunedModel, errMsg string, err error)
+}
+
+// Manager tracks jobs across providers.
+type Manager struct {
+	Router *router.Router
+	// Upstreams maps provider IDs to their fine-tuning API.
+	Upstreams map[string]Upstream
+	// Datasets, when set, lets training_file reference an uploaded
+	// dataset as "dataset:name@version"; the ref is validated and
+	// pinned to its resolved version before forwarding.
+	Datasets *datasets.Store
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// NewManager creates an empty manager.
+func NewManager(r *router.Router) *Manager {
+	return &Manager{Router: r, Upstreams: make(map[string]Upstream), jobs: make(map[string]*Job)}
+}
+
+// Create forwards a job to the provider and starts tracking it.
+func (m *Manager) Create(ctx context.Context, providerID, baseModel, trainingFile string) (*Job, error) {
+	up, ok := m.Upstreams[providerID]
+	if !ok {
+		return nil, errors.New("finetune: provider does not support fine-tuning")
+	}
+	if ref, ok := strings.CutPrefix(trainingFile, "dataset:"); ok {
+		if m.Datasets == nil {
+			return nil, errors.New("finetune: dataset store not configured")
+		}
+		d, err := m.Datasets.Resolve(ref)
+		if err != nil {
+			return nil, err
+		}
+		trainingFile = "dataset:" + d.Ref()
+	}
+	providerJobID, err := up.CreateJob(ctx, baseModel, trainingFile)
+	if err != nil {
+		return nil, err
+	}
+	j := &Job{
+		ID: "ftjob-" + uuid.NewString(), ProviderJobID: providerJobID,
+		ProviderID: providerID, BaseModel: baseModel, TrainingFile: trainingFile,
+		Status: StatusQueued, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	m.mu.Lock()
+	m.jobs[j.ID] = j
+	m.mu.Unlock()
+	return j, nil
+}
+
+// Get refreshes one job from the provider and returns it. A job that
+// just succeeded gets its model registered under a routing alias.
+func (m *Manager) Get(ctx context.Context, id string) (*Job, error) {
+	m.mu.Lock()
+	j, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if j.Status == StatusSucceeded || j.Status == StatusFailed || j.Status == StatusCancelled {
+		copied := *j
+		return &copied, nil
+	}
+
+	up := m.Upstreams[j.ProviderID]
+	status, model, errMsg, err := up.GetJob(ctx, j.ProviderJobID)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	j.Status = status
+	j.Error = errMsg
+	j.UpdatedAt = time.Now()
+	if status == StatusSucceeded && model != "" && j.FineTunedModel == "" {
+		j.FineTunedModel = model
+		j.Alias = "ft:" + j.BaseModel + ":" + j.ID[len("ftjob-"):len("ftjob-")+8]
+		m.registerLocked(j)
+	}
+	copied := *j
+	m.mu.Unlock()
+	return &copied, nil
+}
+
+// registerLocked routes the fine-tuned model under its alias. Caller
+// holds m.mu.
+func (m *Manager) registerLocked(j *Job) {
+	if m.Router == nil {
+		return
+	}
+	m.Router.SetAlias(&models.ModelAlias{
+		Alias: j.Alias,
+		Targets: []models.AliasTarget{
+			{ProviderID: j.ProviderID, Model: j.FineTunedModel, Weight: 1},
+		},
+	})
+}
+
+// List returns all tracked jobs, newest first.
+func (m *Manager) List() []*Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]*Job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		copied := *j
+		out = append(out, &copied)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
@@ -0,0 +1,74 @@
+package finetune
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/router"
+)
+
+// fakeUpstream scripts the provider-side job lifecycle.
+type fakeUpstream struct {
+	status string
+	model  string
+}
+
+func (f *fakeUpstream) CreateJob(context.Context, string, string) (string, error) {
+	return "prov-123", nil
+}
+
+func (f *fakeUpstream) GetJob(context.Context, string) (string, string, string, error) {
+	return f.status, f.model, "", nil
+}
+
+func TestFinishedJobRegistersAlias(t *testing.T) {
+	r := router.New()
+	r.AddProvider(&models.Provider{ID: "p1", Enabled: true})
+	up := &fakeUpstream{status: StatusRunning}
+	m := NewManager(r)
+	m.Upstreams["p1"] = up
+
+	j, err := m.Create(context.Background(), "p1", "gpt-4o-mini", "file-abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := m.Get(context.Background(), j.ID)
+	if err != nil || got.Status != StatusRunning {
+		t.Fatalf("job = %+v, %v", got, err)
+	}
+
+	up.status, up.model = StatusSucceeded, "ft:gpt-4o-mini:custom"
+	got, err = m.Get(context.Background(), j.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.FineTunedModel != "ft:gpt-4o-mini:custom" || got.Alias == "" {
+		t.Fatalf("job = %+v", got)
+	}
+	if !strings.HasPrefix(got.Alias, "ft:gpt-4o-mini:") {
+		t.Fatalf("alias = %q", got.Alias)
+	}
+	// The alias must now route to the fine-tuned model.
+	cands := r.Candidates(got.Alias)
+	if len(cands) != 0 {
+		t.Fatalf("candidates without accounts = %+v", cands)
+	}
+	r.AddAccount(&models.Account{ID: "a1", ProviderID: "p1", Enabled: true})
+	cands = r.Candidates(got.Alias)
+	if len(cands) != 1 || cands[0].Model != "ft:gpt-4o-mini:custom" {
+		t.Fatalf("candidates = %+v", cands)
+	}
+}
+
+func TestCreateRejectsUnsupportedProvider(t *testing.T) {
+	m := NewManager(router.New())
+	if _, err := m.Create(context.Background(), "nope", "m", "f"); err == nil {
+		t.Fatal("expected error for provider without fine-tuning support")
+	}
+	if _, err := m.Get(context.Background(), "ftjob-missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
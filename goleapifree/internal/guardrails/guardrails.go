@@ -0,0 +1,176 @@
+// Package guardrails evaluates declarative validation rules against
+// prompts and completions. Rules live in YAML, are scoped to model
+// aliases and tenants, and can either log or block on violation.
+package guardrails
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Phase says which side of the request a rule inspects.
+type Phase string
+
+// Rule phases.
+const (
+	PhaseInput  Phase = "input"
+	PhaseOutput Phase = "output"
+)
+
+// Rule is one declarative check.
+type Rule struct {
+	Name string `yaml:"name"`
+	// Phase defaults to input for prompt rules; required_disclaimer and
+	// assert_pattern default to output.
+	Phase Phase `yaml:"phase,omitempty"`
+	// Block makes a violation reject the request instead of only logging.
+	Block bool `yaml:"block,omitempty"`
+	// Aliases / Tenants scope the rule; empty means all.
+	Aliases []string `yaml:"aliases,omitempty"`
+	Tenants []string `yaml:"tenants,omitempty"`
+
+	// Exactly one of the following checks is set.
+	MaxPromptLength    int      `yaml:"max_prompt_length,omitempty"`
+	BannedTopics       []string `yaml:"banned_topics,omitempty"`
+	RequiredDisclaimer string   `yaml:"required_disclaimer,omitempty"`
+	// AssertPattern must match the text; DenyPattern must not.
+	AssertPattern string `yaml:"assert_pattern,omitempty"`
+	DenyPattern   string `yaml:"deny_pattern,omitempty"`
+
+	assertRe *regexp.Regexp
+	denyRe   *regexp.Regexp
+}
+
+// Violation records one failed rule evaluation.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Phase   Phase  `json:"phase"`
+	Block   bool   `json:"block"`
+	Message string `json:"message"`
+}
+
+// RuleSet is a compiled set of rules ready for evaluation.
+type RuleSet struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Parse loads and compiles a YAML rule document.
+func Parse(data []byte) (*RuleSet, error) {
+	var rs RuleSet
+	if err := yaml.Unmarshal(data, &rs); err != nil {
+		return nil, fmt.Errorf("guardrails: parsing rules: %w", err)
+	}
+	for i := range rs.Rules {
+		if err := rs.Rules[i].compile(); err != nil {
+			return nil, err
+		}
+	}
+	return &rs, nil
+}
+
+// compile validates the rule and pre-compiles its patterns.
+func (r *Rule) compile() error {
+	if r.Name == "" {
+		return fmt.Errorf("guardrails: rule without a name")
+	}
+	if r.Phase == "" {
+		if r.RequiredDisclaimer != "" || r.AssertPattern != "" {
+			r.Phase = PhaseOutput
+		} else {
+			r.Phase = PhaseInput
+		}
+	}
+	var err error
+	if r.AssertPattern != "" {
+		if r.assertRe, err = regexp.Compile(r.AssertPattern); err != nil {
+			return fmt.Errorf("guardrails: rule %q assert_pattern: %w", r.Name, err)
+		}
+	}
+	if r.DenyPattern != "" {
+		if r.denyRe, err = regexp.Compile(r.DenyPattern); err != nil {
+			return fmt.Errorf("guardrails: rule %q deny_pattern: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// applies reports whether the rule is in scope for the alias and tenant.
+func (r *Rule) applies(alias, tenant string) bool {
+	return (len(r.Aliases) == 0 || contains(r.Aliases, alias)) &&
+		(len(r.Tenants) == 0 || contains(r.Tenants, tenant))
+}
+
+// Evaluate runs all in-scope rules of the given phase over text and
+// returns the violations, blocking ones first.
+func (rs *RuleSet) Evaluate(phase Phase, alias, tenant, text string) []Violation {
+	var out []Violation
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if r.Phase != phase || !r.applies(alias, tenant) {
+			continue
+		}
+		if msg := r.check(text); msg != "" {
+			out = append(out, Violation{Rule: r.Name, Phase: phase, Block: r.Block, Message: msg})
+		}
+	}
+	// Blocking violations first so callers can fail fast on the first entry.
+	for i, v := range out {
+		if v.Block {
+			out[0], out[i] = out[i], out[0]
+			break
+		}
+	}
+	return out
+}
+
+// ShouldBlock reports whether any violation is blocking.
+func ShouldBlock(violations []Violation) bool {
+	for _, v := range violations {
+		if v.Block {
+			return true
+		}
+	}
+	return false
+}
+
+// check runs the rule's single configured predicate.
+func (r *Rule) check(text string) string {
+	switch {
+	case r.MaxPromptLength > 0:
+		if len(text) > r.MaxPromptLength {
+			return fmt.Sprintf("prompt length %d exceeds limit %d", len(text), r.MaxPromptLength)
+		}
+	case len(r.BannedTopics) > 0:
+		lower := strings.ToLower(text)
+		for _, topic := range r.BannedTopics {
+			if strings.Contains(lower, strings.ToLower(topic)) {
+				return fmt.Sprintf("banned topic %q mentioned", topic)
+			}
+		}
+	case r.RequiredDisclaimer != "":
+		if !strings.Contains(text, r.RequiredDisclaimer) {
+			return fmt.Sprintf("required disclaimer %q missing", r.RequiredDisclaimer)
+		}
+	case r.assertRe != nil:
+		if !r.assertRe.MatchString(text) {
+			return fmt.Sprintf("output does not match required pattern %q", r.AssertPattern)
+		}
+	case r.denyRe != nil:
+		if m := r.denyRe.FindString(text); m != "" {
+			return fmt.Sprintf("output matches denied pattern %q: %q", r.DenyPattern, m)
+		}
+	}
+	return ""
+}
+
+func contains(xs []string, x string) bool {
+	for _, v := range xs {
+		if v == x {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package guardrails
+
+import "testing"
+
+const rulesYAML = `
+rules:
+  - name: prompt-length
+    max_prompt_length: 40
+    block: true
+  - name: no-crypto-talk
+    banned_topics: ["crypto pump"]
+    tenants: ["acme"]
+  - name: medical-disclaimer
+    required_disclaimer: "Not medical advice."
+    aliases: ["med-assist"]
+    block: true
+  - name: no-api-keys
+    phase: output
+    deny_pattern: "sk-[a-zA-Z0-9]{20,}"
+    block: true
+`
+
+func TestEvaluateScoping(t *testing.T) {
+	rs, err := Parse([]byte(rulesYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Banned topic only applies to tenant acme.
+	if v := rs.Evaluate(PhaseInput, "", "other", "buy this crypto pump now"); len(v) != 0 {
+		t.Fatalf("rule leaked outside its tenant scope: %+v", v)
+	}
+	v := rs.Evaluate(PhaseInput, "", "acme", "buy this crypto pump now")
+	if len(v) != 1 || v[0].Rule != "no-crypto-talk" || v[0].Block {
+		t.Fatalf("violations = %+v", v)
+	}
+}
+
+func TestEvaluateBlocking(t *testing.T) {
+	rs, err := Parse([]byte(rulesYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := rs.Evaluate(PhaseInput, "", "", "this prompt is definitely comfortably longer than the forty character limit")
+	if !ShouldBlock(v) {
+		t.Fatalf("length violation should block: %+v", v)
+	}
+
+	v = rs.Evaluate(PhaseOutput, "", "", "your key is sk-abcdefghijklmnopqrstuvwx")
+	if len(v) != 1 || v[0].Rule != "no-api-keys" || !v[0].Block {
+		t.Fatalf("output violations = %+v", v)
+	}
+
+	v = rs.Evaluate(PhaseOutput, "med-assist", "", "Take two aspirin. Not medical advice.")
+	if len(v) != 0 {
+		t.Fatalf("disclaimer present but still flagged: %+v", v)
+	}
+	v = rs.Evaluate(PhaseOutput, "med-assist", "", "Take two aspirin.")
+	if len(v) != 1 || v[0].Rule != "medical-disclaimer" {
+		t.Fatalf("missing disclaimer not flagged: %+v", v)
+	}
+}
+
+func TestParseRejectsBadPattern(t *testing.T) {
+	_, err := Parse([]byte("rules:\n  - name: bad\n    assert_pattern: '['\n"))
+	if err == nil {
+		t.Fatal("expected error for invalid regexp")
+	}
+}
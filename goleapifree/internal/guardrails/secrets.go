@@ -0,0 +1,101 @@
+package guardrails
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// SecretAction is what the proxy does when a prompt contains a secret.
+type SecretAction string
+
+// Secret actions. Block rejects the request, Strip redacts the secret
+// in place and forwards, Warn forwards untouched but flags the response
+// (X-Goleapai-Secrets-Detected) so callers can alert.
+const (
+	SecretBlock SecretAction = "block"
+	SecretStrip SecretAction = "strip"
+	SecretWarn  SecretAction = "warn"
+)
+
+// SecretFinding is one detected credential.
+type SecretFinding struct {
+	Kind string `json:"kind"`
+	// Preview shows the first few characters only; the full match is
+	// never logged or returned.
+	Preview string `json:"preview"`
+}
+
+type secretPattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// secretPatterns covers unambiguous, high-confidence credential shapes.
+// Deliberately no entropy heuristics here: false positives on a block
+// action would break legitimate traffic.
+var secretPatterns = []secretPattern{
+	{"aws-access-key-id", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"private-key", regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\b`)},
+	{"github-token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"slack-token", regexp.MustCompile(`\bxox[baprs]-[A-Za-z0-9-]{10,}\b`)},
+	{"openai-key", regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}\b`)},
+}
+
+// SecretScanner detects credentials in outbound prompts before they
+// reach third-party providers.
+type SecretScanner struct {
+	Action SecretAction
+}
+
+// NewSecretScanner creates a scanner; an empty action defaults to warn,
+// the least disruptive mode.
+func NewSecretScanner(action SecretAction) *SecretScanner {
+	if action == "" {
+		action = SecretWarn
+	}
+	return &SecretScanner{Action: action}
+}
+
+// Scan reports every secret found in text.
+func (s *SecretScanner) Scan(text string) []SecretFinding {
+	var out []SecretFinding
+	for _, p := range secretPatterns {
+		for _, m := range p.re.FindAllString(text, -1) {
+			out = append(out, SecretFinding{Kind: p.kind, Preview: preview(m)})
+		}
+	}
+	return out
+}
+
+// Apply runs the configured action: it returns the (possibly redacted)
+// text, the findings, and whether the request must be blocked.
+func (s *SecretScanner) Apply(text string) (string, []SecretFinding, bool) {
+	findings := s.Scan(text)
+	if len(findings) == 0 {
+		return text, nil, false
+	}
+	switch s.Action {
+	case SecretBlock:
+		return text, findings, true
+	case SecretStrip:
+		for _, p := range secretPatterns {
+			text = p.re.ReplaceAllStringFunc(text, func(string) string {
+				return fmt.Sprintf("[REDACTED:%s]", p.kind)
+			})
+		}
+		return text, findings, false
+	default: // SecretWarn
+		return text, findings, false
+	}
+}
+
+// preview keeps just enough of a match to identify which credential
+// leaked without reproducing it.
+func preview(match string) string {
+	const keep = 8
+	if len(match) <= keep {
+		return match
+	}
+	return match[:keep] + "..."
+}
@@ -0,0 +1,241 @@
+// Package harness is the end-to-end test kit: it spins up mock
+// OpenAI-compatible provider servers with scripted responses and
+// failures, wires them into a real router and stats collector, and
+// offers helpers to assert routing, failover and accounting outcomes.
+// Contributors adding a feature get realistic multi-provider tests
+// without standing up real upstreams.
+package harness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/router"
+	"github.com/biodoia/goleapifree/internal/stats"
+)
+
+// Step scripts one response from a mock provider. Steps are consumed in
+// order; the last step repeats once the script is exhausted.
+type Step struct {
+	// Status is the HTTP status to return; 0 means 200.
+	Status int
+	// Content is the assistant message for successful responses.
+	Content string
+	// Delay stalls the response, for timeout and latency scenarios.
+	Delay time.Duration
+	// Tokens is the total_tokens reported in usage; 0 defaults to 10.
+	Tokens int
+}
+
+// MockProvider is an httptest server speaking the OpenAI chat
+// completions shape, following its script step by step.
+type MockProvider struct {
+	Server *httptest.Server
+
+	mu       sync.Mutex
+	script   []Step
+	consumed int
+	requests []string // models seen, in order
+}
+
+// NewMockProvider starts a mock provider following steps. Close it when
+// done (Harness.Close does this for providers it created).
+func NewMockProvider(steps ...Step) *MockProvider {
+	if len(steps) == 0 {
+		steps = []Step{{Content: "ok"}}
+	}
+	m := &MockProvider{script: steps}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.handle))
+	return m
+}
+
+// Requests returns the models of every request the provider received.
+func (m *MockProvider) Requests() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]string, len(m.requests))
+	copy(out, m.requests)
+	return out
+}
+
+func (m *MockProvider) handle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Model string `json:"model"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	m.mu.Lock()
+	m.requests = append(m.requests, req.Model)
+	step := m.script[m.consumed]
+	if m.consumed < len(m.script)-1 {
+		m.consumed++
+	}
+	m.mu.Unlock()
+
+	if step.Delay > 0 {
+		time.Sleep(step.Delay)
+	}
+	if step.Status >= 400 {
+		w.WriteHeader(step.Status)
+		fmt.Fprintf(w, `{"error":{"message":"scripted failure"}}`)
+		return
+	}
+	tokens := step.Tokens
+	if tokens == 0 {
+		tokens = 10
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"model":   req.Model,
+		"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": step.Content}}},
+		"usage":   map[string]int{"total_tokens": tokens},
+	})
+}
+
+// Result is one completed harness request, for assertions.
+type Result struct {
+	ProviderID string
+	Content    string
+	Attempts   int
+}
+
+// Harness bundles a router, collector and the mock providers behind it.
+type Harness struct {
+	Router    *router.Router
+	Collector *stats.Collector
+	// MaxAttempts bounds failover retries per request; defaults to the
+	// number of registered providers.
+	MaxAttempts int
+
+	mu        sync.Mutex
+	providers map[string]*MockProvider
+}
+
+// New creates an empty harness. Register providers, then Chat.
+func New() *Harness {
+	return &Harness{
+		Router:    router.New(),
+		Collector: stats.NewCollector(stats.NewAnalyzer(24*time.Hour), nil),
+		providers: make(map[string]*MockProvider),
+	}
+}
+
+// AddProvider registers a mock provider with one account under id and
+// returns it for scripting assertions.
+func (h *Harness) AddProvider(id string, steps ...Step) *MockProvider {
+	m := NewMockProvider(steps...)
+	h.Router.AddProvider(&models.Provider{ID: id, Name: id, Enabled: true})
+	h.Router.AddAccount(&models.Account{ID: id + "-account", ProviderID: id, Enabled: true})
+	h.mu.Lock()
+	h.providers[id] = m
+	h.mu.Unlock()
+	return m
+}
+
+// SetAlias maps an alias to targets across registered providers, with
+// equal weights.
+func (h *Harness) SetAlias(alias string, providerIDs ...string) {
+	targets := make([]models.AliasTarget, 0, len(providerIDs))
+	for _, id := range providerIDs {
+		targets = append(targets, models.AliasTarget{ProviderID: id, Model: alias, Weight: 1})
+	}
+	h.Router.SetAlias(&models.ModelAlias{Alias: alias, Targets: targets})
+}
+
+// Chat routes one request through the router and sends it to the chosen
+// mock provider, failing over across candidates the way the gateway
+// does, and feeding outcomes into the collector. The returned Result
+// says which provider finally answered and how many attempts it took.
+func (h *Harness) Chat(ctx context.Context, model string) (*Result, error) {
+	max := h.MaxAttempts
+	if max <= 0 {
+		h.mu.Lock()
+		max = len(h.providers)
+		h.mu.Unlock()
+	}
+	failed := make(map[string]bool)
+	h.Router.Exclude = func(providerID string) bool { return failed[providerID] }
+	defer func() { h.Router.Exclude = nil }()
+
+	var lastErr error
+	for attempt := 1; attempt <= max; attempt++ {
+		c, err := h.Router.Route(model)
+		if err != nil {
+			if lastErr != nil {
+				return nil, fmt.Errorf("all candidates failed: %w", lastErr)
+			}
+			return nil, err
+		}
+		content, tokens, err := h.send(ctx, c.Provider.ID, c.Model)
+		h.Router.Release(c)
+		if err != nil {
+			h.Collector.Observe(stats.Sample{Provider: c.Provider.ID}, true)
+			failed[c.Provider.ID] = true
+			lastErr = err
+			continue
+		}
+		h.Collector.Observe(stats.Sample{Provider: c.Provider.ID, Tokens: int64(tokens)}, false)
+		return &Result{ProviderID: c.Provider.ID, Content: content, Attempts: attempt}, nil
+	}
+	return nil, fmt.Errorf("all candidates failed: %w", lastErr)
+}
+
+// send performs the OpenAI-shaped HTTP round-trip against one mock.
+func (h *Harness) send(ctx context.Context, providerID, model string) (content string, tokens int, err error) {
+	h.mu.Lock()
+	m, ok := h.providers[providerID]
+	h.mu.Unlock()
+	if !ok {
+		return "", 0, fmt.Errorf("harness: no mock for provider %q", providerID)
+	}
+	body, _ := json.Marshal(map[string]any{
+		"model":    model,
+		"messages": []map[string]string{{"role": "user", "content": "harness request"}},
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.Server.URL+"/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := m.Server.Client().Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("provider %s: %s", providerID, resp.Status)
+	}
+	var parsed struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			TotalTokens int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", 0, err
+	}
+	if len(parsed.Choices) == 0 {
+		return "", 0, fmt.Errorf("provider %s: empty choices", providerID)
+	}
+	return parsed.Choices[0].Message.Content, parsed.Usage.TotalTokens, nil
+}
+
+// Close shuts down every mock provider.
+func (h *Harness) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, m := range h.providers {
+		m.Server.Close()
+	}
+}
@@ -0,0 +1,84 @@
+package harness
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChatRoutesToScriptedProvider(t *testing.T) {
+	h := New()
+	defer h.Close()
+	h.AddProvider("p1", Step{Content: "hello from p1"})
+	h.SetAlias("fast", "p1")
+
+	res, err := h.Chat(context.Background(), "fast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ProviderID != "p1" || res.Content != "hello from p1" || res.Attempts != 1 {
+		t.Fatalf("result = %+v", res)
+	}
+	totals := h.Collector.Totals()
+	if totals.TotalRequests != 1 || totals.TotalErrors != 0 || totals.TotalTokens != 10 {
+		t.Fatalf("totals = %+v", totals)
+	}
+}
+
+func TestChatFailsOverOnScriptedFailure(t *testing.T) {
+	h := New()
+	defer h.Close()
+	broken := h.AddProvider("p1", Step{Status: 500})
+	h.AddProvider("p2", Step{Content: "rescued"})
+	h.SetAlias("fast", "p1", "p2")
+
+	res, err := h.Chat(context.Background(), "fast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ProviderID != "p2" || res.Content != "rescued" || res.Attempts != 2 {
+		t.Fatalf("result = %+v", res)
+	}
+	if got := broken.Requests(); len(got) != 1 || got[0] != "fast" {
+		t.Fatalf("p1 requests = %v", got)
+	}
+	totals := h.Collector.Totals()
+	if totals.TotalErrors != 1 || totals.TotalRequests != 2 {
+		t.Fatalf("totals = %+v", totals)
+	}
+}
+
+func TestChatReportsWhenEveryProviderFails(t *testing.T) {
+	h := New()
+	defer h.Close()
+	h.AddProvider("p1", Step{Status: 503})
+	h.AddProvider("p2", Step{Status: 502})
+	h.SetAlias("fast", "p1", "p2")
+
+	if _, err := h.Chat(context.Background(), "fast"); err == nil {
+		t.Fatal("expected error when all providers fail")
+	}
+	if totals := h.Collector.Totals(); totals.TotalErrors != 2 {
+		t.Fatalf("totals = %+v", totals)
+	}
+}
+
+func TestScriptAdvancesAndLastStepRepeats(t *testing.T) {
+	h := New()
+	defer h.Close()
+	h.AddProvider("p1", Step{Status: 500}, Step{Content: "recovered"})
+	h.SetAlias("fast", "p1")
+	h.MaxAttempts = 1
+
+	if _, err := h.Chat(context.Background(), "fast"); err == nil {
+		t.Fatal("first scripted step should fail")
+	}
+	for i := 0; i < 2; i++ {
+		res, err := h.Chat(context.Background(), "fast")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Content != "recovered" {
+			t.Fatalf("content = %q", res.Content)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package i18n provides message catalogs and locale resolution for the
+// TUI, WebUI and API error messages, so one deployment speaks one
+// consistent language instead of the historical Italian/English mix.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the fallback for unknown locales and missing keys.
+const DefaultLocale = "en"
+
+// Catalog maps message keys to translated format strings (fmt verbs).
+type Catalog map[string]string
+
+// builtin catalogs. Deployments can extend or override them with
+// RegisterCatalog (e.g. from a locales/ directory at startup).
+var builtin = map[string]Catalog{
+	"en": {
+		"status.healthy":        "healthy",
+		"status.degraded":       "degraded",
+		"status.down":           "down",
+		"error.rate_limited":    "rate limit exceeded, retry in %s",
+		"error.no_provider":     "no provider available for model %s",
+		"error.overloaded":      "gateway overloaded, request shed",
+		"tui.providers.title":   "Providers",
+		"tui.experiments.title": "Experiments",
+		"tui.stats.requests":    "%d requests",
+	},
+	"it": {
+		"status.healthy":        "operativo",
+		"status.degraded":       "degradato",
+		"status.down":           "non disponibile",
+		"error.rate_limited":    "limite di richieste superato, riprova tra %s",
+		"error.no_provider":     "nessun provider disponibile per il modello %s",
+		"error.overloaded":      "gateway sovraccarico, richiesta scartata",
+		"tui.providers.title":   "Provider",
+		"tui.experiments.title": "Esperimenti",
+		"tui.stats.requests":    "%d richieste",
+	},
+}
+
+var (
+	mu       sync.RWMutex
+	catalogs = func() map[string]Catalog {
+		m := make(map[string]Catalog, len(builtin))
+		for loc, c := range builtin {
+			copied := make(Catalog, len(c))
+			for k, v := range c {
+				copied[k] = v
+			}
+			m[loc] = copied
+		}
+		return m
+	}()
+)
+
+// RegisterCatalog merges messages into a locale's catalog, creating the
+// locale if needed. Existing keys are overridden.
+func RegisterCatalog(locale string, messages Catalog) {
+	mu.Lock()
+	defer mu.Unlock()
+	c, ok := catalogs[locale]
+	if !ok {
+		c = make(Catalog, len(messages))
+		catalogs[locale] = c
+	}
+	for k, v := range messages {
+		c[k] = v
+	}
+}
+
+// Locales lists the registered locales, sorted.
+func Locales() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]string, 0, len(catalogs))
+	for loc := range catalogs {
+		out = append(out, loc)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Localizer resolves messages for one locale. The zero value uses the
+// default locale.
+type Localizer struct{ Locale string }
+
+// T translates a key, formatting args into the message. Missing keys
+// fall back to the default locale, then to the key itself so untranslated
+// strings stay visible rather than blank.
+func (l Localizer) T(key string, args ...any) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, loc := range []string{l.Locale, DefaultLocale} {
+		if msg, ok := catalogs[loc][key]; ok {
+			if len(args) == 0 {
+				return msg
+			}
+			return fmt.Sprintf(msg, args...)
+		}
+	}
+	return key
+}
+
+// Resolve picks the locale for a request: the explicit per-user setting
+// wins, then the Accept-Language header, then the default.
+func Resolve(userSetting, acceptLanguage string) Localizer {
+	if userSetting != "" && has(userSetting) {
+		return Localizer{Locale: userSetting}
+	}
+	if loc := matchAcceptLanguage(acceptLanguage); loc != "" {
+		return Localizer{Locale: loc}
+	}
+	return Localizer{Locale: DefaultLocale}
+}
+
+func has(locale string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// matchAcceptLanguage returns the highest-quality registered locale from
+// an Accept-Language header, or "" when none match.
+func matchAcceptLanguage(header string) string {
+	type cand struct {
+		locale string
+		q      float64
+	}
+	var cands []cand
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		locale, q := part, 1.0
+		if base, attrs, ok := strings.Cut(part, ";"); ok {
+			locale = strings.TrimSpace(base)
+			if qs, found := strings.CutPrefix(strings.TrimSpace(attrs), "q="); found {
+				if parsed, err := strconv.ParseFloat(qs, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		// "it-IT" matches the "it" catalog.
+		locale = strings.ToLower(strings.SplitN(locale, "-", 2)[0])
+		if has(locale) {
+			cands = append(cands, cand{locale, q})
+		}
+	}
+	if len(cands) == 0 {
+		return ""
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].q > cands[j].q })
+	return cands[0].locale
+}
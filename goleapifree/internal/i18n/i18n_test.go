@@ -0,0 +1,45 @@
+package i18n
+
+import "testing"
+
+func TestTranslateWithFallback(t *testing.T) {
+	it := Localizer{Locale: "it"}
+	if got := it.T("status.healthy"); got != "operativo" {
+		t.Fatalf("T = %q", got)
+	}
+	if got := it.T("error.no_provider", "gpt-4o"); got != "nessun provider disponibile per il modello gpt-4o" {
+		t.Fatalf("T with args = %q", got)
+	}
+	// Key missing from every catalog: return the key itself.
+	if got := it.T("missing.key"); got != "missing.key" {
+		t.Fatalf("missing key = %q", got)
+	}
+}
+
+func TestResolvePrecedence(t *testing.T) {
+	if l := Resolve("it", "en"); l.Locale != "it" {
+		t.Fatalf("user setting not preferred, got %q", l.Locale)
+	}
+	if l := Resolve("", "it-IT;q=0.9, en;q=0.5"); l.Locale != "it" {
+		t.Fatalf("Accept-Language not honored, got %q", l.Locale)
+	}
+	if l := Resolve("", "fr-FR, de;q=0.8"); l.Locale != DefaultLocale {
+		t.Fatalf("unmatched header should fall back, got %q", l.Locale)
+	}
+	// Unregistered user setting falls through to the header.
+	if l := Resolve("klingon", "it"); l.Locale != "it" {
+		t.Fatalf("invalid setting not skipped, got %q", l.Locale)
+	}
+}
+
+func TestRegisterCatalogOverrides(t *testing.T) {
+	RegisterCatalog("de", Catalog{"status.healthy": "betriebsbereit"})
+	de := Localizer{Locale: "de"}
+	if got := de.T("status.healthy"); got != "betriebsbereit" {
+		t.Fatalf("T = %q", got)
+	}
+	// Keys absent in German fall back to English.
+	if got := de.T("status.down"); got != "down" {
+		t.Fatalf("fallback = %q", got)
+	}
+}
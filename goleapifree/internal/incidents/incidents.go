@@ -0,0 +1,221 @@
+// Package incidents keeps incident records linked to the alerting
+// subsystem: firing burn-rate alerts open incidents, health events
+// append timeline entries automatically, and operators resolve them
+// with notes. Incidents are queryable through the admin API and feed
+// the status page and dashboard.
+package incidents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/biodoia/goleapifree/internal/slo"
+	"github.com/biodoia/goleapifree/internal/statuspage"
+)
+
+// Timeline entry sources.
+const (
+	SourceAlert  = "alert"
+	SourceHealth = "health"
+	SourceNote   = "note"
+)
+
+// Incident errors.
+var (
+	ErrNotFound = errors.New("incidents: incident not found")
+	ErrResolved = errors.New("incidents: incident already resolved")
+)
+
+// TimelineEntry is one event in an incident's history.
+type TimelineEntry struct {
+	At     time.Time `json:"at"`
+	Source string    `json:"source"`
+	Text   string    `json:"text"`
+}
+
+// Incident is one tracked outage or degradation.
+type Incident struct {
+	ID         string          `json:"id"`
+	Target     string          `json:"target"`
+	Title      string          `json:"title"`
+	Severity   string          `json:"severity"`
+	OpenedAt   time.Time       `json:"opened_at"`
+	ResolvedAt *time.Time      `json:"resolved_at,omitempty"`
+	Resolution string          `json:"resolution,omitempty"`
+	Timeline   []TimelineEntry `json:"timeline"`
+}
+
+// Manager owns the incident records.
+type Manager struct {
+	// Alerts feeds Sync; the wiring binds it to the SLO tracker.
+	Alerts func() []slo.Alert
+
+	now func() time.Time
+
+	mu        sync.Mutex
+	incidents []*Incident
+	open      map[string]*Incident // target -> open incident
+	firing    map[string]bool      // targets firing at the last sync
+}
+
+// NewManager returns an empty manager.
+func NewManager() *Manager {
+	return &Manager{
+		now:    time.Now,
+		open:   make(map[string]*Incident),
+		firing: make(map[string]bool),
+	}
+}
+
+// Sync reconciles incidents against the currently firing alerts: new
+// alerts open incidents, repeated ones escalate severity, and cleared
+// ones land in the timeline — resolution itself stays with the operator.
+// Its signature matches the scheduler's JobFunc.
+func (m *Manager) Sync(ctx context.Context) error {
+	var alerts []slo.Alert
+	if m.Alerts != nil {
+		alerts = m.Alerts()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nowFiring := make(map[string]bool, len(alerts))
+	for _, alert := range alerts {
+		nowFiring[alert.Target] = true
+		inc, ok := m.open[alert.Target]
+		if !ok {
+			inc = &Incident{
+				ID:       uuid.NewString(),
+				Target:   alert.Target,
+				Title:    fmt.Sprintf("%s: error budget burning at %.1fx", alert.Target, alert.BurnRate),
+				Severity: alert.Severity,
+				OpenedAt: m.now(),
+			}
+			m.incidents = append(m.incidents, inc)
+			m.open[alert.Target] = inc
+			m.appendLocked(inc, SourceAlert, fmt.Sprintf("%s alert fired, burn rate %.1fx over %s",
+				alert.Severity, alert.BurnRate, alert.Window))
+			continue
+		}
+		if alert.Severity == "page" && inc.Severity != "page" {
+			inc.Severity = alert.Severity
+			m.appendLocked(inc, SourceAlert, fmt.Sprintf("escalated to page, burn rate %.1fx", alert.BurnRate))
+		}
+	}
+	for target := range m.firing {
+		if !nowFiring[target] {
+			if inc, ok := m.open[target]; ok {
+				m.appendLocked(inc, SourceAlert, "alert cleared")
+			}
+		}
+	}
+	m.firing = nowFiring
+	return nil
+}
+
+// RecordHealthEvent appends a health observation to the target's open
+// incident; with no open incident the event is dropped — routine health
+// noise does not deserve records of its own.
+func (m *Manager) RecordHealthEvent(target, text string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if inc, ok := m.open[target]; ok {
+		m.appendLocked(inc, SourceHealth, text)
+	}
+}
+
+// AddNote appends an operator note to an incident, open or resolved.
+func (m *Manager) AddNote(id, text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inc := m.findLocked(id)
+	if inc == nil {
+		return ErrNotFound
+	}
+	m.appendLocked(inc, SourceNote, text)
+	return nil
+}
+
+// Resolve closes an incident with a resolution note.
+func (m *Manager) Resolve(id, resolution string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inc := m.findLocked(id)
+	if inc == nil {
+		return ErrNotFound
+	}
+	if inc.ResolvedAt != nil {
+		return ErrResolved
+	}
+	now := m.now()
+	inc.ResolvedAt = &now
+	inc.Resolution = resolution
+	m.appendLocked(inc, SourceNote, "resolved: "+resolution)
+	delete(m.open, inc.Target)
+	return nil
+}
+
+// Get returns one incident by ID.
+func (m *Manager) Get(id string) (Incident, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inc := m.findLocked(id)
+	if inc == nil {
+		return Incident{}, ErrNotFound
+	}
+	return copyIncident(inc), nil
+}
+
+// List returns every incident, newest first.
+func (m *Manager) List() []Incident {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Incident, 0, len(m.incidents))
+	for i := len(m.incidents) - 1; i >= 0; i-- {
+		out = append(out, copyIncident(m.incidents[i]))
+	}
+	return out
+}
+
+// Events renders the incident history for the status page, newest
+// first.
+func (m *Manager) Events() []statuspage.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]statuspage.Event, 0, len(m.incidents))
+	for i := len(m.incidents) - 1; i >= 0; i-- {
+		inc := m.incidents[i]
+		out = append(out, statuspage.Event{
+			At: inc.OpenedAt, Title: inc.Title,
+			Severity: inc.Severity, Resolved: inc.ResolvedAt,
+		})
+	}
+	return out
+}
+
+// appendLocked adds a timeline entry. Callers hold m.mu.
+func (m *Manager) appendLocked(inc *Incident, source, text string) {
+	inc.Timeline = append(inc.Timeline, TimelineEntry{At: m.now(), Source: source, Text: text})
+}
+
+// findLocked locates an incident by ID. Callers hold m.mu.
+func (m *Manager) findLocked(id string) *Incident {
+	for _, inc := range m.incidents {
+		if inc.ID == id {
+			return inc
+		}
+	}
+	return nil
+}
+
+func copyIncident(inc *Incident) Incident {
+	out := *inc
+	out.Timeline = make([]TimelineEntry, len(inc.Timeline))
+	copy(out.Timeline, inc.Timeline)
+	return out
+}
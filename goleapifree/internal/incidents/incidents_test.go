@@ -0,0 +1,116 @@
+package incidents
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/slo"
+)
+
+func testManager(alerts *[]slo.Alert) *Manager {
+	m := NewManager()
+	m.now = func() time.Time { return time.Date(2026, 9, 1, 16, 0, 0, 0, time.UTC) }
+	m.Alerts = func() []slo.Alert { return *alerts }
+	return m
+}
+
+func TestAlertLifecycle(t *testing.T) {
+	alerts := []slo.Alert{{Target: "openai", Severity: "ticket", Window: time.Hour, BurnRate: 4.2}}
+	m := testManager(&alerts)
+	ctx := context.Background()
+
+	if err := m.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	list := m.List()
+	if len(list) != 1 || list[0].Target != "openai" || list[0].Severity != "ticket" {
+		t.Fatalf("incidents = %+v", list)
+	}
+	id := list[0].ID
+
+	// A repeated, worse alert escalates the same incident rather than
+	// opening a second one.
+	alerts[0].Severity = "page"
+	if err := m.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if list := m.List(); len(list) != 1 || list[0].Severity != "page" {
+		t.Fatalf("incidents = %+v", list)
+	}
+
+	m.RecordHealthEvent("openai", "health check: 3 consecutive timeouts")
+	m.RecordHealthEvent("ghost", "no open incident, dropped")
+
+	// The alert clears but the incident stays open for the operator.
+	alerts = nil
+	if err := m.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	inc, err := m.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inc.ResolvedAt != nil {
+		t.Fatal("cleared alert auto-resolved the incident")
+	}
+	last := inc.Timeline[len(inc.Timeline)-1]
+	if last.Source != SourceAlert || last.Text != "alert cleared" {
+		t.Fatalf("timeline = %+v", inc.Timeline)
+	}
+
+	if err := m.AddNote(id, "root cause: expired key"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Resolve(id, "rotated the credential"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Resolve(id, "again"); !errors.Is(err, ErrResolved) {
+		t.Fatalf("double resolve: %v", err)
+	}
+	inc, _ = m.Get(id)
+	if inc.ResolvedAt == nil || inc.Resolution != "rotated the credential" {
+		t.Fatalf("incident = %+v", inc)
+	}
+
+	// A fresh alert for the same target opens a new incident.
+	alerts = []slo.Alert{{Target: "openai", Severity: "page", Window: time.Hour, BurnRate: 15}}
+	if err := m.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if list := m.List(); len(list) != 2 || list[0].ID == id {
+		t.Fatalf("incidents = %+v", list)
+	}
+}
+
+func TestEventsFeedStatusPage(t *testing.T) {
+	alerts := []slo.Alert{
+		{Target: "a", Severity: "page", BurnRate: 15},
+		{Target: "b", Severity: "ticket", BurnRate: 3},
+	}
+	m := testManager(&alerts)
+	if err := m.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Resolve(m.List()[0].ID, "fixed"); err != nil {
+		t.Fatal(err)
+	}
+	events := m.Events()
+	if len(events) != 2 {
+		t.Fatalf("events = %+v", events)
+	}
+	if events[0].Resolved == nil || events[1].Resolved != nil {
+		t.Fatalf("events = %+v", events)
+	}
+}
+
+func TestUnknownIncident(t *testing.T) {
+	m := NewManager()
+	if err := m.AddNote("ghost", "x"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := m.Get("ghost"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v", err)
+	}
+}
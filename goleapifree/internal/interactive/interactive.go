@@ -0,0 +1,163 @@
+// Package interactive gives coding-tool traffic a latency-optimized
+// routing profile. IDE assistants (Aider, Continue, Cursor, ...) are
+// recognized by their user agents, so the tools need no special
+// headers: their requests skip cheap-but-slow providers and may hedge
+// a second upstream when the first is quiet too long.
+package interactive
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// Latency profile defaults.
+const (
+	// defaultSlowThresholdMs excludes providers whose p95 sits above
+	// it; 2.5s is where a completion stops feeling inline.
+	defaultSlowThresholdMs = 2500
+	// defaultHedgeDelay is how long the first upstream may stay quiet
+	// before a hedge fires.
+	defaultHedgeDelay = 400 * time.Millisecond
+)
+
+// toolAgents maps user-agent markers to canonical tool names. Markers
+// are matched case-insensitively as substrings because the tools embed
+// versions and platforms around them.
+var toolAgents = map[string]string{
+	"aider":    "aider",
+	"continue": "continue",
+	"cursor":   "cursor",
+	"zed":      "zed",
+	"copilot":  "copilot",
+}
+
+// DetectTool returns the canonical name of the coding tool behind a
+// user agent, or "" for ordinary traffic.
+func DetectTool(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	for marker, tool := range toolAgents {
+		if strings.Contains(ua, marker) {
+			return tool
+		}
+	}
+	return ""
+}
+
+// ChatFunc issues one chat completion, the same seam the agents and
+// the speculative decoder use.
+type ChatFunc func(ctx context.Context, req providers.ChatRequest) (*providers.ChatResponse, error)
+
+// Profile is the latency-optimized routing profile applied to
+// interactive requests. Its Exclude and Prior plug into the router
+// hooks for the duration of one Route call.
+type Profile struct {
+	// LatencyP95 reports a provider's p95 latency in milliseconds; the
+	// stats analyzer plugs in. 0 means no data, which neither excludes
+	// nor boosts.
+	LatencyP95 func(providerID string) float64
+	// SlowThresholdMs overrides the exclusion threshold; 0 applies the
+	// default.
+	SlowThresholdMs float64
+	// HedgeDelay overrides how long to wait before hedging; 0 applies
+	// the default.
+	HedgeDelay time.Duration
+}
+
+func (p *Profile) threshold() float64 {
+	if p.SlowThresholdMs > 0 {
+		return p.SlowThresholdMs
+	}
+	return defaultSlowThresholdMs
+}
+
+// Exclude reports whether a provider is too slow for interactive
+// traffic.
+func (p *Profile) Exclude(providerID string) bool {
+	if p.LatencyP95 == nil {
+		return false
+	}
+	lat := p.LatencyP95(providerID)
+	return lat > p.threshold()
+}
+
+// Prior scores providers by speed in [0,1]: instant is 1, the slow
+// threshold is 0, unknown latency sits neutral so new providers still
+// get traffic.
+func (p *Profile) Prior(providerID string) float64 {
+	if p.LatencyP95 == nil {
+		return 0.5
+	}
+	lat := p.LatencyP95(providerID)
+	if lat <= 0 {
+		return 0.5
+	}
+	score := 1 - lat/p.threshold()
+	if score < 0 {
+		return 0
+	}
+	return score
+}
+
+// Hedge races two upstreams: primary starts immediately, secondary
+// fires only if no result arrived within the hedge delay. The first
+// success wins and the loser is canceled; when both fail, both errors
+// come back joined.
+func (p *Profile) Hedge(ctx context.Context, req providers.ChatRequest, primary, secondary ChatFunc) (*providers.ChatResponse, error) {
+	delay := p.HedgeDelay
+	if delay <= 0 {
+		delay = defaultHedgeDelay
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		resp *providers.ChatResponse
+		err  error
+	}
+	results := make(chan result, 2)
+	launch := func(fn ChatFunc) {
+		go func() {
+			resp, err := fn(ctx, req)
+			results <- result{resp, err}
+		}()
+	}
+	launch(primary)
+
+	inFlight := 1
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	var errs []error
+	for {
+		select {
+		case <-timer.C:
+			if secondary != nil {
+				launch(secondary)
+				secondary = nil
+				inFlight++
+			}
+		case r := <-results:
+			if r.err == nil {
+				return r.resp, nil
+			}
+			errs = append(errs, r.err)
+			inFlight--
+			if inFlight == 0 {
+				// Primary failed before the hedge fired: fail over
+				// immediately rather than waiting out the delay.
+				if secondary != nil && len(errs) == 1 {
+					launch(secondary)
+					secondary = nil
+					inFlight++
+					continue
+				}
+				return nil, errors.Join(errs...)
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package interactive
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+func TestDetectTool(t *testing.T) {
+	for _, tc := range []struct{ ua, want string }{
+		{"Aider/0.45.1 (Python 3.12)", "aider"},
+		{"Continue-IDE/1.2", "continue"},
+		{"Cursor/0.40 (darwin)", "cursor"},
+		{"Mozilla/5.0 (X11; Linux)", ""},
+		{"curl/8.5", ""},
+	} {
+		if got := DetectTool(tc.ua); got != tc.want {
+			t.Errorf("DetectTool(%q) = %q, want %q", tc.ua, got, tc.want)
+		}
+	}
+}
+
+func TestProfileRoutingHooks(t *testing.T) {
+	p := &Profile{LatencyP95: func(id string) float64 {
+		switch id {
+		case "fast":
+			return 500
+		case "slow":
+			return 8000
+		default:
+			return 0
+		}
+	}}
+	if p.Exclude("fast") || !p.Exclude("slow") {
+		t.Fatal("exclusion threshold wrong")
+	}
+	if p.Exclude("unknown") {
+		t.Fatal("provider without data excluded")
+	}
+	if p.Prior("fast") <= p.Prior("slow") {
+		t.Fatalf("fast = %v, slow = %v", p.Prior("fast"), p.Prior("slow"))
+	}
+	if p.Prior("unknown") != 0.5 {
+		t.Fatalf("unknown = %v", p.Prior("unknown"))
+	}
+}
+
+func TestHedgeFiresOnSlowPrimary(t *testing.T) {
+	p := &Profile{HedgeDelay: 20 * time.Millisecond}
+	primary := func(ctx context.Context, _ providers.ChatRequest) (*providers.ChatResponse, error) {
+		select {
+		case <-time.After(time.Second):
+			return &providers.ChatResponse{Content: "primary"}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	secondary := func(context.Context, providers.ChatRequest) (*providers.ChatResponse, error) {
+		return &providers.ChatResponse{Content: "hedge"}, nil
+	}
+	resp, err := p.Hedge(context.Background(), providers.ChatRequest{}, primary, secondary)
+	if err != nil || resp.Content != "hedge" {
+		t.Fatalf("Hedge = %+v, %v", resp, err)
+	}
+}
+
+func TestHedgeSkipsSecondaryWhenPrimaryIsFast(t *testing.T) {
+	p := &Profile{HedgeDelay: 50 * time.Millisecond}
+	hedged := false
+	primary := func(context.Context, providers.ChatRequest) (*providers.ChatResponse, error) {
+		return &providers.ChatResponse{Content: "primary"}, nil
+	}
+	secondary := func(context.Context, providers.ChatRequest) (*providers.ChatResponse, error) {
+		hedged = true
+		return &providers.ChatResponse{Content: "hedge"}, nil
+	}
+	resp, err := p.Hedge(context.Background(), providers.ChatRequest{}, primary, secondary)
+	if err != nil || resp.Content != "primary" {
+		t.Fatalf("Hedge = %+v, %v", resp, err)
+	}
+	if hedged {
+		t.Fatal("hedge fired for a fast primary")
+	}
+}
+
+func TestHedgeFailsOverImmediately(t *testing.T) {
+	p := &Profile{HedgeDelay: time.Hour} // timer must not be what triggers it
+	primary := func(context.Context, providers.ChatRequest) (*providers.ChatResponse, error) {
+		return nil, errors.New("upstream 500")
+	}
+	secondary := func(context.Context, providers.ChatRequest) (*providers.ChatResponse, error) {
+		return &providers.ChatResponse{Content: "hedge"}, nil
+	}
+	resp, err := p.Hedge(context.Background(), providers.ChatRequest{}, primary, secondary)
+	if err != nil || resp.Content != "hedge" {
+		t.Fatalf("Hedge = %+v, %v", resp, err)
+	}
+
+	boom := errors.New("also down")
+	secondary = func(context.Context, providers.ChatRequest) (*providers.ChatResponse, error) {
+		return nil, boom
+	}
+	if _, err := p.Hedge(context.Background(), providers.ChatRequest{}, primary, secondary); !errors.Is(err, boom) {
+		t.Fatalf("joined error missing hedge failure: %v", err)
+	}
+}
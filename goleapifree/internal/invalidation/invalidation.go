@@ -0,0 +1,82 @@
+// Package invalidation is the cross-instance cache invalidation bus.
+// When one replica updates providers, aliases or flags, or clears a
+// cache, the others must drop their in-memory copies; subsystems
+// subscribe per kind and receive events published by any replica. The
+// Redis transport carries events between instances; single-replica
+// deployments and tests use the in-process bus.
+package invalidation
+
+import (
+	"context"
+	"sync"
+)
+
+// Event kinds, one per piece of replicated in-memory state.
+const (
+	KindProvider = "provider"
+	KindAlias    = "alias"
+	KindFlag     = "flag"
+	KindCache    = "cache"
+)
+
+// Event names one invalidated entry. Key is the provider ID, alias
+// name, flag name or cache name; empty means "all of this kind".
+type Event struct {
+	Kind string `json:"kind"`
+	Key  string `json:"key,omitempty"`
+	// Origin identifies the publishing instance so replicas skip the
+	// events they published themselves — their state is already fresh.
+	Origin string `json:"origin,omitempty"`
+}
+
+// Bus publishes invalidation events and fans them out to subscribers.
+type Bus interface {
+	Publish(ctx context.Context, e Event) error
+	// Subscribe registers a handler for one kind. Handlers must be
+	// quick or hand off; they run on the bus's dispatch goroutine.
+	Subscribe(kind string, fn func(Event))
+}
+
+// dispatcher is the handler registry shared by the bus implementations.
+type dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string][]func(Event)
+}
+
+func (d *dispatcher) subscribe(kind string, fn func(Event)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.handlers == nil {
+		d.handlers = make(map[string][]func(Event))
+	}
+	d.handlers[kind] = append(d.handlers[kind], fn)
+}
+
+func (d *dispatcher) dispatch(e Event) {
+	d.mu.RLock()
+	handlers := d.handlers[e.Kind]
+	d.mu.RUnlock()
+	for _, fn := range handlers {
+		fn(e)
+	}
+}
+
+// LocalBus delivers events in-process, synchronously. It is the
+// single-replica default and the test double for the Redis bus.
+type LocalBus struct {
+	dispatcher
+}
+
+// NewLocalBus creates an empty in-process bus.
+func NewLocalBus() *LocalBus { return &LocalBus{} }
+
+// Publish implements Bus.
+func (b *LocalBus) Publish(_ context.Context, e Event) error {
+	b.dispatch(e)
+	return nil
+}
+
+// Subscribe implements Bus.
+func (b *LocalBus) Subscribe(kind string, fn func(Event)) {
+	b.subscribe(kind, fn)
+}
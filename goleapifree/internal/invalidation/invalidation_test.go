@@ -0,0 +1,40 @@
+package invalidation
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLocalBusFanout(t *testing.T) {
+	bus := NewLocalBus()
+	var aliases, providers []string
+	bus.Subscribe(KindAlias, func(e Event) { aliases = append(aliases, e.Key) })
+	bus.Subscribe(KindAlias, func(e Event) { aliases = append(aliases, e.Key) })
+	bus.Subscribe(KindProvider, func(e Event) { providers = append(providers, e.Key) })
+
+	if err := bus.Publish(context.Background(), Event{Kind: KindAlias, Key: "fast"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publish(context.Background(), Event{Kind: KindCache, Key: "respcache"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(aliases) != 2 || aliases[0] != "fast" {
+		t.Fatalf("aliases = %v", aliases)
+	}
+	if len(providers) != 0 {
+		t.Fatalf("kind filter leaked: %v", providers)
+	}
+}
+
+func TestDispatchBeforeSubscribeIsSafe(t *testing.T) {
+	bus := NewLocalBus()
+	if err := bus.Publish(context.Background(), Event{Kind: KindFlag, Key: "edge-ui"}); err != nil {
+		t.Fatal(err)
+	}
+	var got []Event
+	bus.Subscribe(KindFlag, func(e Event) { got = append(got, e) })
+	bus.Publish(context.Background(), Event{Kind: KindFlag, Key: "edge-ui"})
+	if len(got) != 1 {
+		t.Fatalf("events = %v", got)
+	}
+}
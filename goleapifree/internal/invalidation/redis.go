@@ -0,0 +1,70 @@
+//go:build !edge
+
+package invalidation
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBus carries invalidation events between replicas over one Redis
+// pub/sub channel. Every instance both publishes and subscribes; the
+// Origin field keeps an instance from re-processing its own events.
+type RedisBus struct {
+	dispatcher
+	rdb     *redis.Client
+	channel string
+	origin  string
+}
+
+// NewRedisBus creates a bus on the given channel. Call Run to start
+// receiving.
+func NewRedisBus(rdb *redis.Client, channel string) *RedisBus {
+	return &RedisBus{rdb: rdb, channel: channel, origin: uuid.NewString()}
+}
+
+// Publish implements Bus. The event reaches every replica including
+// this one; the receive loop drops the echo by origin.
+func (b *RedisBus) Publish(ctx context.Context, e Event) error {
+	e.Origin = b.origin
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return b.rdb.Publish(ctx, b.channel, data).Err()
+}
+
+// Subscribe implements Bus.
+func (b *RedisBus) Subscribe(kind string, fn func(Event)) {
+	b.subscribe(kind, fn)
+}
+
+// Run receives events until the context ends. Redis pub/sub delivery
+// is at-most-once, which is the right trade-off here: a missed event
+// means a cache entry lives until its TTL, not corruption.
+func (b *RedisBus) Run(ctx context.Context) error {
+	sub := b.rdb.Subscribe(ctx, b.channel)
+	defer sub.Close()
+	ch := sub.Channel()
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return ctx.Err()
+			}
+			var e Event
+			if err := json.Unmarshal([]byte(msg.Payload), &e); err != nil {
+				continue // a malformed event must not kill the loop
+			}
+			if e.Origin == b.origin {
+				continue
+			}
+			b.dispatch(e)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
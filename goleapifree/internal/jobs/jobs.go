@@ -0,0 +1,47 @@
+// Package jobs provides durable background queuing for batch work,
+// evaluations, exports and webhook delivery. A Queue hands out jobs under a
+// visibility timeout; unacked jobs reappear, and jobs that exhaust their
+// retries land in the dead-letter set for inspection.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrEmpty is returned by Dequeue when no job is currently available.
+var ErrEmpty = errors.New("jobs: queue empty")
+
+// Job is one unit of background work.
+type Job struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+	// Attempts counts deliveries so far, including the current one.
+	Attempts   int       `json:"attempts"`
+	MaxRetries int       `json:"max_retries"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+}
+
+// Queue is the storage backend contract. Implementations must make
+// Dequeue invisible for the visibility timeout: a job neither Acked nor
+// Nacked within it is redelivered.
+type Queue interface {
+	// Enqueue stores a job for later delivery.
+	Enqueue(ctx context.Context, job *Job) error
+	// Dequeue claims the next available job, or ErrEmpty.
+	Dequeue(ctx context.Context, visibility time.Duration) (*Job, error)
+	// Ack removes a claimed job permanently.
+	Ack(ctx context.Context, id string) error
+	// Nack releases a claimed job; when its retries are exhausted the
+	// backend moves it to the dead-letter set instead of requeuing.
+	Nack(ctx context.Context, id string) error
+	// DeadLetters lists jobs that exhausted their retries.
+	DeadLetters(ctx context.Context) ([]*Job, error)
+}
+
+// Handler processes one job. A non-nil error triggers a retry (or
+// dead-lettering once MaxRetries is exhausted).
+type Handler func(ctx context.Context, job *Job) error
@@ -0,0 +1,102 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryQueue is the in-process Queue used for tests and single-node
+// deployments that do not need durability across restarts.
+type MemoryQueue struct {
+	mu       sync.Mutex
+	ready    []*Job
+	inflight map[string]*inflightJob
+	dead     []*Job
+	now      func() time.Time
+}
+
+type inflightJob struct {
+	job      *Job
+	deadline time.Time
+}
+
+// NewMemoryQueue returns an empty in-memory queue.
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{inflight: make(map[string]*inflightJob), now: time.Now}
+}
+
+// Enqueue implements Queue.
+func (q *MemoryQueue) Enqueue(_ context.Context, job *Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = q.now()
+	}
+	q.ready = append(q.ready, job)
+	return nil
+}
+
+// Dequeue implements Queue.
+func (q *MemoryQueue) Dequeue(_ context.Context, visibility time.Duration) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.reapExpiredLocked()
+	if len(q.ready) == 0 {
+		return nil, ErrEmpty
+	}
+	job := q.ready[0]
+	q.ready = q.ready[1:]
+	job.Attempts++
+	q.inflight[job.ID] = &inflightJob{job: job, deadline: q.now().Add(visibility)}
+	return job, nil
+}
+
+// Ack implements Queue.
+func (q *MemoryQueue) Ack(_ context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.inflight, id)
+	return nil
+}
+
+// Nack implements Queue.
+func (q *MemoryQueue) Nack(_ context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	inf, ok := q.inflight[id]
+	if !ok {
+		return nil
+	}
+	delete(q.inflight, id)
+	q.requeueLocked(inf.job)
+	return nil
+}
+
+// DeadLetters implements Queue.
+func (q *MemoryQueue) DeadLetters(_ context.Context) ([]*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]*Job(nil), q.dead...), nil
+}
+
+// reapExpiredLocked redelivers jobs whose visibility timeout elapsed.
+func (q *MemoryQueue) reapExpiredLocked() {
+	now := q.now()
+	for id, inf := range q.inflight {
+		if now.After(inf.deadline) {
+			delete(q.inflight, id)
+			q.requeueLocked(inf.job)
+		}
+	}
+}
+
+// requeueLocked returns a job to the ready list, or dead-letters it when
+// its retries are spent.
+func (q *MemoryQueue) requeueLocked(job *Job) {
+	if job.Attempts > job.MaxRetries {
+		q.dead = append(q.dead, job)
+		return
+	}
+	q.ready = append(q.ready, job)
+}
@@ -0,0 +1,92 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryQueueRetryAndDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	q := NewMemoryQueue()
+	if err := q.Enqueue(ctx, &Job{ID: "j1", Type: "export", MaxRetries: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// First delivery fails.
+	job, err := q.Dequeue(ctx, time.Minute)
+	if err != nil || job.Attempts != 1 {
+		t.Fatalf("dequeue: %v, attempts %d", err, job.Attempts)
+	}
+	if err := q.Nack(ctx, job.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Retry fails too -> dead letter.
+	job, err = q.Dequeue(ctx, time.Minute)
+	if err != nil || job.Attempts != 2 {
+		t.Fatalf("redelivery: %v, attempts %d", err, job.Attempts)
+	}
+	if err := q.Nack(ctx, job.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := q.Dequeue(ctx, time.Minute); err != ErrEmpty {
+		t.Fatalf("expected empty queue, got %v", err)
+	}
+	dead, _ := q.DeadLetters(ctx)
+	if len(dead) != 1 || dead[0].ID != "j1" {
+		t.Fatalf("dead letters = %+v, want j1", dead)
+	}
+}
+
+func TestMemoryQueueVisibilityTimeout(t *testing.T) {
+	ctx := context.Background()
+	q := NewMemoryQueue()
+	now := time.Now()
+	q.now = func() time.Time { return now }
+
+	_ = q.Enqueue(ctx, &Job{ID: "j1", MaxRetries: 3})
+	if _, err := q.Dequeue(ctx, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	// Still claimed: nothing to deliver.
+	if _, err := q.Dequeue(ctx, time.Minute); err != ErrEmpty {
+		t.Fatalf("expected empty while claimed, got %v", err)
+	}
+	// After the visibility window the job reappears.
+	now = now.Add(2 * time.Minute)
+	job, err := q.Dequeue(ctx, time.Minute)
+	if err != nil || job.ID != "j1" || job.Attempts != 2 {
+		t.Fatalf("expected redelivery of j1, got %+v err %v", job, err)
+	}
+}
+
+func TestPoolProcessesJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := NewMemoryQueue()
+	_ = q.Enqueue(ctx, &Job{ID: "j1", Type: "ping"})
+
+	done := make(chan string, 1)
+	p := NewPool(q)
+	p.Workers = 1
+	p.Idle = 5 * time.Millisecond
+	p.Handle("ping", func(_ context.Context, job *Job) error {
+		done <- job.ID
+		return nil
+	})
+	p.Start(ctx)
+
+	select {
+	case id := <-done:
+		if id != "j1" {
+			t.Fatalf("processed %q, want j1", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("job was not processed")
+	}
+	cancel()
+	p.Wait()
+}
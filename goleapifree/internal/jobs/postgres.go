@@ -0,0 +1,109 @@
+//go:build !edge
+
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// PostgresQueue stores jobs in a single table and claims them with
+// FOR UPDATE SKIP LOCKED, so multiple gateway instances can share one
+// queue without a coordinator.
+type PostgresQueue struct {
+	db *sql.DB
+}
+
+// pgSchema is applied by NewPostgresQueue; visible_at implements the
+// visibility timeout and dead marks exhausted jobs.
+const pgSchema = `
+CREATE TABLE IF NOT EXISTS jobs (
+    id          TEXT PRIMARY KEY,
+    type        TEXT NOT NULL,
+    payload     JSONB NOT NULL DEFAULT '{}',
+    attempts    INT NOT NULL DEFAULT 0,
+    max_retries INT NOT NULL DEFAULT 0,
+    enqueued_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    visible_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    dead        BOOLEAN NOT NULL DEFAULT false
+);
+CREATE INDEX IF NOT EXISTS jobs_ready_idx ON jobs (visible_at) WHERE NOT dead;`
+
+// NewPostgresQueue prepares the schema and returns the queue.
+func NewPostgresQueue(ctx context.Context, db *sql.DB) (*PostgresQueue, error) {
+	if _, err := db.ExecContext(ctx, pgSchema); err != nil {
+		return nil, err
+	}
+	return &PostgresQueue{db: db}, nil
+}
+
+// Enqueue implements Queue.
+func (q *PostgresQueue) Enqueue(ctx context.Context, job *Job) error {
+	_, err := q.db.ExecContext(ctx,
+		`INSERT INTO jobs (id, type, payload, max_retries) VALUES ($1, $2, $3, $4)`,
+		job.ID, job.Type, []byte(job.Payload), job.MaxRetries)
+	return err
+}
+
+// Dequeue implements Queue.
+func (q *PostgresQueue) Dequeue(ctx context.Context, visibility time.Duration) (*Job, error) {
+	row := q.db.QueryRowContext(ctx, `
+UPDATE jobs SET attempts = attempts + 1, visible_at = now() + $1::interval
+WHERE id = (
+    SELECT id FROM jobs
+    WHERE NOT dead AND visible_at <= now()
+    ORDER BY enqueued_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING id, type, payload, attempts, max_retries, enqueued_at`,
+		visibility.String())
+
+	job := &Job{}
+	err := row.Scan(&job.ID, &job.Type, &job.Payload, &job.Attempts, &job.MaxRetries, &job.EnqueuedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEmpty
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Ack implements Queue.
+func (q *PostgresQueue) Ack(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, `DELETE FROM jobs WHERE id = $1`, id)
+	return err
+}
+
+// Nack implements Queue. Jobs over their retry budget are flagged dead in
+// place; others become immediately visible again.
+func (q *PostgresQueue) Nack(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, `
+UPDATE jobs SET
+    dead = attempts > max_retries,
+    visible_at = now()
+WHERE id = $1`, id)
+	return err
+}
+
+// DeadLetters implements Queue.
+func (q *PostgresQueue) DeadLetters(ctx context.Context) ([]*Job, error) {
+	rows, err := q.db.QueryContext(ctx,
+		`SELECT id, type, payload, attempts, max_retries, enqueued_at FROM jobs WHERE dead ORDER BY enqueued_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*Job
+	for rows.Next() {
+		job := &Job{}
+		if err := rows.Scan(&job.ID, &job.Type, &job.Payload, &job.Attempts, &job.MaxRetries, &job.EnqueuedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, job)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,163 @@
+//go:build !edge
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisQueue keeps the ready list in a Redis list, claimed jobs in a
+// sorted set scored by visibility deadline, and dead letters in a list.
+// Expired claims are reaped lazily on Dequeue.
+type RedisQueue struct {
+	rdb    *redis.Client
+	prefix string
+}
+
+// NewRedisQueue returns a queue using the given key prefix, so several
+// logical queues can share one Redis database.
+func NewRedisQueue(rdb *redis.Client, prefix string) *RedisQueue {
+	return &RedisQueue{rdb: rdb, prefix: prefix}
+}
+
+func (q *RedisQueue) readyKey() string    { return q.prefix + ":ready" }
+func (q *RedisQueue) inflightKey() string { return q.prefix + ":inflight" }
+func (q *RedisQueue) deadKey() string     { return q.prefix + ":dead" }
+func (q *RedisQueue) jobKey(id string) string {
+	return q.prefix + ":job:" + id
+}
+
+// Enqueue implements Queue.
+func (q *RedisQueue) Enqueue(ctx context.Context, job *Job) error {
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = time.Now()
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	pipe := q.rdb.TxPipeline()
+	pipe.Set(ctx, q.jobKey(job.ID), data, 0)
+	pipe.RPush(ctx, q.readyKey(), job.ID)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Dequeue implements Queue.
+func (q *RedisQueue) Dequeue(ctx context.Context, visibility time.Duration) (*Job, error) {
+	if err := q.reapExpired(ctx); err != nil {
+		return nil, err
+	}
+	id, err := q.rdb.LPop(ctx, q.readyKey()).Result()
+	if err == redis.Nil {
+		return nil, ErrEmpty
+	}
+	if err != nil {
+		return nil, err
+	}
+	job, err := q.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	job.Attempts++
+	if err := q.store(ctx, job); err != nil {
+		return nil, err
+	}
+	deadline := float64(time.Now().Add(visibility).UnixMilli())
+	if err := q.rdb.ZAdd(ctx, q.inflightKey(), redis.Z{Score: deadline, Member: id}).Err(); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Ack implements Queue.
+func (q *RedisQueue) Ack(ctx context.Context, id string) error {
+	pipe := q.rdb.TxPipeline()
+	pipe.ZRem(ctx, q.inflightKey(), id)
+	pipe.Del(ctx, q.jobKey(id))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Nack implements Queue.
+func (q *RedisQueue) Nack(ctx context.Context, id string) error {
+	removed, err := q.rdb.ZRem(ctx, q.inflightKey(), id).Result()
+	if err != nil || removed == 0 {
+		return err
+	}
+	return q.requeue(ctx, id)
+}
+
+// DeadLetters implements Queue.
+func (q *RedisQueue) DeadLetters(ctx context.Context) ([]*Job, error) {
+	ids, err := q.rdb.LRange(ctx, q.deadKey(), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := q.load(ctx, id)
+		if err != nil {
+			continue // job body expired; skip the tombstone
+		}
+		out = append(out, job)
+	}
+	return out, nil
+}
+
+// reapExpired moves claims past their visibility deadline back to ready
+// (or to the dead-letter list when retries are exhausted).
+func (q *RedisQueue) reapExpired(ctx context.Context) error {
+	now := float64(time.Now().UnixMilli())
+	ids, err := q.rdb.ZRangeByScore(ctx, q.inflightKey(), &redis.ZRangeBy{Min: "0", Max: formatScore(now)}).Result()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if removed, err := q.rdb.ZRem(ctx, q.inflightKey(), id).Result(); err != nil || removed == 0 {
+			continue // another instance reaped it first
+		}
+		if err := q.requeue(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requeue puts an unacked job back on the ready list or dead-letters it.
+func (q *RedisQueue) requeue(ctx context.Context, id string) error {
+	job, err := q.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.Attempts > job.MaxRetries {
+		return q.rdb.RPush(ctx, q.deadKey(), id).Err()
+	}
+	return q.rdb.RPush(ctx, q.readyKey(), id).Err()
+}
+
+func (q *RedisQueue) load(ctx context.Context, id string) (*Job, error) {
+	data, err := q.rdb.Get(ctx, q.jobKey(id)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	job := &Job{}
+	return job, json.Unmarshal(data, job)
+}
+
+func (q *RedisQueue) store(ctx context.Context, job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return q.rdb.Set(ctx, q.jobKey(job.ID), data, 0).Err()
+}
+
+func formatScore(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,95 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Pool runs a fixed number of workers against a queue, dispatching jobs to
+// handlers registered per job type.
+type Pool struct {
+	Queue      Queue
+	Workers    int
+	Visibility time.Duration
+	// Idle is how long workers sleep when the queue is empty.
+	Idle time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	wg       sync.WaitGroup
+}
+
+// NewPool creates a pool with sensible defaults: 4 workers, 5 minute
+// visibility timeout, 1 second idle poll.
+func NewPool(q Queue) *Pool {
+	return &Pool{
+		Queue:      q,
+		Workers:    4,
+		Visibility: 5 * time.Minute,
+		Idle:       time.Second,
+		handlers:   make(map[string]Handler),
+	}
+}
+
+// Handle registers the handler for a job type.
+func (p *Pool) Handle(jobType string, h Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[jobType] = h
+}
+
+// Start launches the workers; they exit when ctx is cancelled. Wait blocks
+// until all workers have returned.
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.Workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.work(ctx)
+		}()
+	}
+}
+
+// Wait blocks until every worker has exited.
+func (p *Pool) Wait() { p.wg.Wait() }
+
+func (p *Pool) work(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		job, err := p.Queue.Dequeue(ctx, p.Visibility)
+		if err != nil {
+			if err != ErrEmpty {
+				log.Printf("jobs: dequeue: %v", err)
+			}
+			select {
+			case <-time.After(p.Idle):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		p.dispatch(ctx, job)
+	}
+}
+
+func (p *Pool) dispatch(ctx context.Context, job *Job) {
+	p.mu.RLock()
+	h, ok := p.handlers[job.Type]
+	p.mu.RUnlock()
+	if !ok {
+		// No handler on this instance: release the claim so another
+		// instance (or a later deploy) can pick it up.
+		_ = p.Queue.Nack(ctx, job.ID)
+		return
+	}
+	if err := h(ctx, job); err != nil {
+		log.Printf("jobs: %s (%s) attempt %d: %v", job.ID, job.Type, job.Attempts, err)
+		_ = p.Queue.Nack(ctx, job.ID)
+		return
+	}
+	_ = p.Queue.Ack(ctx, job.ID)
+}
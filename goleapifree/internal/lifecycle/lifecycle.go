@@ -0,0 +1,208 @@
+// Package lifecycle proposes cleanup of configuration debris: models
+// that stopped seeing traffic and discovered provider candidates that
+// never passed a health check. Nothing is removed automatically — each
+// sweep produces a batch that an admin reviews and approves or rejects.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/biodoia/goleapifree/internal/discovery"
+)
+
+// defaultMaxIdle is the zero-traffic window after which a model is
+// proposed for cleanup.
+const defaultMaxIdle = 30 * 24 * time.Hour
+
+// Item kinds and batch states.
+const (
+	ItemModel     = "model"
+	ItemCandidate = "candidate"
+
+	StatusProposed = "proposed"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+	// StatusSuperseded marks a proposed batch replaced by a newer sweep
+	// before anyone decided on it.
+	StatusSuperseded = "superseded"
+)
+
+// Batch lookup and decision errors.
+var (
+	ErrBatchNotFound = errors.New("lifecycle: batch not found")
+	ErrBatchDecided  = errors.New("lifecycle: batch already decided")
+)
+
+// Item is one cleanup proposal inside a batch.
+type Item struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// Batch is one sweep's worth of proposals.
+type Batch struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Status    string    `json:"status"`
+	Items     []Item    `json:"items"`
+}
+
+// Sweeper runs the cleanup sweep and holds the resulting batches. The
+// zero value needs its source hooks set; unset hooks skip that item
+// kind, so a deployment without discovery still sweeps models.
+type Sweeper struct {
+	// MaxIdle is how long a model may go without traffic, and an
+	// unhealthy candidate without a sighting, before being proposed for
+	// cleanup. 0 applies the 30-day default.
+	MaxIdle time.Duration
+	// ModelTraffic reports the last request time per model.
+	ModelTraffic func() map[string]time.Time
+	// Candidates lists the discovered provider candidates.
+	Candidates func() []discovery.Candidate
+	// RemoveModel and RemoveCandidate apply one approved item each.
+	RemoveModel     func(model string) error
+	RemoveCandidate func(name string)
+
+	mu      sync.Mutex
+	batches map[string]*Batch
+	order   []string // batch IDs, oldest first
+	now     func() time.Time
+}
+
+// NewSweeper returns a sweeper with no batches yet.
+func NewSweeper() *Sweeper {
+	return &Sweeper{batches: make(map[string]*Batch), now: time.Now}
+}
+
+func (s *Sweeper) maxIdle() time.Duration {
+	if s.MaxIdle > 0 {
+		return s.MaxIdle
+	}
+	return defaultMaxIdle
+}
+
+// Run performs one sweep and files the result as a proposed batch,
+// superseding any earlier batch still awaiting a decision. A sweep that
+// finds nothing files no batch. Run matches scheduler.JobFunc.
+func (s *Sweeper) Run(ctx context.Context) error {
+	now := s.now()
+	cutoff := now.Add(-s.maxIdle())
+
+	var items []Item
+	if s.ModelTraffic != nil {
+		traffic := s.ModelTraffic()
+		models := make([]string, 0, len(traffic))
+		for model := range traffic {
+			models = append(models, model)
+		}
+		sort.Strings(models)
+		for _, model := range models {
+			if last := traffic[model]; last.Before(cutoff) {
+				items = append(items, Item{
+					Kind:   ItemModel,
+					ID:     model,
+					Reason: fmt.Sprintf("no traffic since %s", last.UTC().Format(time.RFC3339)),
+				})
+			}
+		}
+	}
+	if s.Candidates != nil {
+		for _, c := range s.Candidates() {
+			// An unhealthy candidate whose sightings dried up never made
+			// it past health checks and is no longer being announced.
+			if !c.Healthy && c.LastSeen.Before(cutoff) {
+				items = append(items, Item{
+					Kind:   ItemCandidate,
+					ID:     c.Name,
+					Reason: fmt.Sprintf("never healthy, last seen %s", c.LastSeen.UTC().Format(time.RFC3339)),
+				})
+			}
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, b := range s.batches {
+		if b.Status == StatusProposed {
+			b.Status = StatusSuperseded
+		}
+	}
+	b := &Batch{ID: uuid.NewString(), CreatedAt: now, Status: StatusProposed, Items: items}
+	s.batches[b.ID] = b
+	s.order = append(s.order, b.ID)
+	return nil
+}
+
+// Batches returns every batch, newest first.
+func (s *Sweeper) Batches() []Batch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Batch, 0, len(s.order))
+	for i := len(s.order) - 1; i >= 0; i-- {
+		out = append(out, *s.batches[s.order[i]])
+	}
+	return out
+}
+
+// Approve applies every item in a proposed batch through the removal
+// hooks. Individual removal failures are joined into the returned error;
+// the batch still counts as approved, since the remaining items were
+// removed.
+func (s *Sweeper) Approve(id string) (Batch, error) {
+	s.mu.Lock()
+	b, ok := s.batches[id]
+	if !ok {
+		s.mu.Unlock()
+		return Batch{}, ErrBatchNotFound
+	}
+	if b.Status != StatusProposed {
+		s.mu.Unlock()
+		return *b, ErrBatchDecided
+	}
+	b.Status = StatusApproved
+	applied := *b
+	s.mu.Unlock()
+
+	var errs []error
+	for _, item := range applied.Items {
+		switch item.Kind {
+		case ItemModel:
+			if s.RemoveModel != nil {
+				if err := s.RemoveModel(item.ID); err != nil {
+					errs = append(errs, fmt.Errorf("remove model %s: %w", item.ID, err))
+				}
+			}
+		case ItemCandidate:
+			if s.RemoveCandidate != nil {
+				s.RemoveCandidate(item.ID)
+			}
+		}
+	}
+	return applied, errors.Join(errs...)
+}
+
+// Reject marks a proposed batch as declined without touching anything.
+func (s *Sweeper) Reject(id string) (Batch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.batches[id]
+	if !ok {
+		return Batch{}, ErrBatchNotFound
+	}
+	if b.Status != StatusProposed {
+		return *b, ErrBatchDecided
+	}
+	b.Status = StatusRejected
+	return *b, nil
+}
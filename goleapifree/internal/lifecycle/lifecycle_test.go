@@ -0,0 +1,136 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/discovery"
+)
+
+func testSweeper(now time.Time) *Sweeper {
+	s := NewSweeper()
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestRunProposesIdleModelsAndStaleCandidates(t *testing.T) {
+	now := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	s := testSweeper(now)
+	s.ModelTraffic = func() map[string]time.Time {
+		return map[string]time.Time{
+			"gpt-4o":    now.Add(-time.Hour),
+			"old-model": now.Add(-60 * 24 * time.Hour),
+		}
+	}
+	s.Candidates = func() []discovery.Candidate {
+		return []discovery.Candidate{
+			{Name: "fresh", Healthy: false, LastSeen: now.Add(-time.Hour)},
+			{Name: "healthy", Healthy: true, LastSeen: now.Add(-90 * 24 * time.Hour)},
+			{Name: "dead", Healthy: false, LastSeen: now.Add(-90 * 24 * time.Hour)},
+		}
+	}
+
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	batches := s.Batches()
+	if len(batches) != 1 || batches[0].Status != StatusProposed {
+		t.Fatalf("batches = %+v", batches)
+	}
+	items := batches[0].Items
+	if len(items) != 2 {
+		t.Fatalf("items = %+v", items)
+	}
+	if items[0].Kind != ItemModel || items[0].ID != "old-model" {
+		t.Fatalf("item 0 = %+v", items[0])
+	}
+	if items[1].Kind != ItemCandidate || items[1].ID != "dead" {
+		t.Fatalf("item 1 = %+v", items[1])
+	}
+}
+
+func TestRunWithNothingToCleanFilesNoBatch(t *testing.T) {
+	now := time.Now()
+	s := testSweeper(now)
+	s.ModelTraffic = func() map[string]time.Time {
+		return map[string]time.Time{"gpt-4o": now}
+	}
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Batches(); len(got) != 0 {
+		t.Fatalf("batches = %+v", got)
+	}
+}
+
+func TestRunSupersedesUndecidedBatch(t *testing.T) {
+	now := time.Now()
+	s := testSweeper(now)
+	s.ModelTraffic = func() map[string]time.Time {
+		return map[string]time.Time{"old": now.Add(-60 * 24 * time.Hour)}
+	}
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	batches := s.Batches()
+	if len(batches) != 2 {
+		t.Fatalf("batches = %+v", batches)
+	}
+	if batches[0].Status != StatusProposed || batches[1].Status != StatusSuperseded {
+		t.Fatalf("statuses = %s, %s", batches[0].Status, batches[1].Status)
+	}
+}
+
+func TestApproveAppliesRemovals(t *testing.T) {
+	now := time.Now()
+	s := testSweeper(now)
+	s.ModelTraffic = func() map[string]time.Time {
+		return map[string]time.Time{"old": now.Add(-60 * 24 * time.Hour)}
+	}
+	s.Candidates = func() []discovery.Candidate {
+		return []discovery.Candidate{{Name: "dead", LastSeen: now.Add(-60 * 24 * time.Hour)}}
+	}
+	var removedModel, removedCandidate string
+	s.RemoveModel = func(m string) error { removedModel = m; return nil }
+	s.RemoveCandidate = func(n string) { removedCandidate = n }
+
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	id := s.Batches()[0].ID
+	b, err := s.Approve(id)
+	if err != nil || b.Status != StatusApproved {
+		t.Fatalf("Approve = %+v, %v", b, err)
+	}
+	if removedModel != "old" || removedCandidate != "dead" {
+		t.Fatalf("removed %q / %q", removedModel, removedCandidate)
+	}
+	if _, err := s.Approve(id); !errors.Is(err, ErrBatchDecided) {
+		t.Fatalf("second approve: %v", err)
+	}
+}
+
+func TestRejectLeavesEverythingAlone(t *testing.T) {
+	now := time.Now()
+	s := testSweeper(now)
+	s.ModelTraffic = func() map[string]time.Time {
+		return map[string]time.Time{"old": now.Add(-60 * 24 * time.Hour)}
+	}
+	s.RemoveModel = func(string) error { t.Fatal("removal ran on reject"); return nil }
+
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	id := s.Batches()[0].ID
+	if b, err := s.Reject(id); err != nil || b.Status != StatusRejected {
+		t.Fatalf("Reject = %+v, %v", b, err)
+	}
+	if _, err := s.Reject("missing"); !errors.Is(err, ErrBatchNotFound) {
+		t.Fatalf("missing batch: %v", err)
+	}
+}
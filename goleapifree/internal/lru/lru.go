@@ -0,0 +1,152 @@
+// Package lru provides the bounded LRU cache used to cap the gateway's
+// in-memory subsystems, plus a gauge registry so current usage is
+// observable. Long-running gateways track per-key limiter windows,
+// sessions and similar state that otherwise grows with every key ever
+// seen; capping them trades perfect recall for a bounded heap.
+package lru
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Stats is a point-in-time usage snapshot of one cache.
+type Stats struct {
+	Entries    int    `json:"entries"`
+	MaxEntries int    `json:"max_entries"` // 0 = unbounded
+	Evictions  uint64 `json:"evictions"`
+}
+
+// Cache is a mutex-guarded LRU map. A max of 0 means unbounded, which
+// keeps it usable as a drop-in map replacement until a cap is set.
+type Cache[K comparable, V any] struct {
+	// OnEvict, when set, is called for each evicted entry (outside any
+	// user-visible operation's return, but under the cache lock).
+	OnEvict func(K, V)
+
+	mu        sync.Mutex
+	max       int
+	ll        *list.List
+	items     map[K]*list.Element
+	evictions uint64
+}
+
+type entry[K comparable, V any] struct {
+	key K
+	val V
+}
+
+// New creates a cache holding at most max entries; max <= 0 is unbounded.
+func New[K comparable, V any](max int) *Cache[K, V] {
+	return &Cache[K, V]{
+		max:   max,
+		ll:    list.New(),
+		items: make(map[K]*list.Element),
+	}
+}
+
+// Get returns the value for key, marking it most recently used.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*entry[K, V]).val, true
+}
+
+// Put inserts or replaces the value for key, marking it most recently
+// used and evicting the least recently used entry when over the cap.
+func (c *Cache[K, V]) Put(key K, val V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*entry[K, V]).val = val
+		return
+	}
+	c.items[key] = c.ll.PushFront(&entry[K, V]{key: key, val: val})
+	c.evictOverCap()
+}
+
+// Remove deletes the entry for key, if present.
+func (c *Cache[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// Len returns the current number of entries.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// SetMax changes the cap, evicting immediately when the cache is over
+// the new bound; max <= 0 removes the cap.
+func (c *Cache[K, V]) SetMax(max int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.max = max
+	c.evictOverCap()
+}
+
+// Stats returns the cache's usage snapshot.
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{Entries: c.ll.Len(), MaxEntries: c.max, Evictions: c.evictions}
+}
+
+// evictOverCap evicts LRU entries until within the cap. Caller holds c.mu.
+func (c *Cache[K, V]) evictOverCap() {
+	for c.max > 0 && c.ll.Len() > c.max {
+		el := c.ll.Back()
+		if el == nil {
+			return
+		}
+		c.removeElement(el)
+		c.evictions++
+	}
+}
+
+func (c *Cache[K, V]) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	e := el.Value.(*entry[K, V])
+	delete(c.items, e.key)
+	if c.OnEvict != nil {
+		c.OnEvict(e.key, e.val)
+	}
+}
+
+// The gauge registry lets capped subsystems publish their usage under a
+// stable name; the admin API serves the combined snapshot.
+var (
+	gaugesMu sync.Mutex
+	gauges   = make(map[string]func() Stats)
+)
+
+// RegisterGauge publishes a usage gauge under name, replacing any
+// previous registration with that name.
+func RegisterGauge(name string, f func() Stats) {
+	gaugesMu.Lock()
+	defer gaugesMu.Unlock()
+	gauges[name] = f
+}
+
+// Gauges returns the current snapshot of every registered gauge.
+func Gauges() map[string]Stats {
+	gaugesMu.Lock()
+	defer gaugesMu.Unlock()
+	out := make(map[string]Stats, len(gauges))
+	for name, f := range gauges {
+		out[name] = f()
+	}
+	return out
+}
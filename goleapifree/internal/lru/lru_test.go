@@ -0,0 +1,70 @@
+package lru
+
+import "testing"
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string, int](2)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a missing before eviction")
+	}
+	c.Put("c", 3) // b is now the LRU entry
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("b should have been evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("recently used a was evicted")
+	}
+	if got := c.Stats(); got.Entries != 2 || got.Evictions != 1 {
+		t.Fatalf("stats = %+v", got)
+	}
+}
+
+func TestUnboundedUntilCapped(t *testing.T) {
+	c := New[int, int](0)
+	for i := 0; i < 100; i++ {
+		c.Put(i, i)
+	}
+	if c.Len() != 100 {
+		t.Fatalf("len = %d", c.Len())
+	}
+	c.SetMax(10)
+	if c.Len() != 10 {
+		t.Fatalf("len after cap = %d", c.Len())
+	}
+	// The newest entries survive the shrink.
+	if _, ok := c.Get(99); !ok {
+		t.Fatal("newest entry evicted by SetMax")
+	}
+}
+
+func TestOnEvictAndRemove(t *testing.T) {
+	var evicted []string
+	c := New[string, int](1)
+	c.OnEvict = func(k string, _ int) { evicted = append(evicted, k) }
+	c.Put("a", 1)
+	c.Put("b", 2)
+	if len(evicted) != 1 || evicted[0] != "a" {
+		t.Fatalf("evicted = %v", evicted)
+	}
+	c.Remove("b")
+	if c.Len() != 0 {
+		t.Fatalf("len = %d", c.Len())
+	}
+	// Remove is not an eviction.
+	if got := c.Stats().Evictions; got != 1 {
+		t.Fatalf("evictions = %d", got)
+	}
+}
+
+func TestGaugeRegistry(t *testing.T) {
+	c := New[string, int](5)
+	c.Put("a", 1)
+	RegisterGauge("test.cache", c.Stats)
+	got := Gauges()["test.cache"]
+	if got.Entries != 1 || got.MaxEntries != 5 {
+		t.Fatalf("gauge = %+v", got)
+	}
+}
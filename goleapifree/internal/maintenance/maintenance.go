@@ -0,0 +1,185 @@
+// Package maintenance runs database housekeeping (reindex, vacuum, log
+// pruning, orphan cleanup) asynchronously with progress reporting for the
+// admin API.
+package maintenance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Dialects select the statements an operation runs.
+const (
+	DialectSQLite   = "sqlite"
+	DialectPostgres = "postgres"
+)
+
+// Operation names accepted by Run.
+const (
+	OpReindex   = "reindex"
+	OpVacuum    = "vacuum"
+	OpPruneLogs = "prune_logs"
+	OpOrphans   = "orphan_cleanup"
+)
+
+// ErrBusy is returned when an operation is already running; maintenance
+// work is serialized to avoid compounding load on the database.
+var ErrBusy = errors.New("maintenance: operation already running")
+
+// ErrUnknownOperation is returned for operation names Run does not know.
+var ErrUnknownOperation = errors.New("maintenance: unknown operation")
+
+// States of the current/last operation.
+const (
+	StateIdle    = "idle"
+	StateRunning = "running"
+	StateDone    = "done"
+	StateFailed  = "failed"
+)
+
+// Status reports the current or most recent operation for the admin API.
+type Status struct {
+	Operation  string    `json:"operation,omitempty"`
+	State      string    `json:"state"`
+	Step       string    `json:"step,omitempty"`
+	StartedAt  time.Time `json:"started_at,omitempty"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	// RowsAffected sums the rows touched by cleanup statements.
+	RowsAffected int64 `json:"rows_affected"`
+}
+
+// Manager serializes maintenance operations against one database.
+type Manager struct {
+	Dialect string
+	// LogRetention bounds prune_logs; defaults to 30 days.
+	LogRetention time.Duration
+
+	// exec runs one statement and reports affected rows; split out from
+	// *sql.DB so plans are testable without a live database.
+	exec func(ctx context.Context, stmt string) (int64, error)
+
+	mu      sync.Mutex
+	status  Status
+	running bool
+}
+
+// New creates a manager executing against db.
+func New(db *sql.DB, dialect string) *Manager {
+	m := &Manager{Dialect: dialect, LogRetention: 30 * 24 * time.Hour}
+	m.exec = func(ctx context.Context, stmt string) (int64, error) {
+		res, err := db.ExecContext(ctx, stmt)
+		if err != nil {
+			return 0, err
+		}
+		// Some statements (VACUUM) report no row count; ignore that.
+		n, _ := res.RowsAffected()
+		return n, nil
+	}
+	m.status = Status{State: StateIdle}
+	return m
+}
+
+// plan returns the statements an operation runs for the manager's
+// dialect, labelled for progress reporting.
+func (m *Manager) plan(op string) ([][2]string, error) {
+	switch op {
+	case OpReindex:
+		if m.Dialect == DialectPostgres {
+			return [][2]string{{"reindex database", "REINDEX DATABASE CONCURRENTLY goleapai"}}, nil
+		}
+		return [][2]string{{"reindex", "REINDEX"}, {"analyze", "ANALYZE"}}, nil
+	case OpVacuum:
+		if m.Dialect == DialectPostgres {
+			return [][2]string{{"vacuum analyze", "VACUUM (ANALYZE)"}}, nil
+		}
+		return [][2]string{{"vacuum", "VACUUM"}}, nil
+	case OpPruneLogs:
+		cutoff := fmt.Sprintf("-%d seconds", int(m.LogRetention.Seconds()))
+		stmt := fmt.Sprintf("DELETE FROM request_logs WHERE created_at < datetime('now', '%s')", cutoff)
+		if m.Dialect == DialectPostgres {
+			stmt = fmt.Sprintf("DELETE FROM request_logs WHERE created_at < now() - interval '%d seconds'", int(m.LogRetention.Seconds()))
+		}
+		return [][2]string{{"prune request logs", stmt}}, nil
+	case OpOrphans:
+		return [][2]string{
+			{"orphaned accounts", "DELETE FROM accounts WHERE provider_id NOT IN (SELECT id FROM providers)"},
+			{"orphaned rate limits", "DELETE FROM rate_limits WHERE account_id NOT IN (SELECT id FROM accounts)"},
+			{"orphaned alias targets", "DELETE FROM alias_targets WHERE provider_id NOT IN (SELECT id FROM providers)"},
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownOperation, op)
+	}
+}
+
+// Run starts an operation asynchronously. It returns ErrBusy when one is
+// already in flight; progress is observable through GetStatus.
+func (m *Manager) Run(ctx context.Context, op string) error {
+	steps, err := m.plan(op)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return ErrBusy
+	}
+	m.running = true
+	m.status = Status{Operation: op, State: StateRunning, StartedAt: time.Now()}
+	m.mu.Unlock()
+
+	go m.execute(ctx, op, steps)
+	return nil
+}
+
+func (m *Manager) execute(ctx context.Context, op string, steps [][2]string) {
+	var rows int64
+	for _, step := range steps {
+		m.mu.Lock()
+		m.status.Step = step[0]
+		m.mu.Unlock()
+
+		n, err := m.exec(ctx, step[1])
+		if err != nil {
+			m.finish(StateFailed, rows, fmt.Sprintf("%s: %v", step[0], err))
+			return
+		}
+		rows += n
+	}
+	m.finish(StateDone, rows, "")
+}
+
+func (m *Manager) finish(state string, rows int64, errMsg string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running = false
+	m.status.State = state
+	m.status.Step = ""
+	m.status.FinishedAt = time.Now()
+	m.status.RowsAffected = rows
+	m.status.Error = errMsg
+}
+
+// GetStatus returns the current or last operation's status.
+func (m *Manager) GetStatus() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// Wait blocks until no operation is running; used by tests and shutdown.
+func (m *Manager) Wait() {
+	for {
+		m.mu.Lock()
+		running := m.running
+		m.mu.Unlock()
+		if !running {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,87 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fakeManager(dialect string, exec func(stmt string) (int64, error)) *Manager {
+	m := &Manager{Dialect: dialect, LogRetention: 30 * 24 * time.Hour, status: Status{State: StateIdle}}
+	m.exec = func(_ context.Context, stmt string) (int64, error) { return exec(stmt) }
+	return m
+}
+
+func TestRunReportsProgressAndRows(t *testing.T) {
+	var stmts []string
+	m := fakeManager(DialectSQLite, func(stmt string) (int64, error) {
+		stmts = append(stmts, stmt)
+		return 3, nil
+	})
+	if err := m.Run(context.Background(), OpOrphans); err != nil {
+		t.Fatal(err)
+	}
+	m.Wait()
+	st := m.GetStatus()
+	if st.State != StateDone || st.RowsAffected != 9 {
+		t.Fatalf("status = %+v", st)
+	}
+	if len(stmts) != 3 || !strings.Contains(stmts[0], "accounts") {
+		t.Fatalf("stmts = %v", stmts)
+	}
+}
+
+func TestRunRejectsConcurrentOperations(t *testing.T) {
+	block := make(chan struct{})
+	m := fakeManager(DialectPostgres, func(string) (int64, error) {
+		<-block
+		return 0, nil
+	})
+	if err := m.Run(context.Background(), OpVacuum); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Run(context.Background(), OpReindex); !errors.Is(err, ErrBusy) {
+		t.Fatalf("err = %v, want ErrBusy", err)
+	}
+	close(block)
+	m.Wait()
+}
+
+func TestRunFailureSurfacesStep(t *testing.T) {
+	m := fakeManager(DialectSQLite, func(string) (int64, error) {
+		return 0, errors.New("disk full")
+	})
+	if err := m.Run(context.Background(), OpVacuum); err != nil {
+		t.Fatal(err)
+	}
+	m.Wait()
+	st := m.GetStatus()
+	if st.State != StateFailed || !strings.Contains(st.Error, "disk full") {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+func TestUnknownOperation(t *testing.T) {
+	m := fakeManager(DialectSQLite, nil)
+	if err := m.Run(context.Background(), "defrag"); !errors.Is(err, ErrUnknownOperation) {
+		t.Fatalf("err = %v, want ErrUnknownOperation", err)
+	}
+}
+
+func TestPruneLogsUsesDialectInterval(t *testing.T) {
+	var got string
+	m := fakeManager(DialectPostgres, func(stmt string) (int64, error) {
+		got = stmt
+		return 0, nil
+	})
+	m.LogRetention = time.Hour
+	if err := m.Run(context.Background(), OpPruneLogs); err != nil {
+		t.Fatal(err)
+	}
+	m.Wait()
+	if !strings.Contains(got, "interval '3600 seconds'") {
+		t.Fatalf("stmt = %q", got)
+	}
+}
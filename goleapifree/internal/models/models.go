@@ -0,0 +1,182 @@
+// Package models defines the core domain types shared across the gateway:
+// providers, accounts, model aliases and their rate limit configuration.
+package models
+
+import "time"
+
+// Provider statuses. The zero value is a fully routable provider; trial
+// providers receive only synthetic probes and a small shadow of real
+// traffic until the trial manager promotes them.
+const (
+	ProviderStatusActive = ""
+	ProviderStatusTrial  = "trial"
+)
+
+// Provider represents an upstream LLM API provider (OpenAI-compatible or
+// otherwise) that the gateway can route requests to.
+type Provider struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	BaseURL string `json:"base_url"`
+	Type    string `json:"type"` // "openai", "anthropic", "ollama", ...
+	Enabled bool   `json:"enabled"`
+	// Status gates how much traffic the provider may see; see the
+	// ProviderStatus constants.
+	Status   string `json:"status,omitempty"`
+	Priority int    `json:"priority"`
+	// Version increments on every update and backs the optimistic
+	// concurrency check on admin mutations.
+	Version int64 `json:"version"`
+	// DataPolicy describes how the provider handles request data; nil
+	// means the policy is unknown, which strict tenant constraints
+	// treat as non-compliant.
+	DataPolicy *DataPolicy `json:"data_policy,omitempty"`
+	// TLS configures transport security toward the provider; nil uses
+	// the system defaults.
+	TLS *TLSOptions `json:"tls,omitempty"`
+	// LanguageQuality scores the provider's output quality per natural
+	// language (ISO 639-1 code -> [0,1]). The multilingual prior reads
+	// it when routing non-English prompts; languages without a score
+	// are assumed mid-pack.
+	LanguageQuality map[string]float64 `json:"language_quality,omitempty"`
+	// ReasoningCapable marks providers that serve reasoning models
+	// (effort levels or thinking budgets). The reasoning exclusion hook
+	// drops providers without it when a request asks for reasoning.
+	ReasoningCapable bool      `json:"reasoning_capable,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TLSOptions covers self-hosted providers behind private CAs or pinned
+// certificates. The provider transport factory turns these into the
+// upstream client's TLS configuration.
+type TLSOptions struct {
+	// CABundle is a path to a PEM file that replaces the system roots
+	// when verifying the provider's certificate.
+	CABundle string `json:"ca_bundle,omitempty"`
+	// PinnedFingerprints are hex SHA-256 digests of acceptable
+	// certificates (an optional "sha256:" prefix and colons are
+	// tolerated). When set, the presented chain must contain a match.
+	PinnedFingerprints []string `json:"pinned_fingerprints,omitempty"`
+	// InsecureSkipVerify disables certificate verification entirely.
+	// The factory logs a loud warning; pinning still applies if set.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+}
+
+// DataPolicy captures a provider's data-handling attributes, sourced
+// from its published terms.
+type DataPolicy struct {
+	// TrainsOnData is true when the provider may train models on
+	// submitted prompts (common on free tiers).
+	TrainsOnData bool `json:"trains_on_data"`
+	// Region is where request data is processed ("eu", "us", ...).
+	Region string `json:"region,omitempty"`
+	// RetentionDays is how long the provider retains request data;
+	// 0 means no stated retention.
+	RetentionDays int `json:"retention_days,omitempty"`
+}
+
+// Account is a single credential set for a provider. Free tiers are usually
+// enforced per account, so limits and quotas hang off the account rather
+// than the provider.
+type Account struct {
+	ID         string `json:"id"`
+	ProviderID string `json:"provider_id"`
+	Label      string `json:"label"`
+	APIKey     string `json:"-"`
+	// NextAPIKey is a staged replacement credential (blue/green key
+	// rotation). It takes no traffic until a verification probe passes
+	// and the rotation commits; like APIKey it never serializes.
+	NextAPIKey string     `json:"-"`
+	Enabled    bool       `json:"enabled"`
+	RateLimit  *RateLimit `json:"rate_limit,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// RateLimit describes the limits a provider enforces on one account.
+// Zero values mean "no limit of that kind".
+type RateLimit struct {
+	// RequestsPerMinute caps request rate.
+	RequestsPerMinute int `json:"requests_per_minute"`
+	// RequestsPerDay caps daily volume (common on free tiers).
+	RequestsPerDay int `json:"requests_per_day"`
+	// TokensPerMinute caps token throughput.
+	TokensPerMinute int `json:"tokens_per_minute"`
+	// MaxConcurrent caps in-flight requests. Some providers throttle on
+	// concurrency rather than request rate; 0 disables the check.
+	MaxConcurrent int `json:"max_concurrent"`
+}
+
+// RequestLog is the persisted record of one proxied request, used by
+// stats queries and exports.
+type RequestLog struct {
+	ID         string `json:"id"`
+	ProviderID string `json:"provider_id"`
+	Tenant     string `json:"tenant,omitempty"`
+	Model      string `json:"model"`
+	Tokens     int64  `json:"tokens"`
+	// ReasoningTokens is the share of Tokens a reasoning model spent on
+	// hidden chain-of-thought, accounted separately so cost reports can
+	// split thinking from visible output. 0 for non-reasoning traffic.
+	ReasoningTokens int64   `json:"reasoning_tokens,omitempty"`
+	Cost            float64 `json:"cost"`
+	LatencyMs       float64 `json:"latency_ms"`
+	// Metadata holds client-supplied tags (feature name, ticket ID,
+	// environment, ...) for cost attribution; keys the client sends in
+	// the request's `metadata` object are stored verbatim.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Language is the programming language detected in the prompt's code
+	// blocks, for coding traffic; empty for everything else. It drives
+	// per-language routing preferences and language-level stats.
+	Language string `json:"language,omitempty"`
+	// Safety holds the brand-safety scores of the response, one [0,1]
+	// score per category ("profanity", "violence", ...). Populated by
+	// the local safety scorer; empty when scoring is disabled.
+	Safety map[string]float64 `json:"safety,omitempty"`
+	// Prompt and Response are the request/response bodies. Whether they
+	// are stored at all, and for how long, is governed by the tenant's
+	// retention policy — accounting fields above are unaffected.
+	Prompt    string    `json:"prompt,omitempty"`
+	Response  string    `json:"response,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ModelAlias maps a stable public model name to one or more concrete
+// provider models, so clients are insulated from provider churn.
+type ModelAlias struct {
+	Alias   string        `json:"alias"`
+	Targets []AliasTarget `json:"targets"`
+	// Cache overrides the response cache policy for this alias; nil
+	// applies the gateway default (cache deterministic requests only).
+	Cache *CachePolicy `json:"cache,omitempty"`
+	// Version supports optimistic concurrency on runtime weight edits,
+	// mirroring Provider.Version.
+	Version int64 `json:"version,omitempty"`
+}
+
+// Cache modes selectable per alias. Some aliases serve deterministic
+// lookup-style traffic worth caching aggressively; others are creative
+// and must never return a canned answer.
+const (
+	// CacheDeterministic caches only temperature-zero requests; the
+	// default when an alias has no policy.
+	CacheDeterministic = "deterministic"
+	// CacheAlways caches regardless of sampling parameters.
+	CacheAlways = "always"
+	// CacheNever disables caching for the alias.
+	CacheNever = "never"
+)
+
+// CachePolicy is one alias's response caching directive.
+type CachePolicy struct {
+	Mode string `json:"mode,omitempty"`
+	// TTLSeconds overrides the cache's default entry lifetime; 0 keeps it.
+	TTLSeconds int `json:"ttl_seconds,omitempty"`
+}
+
+// AliasTarget is one concrete (provider, model) pair behind an alias.
+type AliasTarget struct {
+	ProviderID string  `json:"provider_id"`
+	Model      string  `json:"model"`
+	Weight     float64 `json:"weight"`
+}
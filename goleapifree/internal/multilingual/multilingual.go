@@ -0,0 +1,168 @@
+// Package multilingual detects the natural language of a prompt and
+// steers routing toward providers that handle it well. For languages a
+// chosen model is weak in, an optional pivot translator rewrites the
+// conversation to English before dispatch and translates the answer
+// back, which beats a fluent-sounding but wrong native reply.
+package multilingual
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// English is the pivot language; prompts detected as English never
+// trigger quality-based reordering or translation.
+const English = "en"
+
+// stopwords are high-frequency function words per Latin-script
+// language. Two hits are required before a language tags, the same
+// bar the coding-language heuristic uses, so short or mixed prompts
+// stay unclassified rather than misclassified.
+var stopwords = map[string][]string{
+	"en": {" the ", " and ", " is ", " you ", " with "},
+	"it": {" il ", " che ", " di ", " per ", " sono "},
+	"es": {" el ", " que ", " los ", " por ", " una "},
+	"fr": {" le ", " les ", " est ", " pour ", " dans "},
+	"de": {" der ", " und ", " ist ", " nicht ", " eine "},
+	"pt": {" o ", " que ", " para ", " uma ", " não "},
+}
+
+// DetectLanguage returns the ISO 639-1 code of the text's natural
+// language, or "" when none is apparent. Non-Latin scripts are
+// decisive on their own; Latin-script languages fall back to a
+// stopword heuristic.
+func DetectLanguage(text string) string {
+	if lang := detectScript(text); lang != "" {
+		return lang
+	}
+	padded := " " + strings.ToLower(text) + " "
+	best, bestHits := "", 1
+	for lang, words := range stopwords {
+		hits := 0
+		for _, w := range words {
+			if strings.Contains(padded, w) {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = lang, hits
+		}
+	}
+	return best
+}
+
+// detectScript classifies by Unicode script when a meaningful share of
+// the runes is non-Latin; a stray emoji or borrowed word does not tag.
+func detectScript(text string) string {
+	counts := map[string]int{}
+	total := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		total++
+		switch {
+		case unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r):
+			counts["ja"]++
+		case unicode.Is(unicode.Hangul, r):
+			counts["ko"]++
+		case unicode.Is(unicode.Han, r):
+			counts["zh"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts["ru"]++
+		case unicode.Is(unicode.Arabic, r):
+			counts["ar"]++
+		case unicode.Is(unicode.Greek, r):
+			counts["el"]++
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+	for lang, n := range counts {
+		if n*4 >= total { // at least a quarter of the letters
+			// Han characters inside Japanese text must not flip the
+			// verdict to Chinese.
+			if lang == "zh" && counts["ja"] > 0 {
+				continue
+			}
+			return lang
+		}
+	}
+	return ""
+}
+
+// QualityPrior builds a router prior from per-provider language
+// quality scores: a provider's score for the detected language is its
+// priority, unscored providers sit at a neutral 0.5. It returns nil
+// for English or undetected prompts so callers keep the default prior.
+func QualityPrior(lang string, quality func(providerID string) map[string]float64) func(providerID string) float64 {
+	if lang == "" || lang == English || quality == nil {
+		return nil
+	}
+	return func(providerID string) float64 {
+		if score, ok := quality(providerID)[lang]; ok {
+			return score
+		}
+		return 0.5
+	}
+}
+
+// Translator pivots weak-language requests through English.
+type Translator struct {
+	// Translate rewrites text from one language to another; any decent
+	// model behind the gateway works, wired in as a closure.
+	Translate func(ctx context.Context, text, from, to string) (string, error)
+	// Threshold is the language quality below which pivoting kicks in;
+	// 0 disables pivoting entirely.
+	Threshold float64
+}
+
+// ShouldPivot reports whether a request in lang should be translated
+// before reaching a provider with the given quality scores. Providers
+// without a score for the language are trusted as-is: pivoting costs
+// two extra model calls, so only a known weakness justifies it.
+func (t *Translator) ShouldPivot(lang string, quality map[string]float64) bool {
+	if t == nil || t.Translate == nil || t.Threshold <= 0 {
+		return false
+	}
+	if lang == "" || lang == English {
+		return false
+	}
+	score, ok := quality[lang]
+	return ok && score < t.Threshold
+}
+
+// ToEnglish returns a copy of the request with user messages
+// translated to English. System prompts pass through untouched; they
+// are authored by operators and already in whatever language they
+// intend.
+func (t *Translator) ToEnglish(ctx context.Context, req providers.ChatRequest, lang string) (providers.ChatRequest, error) {
+	msgs := make([]providers.Message, len(req.Messages))
+	copy(msgs, req.Messages)
+	for i, m := range msgs {
+		if m.Role != "user" {
+			continue
+		}
+		translated, err := t.Translate(ctx, m.Content, lang, English)
+		if err != nil {
+			return providers.ChatRequest{}, err
+		}
+		msgs[i].Content = translated
+	}
+	req.Messages = msgs
+	return req, nil
+}
+
+// FromEnglish translates a response back to the prompt's language.
+func (t *Translator) FromEnglish(ctx context.Context, resp providers.ChatResponse, lang string) (providers.ChatResponse, error) {
+	translated, err := t.Translate(ctx, resp.Content, English, lang)
+	if err != nil {
+		return providers.ChatResponse{}, err
+	}
+	resp.Content = translated
+	return resp, nil
+}
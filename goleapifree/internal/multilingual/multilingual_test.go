@@ -0,0 +1,95 @@
+package multilingual
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	for _, tc := range []struct{ text, want string }{
+		{"Spiegami il codice che ho scritto per la pipeline", "it"},
+		{"Explain the code and tell me what is wrong with it", "en"},
+		{"Explique le code pour les tests dans ce projet", "fr"},
+		{"Почему этот код не работает на сервере", "ru"},
+		{"このコードを説明してください", "ja"},
+		{"请解释这段代码的作用", "zh"},
+		{"x = 1", ""},
+	} {
+		if got := DetectLanguage(tc.text); got != tc.want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestQualityPrior(t *testing.T) {
+	quality := func(providerID string) map[string]float64 {
+		switch providerID {
+		case "poly":
+			return map[string]float64{"it": 0.9}
+		case "mono":
+			return map[string]float64{"it": 0.2}
+		default:
+			return nil
+		}
+	}
+	prior := QualityPrior("it", quality)
+	if prior == nil {
+		t.Fatal("prior not built for non-English language")
+	}
+	if prior("poly") != 0.9 || prior("mono") != 0.2 {
+		t.Fatalf("prior = %v / %v", prior("poly"), prior("mono"))
+	}
+	if prior("unknown") != 0.5 {
+		t.Fatalf("unscored provider = %v, want neutral 0.5", prior("unknown"))
+	}
+	if QualityPrior("en", quality) != nil || QualityPrior("", quality) != nil {
+		t.Fatal("English prompts must keep the default prior")
+	}
+}
+
+func TestPivotRoundTrip(t *testing.T) {
+	tr := &Translator{
+		Threshold: 0.6,
+		Translate: func(_ context.Context, text, from, to string) (string, error) {
+			return "[" + from + ">" + to + "] " + text, nil
+		},
+	}
+	if !tr.ShouldPivot("it", map[string]float64{"it": 0.3}) {
+		t.Fatal("weak provider should pivot")
+	}
+	if tr.ShouldPivot("it", map[string]float64{"it": 0.8}) {
+		t.Fatal("strong provider must not pivot")
+	}
+	if tr.ShouldPivot("it", nil) {
+		t.Fatal("unscored provider must not pivot")
+	}
+
+	req := providers.ChatRequest{Messages: []providers.Message{
+		{Role: "system", Content: "Sei un assistente."},
+		{Role: "user", Content: "Spiega questo codice"},
+	}}
+	out, err := tr.ToEnglish(context.Background(), req, "it")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Messages[0].Content != "Sei un assistente." {
+		t.Fatalf("system prompt translated: %q", out.Messages[0].Content)
+	}
+	if !strings.HasPrefix(out.Messages[1].Content, "[it>en]") {
+		t.Fatalf("user message not pivoted: %q", out.Messages[1].Content)
+	}
+	if req.Messages[1].Content != "Spiega questo codice" {
+		t.Fatal("original request mutated")
+	}
+
+	resp, err := tr.FromEnglish(context.Background(), providers.ChatResponse{Content: "answer"}, "it")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Content != "[en>it] answer" {
+		t.Fatalf("back-translation = %q", resp.Content)
+	}
+}
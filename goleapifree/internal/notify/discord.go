@@ -0,0 +1,199 @@
+//go:build !edge
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// discordAPI is the default REST endpoint; tests point it at a fake.
+const discordAPI = "https://discord.com/api/v10"
+
+// Discord is the optional community bot: it posts alerts into a channel
+// and answers `!stats today` / `!ask <prompt>` from channel members. It
+// works over plain REST polling — no gateway websocket — so it fits the
+// scheduler like every other periodic job. Answers to `!ask` go through
+// the injected hook, which the wiring binds to a restricted API key.
+type Discord struct {
+	Token     string
+	ChannelID string
+	// BaseURL overrides the Discord API endpoint; empty uses the real one.
+	BaseURL string
+	// Client performs the REST calls; nil uses http.DefaultClient.
+	Client *http.Client
+
+	// Stats renders the `!stats today` reply for the given day.
+	Stats func(day time.Time) string
+	// Ask answers an `!ask` prompt; the wiring routes it through a
+	// restricted API key so the bot cannot spend the whole budget.
+	Ask func(ctx context.Context, prompt string) (string, error)
+
+	now func() time.Time
+
+	mu     sync.Mutex
+	lastID string
+}
+
+// NewDiscord creates a bot for one channel.
+func NewDiscord(token, channelID string) *Discord {
+	return &Discord{Token: token, ChannelID: channelID, now: time.Now}
+}
+
+// PostAlert sends one message to the bot's channel.
+func (d *Discord) PostAlert(ctx context.Context, text string) error {
+	return d.post(ctx, text)
+}
+
+// discordMessage is the slice of the API's message object the bot reads.
+type discordMessage struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+	Author  struct {
+		Bot bool `json:"bot"`
+	} `json:"author"`
+}
+
+// Poll fetches messages since the last poll and answers any commands.
+// Its signature matches the scheduler's JobFunc. The first poll only
+// records the cursor so a restarted bot does not replay old commands.
+func (d *Discord) Poll(ctx context.Context) error {
+	d.mu.Lock()
+	after := d.lastID
+	d.mu.Unlock()
+
+	messages, err := d.fetch(ctx, after)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	// The API returns newest first; answer in arrival order.
+	d.mu.Lock()
+	d.lastID = messages[0].ID
+	d.mu.Unlock()
+	if after == "" {
+		return nil
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
+		if msg.Author.Bot {
+			continue
+		}
+		reply, err := d.answer(ctx, msg.Content)
+		if err != nil {
+			return err
+		}
+		if reply == "" {
+			continue
+		}
+		if err := d.post(ctx, reply); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// answer dispatches one channel message, returning "" for non-commands.
+func (d *Discord) answer(ctx context.Context, content string) (string, error) {
+	switch {
+	case strings.TrimSpace(content) == "!stats today":
+		if d.Stats == nil {
+			return "stats are not wired up on this gateway", nil
+		}
+		return d.Stats(d.now()), nil
+	case strings.HasPrefix(content, "!ask "):
+		prompt := strings.TrimSpace(strings.TrimPrefix(content, "!ask "))
+		if prompt == "" {
+			return "usage: !ask <prompt>", nil
+		}
+		if d.Ask == nil {
+			return "ask is not wired up on this gateway", nil
+		}
+		reply, err := d.Ask(ctx, prompt)
+		if err != nil {
+			return fmt.Sprintf("sorry, that failed: %v", err), nil
+		}
+		return reply, nil
+	default:
+		return "", nil
+	}
+}
+
+// fetch lists channel messages after the cursor, newest first.
+func (d *Discord) fetch(ctx context.Context, after string) ([]discordMessage, error) {
+	endpoint := fmt.Sprintf("%s/channels/%s/messages?limit=50", d.baseURL(), d.ChannelID)
+	if after != "" {
+		endpoint += "&after=" + url.QueryEscape(after)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := d.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var messages []discordMessage
+	if err := json.NewDecoder(resp.Body).Decode(&messages); err != nil {
+		return nil, fmt.Errorf("notify: discord messages: %w", err)
+	}
+	return messages, nil
+}
+
+// post creates one message in the channel.
+func (d *Discord) post(ctx context.Context, content string) error {
+	raw, err := json.Marshal(map[string]string{"content": content})
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/channels/%s/messages", d.baseURL(), d.ChannelID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := d.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (d *Discord) do(req *http.Request) (*http.Response, error) {
+	if d.Token == "" || d.ChannelID == "" {
+		return nil, errors.New("notify: discord bot needs a token and channel")
+	}
+	req.Header.Set("Authorization", "Bot "+d.Token)
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("notify: discord API returned %s", resp.Status)
+	}
+	return resp, nil
+}
+
+func (d *Discord) baseURL() string {
+	if d.BaseURL != "" {
+		return d.BaseURL
+	}
+	return discordAPI
+}
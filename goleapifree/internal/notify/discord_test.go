@@ -0,0 +1,116 @@
+//go:build !edge
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDiscord serves the two REST calls the bot makes.
+type fakeDiscord struct {
+	messages []discordMessage // newest first
+	posted   []string
+	auth     string
+}
+
+func (f *fakeDiscord) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.auth = r.Header.Get("Authorization")
+		if r.Method == http.MethodPost {
+			var body struct {
+				Content string `json:"content"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			f.posted = append(f.posted, body.Content)
+			fmt.Fprint(w, `{"id":"new"}`)
+			return
+		}
+		after := r.URL.Query().Get("after")
+		var out []discordMessage
+		for _, m := range f.messages {
+			if m.ID > after {
+				out = append(out, m)
+			}
+		}
+		json.NewEncoder(w).Encode(out)
+	})
+}
+
+func newBot(t *testing.T, f *fakeDiscord) *Discord {
+	t.Helper()
+	srv := httptest.NewServer(f.handler())
+	t.Cleanup(srv.Close)
+	d := NewDiscord("tok", "chan-1")
+	d.BaseURL = srv.URL
+	return d
+}
+
+func TestDiscordAnswersCommands(t *testing.T) {
+	f := &fakeDiscord{messages: []discordMessage{{ID: "1", Content: "hello all"}}}
+	d := newBot(t, f)
+	d.Stats = func(day time.Time) string { return "today: 420 requests, $1.23" }
+	d.Ask = func(_ context.Context, prompt string) (string, error) {
+		return "echo: " + prompt, nil
+	}
+
+	// First poll only sets the cursor; old messages are not replayed.
+	if err := d.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.posted) != 0 {
+		t.Fatalf("first poll replied: %v", f.posted)
+	}
+
+	bot := discordMessage{ID: "4", Content: "!stats today"}
+	bot.Author.Bot = true
+	f.messages = append([]discordMessage{
+		bot,
+		{ID: "3", Content: "!ask what is an alias?"},
+		{ID: "2", Content: "!stats today"},
+	}, f.messages...)
+	if err := d.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.posted) != 2 {
+		t.Fatalf("replies = %v", f.posted)
+	}
+	if f.posted[0] != "today: 420 requests, $1.23" || f.posted[1] != "echo: what is an alias?" {
+		t.Fatalf("replies = %v", f.posted)
+	}
+	if f.auth != "Bot tok" {
+		t.Fatalf("auth = %q", f.auth)
+	}
+
+	// Nothing new: no duplicate answers.
+	if err := d.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.posted) != 2 {
+		t.Fatalf("duplicate replies: %v", f.posted)
+	}
+}
+
+func TestDiscordPostAlert(t *testing.T) {
+	f := &fakeDiscord{}
+	d := newBot(t, f)
+	if err := d.PostAlert(context.Background(), "provider openai quarantined"); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.posted) != 1 || !strings.Contains(f.posted[0], "quarantined") {
+		t.Fatalf("posted = %v", f.posted)
+	}
+}
+
+func TestDiscordRequiresCredentials(t *testing.T) {
+	d := NewDiscord("", "")
+	if err := d.PostAlert(context.Background(), "x"); err == nil {
+		t.Fatal("missing credentials accepted")
+	}
+}
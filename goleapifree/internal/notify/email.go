@@ -0,0 +1,312 @@
+// Package notify delivers gateway notifications — alerts, scheduled
+// reports, invitations, quota warnings — over operator-configured
+// channels. Senders are deliberately dumb pipes: what to send and when is
+// the caller's business.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+)
+
+// maxStatuses bounds the in-memory delivery history.
+const maxStatuses = 1000
+
+// ErrNoTemplate is returned when sending with an unregistered template.
+var ErrNoTemplate = errors.New("notify: template not registered")
+
+// EmailConfig connects the sender to one SMTP server.
+type EmailConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"-"`
+	// From is the default sender address; tenants can override it.
+	From string `json:"from"`
+	// PoolSize caps idle SMTP sessions kept open between sends;
+	// defaults to 2.
+	PoolSize int `json:"pool_size"`
+}
+
+// smtpSession is the slice of *smtp.Client the sender uses, split out so
+// tests run without a live server.
+type smtpSession interface {
+	Mail(from string) error
+	Rcpt(to string) error
+	Data() (io.WriteCloser, error)
+	Quit() error
+}
+
+// DeliveryStatus is one send attempt's outcome.
+type DeliveryStatus struct {
+	Tenant   string    `json:"tenant,omitempty"`
+	To       []string  `json:"to"`
+	Template string    `json:"template"`
+	Subject  string    `json:"subject"`
+	SentAt   time.Time `json:"sent_at"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// emailTemplate renders one message kind: the subject and text body are
+// required, the HTML body optional.
+type emailTemplate struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *template.Template
+}
+
+// Email sends templated mail over a pooled SMTP connection.
+type Email struct {
+	cfg EmailConfig
+	// dial opens one authenticated SMTP session; tests inject fakes.
+	dial func() (smtpSession, error)
+	now  func() time.Time
+
+	mu         sync.Mutex
+	idle       []smtpSession
+	tenantFrom map[string]string
+	templates  map[string]*emailTemplate
+	statuses   []DeliveryStatus
+}
+
+// NewEmail creates a sender for one SMTP server.
+func NewEmail(cfg EmailConfig) *Email {
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = 2
+	}
+	e := &Email{
+		cfg:        cfg,
+		now:        time.Now,
+		tenantFrom: make(map[string]string),
+		templates:  make(map[string]*emailTemplate),
+	}
+	e.dial = func() (smtpSession, error) {
+		c, err := smtp.Dial(fmt.Sprintf("%s:%d", cfg.Host, cfg.Port))
+		if err != nil {
+			return nil, err
+		}
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(nil); err != nil {
+				c.Close()
+				return nil, err
+			}
+		}
+		if cfg.Username != "" {
+			auth := smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+			if err := c.Auth(auth); err != nil {
+				c.Close()
+				return nil, err
+			}
+		}
+		return c, nil
+	}
+	return e
+}
+
+// SetTenantFrom overrides the sender address for one tenant's mail.
+func (e *Email) SetTenantFrom(tenant, from string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tenantFrom[tenant] = from
+}
+
+// SetTemplate registers a message kind. Subject and text are required;
+// html may be empty for text-only mail. All three are Go templates over
+// the data passed to Send.
+func (e *Email) SetTemplate(name, subject, text, html string) error {
+	if subject == "" || text == "" {
+		return errors.New("notify: template needs subject and text body")
+	}
+	tmpl := &emailTemplate{}
+	var err error
+	if tmpl.subject, err = texttemplate.New(name + ".subject").Parse(subject); err != nil {
+		return fmt.Errorf("notify: template %s subject: %w", name, err)
+	}
+	if tmpl.text, err = texttemplate.New(name + ".text").Parse(text); err != nil {
+		return fmt.Errorf("notify: template %s text: %w", name, err)
+	}
+	if html != "" {
+		if tmpl.html, err = template.New(name + ".html").Parse(html); err != nil {
+			return fmt.Errorf("notify: template %s html: %w", name, err)
+		}
+	}
+	e.mu.Lock()
+	e.templates[name] = tmpl
+	e.mu.Unlock()
+	return nil
+}
+
+// Send renders a registered template with data and mails it to the
+// recipients, using the tenant's from address when one is configured.
+// The attempt is recorded in the delivery history either way.
+func (e *Email) Send(ctx context.Context, tenant string, to []string, templateName string, data any) error {
+	e.mu.Lock()
+	tmpl, ok := e.templates[templateName]
+	from := e.cfg.From
+	if override, set := e.tenantFrom[tenant]; set {
+		from = override
+	}
+	e.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoTemplate, templateName)
+	}
+	if len(to) == 0 {
+		return errors.New("notify: no recipients")
+	}
+
+	subject, body, err := render(tmpl, from, to, data)
+	if err != nil {
+		return err
+	}
+	status := DeliveryStatus{
+		Tenant: tenant, To: to, Template: templateName,
+		Subject: subject, SentAt: e.now(),
+	}
+	err = e.deliver(ctx, from, to, body)
+	if err != nil {
+		status.Error = err.Error()
+	}
+	e.record(status)
+	return err
+}
+
+// render produces the subject and the full RFC 2822 message, as
+// multipart/alternative when the template has an HTML body.
+func render(tmpl *emailTemplate, from string, to []string, data any) (string, []byte, error) {
+	var subject, text bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return "", nil, err
+	}
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return "", nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", strings.TrimSpace(subject.String()))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+
+	if tmpl.html == nil {
+		msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		msg.Write(text.Bytes())
+		return subject.String(), msg.Bytes(), nil
+	}
+
+	var html bytes.Buffer
+	if err := tmpl.html.Execute(&html, data); err != nil {
+		return "", nil, err
+	}
+	w := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", w.Boundary())
+	for _, part := range []struct {
+		contentType string
+		body        []byte
+	}{
+		{"text/plain; charset=utf-8", text.Bytes()},
+		{"text/html; charset=utf-8", html.Bytes()},
+	} {
+		pw, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return "", nil, err
+		}
+		pw.Write(part.body)
+	}
+	w.Close()
+	return subject.String(), msg.Bytes(), nil
+}
+
+// deliver runs one SMTP transaction on a pooled session. A session that
+// errors is discarded; a clean one goes back to the pool for the next
+// send.
+func (e *Email) deliver(ctx context.Context, from string, to []string, body []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	sess, err := e.acquire()
+	if err != nil {
+		return err
+	}
+	if err := e.transact(sess, from, to, body); err != nil {
+		sess.Quit()
+		return err
+	}
+	e.release(sess)
+	return nil
+}
+
+func (e *Email) transact(sess smtpSession, from string, to []string, body []byte) error {
+	if err := sess.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := sess.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := sess.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// acquire reuses an idle session or dials a new one.
+func (e *Email) acquire() (smtpSession, error) {
+	e.mu.Lock()
+	if n := len(e.idle); n > 0 {
+		sess := e.idle[n-1]
+		e.idle = e.idle[:n-1]
+		e.mu.Unlock()
+		return sess, nil
+	}
+	e.mu.Unlock()
+	return e.dial()
+}
+
+// release returns a healthy session to the pool, or closes it when the
+// pool is full.
+func (e *Email) release(sess smtpSession) {
+	e.mu.Lock()
+	if len(e.idle) < e.cfg.PoolSize {
+		e.idle = append(e.idle, sess)
+		e.mu.Unlock()
+		return
+	}
+	e.mu.Unlock()
+	sess.Quit()
+}
+
+// record appends one delivery status, newest last, bounded.
+func (e *Email) record(s DeliveryStatus) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.statuses = append(e.statuses, s)
+	if len(e.statuses) > maxStatuses {
+		e.statuses = e.statuses[len(e.statuses)-maxStatuses:]
+	}
+}
+
+// Statuses returns the recent delivery history, newest last.
+func (e *Email) Statuses() []DeliveryStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]DeliveryStatus, len(e.statuses))
+	copy(out, e.statuses)
+	return out
+}
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakeSession records one SMTP transaction; failAt makes a named step
+// error to exercise discard paths.
+type fakeSession struct {
+	from   string
+	rcpts  []string
+	body   bytes.Buffer
+	quit   bool
+	failAt string
+}
+
+func (s *fakeSession) Mail(from string) error {
+	if s.failAt == "mail" {
+		return errors.New("mail refused")
+	}
+	s.from = from
+	return nil
+}
+
+func (s *fakeSession) Rcpt(to string) error {
+	s.rcpts = append(s.rcpts, to)
+	return nil
+}
+
+func (s *fakeSession) Data() (io.WriteCloser, error) { return nopCloser{&s.body}, nil }
+func (s *fakeSession) Quit() error                   { s.quit = true; return nil }
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// newTestEmail wires a sender to fake sessions, returning the dial count
+// and the sessions created.
+func newTestEmail(t *testing.T, failAt string) (*Email, *[]*fakeSession) {
+	t.Helper()
+	e := NewEmail(EmailConfig{Host: "mail.test", Port: 25, From: "noreply@goleapai.dev"})
+	sessions := &[]*fakeSession{}
+	e.dial = func() (smtpSession, error) {
+		s := &fakeSession{failAt: failAt}
+		*sessions = append(*sessions, s)
+		return s, nil
+	}
+	if err := e.SetTemplate("quota",
+		"Quota warning for {{.Key}}",
+		"Limit {{.Key}} is at {{.Pct}}%.",
+		"<p>Limit <b>{{.Key}}</b> is at {{.Pct}}%.</p>"); err != nil {
+		t.Fatal(err)
+	}
+	return e, sessions
+}
+
+func TestSendRendersMultipartAndPoolsSessions(t *testing.T) {
+	e, sessions := newTestEmail(t, "")
+	data := map[string]any{"Key": "openai", "Pct": 90}
+	if err := e.Send(context.Background(), "", []string{"ops@acme.io"}, "quota", data); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Send(context.Background(), "", []string{"ops@acme.io"}, "quota", data); err != nil {
+		t.Fatal(err)
+	}
+	if len(*sessions) != 1 {
+		t.Fatalf("dials = %d, want pooled session reused", len(*sessions))
+	}
+	msg := (*sessions)[0].body.String()
+	for _, want := range []string{
+		"Subject: Quota warning for openai",
+		"multipart/alternative",
+		"Limit openai is at 90%.",
+		"<b>openai</b>",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("message missing %q:\n%s", want, msg)
+		}
+	}
+	if (*sessions)[0].from != "noreply@goleapai.dev" {
+		t.Fatalf("from = %q", (*sessions)[0].from)
+	}
+
+	statuses := e.Statuses()
+	if len(statuses) != 2 || statuses[0].Error != "" || statuses[0].Subject != "Quota warning for openai" {
+		t.Fatalf("statuses = %+v", statuses)
+	}
+}
+
+func TestTenantFromOverride(t *testing.T) {
+	e, sessions := newTestEmail(t, "")
+	e.SetTenantFrom("acme", "alerts@acme.io")
+	if err := e.Send(context.Background(), "acme", []string{"x@acme.io"}, "quota",
+		map[string]any{"Key": "k", "Pct": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if (*sessions)[0].from != "alerts@acme.io" {
+		t.Fatalf("from = %q", (*sessions)[0].from)
+	}
+}
+
+func TestFailedSessionIsDiscardedAndTracked(t *testing.T) {
+	e, sessions := newTestEmail(t, "mail")
+	err := e.Send(context.Background(), "", []string{"x@acme.io"}, "quota",
+		map[string]any{"Key": "k", "Pct": 1})
+	if err == nil {
+		t.Fatal("send error swallowed")
+	}
+	if !(*sessions)[0].quit {
+		t.Fatal("failed session kept in pool")
+	}
+	statuses := e.Statuses()
+	if len(statuses) != 1 || !strings.Contains(statuses[0].Error, "mail refused") {
+		t.Fatalf("statuses = %+v", statuses)
+	}
+}
+
+func TestSendGuards(t *testing.T) {
+	e, _ := newTestEmail(t, "")
+	if err := e.Send(context.Background(), "", []string{"x@acme.io"}, "ghost", nil); !errors.Is(err, ErrNoTemplate) {
+		t.Fatalf("err = %v", err)
+	}
+	if err := e.Send(context.Background(), "", nil, "quota", nil); err == nil {
+		t.Fatal("empty recipients accepted")
+	}
+	if err := e.SetTemplate("bad", "", "body", ""); err == nil {
+		t.Fatal("template without subject accepted")
+	}
+	if err := e.SetTemplate("bad", "s", "{{.Broken", ""); err == nil {
+		t.Fatal("unparsable template accepted")
+	}
+}
@@ -0,0 +1,185 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slackReplayWindow rejects signed requests older than this, per Slack's
+// signing guidance.
+const slackReplayWindow = 5 * time.Minute
+
+// ErrBadSignature is returned for Slack requests that fail verification.
+var ErrBadSignature = errors.New("notify: slack signature verification failed")
+
+// Slack is the Slack app integration: slash commands for quick operations
+// and alert messages with an acknowledge button. Commands call back into
+// the gateway through the injected hooks, so the integration stays a thin
+// shell around the admin operations.
+type Slack struct {
+	// SigningSecret verifies that requests really come from Slack.
+	SigningSecret string
+	// WebhookURL receives posted alerts.
+	WebhookURL string
+	// Client posts alerts; nil uses http.DefaultClient.
+	Client *http.Client
+
+	// Status returns the one-line gateway summary for `/goleapai status`.
+	Status func() string
+	// DisableProvider backs `/goleapai provider disable <id>`.
+	DisableProvider func(providerID string) error
+	// AckAlert is called when someone presses an alert's ack button.
+	AckAlert func(alertID, user string) error
+
+	now func() time.Time
+}
+
+// NewSlack creates the integration with the given signing secret.
+func NewSlack(signingSecret string) *Slack {
+	return &Slack{SigningSecret: signingSecret, now: time.Now}
+}
+
+// Verify checks Slack's v0 request signature over the raw body. Requests
+// outside the replay window fail even with a valid signature.
+func (s *Slack) Verify(timestamp, signature string, body []byte) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrBadSignature
+	}
+	if age := s.now().Sub(time.Unix(ts, 0)); age > slackReplayWindow || age < -slackReplayWindow {
+		return ErrBadSignature
+	}
+	mac := hmac.New(sha256.New, []byte(s.SigningSecret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if subtle.ConstantTimeCompare([]byte(want), []byte(signature)) != 1 {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// HandleCommand verifies and dispatches one slash command, returning the
+// reply text Slack shows the caller.
+func (s *Slack) HandleCommand(timestamp, signature string, body []byte) (string, error) {
+	if err := s.Verify(timestamp, signature, body); err != nil {
+		return "", err
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return "", fmt.Errorf("notify: slack command payload: %w", err)
+	}
+	args := strings.Fields(form.Get("text"))
+	switch {
+	case len(args) == 1 && args[0] == "status":
+		if s.Status == nil {
+			return "status is not wired up on this gateway", nil
+		}
+		return s.Status(), nil
+	case len(args) == 3 && args[0] == "provider" && args[1] == "disable":
+		if s.DisableProvider == nil {
+			return "provider control is not wired up on this gateway", nil
+		}
+		if err := s.DisableProvider(args[2]); err != nil {
+			return fmt.Sprintf("could not disable %s: %v", args[2], err), nil
+		}
+		return fmt.Sprintf("provider %s disabled", args[2]), nil
+	default:
+		return "usage: /goleapai status | /goleapai provider disable <id>", nil
+	}
+}
+
+// PostAlert sends an alert message to the webhook with an acknowledge
+// button carrying the alert ID back through HandleInteraction.
+func (s *Slack) PostAlert(ctx context.Context, alertID, text string) error {
+	if s.WebhookURL == "" {
+		return errors.New("notify: slack webhook URL not configured")
+	}
+	payload := map[string]any{
+		"text": text,
+		"blocks": []map[string]any{
+			{
+				"type": "section",
+				"text": map[string]any{"type": "mrkdwn", "text": text},
+			},
+			{
+				"type": "actions",
+				"elements": []map[string]any{{
+					"type":      "button",
+					"action_id": "ack_alert",
+					"value":     alertID,
+					"text":      map[string]any{"type": "plain_text", "text": "Acknowledge"},
+				}},
+			},
+		},
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.WebhookURL, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: slack webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// HandleInteraction verifies and processes one interactive callback (an
+// ack button press), returning the replacement message text.
+func (s *Slack) HandleInteraction(timestamp, signature string, body []byte) (string, error) {
+	if err := s.Verify(timestamp, signature, body); err != nil {
+		return "", err
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return "", fmt.Errorf("notify: slack interaction payload: %w", err)
+	}
+	var payload struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+		Actions []struct {
+			ActionID string `json:"action_id"`
+			Value    string `json:"value"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		return "", fmt.Errorf("notify: slack interaction payload: %w", err)
+	}
+	for _, action := range payload.Actions {
+		if action.ActionID != "ack_alert" {
+			continue
+		}
+		if s.AckAlert != nil {
+			if err := s.AckAlert(action.Value, payload.User.Username); err != nil {
+				return fmt.Sprintf("could not acknowledge: %v", err), nil
+			}
+		}
+		return fmt.Sprintf("alert acknowledged by @%s", payload.User.Username), nil
+	}
+	return "", errors.New("notify: no known action in interaction")
+}
@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sign produces a valid Slack v0 signature for a body at a timestamp.
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newSlack() (*Slack, string) {
+	s := NewSlack("secret")
+	at := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return at }
+	return s, strconv.FormatInt(at.Unix(), 10)
+}
+
+func TestSlackCommandDispatch(t *testing.T) {
+	s, ts := newSlack()
+	s.Status = func() string { return "12 providers up, 0 quarantined" }
+	var disabled string
+	s.DisableProvider = func(id string) error {
+		if id == "ghost" {
+			return errors.New("provider not found")
+		}
+		disabled = id
+		return nil
+	}
+
+	for _, tc := range []struct{ text, want string }{
+		{"status", "12 providers up, 0 quarantined"},
+		{"provider disable openai", "provider openai disabled"},
+		{"provider disable ghost", "could not disable ghost: provider not found"},
+		{"dance", "usage: /goleapai status | /goleapai provider disable <id>"},
+	} {
+		body := []byte(url.Values{"command": {"/goleapai"}, "text": {tc.text}}.Encode())
+		reply, err := s.HandleCommand(ts, sign("secret", ts, body), body)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.text, err)
+		}
+		if reply != tc.want {
+			t.Fatalf("%q reply = %q", tc.text, reply)
+		}
+	}
+	if disabled != "openai" {
+		t.Fatalf("disabled = %q", disabled)
+	}
+}
+
+func TestSlackSignatureChecks(t *testing.T) {
+	s, ts := newSlack()
+	body := []byte("command=%2Fgoleapai&text=status")
+	if _, err := s.HandleCommand(ts, sign("wrong", ts, body), body); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("wrong secret: %v", err)
+	}
+	if _, err := s.HandleCommand(ts, sign("secret", ts, []byte("tampered")), body); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("tampered body: %v", err)
+	}
+	old := strconv.FormatInt(s.now().Add(-10*time.Minute).Unix(), 10)
+	if _, err := s.HandleCommand(old, sign("secret", old, body), body); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("replayed request: %v", err)
+	}
+}
+
+func TestSlackAlertRoundTrip(t *testing.T) {
+	var posted []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	s, ts := newSlack()
+	s.WebhookURL = srv.URL
+	if err := s.PostAlert(context.Background(), "alert-7", "provider openai quarantined"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"ack_alert", "alert-7", "provider openai quarantined", "Acknowledge"} {
+		if !strings.Contains(string(posted), want) {
+			t.Fatalf("alert missing %q: %s", want, posted)
+		}
+	}
+
+	var acked, by string
+	s.AckAlert = func(id, user string) error { acked, by = id, user; return nil }
+	payload := `{"user":{"username":"sam"},"actions":[{"action_id":"ack_alert","value":"alert-7"}]}`
+	body := []byte(url.Values{"payload": {payload}}.Encode())
+	reply, err := s.HandleInteraction(ts, sign("secret", ts, body), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acked != "alert-7" || by != "sam" || reply != "alert acknowledged by @sam" {
+		t.Fatalf("acked=%q by=%q reply=%q", acked, by, reply)
+	}
+}
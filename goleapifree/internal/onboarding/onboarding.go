@@ -0,0 +1,185 @@
+// Package onboarding implements the guided provider setup flow backing
+// the WebUI wizard: pick a type, supply credentials, probe capabilities,
+// run a test completion and only then activate the provider.
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/router"
+)
+
+// Wizard steps, in order. Each transition validates the previous step so
+// a provider can never be activated with untested credentials.
+const (
+	StepType        = "type"
+	StepCredentials = "credentials"
+	StepProbe       = "probe"
+	StepTier        = "tier"
+	StepTest        = "test"
+	StepDone        = "done"
+)
+
+// Errors returned by step transitions.
+var (
+	ErrSessionNotFound = errors.New("onboarding: session not found")
+	ErrWrongStep       = errors.New("onboarding: step out of order")
+	ErrTestFailed      = errors.New("onboarding: test completion failed")
+)
+
+// Prober checks credentials against the provider and lists its models.
+// The live implementation calls the provider's model listing endpoint;
+// tests inject fakes.
+type Prober func(ctx context.Context, providerType, baseURL, apiKey string) ([]string, error)
+
+// Tester runs one short completion through the candidate provider.
+type Tester func(ctx context.Context, providerType, baseURL, apiKey, model string) error
+
+// Session is one in-progress wizard run.
+type Session struct {
+	ID        string    `json:"id"`
+	Step      string    `json:"step"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Provider models.Provider `json:"provider"`
+	// apiKey never serializes; the WebUI sends it once and the wizard
+	// keeps it server-side until activation.
+	apiKey string
+
+	Models     []string `json:"models,omitempty"`
+	Tier       string   `json:"tier,omitempty"`
+	Pool       string   `json:"pool,omitempty"`
+	TestPassed bool     `json:"test_passed"`
+}
+
+// Wizard owns the active sessions.
+type Wizard struct {
+	Probe  Prober
+	Test   Tester
+	Router *router.Router
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+// NewWizard creates a wizard registering activated providers on r.
+func NewWizard(r *router.Router, probe Prober, test Tester) *Wizard {
+	return &Wizard{Probe: probe, Test: test, Router: r, sessions: make(map[string]*Session)}
+}
+
+// Start opens a session at the type-selection step.
+func (w *Wizard) Start() *Session {
+	s := &Session{ID: uuid.NewString(), Step: StepType, CreatedAt: time.Now()}
+	w.mu.Lock()
+	w.sessions[s.ID] = s
+	w.mu.Unlock()
+	return s
+}
+
+func (w *Wizard) session(id, expectStep string) (*Session, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	s, ok := w.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if s.Step != expectStep {
+		return nil, fmt.Errorf("%w: at %s, expected %s", ErrWrongStep, s.Step, expectStep)
+	}
+	return s, nil
+}
+
+// SetType records the provider type and display name.
+func (w *Wizard) SetType(id, providerType, name string) (*Session, error) {
+	s, err := w.session(id, StepType)
+	if err != nil {
+		return nil, err
+	}
+	s.Provider = models.Provider{ID: uuid.NewString(), Name: name, Type: providerType}
+	s.Step = StepCredentials
+	return s, nil
+}
+
+// SetCredentials stores the endpoint and key, then probes capabilities.
+func (w *Wizard) SetCredentials(ctx context.Context, id, baseURL, apiKey string) (*Session, error) {
+	s, err := w.session(id, StepCredentials)
+	if err != nil {
+		return nil, err
+	}
+	s.Provider.BaseURL = baseURL
+	s.apiKey = apiKey
+	s.Step = StepProbe
+
+	modelList, err := w.Probe(ctx, s.Provider.Type, baseURL, apiKey)
+	if err != nil {
+		// Stay on the credentials step so the operator can correct them.
+		s.Step = StepCredentials
+		return s, fmt.Errorf("onboarding: credential probe failed: %w", err)
+	}
+	s.Models = modelList
+	s.Step = StepTier
+	return s, nil
+}
+
+// SetTier records tier and pool membership.
+func (w *Wizard) SetTier(id, tier, pool string) (*Session, error) {
+	s, err := w.session(id, StepTier)
+	if err != nil {
+		return nil, err
+	}
+	s.Tier = tier
+	s.Pool = pool
+	s.Step = StepTest
+	return s, nil
+}
+
+// RunTest performs the test completion against the first probed model.
+func (w *Wizard) RunTest(ctx context.Context, id string) (*Session, error) {
+	s, err := w.session(id, StepTest)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Models) == 0 {
+		return s, fmt.Errorf("%w: no models to test against", ErrTestFailed)
+	}
+	if err := w.Test(ctx, s.Provider.Type, s.Provider.BaseURL, s.apiKey, s.Models[0]); err != nil {
+		return s, fmt.Errorf("%w: %v", ErrTestFailed, err)
+	}
+	s.TestPassed = true
+	return s, nil
+}
+
+// Activate registers the provider and its account on the router and
+// closes the session. It refuses to activate before a passing test.
+func (w *Wizard) Activate(id string) (*models.Provider, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	s, ok := w.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if s.Step != StepTest || !s.TestPassed {
+		return nil, fmt.Errorf("%w: test completion has not passed", ErrWrongStep)
+	}
+	s.Provider.Enabled = true
+	s.Provider.CreatedAt = time.Now()
+	p := s.Provider
+	w.Router.AddProvider(&p)
+	w.Router.AddAccount(&models.Account{
+		ID:         uuid.NewString(),
+		ProviderID: p.ID,
+		Label:      s.Pool,
+		APIKey:     s.apiKey,
+		Enabled:    true,
+	})
+	s.Step = StepDone
+	delete(w.sessions, id)
+	return &p, nil
+}
@@ -0,0 +1,83 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/router"
+)
+
+func okProbe(_ context.Context, _, _, _ string) ([]string, error) {
+	return []string{"gpt-4o-mini"}, nil
+}
+
+func okTest(_ context.Context, _, _, _, _ string) error { return nil }
+
+func TestFullWizardFlow(t *testing.T) {
+	r := router.New()
+	w := NewWizard(r, okProbe, okTest)
+	ctx := context.Background()
+
+	s := w.Start()
+	if _, err := w.SetType(s.ID, "openai", "acme"); err != nil {
+		t.Fatal(err)
+	}
+	s2, err := w.SetCredentials(ctx, s.ID, "https://api.acme.dev", "sk-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s2.Step != StepTier || len(s2.Models) != 1 {
+		t.Fatalf("session after probe = %+v", s2)
+	}
+	if _, err := w.SetTier(s.ID, "free", "default"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.RunTest(ctx, s.ID); err != nil {
+		t.Fatal(err)
+	}
+	p, err := w.Activate(s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.GetProvider(p.ID); got == nil || !got.Enabled {
+		t.Fatalf("provider not registered: %+v", got)
+	}
+	if _, err := w.Activate(s.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("re-activation err = %v", err)
+	}
+}
+
+func TestCannotActivateWithoutPassingTest(t *testing.T) {
+	r := router.New()
+	w := NewWizard(r, okProbe, func(context.Context, string, string, string, string) error {
+		return errors.New("401")
+	})
+	ctx := context.Background()
+
+	s := w.Start()
+	w.SetType(s.ID, "openai", "acme")
+	w.SetCredentials(ctx, s.ID, "https://api.acme.dev", "sk-bad")
+	w.SetTier(s.ID, "free", "default")
+	if _, err := w.RunTest(ctx, s.ID); !errors.Is(err, ErrTestFailed) {
+		t.Fatalf("err = %v, want ErrTestFailed", err)
+	}
+	if _, err := w.Activate(s.ID); !errors.Is(err, ErrWrongStep) {
+		t.Fatalf("err = %v, want ErrWrongStep", err)
+	}
+}
+
+func TestFailedProbeStaysOnCredentials(t *testing.T) {
+	w := NewWizard(router.New(), func(context.Context, string, string, string) ([]string, error) {
+		return nil, errors.New("connection refused")
+	}, okTest)
+	s := w.Start()
+	w.SetType(s.ID, "openai", "acme")
+	if _, err := w.SetCredentials(context.Background(), s.ID, "https://down", "k"); err == nil {
+		t.Fatal("probe failure not surfaced")
+	}
+	// The operator can retry with fixed credentials.
+	if _, err := w.session(s.ID, StepCredentials); err != nil {
+		t.Fatalf("session not back on credentials step: %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+// Package playground records interactive sessions — prompts, sampling
+// parameters, routing decisions and responses — as named snapshots.
+// A snapshot can later be replayed against a different model or routing
+// config, giving a side-by-side qualitative comparison instead of
+// re-typing prompts after every change.
+package playground
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/biodoia/goleapifree/internal/lru"
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// Errors returned by the store.
+var (
+	ErrNotFound = errors.New("playground: session not found")
+)
+
+// Params are the sampling parameters one entry ran under.
+type Params struct {
+	Model       string  `json:"model"`
+	Temperature float64 `json:"temperature,omitempty"`
+	MaxTokens   int     `json:"max_tokens,omitempty"`
+}
+
+// Entry is one exchange inside a session, including where the router
+// actually sent it.
+type Entry struct {
+	Prompt string `json:"prompt"`
+	Params Params `json:"params"`
+	// ProviderID and ResolvedModel record the routing decision taken
+	// at the time, which the requested alias alone does not reveal.
+	ProviderID    string    `json:"provider_id,omitempty"`
+	ResolvedModel string    `json:"resolved_model,omitempty"`
+	Response      string    `json:"response"`
+	LatencyMs     float64   `json:"latency_ms,omitempty"`
+	At            time.Time `json:"at"`
+}
+
+// Session is a named, replayable snapshot.
+type Session struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Entries   []Entry   `json:"entries"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Comparison pairs an original entry with its replayed response.
+type Comparison struct {
+	Prompt        string `json:"prompt"`
+	OriginalModel string `json:"original_model"`
+	Original      string `json:"original"`
+	ReplayModel   string `json:"replay_model"`
+	Replayed      string `json:"replayed"`
+	ReplayError   string `json:"replay_error,omitempty"`
+}
+
+// Store keeps sessions and replays them. Chat issues one completion
+// through the gateway's normal pipeline; tests inject fakes.
+type Store struct {
+	Chat func(ctx context.Context, req providers.ChatRequest) (*providers.ChatResponse, error)
+	// MaxSessions bounds how many sessions are kept; the oldest session
+	// is dropped when the cap is exceeded. 0 keeps everything.
+	MaxSessions int
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+	dropped  uint64
+}
+
+// NewStore returns an empty session store.
+func NewStore() *Store {
+	return &Store{sessions: make(map[string]*Session)}
+}
+
+// Start creates a named session.
+func (s *Store) Start(name string) *Session {
+	sess := &Session{ID: uuid.NewString(), Name: name, CreatedAt: time.Now()}
+	s.mu.Lock()
+	s.sessions[sess.ID] = sess
+	for s.MaxSessions > 0 && len(s.sessions) > s.MaxSessions {
+		oldest := ""
+		for id, candidate := range s.sessions {
+			if oldest == "" || candidate.CreatedAt.Before(s.sessions[oldest].CreatedAt) {
+				oldest = id
+			}
+		}
+		delete(s.sessions, oldest)
+		s.dropped++
+	}
+	s.mu.Unlock()
+	copied := *sess
+	return &copied
+}
+
+// Usage reports session count against the cap, for memory gauges.
+func (s *Store) Usage() lru.Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return lru.Stats{Entries: len(s.sessions), MaxEntries: s.MaxSessions, Evictions: s.dropped}
+}
+
+// Append records one exchange onto a session.
+func (s *Store) Append(id string, e Entry) error {
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		return ErrNotFound
+	}
+	sess.Entries = append(sess.Entries, e)
+	return nil
+}
+
+// Get returns a session by ID.
+func (s *Store) Get(id string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *sess
+	copied.Entries = append([]Entry(nil), sess.Entries...)
+	return &copied, nil
+}
+
+// List returns all sessions, newest first, without entries (listings
+// stay small).
+func (s *Store) List() []*Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*Session, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		copied := *sess
+		copied.Entries = nil
+		out = append(out, &copied)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// Replay re-runs every prompt of a session under a different model (an
+// alias or concrete model name) with the original sampling parameters,
+// returning side-by-side comparisons. A failing replay of one entry is
+// reported in its comparison rather than aborting the rest.
+func (s *Store) Replay(ctx context.Context, id, model string) ([]Comparison, error) {
+	sess, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.Chat == nil {
+		return nil, errors.New("playground: chat backend not configured")
+	}
+	out := make([]Comparison, 0, len(sess.Entries))
+	for _, e := range sess.Entries {
+		cmp := Comparison{
+			Prompt:        e.Prompt,
+			OriginalModel: e.Params.Model,
+			Original:      e.Response,
+			ReplayModel:   model,
+		}
+		resp, err := s.Chat(ctx, providers.ChatRequest{
+			Model:       model,
+			Messages:    []providers.Message{{Role: "user", Content: e.Prompt}},
+			Temperature: e.Params.Temperature,
+			MaxTokens:   e.Params.MaxTokens,
+		})
+		if err != nil {
+			cmp.ReplayError = err.Error()
+		} else {
+			cmp.Replayed = resp.Content
+		}
+		out = append(out, cmp)
+	}
+	return out, nil
+}
+
+// Snapshot serializes all sessions for persistence across restarts.
+func (s *Store) Snapshot() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return json.Marshal(s.sessions)
+}
+
+// Restore replaces the store's contents with a snapshot.
+func (s *Store) Restore(data []byte) error {
+	sessions := make(map[string]*Session)
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.sessions = sessions
+	s.mu.Unlock()
+	return nil
+}
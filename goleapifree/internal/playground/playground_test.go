@@ -0,0 +1,118 @@
+package playground
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+func TestRecordAndReplayAgainstOtherModel(t *testing.T) {
+	s := NewStore()
+	s.Chat = func(_ context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+		if req.Model != "new-alias" {
+			t.Fatalf("replay model = %q", req.Model)
+		}
+		if req.Temperature != 0.7 {
+			t.Fatalf("temperature = %v, want original preserved", req.Temperature)
+		}
+		return &providers.ChatResponse{Content: "replayed: " + req.Messages[0].Content}, nil
+	}
+
+	sess := s.Start("prompt tuning")
+	err := s.Append(sess.ID, Entry{
+		Prompt:        "summarize the release notes",
+		Params:        Params{Model: "old-alias", Temperature: 0.7},
+		ProviderID:    "p1",
+		ResolvedModel: "gpt-4o-mini",
+		Response:      "original summary",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmps, err := s.Replay(context.Background(), sess.ID, "new-alias")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmps) != 1 {
+		t.Fatalf("comparisons = %d", len(cmps))
+	}
+	c := cmps[0]
+	if c.Original != "original summary" || c.Replayed != "replayed: summarize the release notes" {
+		t.Fatalf("comparison = %+v", c)
+	}
+	if c.OriginalModel != "old-alias" || c.ReplayModel != "new-alias" {
+		t.Fatalf("comparison = %+v", c)
+	}
+}
+
+func TestReplayReportsPerEntryErrors(t *testing.T) {
+	s := NewStore()
+	s.Chat = func(_ context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+		if req.Messages[0].Content == "boom" {
+			return nil, errors.New("upstream 500")
+		}
+		return &providers.ChatResponse{Content: "ok"}, nil
+	}
+	sess := s.Start("mixed")
+	s.Append(sess.ID, Entry{Prompt: "boom", Response: "a"})
+	s.Append(sess.ID, Entry{Prompt: "fine", Response: "b"})
+
+	cmps, err := s.Replay(context.Background(), sess.ID, "m")
+	if err != nil || len(cmps) != 2 {
+		t.Fatalf("cmps = %+v, %v", cmps, err)
+	}
+	if cmps[0].ReplayError != "upstream 500" || cmps[1].Replayed != "ok" {
+		t.Fatalf("cmps = %+v", cmps)
+	}
+}
+
+func TestSnapshotRoundTripAndNotFound(t *testing.T) {
+	s := NewStore()
+	sess := s.Start("persisted")
+	s.Append(sess.ID, Entry{Prompt: "p", Response: "r"})
+
+	data, err := s.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewStore()
+	if err := restored.Restore(data); err != nil {
+		t.Fatal(err)
+	}
+	got, err := restored.Get(sess.ID)
+	if err != nil || len(got.Entries) != 1 || got.Name != "persisted" {
+		t.Fatalf("session = %+v, %v", got, err)
+	}
+
+	if err := s.Append("missing", Entry{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Replay(context.Background(), "missing", "m"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+
+	// Listings omit entries but keep ordering metadata.
+	list := s.List()
+	if len(list) != 1 || list[0].Entries != nil {
+		t.Fatalf("list = %+v", list)
+	}
+}
+
+func TestMaxSessionsDropsOldest(t *testing.T) {
+	s := NewStore()
+	s.MaxSessions = 2
+	first := s.Start("first")
+	s.Start("second")
+	s.Start("third")
+
+	if _, err := s.Get(first.ID); err == nil {
+		t.Fatal("oldest session survived the cap")
+	}
+	usage := s.Usage()
+	if usage.Entries != 2 || usage.Evictions != 1 {
+		t.Fatalf("usage = %+v", usage)
+	}
+}
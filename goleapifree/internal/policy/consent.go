@@ -0,0 +1,119 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+// ErrConsentRequired is returned when routing would reach a provider
+// class the user has not acknowledged terms for.
+var ErrConsentRequired = errors.New("policy: consent required")
+
+// Provider classes derived from policy tags.
+const (
+	ClassTrainsOnData  = "trains-on-data"
+	ClassUnknownPolicy = "unknown-policy"
+)
+
+// ClassesFor derives the consent-relevant classes of a provider from
+// its data policy tags.
+func ClassesFor(p *models.Provider) []string {
+	if p.DataPolicy == nil {
+		return []string{ClassUnknownPolicy}
+	}
+	var out []string
+	if p.DataPolicy.TrainsOnData {
+		out = append(out, ClassTrainsOnData)
+	}
+	if p.DataPolicy.Region != "" {
+		out = append(out, "region:"+p.DataPolicy.Region)
+	}
+	return out
+}
+
+// Consent is one recorded terms acknowledgment.
+type Consent struct {
+	User         string    `json:"user"`
+	Class        string    `json:"class"`
+	TermsVersion string    `json:"terms_version"`
+	At           time.Time `json:"at"`
+}
+
+// ConsentLedger records acknowledgments and gates routing on them.
+// Only the classes listed in Required need consent; everything else
+// routes freely.
+type ConsentLedger struct {
+	required map[string]bool
+
+	mu      sync.RWMutex
+	records map[string]Consent // user + "\x00" + class
+	now     func() time.Time
+}
+
+// NewConsentLedger creates a ledger gating the given classes, e.g.
+// ClassTrainsOnData.
+func NewConsentLedger(requiredClasses ...string) *ConsentLedger {
+	required := make(map[string]bool, len(requiredClasses))
+	for _, c := range requiredClasses {
+		required[c] = true
+	}
+	return &ConsentLedger{
+		required: required,
+		records:  make(map[string]Consent),
+		now:      time.Now,
+	}
+}
+
+// Record stores a user's acknowledgment of a class's terms.
+func (l *ConsentLedger) Record(user, class, termsVersion string) (Consent, error) {
+	if user == "" || class == "" {
+		return Consent{}, errors.New("policy: user and class are required")
+	}
+	c := Consent{User: user, Class: class, TermsVersion: termsVersion, At: l.now()}
+	l.mu.Lock()
+	l.records[user+"\x00"+class] = c
+	l.mu.Unlock()
+	return c, nil
+}
+
+// Has reports whether user acknowledged the class's terms.
+func (l *ConsentLedger) Has(user, class string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	_, ok := l.records[user+"\x00"+class]
+	return ok
+}
+
+// Records lists a user's acknowledgments, oldest first.
+func (l *ConsentLedger) Records(user string) []Consent {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var out []Consent
+	for _, c := range l.records {
+		if c.User == user {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out
+}
+
+// Check verifies a user may route to a provider: every gated class the
+// provider belongs to needs a recorded consent. The error names the
+// missing class so clients can prompt for exactly that acknowledgment.
+func (l *ConsentLedger) Check(user string, p *models.Provider) error {
+	for _, class := range ClassesFor(p) {
+		if !l.required[class] {
+			continue
+		}
+		if !l.Has(user, class) {
+			return fmt.Errorf("%w: class %q (provider %s)", ErrConsentRequired, class, p.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package policy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+func TestCheckGatesOnlyRequiredClasses(t *testing.T) {
+	l := NewConsentLedger(ClassTrainsOnData)
+	training := &models.Provider{ID: "p1", DataPolicy: &models.DataPolicy{TrainsOnData: true, Region: "us"}}
+	clean := &models.Provider{ID: "p2", DataPolicy: &models.DataPolicy{Region: "eu"}}
+
+	// No consent for a gated class: routing must fail with the class named.
+	err := l.Check("alice", training)
+	if !errors.Is(err, ErrConsentRequired) || !strings.Contains(err.Error(), ClassTrainsOnData) {
+		t.Fatalf("err = %v", err)
+	}
+	// Ungated classes (region:...) never require consent.
+	if err := l.Check("alice", clean); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+
+	if _, err := l.Record("alice", ClassTrainsOnData, "2026-01"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Check("alice", training); err != nil {
+		t.Fatalf("err after consent = %v", err)
+	}
+	// Consent is per user.
+	if err := l.Check("bob", training); !errors.Is(err, ErrConsentRequired) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestUnknownPolicyClassCanBeGated(t *testing.T) {
+	l := NewConsentLedger(ClassUnknownPolicy)
+	nopolicy := &models.Provider{ID: "p3"}
+	if err := l.Check("alice", nopolicy); !errors.Is(err, ErrConsentRequired) {
+		t.Fatalf("err = %v", err)
+	}
+	l.Record("alice", ClassUnknownPolicy, "2026-01")
+	if err := l.Check("alice", nopolicy); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestRecordsListingAndValidation(t *testing.T) {
+	l := NewConsentLedger(ClassTrainsOnData)
+	l.Record("alice", ClassTrainsOnData, "2026-01")
+	l.Record("alice", "region:us", "2026-01")
+	l.Record("bob", ClassTrainsOnData, "2026-01")
+
+	recs := l.Records("alice")
+	if len(recs) != 2 {
+		t.Fatalf("records = %+v", recs)
+	}
+	if _, err := l.Record("", ClassTrainsOnData, "v"); err == nil {
+		t.Fatal("expected error for empty user")
+	}
+}
@@ -0,0 +1,112 @@
+// Package policy enforces tenant data-processing constraints against
+// provider policy tags: a tenant that forbids providers training on
+// submitted data, requires an EU region, or caps upstream retention gets
+// those rules applied as hard filters on candidate selection — never as
+// soft preferences.
+package policy
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/router"
+)
+
+// ErrNoCompliantProvider is returned when constraints filter out every
+// candidate for a model.
+var ErrNoCompliantProvider = errors.New("policy: no provider satisfies the tenant's data constraints")
+
+// Constraint is one tenant's data-processing requirements. The zero
+// value allows everything.
+type Constraint struct {
+	// DisallowTrainsOnData excludes providers that may train on
+	// submitted prompts. Providers without a declared policy are
+	// excluded too: unknown handling cannot satisfy the constraint.
+	DisallowTrainsOnData bool `json:"disallow_trains_on_data"`
+	// AllowedRegions, when non-empty, restricts processing regions.
+	AllowedRegions []string `json:"allowed_regions,omitempty"`
+	// MaxRetentionDays, when set, excludes providers retaining request
+	// data longer (or with no stated retention).
+	MaxRetentionDays int `json:"max_retention_days,omitempty"`
+}
+
+// restrictive reports whether the constraint filters anything at all.
+func (c Constraint) restrictive() bool {
+	return c.DisallowTrainsOnData || len(c.AllowedRegions) > 0 || c.MaxRetentionDays > 0
+}
+
+// Allows checks one provider against the constraint.
+func (c Constraint) Allows(p *models.Provider) bool {
+	if !c.restrictive() {
+		return true
+	}
+	dp := p.DataPolicy
+	if dp == nil {
+		return false
+	}
+	if c.DisallowTrainsOnData && dp.TrainsOnData {
+		return false
+	}
+	if len(c.AllowedRegions) > 0 {
+		ok := false
+		for _, region := range c.AllowedRegions {
+			if dp.Region == region {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if c.MaxRetentionDays > 0 && (dp.RetentionDays == 0 || dp.RetentionDays > c.MaxRetentionDays) {
+		return false
+	}
+	return true
+}
+
+// Engine holds per-tenant constraints.
+type Engine struct {
+	mu      sync.RWMutex
+	tenants map[string]Constraint
+}
+
+// NewEngine returns an engine with no constraints configured.
+func NewEngine() *Engine {
+	return &Engine{tenants: make(map[string]Constraint)}
+}
+
+// Set configures a tenant's constraint.
+func (e *Engine) Set(tenant string, c Constraint) {
+	e.mu.Lock()
+	e.tenants[tenant] = c
+	e.mu.Unlock()
+}
+
+// ConstraintFor returns a tenant's constraint (zero value when unset).
+func (e *Engine) ConstraintFor(tenant string) Constraint {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.tenants[tenant]
+}
+
+// Filter removes non-compliant candidates for a tenant. It returns
+// ErrNoCompliantProvider when the constraint eliminates every option,
+// so the proxy can surface a policy error instead of a generic 502.
+func (e *Engine) Filter(tenant string, cands []router.Candidate) ([]router.Candidate, error) {
+	c := e.ConstraintFor(tenant)
+	if !c.restrictive() {
+		return cands, nil
+	}
+	out := cands[:0]
+	for _, cand := range cands {
+		if c.Allows(cand.Provider) {
+			out = append(out, cand)
+		}
+	}
+	if len(cands) > 0 && len(out) == 0 {
+		return nil, ErrNoCompliantProvider
+	}
+	return out, nil
+}
@@ -0,0 +1,64 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/router"
+)
+
+func candidates() []router.Candidate {
+	return []router.Candidate{
+		{Provider: &models.Provider{ID: "trains", DataPolicy: &models.DataPolicy{TrainsOnData: true, Region: "us", RetentionDays: 30}}},
+		{Provider: &models.Provider{ID: "eu-clean", DataPolicy: &models.DataPolicy{Region: "eu", RetentionDays: 7}}},
+		{Provider: &models.Provider{ID: "unknown"}}, // no declared policy
+	}
+}
+
+func TestUnconstrainedTenantKeepsEverything(t *testing.T) {
+	e := NewEngine()
+	out, err := e.Filter("acme", candidates())
+	if err != nil || len(out) != 3 {
+		t.Fatalf("out = %+v, %v", out, err)
+	}
+}
+
+func TestTrainsOnDataIsAHardFilter(t *testing.T) {
+	e := NewEngine()
+	e.Set("acme", Constraint{DisallowTrainsOnData: true})
+
+	out, err := e.Filter("acme", candidates())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Both the training provider and the one with unknown policy drop.
+	if len(out) != 1 || out[0].Provider.ID != "eu-clean" {
+		t.Fatalf("out = %+v", out)
+	}
+}
+
+func TestRegionAndRetentionConstraints(t *testing.T) {
+	e := NewEngine()
+	e.Set("acme", Constraint{AllowedRegions: []string{"eu"}, MaxRetentionDays: 14})
+	out, err := e.Filter("acme", candidates())
+	if err != nil || len(out) != 1 || out[0].Provider.ID != "eu-clean" {
+		t.Fatalf("out = %+v, %v", out, err)
+	}
+
+	// Retention cap tighter than any provider: hard failure, not a
+	// silent fallback to a non-compliant provider.
+	e.Set("acme", Constraint{AllowedRegions: []string{"eu"}, MaxRetentionDays: 3})
+	if _, err := e.Filter("acme", candidates()); !errors.Is(err, ErrNoCompliantProvider) {
+		t.Fatalf("err = %v, want ErrNoCompliantProvider", err)
+	}
+}
+
+func TestEmptyCandidateListStaysEmpty(t *testing.T) {
+	e := NewEngine()
+	e.Set("acme", Constraint{DisallowTrainsOnData: true})
+	out, err := e.Filter("acme", nil)
+	if err != nil || len(out) != 0 {
+		t.Fatalf("out = %+v, %v", out, err)
+	}
+}
@@ -0,0 +1,110 @@
+// Package privacy implements the data-subject rights endpoints: GDPR
+// erasure across every store holding personal data, and (elsewhere in
+// this package) right-of-access exports. Subsystems register themselves
+// as erasers; the coordinator fans out, collects per-store row counts
+// and signs the resulting report.
+package privacy
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Eraser is implemented by each subsystem that stores personal data
+// (logs, conversations, devices, tokens, backup indexes, ...).
+type Eraser interface {
+	// Name identifies the store in the erasure report.
+	Name() string
+	// Erase removes or anonymizes the user's data and returns how many
+	// rows were (or, in a dry run, would be) affected.
+	Erase(ctx context.Context, user string, dryRun bool) (rows int64, err error)
+}
+
+// Section is one store's outcome in a report.
+type Section struct {
+	Store string `json:"store"`
+	Rows  int64  `json:"rows"`
+	Error string `json:"error,omitempty"`
+}
+
+// ErasureReport documents one erasure (or dry run) end to end. The
+// signature covers every field above it, so the report can be handed to
+// the data subject as tamper-evident proof.
+type Report struct {
+	User        string    `json:"user"`
+	DryRun      bool      `json:"dry_run"`
+	RequestedAt time.Time `json:"requested_at"`
+	Sections    []Section `json:"sections"`
+	Complete    bool      `json:"complete"`
+	Signature   string    `json:"signature,omitempty"`
+}
+
+// Coordinator fans erasure requests out to registered erasers.
+type Coordinator struct {
+	erasers []Eraser
+	secret  []byte
+	now     func() time.Time
+}
+
+// NewCoordinator creates a coordinator signing reports with secret.
+func NewCoordinator(secret []byte) *Coordinator {
+	return &Coordinator{secret: secret, now: time.Now}
+}
+
+// Register adds a store to the erasure fan-out.
+func (c *Coordinator) Register(e Eraser) {
+	c.erasers = append(c.erasers, e)
+}
+
+// Erase runs the erasure (or a dry run) across every registered store.
+// One failing store does not stop the rest; the report marks the run
+// incomplete so the operator can retry.
+func (c *Coordinator) Erase(ctx context.Context, user string, dryRun bool) (*Report, error) {
+	if user == "" {
+		return nil, errors.New("privacy: user is required")
+	}
+	r := &Report{User: user, DryRun: dryRun, RequestedAt: c.now(), Complete: true}
+	for _, e := range c.erasers {
+		s := Section{Store: e.Name()}
+		rows, err := e.Erase(ctx, user, dryRun)
+		s.Rows = rows
+		if err != nil {
+			s.Error = err.Error()
+			r.Complete = false
+		}
+		r.Sections = append(r.Sections, s)
+	}
+	sig, err := c.sign(r)
+	if err != nil {
+		return nil, err
+	}
+	r.Signature = sig
+	return r, nil
+}
+
+// Verify checks a report's signature.
+func (c *Coordinator) Verify(r *Report) bool {
+	sig, err := c.sign(r)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(r.Signature))
+}
+
+// sign computes the HMAC over the report with its signature blanked.
+func (c *Coordinator) sign(r *Report) (string, error) {
+	unsigned := *r
+	unsigned.Signature = ""
+	payload, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
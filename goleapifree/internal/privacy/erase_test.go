@@ -0,0 +1,99 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeStore counts erasure calls and scripts failures.
+type fakeStore struct {
+	name    string
+	rows    int64
+	fail    bool
+	erased  int
+	dryRuns int
+}
+
+func (f *fakeStore) Name() string { return f.name }
+
+func (f *fakeStore) Erase(_ context.Context, user string, dryRun bool) (int64, error) {
+	if f.fail {
+		return 0, errors.New("table locked")
+	}
+	if dryRun {
+		f.dryRuns++
+	} else {
+		f.erased++
+	}
+	return f.rows, nil
+}
+
+func TestDryRunCountsWithoutErasing(t *testing.T) {
+	logs := &fakeStore{name: "request_logs", rows: 42}
+	devices := &fakeStore{name: "devices", rows: 2}
+	c := NewCoordinator([]byte("secret"))
+	c.Register(logs)
+	c.Register(devices)
+
+	r, err := c.Erase(context.Background(), "alice", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.DryRun || !r.Complete || len(r.Sections) != 2 {
+		t.Fatalf("report = %+v", r)
+	}
+	if r.Sections[0].Rows != 42 || r.Sections[1].Rows != 2 {
+		t.Fatalf("sections = %+v", r.Sections)
+	}
+	if logs.erased != 0 || logs.dryRuns != 1 {
+		t.Fatalf("dry run touched data: %+v", logs)
+	}
+}
+
+func TestErasureReportIsSignedAndTamperEvident(t *testing.T) {
+	c := NewCoordinator([]byte("secret"))
+	c.Register(&fakeStore{name: "request_logs", rows: 10})
+
+	r, err := c.Erase(context.Background(), "alice", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Signature == "" || !c.Verify(r) {
+		t.Fatalf("report not verifiable: %+v", r)
+	}
+
+	tampered := *r
+	tampered.Sections = append([]Section(nil), r.Sections...)
+	tampered.Sections[0].Rows = 0
+	if c.Verify(&tampered) {
+		t.Fatal("tampered report verified")
+	}
+	// A different signing key must reject the report too.
+	other := NewCoordinator([]byte("other"))
+	if other.Verify(r) {
+		t.Fatal("report verified under wrong key")
+	}
+}
+
+func TestFailingStoreMarksReportIncomplete(t *testing.T) {
+	c := NewCoordinator([]byte("secret"))
+	c.Register(&fakeStore{name: "request_logs", fail: true})
+	good := &fakeStore{name: "devices", rows: 1}
+	c.Register(good)
+
+	r, err := c.Erase(context.Background(), "alice", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Complete {
+		t.Fatalf("report = %+v, want incomplete", r)
+	}
+	if r.Sections[0].Error == "" || good.erased != 1 {
+		t.Fatalf("failure must not stop later stores: %+v", r.Sections)
+	}
+
+	if _, err := c.Erase(context.Background(), "", false); err == nil {
+		t.Fatal("expected error for empty user")
+	}
+}
@@ -0,0 +1,172 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/biodoia/goleapifree/internal/jobs"
+	"github.com/biodoia/goleapifree/internal/storage"
+)
+
+// ExportJobType is the queue job type right-of-access exports run under.
+const ExportJobType = "privacy_export"
+
+// Export states.
+const (
+	ExportQueued    = "queued"
+	ExportRunning   = "running"
+	ExportSucceeded = "succeeded"
+	ExportFailed    = "failed"
+)
+
+// ErrExportNotFound is returned for unknown export IDs.
+var ErrExportNotFound = errors.New("privacy: export not found")
+
+// Exporter is implemented by each subsystem contributing a section to a
+// user's right-of-access archive (profile, key metadata, usage,
+// conversations, ...).
+type Exporter interface {
+	// Name becomes the section key in the archive document.
+	Name() string
+	// Export returns the user's data as JSON.
+	Export(ctx context.Context, user string) (json.RawMessage, error)
+}
+
+// Export tracks one right-of-access request through the job queue.
+type Export struct {
+	ID    string `json:"id"`
+	User  string `json:"user"`
+	State string `json:"state"`
+	Error string `json:"error,omitempty"`
+	// ArchiveKey locates the generated document in the archive once
+	// the export succeeds; the download URL is signed off this key.
+	ArchiveKey string    `json:"archive_key,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+}
+
+// ExportManager generates archives asynchronously on the job queue.
+type ExportManager struct {
+	Queue   jobs.Queue
+	Archive storage.Archive
+
+	mu        sync.Mutex
+	exporters []Exporter
+	exports   map[string]*Export
+}
+
+// NewExportManager creates a manager writing archives into archive.
+func NewExportManager(q jobs.Queue, archive storage.Archive) *ExportManager {
+	return &ExportManager{Queue: q, Archive: archive, exports: make(map[string]*Export)}
+}
+
+// Register adds a section source to future exports.
+func (m *ExportManager) Register(e Exporter) {
+	m.mu.Lock()
+	m.exporters = append(m.exporters, e)
+	m.mu.Unlock()
+}
+
+// Request enqueues an export for a user and returns the pollable record.
+func (m *ExportManager) Request(ctx context.Context, user string) (*Export, error) {
+	if user == "" {
+		return nil, errors.New("privacy: user is required")
+	}
+	e := &Export{ID: uuid.NewString(), User: user, State: ExportQueued, CreatedAt: time.Now()}
+	m.mu.Lock()
+	m.exports[e.ID] = e
+	m.mu.Unlock()
+
+	err := m.Queue.Enqueue(ctx, &jobs.Job{
+		ID: e.ID, Type: ExportJobType, Payload: json.RawMessage(fmt.Sprintf("%q", e.ID)), MaxRetries: 1,
+	})
+	if err != nil {
+		m.mu.Lock()
+		delete(m.exports, e.ID)
+		m.mu.Unlock()
+		return nil, err
+	}
+	copied := *e
+	return &copied, nil
+}
+
+// Get returns an export by ID.
+func (m *ExportManager) Get(id string) (*Export, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.exports[id]
+	if !ok {
+		return nil, ErrExportNotFound
+	}
+	copied := *e
+	return &copied, nil
+}
+
+// Handler returns the jobs.Pool handler that assembles archives.
+func (m *ExportManager) Handler() jobs.Handler {
+	return func(ctx context.Context, job *jobs.Job) error {
+		var id string
+		if err := json.Unmarshal(job.Payload, &id); err != nil {
+			return err
+		}
+		m.mu.Lock()
+		e, ok := m.exports[id]
+		if !ok {
+			m.mu.Unlock()
+			return nil
+		}
+		e.State = ExportRunning
+		user := e.User
+		exporters := append([]Exporter(nil), m.exporters...)
+		m.mu.Unlock()
+
+		key, err := m.assemble(ctx, id, user, exporters)
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		e.FinishedAt = time.Now()
+		if err != nil {
+			e.State = ExportFailed
+			e.Error = err.Error()
+			return nil
+		}
+		e.State = ExportSucceeded
+		e.ArchiveKey = key
+		return nil
+	}
+}
+
+// assemble gathers every section into one JSON document and archives it.
+// A section source failing fails the whole export: a partial
+// right-of-access answer is worse than a retried one.
+func (m *ExportManager) assemble(ctx context.Context, id, user string, exporters []Exporter) (string, error) {
+	doc := struct {
+		User        string                     `json:"user"`
+		GeneratedAt time.Time                  `json:"generated_at"`
+		Sections    map[string]json.RawMessage `json:"sections"`
+	}{User: user, GeneratedAt: time.Now(), Sections: make(map[string]json.RawMessage)}
+
+	for _, ex := range exporters {
+		data, err := ex.Export(ctx, user)
+		if err != nil {
+			return "", fmt.Errorf("section %s: %w", ex.Name(), err)
+		}
+		doc.Sections[ex.Name()] = data
+	}
+	payload, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("privacy/exports/%s.json", id)
+	if err := m.Archive.Put(key, strings.NewReader(string(payload))); err != nil {
+		return "", err
+	}
+	return key, nil
+}
@@ -0,0 +1,122 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/jobs"
+	"github.com/biodoia/goleapifree/internal/storage"
+)
+
+type fakeExporter struct {
+	name string
+	data string
+	fail bool
+}
+
+func (f *fakeExporter) Name() string { return f.name }
+
+func (f *fakeExporter) Export(context.Context, string) (json.RawMessage, error) {
+	if f.fail {
+		return nil, errors.New("store offline")
+	}
+	return json.RawMessage(f.data), nil
+}
+
+func drainExports(t *testing.T, q jobs.Queue, m *ExportManager) {
+	t.Helper()
+	h := m.Handler()
+	for {
+		job, err := q.Dequeue(context.Background(), time.Minute)
+		if errors.Is(err, jobs.ErrEmpty) {
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := h(context.Background(), job); err != nil {
+			t.Fatal(err)
+		}
+		if err := q.Ack(context.Background(), job.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestExportAssemblesAllSections(t *testing.T) {
+	archive, err := storage.NewFileArchive(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := jobs.NewMemoryQueue()
+	m := NewExportManager(q, archive)
+	m.Register(&fakeExporter{name: "profile", data: `{"user":"alice"}`})
+	m.Register(&fakeExporter{name: "usage", data: `[{"tokens":100}]`})
+
+	e, err := m.Request(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := m.Get(e.ID); got.State != ExportQueued {
+		t.Fatalf("state = %s", got.State)
+	}
+
+	drainExports(t, q, m)
+	got, err := m.Get(e.ID)
+	if err != nil || got.State != ExportSucceeded || got.ArchiveKey == "" {
+		t.Fatalf("export = %+v, %v", got, err)
+	}
+
+	obj, err := archive.Get(got.ArchiveKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer obj.Close()
+	body, _ := io.ReadAll(obj)
+	var doc struct {
+		User     string                     `json:"user"`
+		Sections map[string]json.RawMessage `json:"sections"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.User != "alice" || len(doc.Sections) != 2 {
+		t.Fatalf("doc = %+v", doc)
+	}
+	var profile struct {
+		User string `json:"user"`
+	}
+	if err := json.Unmarshal(doc.Sections["profile"], &profile); err != nil || profile.User != "alice" {
+		t.Fatalf("profile section = %s, %v", doc.Sections["profile"], err)
+	}
+}
+
+func TestFailingSectionFailsExport(t *testing.T) {
+	archive, _ := storage.NewFileArchive(t.TempDir())
+	q := jobs.NewMemoryQueue()
+	m := NewExportManager(q, archive)
+	m.Register(&fakeExporter{name: "profile", data: `{}`})
+	m.Register(&fakeExporter{name: "conversations", fail: true})
+
+	e, _ := m.Request(context.Background(), "alice")
+	drainExports(t, q, m)
+
+	got, _ := m.Get(e.ID)
+	if got.State != ExportFailed || got.ArchiveKey != "" {
+		t.Fatalf("export = %+v", got)
+	}
+	if got.Error == "" {
+		t.Fatal("failure must carry the section error")
+	}
+
+	if _, err := m.Get("missing"); !errors.Is(err, ErrExportNotFound) {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := m.Request(context.Background(), ""); err == nil {
+		t.Fatal("expected error for empty user")
+	}
+}
@@ -0,0 +1,201 @@
+// Package profiling pushes periodic CPU profiles to a continuous
+// profiling backend (Pyroscope's HTTP ingest protocol, which Parca
+// also accepts), so production latency regressions can be diagnosed
+// without attaching the debug CLI under load. Everything is gated by
+// config: without a server URL nothing runs and request phase labels
+// cost one context lookup.
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Profiling cadence defaults: a 10-second sample out of every
+// 15-second window keeps overhead in the low single digits.
+const (
+	defaultPeriod   = 15 * time.Second
+	defaultDuration = 10 * time.Second
+)
+
+// Config enables and shapes the profiler.
+type Config struct {
+	// ServerURL is the ingest endpoint base (e.g. http://pyroscope:4040);
+	// empty disables profiling entirely.
+	ServerURL string `yaml:"server_url"`
+	// AppName names the profile series; defaults to "goleapifree".
+	AppName string `yaml:"app_name"`
+	// Tags are static labels attached to every upload (region, build).
+	Tags map[string]string `yaml:"tags,omitempty"`
+	// Period is the collection cadence, Duration the sampled slice of
+	// each period.
+	Period   time.Duration `yaml:"period,omitempty"`
+	Duration time.Duration `yaml:"duration,omitempty"`
+}
+
+// Profiler runs the collection loop.
+type Profiler struct {
+	cfg    Config
+	client *http.Client
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu      sync.Mutex
+	uploads int
+	lastErr error
+}
+
+// Start launches the profiler; a config without a server URL returns
+// nil, which every method tolerates, so callers wire it unconditionally.
+func Start(cfg Config) *Profiler {
+	if cfg.ServerURL == "" {
+		return nil
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = "goleapifree"
+	}
+	if cfg.Period <= 0 {
+		cfg.Period = defaultPeriod
+	}
+	if cfg.Duration <= 0 || cfg.Duration > cfg.Period {
+		cfg.Duration = defaultDuration
+		if cfg.Duration > cfg.Period {
+			cfg.Duration = cfg.Period
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Profiler{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go p.loop(ctx)
+	return p
+}
+
+// Stop ends the collection loop and waits for it to finish.
+func (p *Profiler) Stop() {
+	if p == nil {
+		return
+	}
+	p.cancel()
+	<-p.done
+}
+
+// Status reports uploads so far and the last ingest error, for the
+// debug endpoints.
+func (p *Profiler) Status() (uploads int, lastErr error) {
+	if p == nil {
+		return 0, nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.uploads, p.lastErr
+}
+
+func (p *Profiler) loop(ctx context.Context) {
+	defer close(p.done)
+	ticker := time.NewTicker(p.cfg.Period)
+	defer ticker.Stop()
+	for {
+		if err := p.collect(ctx); err != nil && ctx.Err() == nil {
+			p.mu.Lock()
+			p.lastErr = err
+			p.mu.Unlock()
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// collect samples one CPU profile slice and pushes it.
+func (p *Profiler) collect(ctx context.Context) error {
+	var buf bytes.Buffer
+	from := time.Now()
+	if err := pprof.StartCPUProfile(&buf); err != nil {
+		return err
+	}
+	select {
+	case <-time.After(p.cfg.Duration):
+	case <-ctx.Done():
+	}
+	pprof.StopCPUProfile()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return p.upload(ctx, buf.Bytes(), from, time.Now())
+}
+
+// upload pushes one pprof payload to the ingest endpoint.
+func (p *Profiler) upload(ctx context.Context, profile []byte, from, until time.Time) error {
+	q := url.Values{}
+	q.Set("name", p.seriesName())
+	q.Set("from", fmt.Sprintf("%d", from.Unix()))
+	q.Set("until", fmt.Sprintf("%d", until.Unix()))
+	q.Set("format", "pprof")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(p.cfg.ServerURL, "/")+"/ingest?"+q.Encode(), bytes.NewReader(profile))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("profiling: ingest returned %s", resp.Status)
+	}
+	p.mu.Lock()
+	p.uploads++
+	p.lastErr = nil
+	p.mu.Unlock()
+	return nil
+}
+
+// seriesName renders name{tag=value,...} with sorted tags, the label
+// syntax both Pyroscope and Parca accept.
+func (p *Profiler) seriesName() string {
+	if len(p.cfg.Tags) == 0 {
+		return p.cfg.AppName
+	}
+	keys := make([]string, 0, len(p.cfg.Tags))
+	for k := range p.cfg.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+p.cfg.Tags[k])
+	}
+	return p.cfg.AppName + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// phaseLabel is the pprof label naming the request phase.
+const phaseLabel = "phase"
+
+// WithPhase runs fn with a request-phase pprof label ("route",
+// "upstream", "stream", ...) so flame graphs split by phase. Labels
+// flow through the context into every goroutine fn starts.
+func WithPhase(ctx context.Context, phase string, fn func(context.Context)) {
+	pprof.Do(ctx, pprof.Labels(phaseLabel, phase), fn)
+}
+
+// Phase returns the current request phase label, if any.
+func Phase(ctx context.Context) string {
+	v, _ := pprof.Label(ctx, phaseLabel)
+	return v
+}
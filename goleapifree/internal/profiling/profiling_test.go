@@ -0,0 +1,72 @@
+package profiling
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDisabledWithoutServerURL(t *testing.T) {
+	p := Start(Config{})
+	if p != nil {
+		t.Fatal("profiler started without a server URL")
+	}
+	p.Stop() // nil-safe
+	if uploads, err := p.Status(); uploads != 0 || err != nil {
+		t.Fatalf("Status = %d, %v", uploads, err)
+	}
+}
+
+func TestCollectAndIngest(t *testing.T) {
+	var mu sync.Mutex
+	var names []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ingest" || r.URL.Query().Get("format") != "pprof" {
+			t.Errorf("unexpected ingest request: %s", r.URL)
+		}
+		mu.Lock()
+		names = append(names, r.URL.Query().Get("name"))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	p := Start(Config{
+		ServerURL: srv.URL,
+		AppName:   "gateway",
+		Tags:      map[string]string{"region": "eu", "build": "dev"},
+		Period:    50 * time.Millisecond,
+		Duration:  10 * time.Millisecond,
+	})
+	defer p.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if uploads, _ := p.Status(); uploads > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			_, err := p.Status()
+			t.Fatalf("no upload before deadline, lastErr = %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if names[0] != "gateway{build=dev,region=eu}" {
+		t.Fatalf("series name = %q", names[0])
+	}
+}
+
+func TestPhaseLabels(t *testing.T) {
+	if Phase(context.Background()) != "" {
+		t.Fatal("unexpected phase on fresh context")
+	}
+	WithPhase(context.Background(), "upstream", func(ctx context.Context) {
+		if Phase(ctx) != "upstream" {
+			t.Fatalf("phase = %q", Phase(ctx))
+		}
+	})
+}
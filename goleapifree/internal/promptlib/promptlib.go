@@ -0,0 +1,216 @@
+// Package promptlib imports and exports prompt packs: bundles of agent
+// configs (prompt templates and chain definitions) signed by their
+// publisher, so deployments can share curated packs without copy-paste.
+// Imported bundles land in a review queue; nothing touches the live
+// agent registry until an admin approves it.
+package promptlib
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/biodoia/goleapifree/internal/agents"
+)
+
+// Review states of an imported bundle.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Import errors.
+var (
+	ErrUnknownPublisher = errors.New("promptlib: bundle publisher is not trusted")
+	ErrBadSignature     = errors.New("promptlib: bundle signature verification failed")
+	ErrNotFound         = errors.New("promptlib: bundle not found")
+	ErrDecided          = errors.New("promptlib: bundle already reviewed")
+)
+
+// Bundle is the shareable payload: a named pack of agent configs.
+type Bundle struct {
+	Name      string          `json:"name"`
+	Publisher string          `json:"publisher"`
+	CreatedAt time.Time       `json:"created_at"`
+	Agents    []agents.Config `json:"agents"`
+}
+
+// signedBundle is the wire format. The signature covers the compact
+// bundle bytes, so pretty-printing a bundle file by hand (or the
+// indented export itself) does not break verification.
+type signedBundle struct {
+	Bundle    json.RawMessage `json:"bundle"`
+	Signature []byte          `json:"signature"`
+}
+
+// Export serializes and signs a bundle with the publisher's key.
+func Export(bundle Bundle, key ed25519.PrivateKey) ([]byte, error) {
+	if bundle.Name == "" || bundle.Publisher == "" {
+		return nil, errors.New("promptlib: bundle needs a name and publisher")
+	}
+	if len(bundle.Agents) == 0 {
+		return nil, errors.New("promptlib: bundle has no agents")
+	}
+	raw, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(signedBundle{
+		Bundle:    raw,
+		Signature: ed25519.Sign(key, raw),
+	}, "", "  ")
+}
+
+// PendingBundle is one import awaiting (or past) review.
+type PendingBundle struct {
+	ID         string    `json:"id"`
+	Bundle     Bundle    `json:"bundle"`
+	ReceivedAt time.Time `json:"received_at"`
+	Status     string    `json:"status"`
+	// Reason carries the rejection note.
+	Reason     string     `json:"reason,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// Library verifies imported bundles and runs the review queue.
+type Library struct {
+	// Registry receives approved configs; nil leaves approval a no-op
+	// beyond the status change, which tests use.
+	Registry *agents.ConfigRegistry
+
+	now func() time.Time
+
+	mu      sync.Mutex
+	trusted map[string]ed25519.PublicKey
+	queue   []*PendingBundle
+}
+
+// NewLibrary creates a library with no trusted publishers.
+func NewLibrary(registry *agents.ConfigRegistry) *Library {
+	return &Library{
+		Registry: registry,
+		now:      time.Now,
+		trusted:  make(map[string]ed25519.PublicKey),
+	}
+}
+
+// Trust registers a publisher's verification key.
+func (l *Library) Trust(publisher string, key ed25519.PublicKey) {
+	l.mu.Lock()
+	l.trusted[publisher] = key
+	l.mu.Unlock()
+}
+
+// Import verifies a signed bundle against its publisher's trusted key
+// and queues it for review. The bundle does not activate here.
+func (l *Library) Import(data []byte) (PendingBundle, error) {
+	var signed signedBundle
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return PendingBundle{}, fmt.Errorf("promptlib: malformed bundle: %w", err)
+	}
+	var bundle Bundle
+	if err := json.Unmarshal(signed.Bundle, &bundle); err != nil {
+		return PendingBundle{}, fmt.Errorf("promptlib: malformed bundle: %w", err)
+	}
+	l.mu.Lock()
+	key, ok := l.trusted[bundle.Publisher]
+	l.mu.Unlock()
+	if !ok {
+		return PendingBundle{}, ErrUnknownPublisher
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, signed.Bundle); err != nil {
+		return PendingBundle{}, fmt.Errorf("promptlib: malformed bundle: %w", err)
+	}
+	if !ed25519.Verify(key, compact.Bytes(), signed.Signature) {
+		return PendingBundle{}, ErrBadSignature
+	}
+
+	pending := &PendingBundle{
+		ID:         uuid.NewString(),
+		Bundle:     bundle,
+		ReceivedAt: l.now(),
+		Status:     StatusPending,
+	}
+	l.mu.Lock()
+	l.queue = append(l.queue, pending)
+	l.mu.Unlock()
+	return *pending, nil
+}
+
+// Approve activates a pending bundle: every agent config in it is
+// applied through the registry, which bumps versions and notifies
+// watchers like any admin edit.
+func (l *Library) Approve(ctx context.Context, id string) error {
+	l.mu.Lock()
+	pending := l.findLocked(id)
+	if pending == nil {
+		l.mu.Unlock()
+		return ErrNotFound
+	}
+	if pending.Status != StatusPending {
+		l.mu.Unlock()
+		return ErrDecided
+	}
+	now := l.now()
+	pending.Status = StatusApproved
+	pending.ReviewedAt = &now
+	bundle := pending.Bundle
+	l.mu.Unlock()
+
+	if l.Registry != nil {
+		for _, cfg := range bundle.Agents {
+			if _, err := l.Registry.Apply(ctx, cfg); err != nil {
+				return fmt.Errorf("promptlib: activating %s: %w", cfg.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Reject closes a pending bundle without activating anything.
+func (l *Library) Reject(id, reason string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pending := l.findLocked(id)
+	if pending == nil {
+		return ErrNotFound
+	}
+	if pending.Status != StatusPending {
+		return ErrDecided
+	}
+	now := l.now()
+	pending.Status = StatusRejected
+	pending.Reason = reason
+	pending.ReviewedAt = &now
+	return nil
+}
+
+// Queue lists every imported bundle, oldest first.
+func (l *Library) Queue() []PendingBundle {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]PendingBundle, 0, len(l.queue))
+	for _, p := range l.queue {
+		out = append(out, *p)
+	}
+	return out
+}
+
+// findLocked locates a queued bundle by ID. Callers hold l.mu.
+func (l *Library) findLocked(id string) *PendingBundle {
+	for _, p := range l.queue {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
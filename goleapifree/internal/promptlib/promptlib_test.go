@@ -0,0 +1,113 @@
+package promptlib
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/agents"
+)
+
+func testBundle() Bundle {
+	return Bundle{
+		Name:      "curated-coding-pack",
+		Publisher: "platform-team",
+		CreatedAt: time.Date(2026, 9, 1, 17, 0, 0, 0, time.UTC),
+		Agents: []agents.Config{
+			{Name: "reviewer", SystemPrompt: "Review the diff.", Model: "smart"},
+			{Name: "tester", SystemPrompt: "Write tests.", Model: "fast"},
+		},
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Export(testBundle(), priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := agents.NewConfigRegistry(nil)
+	l := NewLibrary(registry)
+	l.Trust("platform-team", pub)
+
+	pending, err := l.Import(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Status != StatusPending || pending.Bundle.Name != "curated-coding-pack" {
+		t.Fatalf("pending = %+v", pending)
+	}
+	// Nothing activates before review.
+	if _, err := registry.Get("reviewer"); !errors.Is(err, agents.ErrAgentNotFound) {
+		t.Fatalf("config live before approval: %v", err)
+	}
+
+	if err := l.Approve(context.Background(), pending.ID); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := registry.Get("reviewer")
+	if err != nil || cfg.Model != "smart" {
+		t.Fatalf("cfg = %+v, err = %v", cfg, err)
+	}
+	if err := l.Approve(context.Background(), pending.ID); !errors.Is(err, ErrDecided) {
+		t.Fatalf("double approve: %v", err)
+	}
+}
+
+func TestImportGuards(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	_, otherPriv, _ := ed25519.GenerateKey(nil)
+	l := NewLibrary(nil)
+
+	data, err := Export(testBundle(), priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Import(data); !errors.Is(err, ErrUnknownPublisher) {
+		t.Fatalf("untrusted publisher: %v", err)
+	}
+
+	l.Trust("platform-team", pub)
+	forged, err := Export(testBundle(), otherPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Import(forged); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("forged signature: %v", err)
+	}
+	if _, err := l.Import([]byte("not json")); err == nil {
+		t.Fatal("malformed bundle accepted")
+	}
+}
+
+func TestReject(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	registry := agents.NewConfigRegistry(nil)
+	l := NewLibrary(registry)
+	l.Trust("platform-team", pub)
+
+	data, _ := Export(testBundle(), priv)
+	pending, err := l.Import(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Reject(pending.ID, "prompts conflict with house style"); err != nil {
+		t.Fatal(err)
+	}
+	queue := l.Queue()
+	if len(queue) != 1 || queue[0].Status != StatusRejected || queue[0].Reason == "" {
+		t.Fatalf("queue = %+v", queue)
+	}
+	if err := l.Approve(context.Background(), pending.ID); !errors.Is(err, ErrDecided) {
+		t.Fatalf("approve after reject: %v", err)
+	}
+	if err := l.Reject("ghost", ""); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unknown id: %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The adapter contract: every adapter must normalize responses the same
+// way, stream deltas in order with a terminal Done chunk, and surface
+// upstream failures as errors. Each adapter runs the shared checks
+// against recorded wire fixtures for its own protocol, so adding a
+// provider means writing fixtures, not inventing new assertions.
+
+const (
+	contractContent = "alpha beta"
+	contractTokens  = 10
+	contractPrompt  = "contract ping"
+)
+
+// contractCase binds one adapter to its wire fixtures.
+type contractCase struct {
+	// model is the request model name in the adapter's dialect.
+	model string
+	// build constructs the adapter against a stub server running handler.
+	build func(t *testing.T, handler http.HandlerFunc) Adapter
+	// chat and stream serve the recorded success fixtures.
+	chat   http.HandlerFunc
+	stream http.HandlerFunc
+	// verifyRequest asserts the provider-native body round-trips the
+	// normalized request.
+	verifyRequest func(t *testing.T, body []byte)
+}
+
+func runAdapterContract(t *testing.T, c contractCase) {
+	req := ChatRequest{Model: c.model, Messages: []Message{{Role: "user", Content: contractPrompt}}}
+
+	t.Run("chat_normalizes_response", func(t *testing.T) {
+		var captured []byte
+		a := c.build(t, captureBody(&captured, c.chat))
+		resp, err := a.Chat(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Content != contractContent {
+			t.Fatalf("content = %q, want %q", resp.Content, contractContent)
+		}
+		if resp.Usage.TotalTokens != contractTokens {
+			t.Fatalf("usage = %+v", resp.Usage)
+		}
+		c.verifyRequest(t, captured)
+	})
+
+	t.Run("stream_orders_chunks_and_terminates", func(t *testing.T) {
+		a := c.build(t, c.stream)
+		ch, err := a.ChatStream(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var text strings.Builder
+		var sawDone bool
+		var usage *Usage
+		for chunk := range ch {
+			if chunk.Err != nil {
+				t.Fatalf("stream error: %v", chunk.Err)
+			}
+			if sawDone {
+				t.Fatal("chunk received after Done")
+			}
+			if chunk.Done {
+				sawDone = true
+				usage = chunk.Usage
+				continue
+			}
+			text.WriteString(chunk.Delta)
+		}
+		if !sawDone {
+			t.Fatal("stream ended without a Done chunk")
+		}
+		if text.String() != contractContent {
+			t.Fatalf("assembled text = %q, want %q", text.String(), contractContent)
+		}
+		if usage == nil || usage.TotalTokens != contractTokens {
+			t.Fatalf("final usage = %+v", usage)
+		}
+	})
+
+	t.Run("error_mapping", func(t *testing.T) {
+		upstream := func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"error":{"message":"overloaded"}}`)
+		}
+		a := c.build(t, upstream)
+		if _, err := a.Chat(context.Background(), req); err == nil {
+			t.Fatal("Chat swallowed an upstream failure")
+		}
+		if _, err := a.ChatStream(context.Background(), req); err == nil {
+			t.Fatal("ChatStream swallowed an upstream failure")
+		}
+	})
+}
+
+// captureBody snapshots the request body before handing it to next.
+func captureBody(dst *[]byte, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*dst = body
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next(w, r)
+	}
+}
+
+func TestOpenRouterContract(t *testing.T) {
+	runAdapterContract(t, contractCase{
+		model: "meta-llama/llama-3-8b",
+		build: func(t *testing.T, handler http.HandlerFunc) Adapter {
+			srv := httptest.NewServer(handler)
+			t.Cleanup(srv.Close)
+			return &OpenRouter{APIKey: "k", Client: srv.Client(), Endpoint: srv.URL}
+		},
+		chat: func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"id":"gen-1","model":"meta-llama/llama-3-8b",
+				"choices":[{"message":{"content":"alpha beta"}}],
+				"usage":{"prompt_tokens":7,"completion_tokens":3,"total_tokens":10}}`)
+		},
+		stream: func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"alpha \"}}]}\n\n")
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"beta\"}}],\"usage\":{\"prompt_tokens\":7,\"completion_tokens\":3,\"total_tokens\":10}}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		},
+		verifyRequest: func(t *testing.T, body []byte) {
+			var parsed struct {
+				Model    string    `json:"model"`
+				Messages []Message `json:"messages"`
+			}
+			if err := json.Unmarshal(body, &parsed); err != nil {
+				t.Fatal(err)
+			}
+			if parsed.Model != "meta-llama/llama-3-8b" {
+				t.Fatalf("model = %q", parsed.Model)
+			}
+			if len(parsed.Messages) != 1 || parsed.Messages[0].Role != "user" || parsed.Messages[0].Content != contractPrompt {
+				t.Fatalf("messages = %+v", parsed.Messages)
+			}
+		},
+	})
+}
+
+func TestVertexContract(t *testing.T) {
+	withToken := func(handler http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/token" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "tok", "expires_in": 3600})
+				return
+			}
+			handler(w, r)
+		}
+	}
+	runAdapterContract(t, contractCase{
+		model: "gemini-1.5-flash",
+		build: func(t *testing.T, handler http.HandlerFunc) Adapter {
+			return newTestVertex(t, withToken(handler))
+		},
+		chat: func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"candidates":[{"content":{"parts":[{"text":"alpha "},{"text":"beta"}]}}],
+				"usageMetadata":{"promptTokenCount":7,"candidatesTokenCount":3,"totalTokenCount":10}}`)
+		},
+		stream: func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"alpha \"}]}}]}\n\n")
+			fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"beta\"}]}}],\"usageMetadata\":{\"promptTokenCount\":7,\"candidatesTokenCount\":3,\"totalTokenCount\":10}}\n\n")
+		},
+		verifyRequest: func(t *testing.T, body []byte) {
+			var parsed struct {
+				Contents []struct {
+					Role  string `json:"role"`
+					Parts []struct {
+						Text string `json:"text"`
+					} `json:"parts"`
+				} `json:"contents"`
+			}
+			if err := json.Unmarshal(body, &parsed); err != nil {
+				t.Fatal(err)
+			}
+			if len(parsed.Contents) != 1 || parsed.Contents[0].Role != "user" || parsed.Contents[0].Parts[0].Text != contractPrompt {
+				t.Fatalf("contents = %+v", parsed.Contents)
+			}
+		},
+	})
+}
+
+func TestBedrockContract(t *testing.T) {
+	anthropicEvent := func(v any) []byte {
+		inner, _ := json.Marshal(v)
+		payload, _ := json.Marshal(map[string]string{"bytes": base64.StdEncoding.EncodeToString(inner)})
+		return encodeEventStreamFrame(payload)
+	}
+	runAdapterContract(t, contractCase{
+		model: "anthropic.claude-3-haiku-20240307-v1:0",
+		build: func(t *testing.T, handler http.HandlerFunc) Adapter {
+			srv := httptest.NewServer(handler)
+			t.Cleanup(srv.Close)
+			return &Bedrock{
+				Region: "us-east-1", AccessKeyID: "AKID", SecretAccessKey: "secret",
+				Client: srv.Client(), Endpoint: srv.URL, now: time.Now,
+			}
+		},
+		chat: func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"id":"msg-1","content":[{"text":"alpha "},{"text":"beta"}],
+				"usage":{"input_tokens":7,"output_tokens":3}}`)
+		},
+		stream: func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(anthropicEvent(map[string]any{
+				"type": "content_block_delta", "delta": map[string]string{"text": "alpha "},
+			}))
+			_, _ = w.Write(anthropicEvent(map[string]any{
+				"type": "content_block_delta", "delta": map[string]string{"text": "beta"},
+			}))
+			_, _ = w.Write(anthropicEvent(map[string]any{
+				"type": "message_delta", "usage": map[string]int{"input_tokens": 7, "output_tokens": 3},
+			}))
+		},
+		verifyRequest: func(t *testing.T, body []byte) {
+			var parsed struct {
+				Messages []struct {
+					Role    string `json:"role"`
+					Content string `json:"content"`
+				} `json:"messages"`
+				MaxTokens int `json:"max_tokens"`
+			}
+			if err := json.Unmarshal(body, &parsed); err != nil {
+				t.Fatal(err)
+			}
+			if len(parsed.Messages) != 1 || parsed.Messages[0].Role != "user" || parsed.Messages[0].Content != contractPrompt {
+				t.Fatalf("messages = %+v", parsed.Messages)
+			}
+			if parsed.MaxTokens == 0 {
+				t.Fatal("max_tokens default not applied")
+			}
+		},
+	})
+}
@@ -0,0 +1,77 @@
+package providers
+
+import "strings"
+
+// Conversation normalization: when a request fails over mid-conversation,
+// the history was shaped by whatever provider answered the earlier turns.
+// Strict providers reject histories the lenient ones produced — repeated
+// roles, system messages mid-thread, assistant-first conversations. Each
+// provider declares its requirements as ConversationRules and the proxy
+// rewrites the history to match before dispatch.
+
+// ConversationRules describes the history shape one provider accepts.
+type ConversationRules struct {
+	// SingleSystem merges every system message into one leading turn.
+	SingleSystem bool
+	// MergeAlternating merges consecutive same-role turns so the
+	// conversation strictly alternates between user and assistant.
+	MergeAlternating bool
+	// LeadingUser requires the first non-system turn to be a user turn;
+	// a neutral continuation turn is synthesized when it is not.
+	LeadingUser bool
+}
+
+// RulesFor returns the conversation rules for a provider type name.
+// Unknown providers get the permissive zero rules, which normalize to
+// the identity.
+func RulesFor(provider string) ConversationRules {
+	switch provider {
+	case "bedrock":
+		// Anthropic's messages schema: one system field, strict
+		// user/assistant alternation, user-first.
+		return ConversationRules{SingleSystem: true, MergeAlternating: true, LeadingUser: true}
+	case "vertex":
+		// Gemini: one systemInstruction, user-first contents.
+		return ConversationRules{SingleSystem: true, LeadingUser: true}
+	default:
+		return ConversationRules{}
+	}
+}
+
+// Normalize rewrites a conversation history to satisfy the rules. The
+// input is never mutated; permissive rules return it as-is.
+func (r ConversationRules) Normalize(msgs []Message) []Message {
+	if !r.SingleSystem && !r.MergeAlternating && !r.LeadingUser {
+		return msgs
+	}
+	out := make([]Message, 0, len(msgs))
+	var system []string
+	for _, m := range msgs {
+		if m.Content == "" {
+			continue
+		}
+		if r.SingleSystem && m.Role == "system" {
+			system = append(system, m.Content)
+			continue
+		}
+		out = append(out, m)
+	}
+	if r.LeadingUser && len(out) > 0 && out[0].Role != "user" {
+		out = append([]Message{{Role: "user", Content: "(conversation continued)"}}, out...)
+	}
+	if r.MergeAlternating {
+		merged := out[:0:0]
+		for _, m := range out {
+			if n := len(merged); n > 0 && merged[n-1].Role == m.Role {
+				merged[n-1].Content += "\n\n" + m.Content
+				continue
+			}
+			merged = append(merged, m)
+		}
+		out = merged
+	}
+	if len(system) > 0 {
+		out = append([]Message{{Role: "system", Content: strings.Join(system, "\n\n")}}, out...)
+	}
+	return out
+}
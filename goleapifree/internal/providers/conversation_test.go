@@ -0,0 +1,69 @@
+package providers
+
+import "testing"
+
+func TestNormalizeMergesSystemsAndAlternation(t *testing.T) {
+	history := []Message{
+		{Role: "system", Content: "be brief"},
+		{Role: "user", Content: "first question"},
+		{Role: "system", Content: "answer in English"},
+		{Role: "assistant", Content: "first answer"},
+		{Role: "assistant", Content: "with an addendum"},
+		{Role: "user", Content: "second question"},
+	}
+	got := RulesFor("bedrock").Normalize(history)
+
+	want := []Message{
+		{Role: "system", Content: "be brief\n\nanswer in English"},
+		{Role: "user", Content: "first question"},
+		{Role: "assistant", Content: "first answer\n\nwith an addendum"},
+		{Role: "user", Content: "second question"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("normalized = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("message %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	// The caller's slice must be untouched.
+	if history[2].Role != "system" {
+		t.Fatal("input history mutated")
+	}
+}
+
+func TestNormalizeSynthesizesLeadingUserTurn(t *testing.T) {
+	// Failover after a prefill: the history starts with an assistant turn.
+	history := []Message{
+		{Role: "assistant", Content: "partial answer"},
+		{Role: "user", Content: "go on"},
+	}
+	got := RulesFor("vertex").Normalize(history)
+	if got[0].Role != "user" || got[1].Role != "assistant" {
+		t.Fatalf("normalized = %+v", got)
+	}
+}
+
+func TestNormalizeDropsEmptyTurns(t *testing.T) {
+	history := []Message{
+		{Role: "user", Content: "question"},
+		{Role: "assistant", Content: ""},
+		{Role: "user", Content: "again"},
+	}
+	got := RulesFor("bedrock").Normalize(history)
+	if len(got) != 1 || got[0].Content != "question\n\nagain" {
+		t.Fatalf("normalized = %+v", got)
+	}
+}
+
+func TestNormalizePermissiveIsIdentity(t *testing.T) {
+	history := []Message{
+		{Role: "assistant", Content: "hi"},
+		{Role: "system", Content: "late system"},
+	}
+	got := RulesFor("openrouter").Normalize(history)
+	if len(got) != 2 || got[1].Role != "system" {
+		t.Fatalf("normalized = %+v", got)
+	}
+}
@@ -0,0 +1,131 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+// Egress control: a prompt-injected tool call or a mistyped base URL can
+// point an outbound request at internal infrastructure. The guard pins
+// upstream HTTP to the hosts of registered providers (plus explicit
+// extras), turning such SSRF attempts into a local error.
+
+// ErrEgressBlocked is returned for outbound requests to hosts outside
+// the allowlist.
+var ErrEgressBlocked = errors.New("providers: egress blocked")
+
+// EgressGuard is a host allowlist applied to outbound transports. The
+// provider-derived set is replaced wholesale on reconfiguration while
+// manually allowed hosts persist.
+type EgressGuard struct {
+	mu      sync.RWMutex
+	derived map[string]struct{} // from registered providers
+	manual  map[string]struct{} // from Allow
+}
+
+// NewEgressGuard returns a guard that initially allows the given hosts.
+func NewEgressGuard(hosts ...string) *EgressGuard {
+	g := &EgressGuard{
+		derived: make(map[string]struct{}),
+		manual:  make(map[string]struct{}),
+	}
+	g.Allow(hosts...)
+	return g
+}
+
+// Allow adds hosts to the allowlist permanently (webhook endpoints,
+// token services and other non-provider destinations).
+func (g *EgressGuard) Allow(hosts ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, h := range hosts {
+		if h = normalizeHost(h); h != "" {
+			g.manual[h] = struct{}{}
+		}
+	}
+}
+
+// SetProviders rebuilds the provider-derived part of the allowlist from
+// the registered providers' base URLs. Call it whenever the provider
+// set changes.
+func (g *EgressGuard) SetProviders(providers []*models.Provider) {
+	derived := make(map[string]struct{}, len(providers))
+	for _, p := range providers {
+		u, err := url.Parse(p.BaseURL)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		derived[normalizeHost(u.Hostname())] = struct{}{}
+	}
+	g.mu.Lock()
+	g.derived = derived
+	g.mu.Unlock()
+}
+
+// Allowed reports whether a host (without port) may be dialed.
+func (g *EgressGuard) Allowed(host string) bool {
+	host = normalizeHost(host)
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if _, ok := g.derived[host]; ok {
+		return true
+	}
+	_, ok := g.manual[host]
+	return ok
+}
+
+// Hosts returns the current allowlist, for diagnostics.
+func (g *EgressGuard) Hosts() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	out := make([]string, 0, len(g.derived)+len(g.manual))
+	for h := range g.derived {
+		out = append(out, h)
+	}
+	for h := range g.manual {
+		if _, dup := g.derived[h]; !dup {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// Wrap returns a RoundTripper that rejects requests to hosts outside
+// the allowlist before any connection is made. A nil base uses
+// http.DefaultTransport. Redirects re-enter RoundTrip, so a redirect to
+// a blocked host is caught too.
+func (g *EgressGuard) Wrap(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &guardedTransport{guard: g, next: base}
+}
+
+type guardedTransport struct {
+	guard *EgressGuard
+	next  http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *guardedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	if !t.guard.Allowed(host) {
+		return nil, fmt.Errorf("%w: %q is not a registered provider host", ErrEgressBlocked, host)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// normalizeHost lowercases a host and strips a stray port suffix.
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if i := strings.LastIndexByte(host, ':'); i >= 0 && !strings.Contains(host, "]") && strings.Count(host, ":") == 1 {
+		host = host[:i]
+	}
+	return strings.Trim(host, "[]")
+}
@@ -0,0 +1,61 @@
+package providers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+func TestEgressGuardDerivesHostsFromProviders(t *testing.T) {
+	g := NewEgressGuard()
+	g.SetProviders([]*models.Provider{
+		{ID: "p1", BaseURL: "https://Api.Example.com/v1"},
+		{ID: "p2", BaseURL: "http://localhost:11434"},
+		{ID: "bad", BaseURL: "not a url"},
+	})
+
+	for _, host := range []string{"api.example.com", "API.EXAMPLE.COM", "localhost"} {
+		if !g.Allowed(host) {
+			t.Fatalf("host %q not allowed", host)
+		}
+	}
+	if g.Allowed("169.254.169.254") {
+		t.Fatal("metadata endpoint allowed")
+	}
+
+	// Reconfiguration replaces derived hosts but keeps manual ones.
+	g.Allow("hooks.example.net")
+	g.SetProviders([]*models.Provider{{ID: "p2", BaseURL: "http://localhost:11434"}})
+	if g.Allowed("api.example.com") {
+		t.Fatal("removed provider host still allowed")
+	}
+	if !g.Allowed("hooks.example.net") {
+		t.Fatal("manually allowed host dropped on reconfiguration")
+	}
+}
+
+func TestEgressGuardBlocksUnlistedHosts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	g := NewEgressGuard()
+	client := &http.Client{Transport: g.Wrap(nil)}
+
+	if _, err := client.Get(srv.URL); !errors.Is(err, ErrEgressBlocked) {
+		t.Fatalf("err = %v, want ErrEgressBlocked", err)
+	}
+
+	g.Allow(u.Hostname())
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("allowed host blocked: %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ImageRequest is a normalized text-to-image generation request.
+type ImageRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Size   string `json:"size,omitempty"`
+	N      int    `json:"n,omitempty"`
+}
+
+// GeneratedImage is one produced image.
+type GeneratedImage struct {
+	Data []byte `json:"-"`
+	MIME string `json:"mime"`
+}
+
+// ImageAdapter is implemented by providers that can generate images.
+type ImageAdapter interface {
+	// GenerateImages produces n images for the prompt.
+	GenerateImages(ctx context.Context, req ImageRequest) ([]GeneratedImage, error)
+}
+
+// SDXLEndpoint adapts the common SDXL-style HTTP endpoints (OpenAI images
+// API shape with base64 responses) that most free image hosts expose.
+type SDXLEndpoint struct {
+	BaseURL string `json:"base_url"`
+	APIKey  string `json:"api_key"`
+	// CostPerImage is the accounted cost per generated image in USD;
+	// free tiers use 0 but still count against image quotas.
+	CostPerImage float64 `json:"cost_per_image"`
+
+	Client *http.Client `json:"-"`
+}
+
+// GenerateImages implements ImageAdapter.
+func (s *SDXLEndpoint) GenerateImages(ctx context.Context, req ImageRequest) ([]GeneratedImage, error) {
+	if req.N <= 0 {
+		req.N = 1
+	}
+	body, err := json.Marshal(map[string]any{
+		"model":           req.Model,
+		"prompt":          req.Prompt,
+		"size":            req.Size,
+		"n":               req.N,
+		"response_format": "b64_json",
+	})
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.BaseURL+"/v1/images/generations", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if s.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+s.APIKey)
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("images: %s: %s", resp.Status, errBody)
+	}
+	var parsed struct {
+		Data []struct {
+			B64JSON string `json:"b64_json"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("images: parsing response: %w", err)
+	}
+	out := make([]GeneratedImage, 0, len(parsed.Data))
+	for _, d := range parsed.Data {
+		raw, err := base64.StdEncoding.DecodeString(d.B64JSON)
+		if err != nil {
+			return nil, fmt.Errorf("images: decoding image data: %w", err)
+		}
+		out = append(out, GeneratedImage{Data: raw, MIME: "image/png"})
+	}
+	return out, nil
+}
@@ -0,0 +1,267 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+func init() {
+	Register("openrouter", func(config json.RawMessage) (Adapter, error) {
+		o := &OpenRouter{Client: http.DefaultClient}
+		if err := json.Unmarshal(config, o); err != nil {
+			return nil, fmt.Errorf("openrouter: parsing config: %w", err)
+		}
+		if o.APIKey == "" {
+			return nil, fmt.Errorf("openrouter: api_key is required")
+		}
+		return o, nil
+	})
+}
+
+// OpenRouter federates with the OpenRouter aggregator, typically as the
+// fallback pool once local free providers are exhausted. It forwards
+// provider preferences, tracks OpenRouter's per-model pricing for the cost
+// tables, and surfaces generation IDs for traceability.
+type OpenRouter struct {
+	APIKey string `json:"api_key"`
+	// Preferences is OpenRouter's "provider" routing preference object,
+	// forwarded verbatim (order, allow_fallbacks, quantizations, ...).
+	Preferences json.RawMessage `json:"preferences,omitempty"`
+	// Referer and Title feed OpenRouter's attribution headers.
+	Referer string `json:"referer,omitempty"`
+	Title   string `json:"title,omitempty"`
+
+	Client *http.Client `json:"-"`
+	// Endpoint overrides the API base, for tests.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	pricingMu sync.RWMutex
+	pricing   map[string]ModelPricing
+}
+
+// ModelPricing is OpenRouter's advertised per-token pricing in USD.
+type ModelPricing struct {
+	PromptPerToken     float64 `json:"prompt_per_token"`
+	CompletionPerToken float64 `json:"completion_per_token"`
+}
+
+// Name implements Adapter.
+func (o *OpenRouter) Name() string { return "openrouter" }
+
+func (o *OpenRouter) baseURL() string {
+	if o.Endpoint != "" {
+		return o.Endpoint
+	}
+	return "https://openrouter.ai/api"
+}
+
+// RequestBody builds the OpenAI-style body with preferences attached.
+func (o *OpenRouter) RequestBody(req ChatRequest, stream bool) ([]byte, error) {
+	body := map[string]any{
+		"model":    req.Model,
+		"messages": req.Messages,
+		"stream":   stream,
+	}
+	if req.MaxTokens > 0 {
+		body["max_tokens"] = req.MaxTokens
+	}
+	if req.Temperature > 0 {
+		body["temperature"] = req.Temperature
+	}
+	if len(o.Preferences) > 0 {
+		body["provider"] = o.Preferences
+	}
+	return json.Marshal(body)
+}
+
+func (o *OpenRouter) newRequest(ctx context.Context, path string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL()+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+o.APIKey)
+	if o.Referer != "" {
+		req.Header.Set("HTTP-Referer", o.Referer)
+	}
+	if o.Title != "" {
+		req.Header.Set("X-Title", o.Title)
+	}
+	return req, nil
+}
+
+// Chat implements Adapter.
+func (o *OpenRouter) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	body, err := o.RequestBody(req, false)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := o.newRequest(ctx, "/v1/chat/completions", body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.Client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openrouter: %s: %s", resp.Status, raw)
+	}
+	var parsed struct {
+		ID      string `json:"id"`
+		Model   string `json:"model"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage Usage `json:"usage"`
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("openrouter: parsing response: %w", err)
+	}
+	out := &ChatResponse{
+		// OpenRouter's id ("gen-...") is its generation ID; keeping it as
+		// the response ID makes requests traceable in their dashboard.
+		ID:    parsed.ID,
+		Model: parsed.Model,
+		Usage: parsed.Usage,
+		Raw:   raw,
+	}
+	if len(parsed.Choices) > 0 {
+		out.Content = parsed.Choices[0].Message.Content
+	}
+	return out, nil
+}
+
+// ChatStream implements Adapter via OpenAI-style SSE.
+func (o *OpenRouter) ChatStream(ctx context.Context, req ChatRequest) (<-chan StreamChunk, error) {
+	body, err := o.RequestBody(req, true)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := o.newRequest(ctx, "/v1/chat/completions", body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.Client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("openrouter: %s: %s", resp.Status, errBody)
+	}
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+		var usage Usage
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			data := strings.TrimSpace(line[5:])
+			if data == "[DONE]" {
+				break
+			}
+			var chunk struct {
+				Choices []struct {
+					Delta struct {
+						Content string `json:"content"`
+					} `json:"delta"`
+				} `json:"choices"`
+				Usage *Usage `json:"usage"`
+			}
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				continue
+			}
+			if chunk.Usage != nil {
+				usage = *chunk.Usage
+			}
+			if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+				select {
+				case out <- StreamChunk{Delta: chunk.Choices[0].Delta.Content}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			out <- StreamChunk{Err: err}
+			return
+		}
+		out <- StreamChunk{Done: true, Usage: &usage}
+	}()
+	return out, nil
+}
+
+// RefreshPricing pulls OpenRouter's model catalog and caches per-token
+// pricing so the cost tables charge federated traffic accurately.
+func (o *OpenRouter) RefreshPricing(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.baseURL()+"/v1/models", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+o.APIKey)
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("openrouter: models: %s", resp.Status)
+	}
+	var parsed struct {
+		Data []struct {
+			ID      string `json:"id"`
+			Pricing struct {
+				Prompt     string `json:"prompt"`
+				Completion string `json:"completion"`
+			} `json:"pricing"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return err
+	}
+	pricing := make(map[string]ModelPricing, len(parsed.Data))
+	for _, m := range parsed.Data {
+		prompt, _ := strconv.ParseFloat(m.Pricing.Prompt, 64)
+		completion, _ := strconv.ParseFloat(m.Pricing.Completion, 64)
+		pricing[m.ID] = ModelPricing{PromptPerToken: prompt, CompletionPerToken: completion}
+	}
+	o.pricingMu.Lock()
+	o.pricing = pricing
+	o.pricingMu.Unlock()
+	return nil
+}
+
+// Cost computes the USD cost of a completed request from the cached
+// pricing table; 0 when the model is unknown (e.g. free models).
+func (o *OpenRouter) Cost(model string, usage Usage) float64 {
+	o.pricingMu.RLock()
+	defer o.pricingMu.RUnlock()
+	p, ok := o.pricing[model]
+	if !ok {
+		return 0
+	}
+	return float64(usage.PromptTokens)*p.PromptPerToken +
+		float64(usage.CompletionTokens)*p.CompletionPerToken
+}
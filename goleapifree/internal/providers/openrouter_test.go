@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenRouterForwardsPreferencesAndParsesGenerationID(t *testing.T) {
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		if r.Header.Get("HTTP-Referer") != "https://example.com" {
+			t.Errorf("missing referer header")
+		}
+		_, _ = w.Write([]byte(`{
+			"id":"gen-abc123","model":"meta-llama/llama-3-8b",
+			"choices":[{"message":{"content":"hi"}}],
+			"usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}
+		}`))
+	}))
+	defer srv.Close()
+
+	o := &OpenRouter{
+		APIKey:      "k",
+		Preferences: json.RawMessage(`{"order":["groq","together"]}`),
+		Referer:     "https://example.com",
+		Client:      srv.Client(),
+		Endpoint:    srv.URL,
+	}
+	resp, err := o.Chat(context.Background(), ChatRequest{Model: "meta-llama/llama-3-8b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ID != "gen-abc123" {
+		t.Fatalf("generation id = %q", resp.ID)
+	}
+	prefs, ok := gotBody["provider"].(map[string]any)
+	if !ok || prefs["order"] == nil {
+		t.Fatalf("provider preferences not forwarded: %v", gotBody["provider"])
+	}
+}
+
+func TestOpenRouterPricing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[
+			{"id":"paid/model","pricing":{"prompt":"0.000001","completion":"0.000002"}},
+			{"id":"free/model","pricing":{"prompt":"0","completion":"0"}}
+		]}`))
+	}))
+	defer srv.Close()
+
+	o := &OpenRouter{APIKey: "k", Client: srv.Client(), Endpoint: srv.URL}
+	if err := o.RefreshPricing(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	usage := Usage{PromptTokens: 1000, CompletionTokens: 500}
+	if got, want := o.Cost("paid/model", usage), 0.000001*1000+0.000002*500; got != want {
+		t.Fatalf("cost = %v, want %v", got, want)
+	}
+	if got := o.Cost("free/model", usage); got != 0 {
+		t.Fatalf("free model cost = %v, want 0", got)
+	}
+	if got := o.Cost("unknown/model", usage); got != 0 {
+		t.Fatalf("unknown model cost = %v, want 0", got)
+	}
+}
+
+func BenchmarkOpenRouterRequestBody(b *testing.B) {
+	o := &OpenRouter{APIKey: "k", Preferences: json.RawMessage(`{"order":["groq"]}`)}
+	req := ChatRequest{
+		Model:       "meta-llama/llama-3-8b",
+		Messages:    []Message{{Role: "user", Content: "benchmark request"}},
+		MaxTokens:   256,
+		Temperature: 0.2,
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := o.RequestBody(req, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOpenRouterCostLookup(b *testing.B) {
+	o := &OpenRouter{pricing: map[string]ModelPricing{
+		"meta-llama/llama-3-8b": {PromptPerToken: 1e-7, CompletionPerToken: 2e-7},
+	}}
+	usage := Usage{PromptTokens: 500, CompletionTokens: 200}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if o.Cost("meta-llama/llama-3-8b", usage) == 0 {
+			b.Fatal("cached pricing missed")
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Package providers contains the upstream adapters. Every provider — from
+// plain OpenAI-compatible endpoints to cloud APIs with exotic auth — is
+// wrapped in the Adapter interface so the proxy and router stay
+// provider-agnostic.
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Message is one turn of a chat conversation in the normalized format.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatRequest is the normalized completion request handed to adapters.
+type ChatRequest struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+	Temperature float64   `json:"temperature,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+// Usage is the token accounting extracted from a provider response.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+	// ReasoningTokens is the portion of CompletionTokens a reasoning
+	// model spent on hidden chain-of-thought, when the provider reports
+	// it. Cost tracking accounts it separately from visible output.
+	ReasoningTokens int `json:"reasoning_tokens,omitempty"`
+}
+
+// ChatResponse is the normalized non-streaming completion result.
+type ChatResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Content string `json:"content"`
+	Usage   Usage  `json:"usage"`
+	// Raw preserves the provider's original body for debugging.
+	Raw json.RawMessage `json:"-"`
+}
+
+// StreamChunk is one delta of a streamed completion.
+type StreamChunk struct {
+	Delta string
+	Done  bool
+	// Usage is set on the final chunk when the provider reports it.
+	Usage *Usage
+	Err   error
+}
+
+// Adapter is the contract every upstream provider implements.
+type Adapter interface {
+	// Name is the provider type identifier ("openai", "bedrock", ...).
+	Name() string
+	// Chat performs a blocking completion.
+	Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error)
+	// ChatStream starts a streaming completion; the channel is closed
+	// after the Done (or Err) chunk.
+	ChatStream(ctx context.Context, req ChatRequest) (<-chan StreamChunk, error)
+}
+
+// registry maps provider type names to adapter factories taking the
+// provider-specific JSON config blob.
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]func(config json.RawMessage) (Adapter, error))
+)
+
+// Register installs an adapter factory for a provider type. Called from
+// adapter init functions.
+func Register(name string, factory func(config json.RawMessage) (Adapter, error)) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// New instantiates an adapter by provider type name.
+func New(name string, config json.RawMessage) (Adapter, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("providers: unknown provider type %q", name)
+	}
+	return factory(config)
+}
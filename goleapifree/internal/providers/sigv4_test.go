@@ -0,0 +1,60 @@
+package providers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSignSigV4KnownAnswer checks the signature against the worked example
+// from the AWS SigV4 documentation (ListUsers on IAM, us-east-1).
+func TestSignSigV4KnownAnswer(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	creds := sigV4Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	signSigV4(req, creds, "us-east-1", "iam", hexSHA256(nil), now)
+
+	auth := req.Header.Get("Authorization")
+	wantSig := "5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if !strings.HasSuffix(auth, "Signature="+wantSig) {
+		t.Fatalf("signature mismatch:\n%s\nwant suffix Signature=%s", auth, wantSig)
+	}
+	if !strings.Contains(auth, "SignedHeaders=content-type;host;x-amz-date") {
+		t.Fatalf("signed headers wrong: %s", auth)
+	}
+}
+
+func TestBedrockEventStreamRoundTrip(t *testing.T) {
+	payload := []byte(`{"bytes":"eyJ0eXBlIjoiY29udGVudF9ibG9ja19kZWx0YSIsImRlbHRhIjp7InRleHQiOiJoaSJ9fQ=="}`)
+	frame := encodeEventStreamFrame(payload)
+	dec := newEventStreamDecoder(strings.NewReader(string(frame)))
+	got, err := dec.next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(payload) {
+		t.Fatalf("payload = %q, want %q", got, payload)
+	}
+	delta, _ := translateBedrockEvent("anthropic.claude-3-haiku-20240307-v1:0", got)
+	if delta != "hi" {
+		t.Fatalf("delta = %q, want hi", delta)
+	}
+}
+
+func TestMapModel(t *testing.T) {
+	if got := mapModel("claude-3-haiku"); got != "anthropic.claude-3-haiku-20240307-v1:0" {
+		t.Fatalf("mapped = %q", got)
+	}
+	if got := mapModel("anthropic.claude-unknown"); got != "anthropic.claude-unknown" {
+		t.Fatalf("unknown models must pass through, got %q", got)
+	}
+}
@@ -0,0 +1,104 @@
+package providers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+// newTLSFixture starts a TLS server with a self-signed certificate and
+// returns it together with the certificate's PEM and SHA-256 fingerprint.
+func newTLSFixture(t *testing.T) (srv *httptest.Server, certPEM []byte, fingerprint string) {
+	t.Helper()
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	raw := srv.Certificate().Raw
+	sum := sha256.Sum256(raw)
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw})
+	return srv, certPEM, hex.EncodeToString(sum[:])
+}
+
+func TestNewHTTPClientDefaults(t *testing.T) {
+	client, err := NewHTTPClient(&models.Provider{Name: "plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client != http.DefaultClient {
+		t.Fatal("provider without TLS options got a custom client")
+	}
+}
+
+func TestNewHTTPClientCustomCABundle(t *testing.T) {
+	srv, certPEM, _ := newTLSFixture(t)
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(bundle, certPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewHTTPClient(&models.Provider{Name: "private", TLS: &models.TLSOptions{CABundle: bundle}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request with custom CA: %v", err)
+	}
+	resp.Body.Close()
+
+	// The same endpoint must fail against the system roots.
+	plain := &http.Client{}
+	if _, err := plain.Get(srv.URL); err == nil {
+		t.Fatal("self-signed server trusted without the bundle")
+	}
+}
+
+func TestNewHTTPClientPinning(t *testing.T) {
+	srv, _, fingerprint := newTLSFixture(t)
+
+	pinned, err := NewHTTPClient(&models.Provider{Name: "pinned", TLS: &models.TLSOptions{
+		InsecureSkipVerify: true,
+		PinnedFingerprints: []string{"sha256:" + fingerprint},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := pinned.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request with matching pin: %v", err)
+	}
+	resp.Body.Close()
+
+	wrong, err := NewHTTPClient(&models.Provider{Name: "wrong-pin", TLS: &models.TLSOptions{
+		InsecureSkipVerify: true,
+		PinnedFingerprints: []string{strings.Repeat("ab", 32)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrong.Get(srv.URL); err == nil {
+		t.Fatal("mismatched pin accepted")
+	}
+}
+
+func TestNewHTTPClientRejectsBadConfig(t *testing.T) {
+	if _, err := NewHTTPClient(&models.Provider{Name: "bad", TLS: &models.TLSOptions{
+		PinnedFingerprints: []string{"not-hex"},
+	}}); err == nil {
+		t.Fatal("invalid fingerprint accepted")
+	}
+	if _, err := NewHTTPClient(&models.Provider{Name: "bad", TLS: &models.TLSOptions{
+		CABundle: filepath.Join(t.TempDir(), "missing.pem"),
+	}}); err == nil {
+		t.Fatal("missing CA bundle accepted")
+	}
+}
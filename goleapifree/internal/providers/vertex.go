@@ -0,0 +1,332 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	Register("vertex", func(config json.RawMessage) (Adapter, error) {
+		v := &Vertex{Client: http.DefaultClient, now: time.Now}
+		if err := json.Unmarshal(config, v); err != nil {
+			return nil, fmt.Errorf("vertex: parsing config: %w", err)
+		}
+		if v.Project == "" || v.Region == "" {
+			return nil, fmt.Errorf("vertex: project and region are required")
+		}
+		if err := v.loadKey(); err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
+// Vertex adapts Google Vertex AI's generateContent APIs. Auth is a
+// service-account JWT exchanged for a short-lived OAuth access token,
+// cached until shortly before expiry.
+type Vertex struct {
+	Project string `json:"project"`
+	Region  string `json:"region"`
+	// ServiceAccount is the JSON key blob (client_email + private_key).
+	ServiceAccount struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	} `json:"service_account"`
+
+	Client *http.Client `json:"-"`
+	// Endpoint overrides the regional endpoint, for tests.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	now func() time.Time
+
+	key     *rsa.PrivateKey
+	tokenMu sync.Mutex
+	token   string
+	expiry  time.Time
+}
+
+// Name implements Adapter.
+func (v *Vertex) Name() string { return "vertex" }
+
+func (v *Vertex) baseURL() string {
+	if v.Endpoint != "" {
+		return v.Endpoint
+	}
+	return fmt.Sprintf("https://%s-aiplatform.googleapis.com", v.Region)
+}
+
+func (v *Vertex) modelURL(model, verb string) string {
+	return fmt.Sprintf("%s/v1/projects/%s/locations/%s/publishers/google/models/%s:%s",
+		v.baseURL(), v.Project, v.Region, model, verb)
+}
+
+// loadKey parses the service account's PEM private key once at startup.
+func (v *Vertex) loadKey() error {
+	block, _ := pem.Decode([]byte(v.ServiceAccount.PrivateKey))
+	if block == nil {
+		return fmt.Errorf("vertex: service account private key is not PEM")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return fmt.Errorf("vertex: service account key is not RSA")
+		}
+		v.key = rsaKey
+		return nil
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("vertex: parsing service account key: %w", err)
+	}
+	v.key = key
+	return nil
+}
+
+// accessToken returns a cached OAuth token, minting a new one via the
+// signed-JWT grant when the cache is empty or near expiry.
+func (v *Vertex) accessToken(ctx context.Context) (string, error) {
+	v.tokenMu.Lock()
+	defer v.tokenMu.Unlock()
+	if v.token != "" && v.now().Before(v.expiry.Add(-time.Minute)) {
+		return v.token, nil
+	}
+
+	tokenURI := v.ServiceAccount.TokenURI
+	if tokenURI == "" {
+		tokenURI = "https://oauth2.googleapis.com/token"
+	}
+	assertion, err := v.signJWT(tokenURI)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vertex: token exchange: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("vertex: token exchange: %s: %s", resp.Status, body)
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", err
+	}
+	v.token = tok.AccessToken
+	v.expiry = v.now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return v.token, nil
+}
+
+// signJWT builds the RS256 service-account assertion.
+func (v *Vertex) signJWT(audience string) (string, error) {
+	now := v.now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iss":   v.ServiceAccount.ClientEmail,
+		"scope": "https://www.googleapis.com/auth/cloud-platform",
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, v.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("vertex: signing JWT: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// vertexRequest converts the normalized request to Vertex's schema.
+func vertexRequest(req ChatRequest) ([]byte, error) {
+	type part struct {
+		Text string `json:"text"`
+	}
+	type content struct {
+		Role  string `json:"role"`
+		Parts []part `json:"parts"`
+	}
+	body := map[string]any{}
+	var contents []content
+	for _, m := range req.Messages {
+		switch m.Role {
+		case "system":
+			body["systemInstruction"] = content{Parts: []part{{Text: m.Content}}}
+		case "assistant":
+			contents = append(contents, content{Role: "model", Parts: []part{{Text: m.Content}}})
+		default:
+			contents = append(contents, content{Role: "user", Parts: []part{{Text: m.Content}}})
+		}
+	}
+	body["contents"] = contents
+	genCfg := map[string]any{}
+	if req.MaxTokens > 0 {
+		genCfg["maxOutputTokens"] = req.MaxTokens
+	}
+	if req.Temperature > 0 {
+		genCfg["temperature"] = req.Temperature
+	}
+	if len(genCfg) > 0 {
+		body["generationConfig"] = genCfg
+	}
+	return json.Marshal(body)
+}
+
+// vertexResponse mirrors the parts of Vertex's reply we consume,
+// including the usage metadata the cost tables need.
+type vertexResponse struct {
+	Candidates []struct {
+		Content struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"content"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+		TotalTokenCount      int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+func (r *vertexResponse) text() string {
+	var sb strings.Builder
+	for _, c := range r.Candidates {
+		for _, p := range c.Content.Parts {
+			sb.WriteString(p.Text)
+		}
+	}
+	return sb.String()
+}
+
+func (r *vertexResponse) usage() Usage {
+	return Usage{
+		PromptTokens:     r.UsageMetadata.PromptTokenCount,
+		CompletionTokens: r.UsageMetadata.CandidatesTokenCount,
+		TotalTokens:      r.UsageMetadata.TotalTokenCount,
+	}
+}
+
+// Chat implements Adapter.
+func (v *Vertex) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	raw, err := v.do(ctx, v.modelURL(req.Model, "generateContent"), req)
+	if err != nil {
+		return nil, err
+	}
+	defer raw.Close()
+	body, err := io.ReadAll(raw)
+	if err != nil {
+		return nil, err
+	}
+	var parsed vertexResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("vertex: parsing response: %w", err)
+	}
+	return &ChatResponse{
+		Model:   req.Model,
+		Content: parsed.text(),
+		Usage:   parsed.usage(),
+		Raw:     body,
+	}, nil
+}
+
+// ChatStream implements Adapter using the SSE streaming endpoint.
+func (v *Vertex) ChatStream(ctx context.Context, req ChatRequest) (<-chan StreamChunk, error) {
+	raw, err := v.do(ctx, v.modelURL(req.Model, "streamGenerateContent")+"?alt=sse", req)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+		defer raw.Close()
+		var usage Usage
+		scanner := bufio.NewScanner(raw)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			var parsed vertexResponse
+			if err := json.Unmarshal([]byte(strings.TrimSpace(line[5:])), &parsed); err != nil {
+				continue
+			}
+			if parsed.UsageMetadata.TotalTokenCount > 0 {
+				usage = parsed.usage()
+			}
+			if text := parsed.text(); text != "" {
+				select {
+				case out <- StreamChunk{Delta: text}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			out <- StreamChunk{Err: err}
+			return
+		}
+		out <- StreamChunk{Done: true, Usage: &usage}
+	}()
+	return out, nil
+}
+
+func (v *Vertex) do(ctx context.Context, url string, req ChatRequest) (io.ReadCloser, error) {
+	token, err := v.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	body, err := vertexRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	resp, err := v.Client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("vertex: %s: %s", resp.Status, errBody)
+	}
+	return resp.Body, nil
+}
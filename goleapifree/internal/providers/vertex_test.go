@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestVertex builds a Vertex adapter with a generated key and a stub
+// server handling both token exchange and generateContent.
+func newTestVertex(t *testing.T, handler http.HandlerFunc) *Vertex {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	v := &Vertex{Project: "proj", Region: "us-central1", Client: srv.Client(), Endpoint: srv.URL, now: time.Now}
+	v.ServiceAccount.ClientEmail = "svc@proj.iam.gserviceaccount.com"
+	v.ServiceAccount.PrivateKey = string(keyPEM)
+	v.ServiceAccount.TokenURI = srv.URL + "/token"
+	if err := v.loadKey(); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestVertexChatExtractsUsage(t *testing.T) {
+	var sawAuth string
+	v := newTestVertex(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "tok123", "expires_in": 3600})
+		default:
+			sawAuth = r.Header.Get("Authorization")
+			_, _ = w.Write([]byte(`{
+				"candidates":[{"content":{"parts":[{"text":"hello "},{"text":"world"}]}}],
+				"usageMetadata":{"promptTokenCount":7,"candidatesTokenCount":3,"totalTokenCount":10}
+			}`))
+		}
+	})
+
+	resp, err := v.Chat(context.Background(), ChatRequest{
+		Model:    "gemini-1.5-flash",
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Content != "hello world" {
+		t.Fatalf("content = %q", resp.Content)
+	}
+	if resp.Usage.TotalTokens != 10 || resp.Usage.PromptTokens != 7 {
+		t.Fatalf("usage = %+v", resp.Usage)
+	}
+	if sawAuth != "Bearer tok123" {
+		t.Fatalf("authorization = %q", sawAuth)
+	}
+}
+
+func TestVertexTokenCaching(t *testing.T) {
+	exchanges := 0
+	v := newTestVertex(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			exchanges++
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "tok", "expires_in": 3600})
+		default:
+			_, _ = w.Write([]byte(`{"candidates":[],"usageMetadata":{}}`))
+		}
+	})
+	for i := 0; i < 3; i++ {
+		if _, err := v.Chat(context.Background(), ChatRequest{Model: "gemini-1.5-flash"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if exchanges != 1 {
+		t.Fatalf("token exchanges = %d, want 1 (cached)", exchanges)
+	}
+}
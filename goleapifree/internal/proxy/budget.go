@@ -0,0 +1,159 @@
+package proxy
+
+// Timeout budgets replace a single static upstream timeout with
+// per-provider budgets derived from recent latency: slow-but-working
+// providers get headroom instead of being killed mid-completion, while
+// dead ones fail fast near the floor.
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout is the budget used until a provider has enough samples.
+const DefaultTimeout = 60 * time.Second
+
+// BudgetConfig tunes the per-provider timeout calculation.
+type BudgetConfig struct {
+	// Floor and Ceiling clamp the derived budget.
+	Floor   time.Duration
+	Ceiling time.Duration
+	// Multiplier is applied to the recent p99 to leave headroom for
+	// tail requests; defaults to 1.5.
+	Multiplier float64
+	// MinSamples is how many observations a provider needs before its
+	// budget becomes dynamic; defaults to 20.
+	MinSamples int
+	// WindowSize is how many recent samples feed the p99; defaults
+	// to 256.
+	WindowSize int
+}
+
+// DefaultBudgetConfig is the production configuration.
+var DefaultBudgetConfig = BudgetConfig{
+	Floor:      5 * time.Second,
+	Ceiling:    120 * time.Second,
+	Multiplier: 1.5,
+	MinSamples: 20,
+	WindowSize: 256,
+}
+
+func (c BudgetConfig) withDefaults() BudgetConfig {
+	if c.Floor == 0 {
+		c.Floor = DefaultBudgetConfig.Floor
+	}
+	if c.Ceiling == 0 {
+		c.Ceiling = DefaultBudgetConfig.Ceiling
+	}
+	if c.Multiplier == 0 {
+		c.Multiplier = DefaultBudgetConfig.Multiplier
+	}
+	if c.MinSamples == 0 {
+		c.MinSamples = DefaultBudgetConfig.MinSamples
+	}
+	if c.WindowSize == 0 {
+		c.WindowSize = DefaultBudgetConfig.WindowSize
+	}
+	return c
+}
+
+// latencyWindow is a fixed-size ring of recent request latencies.
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+	full    bool
+}
+
+func (w *latencyWindow) add(d time.Duration) {
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % len(w.samples)
+	if w.next == 0 {
+		w.full = true
+	}
+}
+
+func (w *latencyWindow) count() int {
+	if w.full {
+		return len(w.samples)
+	}
+	return w.next
+}
+
+// p99 returns the 99th percentile of the window's contents.
+func (w *latencyWindow) p99() time.Duration {
+	n := w.count()
+	sorted := make([]time.Duration, n)
+	copy(sorted, w.samples[:n])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := (n*99 + 99) / 100 // ceil(n*0.99)
+	if idx > n {
+		idx = n
+	}
+	return sorted[idx-1]
+}
+
+// TimeoutBudgets tracks per-provider latency and derives the upstream
+// timeout each request should run under.
+type TimeoutBudgets struct {
+	cfg BudgetConfig
+
+	mu  sync.Mutex
+	per map[string]*latencyWindow
+}
+
+// NewTimeoutBudgets creates a tracker; zero fields of cfg fall back to
+// DefaultBudgetConfig.
+func NewTimeoutBudgets(cfg BudgetConfig) *TimeoutBudgets {
+	return &TimeoutBudgets{cfg: cfg.withDefaults(), per: make(map[string]*latencyWindow)}
+}
+
+// Observe records one completed request's latency for a provider.
+// Timed-out requests should be recorded at the budget they ran under so
+// a struggling provider's budget does not silently shrink.
+func (b *TimeoutBudgets) Observe(providerID string, latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, ok := b.per[providerID]
+	if !ok {
+		w = &latencyWindow{samples: make([]time.Duration, b.cfg.WindowSize)}
+		b.per[providerID] = w
+	}
+	w.add(latency)
+}
+
+// Budget returns the current timeout budget for a provider: recent p99
+// times the multiplier, clamped to [Floor, Ceiling]. Providers without
+// enough history run under DefaultTimeout.
+func (b *TimeoutBudgets) Budget(providerID string) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, ok := b.per[providerID]
+	if !ok || w.count() < b.cfg.MinSamples {
+		return DefaultTimeout
+	}
+	budget := time.Duration(float64(w.p99()) * b.cfg.Multiplier)
+	if budget < b.cfg.Floor {
+		return b.cfg.Floor
+	}
+	if budget > b.cfg.Ceiling {
+		return b.cfg.Ceiling
+	}
+	return budget
+}
+
+// Budgets returns the current budget for every tracked provider, for
+// the admin surface.
+func (b *TimeoutBudgets) Budgets() map[string]time.Duration {
+	b.mu.Lock()
+	providers := make([]string, 0, len(b.per))
+	for id := range b.per {
+		providers = append(providers, id)
+	}
+	b.mu.Unlock()
+	out := make(map[string]time.Duration, len(providers))
+	for _, id := range providers {
+		out[id] = b.Budget(id)
+	}
+	return out
+}
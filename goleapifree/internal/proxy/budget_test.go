@@ -0,0 +1,71 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBudgetFallsBackWithoutHistory(t *testing.T) {
+	b := NewTimeoutBudgets(BudgetConfig{})
+	if got := b.Budget("p1"); got != DefaultTimeout {
+		t.Fatalf("budget = %v, want DefaultTimeout", got)
+	}
+	// Below MinSamples the static default still applies.
+	for i := 0; i < 10; i++ {
+		b.Observe("p1", time.Second)
+	}
+	if got := b.Budget("p1"); got != DefaultTimeout {
+		t.Fatalf("budget = %v, want DefaultTimeout", got)
+	}
+}
+
+func TestBudgetTracksP99WithHeadroom(t *testing.T) {
+	b := NewTimeoutBudgets(BudgetConfig{MinSamples: 20})
+	for i := 0; i < 96; i++ {
+		b.Observe("p1", 2*time.Second)
+	}
+	for i := 0; i < 4; i++ {
+		b.Observe("p1", 10*time.Second) // tail outliers past the p99 cut
+	}
+
+	got := b.Budget("p1")
+	want := time.Duration(float64(10*time.Second) * 1.5)
+	if got != want {
+		t.Fatalf("budget = %v, want %v (p99 x multiplier)", got, want)
+	}
+}
+
+func TestBudgetClamping(t *testing.T) {
+	b := NewTimeoutBudgets(BudgetConfig{MinSamples: 5})
+	// A fast provider still gets at least the floor.
+	for i := 0; i < 20; i++ {
+		b.Observe("fast", 100*time.Millisecond)
+	}
+	if got := b.Budget("fast"); got != DefaultBudgetConfig.Floor {
+		t.Fatalf("budget = %v, want floor %v", got, DefaultBudgetConfig.Floor)
+	}
+	// A pathologically slow one is capped at the ceiling.
+	for i := 0; i < 20; i++ {
+		b.Observe("slow", 10*time.Minute)
+	}
+	if got := b.Budget("slow"); got != DefaultBudgetConfig.Ceiling {
+		t.Fatalf("budget = %v, want ceiling %v", got, DefaultBudgetConfig.Ceiling)
+	}
+}
+
+func TestBudgetWindowEvictsOldSamples(t *testing.T) {
+	b := NewTimeoutBudgets(BudgetConfig{MinSamples: 5, WindowSize: 50})
+	for i := 0; i < 50; i++ {
+		b.Observe("p1", time.Minute) // old, slow era
+	}
+	for i := 0; i < 50; i++ {
+		b.Observe("p1", 4*time.Second) // provider recovered
+	}
+	want := time.Duration(float64(4*time.Second) * 1.5)
+	if got := b.Budget("p1"); got != want {
+		t.Fatalf("budget = %v, want %v after recovery", got, want)
+	}
+	if m := b.Budgets(); m["p1"] != want {
+		t.Fatalf("budgets = %v", m)
+	}
+}
@@ -0,0 +1,118 @@
+package proxy
+
+import (
+	"context"
+	"strings"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// Partial response salvage: when a stream dies midway, the watchdog's
+// fallback attempt should resume the completion rather than restart it.
+// ContinuationRequest packages the partial output for the fallback
+// provider, and StitchContinuation trims the overlap models tend to
+// repeat so the client sees one seamless completion.
+
+// continuationInstruction tells the fallback model to resume rather
+// than answer afresh. It rides in a system message so user content
+// stays untouched.
+const continuationInstruction = "The assistant message above is an incomplete response that was cut off " +
+	"mid-stream. Continue it exactly from where it stops. Do not repeat any of " +
+	"the existing text, do not apologize, and do not add a preamble."
+
+// ContinuationRequest builds the fallback request for a completion that
+// died after emitting partial text: the original conversation, the
+// partial output as an assistant turn, and the continuation instruction.
+// A request with no partial text is returned unchanged — there is
+// nothing to salvage, so a plain retry is correct.
+func ContinuationRequest(req providers.ChatRequest, partial string) providers.ChatRequest {
+	if partial == "" {
+		return req
+	}
+	messages := make([]providers.Message, 0, len(req.Messages)+2)
+	messages = append(messages, req.Messages...)
+	messages = append(messages,
+		providers.Message{Role: "assistant", Content: partial},
+		providers.Message{Role: "system", Content: continuationInstruction},
+	)
+	req.Messages = messages
+	return req
+}
+
+// StitchContinuation trims the start of a continuation delta stream so
+// it lines up with the text the client already has. Models asked to
+// continue often re-emit the tail of the partial output; the stitcher
+// drops the longest suffix of emitted that the continuation re-starts
+// with, checking word boundaries down to a single character.
+func StitchContinuation(emitted, continuation string) string {
+	max := len(emitted)
+	if len(continuation) < max {
+		max = len(continuation)
+	}
+	for n := max; n > 0; n-- {
+		if strings.HasSuffix(emitted, continuation[:n]) {
+			return continuation[n:]
+		}
+	}
+	return continuation
+}
+
+// stitchingSource wraps a chunk stream, applying StitchContinuation to
+// the first deltas until the overlap with emitted is consumed.
+func stitchingSource(ctx context.Context, emitted string, chunks <-chan providers.StreamChunk) <-chan providers.StreamChunk {
+	out := make(chan providers.StreamChunk)
+	send := func(chunk providers.StreamChunk) bool {
+		select {
+		case out <- chunk:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	go func() {
+		defer close(out)
+		// Buffer deltas until we have at least as much text as could
+		// possibly overlap, then trim once and pass the rest through.
+		var head strings.Builder
+		trimming := true
+		for chunk := range chunks {
+			if trimming && chunk.Err == nil && !chunk.Done {
+				head.WriteString(chunk.Delta)
+				if head.Len() < len(emitted) {
+					continue
+				}
+				trimming = false
+				chunk.Delta = StitchContinuation(emitted, head.String())
+				if chunk.Delta == "" {
+					continue
+				}
+			} else if trimming {
+				// Stream ended before outgrowing the overlap window.
+				trimming = false
+				if rest := StitchContinuation(emitted, head.String()); rest != "" {
+					if !send(providers.StreamChunk{Delta: rest}) {
+						return
+					}
+				}
+			}
+			if !send(chunk) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ContinuationSource adapts a StreamSource so that failover attempts
+// resume from the already-emitted text: the source still opens the
+// stream, but its deltas are stitched against what the client has seen.
+// Attempt 0 passes through untouched.
+func ContinuationSource(source StreamSource) StreamSource {
+	return func(ctx context.Context, attempt int, emitted string) (string, <-chan providers.StreamChunk, error) {
+		providerID, chunks, err := source(ctx, attempt, emitted)
+		if err != nil || attempt == 0 || emitted == "" {
+			return providerID, chunks, err
+		}
+		return providerID, stitchingSource(ctx, emitted, chunks), nil
+	}
+}
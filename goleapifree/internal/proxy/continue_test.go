@@ -0,0 +1,76 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+func TestContinuationRequest(t *testing.T) {
+	req := providers.ChatRequest{
+		Model:    "gpt-4o",
+		Messages: []providers.Message{{Role: "user", Content: "tell me a story"}},
+	}
+	cont := ContinuationRequest(req, "Once upon a time")
+	if len(cont.Messages) != 3 {
+		t.Fatalf("messages = %d", len(cont.Messages))
+	}
+	if cont.Messages[1].Role != "assistant" || cont.Messages[1].Content != "Once upon a time" {
+		t.Fatalf("partial turn = %+v", cont.Messages[1])
+	}
+	if cont.Messages[2].Role != "system" || !strings.Contains(cont.Messages[2].Content, "Continue it exactly") {
+		t.Fatalf("instruction turn = %+v", cont.Messages[2])
+	}
+	if len(req.Messages) != 1 {
+		t.Fatal("original request mutated")
+	}
+	if same := ContinuationRequest(req, ""); len(same.Messages) != 1 {
+		t.Fatal("empty partial should pass through")
+	}
+}
+
+func TestStitchContinuation(t *testing.T) {
+	for _, tc := range []struct{ emitted, cont, want string }{
+		{"The answer is ", "is 42.", "42."},
+		{"The answer is ", "The answer is 42.", "42."},
+		{"The answer is ", "42.", "42."},
+		{"", "anything", "anything"},
+		{"abc", "abc", ""},
+	} {
+		if got := StitchContinuation(tc.emitted, tc.cont); got != tc.want {
+			t.Errorf("Stitch(%q, %q) = %q, want %q", tc.emitted, tc.cont, got, tc.want)
+		}
+	}
+}
+
+func TestContinuationSourceStitchesStream(t *testing.T) {
+	// First provider stalls after the opening words; the fallback
+	// re-emits them and the client must not see the repeat.
+	var buf bytes.Buffer
+	wd := &Watchdog{
+		Limits: StreamLimits{UpstreamIdleTimeout: 20 * time.Millisecond},
+		Source: ContinuationSource(func(_ context.Context, attempt int, emitted string) (string, <-chan providers.StreamChunk, error) {
+			if attempt == 0 {
+				return "stalling", stallChan("Once upon "), nil
+			}
+			if emitted != "Once upon " {
+				t.Errorf("fallback emitted = %q", emitted)
+			}
+			return "healthy", chunkChan("Once ", "upon a time.", " The end."), nil
+		}),
+	}
+	if err := wd.Pump(context.Background(), &buf); err != nil {
+		t.Fatalf("Pump: %v", err)
+	}
+	out := buf.String()
+	if strings.Count(out, "Once upon ") != 1 {
+		t.Fatalf("repeated text reached the client: %q", out)
+	}
+	if !strings.Contains(out, "a time.") || !strings.Contains(out, " The end.") {
+		t.Fatalf("continuation missing: %q", out)
+	}
+}
@@ -0,0 +1,81 @@
+package proxy
+
+import "encoding/json"
+
+// Streaming event verbosity, selected per request through the
+// `extensions` object: {"extensions": {"events": "minimal"}}.
+// Constrained mobile clients ask for minimal streams, dashboards for
+// rich ones; everyone else gets the standard OpenAI-style stream.
+const (
+	// EventsStandard is the default OpenAI-compatible stream.
+	EventsStandard = ""
+	// EventsMinimal sends content deltas only: no keepalive comments,
+	// nothing a byte-counting mobile client has to pay for.
+	EventsMinimal = "minimal"
+	// EventsRich adds per-chunk token estimates and names the serving
+	// provider in heartbeat frames.
+	EventsRich = "rich"
+)
+
+// StreamProfile is the resolved event verbosity for one connection.
+type StreamProfile struct {
+	// ContentOnly suppresses every non-content frame.
+	ContentOnly bool
+	// TokenCounts attaches a token estimate to each delta frame.
+	TokenCounts bool
+	// Heartbeat upgrades keepalive comments to heartbeat frames naming
+	// the provider.
+	Heartbeat bool
+	// Provider is the display name heartbeat frames carry.
+	Provider string
+}
+
+// ProfileFor maps an events selector to its profile; unknown selectors
+// fall back to the standard stream rather than failing the request.
+func ProfileFor(events string) StreamProfile {
+	switch events {
+	case EventsMinimal:
+		return StreamProfile{ContentOnly: true}
+	case EventsRich:
+		return StreamProfile{TokenCounts: true, Heartbeat: true}
+	default:
+		return StreamProfile{}
+	}
+}
+
+// PeekStreamEvents extracts the extensions.events selector from a raw
+// request body without unmarshaling the rest of it.
+func PeekStreamEvents(body []byte) string {
+	var ext struct {
+		Events string `json:"events"`
+	}
+	peekExtensions(body, &ext)
+	return ext.Events
+}
+
+// PeekStreamPace extracts the extensions.pace_tokens_per_sec request,
+// the client-side knob for smooth output pacing; 0 means unpaced.
+func PeekStreamPace(body []byte) float64 {
+	var ext struct {
+		Pace float64 `json:"pace_tokens_per_sec"`
+	}
+	peekExtensions(body, &ext)
+	return ext.Pace
+}
+
+// peekExtensions unmarshals the top-level extensions object into dst,
+// leaving dst zeroed when the object is absent or malformed.
+func peekExtensions(body []byte, dst any) {
+	start, end, err := fieldValueSpan(body, "extensions")
+	if err != nil || start < 0 {
+		return
+	}
+	_ = json.Unmarshal(body[start:end], dst)
+}
+
+// estimateTokens is the usual chars/4 approximation; rich streams are
+// explicit that per-chunk counts are estimates, with exact usage still
+// arriving on the final chunk.
+func estimateTokens(delta string) int {
+	return (len(delta) + 3) / 4
+}
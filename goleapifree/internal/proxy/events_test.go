@@ -0,0 +1,78 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+func TestPeekStreamEvents(t *testing.T) {
+	for _, tc := range []struct{ body, want string }{
+		{`{"model":"m","extensions":{"events":"minimal"}}`, "minimal"},
+		{`{"model":"m","extensions":{"events":"rich"}}`, "rich"},
+		{`{"model":"m"}`, ""},
+		{`{"messages":[{"content":"{\"extensions\":1}"}]}`, ""},
+		{`not json`, ""},
+	} {
+		if got := PeekStreamEvents([]byte(tc.body)); got != tc.want {
+			t.Errorf("PeekStreamEvents(%s) = %q, want %q", tc.body, got, tc.want)
+		}
+	}
+}
+
+func TestMinimalProfileSuppressesKeepalives(t *testing.T) {
+	src := make(chan providers.StreamChunk)
+	var buf bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- PumpSSE(context.Background(), src, &buf, StreamLimits{
+			KeepaliveInterval: 10 * time.Millisecond,
+			Profile:           ProfileFor(EventsMinimal),
+		})
+	}()
+	time.Sleep(50 * time.Millisecond)
+	src <- providers.StreamChunk{Delta: "hi"}
+	src <- providers.StreamChunk{Done: true}
+	close(src)
+	if err := <-done; err != nil {
+		t.Fatalf("PumpSSE: %v", err)
+	}
+	if strings.Contains(buf.String(), ": keepalive") {
+		t.Fatalf("minimal stream carried comment frames: %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "hi") {
+		t.Fatalf("content missing: %q", buf.String())
+	}
+}
+
+func TestRichProfileAddsTokensAndHeartbeat(t *testing.T) {
+	src := make(chan providers.StreamChunk)
+	var buf bytes.Buffer
+	done := make(chan error, 1)
+	profile := ProfileFor(EventsRich)
+	profile.Provider = "openai"
+	go func() {
+		done <- PumpSSE(context.Background(), src, &buf, StreamLimits{
+			KeepaliveInterval: 10 * time.Millisecond,
+			Profile:           profile,
+		})
+	}()
+	time.Sleep(50 * time.Millisecond)
+	src <- providers.StreamChunk{Delta: "twelve chars"}
+	src <- providers.StreamChunk{Done: true}
+	close(src)
+	if err := <-done; err != nil {
+		t.Fatalf("PumpSSE: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, ": heartbeat provider=openai") {
+		t.Fatalf("no heartbeat frame: %q", out)
+	}
+	if !strings.Contains(out, `"chunk_tokens":3`) {
+		t.Fatalf("no token estimate: %q", out)
+	}
+}
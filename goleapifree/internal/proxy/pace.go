@@ -0,0 +1,49 @@
+package proxy
+
+import "time"
+
+// maxPaceTokensPerSec bounds client-requested pacing; anything above
+// it is effectively "no pacing" and treated as such.
+const maxPaceTokensPerSec = 1000
+
+// pacer smooths delta writes to a target tokens/sec. It tracks the
+// cumulative token budget against elapsed time and sleeps off any
+// surplus before a write, so output is paced without buffering
+// anything beyond the pump's existing chunk buffer — the sleep itself
+// is the backpressure that slows the upstream read loop.
+type pacer struct {
+	rate  float64 // tokens per second
+	sent  float64
+	start time.Time
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// newPacer creates a pacer for the given rate; rates <= 0 or above the
+// cap return nil, which callers treat as pacing disabled.
+func newPacer(tokensPerSec float64) *pacer {
+	if tokensPerSec <= 0 || tokensPerSec > maxPaceTokensPerSec {
+		return nil
+	}
+	return &pacer{rate: tokensPerSec, now: time.Now, sleep: time.Sleep}
+}
+
+// wait blocks until the budget allows sending the next tokens. The
+// first write passes immediately so time-to-first-token is unaffected.
+func (p *pacer) wait(tokens int) {
+	if p == nil {
+		return
+	}
+	now := p.now()
+	if p.start.IsZero() {
+		p.start = now
+		p.sent += float64(tokens)
+		return
+	}
+	due := p.start.Add(time.Duration(p.sent / p.rate * float64(time.Second)))
+	if wait := due.Sub(now); wait > 0 {
+		p.sleep(wait)
+	}
+	p.sent += float64(tokens)
+}
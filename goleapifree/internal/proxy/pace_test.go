@@ -0,0 +1,73 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+func TestPacerSchedule(t *testing.T) {
+	clock := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	var slept time.Duration
+	p := newPacer(10) // 10 tokens/sec
+	p.now = func() time.Time { return clock }
+	p.sleep = func(d time.Duration) { slept += d; clock = clock.Add(d) }
+
+	p.wait(5) // first write is immediate
+	if slept != 0 {
+		t.Fatalf("first write slept %v", slept)
+	}
+	p.wait(5) // 5 tokens sent, none of the budget elapsed: wait 500ms
+	if slept != 500*time.Millisecond {
+		t.Fatalf("slept %v, want 500ms", slept)
+	}
+	clock = clock.Add(2 * time.Second) // client stalled; budget piles up
+	p.wait(5)
+	if slept != 500*time.Millisecond {
+		t.Fatalf("pacer slept %v during surplus", slept)
+	}
+}
+
+func TestPacerDisabled(t *testing.T) {
+	if newPacer(0) != nil || newPacer(-1) != nil || newPacer(maxPaceTokensPerSec+1) != nil {
+		t.Fatal("out-of-range rates must disable pacing")
+	}
+	var p *pacer
+	p.wait(10) // nil-safe
+}
+
+func TestPeekStreamPace(t *testing.T) {
+	if got := PeekStreamPace([]byte(`{"extensions":{"pace_tokens_per_sec":12.5}}`)); got != 12.5 {
+		t.Fatalf("pace = %v", got)
+	}
+	if got := PeekStreamPace([]byte(`{"model":"m"}`)); got != 0 {
+		t.Fatalf("pace = %v for absent extensions", got)
+	}
+}
+
+func TestPumpSSEPacesOutput(t *testing.T) {
+	src := make(chan providers.StreamChunk, 4)
+	// Three deltas of ~4 tokens each at 40 tokens/sec: roughly 200ms
+	// after the immediate first write.
+	for i := 0; i < 3; i++ {
+		src <- providers.StreamChunk{Delta: "sixteen  chars  "}
+	}
+	src <- providers.StreamChunk{Done: true}
+	close(src)
+
+	var buf bytes.Buffer
+	start := time.Now()
+	if err := PumpSSE(context.Background(), src, &buf, StreamLimits{PaceTokensPerSec: 40}); err != nil {
+		t.Fatalf("PumpSSE: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("stream finished in %v, pacing not applied", elapsed)
+	}
+	if got := strings.Count(buf.String(), "sixteen  chars"); got != 3 {
+		t.Fatalf("deltas = %d", got)
+	}
+}
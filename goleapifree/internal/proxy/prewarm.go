@@ -0,0 +1,145 @@
+package proxy
+
+// Connection pre-warming keeps TLS/HTTP2 connections to the busiest
+// providers established through idle periods, so the first request after
+// a lull does not pay the full handshake. Which providers count as
+// "busiest" comes from the analyzer's history for the current hour of
+// day, not a static list.
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/stats"
+)
+
+// PrewarmConfig tunes the pre-warmer.
+type PrewarmConfig struct {
+	// TopN is how many providers stay warm at once; defaults to 3.
+	TopN int
+	// Interval is how often connections are refreshed; defaults to 30s,
+	// which is inside common idle-connection timeouts.
+	Interval time.Duration
+}
+
+// WarmStatus is the last outcome for one provider.
+type WarmStatus struct {
+	LastWarmed time.Time `json:"last_warmed"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// Prewarmer ranks providers by predicted traffic and keeps connections
+// to the top ones alive.
+type Prewarmer struct {
+	Analyzer *stats.Analyzer
+	// Ping establishes (or reuses) a connection to a provider. The live
+	// implementation issues a lightweight request over the shared
+	// keep-alive transport; tests inject fakes.
+	Ping func(ctx context.Context, providerID string) error
+
+	cfg PrewarmConfig
+	now func() time.Time
+
+	mu     sync.Mutex
+	status map[string]WarmStatus
+}
+
+// NewPrewarmer creates a pre-warmer over the analyzer's history.
+func NewPrewarmer(a *stats.Analyzer, cfg PrewarmConfig) *Prewarmer {
+	if cfg.TopN <= 0 {
+		cfg.TopN = 3
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	return &Prewarmer{
+		Analyzer: a,
+		cfg:      cfg,
+		now:      time.Now,
+		status:   make(map[string]WarmStatus),
+	}
+}
+
+// Rank orders providers by their historical request volume in the
+// current hour of day, busiest first. Providers with no history for the
+// hour rank by total volume so cold caches still warm something useful.
+func (p *Prewarmer) Rank() []string {
+	hour := p.now().Hour()
+	type scored struct {
+		id              string
+		hourly, overall int64
+	}
+	var all []scored
+	for _, id := range p.Analyzer.Providers() {
+		s := scored{id: id}
+		for _, b := range p.Analyzer.ProviderBuckets(id) {
+			s.overall += b.Requests
+			if b.Start.Hour() == hour {
+				s.hourly += b.Requests
+			}
+		}
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].hourly != all[j].hourly {
+			return all[i].hourly > all[j].hourly
+		}
+		if all[i].overall != all[j].overall {
+			return all[i].overall > all[j].overall
+		}
+		return all[i].id < all[j].id
+	})
+	out := make([]string, 0, len(all))
+	for _, s := range all {
+		out = append(out, s.id)
+	}
+	return out
+}
+
+// Warm pings the current top-N providers once and returns which ones it
+// touched. Failures are recorded in the status map but do not abort the
+// pass: one unreachable provider must not leave the rest cold.
+func (p *Prewarmer) Warm(ctx context.Context) []string {
+	ranked := p.Rank()
+	if len(ranked) > p.cfg.TopN {
+		ranked = ranked[:p.cfg.TopN]
+	}
+	for _, id := range ranked {
+		st := WarmStatus{LastWarmed: p.now()}
+		if err := p.Ping(ctx, id); err != nil {
+			st.LastError = err.Error()
+		}
+		p.mu.Lock()
+		p.status[id] = st
+		p.mu.Unlock()
+	}
+	return ranked
+}
+
+// Run re-warms on the configured interval until ctx is cancelled.
+func (p *Prewarmer) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+	p.Warm(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Warm(ctx)
+		}
+	}
+}
+
+// Status reports the last warm outcome per provider.
+func (p *Prewarmer) Status() map[string]WarmStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make(map[string]WarmStatus, len(p.status))
+	for id, st := range p.status {
+		out[id] = st
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/stats"
+)
+
+func TestRankPrefersCurrentHourTraffic(t *testing.T) {
+	a := stats.NewAnalyzer(24 * time.Hour)
+	now := time.Now().Truncate(time.Hour)
+	// "busy-now" dominates the current hour; "busy-overall" has more
+	// total traffic but in a different hour.
+	for i := 0; i < 10; i++ {
+		a.Record(stats.Sample{Provider: "busy-now", At: now})
+	}
+	for i := 0; i < 50; i++ {
+		a.Record(stats.Sample{Provider: "busy-overall", At: now.Add(-6 * time.Hour)})
+	}
+	a.Record(stats.Sample{Provider: "quiet", At: now})
+
+	p := NewPrewarmer(a, PrewarmConfig{})
+	p.now = func() time.Time { return now }
+
+	ranked := p.Rank()
+	if len(ranked) != 3 || ranked[0] != "busy-now" {
+		t.Fatalf("ranked = %v", ranked)
+	}
+}
+
+func TestWarmPingsTopNAndRecordsFailures(t *testing.T) {
+	a := stats.NewAnalyzer(24 * time.Hour)
+	now := time.Now().Truncate(time.Hour)
+	for i, id := range []string{"p1", "p2", "p3", "p4"} {
+		for j := 0; j <= 40-10*i; j++ { // p1 busiest, p4 quietest
+			a.Record(stats.Sample{Provider: id, At: now})
+		}
+	}
+
+	var mu sync.Mutex
+	pinged := map[string]int{}
+	p := NewPrewarmer(a, PrewarmConfig{TopN: 2})
+	p.now = func() time.Time { return now }
+	p.Ping = func(_ context.Context, id string) error {
+		mu.Lock()
+		pinged[id]++
+		mu.Unlock()
+		if id == "p2" {
+			return errors.New("dial timeout")
+		}
+		return nil
+	}
+
+	warmed := p.Warm(context.Background())
+	if len(warmed) != 2 || warmed[0] != "p1" || warmed[1] != "p2" {
+		t.Fatalf("warmed = %v", warmed)
+	}
+	if pinged["p3"] != 0 || pinged["p4"] != 0 {
+		t.Fatalf("pinged beyond top-N: %v", pinged)
+	}
+
+	st := p.Status()
+	if st["p1"].LastError != "" || st["p2"].LastError != "dial timeout" {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+func TestRunRewarmsOnInterval(t *testing.T) {
+	a := stats.NewAnalyzer(24 * time.Hour)
+	a.Record(stats.Sample{Provider: "p1", At: time.Now()})
+
+	var mu sync.Mutex
+	count := 0
+	p := NewPrewarmer(a, PrewarmConfig{TopN: 1, Interval: 5 * time.Millisecond})
+	p.Ping = func(context.Context, string) error {
+		mu.Lock()
+		count++
+		mu.Unlock()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 40*time.Millisecond)
+	defer cancel()
+	p.Run(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if count < 2 {
+		t.Fatalf("pings = %d, want repeated warming", count)
+	}
+}
@@ -0,0 +1,190 @@
+package proxy
+
+// Pass-through body rewriting for the chat hot path. Unmarshaling the
+// whole chat request just to swap the model name re-marshals every
+// untouched field and dominates GC at high request rates; the helpers
+// here scan the raw JSON once, splice in the targeted rewrite, and leave
+// every other byte exactly as the client sent it.
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrNotObject means the request body is not a JSON object.
+var ErrNotObject = errors.New("proxy: request body is not a JSON object")
+
+// PeekModel returns the top-level "model" string from a raw request
+// body without unmarshaling it.
+func PeekModel(body []byte) (string, bool) {
+	start, end, err := fieldValueSpan(body, "model")
+	if err != nil || start < 0 || body[start] != '"' {
+		return "", false
+	}
+	return unquote(body[start:end])
+}
+
+// PeekStream returns the top-level "stream" flag without unmarshaling
+// the body; absent or malformed means false.
+func PeekStream(body []byte) bool {
+	start, end, err := fieldValueSpan(body, "stream")
+	if err != nil || start < 0 {
+		return false
+	}
+	return string(body[start:end]) == "true"
+}
+
+// RewriteModel returns the body with only the top-level "model" value
+// replaced; every other byte passes through untouched. A body without a
+// model field gets one inserted. The result is appended into a single
+// exact-size allocation — no intermediate decode or re-marshal.
+func RewriteModel(body []byte, model string) ([]byte, error) {
+	return AppendRewriteModel(make([]byte, 0, len(body)+len(model)+16), body, model)
+}
+
+// AppendRewriteModel is RewriteModel appending into dst, so callers with
+// a pooled buffer avoid the allocation entirely.
+func AppendRewriteModel(dst, body []byte, model string) ([]byte, error) {
+	start, end, err := fieldValueSpan(body, "model")
+	if err != nil {
+		return nil, err
+	}
+	if start < 0 {
+		// No model field: insert one right after the opening brace.
+		i := skipSpace(body, 0)
+		dst = append(dst, body[:i+1]...)
+		dst = append(dst, `"model":`...)
+		dst = strconv.AppendQuote(dst, model)
+		if j := skipSpace(body, i+1); j >= len(body) || body[j] != '}' {
+			dst = append(dst, ',')
+		}
+		return append(dst, body[i+1:]...), nil
+	}
+	dst = append(dst, body[:start]...)
+	dst = strconv.AppendQuote(dst, model)
+	return append(dst, body[end:]...), nil
+}
+
+// fieldValueSpan locates the value of a top-level object member,
+// returning its [start, end) byte span, or start -1 when the field is
+// absent. Nested objects are skipped wholesale, so a "model" key inside
+// a message never matches.
+func fieldValueSpan(body []byte, name string) (start, end int, err error) {
+	i := skipSpace(body, 0)
+	if i >= len(body) || body[i] != '{' {
+		return -1, -1, ErrNotObject
+	}
+	i++
+	for {
+		i = skipSpace(body, i)
+		if i >= len(body) {
+			return -1, -1, errors.New("proxy: unterminated object")
+		}
+		if body[i] == '}' {
+			return -1, -1, nil
+		}
+		if body[i] == ',' {
+			i++
+			continue
+		}
+		keyStart := i
+		keyEnd, err := skipString(body, i)
+		if err != nil {
+			return -1, -1, err
+		}
+		i = skipSpace(body, keyEnd)
+		if i >= len(body) || body[i] != ':' {
+			return -1, -1, errors.New("proxy: malformed object member")
+		}
+		i = skipSpace(body, i+1)
+		valEnd, err := skipValue(body, i)
+		if err != nil {
+			return -1, -1, err
+		}
+		// Keys needing escape sequences can never equal a plain field
+		// name, so raw byte comparison is enough.
+		if string(body[keyStart+1:keyEnd-1]) == name {
+			return i, valEnd, nil
+		}
+		i = valEnd
+	}
+}
+
+// skipValue returns the index just past the JSON value starting at i.
+func skipValue(body []byte, i int) (int, error) {
+	if i >= len(body) {
+		return 0, errors.New("proxy: truncated value")
+	}
+	switch body[i] {
+	case '"':
+		return skipString(body, i)
+	case '{', '[':
+		depth := 0
+		for ; i < len(body); i++ {
+			switch body[i] {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1, nil
+				}
+			case '"':
+				end, err := skipString(body, i)
+				if err != nil {
+					return 0, err
+				}
+				i = end - 1
+			}
+		}
+		return 0, errors.New("proxy: unterminated value")
+	default:
+		for ; i < len(body); i++ {
+			switch body[i] {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				return i, nil
+			}
+		}
+		return i, nil
+	}
+}
+
+// skipString returns the index just past the string starting at i.
+func skipString(body []byte, i int) (int, error) {
+	if i >= len(body) || body[i] != '"' {
+		return 0, errors.New("proxy: expected string")
+	}
+	for i++; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return 0, errors.New("proxy: unterminated string")
+}
+
+func skipSpace(body []byte, i int) int {
+	for i < len(body) {
+		switch body[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// unquote decodes a raw JSON string token. The fast path hands back a
+// plain substring; only strings with escapes pay for strconv.
+func unquote(raw []byte) (string, bool) {
+	for _, c := range raw[1 : len(raw)-1] {
+		if c == '\\' {
+			s, err := strconv.Unquote(string(raw))
+			return s, err == nil
+		}
+	}
+	return string(raw[1 : len(raw)-1]), true
+}
@@ -0,0 +1,100 @@
+package proxy
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRewriteModelLeavesOtherBytesUntouched(t *testing.T) {
+	body := []byte(`{"model":"gpt-4","messages":[{"role":"user","content":"hi"}],"temperature":0.7,"vendor_extra":{"model":"inner"}}`)
+	got, err := RewriteModel(body, "llama-3-70b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"model":"llama-3-70b","messages":[{"role":"user","content":"hi"}],"temperature":0.7,"vendor_extra":{"model":"inner"}}`
+	if string(got) != want {
+		t.Fatalf("rewritten = %s", got)
+	}
+}
+
+func TestRewriteModelHandlesEscapesAndWhitespace(t *testing.T) {
+	body := []byte("{\n  \"messages\": [{\"content\": \"say \\\"model\\\": done\"}],\n  \"model\" : \"a\"\n}")
+	got, err := RewriteModel(body, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(got, &parsed); err != nil || parsed.Model != "b" {
+		t.Fatalf("model = %q, %v in %s", parsed.Model, err, got)
+	}
+	if !strings.Contains(string(got), `say \"model\": done`) {
+		t.Fatalf("message content altered: %s", got)
+	}
+}
+
+func TestRewriteModelInsertsWhenAbsent(t *testing.T) {
+	got, err := RewriteModel([]byte(`{"messages":[]}`), "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `{"model":"m","messages":[]}` {
+		t.Fatalf("rewritten = %s", got)
+	}
+	got, err = RewriteModel([]byte(`{}`), "m")
+	if err != nil || string(got) != `{"model":"m"}` {
+		t.Fatalf("rewritten = %s, %v", got, err)
+	}
+}
+
+func TestRewriteModelRejectsNonObject(t *testing.T) {
+	if _, err := RewriteModel([]byte(`[1,2]`), "m"); err != ErrNotObject {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestPeekModelAndStream(t *testing.T) {
+	body := []byte(`{"stream":true,"model":"gpt-4","n":1}`)
+	model, ok := PeekModel(body)
+	if !ok || model != "gpt-4" {
+		t.Fatalf("model = %q, %v", model, ok)
+	}
+	if !PeekStream(body) {
+		t.Fatal("stream = false")
+	}
+	if PeekStream([]byte(`{"model":"x"}`)) {
+		t.Fatal("absent stream = true")
+	}
+	if _, ok := PeekModel([]byte(`{"model":42}`)); ok {
+		t.Fatal("non-string model accepted")
+	}
+}
+
+func TestAppendRewriteModelReusesBuffer(t *testing.T) {
+	buf := make([]byte, 0, 256)
+	body := []byte(`{"model":"a","x":1}`)
+	got, err := AppendRewriteModel(buf, body, "bb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `{"model":"bb","x":1}` {
+		t.Fatalf("rewritten = %s", got)
+	}
+	if &buf[:1][0] != &got[:1][0] {
+		t.Fatal("buffer was reallocated despite sufficient capacity")
+	}
+}
+
+func BenchmarkRewriteModel(b *testing.B) {
+	body := []byte(`{"model":"gpt-4","messages":[{"role":"system","content":"be brief"},{"role":"user","content":"summarize the attached document in three bullet points"}],"temperature":0.2,"max_tokens":512}`)
+	buf := make([]byte, 0, len(body)+32)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if _, err = AppendRewriteModel(buf[:0], body, "llama-3-70b"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
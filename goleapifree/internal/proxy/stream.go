@@ -0,0 +1,239 @@
+// Package proxy implements the request forwarding path between clients
+// and provider adapters, including the streaming machinery.
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// Streaming errors surfaced to the caller for accounting.
+var (
+	// ErrByteCapExceeded means the per-connection byte cap was hit; the
+	// stream is truncated, not failed, so partial output still reached
+	// the client.
+	ErrByteCapExceeded = errors.New("proxy: stream byte cap exceeded")
+	// ErrSlowClient means the client could not drain the stream within
+	// the write timeout and the connection was dropped to free the
+	// upstream slot.
+	ErrSlowClient = errors.New("proxy: slow client, stream aborted")
+	// ErrUpstreamStalled means the provider stopped producing chunks
+	// without closing the stream (half-open connection). Callers should
+	// treat it as a provider failure and fail over.
+	ErrUpstreamStalled = errors.New("proxy: upstream stream stalled")
+)
+
+// StreamLimits bounds one streaming connection. The zero value applies
+// defaults from DefaultStreamLimits.
+type StreamLimits struct {
+	// MaxBytes caps total bytes written to the client; 0 = unlimited.
+	MaxBytes int64
+	// WriteTimeout is how long one client write may stall before the
+	// connection is treated as a slow client and dropped.
+	WriteTimeout time.Duration
+	// BufferSize is the fixed per-connection chunk buffer. Upstream
+	// reads block once it is full, which is the backpressure that keeps
+	// memory bounded no matter how slow the client is.
+	BufferSize int
+	// KeepaliveInterval sends an SSE comment frame when no chunk has
+	// arrived for this long, so intermediary proxies do not drop idle
+	// connections during slow completions. 0 disables keepalives.
+	KeepaliveInterval time.Duration
+	// UpstreamIdleTimeout treats the upstream as half-open when no chunk
+	// arrives for this long, ending the stream with ErrUpstreamStalled
+	// so the caller can fail over. 0 disables detection.
+	UpstreamIdleTimeout time.Duration
+	// OnDelta observes each delta after its write to the client succeeds.
+	// The stall watchdog uses it to know exactly what the client has seen,
+	// so a failover can continue from the right place.
+	OnDelta func(delta string)
+	// Profile selects the client's event verbosity; the zero value is
+	// the standard OpenAI-style stream.
+	Profile StreamProfile
+	// PaceTokensPerSec smooths delta writes to a target rate for
+	// consumers that want steady output (TTS, typewriter UIs).
+	// 0 streams at upstream speed.
+	PaceTokensPerSec float64
+}
+
+// DefaultStreamLimits are applied for unset fields.
+var DefaultStreamLimits = StreamLimits{
+	MaxBytes:     32 << 20, // 32 MiB
+	WriteTimeout: 30 * time.Second,
+	BufferSize:   32,
+}
+
+func (l StreamLimits) withDefaults() StreamLimits {
+	if l.MaxBytes == 0 {
+		l.MaxBytes = DefaultStreamLimits.MaxBytes
+	}
+	if l.WriteTimeout == 0 {
+		l.WriteTimeout = DefaultStreamLimits.WriteTimeout
+	}
+	if l.BufferSize == 0 {
+		l.BufferSize = DefaultStreamLimits.BufferSize
+	}
+	return l
+}
+
+// flusher is satisfied by buffered response writers.
+type flusher interface{ Flush() error }
+
+// PumpSSE forwards provider chunks to the client as OpenAI-style SSE.
+// Chunks flow through a fixed-size buffer: the upstream send blocks when
+// the buffer is full, propagating backpressure to the provider read loop
+// instead of buffering unbounded data per connection.
+func PumpSSE(ctx context.Context, chunks <-chan providers.StreamChunk, w io.Writer, limits StreamLimits) error {
+	limits = limits.withDefaults()
+	buffered := make(chan providers.StreamChunk, limits.BufferSize)
+
+	go func() {
+		defer close(buffered)
+		for chunk := range chunks {
+			select {
+			case buffered <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Content-only clients opted out of comment frames entirely.
+	if limits.Profile.ContentOnly {
+		limits.KeepaliveInterval = 0
+	}
+	pace := newPacer(limits.PaceTokensPerSec)
+	keepalive := newOptionalTimer(limits.KeepaliveInterval)
+	idle := newOptionalTimer(limits.UpstreamIdleTimeout)
+	defer keepalive.stop()
+	defer idle.stop()
+
+	var written int64
+	for {
+		select {
+		case chunk, ok := <-buffered:
+			if !ok {
+				return ctx.Err()
+			}
+			if chunk.Err != nil {
+				return chunk.Err
+			}
+			var payload []byte
+			if chunk.Done {
+				payload = []byte("data: [DONE]\n\n")
+			} else {
+				frame := map[string]any{
+					"choices": []map[string]any{{"delta": map[string]string{"content": chunk.Delta}}},
+				}
+				if limits.Profile.TokenCounts {
+					frame["usage"] = map[string]int{"chunk_tokens": estimateTokens(chunk.Delta)}
+				}
+				data, err := json.Marshal(frame)
+				if err != nil {
+					return err
+				}
+				payload = []byte(fmt.Sprintf("data: %s\n\n", data))
+			}
+
+			if limits.MaxBytes > 0 && written+int64(len(payload)) > limits.MaxBytes {
+				return ErrByteCapExceeded
+			}
+			if !chunk.Done {
+				pace.wait(estimateTokens(chunk.Delta))
+			}
+			if err := writeWithTimeout(w, payload, limits.WriteTimeout); err != nil {
+				return err
+			}
+			written += int64(len(payload))
+			if chunk.Done {
+				return nil
+			}
+			if limits.OnDelta != nil {
+				limits.OnDelta(chunk.Delta)
+			}
+			keepalive.reset(limits.KeepaliveInterval)
+			idle.reset(limits.UpstreamIdleTimeout)
+
+		case <-keepalive.c():
+			// SSE comment frames are ignored by clients but keep the
+			// connection warm through intermediary proxies. Rich streams
+			// name the serving provider so dashboards can show it.
+			frame := ": keepalive\n\n"
+			if limits.Profile.Heartbeat {
+				frame = fmt.Sprintf(": heartbeat provider=%s\n\n", limits.Profile.Provider)
+			}
+			if err := writeWithTimeout(w, []byte(frame), limits.WriteTimeout); err != nil {
+				return err
+			}
+			keepalive.reset(limits.KeepaliveInterval)
+
+		case <-idle.c():
+			return ErrUpstreamStalled
+		}
+	}
+}
+
+// optionalTimer wraps a timer that may be disabled; its channel never
+// fires when disabled, which keeps the pump's select simple.
+type optionalTimer struct{ t *time.Timer }
+
+func newOptionalTimer(d time.Duration) optionalTimer {
+	if d <= 0 {
+		return optionalTimer{}
+	}
+	return optionalTimer{t: time.NewTimer(d)}
+}
+
+func (o optionalTimer) c() <-chan time.Time {
+	if o.t == nil {
+		return nil
+	}
+	return o.t.C
+}
+
+func (o optionalTimer) reset(d time.Duration) {
+	if o.t == nil {
+		return
+	}
+	if !o.t.Stop() {
+		select {
+		case <-o.t.C:
+		default:
+		}
+	}
+	o.t.Reset(d)
+}
+
+func (o optionalTimer) stop() {
+	if o.t != nil {
+		o.t.Stop()
+	}
+}
+
+// writeWithTimeout performs one client write, failing with ErrSlowClient
+// when it stalls beyond the timeout. The write itself runs on the calling
+// goroutine's writer, so a timed-out write abandons the connection.
+func writeWithTimeout(w io.Writer, payload []byte, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := w.Write(payload)
+		if err == nil {
+			if f, ok := w.(flusher); ok {
+				err = f.Flush()
+			}
+		}
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return ErrSlowClient
+	}
+}
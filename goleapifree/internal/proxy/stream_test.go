@@ -0,0 +1,124 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+func chunkChan(deltas ...string) <-chan providers.StreamChunk {
+	ch := make(chan providers.StreamChunk, len(deltas)+1)
+	for _, d := range deltas {
+		ch <- providers.StreamChunk{Delta: d}
+	}
+	ch <- providers.StreamChunk{Done: true}
+	close(ch)
+	return ch
+}
+
+func TestPumpSSEForwardsChunks(t *testing.T) {
+	var buf bytes.Buffer
+	err := PumpSSE(context.Background(), chunkChan("hello", " world"), &buf, StreamLimits{})
+	if err != nil {
+		t.Fatalf("PumpSSE: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "hello") || !strings.Contains(out, " world") {
+		t.Fatalf("deltas missing from output: %q", out)
+	}
+	if !strings.HasSuffix(out, "data: [DONE]\n\n") {
+		t.Fatalf("stream not terminated: %q", out)
+	}
+}
+
+func TestPumpSSEByteCap(t *testing.T) {
+	var buf bytes.Buffer
+	err := PumpSSE(context.Background(), chunkChan(strings.Repeat("x", 100)), &buf, StreamLimits{MaxBytes: 50})
+	if !errors.Is(err, ErrByteCapExceeded) {
+		t.Fatalf("err = %v, want ErrByteCapExceeded", err)
+	}
+}
+
+// stalledWriter blocks every write until released.
+type stalledWriter struct{ release chan struct{} }
+
+func (w *stalledWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return len(p), nil
+}
+
+func TestPumpSSESlowClient(t *testing.T) {
+	w := &stalledWriter{release: make(chan struct{})}
+	defer close(w.release)
+	err := PumpSSE(context.Background(), chunkChan("data"), w, StreamLimits{WriteTimeout: 20 * time.Millisecond})
+	if !errors.Is(err, ErrSlowClient) {
+		t.Fatalf("err = %v, want ErrSlowClient", err)
+	}
+}
+
+func TestPumpSSEBackpressure(t *testing.T) {
+	// With a buffer of 1 and a stalled client, the upstream send loop
+	// must block instead of draining the whole source channel.
+	src := make(chan providers.StreamChunk)
+	w := &stalledWriter{release: make(chan struct{})}
+	done := make(chan error, 1)
+	go func() {
+		done <- PumpSSE(context.Background(), src, w, StreamLimits{BufferSize: 1, WriteTimeout: time.Minute})
+	}()
+
+	sent := 0
+	timeout := time.After(200 * time.Millisecond)
+loop:
+	for {
+		select {
+		case src <- providers.StreamChunk{Delta: "d"}:
+			sent++
+		case <-timeout:
+			break loop
+		}
+	}
+	// One chunk in flight at the writer, one in the buffer, one blocked
+	// in the pump's forwarding goroutine.
+	if sent > 3 {
+		t.Fatalf("upstream sent %d chunks against a stalled client, want backpressure", sent)
+	}
+	close(w.release)
+	close(src)
+	if err := <-done; err != nil && !errors.Is(err, io.EOF) && err != context.Canceled {
+		t.Logf("pump finished with %v", err)
+	}
+}
+
+func TestPumpSSEKeepalive(t *testing.T) {
+	src := make(chan providers.StreamChunk)
+	var buf bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- PumpSSE(context.Background(), src, &buf, StreamLimits{KeepaliveInterval: 10 * time.Millisecond})
+	}()
+	time.Sleep(50 * time.Millisecond)
+	src <- providers.StreamChunk{Done: true}
+	close(src)
+	if err := <-done; err != nil {
+		t.Fatalf("PumpSSE: %v", err)
+	}
+	if !strings.Contains(buf.String(), ": keepalive\n\n") {
+		t.Fatalf("no keepalive frame in output: %q", buf.String())
+	}
+}
+
+func TestPumpSSEUpstreamStalled(t *testing.T) {
+	src := make(chan providers.StreamChunk)
+	defer close(src)
+	var buf bytes.Buffer
+	err := PumpSSE(context.Background(), src, &buf, StreamLimits{UpstreamIdleTimeout: 20 * time.Millisecond})
+	if !errors.Is(err, ErrUpstreamStalled) {
+		t.Fatalf("err = %v, want ErrUpstreamStalled", err)
+	}
+}
@@ -0,0 +1,121 @@
+package proxy
+
+// Tool-call aggregation: providers fragment tool_call arguments across
+// many streaming chunks, and they fragment them differently, which
+// pushes reassembly onto every client. When a client opts in via the
+// aggregation header, the gateway buffers the fragments and emits each
+// complete tool call as a single chunk in the OpenAI format.
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// ToolCallAggregationHeader is the opt-in request header; any non-empty
+// value enables aggregation for that stream.
+const ToolCallAggregationHeader = "X-Aggregate-Tool-Calls"
+
+// ToolCall is one complete call in the OpenAI wire shape.
+type ToolCall struct {
+	Index    int    `json:"index"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments,omitempty"`
+	} `json:"function"`
+}
+
+// chunkShape is the subset of an OpenAI streaming chunk the aggregator
+// inspects; everything else passes through untouched.
+type chunkShape struct {
+	Choices []struct {
+		Delta struct {
+			Content   string     `json:"content"`
+			ToolCalls []ToolCall `json:"tool_calls"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// ToolCallAggregator reassembles one stream's tool calls. It is not
+// safe for concurrent use; each stream gets its own.
+type ToolCallAggregator struct {
+	pending map[int]*ToolCall
+}
+
+// NewToolCallAggregator creates an aggregator for one stream.
+func NewToolCallAggregator() *ToolCallAggregator {
+	return &ToolCallAggregator{pending: make(map[int]*ToolCall)}
+}
+
+// Process consumes one chunk's JSON and returns the chunks to forward
+// now. Content chunks pass through unchanged; tool-call fragments are
+// buffered and return nothing until the finish_reason arrives, at which
+// point the complete calls go out as a single chunk. Unparsable data
+// passes through so aggregation never corrupts a stream.
+func (a *ToolCallAggregator) Process(data []byte) [][]byte {
+	var chunk chunkShape
+	if err := json.Unmarshal(data, &chunk); err != nil || len(chunk.Choices) == 0 {
+		return [][]byte{data}
+	}
+	choice := chunk.Choices[0]
+	for _, fragment := range choice.Delta.ToolCalls {
+		call, ok := a.pending[fragment.Index]
+		if !ok {
+			call = &ToolCall{Index: fragment.Index}
+			a.pending[fragment.Index] = call
+		}
+		if fragment.ID != "" {
+			call.ID = fragment.ID
+		}
+		if fragment.Type != "" {
+			call.Type = fragment.Type
+		}
+		if fragment.Function.Name != "" {
+			call.Function.Name = fragment.Function.Name
+		}
+		call.Function.Arguments += fragment.Function.Arguments
+	}
+	if choice.FinishReason == "tool_calls" {
+		return a.flush("tool_calls")
+	}
+	if len(choice.Delta.ToolCalls) > 0 {
+		// Fragment buffered; nothing to forward yet.
+		return nil
+	}
+	return [][]byte{data}
+}
+
+// Flush emits any buffered calls, for streams that end without a
+// tool_calls finish_reason (some providers just close the stream).
+func (a *ToolCallAggregator) Flush() [][]byte {
+	return a.flush("")
+}
+
+func (a *ToolCallAggregator) flush(finishReason string) [][]byte {
+	if len(a.pending) == 0 {
+		return nil
+	}
+	calls := make([]ToolCall, 0, len(a.pending))
+	for _, call := range a.pending {
+		calls = append(calls, *call)
+	}
+	sort.Slice(calls, func(i, j int) bool { return calls[i].Index < calls[j].Index })
+	a.pending = make(map[int]*ToolCall)
+
+	out := map[string]any{
+		"choices": []map[string]any{{
+			"index": 0,
+			"delta": map[string]any{"tool_calls": calls},
+		}},
+	}
+	if finishReason != "" {
+		out["choices"].([]map[string]any)[0]["finish_reason"] = finishReason
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil
+	}
+	return [][]byte{data}
+}
@@ -0,0 +1,79 @@
+package proxy
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAggregatorBuffersFragmentsUntilFinish(t *testing.T) {
+	a := NewToolCallAggregator()
+
+	// OpenAI-style fragmentation: id and name on the first fragment,
+	// arguments split across the rest.
+	fragments := []string{
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Oslo\"}"}}]}}]}`,
+	}
+	for _, f := range fragments {
+		if got := a.Process([]byte(f)); got != nil {
+			t.Fatalf("fragment forwarded early: %s", got)
+		}
+	}
+
+	final := a.Process([]byte(`{"choices":[{"delta":{},"finish_reason":"tool_calls"}]}`))
+	if len(final) != 1 {
+		t.Fatalf("emitted %d chunks, want 1", len(final))
+	}
+	var chunk chunkShape
+	if err := json.Unmarshal(final[0], &chunk); err != nil {
+		t.Fatal(err)
+	}
+	calls := chunk.Choices[0].Delta.ToolCalls
+	if len(calls) != 1 || calls[0].ID != "call_1" || calls[0].Function.Name != "get_weather" {
+		t.Fatalf("calls = %+v", calls)
+	}
+	if calls[0].Function.Arguments != `{"city":"Oslo"}` {
+		t.Fatalf("arguments = %q", calls[0].Function.Arguments)
+	}
+	if chunk.Choices[0].FinishReason != "tool_calls" {
+		t.Fatalf("finish_reason = %q", chunk.Choices[0].FinishReason)
+	}
+}
+
+func TestAggregatorPassesContentThrough(t *testing.T) {
+	a := NewToolCallAggregator()
+	data := []byte(`{"choices":[{"delta":{"content":"hello"}}]}`)
+	got := a.Process(data)
+	if len(got) != 1 || string(got[0]) != string(data) {
+		t.Fatalf("content chunk altered: %s", got)
+	}
+	// Malformed data must pass through rather than be dropped.
+	junk := []byte(`not json`)
+	if got := a.Process(junk); len(got) != 1 || string(got[0]) != "not json" {
+		t.Fatalf("junk handling = %s", got)
+	}
+}
+
+func TestAggregatorHandlesParallelCallsAndFlush(t *testing.T) {
+	a := NewToolCallAggregator()
+	a.Process([]byte(`{"choices":[{"delta":{"tool_calls":[{"index":1,"id":"call_b","function":{"name":"b","arguments":"{}"}}]}}]}`))
+	a.Process([]byte(`{"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_a","function":{"name":"a","arguments":"{}"}}]}}]}`))
+
+	// Stream closed without a finish_reason: Flush still completes.
+	out := a.Flush()
+	if len(out) != 1 {
+		t.Fatalf("flush emitted %d chunks", len(out))
+	}
+	var chunk chunkShape
+	if err := json.Unmarshal(out[0], &chunk); err != nil {
+		t.Fatal(err)
+	}
+	calls := chunk.Choices[0].Delta.ToolCalls
+	if len(calls) != 2 || calls[0].ID != "call_a" || calls[1].ID != "call_b" {
+		t.Fatalf("calls = %+v", calls)
+	}
+	if a.Flush() != nil {
+		t.Fatal("second flush re-emitted calls")
+	}
+}
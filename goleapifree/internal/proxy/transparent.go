@@ -0,0 +1,236 @@
+package proxy
+
+// Transparent mode lets unmodified OpenAI clients (tools with no
+// base-URL setting) run through the gateway: the gateway acts as an
+// HTTPS proxy, terminates TLS for api.openai.com with its own CA,
+// rewrites auth and hands the request to the normal pipeline. Traffic
+// for every other host is tunneled untouched.
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// InterceptedHost is the default host transparent mode terminates.
+const InterceptedHost = "api.openai.com"
+
+// CA is the interception certificate authority. Its certificate must be
+// installed on client machines; leaves for intercepted hosts are minted
+// on demand and cached.
+type CA struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+
+	mu     sync.Mutex
+	leaves map[string]*tls.Certificate
+}
+
+// NewCA generates a fresh interception CA valid for ten years.
+func NewCA() (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "goleapai transparent proxy CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		leaves:  make(map[string]*tls.Certificate),
+	}, nil
+}
+
+// CertPEM returns the CA certificate for distribution to clients.
+func (ca *CA) CertPEM() []byte { return ca.certPEM }
+
+// Pool returns a cert pool containing just this CA, for tests and for
+// gateway-internal clients.
+func (ca *CA) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// leaf mints (or returns the cached) server certificate for host.
+func (ca *CA) leaf(host string) (*tls.Certificate, error) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	if leaf, ok := ca.leaves[host]; ok {
+		return leaf, nil
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, err
+	}
+	leaf := &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	ca.leaves[host] = leaf
+	return leaf, nil
+}
+
+// Interceptor is the CONNECT proxy. Intercepted hosts terminate at the
+// gateway pipeline; everything else is tunneled byte-for-byte.
+type Interceptor struct {
+	CA *CA
+	// Hosts lists hostnames (without port) to intercept.
+	Hosts map[string]bool
+	// Handler receives intercepted requests with the client's
+	// Authorization header stripped — the pipeline injects provider
+	// credentials itself.
+	Handler http.Handler
+	// Dial opens tunnels for pass-through hosts; defaults to net.Dial.
+	Dial func(network, addr string) (net.Conn, error)
+}
+
+// NewInterceptor builds an interceptor for the default OpenAI host.
+func NewInterceptor(ca *CA, handler http.Handler) *Interceptor {
+	return &Interceptor{
+		CA:      ca,
+		Hosts:   map[string]bool{InterceptedHost: true},
+		Handler: handler,
+		Dial:    net.Dial,
+	}
+}
+
+// ServeHTTP implements the proxy side: only CONNECT is meaningful here.
+func (i *Interceptor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "transparent proxy speaks CONNECT only", http.StatusMethodNotAllowed)
+		return
+	}
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "hijacking unsupported", http.StatusInternalServerError)
+		return
+	}
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		return
+	}
+	if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+		conn.Close()
+		return
+	}
+
+	if i.Hosts[host] {
+		i.terminate(conn, host)
+		return
+	}
+	i.tunnel(conn, r.Host)
+}
+
+// terminate completes the TLS handshake with a minted certificate and
+// serves the decrypted requests through the gateway handler.
+func (i *Interceptor) terminate(conn net.Conn, host string) {
+	leaf, err := i.CA.leaf(host)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{*leaf}})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The client's key is whatever placeholder the tool was
+		// configured with; the pipeline supplies real credentials.
+		r.Header.Del("Authorization")
+		r.Header.Set("X-Intercepted-Host", host)
+		r.URL.Scheme = "https"
+		r.URL.Host = host
+		i.Handler.ServeHTTP(w, r)
+	})
+	http.Serve(&singleConnListener{conn: tlsConn}, handler)
+}
+
+// tunnel blind-copies bytes for hosts outside the intercept list.
+func (i *Interceptor) tunnel(conn net.Conn, addr string) {
+	upstream, err := i.Dial("tcp", addr)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go func() {
+		defer upstream.Close()
+		io.Copy(upstream, conn)
+	}()
+	go func() {
+		defer conn.Close()
+		io.Copy(conn, upstream)
+	}()
+}
+
+// singleConnListener adapts one hijacked connection to net.Listener so
+// net/http can serve it.
+type singleConnListener struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (l *singleConnListener) Accept() (net.Conn, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil, fmt.Errorf("listener closed")
+	}
+	c := l.conn
+	l.conn = nil
+	return c, nil
+}
+
+func (l *singleConnListener) Close() error { return nil }
+
+func (l *singleConnListener) Addr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4zero, Port: 0}
+}
@@ -0,0 +1,85 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// defaultStreamAttempts bounds how many providers one completion may
+// burn through before the stall is surfaced to the client.
+const defaultStreamAttempts = 3
+
+// ErrAllStreamsStalled means every failover attempt stalled too; the
+// client keeps whatever text was emitted before the last stall.
+var ErrAllStreamsStalled = errors.New("proxy: all stream attempts stalled")
+
+// StreamSource opens the stream for one failover attempt. emitted is the
+// text the client has already seen from earlier attempts — attempt 0
+// always gets ""; later attempts can feed it back to the fallback
+// provider as context so the completion resumes rather than restarts.
+type StreamSource func(ctx context.Context, attempt int, emitted string) (providerID string, chunks <-chan providers.StreamChunk, err error)
+
+// Watchdog supervises a streaming completion. When the upstream stops
+// producing tokens for longer than the stall threshold, it aborts that
+// stream, counts the stall against the provider, and asks the source for
+// the next one — preserving already-emitted text as context instead of
+// throwing the partial completion away.
+type Watchdog struct {
+	// Limits applies to each attempt; UpstreamIdleTimeout is the stall
+	// threshold and must be set.
+	Limits StreamLimits
+	// MaxAttempts caps total providers tried; 0 applies the default.
+	MaxAttempts int
+	// Source opens the stream for each attempt.
+	Source StreamSource
+	// OnStall records one stall against a provider; the wiring binds it
+	// to the reliability scorer so chronic stallers lose routing weight.
+	OnStall func(providerID string)
+}
+
+// Pump runs the completion to the client, failing over across stalled
+// providers. It returns nil once any attempt finishes the stream, the
+// attempt's error for non-stall failures, and ErrAllStreamsStalled when
+// the attempt budget runs out.
+func (wd *Watchdog) Pump(ctx context.Context, w io.Writer) error {
+	if wd.Limits.UpstreamIdleTimeout <= 0 {
+		return errors.New("proxy: watchdog needs an UpstreamIdleTimeout")
+	}
+	attempts := wd.MaxAttempts
+	if attempts <= 0 {
+		attempts = defaultStreamAttempts
+	}
+
+	var emitted strings.Builder
+	limits := wd.Limits
+	next := limits.OnDelta
+	limits.OnDelta = func(delta string) {
+		emitted.WriteString(delta)
+		if next != nil {
+			next(delta)
+		}
+	}
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		providerID, chunks, err := wd.Source(ctx, attempt, emitted.String())
+		if err != nil {
+			return err
+		}
+		// A per-attempt context releases the abandoned stream's
+		// goroutines once we move on to the next provider.
+		attemptCtx, cancel := context.WithCancel(ctx)
+		err = PumpSSE(attemptCtx, chunks, w, limits)
+		cancel()
+		if !errors.Is(err, ErrUpstreamStalled) {
+			return err
+		}
+		if wd.OnStall != nil {
+			wd.OnStall(providerID)
+		}
+	}
+	return ErrAllStreamsStalled
+}
@@ -0,0 +1,83 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// stallChan sends its deltas and then goes silent without closing,
+// simulating a half-open provider stream.
+func stallChan(deltas ...string) <-chan providers.StreamChunk {
+	ch := make(chan providers.StreamChunk, len(deltas))
+	for _, d := range deltas {
+		ch <- providers.StreamChunk{Delta: d}
+	}
+	return ch
+}
+
+func TestWatchdogFailsOver(t *testing.T) {
+	var buf bytes.Buffer
+	var stalled []string
+	var handoff string
+	wd := &Watchdog{
+		Limits: StreamLimits{UpstreamIdleTimeout: 20 * time.Millisecond},
+		Source: func(_ context.Context, attempt int, emitted string) (string, <-chan providers.StreamChunk, error) {
+			if attempt == 0 {
+				return "stalling", stallChan("The answer "), nil
+			}
+			handoff = emitted
+			return "healthy", chunkChan("is 42."), nil
+		},
+		OnStall: func(id string) { stalled = append(stalled, id) },
+	}
+	if err := wd.Pump(context.Background(), &buf); err != nil {
+		t.Fatalf("Pump: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "The answer ") || !strings.Contains(out, "is 42.") {
+		t.Fatalf("output = %q", out)
+	}
+	if !strings.HasSuffix(out, "data: [DONE]\n\n") {
+		t.Fatalf("stream not terminated: %q", out)
+	}
+	if handoff != "The answer " {
+		t.Fatalf("fallback got emitted = %q", handoff)
+	}
+	if len(stalled) != 1 || stalled[0] != "stalling" {
+		t.Fatalf("stalls = %v", stalled)
+	}
+}
+
+func TestWatchdogGivesUp(t *testing.T) {
+	var buf bytes.Buffer
+	stalls := 0
+	wd := &Watchdog{
+		Limits:      StreamLimits{UpstreamIdleTimeout: 10 * time.Millisecond},
+		MaxAttempts: 2,
+		Source: func(_ context.Context, attempt int, _ string) (string, <-chan providers.StreamChunk, error) {
+			return "p", stallChan(), nil
+		},
+		OnStall: func(string) { stalls++ },
+	}
+	if err := wd.Pump(context.Background(), &buf); !errors.Is(err, ErrAllStreamsStalled) {
+		t.Fatalf("err = %v, want ErrAllStreamsStalled", err)
+	}
+	if stalls != 2 {
+		t.Fatalf("stalls = %d", stalls)
+	}
+}
+
+func TestWatchdogRequiresStallThreshold(t *testing.T) {
+	wd := &Watchdog{Source: func(context.Context, int, string) (string, <-chan providers.StreamChunk, error) {
+		return "p", chunkChan("x"), nil
+	}}
+	if err := wd.Pump(context.Background(), &bytes.Buffer{}); err == nil {
+		t.Fatal("missing stall threshold accepted")
+	}
+}
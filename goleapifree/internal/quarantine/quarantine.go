@@ -0,0 +1,216 @@
+// Package quarantine validates provider responses and temporarily pulls
+// providers that repeatedly return garbage (HTML error pages, truncated
+// JSON) out of routing instead of letting them poison client responses.
+package quarantine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Verdict scores one response payload. Score is in [0,1]; anything below
+// 1 carries a Reason describing what looked wrong.
+type Verdict struct {
+	Score  float64
+	Reason string
+}
+
+// OK reports whether the payload passed validation.
+func (v Verdict) OK() bool { return v.Score >= 1 }
+
+// Validate scores the sanity of one provider response body. providerType
+// selects the expected shape; unknown types only get the generic checks.
+func Validate(providerType string, body []byte) Verdict {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return Verdict{Score: 0, Reason: "empty body"}
+	}
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		return Verdict{Score: 0, Reason: "HTML where JSON expected"}
+	}
+	if !json.Valid(trimmed) {
+		return Verdict{Score: 0, Reason: "invalid or truncated JSON"}
+	}
+
+	var parsed map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &parsed); err != nil {
+		return Verdict{Score: 0.5, Reason: "JSON body is not an object"}
+	}
+	// An error object is a valid provider response, not garbage.
+	if _, ok := parsed["error"]; ok {
+		return Verdict{Score: 1}
+	}
+	if field := requiredField(providerType); field != "" {
+		if _, ok := parsed[field]; !ok {
+			return Verdict{Score: 0.5, Reason: fmt.Sprintf("missing %q field", field)}
+		}
+	}
+	return Verdict{Score: 1}
+}
+
+// requiredField is the top-level field a well-formed success response
+// carries for each provider family.
+func requiredField(providerType string) string {
+	switch providerType {
+	case "anthropic", "bedrock":
+		return "content"
+	case "vertex", "google":
+		return "candidates"
+	case "openai", "openrouter", "groq":
+		return "choices"
+	default:
+		return ""
+	}
+}
+
+// Config sets quarantine thresholds.
+type Config struct {
+	// FailureThreshold is the consecutive validation failures that
+	// trigger quarantine.
+	FailureThreshold int
+	// Cooldown is how long a quarantined provider stays out of routing.
+	Cooldown time.Duration
+	// OnQuarantine is called (if set) when a provider is pulled, for
+	// alerting. It runs on the recording goroutine; keep it fast.
+	OnQuarantine func(providerID, reason string)
+}
+
+type providerState struct {
+	consecutive int
+	until       time.Time
+	lastReason  string
+}
+
+// Tracker records validation outcomes per provider and decides who is
+// quarantined. It is the router's Exclude hook.
+type Tracker struct {
+	cfg Config
+	now func() time.Time
+
+	mu    sync.Mutex
+	state map[string]*providerState
+}
+
+// NewTracker creates a tracker with defaulted thresholds
+// (3 failures, 5 minute cooldown).
+func NewTracker(cfg Config) *Tracker {
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = 3
+	}
+	if cfg.Cooldown == 0 {
+		cfg.Cooldown = 5 * time.Minute
+	}
+	return &Tracker{cfg: cfg, now: time.Now, state: make(map[string]*providerState)}
+}
+
+// Record feeds one validation verdict for a provider. A passing verdict
+// resets the failure streak; a failing one may trip quarantine.
+func (t *Tracker) Record(providerID string, v Verdict) {
+	t.mu.Lock()
+	st, ok := t.state[providerID]
+	if !ok {
+		st = &providerState{}
+		t.state[providerID] = st
+	}
+	if v.OK() {
+		st.consecutive = 0
+		t.mu.Unlock()
+		return
+	}
+	st.consecutive++
+	st.lastReason = v.Reason
+	tripped := st.consecutive >= t.cfg.FailureThreshold && t.now().After(st.until)
+	if tripped {
+		st.until = t.now().Add(t.cfg.Cooldown)
+	}
+	t.mu.Unlock()
+	if tripped && t.cfg.OnQuarantine != nil {
+		t.cfg.OnQuarantine(providerID, v.Reason)
+	}
+}
+
+// Quarantined reports whether a provider is currently out of routing.
+// Its signature matches the router's Exclude hook.
+func (t *Tracker) Quarantined(providerID string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	st, ok := t.state[providerID]
+	return ok && t.now().Before(st.until)
+}
+
+// persistedState is the serialized form of providerState; the live
+// struct keeps its fields unexported.
+type persistedState struct {
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Until               time.Time `json:"until,omitempty"`
+	LastReason          string    `json:"last_reason,omitempty"`
+}
+
+// Snapshot serializes the tracker state so quarantines survive a
+// restart; Restore loads it back. Both fit the warmup snapshot pattern.
+func (t *Tracker) Snapshot() ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make(map[string]persistedState, len(t.state))
+	for id, st := range t.state {
+		out[id] = persistedState{
+			ConsecutiveFailures: st.consecutive,
+			Until:               st.until,
+			LastReason:          st.lastReason,
+		}
+	}
+	return json.Marshal(out)
+}
+
+// Restore replaces the tracker state from a Snapshot payload. Cooldowns
+// that expired while the process was down simply read as not quarantined.
+func (t *Tracker) Restore(data []byte) error {
+	in := make(map[string]persistedState)
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	state := make(map[string]*providerState, len(in))
+	for id, st := range in {
+		state[id] = &providerState{
+			consecutive: st.ConsecutiveFailures,
+			until:       st.Until,
+			lastReason:  st.LastReason,
+		}
+	}
+	t.mu.Lock()
+	t.state = state
+	t.mu.Unlock()
+	return nil
+}
+
+// Status describes one provider's quarantine state for the admin API.
+type Status struct {
+	ProviderID          string    `json:"provider_id"`
+	Quarantined         bool      `json:"quarantined"`
+	Until               time.Time `json:"until,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastReason          string    `json:"last_reason,omitempty"`
+}
+
+// Statuses returns the state of every tracked provider.
+func (t *Tracker) Statuses() []Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]Status, 0, len(t.state))
+	for id, st := range t.state {
+		s := Status{
+			ProviderID:          id,
+			Quarantined:         t.now().Before(st.until),
+			ConsecutiveFailures: st.consecutive,
+			LastReason:          st.lastReason,
+		}
+		if s.Quarantined {
+			s.Until = st.until
+		}
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package quarantine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateCatchesGarbage(t *testing.T) {
+	cases := []struct {
+		name string
+		typ  string
+		body string
+		ok   bool
+	}{
+		{"html error page", "openai", "<html><body>502</body></html>", false},
+		{"truncated json", "openai", `{"choices":[{"message":`, false},
+		{"empty", "openai", "", false},
+		{"valid openai", "openai", `{"choices":[{"message":{"content":"hi"}}]}`, true},
+		{"valid anthropic", "anthropic", `{"content":[{"type":"text","text":"hi"}]}`, true},
+		{"wrong shape", "anthropic", `{"choices":[]}`, false},
+		{"error object is valid", "openai", `{"error":{"message":"rate limited"}}`, true},
+		{"unknown type generic only", "mystery", `{"anything":1}`, true},
+	}
+	for _, tc := range cases {
+		if got := Validate(tc.typ, []byte(tc.body)).OK(); got != tc.ok {
+			t.Errorf("%s: OK() = %v, want %v", tc.name, got, tc.ok)
+		}
+	}
+}
+
+func TestTrackerQuarantinesAfterThreshold(t *testing.T) {
+	var alerted string
+	tr := NewTracker(Config{
+		FailureThreshold: 2,
+		Cooldown:         time.Minute,
+		OnQuarantine:     func(id, reason string) { alerted = id + ": " + reason },
+	})
+	now := time.Unix(1000, 0)
+	tr.now = func() time.Time { return now }
+
+	bad := Verdict{Score: 0, Reason: "HTML where JSON expected"}
+	tr.Record("p1", bad)
+	if tr.Quarantined("p1") {
+		t.Fatal("quarantined after a single failure")
+	}
+	tr.Record("p1", bad)
+	if !tr.Quarantined("p1") {
+		t.Fatal("not quarantined after reaching threshold")
+	}
+	if alerted == "" {
+		t.Fatal("OnQuarantine not called")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if tr.Quarantined("p1") {
+		t.Fatal("quarantine did not expire after cooldown")
+	}
+}
+
+func TestTrackerSuccessResetsStreak(t *testing.T) {
+	tr := NewTracker(Config{FailureThreshold: 2})
+	tr.Record("p1", Verdict{Score: 0, Reason: "empty body"})
+	tr.Record("p1", Verdict{Score: 1})
+	tr.Record("p1", Verdict{Score: 0, Reason: "empty body"})
+	if tr.Quarantined("p1") {
+		t.Fatal("non-consecutive failures tripped quarantine")
+	}
+}
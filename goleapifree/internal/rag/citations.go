@@ -0,0 +1,82 @@
+// Package rag post-processes retrieval-augmented answers. Models cite
+// the chunks they were given with inline markers; the gateway turns
+// those markers into structured citations, validates them against the
+// chunks actually retrieved, and flags references to chunks that were
+// never provided — the classic hallucinated citation.
+package rag
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// ExtensionField is the vendor extension key under which citations are
+// attached to a chat response.
+const ExtensionField = "x_goleapai_citations"
+
+// Chunk is one retrieved context chunk handed to the model.
+type Chunk struct {
+	ID        string `json:"id"`
+	SourceURI string `json:"source_uri,omitempty"`
+	Text      string `json:"text,omitempty"`
+}
+
+// Citation is one reference found in an answer. Offsets are byte
+// positions of the marker within the answer text.
+type Citation struct {
+	ChunkID   string `json:"chunk_id"`
+	SourceURI string `json:"source_uri,omitempty"`
+	Start     int    `json:"start"`
+	End       int    `json:"end"`
+	// Hallucinated marks a citation of a chunk that was not among the
+	// retrieved context; clients should not render it as a source.
+	Hallucinated bool `json:"hallucinated,omitempty"`
+}
+
+// Annotations is the full extension payload for one answer.
+type Annotations struct {
+	Citations []Citation `json:"citations"`
+	// Hallucinated counts flagged citations, so callers can alert on
+	// answers inventing sources without walking the list.
+	Hallucinated int `json:"hallucinated"`
+}
+
+// markerPattern matches both marker dialects models produce: explicit
+// [chunk:<id>] references and numeric [N] footnotes (1-based into the
+// retrieved chunk list).
+var markerPattern = regexp.MustCompile(`\[(?:chunk:([A-Za-z0-9_.-]+)|(\d{1,3}))\]`)
+
+// Annotate extracts and validates every citation marker in answer
+// against the retrieved chunks.
+func Annotate(answer string, chunks []Chunk) Annotations {
+	byID := make(map[string]Chunk, len(chunks))
+	for _, c := range chunks {
+		byID[c.ID] = c
+	}
+	ann := Annotations{Citations: []Citation{}}
+	for _, m := range markerPattern.FindAllStringSubmatchIndex(answer, -1) {
+		cit := Citation{Start: m[0], End: m[1]}
+		if m[2] >= 0 {
+			cit.ChunkID = answer[m[2]:m[3]]
+			if chunk, ok := byID[cit.ChunkID]; ok {
+				cit.SourceURI = chunk.SourceURI
+			} else {
+				cit.Hallucinated = true
+			}
+		} else {
+			n, _ := strconv.Atoi(answer[m[4]:m[5]])
+			if n >= 1 && n <= len(chunks) {
+				cit.ChunkID = chunks[n-1].ID
+				cit.SourceURI = chunks[n-1].SourceURI
+			} else {
+				cit.ChunkID = answer[m[0]:m[1]]
+				cit.Hallucinated = true
+			}
+		}
+		if cit.Hallucinated {
+			ann.Hallucinated++
+		}
+		ann.Citations = append(ann.Citations, cit)
+	}
+	return ann
+}
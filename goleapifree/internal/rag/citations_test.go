@@ -0,0 +1,51 @@
+package rag
+
+import "testing"
+
+func ragChunks() []Chunk {
+	return []Chunk{
+		{ID: "doc1-p3", SourceURI: "s3://docs/handbook.pdf#p3"},
+		{ID: "doc2-p1", SourceURI: "https://wiki.example.com/onboarding"},
+	}
+}
+
+func TestAnnotateResolvesBothMarkerDialects(t *testing.T) {
+	answer := "Expenses need approval [chunk:doc1-p3] and onboarding starts Monday [2]."
+	ann := Annotate(answer, ragChunks())
+	if len(ann.Citations) != 2 || ann.Hallucinated != 0 {
+		t.Fatalf("annotations = %+v", ann)
+	}
+	first := ann.Citations[0]
+	if first.ChunkID != "doc1-p3" || first.SourceURI != "s3://docs/handbook.pdf#p3" {
+		t.Fatalf("first citation = %+v", first)
+	}
+	if got := answer[first.Start:first.End]; got != "[chunk:doc1-p3]" {
+		t.Fatalf("offsets cover %q", got)
+	}
+	if ann.Citations[1].ChunkID != "doc2-p1" {
+		t.Fatalf("numeric citation = %+v", ann.Citations[1])
+	}
+}
+
+func TestAnnotateFlagsHallucinatedCitations(t *testing.T) {
+	answer := "Policy says so [chunk:doc9-p9], see also [5]."
+	ann := Annotate(answer, ragChunks())
+	if len(ann.Citations) != 2 || ann.Hallucinated != 2 {
+		t.Fatalf("annotations = %+v", ann)
+	}
+	for _, c := range ann.Citations {
+		if !c.Hallucinated {
+			t.Fatalf("citation not flagged: %+v", c)
+		}
+		if c.SourceURI != "" {
+			t.Fatalf("hallucinated citation got a source: %+v", c)
+		}
+	}
+}
+
+func TestAnnotateWithoutMarkers(t *testing.T) {
+	ann := Annotate("plain answer, no sources", ragChunks())
+	if len(ann.Citations) != 0 || ann.Hallucinated != 0 {
+		t.Fatalf("annotations = %+v", ann)
+	}
+}
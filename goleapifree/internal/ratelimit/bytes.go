@@ -0,0 +1,147 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ByteLimits caps traffic volume per key. Zero values mean "no limit of
+// that kind", matching RateLimit's convention.
+type ByteLimits struct {
+	// MaxRequestBytes caps one request body.
+	MaxRequestBytes int64 `json:"max_request_bytes,omitempty"`
+	// MaxResponseBytes caps one response body.
+	MaxResponseBytes int64 `json:"max_response_bytes,omitempty"`
+	// MonthlyBandwidth caps total request+response bytes per calendar
+	// month.
+	MonthlyBandwidth int64 `json:"monthly_bandwidth,omitempty"`
+}
+
+// ByteQuota tracks per-key byte usage against ByteLimits. Giant prompts
+// and runaway completions hurt small deployments long before request
+// counts do, so volume gets its own quota besides the rate limiters.
+type ByteQuota struct {
+	limits ByteLimits
+
+	mu    sync.Mutex
+	usage map[string]*bandwidthState
+	now   func() time.Time
+}
+
+type bandwidthState struct {
+	month string // "2006-01"
+	used  int64
+}
+
+// NewByteQuota creates a quota tracker for the given limits.
+func NewByteQuota(limits ByteLimits) *ByteQuota {
+	return &ByteQuota{
+		limits: limits,
+		usage:  make(map[string]*bandwidthState),
+		now:    time.Now,
+	}
+}
+
+// AllowRequest checks a request body size against the per-request cap
+// and the key's remaining monthly bandwidth.
+func (q *ByteQuota) AllowRequest(key string, size int64) bool {
+	if q.limits.MaxRequestBytes > 0 && size > q.limits.MaxRequestBytes {
+		return false
+	}
+	if q.limits.MonthlyBandwidth > 0 && q.used(key)+size > q.limits.MonthlyBandwidth {
+		return false
+	}
+	return true
+}
+
+// AllowResponse checks a response body size against the per-response cap.
+func (q *ByteQuota) AllowResponse(size int64) bool {
+	return q.limits.MaxResponseBytes <= 0 || size <= q.limits.MaxResponseBytes
+}
+
+// Record charges bytes against the key's monthly bandwidth.
+func (q *ByteQuota) Record(key string, bytes int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.state(key).used += bytes
+}
+
+// Used returns the key's bandwidth consumed this month.
+func (q *ByteQuota) Used(key string) int64 { return q.used(key) }
+
+// Remaining returns the key's monthly bandwidth left; -1 when unlimited.
+func (q *ByteQuota) Remaining(key string) int64 {
+	if q.limits.MonthlyBandwidth <= 0 {
+		return -1
+	}
+	left := q.limits.MonthlyBandwidth - q.used(key)
+	if left < 0 {
+		left = 0
+	}
+	return left
+}
+
+func (q *ByteQuota) used(key string) int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.state(key).used
+}
+
+// state returns the key's current-month state, resetting it when the
+// calendar month rolled over. Caller holds q.mu.
+func (q *ByteQuota) state(key string) *bandwidthState {
+	month := q.now().UTC().Format("2006-01")
+	st, ok := q.usage[key]
+	if !ok || st.month != month {
+		st = &bandwidthState{month: month}
+		q.usage[key] = st
+	}
+	return st
+}
+
+// ByteMiddleware enforces the byte quotas around a handler: oversized
+// requests are rejected with 413, responses over the cap are replaced
+// with 502 (the body is already produced, but it never reaches the
+// client), and both directions are charged against the key's monthly
+// bandwidth.
+func ByteMiddleware(q *ByteQuota, keyFunc KeyExtractor) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := keyFunc(c)
+		if key == "" {
+			return c.Next()
+		}
+		reqSize := int64(len(c.Body()))
+		if !q.AllowRequest(key, reqSize) {
+			if q.limits.MaxRequestBytes > 0 && reqSize > q.limits.MaxRequestBytes {
+				return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+					"error": fiber.Map{
+						"message": "request body exceeds size quota",
+						"type":    "request_too_large",
+					},
+				})
+			}
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": fiber.Map{
+					"message": "monthly bandwidth quota exhausted",
+					"type":    "bandwidth_quota_exceeded",
+				},
+			})
+		}
+
+		err := c.Next()
+
+		respSize := int64(len(c.Response().Body()))
+		q.Record(key, reqSize+respSize)
+		if !q.AllowResponse(respSize) {
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+				"error": fiber.Map{
+					"message": "response exceeds size quota",
+					"type":    "response_too_large",
+				},
+			})
+		}
+		return err
+	}
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestByteQuotaCapsRequestSize(t *testing.T) {
+	q := NewByteQuota(ByteLimits{MaxRequestBytes: 100})
+	if !q.AllowRequest("k1", 100) {
+		t.Fatal("request at the cap rejected")
+	}
+	if q.AllowRequest("k1", 101) {
+		t.Fatal("oversized request admitted")
+	}
+	// No monthly cap configured: usage does not block.
+	q.Record("k1", 1<<30)
+	if !q.AllowRequest("k1", 50) {
+		t.Fatal("request rejected without a bandwidth cap")
+	}
+}
+
+func TestByteQuotaTracksMonthlyBandwidth(t *testing.T) {
+	q := NewByteQuota(ByteLimits{MonthlyBandwidth: 1000})
+	q.now = func() time.Time { return time.Date(2026, 9, 15, 0, 0, 0, 0, time.UTC) }
+
+	q.Record("k1", 900)
+	if got := q.Remaining("k1"); got != 100 {
+		t.Fatalf("remaining = %d, want 100", got)
+	}
+	if q.AllowRequest("k1", 200) {
+		t.Fatal("request over remaining bandwidth admitted")
+	}
+	if !q.AllowRequest("k1", 100) {
+		t.Fatal("request within remaining bandwidth rejected")
+	}
+	// Other keys have their own budget.
+	if !q.AllowRequest("k2", 1000) {
+		t.Fatal("fresh key inherited another key's usage")
+	}
+}
+
+func TestByteQuotaResetsOnMonthRollover(t *testing.T) {
+	q := NewByteQuota(ByteLimits{MonthlyBandwidth: 1000})
+	now := time.Date(2026, 9, 30, 23, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+
+	q.Record("k1", 1000)
+	if q.AllowRequest("k1", 1) {
+		t.Fatal("exhausted quota admitted a request")
+	}
+	now = time.Date(2026, 10, 1, 0, 30, 0, 0, time.UTC)
+	if !q.AllowRequest("k1", 1) {
+		t.Fatal("quota did not reset on month rollover")
+	}
+	if got := q.Used("k1"); got != 0 {
+		t.Fatalf("used after rollover = %d", got)
+	}
+}
+
+func TestByteQuotaResponseCap(t *testing.T) {
+	q := NewByteQuota(ByteLimits{MaxResponseBytes: 10})
+	if !q.AllowResponse(10) || q.AllowResponse(11) {
+		t.Fatal("response cap not enforced at the boundary")
+	}
+	if !NewByteQuota(ByteLimits{}).AllowResponse(1 << 30) {
+		t.Fatal("unlimited quota rejected a response")
+	}
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+// Level names one tier in a hierarchical limit chain.
+type Level struct {
+	// Name identifies the tier ("global", "tenant", "user", "key").
+	Name string
+	// Limiter enforces this tier's limit.
+	Limiter Limiter
+	// KeyFunc maps the request key to this tier's limiting key, e.g. the
+	// global tier collapses everything to a single key. A nil KeyFunc
+	// uses the request key unchanged.
+	KeyFunc func(requestKey string) string
+}
+
+// HierarchyInfo extends Info with the tier that is currently the
+// tightest constraint for the key.
+type HierarchyInfo struct {
+	Info
+	// Bottleneck is the name of the level with the fewest remaining
+	// requests, i.e. the one a client will hit first.
+	Bottleneck string `json:"bottleneck"`
+}
+
+// Hierarchy chains multiple limits (global → tenant → user → key); a
+// request is admitted only when every level admits it. Levels are checked
+// outermost first and evaluation short-circuits on the first rejection, so
+// inner levels are not consumed for a request the outer level already
+// refused.
+type Hierarchy struct {
+	levels []Level
+}
+
+// NewHierarchy builds a chain from outermost to innermost level.
+func NewHierarchy(levels ...Level) *Hierarchy {
+	return &Hierarchy{levels: levels}
+}
+
+// Allow implements Limiter over the whole chain. On rejection at level i,
+// the units already consumed at levels 0..i-1 stay consumed — the same
+// trade-off single limiters make when an upstream later fails — which keeps
+// the hot path to one lock acquisition per level.
+func (h *Hierarchy) Allow(key string) bool {
+	for _, lv := range h.levels {
+		if !lv.Limiter.Allow(lv.key(key)) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetInfo implements Limiter; the returned snapshot is the one from the
+// bottleneck level so headers reflect what the client will actually hit.
+func (h *Hierarchy) GetInfo(key string) Info {
+	return h.GetHierarchyInfo(key).Info
+}
+
+// GetHierarchyInfo reports the bottleneck level along with its snapshot.
+func (h *Hierarchy) GetHierarchyInfo(key string) HierarchyInfo {
+	var out HierarchyInfo
+	for i, lv := range h.levels {
+		info := lv.Limiter.GetInfo(lv.key(key))
+		if i == 0 || info.Remaining < out.Remaining {
+			out.Info = info
+			out.Bottleneck = lv.Name
+		}
+	}
+	return out
+}
+
+func (lv Level) key(requestKey string) string {
+	if lv.KeyFunc == nil {
+		return requestKey
+	}
+	return lv.KeyFunc(requestKey)
+}
@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHierarchyShortCircuit(t *testing.T) {
+	global := NewSlidingWindow(2, time.Minute)
+	user := NewSlidingWindow(10, time.Minute)
+	h := NewHierarchy(
+		Level{Name: "global", Limiter: global, KeyFunc: func(string) string { return "global" }},
+		Level{Name: "user", Limiter: user},
+	)
+
+	if !h.Allow("u1") || !h.Allow("u2") {
+		t.Fatal("first two requests should pass")
+	}
+	if h.Allow("u3") {
+		t.Fatal("third request should be rejected by the global level")
+	}
+	// Rejection at the global level must not have consumed u3's user budget.
+	if got := user.GetInfo("u3").Remaining; got != 10 {
+		t.Fatalf("user level consumed on short-circuit: remaining = %d, want 10", got)
+	}
+}
+
+func TestHierarchyBottleneck(t *testing.T) {
+	h := NewHierarchy(
+		Level{Name: "global", Limiter: NewSlidingWindow(100, time.Minute), KeyFunc: func(string) string { return "global" }},
+		Level{Name: "user", Limiter: NewSlidingWindow(3, time.Minute)},
+	)
+	h.Allow("u1")
+	h.Allow("u1")
+
+	info := h.GetHierarchyInfo("u1")
+	if info.Bottleneck != "user" {
+		t.Fatalf("bottleneck = %q, want user", info.Bottleneck)
+	}
+	if info.Remaining != 1 {
+		t.Fatalf("remaining = %d, want 1", info.Remaining)
+	}
+}
+
+func BenchmarkSlidingWindowAllow(b *testing.B) {
+	s := NewSlidingWindow(1<<30, time.Minute)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !s.Allow("key") {
+			b.Fatal("limiter rejected under no contention")
+		}
+	}
+}
+
+func BenchmarkHierarchyAllow(b *testing.B) {
+	h := NewHierarchy(
+		Level{Name: "global", Limiter: NewSlidingWindow(1<<30, time.Minute), KeyFunc: func(string) string { return "global" }},
+		Level{Name: "user", Limiter: NewSlidingWindow(1<<30, time.Minute)},
+	)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !h.Allow("user-1") {
+			b.Fatal("limiter rejected under no contention")
+		}
+	}
+}
+
+func TestSlidingWindowCapKeys(t *testing.T) {
+	s := NewSlidingWindow(10, time.Minute)
+	s.CapKeys(2)
+	for _, key := range []string{"a", "b", "c"} {
+		s.Allow(key)
+	}
+	stats := s.KeyStats()
+	if stats.Entries != 2 || stats.Evictions != 1 {
+		t.Fatalf("key stats = %+v", stats)
+	}
+	// An evicted key that returns starts a fresh window rather than
+	// inheriting stale counts.
+	if !s.Allow("a") {
+		t.Fatal("returning key rejected")
+	}
+}
@@ -0,0 +1,150 @@
+// Package ratelimit implements the gateway's rate limiting primitives:
+// sliding-window and concurrency limiters plus the HTTP middleware that
+// applies them to incoming traffic.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/lru"
+)
+
+// Info is a point-in-time snapshot of a key's standing against a limit,
+// used to populate the X-RateLimit-* response headers.
+type Info struct {
+	Limit     int           `json:"limit"`
+	Remaining int           `json:"remaining"`
+	Reset     time.Time     `json:"reset"`
+	RetryIn   time.Duration `json:"retry_in"`
+}
+
+// Limiter is the interface every rate limiting algorithm implements.
+// Allow consumes one unit for the key and reports whether it was admitted;
+// GetInfo is read-only and never consumes.
+type Limiter interface {
+	Allow(key string) bool
+	GetInfo(key string) Info
+}
+
+// SlidingWindow is a sliding-window-counter limiter: the previous window's
+// count is weighted by its remaining overlap, which smooths the boundary
+// bursts a fixed window would allow.
+type SlidingWindow struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	counts *lru.Cache[string, *windowState]
+	now    func() time.Time
+	// LimitFor, when set, overrides the limit per key; the loan book
+	// plugs in here. Returning 0 falls back to the configured limit and
+	// a negative value exempts the key entirely.
+	LimitFor func(key string) int
+}
+
+type windowState struct {
+	start   time.Time
+	current int
+	prev    int
+}
+
+// NewSlidingWindow creates a limiter admitting at most limit events per
+// window for each key.
+func NewSlidingWindow(limit int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{
+		limit:  limit,
+		window: window,
+		counts: lru.New[string, *windowState](0),
+		now:    time.Now,
+	}
+}
+
+// CapKeys bounds how many keys the limiter tracks; the least recently
+// seen key's window is evicted when the cap is exceeded, so an evicted
+// key that returns starts a fresh window. Without a cap the per-key
+// state grows with every key ever seen.
+func (s *SlidingWindow) CapKeys(max int) {
+	s.counts.SetMax(max)
+}
+
+// KeyStats reports how many keys are tracked, for memory gauges.
+func (s *SlidingWindow) KeyStats() lru.Stats {
+	return s.counts.Stats()
+}
+
+// Allow implements Limiter.
+func (s *SlidingWindow) Allow(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	limit := s.limitFor(key)
+	st := s.advance(key)
+	if limit < 0 {
+		st.current++ // exempt: admitted but still counted
+		return true
+	}
+	if s.weighted(st) >= float64(limit) {
+		return false
+	}
+	st.current++
+	return true
+}
+
+// GetInfo implements Limiter.
+func (s *SlidingWindow) GetInfo(key string) Info {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	limit := s.limitFor(key)
+	st := s.advance(key)
+	reset := st.start.Add(s.window)
+	if limit < 0 {
+		return Info{Limit: -1, Remaining: -1, Reset: reset}
+	}
+	remaining := limit - int(s.weighted(st))
+	if remaining < 0 {
+		remaining = 0
+	}
+	info := Info{Limit: limit, Remaining: remaining, Reset: reset}
+	if remaining == 0 {
+		info.RetryIn = reset.Sub(s.now())
+	}
+	return info
+}
+
+// limitFor resolves the effective limit for a key. Caller holds s.mu.
+func (s *SlidingWindow) limitFor(key string) int {
+	if s.LimitFor != nil {
+		if l := s.LimitFor(key); l != 0 {
+			return l
+		}
+	}
+	return s.limit
+}
+
+// advance rolls the key's window forward to cover now. Caller holds s.mu.
+func (s *SlidingWindow) advance(key string) *windowState {
+	now := s.now()
+	st, ok := s.counts.Get(key)
+	if !ok {
+		st = &windowState{start: now}
+		s.counts.Put(key, st)
+		return st
+	}
+	elapsed := now.Sub(st.start)
+	switch {
+	case elapsed >= 2*s.window:
+		st.start, st.prev, st.current = now, 0, 0
+	case elapsed >= s.window:
+		st.start = st.start.Add(s.window)
+		st.prev, st.current = st.current, 0
+	}
+	return st
+}
+
+// weighted returns the sliding count for a state. Caller holds s.mu.
+func (s *SlidingWindow) weighted(st *windowState) float64 {
+	frac := 1 - float64(s.now().Sub(st.start))/float64(s.window)
+	if frac < 0 {
+		frac = 0
+	}
+	return float64(st.prev)*frac + float64(st.current)
+}
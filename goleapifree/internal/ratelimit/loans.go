@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Burst loans: a customer announcing a traffic spike gets extra capacity
+// up front and repays it afterwards through a reduced limit, so the
+// grant is neutral over time instead of a permanent quota bump. Full
+// exemptions (no limit at all until expiry) cover the rare cases where
+// payback makes no sense, like an incident backfill.
+
+// Loan is one temporary per-key limit adjustment.
+type Loan struct {
+	Key string `json:"key"`
+	// Extra units per window granted on top of the base limit; 0 for a
+	// full exemption.
+	Extra int `json:"extra,omitempty"`
+	// Exempt lifts the limit entirely until expiry, with no payback.
+	Exempt    bool      `json:"exempt,omitempty"`
+	GrantedAt time.Time `json:"granted_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// PaybackUntil closes the payback period: between ExpiresAt and this
+	// instant the key runs at base-Extra, repaying per window what the
+	// loan granted per window. Zero for exemptions.
+	PaybackUntil time.Time `json:"payback_until,omitempty"`
+}
+
+// ErrLoanActive is returned when a key already holds an unexpired loan.
+var ErrLoanActive = errors.New("ratelimit: key already has an active loan")
+
+// LoanBook manages the outstanding loans against one base limit and
+// plugs into SlidingWindow.LimitFor.
+type LoanBook struct {
+	base int
+
+	mu    sync.Mutex
+	loans map[string]*Loan
+	now   func() time.Time
+}
+
+// NewLoanBook creates a loan book for a limiter whose configured limit
+// is base.
+func NewLoanBook(base int) *LoanBook {
+	return &LoanBook{base: base, loans: make(map[string]*Loan), now: time.Now}
+}
+
+// Grant issues a burst loan: extra units per window for d, then the same
+// reduction for another d as automatic payback. An active loan must be
+// revoked (or run out) before a new one is granted.
+func (b *LoanBook) Grant(key string, extra int, d time.Duration) (*Loan, error) {
+	if extra <= 0 {
+		return nil, errors.New("ratelimit: loan must grant a positive extra")
+	}
+	if extra >= b.base {
+		return nil, errors.New("ratelimit: loan payback would zero the limit; grant less than the base limit")
+	}
+	return b.add(key, &Loan{Key: key, Extra: extra}, d)
+}
+
+// Exempt lifts the key's limit entirely for d, with no payback.
+func (b *LoanBook) Exempt(key string, d time.Duration) (*Loan, error) {
+	return b.add(key, &Loan{Key: key, Exempt: true}, d)
+}
+
+func (b *LoanBook) add(key string, loan *Loan, d time.Duration) (*Loan, error) {
+	if d <= 0 {
+		return nil, errors.New("ratelimit: loan duration must be positive")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if existing, ok := b.loans[key]; ok && b.outstanding(existing, now) {
+		return nil, ErrLoanActive
+	}
+	loan.GrantedAt = now
+	loan.ExpiresAt = now.Add(d)
+	if !loan.Exempt {
+		loan.PaybackUntil = loan.ExpiresAt.Add(d)
+	}
+	b.loans[key] = loan
+	out := *loan
+	return &out, nil
+}
+
+// Revoke cancels a key's loan immediately, payback included.
+func (b *LoanBook) Revoke(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.loans, key)
+}
+
+// Loans returns every outstanding loan, sorted by key; settled loans
+// are dropped on the way.
+func (b *LoanBook) Loans() []Loan {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	out := make([]Loan, 0, len(b.loans))
+	for key, loan := range b.loans {
+		if !b.outstanding(loan, now) {
+			delete(b.loans, key)
+			continue
+		}
+		out = append(out, *loan)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// LimitFor resolves the key's effective limit and is wired into
+// SlidingWindow.LimitFor: negative while exempt, raised during the
+// loan, reduced during payback, 0 ("use the base limit") otherwise.
+func (b *LoanBook) LimitFor(key string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	loan, ok := b.loans[key]
+	if !ok {
+		return 0
+	}
+	now := b.now()
+	switch {
+	case !b.outstanding(loan, now):
+		delete(b.loans, key)
+		return 0
+	case loan.Exempt:
+		return -1
+	case now.Before(loan.ExpiresAt):
+		return b.base + loan.Extra
+	default:
+		return b.base - loan.Extra
+	}
+}
+
+// outstanding reports whether a loan still affects the key at now.
+// Caller holds b.mu.
+func (b *LoanBook) outstanding(loan *Loan, now time.Time) bool {
+	end := loan.ExpiresAt
+	if !loan.PaybackUntil.IsZero() {
+		end = loan.PaybackUntil
+	}
+	return now.Before(end)
+}
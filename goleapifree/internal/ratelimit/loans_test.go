@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoanRaisesThenRepaysLimit(t *testing.T) {
+	book := NewLoanBook(10)
+	now := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	book.now = func() time.Time { return now }
+
+	if _, err := book.Grant("k1", 5, time.Hour); err != nil {
+		t.Fatalf("Grant: %v", err)
+	}
+	if got := book.LimitFor("k1"); got != 15 {
+		t.Fatalf("limit during loan = %d, want 15", got)
+	}
+	if got := book.LimitFor("other"); got != 0 {
+		t.Fatalf("unrelated key limit = %d, want 0 (base)", got)
+	}
+
+	// After expiry the key repays: base minus the borrowed extra.
+	now = now.Add(90 * time.Minute)
+	if got := book.LimitFor("k1"); got != 5 {
+		t.Fatalf("limit during payback = %d, want 5", got)
+	}
+
+	// Payback runs as long as the loan did, then everything settles.
+	now = now.Add(time.Hour)
+	if got := book.LimitFor("k1"); got != 0 {
+		t.Fatalf("limit after payback = %d, want 0 (base)", got)
+	}
+	if loans := book.Loans(); len(loans) != 0 {
+		t.Fatalf("settled loan still listed: %+v", loans)
+	}
+}
+
+func TestExemptionLiftsLimitWithoutPayback(t *testing.T) {
+	book := NewLoanBook(10)
+	now := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	book.now = func() time.Time { return now }
+
+	loan, err := book.Exempt("k1", time.Hour)
+	if err != nil {
+		t.Fatalf("Exempt: %v", err)
+	}
+	if !loan.PaybackUntil.IsZero() {
+		t.Fatalf("exemption has payback: %+v", loan)
+	}
+	if got := book.LimitFor("k1"); got != -1 {
+		t.Fatalf("limit while exempt = %d, want -1", got)
+	}
+	now = now.Add(2 * time.Hour)
+	if got := book.LimitFor("k1"); got != 0 {
+		t.Fatalf("limit after exemption = %d, want 0 (base)", got)
+	}
+}
+
+func TestLoanGuards(t *testing.T) {
+	book := NewLoanBook(10)
+	if _, err := book.Grant("k1", 0, time.Hour); err == nil {
+		t.Fatal("zero-extra loan granted")
+	}
+	if _, err := book.Grant("k1", 10, time.Hour); err == nil {
+		t.Fatal("loan that zeroes the payback limit granted")
+	}
+	if _, err := book.Grant("k1", 5, 0); err == nil {
+		t.Fatal("zero-duration loan granted")
+	}
+
+	if _, err := book.Grant("k1", 5, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := book.Grant("k1", 2, time.Hour); !errors.Is(err, ErrLoanActive) {
+		t.Fatalf("err = %v, want ErrLoanActive", err)
+	}
+	book.Revoke("k1")
+	if _, err := book.Grant("k1", 2, time.Hour); err != nil {
+		t.Fatalf("grant after revoke: %v", err)
+	}
+}
+
+func TestSlidingWindowHonorsLoanLimits(t *testing.T) {
+	sw := NewSlidingWindow(2, time.Minute)
+	now := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	sw.now = func() time.Time { return now }
+
+	book := NewLoanBook(2)
+	book.now = sw.now
+	sw.LimitFor = book.LimitFor
+
+	if _, err := book.Grant("k1", 1, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	admitted := 0
+	for i := 0; i < 5; i++ {
+		if sw.Allow("k1") {
+			admitted++
+		}
+	}
+	if admitted != 3 {
+		t.Fatalf("admitted %d with loan, want 3", admitted)
+	}
+	if info := sw.GetInfo("k1"); info.Limit != 3 {
+		t.Fatalf("info limit = %d, want 3", info.Limit)
+	}
+
+	// Exempt keys are admitted without bound.
+	if _, err := book.Exempt("vip", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if !sw.Allow("vip") {
+			t.Fatal("exempt key throttled")
+		}
+	}
+	if info := sw.GetInfo("vip"); info.Limit != -1 || info.Remaining != -1 {
+		t.Fatalf("exempt info = %+v", info)
+	}
+}
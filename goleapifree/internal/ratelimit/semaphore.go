@@ -0,0 +1,76 @@
+package ratelimit
+
+import "sync"
+
+// ConcurrencyLimiter tracks in-flight requests per provider account and
+// enforces the MaxConcurrent value from the account's RateLimit. Providers
+// that throttle on concurrency rather than request rate (e.g. some free
+// tiers) need this in addition to the rate-based limiters.
+type ConcurrencyLimiter struct {
+	mu     sync.Mutex
+	limits map[string]int // accountID -> max in-flight, 0 = unlimited
+	flight map[string]int // accountID -> current in-flight
+}
+
+// NewConcurrencyLimiter returns an empty limiter; limits are registered
+// via SetLimit as accounts are loaded or updated.
+func NewConcurrencyLimiter() *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		limits: make(map[string]int),
+		flight: make(map[string]int),
+	}
+}
+
+// SetLimit configures the maximum in-flight requests for an account.
+// A max of 0 removes the cap.
+func (c *ConcurrencyLimiter) SetLimit(accountID string, max int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if max <= 0 {
+		delete(c.limits, accountID)
+		return
+	}
+	c.limits[accountID] = max
+}
+
+// TryAcquire reserves one in-flight slot for the account. It returns false
+// without blocking when the account is at its concurrency cap; callers must
+// call Release exactly once for every successful acquire.
+func (c *ConcurrencyLimiter) TryAcquire(accountID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if max, ok := c.limits[accountID]; ok && c.flight[accountID] >= max {
+		return false
+	}
+	c.flight[accountID]++
+	return true
+}
+
+// Release returns a previously acquired slot.
+func (c *ConcurrencyLimiter) Release(accountID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.flight[accountID] > 0 {
+		c.flight[accountID]--
+	}
+	if c.flight[accountID] == 0 {
+		delete(c.flight, accountID)
+	}
+}
+
+// Available reports whether the account has at least one free slot. It is a
+// read-only check for routing decisions; the router still has to TryAcquire
+// before dispatching because availability can change in between.
+func (c *ConcurrencyLimiter) Available(accountID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	max, ok := c.limits[accountID]
+	return !ok || c.flight[accountID] < max
+}
+
+// InFlight returns the current number of in-flight requests for an account.
+func (c *ConcurrencyLimiter) InFlight(accountID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.flight[accountID]
+}
@@ -0,0 +1,39 @@
+package ratelimit
+
+import "testing"
+
+func TestConcurrencyLimiterCap(t *testing.T) {
+	c := NewConcurrencyLimiter()
+	c.SetLimit("acc1", 2)
+
+	if !c.TryAcquire("acc1") || !c.TryAcquire("acc1") {
+		t.Fatal("expected two acquires to succeed")
+	}
+	if c.TryAcquire("acc1") {
+		t.Fatal("expected third acquire to fail at cap 2")
+	}
+	if c.Available("acc1") {
+		t.Fatal("account at cap should not be available")
+	}
+
+	c.Release("acc1")
+	if !c.Available("acc1") {
+		t.Fatal("account should be available after release")
+	}
+	if !c.TryAcquire("acc1") {
+		t.Fatal("acquire should succeed after release")
+	}
+}
+
+func TestConcurrencyLimiterUnlimited(t *testing.T) {
+	c := NewConcurrencyLimiter()
+	for i := 0; i < 100; i++ {
+		if !c.TryAcquire("free") {
+			t.Fatalf("acquire %d failed on unlimited account", i)
+		}
+	}
+	c.SetLimit("free", 0) // explicit zero removes any cap
+	if !c.Available("free") {
+		t.Fatal("unlimited account should always be available")
+	}
+}
@@ -0,0 +1,188 @@
+// Package reasoning normalizes the knobs of reasoning models across
+// provider families. Clients speak either dialect — an o-series effort
+// level or an Anthropic thinking budget — and the gateway translates to
+// whatever the serving provider understands, applies the configured
+// trace policy to chain-of-thought output, and keeps reasoning tokens
+// distinguishable for cost tracking.
+package reasoning
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Effort levels in the o-series vocabulary.
+const (
+	EffortLow    = "low"
+	EffortMedium = "medium"
+	EffortHigh   = "high"
+)
+
+// effortBudgets maps effort levels to thinking budgets (tokens) for
+// providers that take a budget instead of a level.
+var effortBudgets = map[string]int{
+	EffortLow:    2048,
+	EffortMedium: 8192,
+	EffortHigh:   32768,
+}
+
+// Trace policies for chain-of-thought output.
+const (
+	// TraceStrip removes reasoning traces before the response reaches
+	// the client; the default, since traces can leak system prompts.
+	TraceStrip = "strip"
+	// TracePass forwards traces untouched, for tenants that consume
+	// them deliberately.
+	TracePass = "pass"
+)
+
+// Params are the normalized reasoning knobs of one request. The zero
+// value means the request did not ask for reasoning.
+type Params struct {
+	// Effort is an o-series effort level; empty when the client sent a
+	// budget instead.
+	Effort string `json:"effort,omitempty"`
+	// BudgetTokens is an Anthropic-style thinking budget; 0 when the
+	// client sent an effort level instead.
+	BudgetTokens int `json:"budget_tokens,omitempty"`
+}
+
+// Active reports whether the request asked for reasoning at all.
+func (p Params) Active() bool { return p.Effort != "" || p.BudgetTokens > 0 }
+
+// Budget resolves the thinking budget, deriving it from the effort
+// level when the client did not send one explicitly.
+func (p Params) Budget() int {
+	if p.BudgetTokens > 0 {
+		return p.BudgetTokens
+	}
+	return effortBudgets[p.Effort]
+}
+
+// EffortLevel resolves the effort level, bucketing an explicit budget
+// into the nearest level when the client did not send one.
+func (p Params) EffortLevel() string {
+	if p.Effort != "" {
+		return p.Effort
+	}
+	switch {
+	case p.BudgetTokens <= 0:
+		return ""
+	case p.BudgetTokens <= effortBudgets[EffortLow]:
+		return EffortLow
+	case p.BudgetTokens <= effortBudgets[EffortMedium]:
+		return EffortMedium
+	default:
+		return EffortHigh
+	}
+}
+
+// ForProvider maps the params to the wire parameters of a provider
+// family; nil for families without reasoning controls, so callers can
+// merge the result into the outgoing body unconditionally.
+func (p Params) ForProvider(providerType string) map[string]any {
+	if !p.Active() {
+		return nil
+	}
+	switch providerType {
+	case "openai", "openrouter", "groq":
+		return map[string]any{"reasoning_effort": p.EffortLevel()}
+	case "anthropic", "bedrock":
+		return map[string]any{"thinking": map[string]any{
+			"type":          "enabled",
+			"budget_tokens": p.Budget(),
+		}}
+	default:
+		return nil
+	}
+}
+
+// ParseRequest extracts reasoning params from a raw request body,
+// accepting both dialects: a top-level reasoning_effort string and a
+// thinking object with budget_tokens.
+func ParseRequest(body []byte) Params {
+	var req struct {
+		ReasoningEffort string `json:"reasoning_effort"`
+		Thinking        struct {
+			BudgetTokens int `json:"budget_tokens"`
+		} `json:"thinking"`
+	}
+	_ = json.Unmarshal(body, &req)
+	p := Params{BudgetTokens: req.Thinking.BudgetTokens}
+	switch req.ReasoningEffort {
+	case EffortLow, EffortMedium, EffortHigh:
+		p.Effort = req.ReasoningEffort
+	}
+	return p
+}
+
+// SplitTraces separates inline reasoning traces — <think> or <thinking>
+// blocks, as emitted by DeepSeek R1 style models — from the visible
+// content. Unclosed blocks count as trace to the end, so a truncated
+// response never leaks a partial trace.
+func SplitTraces(content string) (visible, trace string) {
+	var vis, tr strings.Builder
+	for {
+		start, tag := nextTraceTag(content)
+		if start < 0 {
+			vis.WriteString(content)
+			break
+		}
+		vis.WriteString(content[:start])
+		rest := content[start+len(tag):]
+		closing := "</" + tag[1:]
+		end := strings.Index(rest, closing)
+		if end < 0 {
+			tr.WriteString(rest)
+			break
+		}
+		tr.WriteString(rest[:end])
+		content = rest[end+len(closing):]
+	}
+	return strings.TrimSpace(vis.String()), strings.TrimSpace(tr.String())
+}
+
+// nextTraceTag finds the earliest trace opening tag in content.
+func nextTraceTag(content string) (int, string) {
+	idx, tag := -1, ""
+	for _, candidate := range []string{"<think>", "<thinking>"} {
+		if i := strings.Index(content, candidate); i >= 0 && (idx < 0 || i < idx) {
+			idx, tag = i, candidate
+		}
+	}
+	return idx, tag
+}
+
+// ApplyTracePolicy returns the content a client may see under the given
+// policy. Unknown policies strip, erring toward not leaking.
+func ApplyTracePolicy(policy, content string) string {
+	if policy == TracePass {
+		return content
+	}
+	visible, _ := SplitTraces(content)
+	return visible
+}
+
+// Capable reports whether a model name belongs to a known reasoning
+// family, for providers that do not declare the capability explicitly
+// on their configuration.
+func Capable(model string) bool {
+	m := strings.ToLower(model)
+	for _, prefix := range []string{"o1", "o3", "o4"} {
+		if m == prefix || strings.HasPrefix(m, prefix+"-") {
+			return true
+		}
+	}
+	return strings.Contains(m, "-r1") || strings.HasPrefix(m, "r1-") ||
+		strings.Contains(m, "thinking") || strings.Contains(m, "reasoner")
+}
+
+// Exclude builds a router exclusion hook for reasoning requests:
+// providers whose configuration does not mark them reasoning-capable
+// are dropped from the candidate set. Wire it only when the request's
+// params are Active.
+func Exclude(capable func(providerID string) bool) func(providerID string) bool {
+	return func(providerID string) bool {
+		return !capable(providerID)
+	}
+}
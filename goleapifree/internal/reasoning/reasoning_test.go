@@ -0,0 +1,117 @@
+package reasoning
+
+import "testing"
+
+func TestParamsTranslateBetweenDialects(t *testing.T) {
+	p := Params{Effort: EffortMedium}
+	if p.Budget() != 8192 {
+		t.Fatalf("budget = %d", p.Budget())
+	}
+	p = Params{BudgetTokens: 3000}
+	if p.EffortLevel() != EffortMedium {
+		t.Fatalf("effort = %q", p.EffortLevel())
+	}
+	if (Params{BudgetTokens: 2048}).EffortLevel() != EffortLow {
+		t.Fatal("2048 tokens should bucket as low")
+	}
+	if (Params{BudgetTokens: 100000}).EffortLevel() != EffortHigh {
+		t.Fatal("large budget should bucket as high")
+	}
+}
+
+func TestForProvider(t *testing.T) {
+	p := Params{Effort: EffortHigh}
+	if got := p.ForProvider("openai"); got["reasoning_effort"] != EffortHigh {
+		t.Fatalf("openai params = %v", got)
+	}
+	anthropic := p.ForProvider("anthropic")
+	thinking, ok := anthropic["thinking"].(map[string]any)
+	if !ok || thinking["budget_tokens"] != 32768 {
+		t.Fatalf("anthropic params = %v", anthropic)
+	}
+	if p.ForProvider("ollama") != nil {
+		t.Fatal("unknown family should map to nothing")
+	}
+	if (Params{}).ForProvider("openai") != nil {
+		t.Fatal("inactive params should map to nothing")
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	p := ParseRequest([]byte(`{"model":"o3","reasoning_effort":"high"}`))
+	if p.Effort != EffortHigh || !p.Active() {
+		t.Fatalf("params = %+v", p)
+	}
+	p = ParseRequest([]byte(`{"model":"claude","thinking":{"type":"enabled","budget_tokens":5000}}`))
+	if p.BudgetTokens != 5000 {
+		t.Fatalf("params = %+v", p)
+	}
+	if ParseRequest([]byte(`{"model":"gpt-4o","reasoning_effort":"max"}`)).Active() {
+		t.Fatal("unknown effort level accepted")
+	}
+	if ParseRequest([]byte(`{"model":"gpt-4o"}`)).Active() {
+		t.Fatal("plain request counted as reasoning")
+	}
+}
+
+func TestSplitTraces(t *testing.T) {
+	visible, trace := SplitTraces("<think>step by step</think>The answer is 4.")
+	if visible != "The answer is 4." || trace != "step by step" {
+		t.Fatalf("visible = %q, trace = %q", visible, trace)
+	}
+
+	visible, trace = SplitTraces("a<thinking>one</thinking>b<think>two</think>c")
+	if visible != "abc" || trace != "onetwo" {
+		t.Fatalf("visible = %q, trace = %q", visible, trace)
+	}
+
+	// A truncated response must not leak a partial trace.
+	visible, trace = SplitTraces("done.<think>secret so far")
+	if visible != "done." || trace != "secret so far" {
+		t.Fatalf("visible = %q, trace = %q", visible, trace)
+	}
+
+	if v, tr := SplitTraces("no traces here"); v != "no traces here" || tr != "" {
+		t.Fatalf("visible = %q, trace = %q", v, tr)
+	}
+}
+
+func TestApplyTracePolicy(t *testing.T) {
+	content := "<think>hmm</think>ok"
+	if got := ApplyTracePolicy(TracePass, content); got != content {
+		t.Fatalf("pass = %q", got)
+	}
+	if got := ApplyTracePolicy(TraceStrip, content); got != "ok" {
+		t.Fatalf("strip = %q", got)
+	}
+	if got := ApplyTracePolicy("bogus", content); got != "ok" {
+		t.Fatalf("unknown policy = %q", got)
+	}
+}
+
+func TestCapable(t *testing.T) {
+	for _, tc := range []struct {
+		model string
+		want  bool
+	}{
+		{"o3-mini", true},
+		{"o1", true},
+		{"o4-mini-high", true},
+		{"deepseek-r1", true},
+		{"qwen-qwq-reasoner", true},
+		{"claude-3-7-sonnet-thinking", true},
+		{"gpt-4o", false},
+		{"openchat", false}, // "o" prefixes must not match loosely
+	} {
+		if got := Capable(tc.model); got != tc.want {
+			t.Errorf("Capable(%q) = %v, want %v", tc.model, got, tc.want)
+		}
+	}
+}
+
+func TestExcludeHook(t *testing.T) {
+	exclude := Exclude(func(id string) bool { return id == "openai" })
+	if exclude("openai") || !exclude("ollama") {
+		t.Fatal("exclusion hook wrong")
+	}
+}
@@ -0,0 +1,115 @@
+// Package reconcile runs at startup to detect configuration drift left by
+// older versions or interrupted writes: orphaned accounts, alias targets
+// pointing at deleted providers, and zeroed enum fields. It reports what
+// it found and, when Fix is set, repairs it before the router loads.
+package reconcile
+
+import (
+	"fmt"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+// Change records one detected (and possibly repaired) inconsistency.
+type Change struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Detail  string `json:"detail"`
+	Fixed   bool   `json:"fixed"`
+}
+
+// Reconciler inspects the loaded configuration in place.
+type Reconciler struct {
+	// Fix applies repairs; when false the reconciler only reports.
+	Fix bool
+}
+
+// Result is the boot report.
+type Result struct {
+	Changes []Change `json:"changes"`
+	// Accounts and Aliases are the (possibly filtered) survivors the
+	// caller should load into the router.
+	Accounts []*models.Account
+	Aliases  []*models.ModelAlias
+}
+
+// Run checks accounts and aliases against the provider set. Providers
+// themselves are repaired in place (defaulted enum fields); orphaned
+// accounts and dangling alias targets are dropped from the returned
+// slices when Fix is set.
+func (r *Reconciler) Run(providers []*models.Provider, accounts []*models.Account, aliases []*models.ModelAlias) Result {
+	res := Result{}
+	known := make(map[string]bool, len(providers))
+	for _, p := range providers {
+		known[p.ID] = true
+		// Type was nullable before it became an enum; default blanks so
+		// the adapter factory never sees an empty type.
+		if p.Type == "" {
+			res.record(&Change{
+				Kind:    "provider_missing_type",
+				Subject: p.ID,
+				Detail:  `type was empty, defaulted to "openai"`,
+			}, r.Fix)
+			if r.Fix {
+				p.Type = "openai"
+			}
+		}
+	}
+
+	for _, a := range accounts {
+		if !known[a.ProviderID] {
+			res.record(&Change{
+				Kind:    "orphaned_account",
+				Subject: a.ID,
+				Detail:  fmt.Sprintf("references missing provider %s", a.ProviderID),
+			}, r.Fix)
+			if r.Fix {
+				continue // drop it
+			}
+		}
+		if a.RateLimit != nil && (a.RateLimit.RequestsPerMinute < 0 || a.RateLimit.MaxConcurrent < 0) {
+			res.record(&Change{
+				Kind:    "invalid_rate_limit",
+				Subject: a.ID,
+				Detail:  "negative limit values cleared",
+			}, r.Fix)
+			if r.Fix {
+				a.RateLimit = nil
+			}
+		}
+		res.Accounts = append(res.Accounts, a)
+	}
+
+	for _, al := range aliases {
+		var kept []models.AliasTarget
+		for _, t := range al.Targets {
+			if !known[t.ProviderID] {
+				res.record(&Change{
+					Kind:    "dangling_alias_target",
+					Subject: al.Alias,
+					Detail:  fmt.Sprintf("target references missing provider %s", t.ProviderID),
+				}, r.Fix)
+				if r.Fix {
+					continue
+				}
+			}
+			kept = append(kept, t)
+		}
+		if r.Fix && len(kept) == 0 {
+			res.record(&Change{
+				Kind:    "empty_alias",
+				Subject: al.Alias,
+				Detail:  "no valid targets remain, alias dropped",
+			}, true)
+			continue
+		}
+		al.Targets = kept
+		res.Aliases = append(res.Aliases, al)
+	}
+	return res
+}
+
+func (res *Result) record(c *Change, fixed bool) {
+	c.Fixed = fixed
+	res.Changes = append(res.Changes, *c)
+}
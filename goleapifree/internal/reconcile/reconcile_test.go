@@ -0,0 +1,66 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+func fixtures() ([]*models.Provider, []*models.Account, []*models.ModelAlias) {
+	providers := []*models.Provider{
+		{ID: "p1", Type: "openai"},
+		{ID: "p2"}, // Type drifted to empty
+	}
+	accounts := []*models.Account{
+		{ID: "a1", ProviderID: "p1"},
+		{ID: "a2", ProviderID: "deleted"},
+		{ID: "a3", ProviderID: "p1", RateLimit: &models.RateLimit{RequestsPerMinute: -5}},
+	}
+	aliases := []*models.ModelAlias{
+		{Alias: "fast", Targets: []models.AliasTarget{
+			{ProviderID: "p1", Model: "gpt-4o-mini"},
+			{ProviderID: "deleted", Model: "ghost"},
+		}},
+		{Alias: "dead", Targets: []models.AliasTarget{{ProviderID: "deleted", Model: "x"}}},
+	}
+	return providers, accounts, aliases
+}
+
+func TestReportOnlyKeepsEverything(t *testing.T) {
+	providers, accounts, aliases := fixtures()
+	res := (&Reconciler{}).Run(providers, accounts, aliases)
+	if len(res.Changes) != 5 {
+		t.Fatalf("changes = %d, want 5: %+v", len(res.Changes), res.Changes)
+	}
+	for _, c := range res.Changes {
+		if c.Fixed {
+			t.Fatalf("report-only run marked change fixed: %+v", c)
+		}
+	}
+	if len(res.Accounts) != 3 || len(res.Aliases) != 2 {
+		t.Fatalf("report-only run dropped records: %d accounts, %d aliases", len(res.Accounts), len(res.Aliases))
+	}
+	if providers[1].Type != "" {
+		t.Fatal("report-only run mutated provider type")
+	}
+}
+
+func TestFixRepairsAndDrops(t *testing.T) {
+	providers, accounts, aliases := fixtures()
+	res := (&Reconciler{Fix: true}).Run(providers, accounts, aliases)
+
+	if providers[1].Type != "openai" {
+		t.Fatalf("provider type = %q, want defaulted", providers[1].Type)
+	}
+	if len(res.Accounts) != 2 {
+		t.Fatalf("accounts = %d, want orphan dropped", len(res.Accounts))
+	}
+	for _, a := range res.Accounts {
+		if a.ID == "a3" && a.RateLimit != nil {
+			t.Fatal("invalid rate limit not cleared")
+		}
+	}
+	if len(res.Aliases) != 1 || len(res.Aliases[0].Targets) != 1 {
+		t.Fatalf("aliases = %+v, want only the surviving target of \"fast\"", res.Aliases)
+	}
+}
@@ -0,0 +1,210 @@
+// Package recorder captures sanitized upstream request/response pairs
+// into per-provider fixture files. Turned on in record mode, it sits in
+// the provider transport chain like the egress guard does, so adapters
+// need no changes; the resulting fixtures feed the mock provider and
+// the adapter contract tests with realistic wire payloads.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/guardrails"
+)
+
+// defaultLimit caps fixtures per provider per process; record mode is
+// for harvesting samples, not archiving all traffic.
+const defaultLimit = 20
+
+// Fixture is one recorded exchange as written to disk. Bodies are the
+// raw wire payloads after secret redaction; streamed responses keep
+// their SSE/event-stream framing so contract tests replay them as-is.
+type Fixture struct {
+	Provider   string    `json:"provider"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Status     int       `json:"status"`
+	Stream     bool      `json:"stream"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Request    string    `json:"request"`
+	Response   string    `json:"response"`
+}
+
+// Recorder writes fixtures under Dir, one subdirectory per provider.
+type Recorder struct {
+	Dir string
+	// Limit caps fixtures per provider; 0 applies defaultLimit.
+	Limit int
+
+	now     func() time.Time
+	scanner *guardrails.SecretScanner
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// New creates a recorder writing under dir.
+func New(dir string) *Recorder {
+	return &Recorder{
+		Dir:     dir,
+		now:     time.Now,
+		scanner: guardrails.NewSecretScanner(guardrails.SecretStrip),
+		counts:  make(map[string]int),
+	}
+}
+
+// Wrap returns a RoundTripper that records exchanges for one provider.
+// A nil base uses http.DefaultTransport. Recording failures never fail
+// the request; fixtures are best-effort.
+func (r *Recorder) Wrap(provider string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &recordingTransport{rec: r, provider: provider, next: base}
+}
+
+type recordingTransport struct {
+	rec      *Recorder
+	provider string
+	next     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper. The request body is read up
+// front and restored; the response body is captured through a tee so
+// streaming consumers see chunks at the usual pace and the fixture is
+// written once the body is drained or closed.
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if !t.rec.reserve(t.provider) {
+		return resp, nil
+	}
+	fixture := Fixture{
+		Provider: t.provider,
+		Method:   req.Method,
+		// Query strings can carry API keys (some providers authenticate
+		// that way), so only the path is recorded.
+		Path:       req.URL.Path,
+		Status:     resp.StatusCode,
+		Stream:     strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"),
+		RecordedAt: t.rec.now(),
+		Request:    string(reqBody),
+	}
+	resp.Body = &captureBody{inner: resp.Body, rec: t.rec, fixture: fixture}
+	return resp, nil
+}
+
+// captureBody tees the response body and writes the fixture exactly
+// once, when the stream ends or the caller closes it.
+type captureBody struct {
+	inner   io.ReadCloser
+	rec     *Recorder
+	fixture Fixture
+	buf     bytes.Buffer
+	done    bool
+}
+
+func (b *captureBody) Read(p []byte) (int, error) {
+	n, err := b.inner.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *captureBody) Close() error {
+	b.finish()
+	return b.inner.Close()
+}
+
+func (b *captureBody) finish() {
+	if b.done {
+		return
+	}
+	b.done = true
+	b.fixture.Response = b.buf.String()
+	b.rec.write(b.fixture)
+}
+
+// reserve takes one fixture slot for the provider, enforcing the
+// per-provider cap.
+func (r *Recorder) reserve(provider string) bool {
+	limit := r.Limit
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counts[provider] >= limit {
+		return false
+	}
+	r.counts[provider]++
+	return true
+}
+
+// write sanitizes and persists one fixture. Errors are swallowed by
+// design: a full disk must not take down record mode's live traffic.
+func (r *Recorder) write(f Fixture) {
+	f.Request, _, _ = r.scanner.Apply(f.Request)
+	f.Response, _, _ = r.scanner.Apply(f.Response)
+
+	dir := filepath.Join(r.Dir, f.Provider)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return
+	}
+	name := fmt.Sprintf("%s-%03d.json", f.RecordedAt.UTC().Format("20060102T150405"), r.nextSeq(f.Provider))
+	_ = os.WriteFile(filepath.Join(dir, name), data, 0o644)
+}
+
+// nextSeq disambiguates fixtures recorded within the same second.
+func (r *Recorder) nextSeq(provider string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counts[provider]
+}
+
+// Load reads every fixture recorded for a provider, oldest first; the
+// mock provider and contract tests consume it.
+func Load(dir, provider string) ([]Fixture, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, provider))
+	if err != nil {
+		return nil, err
+	}
+	var out []Fixture
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, provider, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var f Fixture
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("recorder: %s: %w", e.Name(), err)
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
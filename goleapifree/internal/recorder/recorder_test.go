@@ -0,0 +1,112 @@
+package recorder
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecordChatExchange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"content":"hi"}}]}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	rec := New(dir)
+	client := &http.Client{Transport: rec.Wrap("openai", nil)}
+
+	body := `{"model":"gpt-4o","messages":[{"role":"user","content":"key is sk-aaaabbbbccccddddeeee12345"}]}`
+	resp, err := client.Post(srv.URL+"/v1/chat/completions?key=secret", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(out), "hi") {
+		t.Fatalf("response body altered: %q", out)
+	}
+
+	fixtures, err := Load(dir, "openai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) != 1 {
+		t.Fatalf("fixtures = %d", len(fixtures))
+	}
+	f := fixtures[0]
+	if f.Method != "POST" || f.Path != "/v1/chat/completions" || f.Status != 200 || f.Stream {
+		t.Fatalf("fixture = %+v", f)
+	}
+	if strings.Contains(f.Path, "key=") {
+		t.Fatal("query string recorded")
+	}
+	if strings.Contains(f.Request, "sk-aaaabbbbccccddddeeee12345") {
+		t.Fatal("secret not redacted")
+	}
+	if !strings.Contains(f.Request, "[REDACTED:openai-key]") {
+		t.Fatalf("request = %q", f.Request)
+	}
+	if !strings.Contains(f.Response, "hi") {
+		t.Fatalf("response = %q", f.Response)
+	}
+}
+
+func TestRecordStreamKeepsFraming(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"delta\":\"a\"}\n\ndata: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	rec := New(dir)
+	client := &http.Client{Transport: rec.Wrap("groq", nil)}
+	resp, err := client.Get(srv.URL + "/v1/chat/completions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	fixtures, err := Load(dir, "groq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) != 1 || !fixtures[0].Stream {
+		t.Fatalf("fixtures = %+v", fixtures)
+	}
+	if !strings.Contains(fixtures[0].Response, "data: [DONE]") {
+		t.Fatalf("framing lost: %q", fixtures[0].Response)
+	}
+}
+
+func TestPerProviderLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	rec := New(dir)
+	rec.Limit = 2
+	client := &http.Client{Transport: rec.Wrap("openai", nil)}
+	for i := 0; i < 5; i++ {
+		resp, err := client.Get(srv.URL + "/v1/models")
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	fixtures, err := Load(dir, "openai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) != 2 {
+		t.Fatalf("fixtures = %d, want capped at 2", len(fixtures))
+	}
+}
@@ -0,0 +1,183 @@
+// Package reliability maintains a decayed historical reliability score per
+// provider, combining uptime, error mix and recovery time. Unlike the
+// point-in-time health check, the score remembers how a provider behaved
+// over days and fades old incidents exponentially.
+package reliability
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HalfLife controls how fast history fades: an outcome observed one
+// half-life ago carries half the weight of one observed now.
+const defaultHalfLife = 12 * time.Hour
+
+// state is the persisted per-provider accumulator. Weights are decayed
+// lazily on each observation, so idle providers cost nothing.
+type state struct {
+	SuccessWeight float64 `json:"success_weight"`
+	FailureWeight float64 `json:"failure_weight"`
+	// ErrorWeights tracks the decayed mix of error kinds (timeout,
+	// http_5xx, garbage, ...) for the dashboard.
+	ErrorWeights map[string]float64 `json:"error_weights,omitempty"`
+	// DownSince is set while the provider is in a failing streak, and
+	// feeds MTTRSeconds when it recovers.
+	DownSince   *time.Time `json:"down_since,omitempty"`
+	MTTRSeconds float64    `json:"mttr_seconds"`
+	Recoveries  int        `json:"recoveries"`
+	LastUpdated time.Time  `json:"last_updated"`
+}
+
+// Scorer accumulates outcomes and produces scores in [0,1].
+type Scorer struct {
+	halfLife time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	providers map[string]*state
+}
+
+// NewScorer returns a scorer with the default half-life.
+func NewScorer() *Scorer {
+	return &Scorer{
+		halfLife:  defaultHalfLife,
+		now:       time.Now,
+		providers: make(map[string]*state),
+	}
+}
+
+// RecordSuccess feeds one successful request for the provider.
+func (s *Scorer) RecordSuccess(providerID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.decayed(providerID)
+	st.SuccessWeight++
+	if st.DownSince != nil {
+		st.MTTRSeconds += s.now().Sub(*st.DownSince).Seconds()
+		st.Recoveries++
+		st.DownSince = nil
+	}
+}
+
+// RecordFailure feeds one failed request, tagged with an error kind
+// such as "timeout", "http_5xx" or "garbage".
+func (s *Scorer) RecordFailure(providerID, kind string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.decayed(providerID)
+	st.FailureWeight++
+	if st.ErrorWeights == nil {
+		st.ErrorWeights = make(map[string]float64)
+	}
+	st.ErrorWeights[kind]++
+	if st.DownSince == nil {
+		t := s.now()
+		st.DownSince = &t
+	}
+}
+
+// decayed returns the provider's state with decay applied up to now.
+// Callers must hold s.mu.
+func (s *Scorer) decayed(providerID string) *state {
+	st, ok := s.providers[providerID]
+	if !ok {
+		st = &state{LastUpdated: s.now()}
+		s.providers[providerID] = st
+		return st
+	}
+	elapsed := s.now().Sub(st.LastUpdated)
+	if elapsed > 0 {
+		factor := math.Exp2(-elapsed.Hours() / s.halfLife.Hours())
+		st.SuccessWeight *= factor
+		st.FailureWeight *= factor
+		for k := range st.ErrorWeights {
+			st.ErrorWeights[k] *= factor
+		}
+	}
+	st.LastUpdated = s.now()
+	return st
+}
+
+// Score is the routing prior for a provider: decayed success rate,
+// discounted while the provider is mid-incident and by slow recoveries.
+// Unknown providers score 0.5 so new providers are neither favored nor
+// starved.
+func (s *Scorer) Score(providerID string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.providers[providerID]
+	if !ok {
+		return 0.5
+	}
+	total := st.SuccessWeight + st.FailureWeight
+	if total == 0 {
+		return 0.5
+	}
+	score := st.SuccessWeight / total
+	if st.DownSince != nil {
+		score *= 0.5
+	}
+	// Penalize providers that historically take long to recover: an
+	// average recovery above 5 minutes halves the score at the limit.
+	if st.Recoveries > 0 {
+		avg := st.MTTRSeconds / float64(st.Recoveries)
+		score *= 1 - 0.5*math.Min(avg/300, 1)
+	}
+	return score
+}
+
+// Report is one provider's reliability summary for the dashboard.
+type Report struct {
+	ProviderID string             `json:"provider_id"`
+	Score      float64            `json:"score"`
+	Uptime     float64            `json:"uptime"`
+	MTTR       time.Duration      `json:"mttr"`
+	ErrorMix   map[string]float64 `json:"error_mix,omitempty"`
+	Down       bool               `json:"down"`
+}
+
+// Reports returns every tracked provider, best score first.
+func (s *Scorer) Reports() []Report {
+	s.mu.Lock()
+	out := make([]Report, 0, len(s.providers))
+	for id, st := range s.providers {
+		r := Report{ProviderID: id, ErrorMix: st.ErrorWeights, Down: st.DownSince != nil}
+		if total := st.SuccessWeight + st.FailureWeight; total > 0 {
+			r.Uptime = st.SuccessWeight / total
+		}
+		if st.Recoveries > 0 {
+			r.MTTR = time.Duration(st.MTTRSeconds/float64(st.Recoveries)) * time.Second
+		}
+		out = append(out, r)
+	}
+	s.mu.Unlock()
+	for i := range out {
+		out[i].Score = s.Score(out[i].ProviderID)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+// Snapshot serializes the scorer state for persistence; Restore loads it
+// back. Both fit the JSON-snapshot pattern used by the stats collector.
+func (s *Scorer) Snapshot() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return json.Marshal(s.providers)
+}
+
+// Restore replaces the scorer state from a Snapshot payload.
+func (s *Scorer) Restore(data []byte) error {
+	providers := make(map[string]*state)
+	if err := json.Unmarshal(data, &providers); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.providers = providers
+	s.mu.Unlock()
+	return nil
+}
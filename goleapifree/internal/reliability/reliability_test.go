@@ -0,0 +1,83 @@
+package reliability
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestScorer() (*Scorer, *time.Time) {
+	s := NewScorer()
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestScoreTracksSuccessRate(t *testing.T) {
+	s, _ := newTestScorer()
+	for i := 0; i < 9; i++ {
+		s.RecordSuccess("p1")
+	}
+	s.RecordFailure("p1", "http_5xx")
+	s.RecordSuccess("p1") // recover so the mid-incident discount clears
+
+	if got := s.Score("p1"); got < 0.8 || got > 0.95 {
+		t.Fatalf("score = %v, want roughly the success rate", got)
+	}
+	if got := s.Score("unknown"); got != 0.5 {
+		t.Fatalf("unknown provider score = %v, want 0.5", got)
+	}
+}
+
+func TestOldIncidentsDecay(t *testing.T) {
+	s, now := newTestScorer()
+	for i := 0; i < 10; i++ {
+		s.RecordFailure("p1", "timeout")
+	}
+	s.RecordSuccess("p1")
+	badScore := s.Score("p1")
+
+	// Three half-lives later a run of successes should dominate.
+	*now = now.Add(36 * time.Hour)
+	for i := 0; i < 10; i++ {
+		s.RecordSuccess("p1")
+	}
+	if got := s.Score("p1"); got <= badScore || got < 0.7 {
+		t.Fatalf("score = %v after recovery, old incident did not decay (was %v)", got, badScore)
+	}
+}
+
+func TestMTTRPenalty(t *testing.T) {
+	s, now := newTestScorer()
+	// Quick recovery: down for 10 seconds.
+	s.RecordFailure("fast", "timeout")
+	*now = now.Add(10 * time.Second)
+	for i := 0; i < 20; i++ {
+		s.RecordSuccess("fast")
+	}
+	// Slow recovery: down for 10 minutes.
+	s.RecordFailure("slow", "timeout")
+	*now = now.Add(10 * time.Minute)
+	for i := 0; i < 20; i++ {
+		s.RecordSuccess("slow")
+	}
+	if s.Score("slow") >= s.Score("fast") {
+		t.Fatalf("slow recoverer %v not penalized vs fast %v", s.Score("slow"), s.Score("fast"))
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	s, _ := newTestScorer()
+	s.RecordSuccess("p1")
+	s.RecordFailure("p2", "garbage")
+	data, err := s.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewScorer()
+	if err := restored.Restore(data); err != nil {
+		t.Fatal(err)
+	}
+	if len(restored.Reports()) != 2 {
+		t.Fatalf("reports after restore = %d, want 2", len(restored.Reports()))
+	}
+}
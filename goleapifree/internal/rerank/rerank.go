@@ -0,0 +1,155 @@
+// Package rerank scores documents against a query for RAG context
+// selection, via hosted rerank APIs (Cohere/Jina style) with a local
+// lexical fallback when no provider is reachable.
+package rerank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Result is one scored document, referencing the input by index.
+type Result struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+// Reranker orders documents by relevance to the query. Results come back
+// sorted best-first, truncated to topN (0 = all).
+type Reranker interface {
+	Rerank(ctx context.Context, query string, documents []string, topN int) ([]Result, error)
+}
+
+// HTTPReranker calls a hosted rerank API using the request/response shape
+// shared by Cohere and Jina.
+type HTTPReranker struct {
+	BaseURL string `json:"base_url"`
+	APIKey  string `json:"api_key"`
+	Model   string `json:"model"`
+
+	Client *http.Client `json:"-"`
+}
+
+// Rerank implements Reranker.
+func (h *HTTPReranker) Rerank(ctx context.Context, query string, documents []string, topN int) ([]Result, error) {
+	body, err := json.Marshal(map[string]any{
+		"model":     h.Model,
+		"query":     query,
+		"documents": documents,
+		"top_n":     topN,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.BaseURL+"/v1/rerank", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+h.APIKey)
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("rerank: %s: %s", resp.Status, errBody)
+	}
+	var parsed struct {
+		Results []Result `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("rerank: parsing response: %w", err)
+	}
+	return parsed.Results, nil
+}
+
+// Local is the dependency-free fallback reranker. It scores documents by
+// IDF-weighted term overlap with the query — far from a cross-encoder,
+// but good enough to keep RAG chains functional when providers are down.
+type Local struct{}
+
+// Rerank implements Reranker.
+func (Local) Rerank(_ context.Context, query string, documents []string, topN int) ([]Result, error) {
+	queryTerms := tokenize(query)
+	if len(queryTerms) == 0 {
+		return nil, fmt.Errorf("rerank: empty query")
+	}
+
+	// Document frequency per term for IDF weighting.
+	df := make(map[string]int)
+	docTerms := make([]map[string]int, len(documents))
+	for i, doc := range documents {
+		tf := make(map[string]int)
+		for _, t := range tokenize(doc) {
+			tf[t]++
+		}
+		docTerms[i] = tf
+		for t := range tf {
+			df[t]++
+		}
+	}
+
+	n := float64(len(documents))
+	results := make([]Result, len(documents))
+	for i := range documents {
+		var score float64
+		for _, t := range queryTerms {
+			tf := docTerms[i][t]
+			if tf == 0 {
+				continue
+			}
+			idf := math.Log(1 + n/float64(df[t]))
+			score += (1 + math.Log(float64(tf))) * idf
+		}
+		results[i] = Result{Index: i, RelevanceScore: score}
+	}
+	sort.SliceStable(results, func(a, b int) bool {
+		return results[a].RelevanceScore > results[b].RelevanceScore
+	})
+	if topN > 0 && topN < len(results) {
+		results = results[:topN]
+	}
+	return results, nil
+}
+
+// WithFallback chains a primary reranker with the local fallback.
+type WithFallback struct {
+	Primary  Reranker
+	Fallback Reranker
+}
+
+// Rerank implements Reranker, falling back on primary failure.
+func (w WithFallback) Rerank(ctx context.Context, query string, documents []string, topN int) ([]Result, error) {
+	if w.Primary != nil {
+		if results, err := w.Primary.Rerank(ctx, query, documents, topN); err == nil {
+			return results, nil
+		}
+	}
+	return w.Fallback.Rerank(ctx, query, documents, topN)
+}
+
+func tokenize(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9')
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		if len(f) > 1 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
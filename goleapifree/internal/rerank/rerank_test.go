@@ -0,0 +1,45 @@
+package rerank
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLocalPrefersRelevantDocuments(t *testing.T) {
+	docs := []string{
+		"the weather today is sunny with light wind",
+		"go routers select provider accounts for llm requests",
+		"recipe for sourdough bread with rye flour",
+	}
+	results, err := Local{}.Rerank(context.Background(), "llm provider routing", docs, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %d, want 2", len(results))
+	}
+	if results[0].Index != 1 {
+		t.Fatalf("top result index = %d, want 1 (router doc)", results[0].Index)
+	}
+	if results[0].RelevanceScore <= results[1].RelevanceScore {
+		t.Fatalf("scores not descending: %+v", results)
+	}
+}
+
+type failingReranker struct{}
+
+func (failingReranker) Rerank(context.Context, string, []string, int) ([]Result, error) {
+	return nil, errors.New("provider down")
+}
+
+func TestWithFallback(t *testing.T) {
+	w := WithFallback{Primary: failingReranker{}, Fallback: Local{}}
+	results, err := w.Rerank(context.Background(), "bread recipe", []string{"llm routing", "sourdough bread recipe"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Index != 1 {
+		t.Fatalf("fallback top index = %d, want 1", results[0].Index)
+	}
+}
@@ -0,0 +1,177 @@
+// Package resources collects GPU/CPU/memory metrics from local provider
+// hosts (a node-exporter scrape or `ollama ps` in the live wiring) and
+// distils them into a saturation figure. The router wiring multiplies
+// HealthFactor into the reliability prior so saturated hosts lose
+// routing weight, and the capacity planner surfaces Saturation in its
+// report.
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultThreshold is the saturation above which the health factor
+// starts dropping; below it a busy host is just a busy host.
+const defaultThreshold = 0.8
+
+// Sample is one scrape of a host's resource usage. Utilisations are in
+// [0,1]; sizes in MB. Fields a scraper cannot measure stay zero and are
+// ignored.
+type Sample struct {
+	At          time.Time `json:"at"`
+	GPUUtil     float64   `json:"gpu_util"`
+	VRAMUsedMB  int       `json:"vram_used_mb"`
+	VRAMTotalMB int       `json:"vram_total_mb"`
+	CPUUtil     float64   `json:"cpu_util"`
+	MemUsedMB   int       `json:"mem_used_mb"`
+	MemTotalMB  int       `json:"mem_total_mb"`
+}
+
+// HostStatus is one tracked host's latest state for the admin API.
+type HostStatus struct {
+	ProviderID string  `json:"provider_id"`
+	Host       string  `json:"host"`
+	Sample     Sample  `json:"sample"`
+	Saturation float64 `json:"saturation"`
+	LastError  string  `json:"last_error,omitempty"`
+}
+
+// Monitor polls tracked hosts and keeps their latest sample.
+type Monitor struct {
+	// Scrape fetches one host's metrics; tests inject fakes.
+	Scrape func(ctx context.Context, host string) (Sample, error)
+	// Threshold is where saturation starts to hurt the health factor;
+	// 0 applies the default.
+	Threshold float64
+
+	now func() time.Time
+
+	mu     sync.Mutex
+	hosts  map[string]string
+	latest map[string]Sample
+	errs   map[string]string
+}
+
+// NewMonitor creates a monitor around a scrape function.
+func NewMonitor(scrape func(ctx context.Context, host string) (Sample, error)) *Monitor {
+	return &Monitor{
+		Scrape: scrape,
+		now:    time.Now,
+		hosts:  make(map[string]string),
+		latest: make(map[string]Sample),
+		errs:   make(map[string]string),
+	}
+}
+
+// Track registers a local provider's host for polling.
+func (m *Monitor) Track(providerID, host string) {
+	m.mu.Lock()
+	m.hosts[providerID] = host
+	m.mu.Unlock()
+}
+
+// Poll scrapes every tracked host. Its signature matches the
+// scheduler's JobFunc; one unreachable host does not skip the rest, and
+// its stale sample is kept so routing degrades gracefully.
+func (m *Monitor) Poll(ctx context.Context) error {
+	m.mu.Lock()
+	hosts := make(map[string]string, len(m.hosts))
+	for id, host := range m.hosts {
+		hosts[id] = host
+	}
+	m.mu.Unlock()
+
+	ids := make([]string, 0, len(hosts))
+	for id := range hosts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var errs []error
+	for _, id := range ids {
+		sample, err := m.Scrape(ctx, hosts[id])
+		m.mu.Lock()
+		if err != nil {
+			m.errs[id] = err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+		} else {
+			sample.At = m.now()
+			m.latest[id] = sample
+			delete(m.errs, id)
+		}
+		m.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// saturation reduces a sample to its most constrained resource.
+func saturation(s Sample) float64 {
+	max := s.GPUUtil
+	if s.CPUUtil > max {
+		max = s.CPUUtil
+	}
+	if s.VRAMTotalMB > 0 {
+		if frac := float64(s.VRAMUsedMB) / float64(s.VRAMTotalMB); frac > max {
+			max = frac
+		}
+	}
+	if s.MemTotalMB > 0 {
+		if frac := float64(s.MemUsedMB) / float64(s.MemTotalMB); frac > max {
+			max = frac
+		}
+	}
+	if max > 1 {
+		max = 1
+	}
+	return max
+}
+
+// Saturation is the provider's most constrained resource in [0,1].
+// Untracked providers report 0: the monitor only speaks for hosts it
+// watches.
+func (m *Monitor) Saturation(providerID string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sample, ok := m.latest[providerID]
+	if !ok {
+		return 0
+	}
+	return saturation(sample)
+}
+
+// HealthFactor maps saturation to a routing multiplier: 1 below the
+// threshold, falling linearly to 0.5 at full saturation. The wiring
+// multiplies it into the reliability prior.
+func (m *Monitor) HealthFactor(providerID string) float64 {
+	sat := m.Saturation(providerID)
+	threshold := m.Threshold
+	if threshold <= 0 {
+		threshold = defaultThreshold
+	}
+	if sat <= threshold {
+		return 1
+	}
+	return 1 - 0.5*(sat-threshold)/(1-threshold)
+}
+
+// Statuses lists every tracked host, provider order.
+func (m *Monitor) Statuses() []HostStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]HostStatus, 0, len(m.hosts))
+	for id, host := range m.hosts {
+		st := HostStatus{ProviderID: id, Host: host, LastError: m.errs[id]}
+		if sample, ok := m.latest[id]; ok {
+			st.Sample = sample
+			st.Saturation = saturation(sample)
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ProviderID < out[j].ProviderID })
+	return out
+}
@@ -0,0 +1,65 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPollAndSaturation(t *testing.T) {
+	samples := map[string]Sample{
+		"gpu-1:9100": {GPUUtil: 0.95, VRAMUsedMB: 20000, VRAMTotalMB: 24000},
+		"gpu-2:9100": {CPUUtil: 0.2, MemUsedMB: 8000, MemTotalMB: 32000},
+	}
+	m := NewMonitor(func(_ context.Context, host string) (Sample, error) {
+		return samples[host], nil
+	})
+	m.Track("ollama-1", "gpu-1:9100")
+	m.Track("ollama-2", "gpu-2:9100")
+	if err := m.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if sat := m.Saturation("ollama-1"); sat != 0.95 {
+		t.Fatalf("saturation = %v", sat)
+	}
+	if sat := m.Saturation("ollama-2"); sat != 0.25 {
+		t.Fatalf("saturation = %v", sat)
+	}
+	if sat := m.Saturation("openai"); sat != 0 {
+		t.Fatalf("untracked saturation = %v", sat)
+	}
+
+	// Below the threshold the factor is flat; above it decays to 0.5.
+	if f := m.HealthFactor("ollama-2"); f != 1 {
+		t.Fatalf("healthy factor = %v", f)
+	}
+	if f := m.HealthFactor("ollama-1"); f >= 1 || f < 0.5 {
+		t.Fatalf("saturated factor = %v", f)
+	}
+}
+
+func TestPollKeepsStaleSampleOnError(t *testing.T) {
+	healthy := true
+	m := NewMonitor(func(context.Context, string) (Sample, error) {
+		if !healthy {
+			return Sample{}, errors.New("scrape timeout")
+		}
+		return Sample{GPUUtil: 0.9}, nil
+	})
+	m.Track("ollama-1", "gpu-1:9100")
+	if err := m.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	healthy = false
+	if err := m.Poll(context.Background()); err == nil {
+		t.Fatal("failed scrape reported success")
+	}
+	if sat := m.Saturation("ollama-1"); sat != 0.9 {
+		t.Fatalf("stale sample dropped: %v", sat)
+	}
+	sts := m.Statuses()
+	if len(sts) != 1 || sts[0].LastError == "" || sts[0].Saturation != 0.9 {
+		t.Fatalf("statuses = %+v", sts)
+	}
+}
@@ -0,0 +1,104 @@
+// Package respcache caches non-streaming completion responses per model
+// alias, honoring each alias's cache policy: deterministic-only by
+// default, always or never by directive, with an optional TTL override.
+package respcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/lru"
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// defaultTTL is the entry lifetime when the alias policy does not
+// override it.
+const defaultTTL = 5 * time.Minute
+
+// Cacheable decides whether a request may be served from or stored in
+// the cache under an alias's policy. Streaming requests never cache.
+func Cacheable(policy *models.CachePolicy, req providers.ChatRequest) bool {
+	if req.Stream {
+		return false
+	}
+	mode := models.CacheDeterministic
+	if policy != nil && policy.Mode != "" {
+		mode = policy.Mode
+	}
+	switch mode {
+	case models.CacheAlways:
+		return true
+	case models.CacheNever:
+		return false
+	default:
+		return req.Temperature == 0
+	}
+}
+
+type cacheEntry struct {
+	resp    providers.ChatResponse
+	expires time.Time
+}
+
+// Cache is the bounded response cache shared across aliases.
+type Cache struct {
+	// TTL is the default entry lifetime; 0 applies the package default.
+	TTL time.Duration
+
+	now     func() time.Time
+	entries *lru.Cache[string, cacheEntry]
+}
+
+// New creates a cache holding at most maxEntries responses and
+// publishes its usage gauge.
+func New(maxEntries int) *Cache {
+	c := &Cache{
+		now:     time.Now,
+		entries: lru.New[string, cacheEntry](maxEntries),
+	}
+	lru.RegisterGauge("respcache", c.entries.Stats)
+	return c
+}
+
+// Key derives the cache key for a request under an alias. Everything
+// that changes the completion is hashed; the alias keeps identical
+// prompts on different aliases apart, since their targets may differ.
+func Key(alias string, req providers.ChatRequest) string {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	enc.Encode(alias)
+	enc.Encode(req.Model)
+	enc.Encode(req.Messages)
+	enc.Encode(req.MaxTokens)
+	enc.Encode(req.Temperature)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the cached response for a key, expiring stale entries.
+func (c *Cache) Get(key string) (providers.ChatResponse, bool) {
+	entry, ok := c.entries.Get(key)
+	if !ok {
+		return providers.ChatResponse{}, false
+	}
+	if c.now().After(entry.expires) {
+		c.entries.Remove(key)
+		return providers.ChatResponse{}, false
+	}
+	return entry.resp, true
+}
+
+// Put stores a response under the alias's TTL: the policy override
+// first, then the cache default.
+func (c *Cache) Put(key string, resp providers.ChatResponse, policy *models.CachePolicy) {
+	ttl := c.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	if policy != nil && policy.TTLSeconds > 0 {
+		ttl = time.Duration(policy.TTLSeconds) * time.Second
+	}
+	c.entries.Put(key, cacheEntry{resp: resp, expires: c.now().Add(ttl)})
+}
@@ -0,0 +1,72 @@
+package respcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+func TestCacheable(t *testing.T) {
+	deterministic := providers.ChatRequest{Messages: []providers.Message{{Role: "user", Content: "q"}}}
+	creative := deterministic
+	creative.Temperature = 0.9
+	streaming := deterministic
+	streaming.Stream = true
+
+	for _, tc := range []struct {
+		name   string
+		policy *models.CachePolicy
+		req    providers.ChatRequest
+		want   bool
+	}{
+		{"default deterministic", nil, deterministic, true},
+		{"default creative", nil, creative, false},
+		{"always", &models.CachePolicy{Mode: models.CacheAlways}, creative, true},
+		{"never", &models.CachePolicy{Mode: models.CacheNever}, deterministic, false},
+		{"explicit deterministic", &models.CachePolicy{Mode: models.CacheDeterministic}, creative, false},
+		{"streaming never cached", &models.CachePolicy{Mode: models.CacheAlways}, streaming, false},
+	} {
+		if got := Cacheable(tc.policy, tc.req); got != tc.want {
+			t.Errorf("%s: Cacheable = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestKeySeparatesAliasesAndParams(t *testing.T) {
+	req := providers.ChatRequest{Messages: []providers.Message{{Role: "user", Content: "q"}}}
+	if Key("fast", req) != Key("fast", req) {
+		t.Fatal("key not stable")
+	}
+	if Key("fast", req) == Key("smart", req) {
+		t.Fatal("aliases share a key")
+	}
+	other := req
+	other.MaxTokens = 100
+	if Key("fast", req) == Key("fast", other) {
+		t.Fatal("parameter change kept the key")
+	}
+}
+
+func TestTTLAndOverride(t *testing.T) {
+	c := New(10)
+	at := time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return at }
+
+	resp := providers.ChatResponse{Content: "42"}
+	c.Put("default", resp, nil)
+	c.Put("long", resp, &models.CachePolicy{TTLSeconds: 3600})
+
+	at = at.Add(10 * time.Minute)
+	if _, ok := c.Get("default"); ok {
+		t.Fatal("default TTL entry survived")
+	}
+	if got, ok := c.Get("long"); !ok || got.Content != "42" {
+		t.Fatal("TTL override ignored")
+	}
+	at = at.Add(2 * time.Hour)
+	if _, ok := c.Get("long"); ok {
+		t.Fatal("override entry never expired")
+	}
+}
@@ -0,0 +1,120 @@
+// Package retention enforces per-tenant content retention: prompts,
+// responses and metadata each carry their own store/expiry rule, applied
+// by the logging pipeline at write time and again whenever logs leave
+// the gateway (backups, exports). Accounting fields (tokens, cost,
+// latency) are never touched — only content is.
+package retention
+
+import (
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+// Rule governs one content class. TTL 0 means "keep as long as the
+// record exists"; Store false means "never write it at all".
+type Rule struct {
+	Store bool          `json:"store"`
+	TTL   time.Duration `json:"ttl,omitempty"`
+}
+
+// expired reports whether content written at t should be gone by now.
+func (r Rule) expired(t, now time.Time) bool {
+	if !r.Store {
+		return true
+	}
+	return r.TTL > 0 && now.Sub(t) > r.TTL
+}
+
+// Policy is one tenant's retention configuration.
+type Policy struct {
+	Prompts   Rule `json:"prompts"`
+	Responses Rule `json:"responses"`
+	Metadata  Rule `json:"metadata"`
+}
+
+// DefaultPolicy stores everything indefinitely, matching the gateway's
+// behavior before retention controls existed.
+var DefaultPolicy = Policy{
+	Prompts:   Rule{Store: true},
+	Responses: Rule{Store: true},
+	Metadata:  Rule{Store: true},
+}
+
+// Registry holds per-tenant policies with a configurable default.
+type Registry struct {
+	Default Policy
+
+	mu       sync.RWMutex
+	policies map[string]Policy
+	now      func() time.Time
+}
+
+// NewRegistry creates a registry defaulting to DefaultPolicy.
+func NewRegistry() *Registry {
+	return &Registry{
+		Default:  DefaultPolicy,
+		policies: make(map[string]Policy),
+		now:      time.Now,
+	}
+}
+
+// Set configures a tenant's policy.
+func (r *Registry) Set(tenant string, p Policy) {
+	r.mu.Lock()
+	r.policies[tenant] = p
+	r.mu.Unlock()
+}
+
+// PolicyFor resolves a tenant's policy, falling back to the default.
+func (r *Registry) PolicyFor(tenant string) Policy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, ok := r.policies[tenant]; ok {
+		return p
+	}
+	return r.Default
+}
+
+// ScrubOnWrite drops content the tenant never stores. The logging
+// pipeline calls this before persisting, so "never store bodies" means
+// the bytes never reach disk.
+func (r *Registry) ScrubOnWrite(log *models.RequestLog) {
+	p := r.PolicyFor(log.Tenant)
+	if !p.Prompts.Store {
+		log.Prompt = ""
+	}
+	if !p.Responses.Store {
+		log.Response = ""
+	}
+	if !p.Metadata.Store {
+		log.Metadata = nil
+	}
+}
+
+// Redact clears content whose TTL has passed, in place. It runs in the
+// maintenance sweep and — so stale copies cannot resurface — on every
+// read that leaves the gateway.
+func (r *Registry) Redact(log *models.RequestLog) {
+	p := r.PolicyFor(log.Tenant)
+	now := r.now()
+	if p.Prompts.expired(log.CreatedAt, now) {
+		log.Prompt = ""
+	}
+	if p.Responses.expired(log.CreatedAt, now) {
+		log.Response = ""
+	}
+	if p.Metadata.expired(log.CreatedAt, now) {
+		log.Metadata = nil
+	}
+}
+
+// Filter applies Redact to a batch and returns it, for backup and
+// export paths that hand whole slices onward.
+func (r *Registry) Filter(logs []models.RequestLog) []models.RequestLog {
+	for i := range logs {
+		r.Redact(&logs[i])
+	}
+	return logs
+}
@@ -0,0 +1,96 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+func sampleLog(tenant string, age time.Duration, now time.Time) models.RequestLog {
+	return models.RequestLog{
+		ID: "r1", Tenant: tenant, Tokens: 100, Cost: 0.01,
+		Prompt: "the prompt", Response: "the response",
+		Metadata:  map[string]string{"feature": "search"},
+		CreatedAt: now.Add(-age),
+	}
+}
+
+func TestScrubOnWriteHonorsNeverStore(t *testing.T) {
+	r := NewRegistry()
+	r.Set("acme", Policy{
+		Prompts:   Rule{Store: false},
+		Responses: Rule{Store: false},
+		Metadata:  Rule{Store: true, TTL: 90 * 24 * time.Hour},
+	})
+
+	log := sampleLog("acme", 0, time.Now())
+	r.ScrubOnWrite(&log)
+	if log.Prompt != "" || log.Response != "" {
+		t.Fatalf("bodies survived scrub: %+v", log)
+	}
+	if log.Metadata["feature"] != "search" || log.Tokens != 100 {
+		t.Fatalf("metadata/accounting must survive: %+v", log)
+	}
+
+	// Tenants without a policy keep everything.
+	other := sampleLog("other", 0, time.Now())
+	r.ScrubOnWrite(&other)
+	if other.Prompt == "" || other.Response == "" {
+		t.Fatalf("default policy dropped content: %+v", other)
+	}
+}
+
+func TestRedactExpiresClassesIndependently(t *testing.T) {
+	now := time.Now()
+	r := NewRegistry()
+	r.now = func() time.Time { return now }
+	r.Set("acme", Policy{
+		Prompts:   Rule{Store: true, TTL: 7 * 24 * time.Hour},
+		Responses: Rule{Store: true, TTL: 7 * 24 * time.Hour},
+		Metadata:  Rule{Store: true, TTL: 90 * 24 * time.Hour},
+	})
+
+	fresh := sampleLog("acme", 24*time.Hour, now)
+	r.Redact(&fresh)
+	if fresh.Prompt == "" || fresh.Metadata == nil {
+		t.Fatalf("fresh log redacted early: %+v", fresh)
+	}
+
+	aged := sampleLog("acme", 30*24*time.Hour, now)
+	r.Redact(&aged)
+	if aged.Prompt != "" || aged.Response != "" {
+		t.Fatalf("bodies past TTL survived: %+v", aged)
+	}
+	if aged.Metadata == nil {
+		t.Fatal("metadata inside its longer TTL was dropped")
+	}
+	if aged.Cost != 0.01 {
+		t.Fatalf("accounting changed: %+v", aged)
+	}
+
+	ancient := sampleLog("acme", 100*24*time.Hour, now)
+	r.Redact(&ancient)
+	if ancient.Metadata != nil {
+		t.Fatalf("metadata past TTL survived: %+v", ancient)
+	}
+}
+
+func TestFilterRedactsBatchesForExport(t *testing.T) {
+	now := time.Now()
+	r := NewRegistry()
+	r.now = func() time.Time { return now }
+	r.Set("acme", Policy{
+		Prompts:   Rule{Store: true, TTL: time.Hour},
+		Responses: Rule{Store: true, TTL: time.Hour},
+		Metadata:  Rule{Store: true},
+	})
+
+	logs := r.Filter([]models.RequestLog{
+		sampleLog("acme", 2*time.Hour, now),
+		sampleLog("acme", time.Minute, now),
+	})
+	if logs[0].Prompt != "" || logs[1].Prompt == "" {
+		t.Fatalf("filter = %+v", logs)
+	}
+}
@@ -0,0 +1,239 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+// Alias weight management: model migrations should not require a restart
+// or a flag-day cutover. The AliasStore persists runtime weight edits so
+// they survive restarts, and WeightShift moves traffic toward a new
+// target gradually ("10% per hour") under the scheduler.
+
+// WeightShift is a scheduled, gradual traffic move toward one alias
+// target. Each step raises the target's share of the alias by
+// StepPercent until it reaches TargetPercent.
+type WeightShift struct {
+	Alias      string `json:"alias"`
+	ProviderID string `json:"provider_id"`
+	Model      string `json:"model"`
+	// StepPercent is how many percentage points of traffic share move to
+	// the target per step.
+	StepPercent float64 `json:"step_percent"`
+	// TargetPercent is the share at which the shift completes (100 moves
+	// everything to the target).
+	TargetPercent float64 `json:"target_percent"`
+	// Interval separates steps; the first step runs at NextAt.
+	Interval time.Duration `json:"interval"`
+	NextAt   time.Time     `json:"next_at"`
+	Done     bool          `json:"done"`
+}
+
+// AliasStore persists alias definitions and pending weight shifts as a
+// JSON file and keeps the router in sync with every change.
+type AliasStore struct {
+	router *Router
+	path   string
+
+	mu     sync.Mutex
+	shifts []*WeightShift
+	now    func() time.Time
+}
+
+// aliasFile is the on-disk shape of the store.
+type aliasFile struct {
+	Aliases []*models.ModelAlias `json:"aliases"`
+	Shifts  []*WeightShift       `json:"shifts,omitempty"`
+}
+
+// NewAliasStore loads the alias file (if present), replays its aliases
+// into the router and returns the store. A missing file is a first run,
+// not an error.
+func NewAliasStore(path string, r *Router) (*AliasStore, error) {
+	s := &AliasStore{router: r, path: path, now: time.Now}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file aliasFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("router: corrupt alias file %s: %w", path, err)
+	}
+	for _, a := range file.Aliases {
+		r.SetAlias(a)
+	}
+	s.shifts = file.Shifts
+	return s, nil
+}
+
+// Set registers or replaces an alias and persists the change.
+func (s *AliasStore) Set(alias *models.ModelAlias) error {
+	s.router.SetAlias(alias)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flushLocked()
+}
+
+// Update applies a versioned alias update (see Router.UpdateAlias) and
+// persists it on success.
+func (s *AliasStore) Update(alias *models.ModelAlias, expectedVersion int64) (*models.ModelAlias, error) {
+	updated, err := s.router.UpdateAlias(alias, expectedVersion)
+	if err != nil {
+		return updated, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return updated, s.flushLocked()
+}
+
+// Schedule validates and enqueues a gradual weight shift. The first step
+// runs on the next shift job tick; subsequent steps follow Interval.
+func (s *AliasStore) Schedule(shift WeightShift) (*WeightShift, error) {
+	if shift.StepPercent <= 0 || shift.StepPercent > 100 {
+		return nil, errors.New("router: step_percent must be in (0,100]")
+	}
+	if shift.TargetPercent <= 0 || shift.TargetPercent > 100 {
+		return nil, errors.New("router: target_percent must be in (0,100]")
+	}
+	if shift.Interval <= 0 {
+		return nil, errors.New("router: shift interval must be positive")
+	}
+	alias := s.router.GetAlias(shift.Alias)
+	if alias == nil {
+		return nil, ErrAliasNotFound
+	}
+	if findTarget(alias, shift.ProviderID, shift.Model) < 0 {
+		return nil, fmt.Errorf("router: alias %q has no target %s/%s", shift.Alias, shift.ProviderID, shift.Model)
+	}
+	shift.NextAt = s.now()
+	shift.Done = false
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shifts = append(s.shifts, &shift)
+	return &shift, s.flushLocked()
+}
+
+// Shifts returns a snapshot of every scheduled shift, finished ones
+// included, for the admin API.
+func (s *AliasStore) Shifts() []WeightShift {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]WeightShift, 0, len(s.shifts))
+	for _, sh := range s.shifts {
+		out = append(out, *sh)
+	}
+	return out
+}
+
+// RunShifts applies one step to every due shift. It matches the
+// scheduler's JobFunc signature so startup can register it directly,
+// e.g. every few minutes; each shift's own Interval controls its pace.
+func (s *AliasStore) RunShifts(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	changed := false
+	for _, sh := range s.shifts {
+		if sh.Done || now.Before(sh.NextAt) {
+			continue
+		}
+		if err := s.stepLocked(sh); err != nil {
+			return err
+		}
+		sh.NextAt = now.Add(sh.Interval)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return s.flushLocked()
+}
+
+// stepLocked moves one StepPercent of traffic share to the shift's
+// target, rescaling the target weight against the rest of the alias.
+// Caller holds s.mu.
+func (s *AliasStore) stepLocked(sh *WeightShift) error {
+	alias := s.router.GetAlias(sh.Alias)
+	if alias == nil {
+		return fmt.Errorf("router: shift target alias %q disappeared", sh.Alias)
+	}
+	idx := findTarget(alias, sh.ProviderID, sh.Model)
+	if idx < 0 {
+		return fmt.Errorf("router: alias %q lost target %s/%s mid-shift", sh.Alias, sh.ProviderID, sh.Model)
+	}
+
+	next := &models.ModelAlias{Alias: alias.Alias, Targets: make([]models.AliasTarget, len(alias.Targets))}
+	copy(next.Targets, alias.Targets)
+	// An alias with no weights set splits evenly (see Candidates); make
+	// that explicit before redistributing.
+	var total float64
+	for _, t := range next.Targets {
+		if t.Weight > 0 {
+			total += t.Weight
+		}
+	}
+	if total == 0 {
+		for i := range next.Targets {
+			next.Targets[i].Weight = 1
+		}
+		total = float64(len(next.Targets))
+	}
+	rest := total - next.Targets[idx].Weight
+
+	share := next.Targets[idx].Weight / total * 100
+	share += sh.StepPercent
+	goal := sh.TargetPercent
+	if share >= goal {
+		share = goal
+		sh.Done = true
+	}
+	if share >= 100 || rest == 0 {
+		// Everything moves to the target; the other targets stay listed
+		// drained at weight zero so reverting is a weight edit, not a
+		// re-add.
+		for i := range next.Targets {
+			next.Targets[i].Weight = 0
+		}
+		next.Targets[idx].Weight = 1
+	} else {
+		// Solve w/(w+rest) = share, keeping the other weights untouched
+		// so their relative split is preserved.
+		next.Targets[idx].Weight = share / (100 - share) * rest
+	}
+
+	_, err := s.router.UpdateAlias(next, alias.Version)
+	return err
+}
+
+// flushLocked persists the router's aliases plus the shift queue.
+// Caller holds s.mu.
+func (s *AliasStore) flushLocked() error {
+	file := aliasFile{Aliases: s.router.Aliases(), Shifts: s.shifts}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// findTarget returns the index of the (provider, model) target in the
+// alias, or -1.
+func findTarget(alias *models.ModelAlias, providerID, model string) int {
+	for i, t := range alias.Targets {
+		if t.ProviderID == providerID && t.Model == model {
+			return i
+		}
+	}
+	return -1
+}
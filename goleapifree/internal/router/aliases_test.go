@@ -0,0 +1,185 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+func twoTargetAlias() *models.ModelAlias {
+	return &models.ModelAlias{Alias: "prod", Targets: []models.AliasTarget{
+		{ProviderID: "p1", Model: "old", Weight: 1},
+		{ProviderID: "p2", Model: "new", Weight: 0},
+	}}
+}
+
+func TestAliasStorePersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.json")
+	r := New()
+	store, err := NewAliasStore(path, r)
+	if err != nil {
+		t.Fatalf("NewAliasStore: %v", err)
+	}
+	if err := store.Set(twoTargetAlias()); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	// A fresh router loading the same file sees the alias again.
+	r2 := New()
+	if _, err := NewAliasStore(path, r2); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	alias := r2.GetAlias("prod")
+	if alias == nil || len(alias.Targets) != 2 || alias.Version != 1 {
+		t.Fatalf("reloaded alias = %+v", alias)
+	}
+}
+
+func TestUpdateAliasVersionCheck(t *testing.T) {
+	r := New()
+	r.SetAlias(twoTargetAlias())
+
+	updated, err := r.UpdateAlias(&models.ModelAlias{Alias: "prod", Targets: []models.AliasTarget{
+		{ProviderID: "p2", Model: "new", Weight: 1},
+	}}, 1)
+	if err != nil || updated.Version != 2 {
+		t.Fatalf("UpdateAlias = %+v, %v", updated, err)
+	}
+
+	current, err := r.UpdateAlias(twoTargetAlias(), 1)
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("err = %v, want ErrVersionConflict", err)
+	}
+	if len(current.Targets) != 1 {
+		t.Fatalf("conflict did not return current state: %+v", current)
+	}
+
+	if _, err := r.UpdateAlias(&models.ModelAlias{Alias: "ghost"}, 1); !errors.Is(err, ErrAliasNotFound) {
+		t.Fatalf("err = %v, want ErrAliasNotFound", err)
+	}
+}
+
+func TestScheduleRejectsBadShifts(t *testing.T) {
+	r := New()
+	r.SetAlias(twoTargetAlias())
+	store, err := NewAliasStore(filepath.Join(t.TempDir(), "aliases.json"), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []WeightShift{
+		{Alias: "ghost", ProviderID: "p2", Model: "new", StepPercent: 10, TargetPercent: 100, Interval: time.Hour},
+		{Alias: "prod", ProviderID: "p9", Model: "new", StepPercent: 10, TargetPercent: 100, Interval: time.Hour},
+		{Alias: "prod", ProviderID: "p2", Model: "new", StepPercent: 0, TargetPercent: 100, Interval: time.Hour},
+		{Alias: "prod", ProviderID: "p2", Model: "new", StepPercent: 10, TargetPercent: 0, Interval: time.Hour},
+		{Alias: "prod", ProviderID: "p2", Model: "new", StepPercent: 10, TargetPercent: 100},
+	}
+	for i, shift := range cases {
+		if _, err := store.Schedule(shift); err == nil {
+			t.Fatalf("case %d: bad shift accepted", i)
+		}
+	}
+}
+
+func TestRunShiftsMovesShareGradually(t *testing.T) {
+	r := New()
+	store, err := NewAliasStore(filepath.Join(t.TempDir(), "aliases.json"), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	if err := store.Set(twoTargetAlias()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Schedule(WeightShift{
+		Alias: "prod", ProviderID: "p2", Model: "new",
+		StepPercent: 10, TargetPercent: 100, Interval: time.Hour,
+	}); err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+
+	share := func() float64 {
+		alias := r.GetAlias("prod")
+		var total float64
+		for _, tg := range alias.Targets {
+			total += tg.Weight
+		}
+		return alias.Targets[1].Weight / total * 100
+	}
+
+	if err := store.RunShifts(context.Background()); err != nil {
+		t.Fatalf("RunShifts: %v", err)
+	}
+	if got := share(); math.Abs(got-10) > 0.001 {
+		t.Fatalf("share after first step = %.2f, want 10", got)
+	}
+
+	// A tick before the interval elapses must not step again.
+	now = now.Add(30 * time.Minute)
+	if err := store.RunShifts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := share(); math.Abs(got-10) > 0.001 {
+		t.Fatalf("share stepped early: %.2f", got)
+	}
+
+	// Run the schedule out: ten hourly steps reach 100% and finish.
+	for i := 0; i < 12; i++ {
+		now = now.Add(time.Hour)
+		if err := store.RunShifts(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := share(); got != 100 {
+		t.Fatalf("final share = %.2f, want 100", got)
+	}
+	shifts := store.Shifts()
+	if len(shifts) != 1 || !shifts[0].Done {
+		t.Fatalf("shifts = %+v", shifts)
+	}
+	// The other target stays listed at weight zero so reverting is a
+	// plain weight edit.
+	if alias := r.GetAlias("prod"); len(alias.Targets) != 2 || alias.Targets[0].Weight != 0 {
+		t.Fatalf("alias after shift = %+v", r.GetAlias("prod"))
+	}
+}
+
+func TestRunShiftsStopsAtPartialTarget(t *testing.T) {
+	r := New()
+	store, err := NewAliasStore(filepath.Join(t.TempDir(), "aliases.json"), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	if err := store.Set(twoTargetAlias()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Schedule(WeightShift{
+		Alias: "prod", ProviderID: "p2", Model: "new",
+		StepPercent: 20, TargetPercent: 50, Interval: time.Hour,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := store.RunShifts(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(time.Hour)
+	}
+	alias := r.GetAlias("prod")
+	if math.Abs(alias.Targets[1].Weight-alias.Targets[0].Weight) > 0.001 {
+		t.Fatalf("50%% shift did not balance weights: %+v", alias.Targets)
+	}
+	if !store.Shifts()[0].Done {
+		t.Fatal("shift at target not marked done")
+	}
+}
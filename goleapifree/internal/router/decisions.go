@@ -0,0 +1,160 @@
+package router
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Routing decision analytics: each Route call is recorded as a Decision
+// — who won, who was available but lost the draw, and why the rest were
+// filtered — and the log aggregates them into the "quality filter
+// excluded provider X in 40% of requests last week" style of report
+// that individual request logs cannot answer.
+
+// maxDecisions bounds the log regardless of the retention window.
+const maxDecisions = 100_000
+
+// Decision records the outcome of one Route call.
+type Decision struct {
+	Model  string `json:"model"`
+	Winner string `json:"winner,omitempty"` // provider ID, empty when no candidate was available
+	// Lost lists providers that offered a candidate but were not picked.
+	Lost []string `json:"lost,omitempty"`
+	// Filtered maps a provider to the first reason it yielded no
+	// candidate: "disabled", "excluded", "drained", "account_disabled"
+	// or "saturated".
+	Filtered map[string]string `json:"filtered,omitempty"`
+	At       time.Time         `json:"at"`
+}
+
+// newDecision derives a Decision from Route's working state.
+func newDecision(model, winner string, candidates []Candidate, filtered map[string]string) Decision {
+	d := Decision{Model: model, Winner: winner, Filtered: filtered, At: time.Now()}
+	seen := map[string]bool{winner: true}
+	for _, c := range candidates {
+		if id := c.Provider.ID; !seen[id] {
+			seen[id] = true
+			d.Lost = append(d.Lost, id)
+		}
+	}
+	return d
+}
+
+// ProviderDecisionStats aggregates one provider's standing across the
+// reported decisions.
+type ProviderDecisionStats struct {
+	ProviderID string `json:"provider_id"`
+	// Wins is how often the provider served the request.
+	Wins int `json:"wins"`
+	// Lost is how often it was available but another provider won.
+	Lost int `json:"lost"`
+	// Filtered counts, per reason, how often the provider was removed
+	// before selection.
+	Filtered map[string]int `json:"filtered,omitempty"`
+}
+
+// DecisionReport is the aggregate over a time range.
+type DecisionReport struct {
+	Window    time.Duration `json:"window"`
+	Decisions int           `json:"decisions"`
+	// NoCandidate is how many decisions ended with ErrNoCandidate.
+	NoCandidate int                     `json:"no_candidate"`
+	Providers   []ProviderDecisionStats `json:"providers"`
+}
+
+// DecisionLog retains recent decisions and answers aggregate queries.
+// It plugs into Router.Observe.
+type DecisionLog struct {
+	retention time.Duration
+
+	mu      sync.Mutex
+	entries []Decision
+	now     func() time.Time
+}
+
+// NewDecisionLog retains decisions for the given duration (and at most
+// maxDecisions entries).
+func NewDecisionLog(retention time.Duration) *DecisionLog {
+	return &DecisionLog{retention: retention, now: time.Now}
+}
+
+// Record appends one decision, pruning expired entries as it goes.
+func (l *DecisionLog) Record(d Decision) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if d.At.IsZero() {
+		d.At = l.now()
+	}
+	l.pruneLocked()
+	l.entries = append(l.entries, d)
+	if len(l.entries) > maxDecisions {
+		l.entries = l.entries[len(l.entries)-maxDecisions:]
+	}
+}
+
+// Report aggregates the decisions of the last window; a window of 0 (or
+// longer than the retention) covers everything retained.
+func (l *DecisionLog) Report(window time.Duration) DecisionReport {
+	if window <= 0 || window > l.retention {
+		window = l.retention
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pruneLocked()
+
+	cutoff := l.now().Add(-window)
+	report := DecisionReport{Window: window}
+	byProvider := make(map[string]*ProviderDecisionStats)
+	stat := func(id string) *ProviderDecisionStats {
+		s, ok := byProvider[id]
+		if !ok {
+			s = &ProviderDecisionStats{ProviderID: id}
+			byProvider[id] = s
+		}
+		return s
+	}
+
+	for _, d := range l.entries {
+		if d.At.Before(cutoff) {
+			continue
+		}
+		report.Decisions++
+		if d.Winner == "" {
+			report.NoCandidate++
+		} else {
+			stat(d.Winner).Wins++
+		}
+		for _, id := range d.Lost {
+			stat(id).Lost++
+		}
+		for id, reason := range d.Filtered {
+			s := stat(id)
+			if s.Filtered == nil {
+				s.Filtered = make(map[string]int)
+			}
+			s.Filtered[reason]++
+		}
+	}
+
+	report.Providers = make([]ProviderDecisionStats, 0, len(byProvider))
+	for _, s := range byProvider {
+		report.Providers = append(report.Providers, *s)
+	}
+	sort.Slice(report.Providers, func(i, j int) bool {
+		return report.Providers[i].ProviderID < report.Providers[j].ProviderID
+	})
+	return report
+}
+
+// pruneLocked drops entries older than the retention. Caller holds l.mu.
+func (l *DecisionLog) pruneLocked() {
+	cutoff := l.now().Add(-l.retention)
+	i := 0
+	for i < len(l.entries) && l.entries[i].At.Before(cutoff) {
+		i++
+	}
+	if i > 0 {
+		l.entries = append(l.entries[:0], l.entries[i:]...)
+	}
+}
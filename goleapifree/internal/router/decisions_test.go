@@ -0,0 +1,93 @@
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+func TestRouteObservesDecisions(t *testing.T) {
+	r := New()
+	log := NewDecisionLog(time.Hour)
+	r.Observe = log.Record
+
+	r.AddProvider(&models.Provider{ID: "alpha", Enabled: true})
+	r.AddProvider(&models.Provider{ID: "beta", Enabled: true})
+	r.AddProvider(&models.Provider{ID: "down", Enabled: false})
+	r.AddProvider(&models.Provider{ID: "banned", Enabled: true})
+	r.AddAccount(&models.Account{ID: "a1", ProviderID: "alpha", Enabled: true})
+	r.AddAccount(&models.Account{ID: "a2", ProviderID: "beta", Enabled: true})
+	r.AddAccount(&models.Account{ID: "a3", ProviderID: "banned", Enabled: true})
+	r.Exclude = func(id string) bool { return id == "banned" }
+
+	for i := 0; i < 10; i++ {
+		c, err := r.Route("some-model")
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Release(c)
+	}
+
+	report := log.Report(0)
+	if report.Decisions != 10 || report.NoCandidate != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	stats := make(map[string]ProviderDecisionStats)
+	for _, s := range report.Providers {
+		stats[s.ProviderID] = s
+	}
+	if stats["alpha"].Wins != 10 {
+		t.Fatalf("winner stats = %+v", stats["alpha"])
+	}
+	if stats["beta"].Lost != 10 {
+		t.Fatalf("loser stats = %+v", stats["beta"])
+	}
+	if stats["down"].Filtered["disabled"] != 10 {
+		t.Fatalf("down stats = %+v", stats["down"])
+	}
+	if stats["banned"].Filtered["excluded"] != 10 {
+		t.Fatalf("banned stats = %+v", stats["banned"])
+	}
+}
+
+func TestDecisionLogWindowAndRetention(t *testing.T) {
+	log := NewDecisionLog(time.Hour)
+	now := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	log.now = func() time.Time { return now }
+
+	log.Record(Decision{Model: "m", Winner: "old"})
+	now = now.Add(30 * time.Minute)
+	log.Record(Decision{Model: "m", Winner: "new"})
+
+	// A 10-minute window sees only the recent decision.
+	report := log.Report(10 * time.Minute)
+	if report.Decisions != 1 || report.Providers[0].ProviderID != "new" {
+		t.Fatalf("windowed report = %+v", report)
+	}
+
+	// Past the retention the old entry is pruned entirely.
+	now = now.Add(45 * time.Minute)
+	if report := log.Report(0); report.Decisions != 1 {
+		t.Fatalf("retained decisions = %d, want 1", report.Decisions)
+	}
+}
+
+func TestRouteObservesNoCandidate(t *testing.T) {
+	r := New()
+	log := NewDecisionLog(time.Hour)
+	r.Observe = log.Record
+	r.AddProvider(&models.Provider{ID: "p1", Enabled: true})
+	r.AddAccount(&models.Account{ID: "a1", ProviderID: "p1", Enabled: false})
+
+	if _, err := r.Route("m"); err == nil {
+		t.Fatal("expected ErrNoCandidate")
+	}
+	report := log.Report(0)
+	if report.NoCandidate != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	if report.Providers[0].Filtered["account_disabled"] != 1 {
+		t.Fatalf("provider stats = %+v", report.Providers[0])
+	}
+}
@@ -0,0 +1,127 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+// Blue/green credential rotation: a replacement API key is staged next
+// to the live one, verified against the provider, and only then switched
+// in. The live key serves every request until the very moment of the
+// switch, so rotating a key never opens a window of auth failures.
+
+// ErrAccountNotFound is returned for rotation calls against unknown
+// accounts.
+var ErrAccountNotFound = errors.New("router: account not found")
+
+// ErrNoStagedCredential is returned when a rotation is committed without
+// a staged credential to switch to.
+var ErrNoStagedCredential = errors.New("router: no staged credential")
+
+// ErrCredentialChanged is returned when the staged credential was
+// replaced while its verification probe was in flight.
+var ErrCredentialChanged = errors.New("router: staged credential changed during verification")
+
+// CredentialProbe verifies that a provider accepts an API key, e.g. with
+// a minimal authenticated request. Injectable so tests and exotic
+// providers can supply their own check.
+type CredentialProbe func(ctx context.Context, p *models.Provider, apiKey string) error
+
+// DefaultCredentialProbe issues an authenticated GET {base_url}/models
+// — the cheapest call every OpenAI-compatible provider answers — and
+// accepts any 2xx. A nil client uses http.DefaultClient.
+func DefaultCredentialProbe(client *http.Client) CredentialProbe {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context, p *models.Provider, apiKey string) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.BaseURL, "/")+"/models", nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("router: probe got %d from %s", resp.StatusCode, p.Name)
+		}
+		return nil
+	}
+}
+
+// StageCredential attaches the next credential to an account. It takes
+// no traffic until RotateCredential verifies and commits it; staging
+// again simply replaces the previous staged key.
+func (r *Router) StageCredential(accountID, apiKey string) error {
+	if apiKey == "" {
+		return errors.New("router: staged credential must not be empty")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a := r.findAccountLocked(accountID)
+	if a == nil {
+		return ErrAccountNotFound
+	}
+	a.NextAPIKey = apiKey
+	return nil
+}
+
+// RotateCredential probes the account's staged credential and, only when
+// the probe passes, atomically switches traffic to it. On probe failure
+// the staged key stays staged (for inspection or a retry) and the live
+// key keeps serving.
+func (r *Router) RotateCredential(ctx context.Context, accountID string, probe CredentialProbe) error {
+	r.mu.RLock()
+	a := r.findAccountLocked(accountID)
+	var staged string
+	var provider *models.Provider
+	if a != nil {
+		staged = a.NextAPIKey
+		provider = r.providers[a.ProviderID]
+	}
+	r.mu.RUnlock()
+
+	if a == nil {
+		return ErrAccountNotFound
+	}
+	if staged == "" {
+		return ErrNoStagedCredential
+	}
+	if provider == nil {
+		return ErrProviderNotFound
+	}
+	// Probe outside the lock: a slow provider must not stall routing.
+	if err := probe(ctx, provider, staged); err != nil {
+		return fmt.Errorf("router: credential probe failed: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if a.NextAPIKey != staged {
+		return ErrCredentialChanged
+	}
+	a.APIKey = staged
+	a.NextAPIKey = ""
+	return nil
+}
+
+// findAccountLocked returns the account with the given ID, or nil.
+// Caller holds r.mu.
+func (r *Router) findAccountLocked(id string) *models.Account {
+	for _, accounts := range r.accounts {
+		for _, a := range accounts {
+			if a.ID == id {
+				return a
+			}
+		}
+	}
+	return nil
+}
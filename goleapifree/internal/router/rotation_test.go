@@ -0,0 +1,116 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+func rotationRouter() *Router {
+	r := New()
+	r.AddProvider(&models.Provider{ID: "p1", Name: "one", Enabled: true})
+	r.AddAccount(&models.Account{ID: "a1", ProviderID: "p1", APIKey: "old-key", Enabled: true})
+	return r
+}
+
+func TestRotateCredentialSwitchesAfterProbe(t *testing.T) {
+	r := rotationRouter()
+	if err := r.StageCredential("a1", "new-key"); err != nil {
+		t.Fatalf("StageCredential: %v", err)
+	}
+
+	var probed string
+	probe := func(_ context.Context, p *models.Provider, key string) error {
+		if p.ID != "p1" {
+			t.Fatalf("probed provider %q", p.ID)
+		}
+		probed = key
+		return nil
+	}
+	if err := r.RotateCredential(context.Background(), "a1", probe); err != nil {
+		t.Fatalf("RotateCredential: %v", err)
+	}
+	if probed != "new-key" {
+		t.Fatalf("probe saw %q", probed)
+	}
+	a := r.findAccountLocked("a1")
+	if a.APIKey != "new-key" || a.NextAPIKey != "" {
+		t.Fatalf("account after rotation = %+v", a)
+	}
+}
+
+func TestRotateCredentialKeepsOldKeyOnProbeFailure(t *testing.T) {
+	r := rotationRouter()
+	if err := r.StageCredential("a1", "bad-key"); err != nil {
+		t.Fatal(err)
+	}
+	probe := func(context.Context, *models.Provider, string) error {
+		return errors.New("401 unauthorized")
+	}
+	if err := r.RotateCredential(context.Background(), "a1", probe); err == nil {
+		t.Fatal("failed probe committed a rotation")
+	}
+	a := r.findAccountLocked("a1")
+	if a.APIKey != "old-key" {
+		t.Fatalf("live key changed despite failed probe: %q", a.APIKey)
+	}
+	if a.NextAPIKey != "bad-key" {
+		t.Fatal("staged key dropped; operator cannot retry or inspect")
+	}
+}
+
+func TestRotateCredentialGuards(t *testing.T) {
+	r := rotationRouter()
+	ok := func(context.Context, *models.Provider, string) error { return nil }
+
+	if err := r.RotateCredential(context.Background(), "ghost", ok); !errors.Is(err, ErrAccountNotFound) {
+		t.Fatalf("err = %v, want ErrAccountNotFound", err)
+	}
+	if err := r.RotateCredential(context.Background(), "a1", ok); !errors.Is(err, ErrNoStagedCredential) {
+		t.Fatalf("err = %v, want ErrNoStagedCredential", err)
+	}
+	if err := r.StageCredential("a1", ""); err == nil {
+		t.Fatal("empty credential staged")
+	}
+
+	// A re-stage during the probe must not commit the stale key.
+	if err := r.StageCredential("a1", "first"); err != nil {
+		t.Fatal(err)
+	}
+	restage := func(context.Context, *models.Provider, string) error {
+		return r.StageCredential("a1", "second")
+	}
+	if err := r.RotateCredential(context.Background(), "a1", restage); !errors.Is(err, ErrCredentialChanged) {
+		t.Fatalf("err = %v, want ErrCredentialChanged", err)
+	}
+	if a := r.findAccountLocked("a1"); a.APIKey != "old-key" || a.NextAPIKey != "second" {
+		t.Fatalf("account after conflict = %+v", a)
+	}
+}
+
+func TestDefaultCredentialProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			t.Fatalf("probe hit %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+
+	probe := DefaultCredentialProbe(srv.Client())
+	p := &models.Provider{ID: "p1", Name: "one", BaseURL: srv.URL + "/"}
+	if err := probe(context.Background(), p, "good"); err != nil {
+		t.Fatalf("valid key rejected: %v", err)
+	}
+	if err := probe(context.Background(), p, "bad"); err == nil {
+		t.Fatal("rejected key passed the probe")
+	}
+}
@@ -0,0 +1,342 @@
+// Package router picks the provider account that should serve a request,
+// based on alias targets, account health and rate limit availability.
+package router
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/ratelimit"
+)
+
+// ErrNoCandidate is returned when no enabled account can accept the request.
+var ErrNoCandidate = errors.New("router: no available provider account")
+
+// ErrVersionConflict is returned when an update carries a stale version;
+// the admin API maps it to 409 with the current state.
+var ErrVersionConflict = errors.New("router: provider modified concurrently")
+
+// ErrProviderNotFound is returned for updates against unknown providers.
+var ErrProviderNotFound = errors.New("router: provider not found")
+
+// ErrAliasNotFound is returned for updates against unknown aliases.
+var ErrAliasNotFound = errors.New("router: alias not found")
+
+// Candidate is a routable (account, model) pair the router selected.
+type Candidate struct {
+	Provider *models.Provider
+	Account  *models.Account
+	Model    string
+	// Weight is the alias target's routing weight (1 for concrete models).
+	Weight float64
+}
+
+// Router holds the registered providers/accounts and the shared
+// concurrency limiter consulted during candidate selection.
+type Router struct {
+	mu          sync.RWMutex
+	providers   map[string]*models.Provider
+	accounts    map[string][]*models.Account // providerID -> accounts
+	aliases     map[string]*models.ModelAlias
+	Concurrency *ratelimit.ConcurrencyLimiter
+	// Exclude, when set, removes providers from candidate selection by
+	// ID; the quarantine tracker plugs in here.
+	Exclude func(providerID string) bool
+	// Prior, when set, scores providers in [0,1]; Route tries higher
+	// scoring providers first. The reliability scorer plugs in here.
+	Prior func(providerID string) float64
+	// Rand, when set, orders candidates by weighted random draw before
+	// the Prior sort, so alias target weights shape traffic. Injecting a
+	// seeded source makes routing decisions reproducible in tests and
+	// what-if simulations; nil keeps the deterministic first-fit order.
+	Rand func() float64
+	// Observe, when set, receives one Decision per Route call; the
+	// decision log plugs in here. Leaving it nil skips all tracing work
+	// on the hot path.
+	Observe func(Decision)
+}
+
+// New returns an empty router with a fresh concurrency limiter.
+func New() *Router {
+	return &Router{
+		providers:   make(map[string]*models.Provider),
+		accounts:    make(map[string][]*models.Account),
+		aliases:     make(map[string]*models.ModelAlias),
+		Concurrency: ratelimit.NewConcurrencyLimiter(),
+	}
+}
+
+// AddProvider registers or replaces a provider. New providers start at
+// version 1.
+func (r *Router) AddProvider(p *models.Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p.Version == 0 {
+		p.Version = 1
+	}
+	r.providers[p.ID] = p
+}
+
+// GetProvider returns the provider with the given ID, or nil.
+func (r *Router) GetProvider(id string) *models.Provider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.providers[id]
+}
+
+// UpdateProvider replaces a provider only when expectedVersion matches
+// the stored version, bumping the version on success. On a mismatch it
+// returns ErrVersionConflict together with the current state so the
+// caller can surface what won.
+func (r *Router) UpdateProvider(p *models.Provider, expectedVersion int64) (*models.Provider, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current, ok := r.providers[p.ID]
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
+	if current.Version != expectedVersion {
+		return current, ErrVersionConflict
+	}
+	p.Version = current.Version + 1
+	p.CreatedAt = current.CreatedAt
+	p.UpdatedAt = time.Now()
+	r.providers[p.ID] = p
+	return p, nil
+}
+
+// SetProviderStatus changes a provider's status in place, without the
+// version bump of a full update; the trial manager uses it to move
+// providers between trial and active.
+func (r *Router) SetProviderStatus(id, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.providers[id]
+	if !ok {
+		return ErrProviderNotFound
+	}
+	p.Status = status
+	return nil
+}
+
+// AddAccount registers an account and syncs its concurrency cap with the
+// shared limiter.
+func (r *Router) AddAccount(a *models.Account) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.accounts[a.ProviderID] = append(r.accounts[a.ProviderID], a)
+	if a.RateLimit != nil {
+		r.Concurrency.SetLimit(a.ID, a.RateLimit.MaxConcurrent)
+	}
+}
+
+// Providers returns all registered providers.
+func (r *Router) Providers() []*models.Provider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]*models.Provider, 0, len(r.providers))
+	for _, p := range r.providers {
+		out = append(out, p)
+	}
+	return out
+}
+
+// SetAlias registers or replaces a model alias. New aliases start at
+// version 1.
+func (r *Router) SetAlias(alias *models.ModelAlias) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if alias.Version == 0 {
+		alias.Version = 1
+	}
+	r.aliases[alias.Alias] = alias
+}
+
+// GetAlias returns the alias with the given name, or nil.
+func (r *Router) GetAlias(name string) *models.ModelAlias {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.aliases[name]
+}
+
+// Aliases returns all registered aliases, sorted by name.
+func (r *Router) Aliases() []*models.ModelAlias {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]*models.ModelAlias, 0, len(r.aliases))
+	for _, a := range r.aliases {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Alias < out[j].Alias })
+	return out
+}
+
+// UpdateAlias replaces an alias only when expectedVersion matches the
+// stored version, bumping the version on success. It is the alias
+// counterpart of UpdateProvider and follows the same conflict contract.
+func (r *Router) UpdateAlias(alias *models.ModelAlias, expectedVersion int64) (*models.ModelAlias, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current, ok := r.aliases[alias.Alias]
+	if !ok {
+		return nil, ErrAliasNotFound
+	}
+	if current.Version != expectedVersion {
+		return current, ErrVersionConflict
+	}
+	alias.Version = current.Version + 1
+	r.aliases[alias.Alias] = alias
+	return alias, nil
+}
+
+// Candidates resolves a model name (alias or concrete) to the list of
+// accounts that could serve it right now. Accounts that are disabled or at
+// their concurrency cap are skipped, so a saturated account never shows up
+// as a routing option.
+func (r *Router) Candidates(model string) []Candidate {
+	out, _ := r.candidates(model, false)
+	return out
+}
+
+// candidates resolves the model's routing options; with trace set it
+// additionally reports, per provider that yielded no candidate, the
+// first reason it was filtered.
+func (r *Router) candidates(model string, trace bool) ([]Candidate, map[string]string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var filtered map[string]string
+	note := func(providerID, reason string) {
+		if !trace {
+			return
+		}
+		if filtered == nil {
+			filtered = make(map[string]string)
+		}
+		if _, seen := filtered[providerID]; !seen {
+			filtered[providerID] = reason
+		}
+	}
+
+	targets := r.resolveTargets(model)
+	// Weights are relative. An alias with no weights set splits evenly;
+	// once any target carries a weight, a zero-weight target is drained
+	// and yields no candidates (how gradual shifts park the old model).
+	weighted := false
+	for _, t := range targets {
+		if t.Weight > 0 {
+			weighted = true
+			break
+		}
+	}
+	var out []Candidate
+	for _, t := range targets {
+		weight := t.Weight
+		if !weighted {
+			weight = 1
+		}
+		if weight <= 0 {
+			note(t.ProviderID, "drained")
+			continue
+		}
+		p, ok := r.providers[t.ProviderID]
+		if !ok || !p.Enabled {
+			note(t.ProviderID, "disabled")
+			continue
+		}
+		if p.Status == models.ProviderStatusTrial {
+			note(t.ProviderID, "trial")
+			continue
+		}
+		if r.Exclude != nil && r.Exclude(t.ProviderID) {
+			note(t.ProviderID, "excluded")
+			continue
+		}
+		contributed := false
+		for _, a := range r.accounts[t.ProviderID] {
+			if !a.Enabled {
+				note(t.ProviderID, "account_disabled")
+				continue
+			}
+			if !r.Concurrency.Available(a.ID) {
+				note(t.ProviderID, "saturated")
+				continue
+			}
+			out = append(out, Candidate{Provider: p, Account: a, Model: t.Model, Weight: weight})
+			contributed = true
+		}
+		if contributed {
+			delete(filtered, t.ProviderID)
+		}
+	}
+	return out, filtered
+}
+
+// Route picks the first available candidate for the model and reserves its
+// concurrency slot. When a Prior is configured, candidates from more
+// reliable providers are tried first. The caller must call Release on the
+// returned candidate's account when the request finishes.
+func (r *Router) Route(model string) (*Candidate, error) {
+	trace := r.Observe != nil
+	candidates, filtered := r.candidates(model, trace)
+	if r.Rand != nil {
+		// Weighted sampling without replacement (Efraimidis–Spirakis):
+		// each candidate draws u^(1/w); sorting by the draw makes higher
+		// weights win proportionally more often.
+		keys := make(map[*models.Account]float64, len(candidates))
+		for _, c := range candidates {
+			keys[c.Account] = math.Pow(r.Rand(), 1/c.Weight)
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return keys[candidates[i].Account] > keys[candidates[j].Account]
+		})
+	}
+	if r.Prior != nil {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return r.Prior(candidates[i].Provider.ID) > r.Prior(candidates[j].Provider.ID)
+		})
+	}
+	for _, c := range candidates {
+		if r.Concurrency.TryAcquire(c.Account.ID) {
+			cand := c
+			if trace {
+				r.Observe(newDecision(model, cand.Provider.ID, candidates, filtered))
+			}
+			return &cand, nil
+		}
+	}
+	if trace {
+		r.Observe(newDecision(model, "", candidates, filtered))
+	}
+	return nil, ErrNoCandidate
+}
+
+// Release frees the concurrency slot held by a dispatched candidate.
+func (r *Router) Release(c *Candidate) {
+	if c != nil && c.Account != nil {
+		r.Concurrency.Release(c.Account.ID)
+	}
+}
+
+func (r *Router) resolveTargets(model string) []models.AliasTarget {
+	if alias, ok := r.aliases[model]; ok {
+		return alias.Targets
+	}
+	// Concrete model name: every provider is a potential target; adapters
+	// reject models they do not serve. Sorted so candidate order never
+	// depends on map iteration.
+	ids := make([]string, 0, len(r.providers))
+	for id := range r.providers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	ts := make([]models.AliasTarget, 0, len(ids))
+	for _, id := range ids {
+		ts = append(ts, models.AliasTarget{ProviderID: id, Model: model, Weight: 1})
+	}
+	return ts
+}
@@ -0,0 +1,121 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+func TestUpdateProviderVersionCheck(t *testing.T) {
+	r := New()
+	r.AddProvider(&models.Provider{ID: "p1", Name: "one", Enabled: true})
+	if got := r.GetProvider("p1").Version; got != 1 {
+		t.Fatalf("initial version = %d, want 1", got)
+	}
+
+	updated, err := r.UpdateProvider(&models.Provider{ID: "p1", Name: "renamed"}, 1)
+	if err != nil {
+		t.Fatalf("UpdateProvider: %v", err)
+	}
+	if updated.Version != 2 || updated.Name != "renamed" {
+		t.Fatalf("updated = %+v", updated)
+	}
+
+	// A writer still holding version 1 must get a conflict plus the
+	// current state, never a silent overwrite.
+	current, err := r.UpdateProvider(&models.Provider{ID: "p1", Name: "stale"}, 1)
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("err = %v, want ErrVersionConflict", err)
+	}
+	if current.Name != "renamed" {
+		t.Fatalf("conflict did not return current state: %+v", current)
+	}
+
+	if _, err := r.UpdateProvider(&models.Provider{ID: "ghost"}, 1); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("err = %v, want ErrProviderNotFound", err)
+	}
+}
+
+func BenchmarkRoute(b *testing.B) {
+	r := New()
+	targets := make([]models.AliasTarget, 0, 5)
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("p%d", i)
+		r.AddProvider(&models.Provider{ID: id, Enabled: true})
+		r.AddAccount(&models.Account{ID: id + "-a", ProviderID: id, Enabled: true})
+		targets = append(targets, models.AliasTarget{ProviderID: id, Model: "m", Weight: 1})
+	}
+	r.SetAlias(&models.ModelAlias{Alias: "bench", Targets: targets})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c, err := r.Route("bench")
+		if err != nil {
+			b.Fatal(err)
+		}
+		r.Release(c)
+	}
+}
+
+func TestSeededRoutingIsReproducible(t *testing.T) {
+	build := func(seed int64) *Router {
+		r := New()
+		r.Rand = rand.New(rand.NewSource(seed)).Float64
+		r.AddProvider(&models.Provider{ID: "p1", Enabled: true})
+		r.AddProvider(&models.Provider{ID: "p2", Enabled: true})
+		r.AddAccount(&models.Account{ID: "a1", ProviderID: "p1", Enabled: true})
+		r.AddAccount(&models.Account{ID: "a2", ProviderID: "p2", Enabled: true})
+		r.SetAlias(&models.ModelAlias{Alias: "m", Targets: []models.AliasTarget{
+			{ProviderID: "p1", Model: "m", Weight: 3},
+			{ProviderID: "p2", Model: "m", Weight: 1},
+		}})
+		return r
+	}
+
+	pick := func(r *Router) []string {
+		var seq []string
+		for i := 0; i < 50; i++ {
+			c, err := r.Route("m")
+			if err != nil {
+				t.Fatal(err)
+			}
+			seq = append(seq, c.Provider.ID)
+			r.Release(c)
+		}
+		return seq
+	}
+
+	first := pick(build(42))
+	second := pick(build(42))
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("decision %d differs: %s vs %s", i, first[i], second[i])
+		}
+	}
+	// The 3:1 weights must actually shape traffic.
+	heavy := 0
+	for _, id := range first {
+		if id == "p1" {
+			heavy++
+		}
+	}
+	if heavy < 25 {
+		t.Fatalf("p1 picked %d/50 times despite 3x weight", heavy)
+	}
+}
+
+func TestConcreteModelCandidatesAreOrdered(t *testing.T) {
+	r := New()
+	for _, id := range []string{"zeta", "alpha", "mid"} {
+		r.AddProvider(&models.Provider{ID: id, Enabled: true})
+		r.AddAccount(&models.Account{ID: id + "-a", ProviderID: id, Enabled: true})
+	}
+	for i := 0; i < 10; i++ {
+		got := r.Candidates("concrete-model")
+		if len(got) != 3 || got[0].Provider.ID != "alpha" || got[1].Provider.ID != "mid" || got[2].Provider.ID != "zeta" {
+			t.Fatalf("candidate order = %v", []string{got[0].Provider.ID, got[1].Provider.ID, got[2].Provider.ID})
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package safety scores completions for profanity and brand-safety
+// categories with a local term-list classifier, and aggregates the
+// scores into per-provider distributions. Free providers vary widely
+// in what they return; the reports let content teams audit that
+// without reading stored responses.
+package safety
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Brand-safety categories, loosely following the industry taxonomy.
+const (
+	CategoryProfanity  = "profanity"
+	CategoryViolence   = "violence"
+	CategoryAdult      = "adult"
+	CategorySubstances = "substances"
+	CategoryGambling   = "gambling"
+)
+
+// saturationHits is how many distinct term hits push a category score
+// to 1. A single stray word scores low; sustained content saturates.
+const saturationHits = 3
+
+// defaultTerms are the built-in category term lists. They are
+// deliberately coarse — this is a tripwire for auditing distributions,
+// not a moderation verdict on any single response.
+var defaultTerms = map[string][]string{
+	CategoryProfanity:  {"damn", "hell", "crap", "shit", "fuck", "bastard", "asshole"},
+	CategoryViolence:   {"kill", "murder", "shoot", "stab", "bomb", "massacre", "torture"},
+	CategoryAdult:      {"porn", "nude", "nsfw", "explicit", "erotic"},
+	CategorySubstances: {"cocaine", "heroin", "meth", "fentanyl", "overdose"},
+	CategoryGambling:   {"casino", "betting", "jackpot", "slot machine", "bookmaker"},
+}
+
+// Scorer classifies text against category term lists. The zero value
+// uses the built-in lists.
+type Scorer struct {
+	// Terms overrides the category lists when non-nil, so deployments
+	// can tune vocabulary per market.
+	Terms map[string][]string
+}
+
+// Score returns one [0,1] score per category with at least one hit;
+// clean text returns an empty map, which keeps RequestLog entries
+// small for the common case.
+func (s *Scorer) Score(text string) map[string]float64 {
+	terms := defaultTerms
+	if s != nil && s.Terms != nil {
+		terms = s.Terms
+	}
+	lowered := strings.ToLower(text)
+	scores := make(map[string]float64)
+	for category, words := range terms {
+		hits := 0
+		for _, w := range words {
+			if strings.Contains(lowered, w) {
+				hits++
+			}
+		}
+		if hits == 0 {
+			continue
+		}
+		score := float64(hits) / saturationHits
+		if score > 1 {
+			score = 1
+		}
+		scores[category] = score
+	}
+	return scores
+}
+
+// Distribution buckets. Scores land in exactly one bucket per
+// category; responses with no score for a category count as clean.
+const (
+	BucketClean  = "clean"
+	BucketLow    = "low"    // (0, 1/3]
+	BucketMedium = "medium" // (1/3, 2/3]
+	BucketHigh   = "high"   // (2/3, 1]
+)
+
+func bucket(score float64) string {
+	switch {
+	case score <= 0:
+		return BucketClean
+	case score <= 1.0/3:
+		return BucketLow
+	case score <= 2.0/3:
+		return BucketMedium
+	default:
+		return BucketHigh
+	}
+}
+
+// CategoryDistribution is one provider's score histogram for one
+// category.
+type CategoryDistribution struct {
+	Category string         `json:"category"`
+	Buckets  map[string]int `json:"buckets"`
+	Mean     float64        `json:"mean"`
+}
+
+// ProviderReport aggregates everything scored for one provider.
+type ProviderReport struct {
+	ProviderID string                 `json:"provider_id"`
+	Responses  int                    `json:"responses"`
+	Categories []CategoryDistribution `json:"categories"`
+}
+
+// Auditor accumulates scores per provider for distribution reports.
+type Auditor struct {
+	mu        sync.Mutex
+	responses map[string]int
+	sums      map[string]map[string]float64
+	buckets   map[string]map[string]map[string]int
+}
+
+// NewAuditor creates an empty auditor.
+func NewAuditor() *Auditor {
+	return &Auditor{
+		responses: make(map[string]int),
+		sums:      make(map[string]map[string]float64),
+		buckets:   make(map[string]map[string]map[string]int),
+	}
+}
+
+// Record adds one response's scores to the provider's distribution.
+// Categories absent from scores count toward the clean bucket, so the
+// report's bucket totals always sum to the response count.
+func (a *Auditor) Record(providerID string, scores map[string]float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.responses[providerID]++
+	if a.sums[providerID] == nil {
+		a.sums[providerID] = make(map[string]float64)
+		a.buckets[providerID] = make(map[string]map[string]int)
+	}
+	for category := range defaultTerms {
+		score := scores[category]
+		a.sums[providerID][category] += score
+		if a.buckets[providerID][category] == nil {
+			a.buckets[providerID][category] = make(map[string]int)
+		}
+		a.buckets[providerID][category][bucket(score)]++
+	}
+}
+
+// Report returns per-provider distributions, sorted by provider ID
+// with categories sorted by name.
+func (a *Auditor) Report() []ProviderReport {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ids := make([]string, 0, len(a.responses))
+	for id := range a.responses {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	out := make([]ProviderReport, 0, len(ids))
+	for _, id := range ids {
+		report := ProviderReport{ProviderID: id, Responses: a.responses[id]}
+		categories := make([]string, 0, len(a.sums[id]))
+		for category := range a.sums[id] {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		for _, category := range categories {
+			counts := make(map[string]int, len(a.buckets[id][category]))
+			for b, n := range a.buckets[id][category] {
+				counts[b] = n
+			}
+			report.Categories = append(report.Categories, CategoryDistribution{
+				Category: category,
+				Buckets:  counts,
+				Mean:     a.sums[id][category] / float64(a.responses[id]),
+			})
+		}
+		out = append(out, report)
+	}
+	return out
+}
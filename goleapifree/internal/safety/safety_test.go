@@ -0,0 +1,66 @@
+package safety
+
+import "testing"
+
+func TestScore(t *testing.T) {
+	var s Scorer
+	scores := s.Score("I will kill the process, damn it")
+	if scores[CategoryViolence] == 0 || scores[CategoryProfanity] == 0 {
+		t.Fatalf("scores = %v", scores)
+	}
+	if scores[CategoryViolence] > 1.0/3+0.001 {
+		t.Fatalf("single hit should score low, got %v", scores[CategoryViolence])
+	}
+	if len(s.Score("please summarize this document")) != 0 {
+		t.Fatal("clean text scored")
+	}
+
+	saturated := s.Score("kill murder shoot bomb massacre")
+	if saturated[CategoryViolence] != 1 {
+		t.Fatalf("saturated score = %v, want 1", saturated[CategoryViolence])
+	}
+}
+
+func TestScorerOverride(t *testing.T) {
+	s := Scorer{Terms: map[string][]string{"competitors": {"acme"}}}
+	scores := s.Score("switch to Acme today")
+	if scores["competitors"] == 0 {
+		t.Fatalf("override list ignored: %v", scores)
+	}
+	if scores[CategoryProfanity] != 0 {
+		t.Fatal("default lists leaked through an override")
+	}
+}
+
+func TestAuditorDistribution(t *testing.T) {
+	var s Scorer
+	a := NewAuditor()
+	a.Record("openai", s.Score("summarize the quarterly report"))
+	a.Record("openai", s.Score("damn shit fuck"))
+	a.Record("shady", s.Score("kill murder shoot bomb"))
+
+	reports := a.Report()
+	if len(reports) != 2 || reports[0].ProviderID != "openai" || reports[1].ProviderID != "shady" {
+		t.Fatalf("reports = %+v", reports)
+	}
+	if reports[0].Responses != 2 {
+		t.Fatalf("responses = %d", reports[0].Responses)
+	}
+	for _, cat := range reports[0].Categories {
+		total := 0
+		for _, n := range cat.Buckets {
+			total += n
+		}
+		if total != 2 {
+			t.Fatalf("%s buckets do not cover all responses: %v", cat.Category, cat.Buckets)
+		}
+		if cat.Category == CategoryProfanity {
+			if cat.Buckets[BucketClean] != 1 || cat.Buckets[BucketHigh] != 1 {
+				t.Fatalf("profanity buckets = %v", cat.Buckets)
+			}
+			if cat.Mean != 0.5 {
+				t.Fatalf("profanity mean = %v", cat.Mean)
+			}
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Package scheduler replaces the ad-hoc tickers spread around the codebase
+// with a single cron-based job registry. Jobs are registered once at
+// startup; operators can list, trigger and pause them through the admin API.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// historyLen is how many past runs are kept per job.
+const historyLen = 20
+
+// LeaderGate answers whether this instance should run cluster-singleton
+// jobs. Single-node deployments use AlwaysLeader; clustered deployments
+// plug in a lock-based implementation.
+type LeaderGate interface {
+	IsLeader() bool
+}
+
+// AlwaysLeader is the single-node LeaderGate.
+type AlwaysLeader struct{}
+
+// IsLeader implements LeaderGate.
+func (AlwaysLeader) IsLeader() bool { return true }
+
+// JobFunc is the body of a scheduled job.
+type JobFunc func(ctx context.Context) error
+
+// Run records one execution of a job.
+type Run struct {
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Err       string        `json:"error,omitempty"`
+	Trigger   string        `json:"trigger"` // "cron" or "manual"
+}
+
+// Job is a registered scheduled task.
+type Job struct {
+	Name string `json:"name"`
+	// Spec is the cron expression (robfig/cron v3 syntax).
+	Spec string `json:"spec"`
+	// Jitter delays each cron-triggered run by up to this duration, so
+	// many instances starting together do not stampede shared resources.
+	Jitter time.Duration `json:"jitter"`
+	// Singleton jobs run only on the cluster leader.
+	Singleton bool `json:"singleton"`
+
+	fn      JobFunc
+	entryID cron.EntryID
+
+	mu      sync.Mutex
+	paused  bool
+	running bool
+	history []Run
+}
+
+// JobStatus is the admin-facing view of a job.
+type JobStatus struct {
+	Name      string    `json:"name"`
+	Spec      string    `json:"spec"`
+	Singleton bool      `json:"singleton"`
+	Paused    bool      `json:"paused"`
+	Running   bool      `json:"running"`
+	NextRun   time.Time `json:"next_run"`
+	History   []Run     `json:"history"`
+}
+
+// Scheduler owns the cron engine and the job registry.
+type Scheduler struct {
+	mu     sync.RWMutex
+	cron   *cron.Cron
+	jobs   map[string]*Job
+	leader LeaderGate
+	ctx    context.Context
+	cancel context.CancelFunc
+	// randInt63n draws the jitter delay; injectable for reproducible runs.
+	randInt63n func(int64) int64
+}
+
+// New creates a stopped scheduler; call Start after registering jobs.
+func New(leader LeaderGate) *Scheduler {
+	if leader == nil {
+		leader = AlwaysLeader{}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		cron:       cron.New(),
+		jobs:       make(map[string]*Job),
+		leader:     leader,
+		ctx:        ctx,
+		cancel:     cancel,
+		randInt63n: rand.Int63n,
+	}
+}
+
+// Register adds a job to the registry and schedules it. Registering a
+// duplicate name or an invalid cron spec is a programming error surfaced
+// at startup.
+func (s *Scheduler) Register(name, spec string, opts Options, fn JobFunc) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("scheduler: job %q already registered", name)
+	}
+	job := &Job{Name: name, Spec: spec, Jitter: opts.Jitter, Singleton: opts.Singleton, fn: fn}
+	id, err := s.cron.AddFunc(spec, func() { s.execute(job, "cron") })
+	if err != nil {
+		return fmt.Errorf("scheduler: job %q spec %q: %w", name, spec, err)
+	}
+	job.entryID = id
+	s.jobs[name] = job
+	return nil
+}
+
+// Options tunes a job's scheduling behaviour.
+type Options struct {
+	Jitter    time.Duration
+	Singleton bool
+}
+
+// Start begins cron evaluation.
+func (s *Scheduler) Start() { s.cron.Start() }
+
+// Stop halts cron evaluation and cancels in-flight job contexts.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	<-s.cron.Stop().Done()
+}
+
+// Jobs returns the status of every registered job, for the admin API.
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		out = append(out, s.status(j))
+	}
+	return out
+}
+
+// Trigger runs a job immediately regardless of its schedule or pause
+// state. It is the admin "run now" action and ignores jitter.
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.RLock()
+	job, ok := s.jobs[name]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("scheduler: unknown job %q", name)
+	}
+	go s.run(job, "manual")
+	return nil
+}
+
+// SetPaused pauses or resumes cron-triggered runs of a job.
+func (s *Scheduler) SetPaused(name string, paused bool) error {
+	s.mu.RLock()
+	job, ok := s.jobs[name]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("scheduler: unknown job %q", name)
+	}
+	job.mu.Lock()
+	job.paused = paused
+	job.mu.Unlock()
+	return nil
+}
+
+// execute is the cron entry point: it applies pause, leadership and jitter
+// before running the job body.
+func (s *Scheduler) execute(job *Job, trigger string) {
+	job.mu.Lock()
+	paused := job.paused
+	job.mu.Unlock()
+	if paused {
+		return
+	}
+	if job.Singleton && !s.leader.IsLeader() {
+		return
+	}
+	if job.Jitter > 0 {
+		select {
+		case <-time.After(time.Duration(s.randInt63n(int64(job.Jitter)))):
+		case <-s.ctx.Done():
+			return
+		}
+	}
+	s.run(job, trigger)
+}
+
+// run executes the job body once and records the outcome.
+func (s *Scheduler) run(job *Job, trigger string) {
+	job.mu.Lock()
+	if job.running {
+		job.mu.Unlock()
+		return // overlapping runs are skipped, matching the old tickers
+	}
+	job.running = true
+	job.mu.Unlock()
+
+	start := time.Now()
+	err := job.fn(s.ctx)
+	if s.ctx.Err() != nil && err == nil {
+		err = errors.New("cancelled during shutdown")
+	}
+
+	run := Run{StartedAt: start, Duration: time.Since(start), Trigger: trigger}
+	if err != nil {
+		run.Err = err.Error()
+	}
+	job.mu.Lock()
+	job.running = false
+	job.history = append(job.history, run)
+	if len(job.history) > historyLen {
+		job.history = job.history[len(job.history)-historyLen:]
+	}
+	job.mu.Unlock()
+}
+
+// status snapshots one job. Caller holds s.mu (read).
+func (s *Scheduler) status(j *Job) JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	st := JobStatus{
+		Name:      j.Name,
+		Spec:      j.Spec,
+		Singleton: j.Singleton,
+		Paused:    j.paused,
+		Running:   j.running,
+		History:   append([]Run(nil), j.history...),
+	}
+	if entry := s.cron.Entry(j.entryID); entry.ID != 0 {
+		st.NextRun = entry.Next
+	}
+	return st
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTriggerRecordsHistory(t *testing.T) {
+	s := New(nil)
+	var calls atomic.Int32
+	err := s.Register("noop", "@hourly", Options{}, func(context.Context) error {
+		calls.Add(1)
+		return errors.New("boom")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Trigger("noop"); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for calls.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want 1", calls.Load())
+	}
+
+	jobs := s.Jobs()
+	if len(jobs) != 1 {
+		t.Fatalf("jobs = %d, want 1", len(jobs))
+	}
+	// run() updates history after fn returns; poll briefly.
+	for time.Now().Before(deadline) && len(s.Jobs()[0].History) == 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	hist := s.Jobs()[0].History
+	if len(hist) != 1 || hist[0].Err != "boom" || hist[0].Trigger != "manual" {
+		t.Fatalf("unexpected history %+v", hist)
+	}
+}
+
+func TestRegisterRejectsDuplicatesAndBadSpecs(t *testing.T) {
+	s := New(nil)
+	ok := func(context.Context) error { return nil }
+	if err := s.Register("a", "@daily", Options{}, ok); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Register("a", "@daily", Options{}, ok); err == nil {
+		t.Fatal("duplicate registration should fail")
+	}
+	if err := s.Register("b", "not a cron spec", Options{}, ok); err == nil {
+		t.Fatal("invalid spec should fail")
+	}
+}
+
+func TestSingletonSkipsNonLeader(t *testing.T) {
+	s := New(notLeader{})
+	var calls atomic.Int32
+	_ = s.Register("single", "@hourly", Options{Singleton: true}, func(context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+	s.execute(s.jobs["single"], "cron")
+	if calls.Load() != 0 {
+		t.Fatal("singleton job ran on non-leader")
+	}
+}
+
+type notLeader struct{}
+
+func (notLeader) IsLeader() bool { return false }
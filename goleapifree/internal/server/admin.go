@@ -0,0 +1,1031 @@
+// Package server hosts the gateway's HTTP surfaces. The admin app exposes
+// operational endpoints (stats, scheduling, configuration) separately from
+// the OpenAI-compatible proxy listener.
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/biodoia/goleapifree/internal/agents"
+	"github.com/biodoia/goleapifree/internal/apierror"
+	"github.com/biodoia/goleapifree/internal/configdiff"
+	"github.com/biodoia/goleapifree/internal/datasets"
+	"github.com/biodoia/goleapifree/internal/discovery"
+	"github.com/biodoia/goleapifree/internal/envelope"
+	"github.com/biodoia/goleapifree/internal/export"
+	"github.com/biodoia/goleapifree/internal/incidents"
+	"github.com/biodoia/goleapifree/internal/invalidation"
+	"github.com/biodoia/goleapifree/internal/lifecycle"
+	"github.com/biodoia/goleapifree/internal/lru"
+	"github.com/biodoia/goleapifree/internal/maintenance"
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/notify"
+	"github.com/biodoia/goleapifree/internal/playground"
+	"github.com/biodoia/goleapifree/internal/policy"
+	"github.com/biodoia/goleapifree/internal/privacy"
+	"github.com/biodoia/goleapifree/internal/ratelimit"
+	"github.com/biodoia/goleapifree/internal/router"
+	"github.com/biodoia/goleapifree/internal/safety"
+	"github.com/biodoia/goleapifree/internal/scheduler"
+	"github.com/biodoia/goleapifree/internal/simulate"
+	"github.com/biodoia/goleapifree/internal/stats"
+	"github.com/biodoia/goleapifree/internal/statuswatch"
+)
+
+// Admin bundles the subsystems the admin API reads from and mutates.
+type Admin struct {
+	app *fiber.App
+	// readOnly rejects every mutating admin operation while set, so
+	// migrations and restores can run without config churn underneath
+	// them. Completions on the public app are unaffected.
+	readOnly   atomic.Bool
+	Planner    *stats.CapacityPlanner
+	Analyzer   *stats.Analyzer
+	Quotas     map[string]int64
+	Aliases    []models.ModelAlias
+	ProvName   map[string]string // provider ID -> display name
+	Scheduler  *scheduler.Scheduler
+	Router     *router.Router
+	AliasStore *router.AliasStore
+	// CredProbe verifies staged credentials before a rotation commits;
+	// nil falls back to router.DefaultCredentialProbe.
+	CredProbe   router.CredentialProbe
+	Loans       *ratelimit.LoanBook
+	Decisions   *router.DecisionLog
+	Discovery   *discovery.Registry
+	Maintenance *maintenance.Manager
+	Agents      *agents.ConfigRegistry
+	Datasets    *datasets.Store
+	Playground  *playground.Store
+	Privacy     *privacy.Coordinator
+	Exports     *privacy.ExportManager
+	Consents    *policy.ConsentLedger
+	Slack       *notify.Slack
+	Incidents   *incidents.Manager
+	Safety      *safety.Auditor
+	Lifecycle   *lifecycle.Sweeper
+	// Simulate injects staging-parity constraints into provider
+	// transports; nil outside staging deployments.
+	Simulate    *simulate.Injector
+	StatusWatch *statuswatch.Watcher
+	Keys        *envelope.Keyring
+	// Invalidation notifies other replicas after config mutations so
+	// their in-memory state does not go stale; nil in single-replica
+	// deployments.
+	Invalidation invalidation.Bus
+}
+
+// invalidate publishes one invalidation event, best-effort: the local
+// update already succeeded and must not be rolled back over a bus
+// hiccup.
+func (a *Admin) invalidate(c *fiber.Ctx, kind, key string) {
+	if a.Invalidation == nil {
+		return
+	}
+	_ = a.Invalidation.Publish(c.Context(), invalidation.Event{Kind: kind, Key: key})
+}
+
+// NewAdmin builds the admin Fiber app and registers its routes.
+func NewAdmin() *Admin {
+	a := &Admin{
+		app: fiber.New(fiber.Config{
+			DisableStartupMessage: true,
+			ErrorHandler:          apierror.Handler,
+		}),
+		Quotas:   make(map[string]int64),
+		ProvName: make(map[string]string),
+	}
+	a.routes()
+	return a
+}
+
+// App exposes the underlying Fiber app for listening and for tests.
+func (a *Admin) App() *fiber.App { return a.app }
+
+// Listen serves the admin API on addr.
+func (a *Admin) Listen(addr string) error { return a.app.Listen(addr) }
+
+// SetReadOnly toggles read-only mode; also reachable at runtime through
+// the admin endpoints below.
+func (a *Admin) SetReadOnly(on bool) { a.readOnly.Store(on) }
+
+// ReadOnly reports whether read-only mode is active.
+func (a *Admin) ReadOnly() bool { return a.readOnly.Load() }
+
+func (a *Admin) routes() {
+	admin := a.app.Group("/admin")
+	admin.Use(a.rejectWritesWhenReadOnly)
+	admin.Get("/readonly", a.handleReadOnlyStatus)
+	admin.Post("/readonly/enable", a.handleReadOnlySet(true))
+	admin.Post("/readonly/disable", a.handleReadOnlySet(false))
+	admin.Get("/stats/capacity", a.handleCapacity)
+	admin.Get("/stats/compare", a.handleStatsCompare)
+	admin.Get("/memory", a.handleMemory)
+	admin.Get("/providers", a.handleProviders)
+	admin.Put("/providers/:id", a.handleProviderUpdate)
+	admin.Post("/accounts/:id/credential", a.handleCredentialStage)
+	admin.Post("/accounts/:id/rotate", a.handleCredentialRotate)
+	admin.Get("/aliases", a.handleAliases)
+	admin.Get("/aliases/shifts", a.handleAliasShifts)
+	admin.Put("/aliases/:name", a.handleAliasUpdate)
+	admin.Post("/aliases/:name/shift", a.handleAliasShiftSchedule)
+	admin.Get("/routing/decisions", a.handleRoutingDecisions)
+	admin.Get("/limits/loans", a.handleLoansList)
+	admin.Post("/limits/:key/loan", a.handleLoanGrant)
+	admin.Delete("/limits/:key/loan", a.handleLoanRevoke)
+	admin.Get("/discovery", a.handleDiscovery)
+	admin.Get("/jobs", a.handleJobsList)
+	admin.Post("/jobs/:name/trigger", a.handleJobTrigger)
+	admin.Post("/jobs/:name/pause", a.handleJobPause(true))
+	admin.Post("/jobs/:name/resume", a.handleJobPause(false))
+	admin.Get("/agents", a.handleAgentsList)
+	admin.Put("/agents/:name", a.handleAgentUpdate)
+	admin.Get("/maintenance", a.handleMaintenanceStatus)
+	admin.Post("/maintenance/:op", a.handleMaintenanceRun)
+	admin.Get("/datasets", a.handleDatasetsList)
+	admin.Get("/datasets/:name/versions", a.handleDatasetVersions)
+	admin.Post("/datasets/:name", a.handleDatasetUpload)
+	admin.Get("/playground/:id/export", a.handleSessionExport)
+	admin.Post("/users/:id/erase", a.handleUserErase)
+	admin.Post("/users/:id/export", a.handleUserExport)
+	admin.Get("/exports/:id", a.handleExportStatus)
+	admin.Post("/consents", a.handleConsentRecord)
+	admin.Get("/consents/:user", a.handleConsentList)
+	admin.Get("/incidents", a.handleIncidentsList)
+	admin.Get("/status/incidents", a.handleStatusIncidents)
+	admin.Get("/incidents/:id", a.handleIncidentGet)
+	admin.Post("/incidents/:id/notes", a.handleIncidentNote)
+	admin.Post("/incidents/:id/resolve", a.handleIncidentResolve)
+	admin.Get("/simulate", a.handleSimulateList)
+	admin.Put("/simulate/:provider", a.handleSimulateSet)
+	admin.Delete("/simulate/:provider", a.handleSimulateClear)
+	admin.Get("/lifecycle/batches", a.handleLifecycleBatches)
+	admin.Post("/lifecycle/batches/:id/approve", a.handleLifecycleDecision(true))
+	admin.Post("/lifecycle/batches/:id/reject", a.handleLifecycleDecision(false))
+	admin.Get("/config/snapshot", a.handleConfigSnapshot)
+	admin.Post("/config/diff", a.handleConfigDiff)
+	admin.Get("/safety", a.handleSafetyReport)
+	admin.Get("/tenants/:tenant/keys", a.handleTenantKeys)
+	admin.Post("/tenants/:tenant/keys/rotate", a.handleTenantKeyRotate)
+	admin.Post("/keys/master/rotate", a.handleMasterKeyRotate)
+
+	// Slack posts signed requests from outside, so these live off the
+	// /admin group and do their own verification.
+	a.app.Post("/integrations/slack/command", a.handleSlackCommand)
+	a.app.Post("/integrations/slack/interact", a.handleSlackInteraction)
+	// Provider status pages deliver incident webhooks here; also off the
+	// /admin group, since the sender is external.
+	a.app.Post("/integrations/status/:provider", a.handleStatusWebhook)
+}
+
+// rejectWritesWhenReadOnly blocks mutating verbs while read-only mode is
+// active. The toggle endpoints themselves stay reachable so an operator
+// can always turn the mode back off.
+func (a *Admin) rejectWritesWhenReadOnly(c *fiber.Ctx) error {
+	if !a.readOnly.Load() || c.Method() == fiber.MethodGet {
+		return c.Next()
+	}
+	if strings.HasPrefix(c.Path(), "/admin/readonly/") {
+		return c.Next()
+	}
+	return apierror.New(apierror.CodeForbidden, "gateway is in read-only mode")
+}
+
+func (a *Admin) handleReadOnlyStatus(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"read_only": a.readOnly.Load()})
+}
+
+func (a *Admin) handleReadOnlySet(on bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		a.readOnly.Store(on)
+		return c.JSON(fiber.Map{"read_only": on})
+	}
+}
+
+func (a *Admin) handleProviders(c *fiber.Ctx) error {
+	if a.Router == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "router not running")
+	}
+	providers := a.Router.Providers()
+	if providers == nil {
+		providers = []*models.Provider{}
+	}
+	return c.JSON(providers)
+}
+
+// handleProviderUpdate replaces a provider with optimistic concurrency:
+// the expected version comes from If-Match or the body's version field,
+// and a stale version gets 409 with the current state so concurrent
+// edits never silently overwrite each other.
+func (a *Admin) handleProviderUpdate(c *fiber.Ctx) error {
+	if a.Router == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "router not running")
+	}
+	var p models.Provider
+	if err := c.BodyParser(&p); err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, "invalid provider payload")
+	}
+	p.ID = c.Params("id")
+
+	expected := p.Version
+	if etag := c.Get(fiber.HeaderIfMatch); etag != "" {
+		v, err := strconv.ParseInt(strings.Trim(etag, `"`), 10, 64)
+		if err != nil {
+			return apierror.New(apierror.CodeInvalidRequest, "If-Match must be a version number")
+		}
+		expected = v
+	}
+	if expected == 0 {
+		return apierror.New(apierror.CodeInvalidRequest, "update requires If-Match or a version field")
+	}
+
+	updated, err := a.Router.UpdateProvider(&p, expected)
+	switch {
+	case errors.Is(err, router.ErrProviderNotFound):
+		return apierror.New(apierror.CodeNotFound, "provider not found")
+	case errors.Is(err, router.ErrVersionConflict):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": fiber.Map{
+				"code":    "conflict",
+				"message": "provider was modified concurrently; re-fetch and retry",
+			},
+			"current": updated,
+		})
+	case err != nil:
+		return err
+	}
+	a.invalidate(c, invalidation.KindProvider, updated.ID)
+	c.Set(fiber.HeaderETag, strconv.FormatInt(updated.Version, 10))
+	return c.JSON(updated)
+}
+
+// handleCredentialStage attaches a replacement API key to an account.
+// The key carries no traffic until /rotate verifies and commits it.
+func (a *Admin) handleCredentialStage(c *fiber.Ctx) error {
+	if a.Router == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "router not running")
+	}
+	var req struct {
+		APIKey string `json:"api_key"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, "invalid credential payload")
+	}
+	err := a.Router.StageCredential(c.Params("id"), req.APIKey)
+	switch {
+	case errors.Is(err, router.ErrAccountNotFound):
+		return apierror.New(apierror.CodeNotFound, "account not found")
+	case err != nil:
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+// handleCredentialRotate probes the staged key and atomically switches
+// traffic to it when the probe passes; the old key serves until then.
+func (a *Admin) handleCredentialRotate(c *fiber.Ctx) error {
+	if a.Router == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "router not running")
+	}
+	probe := a.CredProbe
+	if probe == nil {
+		probe = router.DefaultCredentialProbe(nil)
+	}
+	err := a.Router.RotateCredential(c.Context(), c.Params("id"), probe)
+	switch {
+	case errors.Is(err, router.ErrAccountNotFound):
+		return apierror.New(apierror.CodeNotFound, "account not found")
+	case errors.Is(err, router.ErrNoStagedCredential):
+		return apierror.New(apierror.CodeInvalidRequest, "no credential staged for this account")
+	case errors.Is(err, router.ErrCredentialChanged):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": fiber.Map{
+				"code":    "conflict",
+				"message": "staged credential changed during verification; retry",
+			},
+		})
+	case err != nil:
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": fiber.Map{
+				"code":    "probe_failed",
+				"message": err.Error(),
+			},
+		})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (a *Admin) handleAliases(c *fiber.Ctx) error {
+	if a.Router == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "router not running")
+	}
+	aliases := a.Router.Aliases()
+	if aliases == nil {
+		aliases = []*models.ModelAlias{}
+	}
+	return c.JSON(aliases)
+}
+
+// handleAliasUpdate replaces an alias (targets, weights, fallback order)
+// at runtime with the same optimistic-concurrency contract as provider
+// updates; the change is persisted so it survives restarts.
+func (a *Admin) handleAliasUpdate(c *fiber.Ctx) error {
+	if a.AliasStore == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "alias store not configured")
+	}
+	var alias models.ModelAlias
+	if err := c.BodyParser(&alias); err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, "invalid alias payload")
+	}
+	alias.Alias = c.Params("name")
+
+	expected := alias.Version
+	if etag := c.Get(fiber.HeaderIfMatch); etag != "" {
+		v, err := strconv.ParseInt(strings.Trim(etag, `"`), 10, 64)
+		if err != nil {
+			return apierror.New(apierror.CodeInvalidRequest, "If-Match must be a version number")
+		}
+		expected = v
+	}
+	if expected == 0 {
+		return apierror.New(apierror.CodeInvalidRequest, "update requires If-Match or a version field")
+	}
+
+	updated, err := a.AliasStore.Update(&alias, expected)
+	switch {
+	case errors.Is(err, router.ErrAliasNotFound):
+		return apierror.New(apierror.CodeNotFound, "alias not found")
+	case errors.Is(err, router.ErrVersionConflict):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": fiber.Map{
+				"code":    "conflict",
+				"message": "alias was modified concurrently; re-fetch and retry",
+			},
+			"current": updated,
+		})
+	case err != nil:
+		return err
+	}
+	a.invalidate(c, invalidation.KindAlias, updated.Alias)
+	c.Set(fiber.HeaderETag, strconv.FormatInt(updated.Version, 10))
+	return c.JSON(updated)
+}
+
+func (a *Admin) handleAliasShifts(c *fiber.Ctx) error {
+	if a.AliasStore == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "alias store not configured")
+	}
+	return c.JSON(a.AliasStore.Shifts())
+}
+
+// handleAliasShiftSchedule enqueues a gradual weight shift, e.g. "move
+// 10% per hour to the new model" as step_percent 10 / interval "1h" /
+// target_percent 100. The scheduler's shift job applies the steps.
+func (a *Admin) handleAliasShiftSchedule(c *fiber.Ctx) error {
+	if a.AliasStore == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "alias store not configured")
+	}
+	var req struct {
+		ProviderID    string  `json:"provider_id"`
+		Model         string  `json:"model"`
+		StepPercent   float64 `json:"step_percent"`
+		TargetPercent float64 `json:"target_percent"`
+		Interval      string  `json:"interval"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, "invalid shift payload")
+	}
+	interval, err := time.ParseDuration(req.Interval)
+	if err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, "interval must be a duration like 1h")
+	}
+	shift, err := a.AliasStore.Schedule(router.WeightShift{
+		Alias:         c.Params("name"),
+		ProviderID:    req.ProviderID,
+		Model:         req.Model,
+		StepPercent:   req.StepPercent,
+		TargetPercent: req.TargetPercent,
+		Interval:      interval,
+	})
+	switch {
+	case errors.Is(err, router.ErrAliasNotFound):
+		return apierror.New(apierror.CodeNotFound, "alias not found")
+	case err != nil:
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	return c.Status(fiber.StatusCreated).JSON(shift)
+}
+
+// handleRoutingDecisions aggregates recent routing decisions (wins,
+// losses and filter reasons per provider) over ?window=168h.
+func (a *Admin) handleRoutingDecisions(c *fiber.Ctx) error {
+	if a.Decisions == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "decision log not configured")
+	}
+	var window time.Duration
+	if w := c.Query("window"); w != "" {
+		parsed, err := time.ParseDuration(w)
+		if err != nil || parsed <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid window")
+		}
+		window = parsed
+	}
+	return c.JSON(a.Decisions.Report(window))
+}
+
+func (a *Admin) handleLoansList(c *fiber.Ctx) error {
+	if a.Loans == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "loan book not configured")
+	}
+	return c.JSON(a.Loans.Loans())
+}
+
+// handleLoanGrant issues a burst loan (extra units per window, repaid
+// after expiry through a reduced limit) or, with exempt=true, a full
+// temporary exemption for a planned traffic spike.
+func (a *Admin) handleLoanGrant(c *fiber.Ctx) error {
+	if a.Loans == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "loan book not configured")
+	}
+	var req struct {
+		Extra    int    `json:"extra"`
+		Duration string `json:"duration"`
+		Exempt   bool   `json:"exempt"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, "invalid loan payload")
+	}
+	d, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, "duration must be a duration like 2h")
+	}
+	var loan *ratelimit.Loan
+	if req.Exempt {
+		loan, err = a.Loans.Exempt(c.Params("key"), d)
+	} else {
+		loan, err = a.Loans.Grant(c.Params("key"), req.Extra, d)
+	}
+	switch {
+	case errors.Is(err, ratelimit.ErrLoanActive):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": fiber.Map{
+				"code":    "conflict",
+				"message": "key already has an active loan; revoke it first",
+			},
+		})
+	case err != nil:
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	return c.Status(fiber.StatusCreated).JSON(loan)
+}
+
+func (a *Admin) handleLoanRevoke(c *fiber.Ctx) error {
+	if a.Loans == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "loan book not configured")
+	}
+	a.Loans.Revoke(c.Params("key"))
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (a *Admin) handleDiscovery(c *fiber.Ctx) error {
+	if a.Discovery == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "discovery not running")
+	}
+	return c.JSON(a.Discovery.List())
+}
+
+func (a *Admin) handleJobsList(c *fiber.Ctx) error {
+	if a.Scheduler == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "scheduler not running")
+	}
+	return c.JSON(a.Scheduler.Jobs())
+}
+
+func (a *Admin) handleJobTrigger(c *fiber.Ctx) error {
+	if a.Scheduler == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "scheduler not running")
+	}
+	if err := a.Scheduler.Trigger(c.Params("name")); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+func (a *Admin) handleJobPause(paused bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if a.Scheduler == nil {
+			return fiber.NewError(fiber.StatusServiceUnavailable, "scheduler not running")
+		}
+		if err := a.Scheduler.SetPaused(c.Params("name"), paused); err != nil {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (a *Admin) handleAgentsList(c *fiber.Ctx) error {
+	if a.Agents == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "agents not configured")
+	}
+	return c.JSON(a.Agents.List())
+}
+
+func (a *Admin) handleAgentUpdate(c *fiber.Ctx) error {
+	if a.Agents == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "agents not configured")
+	}
+	var cfg agents.Config
+	if err := c.BodyParser(&cfg); err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, "invalid agent config payload")
+	}
+	cfg.Name = c.Params("name")
+	applied, err := a.Agents.Apply(c.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	return c.JSON(applied)
+}
+
+func (a *Admin) handleMaintenanceStatus(c *fiber.Ctx) error {
+	if a.Maintenance == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "maintenance not configured")
+	}
+	return c.JSON(a.Maintenance.GetStatus())
+}
+
+func (a *Admin) handleMaintenanceRun(c *fiber.Ctx) error {
+	if a.Maintenance == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "maintenance not configured")
+	}
+	err := a.Maintenance.Run(c.Context(), c.Params("op"))
+	switch {
+	case errors.Is(err, maintenance.ErrUnknownOperation):
+		return apierror.New(apierror.CodeNotFound, err.Error())
+	case errors.Is(err, maintenance.ErrBusy):
+		return c.Status(fiber.StatusConflict).JSON(a.Maintenance.GetStatus())
+	case err != nil:
+		return err
+	}
+	return c.Status(fiber.StatusAccepted).JSON(a.Maintenance.GetStatus())
+}
+
+func (a *Admin) handleCapacity(c *fiber.Ctx) error {
+	if a.Planner == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "stats subsystem not running")
+	}
+	return c.JSON(a.Planner.Report(a.Quotas, a.Aliases, a.ProvName))
+}
+
+// handleStatsCompare contrasts two RFC3339 time ranges
+// (?a_from=&a_to=&b_from=&b_to=) across providers with computed deltas.
+func (a *Admin) handleStatsCompare(c *fiber.Ctx) error {
+	if a.Analyzer == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "stats subsystem not running")
+	}
+	parseRange := func(fromKey, toKey string) (stats.Range, error) {
+		from, err := time.Parse(time.RFC3339, c.Query(fromKey))
+		if err != nil {
+			return stats.Range{}, fmt.Errorf("%s must be RFC3339", fromKey)
+		}
+		to, err := time.Parse(time.RFC3339, c.Query(toKey))
+		if err != nil {
+			return stats.Range{}, fmt.Errorf("%s must be RFC3339", toKey)
+		}
+		if !to.After(from) {
+			return stats.Range{}, fmt.Errorf("%s must be after %s", toKey, fromKey)
+		}
+		return stats.Range{From: from, To: to}, nil
+	}
+	ra, err := parseRange("a_from", "a_to")
+	if err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	rb, err := parseRange("b_from", "b_to")
+	if err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	return c.JSON(a.Analyzer.Compare(ra, rb))
+}
+
+func (a *Admin) handleDatasetsList(c *fiber.Ctx) error {
+	if a.Datasets == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "datasets not configured")
+	}
+	return c.JSON(a.Datasets.List())
+}
+
+func (a *Admin) handleDatasetVersions(c *fiber.Ctx) error {
+	if a.Datasets == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "datasets not configured")
+	}
+	versions, err := a.Datasets.Versions(c.Params("name"))
+	if errors.Is(err, datasets.ErrNotFound) {
+		return apierror.New(apierror.CodeNotFound, "dataset not found")
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(versions)
+}
+
+// handleDatasetUpload accepts a raw JSONL body and stores it as the next
+// version of the named dataset.
+func (a *Admin) handleDatasetUpload(c *fiber.Ctx) error {
+	if a.Datasets == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "datasets not configured")
+	}
+	d, err := a.Datasets.Upload(c.Params("name"), bytes.NewReader(c.Body()))
+	if err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	return c.Status(fiber.StatusCreated).JSON(d)
+}
+
+// handleSessionExport downloads a recorded playground session as
+// Markdown or an .ipynb notebook (?format=markdown|notebook).
+func (a *Admin) handleSessionExport(c *fiber.Ctx) error {
+	if a.Playground == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "playground not configured")
+	}
+	sess, err := a.Playground.Get(c.Params("id"))
+	if errors.Is(err, playground.ErrNotFound) {
+		return apierror.New(apierror.CodeNotFound, "session not found")
+	}
+	if err != nil {
+		return err
+	}
+	switch c.Query("format", "markdown") {
+	case "markdown":
+		c.Set(fiber.HeaderContentType, "text/markdown; charset=utf-8")
+		return c.SendString(export.SessionMarkdown(sess))
+	case "notebook":
+		data, err := export.SessionNotebook(sess)
+		if err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, "application/x-ipynb+json")
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="session-`+sess.ID+`.ipynb"`)
+		return c.Send(data)
+	default:
+		return apierror.New(apierror.CodeInvalidRequest, "format must be markdown or notebook")
+	}
+}
+
+// handleUserErase runs a GDPR erasure across every registered store.
+// ?dry_run=true only reports what would be removed.
+func (a *Admin) handleUserErase(c *fiber.Ctx) error {
+	if a.Privacy == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "privacy subsystem not configured")
+	}
+	dryRun := c.Query("dry_run") == "true"
+	report, err := a.Privacy.Erase(c.Context(), c.Params("id"), dryRun)
+	if err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	status := fiber.StatusOK
+	if !report.Complete {
+		status = fiber.StatusMultiStatus
+	}
+	return c.Status(status).JSON(report)
+}
+
+// handleUserExport starts an asynchronous right-of-access export; the
+// client polls /admin/exports/:id for the archive key.
+func (a *Admin) handleUserExport(c *fiber.Ctx) error {
+	if a.Exports == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "privacy subsystem not configured")
+	}
+	e, err := a.Exports.Request(c.Context(), c.Params("id"))
+	if err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	return c.Status(fiber.StatusAccepted).JSON(e)
+}
+
+func (a *Admin) handleExportStatus(c *fiber.Ctx) error {
+	if a.Exports == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "privacy subsystem not configured")
+	}
+	e, err := a.Exports.Get(c.Params("id"))
+	if errors.Is(err, privacy.ErrExportNotFound) {
+		return apierror.New(apierror.CodeNotFound, "export not found")
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(e)
+}
+
+// handleConsentRecord stores a user's terms acknowledgment for a
+// provider class, unblocking routing to that class.
+func (a *Admin) handleConsentRecord(c *fiber.Ctx) error {
+	if a.Consents == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "consent tracking not configured")
+	}
+	var req struct {
+		User         string `json:"user"`
+		Class        string `json:"class"`
+		TermsVersion string `json:"terms_version"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, "invalid consent payload")
+	}
+	consent, err := a.Consents.Record(req.User, req.Class, req.TermsVersion)
+	if err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	return c.Status(fiber.StatusCreated).JSON(consent)
+}
+
+func (a *Admin) handleConsentList(c *fiber.Ctx) error {
+	if a.Consents == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "consent tracking not configured")
+	}
+	return c.JSON(a.Consents.Records(c.Params("user")))
+}
+
+func (a *Admin) handleIncidentsList(c *fiber.Ctx) error {
+	if a.Incidents == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "incident tracking not configured")
+	}
+	return c.JSON(a.Incidents.List())
+}
+
+func (a *Admin) handleIncidentGet(c *fiber.Ctx) error {
+	if a.Incidents == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "incident tracking not configured")
+	}
+	inc, err := a.Incidents.Get(c.Params("id"))
+	if err != nil {
+		return apierror.New(apierror.CodeNotFound, "no incident with that ID")
+	}
+	return c.JSON(inc)
+}
+
+func (a *Admin) handleIncidentNote(c *fiber.Ctx) error {
+	if a.Incidents == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "incident tracking not configured")
+	}
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := c.BodyParser(&req); err != nil || strings.TrimSpace(req.Text) == "" {
+		return apierror.New(apierror.CodeInvalidRequest, "note needs a text field")
+	}
+	if err := a.Incidents.AddNote(c.Params("id"), req.Text); err != nil {
+		return apierror.New(apierror.CodeNotFound, "no incident with that ID")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (a *Admin) handleIncidentResolve(c *fiber.Ctx) error {
+	if a.Incidents == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "incident tracking not configured")
+	}
+	var req struct {
+		Resolution string `json:"resolution"`
+	}
+	if err := c.BodyParser(&req); err != nil || strings.TrimSpace(req.Resolution) == "" {
+		return apierror.New(apierror.CodeInvalidRequest, "resolve needs a resolution field")
+	}
+	err := a.Incidents.Resolve(c.Params("id"), req.Resolution)
+	switch {
+	case errors.Is(err, incidents.ErrNotFound):
+		return apierror.New(apierror.CodeNotFound, "no incident with that ID")
+	case errors.Is(err, incidents.ErrResolved):
+		return apierror.New(apierror.CodeInvalidRequest, "incident is already resolved")
+	case err != nil:
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	inc, _ := a.Incidents.Get(c.Params("id"))
+	return c.JSON(inc)
+}
+
+func (a *Admin) handleSimulateList(c *fiber.Ctx) error {
+	if a.Simulate == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "constraint injection not configured")
+	}
+	return c.JSON(a.Simulate.Constraints())
+}
+
+// handleSimulateSet replaces a provider's simulated constraint at
+// runtime, so load tests can adjust latency and concurrency without a
+// restart.
+func (a *Admin) handleSimulateSet(c *fiber.Ctx) error {
+	if a.Simulate == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "constraint injection not configured")
+	}
+	var constraint simulate.Constraint
+	if err := c.BodyParser(&constraint); err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, "invalid constraint payload")
+	}
+	if constraint.LatencyMs < 0 || constraint.JitterMs < 0 || constraint.MaxConcurrent < 0 {
+		return apierror.New(apierror.CodeInvalidRequest, "constraint values must be non-negative")
+	}
+	a.Simulate.Set(c.Params("provider"), constraint)
+	return c.JSON(constraint)
+}
+
+func (a *Admin) handleSimulateClear(c *fiber.Ctx) error {
+	if a.Simulate == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "constraint injection not configured")
+	}
+	a.Simulate.Set(c.Params("provider"), simulate.Constraint{})
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (a *Admin) handleLifecycleBatches(c *fiber.Ctx) error {
+	if a.Lifecycle == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "lifecycle sweeps not configured")
+	}
+	return c.JSON(a.Lifecycle.Batches())
+}
+
+// handleLifecycleDecision approves or rejects a proposed cleanup batch.
+// Approval applies the removals; partial failures come back as 207 with
+// the batch state, so the operator sees what actually went through.
+func (a *Admin) handleLifecycleDecision(approve bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if a.Lifecycle == nil {
+			return fiber.NewError(fiber.StatusServiceUnavailable, "lifecycle sweeps not configured")
+		}
+		var b lifecycle.Batch
+		var err error
+		if approve {
+			b, err = a.Lifecycle.Approve(c.Params("id"))
+		} else {
+			b, err = a.Lifecycle.Reject(c.Params("id"))
+		}
+		switch {
+		case errors.Is(err, lifecycle.ErrBatchNotFound):
+			return apierror.New(apierror.CodeNotFound, "batch not found")
+		case errors.Is(err, lifecycle.ErrBatchDecided):
+			return apierror.New(apierror.CodeInvalidRequest, "batch was already decided")
+		case err != nil:
+			return c.Status(fiber.StatusMultiStatus).JSON(fiber.Map{
+				"batch": b,
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(b)
+	}
+}
+
+// handleConfigSnapshot exports the running configuration (providers and
+// aliases, including their DB-stored routing state) as a portable
+// snapshot for promotion to another environment.
+func (a *Admin) handleConfigSnapshot(c *fiber.Ctx) error {
+	if a.Router == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "router not running")
+	}
+	return c.JSON(a.configSnapshot())
+}
+
+// handleConfigDiff compares the running configuration against a snapshot
+// posted in the body (JSON or YAML) and returns the structured diff.
+// Nothing is applied; the diff is review material for a promotion.
+func (a *Admin) handleConfigDiff(c *fiber.Ctx) error {
+	if a.Router == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "router not running")
+	}
+	snap, err := configdiff.Parse(c.Body())
+	if err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	return c.JSON(configdiff.Compare(a.configSnapshot(), snap))
+}
+
+func (a *Admin) configSnapshot() configdiff.Snapshot {
+	return configdiff.Snapshot{
+		Providers: a.Router.Providers(),
+		Aliases:   a.Router.Aliases(),
+	}
+}
+
+// handleSafetyReport serves the per-provider brand-safety score
+// distributions for content audits.
+func (a *Admin) handleSafetyReport(c *fiber.Ctx) error {
+	if a.Safety == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "safety scoring not configured")
+	}
+	return c.JSON(a.Safety.Report())
+}
+
+func (a *Admin) handleTenantKeys(c *fiber.Ctx) error {
+	if a.Keys == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "envelope encryption not configured")
+	}
+	return c.JSON(fiber.Map{
+		"tenant":   c.Params("tenant"),
+		"versions": a.Keys.Versions(c.Params("tenant")),
+	})
+}
+
+// handleTenantKeyRotate mints a new data key generation for the tenant.
+// Old generations stay on the keyring so existing ciphertext keeps
+// decrypting until the re-encryption job migrates it.
+func (a *Admin) handleTenantKeyRotate(c *fiber.Ctx) error {
+	if a.Keys == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "envelope encryption not configured")
+	}
+	version, err := a.Keys.RotateTenant(c.Params("tenant"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"tenant": c.Params("tenant"), "version": version})
+}
+
+// handleMasterKeyRotate re-wraps every tenant data key under a new
+// master key supplied as base64. No stored blob changes, so this is
+// cheap regardless of archive size.
+func (a *Admin) handleMasterKeyRotate(c *fiber.Ctx) error {
+	if a.Keys == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "envelope encryption not configured")
+	}
+	var req struct {
+		Key string `json:"key"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, "invalid rotation payload")
+	}
+	master, err := base64.StdEncoding.DecodeString(req.Key)
+	if err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, "key must be base64")
+	}
+	if err := a.Keys.RotateMaster(master); err != nil {
+		if errors.Is(err, envelope.ErrBadMasterKey) {
+			return apierror.New(apierror.CodeInvalidRequest, err.Error())
+		}
+		return err
+	}
+	return c.JSON(fiber.Map{"rotated": true})
+}
+
+// handleStatusIncidents lists the active incidents declared on provider
+// status pages, with links for the dashboard's annotations.
+func (a *Admin) handleStatusIncidents(c *fiber.Ctx) error {
+	if a.StatusWatch == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "status page tracking not configured")
+	}
+	return c.JSON(a.StatusWatch.Active())
+}
+
+// handleStatusWebhook ingests one status-page webhook delivery for the
+// provider in the path.
+func (a *Admin) handleStatusWebhook(c *fiber.Ctx) error {
+	if a.StatusWatch == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "status page tracking not configured")
+	}
+	if err := a.StatusWatch.HandleWebhook(c.Params("provider"), c.Body()); err != nil {
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// handleSlackCommand serves the Slack app's slash commands. Signature
+// verification happens inside the integration, against the raw body.
+func (a *Admin) handleSlackCommand(c *fiber.Ctx) error {
+	if a.Slack == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "slack integration not configured")
+	}
+	reply, err := a.Slack.HandleCommand(
+		c.Get("X-Slack-Request-Timestamp"), c.Get("X-Slack-Signature"), c.Body())
+	switch {
+	case errors.Is(err, notify.ErrBadSignature):
+		return apierror.New(apierror.CodeUnauthorized, "slack signature verification failed")
+	case err != nil:
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	return c.JSON(fiber.Map{"response_type": "ephemeral", "text": reply})
+}
+
+// handleSlackInteraction serves the ack buttons on posted alerts.
+func (a *Admin) handleSlackInteraction(c *fiber.Ctx) error {
+	if a.Slack == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "slack integration not configured")
+	}
+	reply, err := a.Slack.HandleInteraction(
+		c.Get("X-Slack-Request-Timestamp"), c.Get("X-Slack-Signature"), c.Body())
+	switch {
+	case errors.Is(err, notify.ErrBadSignature):
+		return apierror.New(apierror.CodeUnauthorized, "slack signature verification failed")
+	case err != nil:
+		return apierror.New(apierror.CodeInvalidRequest, err.Error())
+	}
+	return c.JSON(fiber.Map{"replace_original": true, "text": reply})
+}
+
+// handleMemory reports the usage gauges of every capped in-memory
+// subsystem, so operators can see what a long-running gateway is holding.
+func (a *Admin) handleMemory(c *fiber.Ctx) error {
+	return c.JSON(lru.Gauges())
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"crypto/subtle"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
+
+	"github.com/biodoia/goleapifree/internal/apierror"
+)
+
+// Debug hosts the profiling endpoints and runtime metrics on a listener
+// of its own, separate from both the public proxy and the admin API, so
+// it can be bound to localhost or a management network. Every route is
+// guarded by a bearer token; without a configured token the listener
+// refuses all requests rather than exposing heap contents by accident.
+type Debug struct {
+	app     *fiber.App
+	token   string
+	started time.Time
+}
+
+// NewDebug builds the debug app. The token guards every route.
+func NewDebug(token string) *Debug {
+	d := &Debug{
+		app: fiber.New(fiber.Config{
+			DisableStartupMessage: true,
+			ErrorHandler:          apierror.Handler,
+		}),
+		token:   token,
+		started: time.Now(),
+	}
+	d.app.Use(d.requireToken)
+	d.app.Use(pprof.New())
+	d.app.Get("/debug/vars", d.handleVars)
+	return d
+}
+
+// App exposes the underlying Fiber app for listening and for tests.
+func (d *Debug) App() *fiber.App { return d.app }
+
+// Listen serves the debug endpoints on addr.
+func (d *Debug) Listen(addr string) error { return d.app.Listen(addr) }
+
+func (d *Debug) requireToken(c *fiber.Ctx) error {
+	if d.token == "" {
+		return apierror.New(apierror.CodeForbidden, "debug endpoints disabled: no token configured")
+	}
+	got := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(got), []byte(d.token)) != 1 {
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid debug token")
+	}
+	return c.Next()
+}
+
+// handleVars reports the runtime numbers an operator checks first when a
+// gateway misbehaves: goroutine count, heap shape and GC pressure.
+func (d *Debug) handleVars(c *fiber.Ctx) error {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return c.JSON(fiber.Map{
+		"uptime_seconds":    time.Since(d.started).Seconds(),
+		"goroutines":        runtime.NumGoroutine(),
+		"gomaxprocs":        runtime.GOMAXPROCS(0),
+		"heap_alloc_bytes":  m.HeapAlloc,
+		"heap_sys_bytes":    m.HeapSys,
+		"heap_objects":      m.HeapObjects,
+		"stack_sys_bytes":   m.StackSys,
+		"num_gc":            m.NumGC,
+		"gc_pause_total_ms": float64(m.PauseTotalNs) / 1e6,
+		"next_gc_bytes":     m.NextGC,
+	})
+}
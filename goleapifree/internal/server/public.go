@@ -0,0 +1,370 @@
+// The public app is the OpenAI-compatible surface clients talk to.
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/biodoia/goleapifree/internal/apierror"
+	"github.com/biodoia/goleapifree/internal/boot"
+	"github.com/biodoia/goleapifree/internal/capabilities"
+	"github.com/biodoia/goleapifree/internal/executions"
+	"github.com/biodoia/goleapifree/internal/finetune"
+	"github.com/biodoia/goleapifree/internal/providers"
+	"github.com/biodoia/goleapifree/internal/rerank"
+	"github.com/biodoia/goleapifree/internal/stats"
+	"github.com/biodoia/goleapifree/internal/storage"
+)
+
+// Public bundles what the client-facing API needs.
+type Public struct {
+	app       *fiber.App
+	Collector *stats.Collector
+	Archive   storage.Archive
+	Signer    *storage.URLSigner
+	// Images maps model names to the adapter serving them, with the
+	// accounted cost per generated image.
+	Images map[string]ImageBackend
+	// Reranker serves /v1/rerank; nil disables the endpoint.
+	Reranker rerank.Reranker
+	// PublicStats serves the DP-noised aggregate usage endpoint.
+	PublicStats *stats.PrivateAggregator
+	// Usage serves the cursor-paginated per-request usage listing.
+	Usage *stats.UsageLog
+	// Executions serves the async execution API; nil disables it.
+	Executions *executions.Manager
+	// FineTunes serves the fine-tuning proxy; nil disables it.
+	FineTunes *finetune.Manager
+	// Boot reports startup state on /readyz; nil reports always-ready,
+	// which keeps probes working in tests and minimal deployments.
+	Boot *boot.Supervisor
+	// Capabilities serves probed per-model parameter schemas.
+	Capabilities *capabilities.Registry
+}
+
+// ImageBackend pairs an image adapter with its accounting metadata.
+type ImageBackend struct {
+	Adapter      providers.ImageAdapter
+	Provider     string
+	CostPerImage float64
+}
+
+// NewPublic builds the public Fiber app and registers its routes.
+func NewPublic() *Public {
+	p := &Public{
+		app: fiber.New(fiber.Config{
+			DisableStartupMessage: true,
+			BodyLimit:             16 * 1024 * 1024,
+			ErrorHandler:          apierror.Handler,
+		}),
+		Images: make(map[string]ImageBackend),
+	}
+	p.routes()
+	return p
+}
+
+// App exposes the underlying Fiber app for listening and for tests.
+func (p *Public) App() *fiber.App { return p.app }
+
+// Listen serves the public API on addr.
+func (p *Public) Listen(addr string) error { return p.app.Listen(addr) }
+
+func (p *Public) routes() {
+	p.app.Get("/readyz", p.handleReadyz)
+	p.app.Post("/v1/images/generations", p.handleImageGenerations)
+	p.app.Post("/v1/rerank", p.handleRerank)
+	p.app.Get("/archive/*", p.handleArchiveGet)
+	p.app.Get("/v1/public/usage", p.handlePublicUsage)
+	p.app.Get("/v1/usage", p.handleUsage)
+	p.app.Get("/v1/models/:id/capabilities", p.handleModelCapabilities)
+	p.app.Post("/v1/executions", p.handleExecutionSubmit)
+	p.app.Get("/v1/executions/:id", p.handleExecutionGet)
+	p.app.Post("/v1/executions/:id/cancel", p.handleExecutionCancel)
+	p.app.Post("/v1/fine_tuning/jobs", p.handleFineTuneCreate)
+	p.app.Get("/v1/fine_tuning/jobs", p.handleFineTuneList)
+	p.app.Get("/v1/fine_tuning/jobs/:id", p.handleFineTuneGet)
+}
+
+// handleReadyz reports startup state: 200 once every required
+// component is up, 503 before that, with per-component detail either
+// way so partial degradation (an optional subsystem still retrying) is
+// visible without failing the probe.
+func (p *Public) handleReadyz(c *fiber.Ctx) error {
+	if p.Boot == nil {
+		return c.JSON(fiber.Map{"ready": true})
+	}
+	ready := p.Boot.Ready()
+	if !ready {
+		c.Status(fiber.StatusServiceUnavailable)
+	}
+	return c.JSON(fiber.Map{"ready": ready, "components": p.Boot.Statuses()})
+}
+
+// handleModelCapabilities serves the probed capability schema of one
+// model, so SDKs validate requests client-side instead of discovering
+// limits through 400s.
+func (p *Public) handleModelCapabilities(c *fiber.Ctx) error {
+	if p.Capabilities == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "capability probing not configured")
+	}
+	schema, err := p.Capabilities.Get(c.Params("id"))
+	if errors.Is(err, capabilities.ErrUnknownModel) {
+		return apierror.New(apierror.CodeNotFound, "model not probed")
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(schema)
+}
+
+// handleFineTuneCreate forwards an OpenAI-shaped fine-tuning job to the
+// selected provider.
+func (p *Public) handleFineTuneCreate(c *fiber.Ctx) error {
+	if p.FineTunes == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "fine-tuning not configured")
+	}
+	var req struct {
+		Model        string `json:"model"`
+		TrainingFile string `json:"training_file"`
+		Provider     string `json:"provider"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.Model == "" || req.TrainingFile == "" || req.Provider == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "model, training_file and provider are required")
+	}
+	j, err := p.FineTunes.Create(c.Context(), req.Provider, req.Model, req.TrainingFile)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadGateway, err.Error())
+	}
+	return c.Status(fiber.StatusCreated).JSON(j)
+}
+
+func (p *Public) handleFineTuneList(c *fiber.Ctx) error {
+	if p.FineTunes == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "fine-tuning not configured")
+	}
+	return c.JSON(fiber.Map{"data": p.FineTunes.List()})
+}
+
+func (p *Public) handleFineTuneGet(c *fiber.Ctx) error {
+	if p.FineTunes == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "fine-tuning not configured")
+	}
+	j, err := p.FineTunes.Get(c.Context(), c.Params("id"))
+	if errors.Is(err, finetune.ErrNotFound) {
+		return apierror.New(apierror.CodeNotFound, "fine-tuning job not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadGateway, err.Error())
+	}
+	return c.JSON(j)
+}
+
+// handleExecutionSubmit accepts a heavy request and returns an execution
+// ID immediately; the work runs on the job queue.
+func (p *Public) handleExecutionSubmit(c *fiber.Ctx) error {
+	if p.Executions == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "executions not configured")
+	}
+	var req struct {
+		Kind        string          `json:"kind"`
+		Payload     json.RawMessage `json:"payload"`
+		CallbackURL string          `json:"callback_url"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.Kind == "" || len(req.Payload) == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "kind and payload are required")
+	}
+	e, err := p.Executions.Submit(c.Context(), req.Kind, req.Payload, req.CallbackURL)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusAccepted).JSON(e)
+}
+
+func (p *Public) handleExecutionGet(c *fiber.Ctx) error {
+	if p.Executions == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "executions not configured")
+	}
+	e, err := p.Executions.Get(c.Params("id"))
+	if errors.Is(err, executions.ErrNotFound) {
+		return apierror.New(apierror.CodeNotFound, "execution not found")
+	}
+	return c.JSON(e)
+}
+
+func (p *Public) handleExecutionCancel(c *fiber.Ctx) error {
+	if p.Executions == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "executions not configured")
+	}
+	e, err := p.Executions.Cancel(c.Params("id"))
+	switch {
+	case errors.Is(err, executions.ErrNotFound):
+		return apierror.New(apierror.CodeNotFound, "execution not found")
+	case errors.Is(err, executions.ErrNotCancelable):
+		return apierror.New(apierror.CodeInvalidRequest, "execution already finished")
+	case err != nil:
+		return err
+	}
+	return c.JSON(e)
+}
+
+// handlePublicUsage publishes noise-injected aggregate usage, suitable
+// for shared dashboards in multi-tenant deployments.
+func (p *Public) handlePublicUsage(c *fiber.Ctx) error {
+	if p.PublicStats == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "public stats not configured")
+	}
+	window := 24 * time.Hour
+	if w := c.Query("window"); w != "" {
+		parsed, err := time.ParseDuration(w)
+		if err != nil || parsed <= 0 || parsed > 30*24*time.Hour {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid window")
+		}
+		window = parsed
+	}
+	return c.JSON(p.PublicStats.Usage(window))
+}
+
+// handleUsage lists individual request usage with cursor pagination,
+// so client SDKs sync usage incrementally instead of scraping admin
+// endpoints. Entries carry accounting fields only.
+func (p *Public) handleUsage(c *fiber.Ctx) error {
+	if p.Usage == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "usage listing not configured")
+	}
+	q := stats.UsageQuery{
+		Tenant: c.Query("tenant"),
+		Cursor: c.Query("cursor"),
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid limit")
+		}
+		q.Limit = n
+	}
+	for _, bound := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &q.From}, {"to", &q.To}} {
+		if v := c.Query(bound.name); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid %s, want RFC 3339", bound.name))
+			}
+			*bound.dst = parsed
+		}
+	}
+	page, err := p.Usage.Page(q)
+	if errors.Is(err, stats.ErrBadCursor) {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid cursor")
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(page)
+}
+
+// handleRerank scores documents against a query in the Cohere/Jina
+// request shape.
+func (p *Public) handleRerank(c *fiber.Ctx) error {
+	if p.Reranker == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "rerank not configured")
+	}
+	var req struct {
+		Query     string   `json:"query"`
+		Documents []string `json:"documents"`
+		TopN      int      `json:"top_n"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.Query == "" || len(req.Documents) == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "query and documents are required")
+	}
+	results, err := p.Reranker.Rerank(c.Context(), req.Query, req.Documents, req.TopN)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadGateway, err.Error())
+	}
+	return c.JSON(fiber.Map{"results": results})
+}
+
+// handleImageGenerations implements the OpenAI images API over the
+// configured image backends. Generated images are archived and returned
+// as signed URLs; per-image cost is fed into the collector.
+func (p *Public) handleImageGenerations(c *fiber.Ctx) error {
+	var req providers.ImageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.Prompt == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "prompt is required")
+	}
+	backend, ok := p.Images[req.Model]
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("unknown image model %q", req.Model))
+	}
+
+	images, err := backend.Adapter.GenerateImages(c.Context(), req)
+	if err != nil {
+		if p.Collector != nil {
+			p.Collector.Observe(stats.Sample{Provider: backend.Provider}, true)
+		}
+		return fiber.NewError(fiber.StatusBadGateway, err.Error())
+	}
+
+	type imageOut struct {
+		URL string `json:"url"`
+	}
+	out := struct {
+		Created int64      `json:"created"`
+		Data    []imageOut `json:"data"`
+	}{Created: time.Now().Unix()}
+
+	for _, img := range images {
+		key := fmt.Sprintf("images/%s/%s.png", time.Now().UTC().Format("2006/01/02"), uuid.NewString())
+		if err := p.Archive.Put(key, bytes.NewReader(img.Data)); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "archiving image failed")
+		}
+		out.Data = append(out.Data, imageOut{URL: p.Signer.Sign(key, time.Now().Add(24*time.Hour))})
+		if p.Collector != nil {
+			p.Collector.Observe(stats.Sample{Provider: backend.Provider, Cost: backend.CostPerImage}, false)
+		}
+	}
+	return c.JSON(out)
+}
+
+// handleArchiveGet serves archived objects after verifying the signed URL.
+func (p *Public) handleArchiveGet(c *fiber.Ctx) error {
+	key := c.Params("*")
+	if key == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "missing key")
+	}
+	if err := p.Signer.Verify(key, c.Query("exp"), c.Query("sig"), time.Now()); err != nil {
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	}
+	obj, err := p.Archive.Get(key)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "not found")
+	}
+	defer obj.Close()
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "read failed")
+	}
+	c.Set("Content-Type", "image/png")
+	return c.Send(data)
+}
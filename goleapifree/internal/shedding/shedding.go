@@ -0,0 +1,175 @@
+// Package shedding rejects low-priority traffic early when the gateway is
+// overloaded, instead of letting every request queue up and time out.
+package shedding
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Priority classes, highest first. Interactive traffic is shed last.
+const (
+	PriorityInteractive = 0
+	PriorityStandard    = 1
+	PriorityBatch       = 2
+)
+
+// Config sets the overload thresholds.
+type Config struct {
+	// MaxQueueLatency is the smoothed internal queue latency above which
+	// shedding starts.
+	MaxQueueLatency time.Duration
+	// MaxHeapBytes is the heap size above which shedding starts;
+	// 0 disables the memory signal.
+	MaxHeapBytes uint64
+	// RetryAfter is advertised to shed clients.
+	RetryAfter time.Duration
+}
+
+// Shedder tracks load signals and decides which priorities to admit.
+// Overload severity escalates: mild overload sheds batch traffic only,
+// sustained overload sheds standard too; interactive is never shed.
+type Shedder struct {
+	cfg Config
+
+	mu          sync.Mutex
+	ewmaLatency time.Duration
+
+	heapBytes atomic.Uint64
+	shedCount [3]atomic.Int64
+}
+
+// New creates a shedder and starts its memory sampler.
+func New(cfg Config) *Shedder {
+	if cfg.RetryAfter == 0 {
+		cfg.RetryAfter = 5 * time.Second
+	}
+	s := &Shedder{cfg: cfg}
+	go s.sampleMemory()
+	return s
+}
+
+// ObserveQueueLatency feeds one measured internal queue latency into the
+// smoothed signal. Call it from the proxy's dispatch path.
+func (s *Shedder) ObserveQueueLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ewmaLatency == 0 {
+		s.ewmaLatency = d
+		return
+	}
+	// EWMA with alpha 0.2: responsive but resistant to single spikes.
+	s.ewmaLatency = time.Duration(0.8*float64(s.ewmaLatency) + 0.2*float64(d))
+}
+
+// overloadLevel is 0 (healthy), 1 (shed batch) or 2 (shed batch+standard).
+func (s *Shedder) overloadLevel() int {
+	s.mu.Lock()
+	latency := s.ewmaLatency
+	s.mu.Unlock()
+
+	level := 0
+	if s.cfg.MaxQueueLatency > 0 && latency > s.cfg.MaxQueueLatency {
+		level = 1
+		if latency > 2*s.cfg.MaxQueueLatency {
+			level = 2
+		}
+	}
+	if s.cfg.MaxHeapBytes > 0 && s.heapBytes.Load() > s.cfg.MaxHeapBytes {
+		if level < 1 {
+			level = 1
+		}
+		if s.heapBytes.Load() > s.cfg.MaxHeapBytes+(s.cfg.MaxHeapBytes/2) {
+			level = 2
+		}
+	}
+	return level
+}
+
+// Allow reports whether a request of the given priority is admitted, and
+// counts sheds per priority for the metrics endpoint.
+func (s *Shedder) Allow(priority int) bool {
+	level := s.overloadLevel()
+	shed := (level >= 1 && priority >= PriorityBatch) ||
+		(level >= 2 && priority >= PriorityStandard)
+	if shed && priority >= 0 && priority < len(s.shedCount) {
+		s.shedCount[priority].Add(1)
+	}
+	return !shed
+}
+
+// Metrics is the exported shed accounting.
+type Metrics struct {
+	QueueLatency time.Duration    `json:"queue_latency"`
+	HeapBytes    uint64           `json:"heap_bytes"`
+	Level        int              `json:"overload_level"`
+	ShedCounts   map[string]int64 `json:"shed_counts"`
+}
+
+// Snapshot returns current metrics.
+func (s *Shedder) Snapshot() Metrics {
+	s.mu.Lock()
+	latency := s.ewmaLatency
+	s.mu.Unlock()
+	return Metrics{
+		QueueLatency: latency,
+		HeapBytes:    s.heapBytes.Load(),
+		Level:        s.overloadLevel(),
+		ShedCounts: map[string]int64{
+			"interactive": s.shedCount[PriorityInteractive].Load(),
+			"standard":    s.shedCount[PriorityStandard].Load(),
+			"batch":       s.shedCount[PriorityBatch].Load(),
+		},
+	}
+}
+
+// sampleMemory refreshes the heap signal every second.
+func (s *Shedder) sampleMemory() {
+	for {
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		s.heapBytes.Store(m.HeapAlloc)
+		time.Sleep(time.Second)
+	}
+}
+
+// PriorityFunc derives a request's priority class.
+type PriorityFunc func(c *fiber.Ctx) int
+
+// HeaderPriority reads X-Priority (interactive|standard|batch), defaulting
+// to standard.
+func HeaderPriority(c *fiber.Ctx) int {
+	switch c.Get("X-Priority") {
+	case "interactive":
+		return PriorityInteractive
+	case "batch":
+		return PriorityBatch
+	default:
+		return PriorityStandard
+	}
+}
+
+// Middleware sheds requests before any expensive work happens.
+func Middleware(s *Shedder, priority PriorityFunc) fiber.Handler {
+	if priority == nil {
+		priority = HeaderPriority
+	}
+	return func(c *fiber.Ctx) error {
+		if s.Allow(priority(c)) {
+			return c.Next()
+		}
+		c.Set("Retry-After", strconv.Itoa(int(s.cfg.RetryAfter.Seconds())))
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"error": fiber.Map{
+				"code":      "overloaded",
+				"message":   "gateway overloaded, request shed",
+				"retryable": true,
+			},
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package shedding
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestShedder() *Shedder {
+	// Construct directly to avoid the background memory sampler.
+	return &Shedder{cfg: Config{MaxQueueLatency: 100 * time.Millisecond, RetryAfter: time.Second}}
+}
+
+func TestHealthyAdmitsEverything(t *testing.T) {
+	s := newTestShedder()
+	s.ObserveQueueLatency(10 * time.Millisecond)
+	for p := PriorityInteractive; p <= PriorityBatch; p++ {
+		if !s.Allow(p) {
+			t.Fatalf("priority %d shed while healthy", p)
+		}
+	}
+}
+
+func TestMildOverloadShedsBatchOnly(t *testing.T) {
+	s := newTestShedder()
+	for i := 0; i < 50; i++ {
+		s.ObserveQueueLatency(150 * time.Millisecond)
+	}
+	if !s.Allow(PriorityInteractive) || !s.Allow(PriorityStandard) {
+		t.Fatal("mild overload shed non-batch traffic")
+	}
+	if s.Allow(PriorityBatch) {
+		t.Fatal("mild overload admitted batch traffic")
+	}
+	if got := s.Snapshot().ShedCounts["batch"]; got != 1 {
+		t.Fatalf("batch shed count = %d, want 1", got)
+	}
+}
+
+func TestSevereOverloadShedsStandard(t *testing.T) {
+	s := newTestShedder()
+	for i := 0; i < 50; i++ {
+		s.ObserveQueueLatency(time.Second)
+	}
+	if s.Allow(PriorityStandard) {
+		t.Fatal("severe overload admitted standard traffic")
+	}
+	if !s.Allow(PriorityInteractive) {
+		t.Fatal("interactive traffic must never be shed")
+	}
+	if s.Snapshot().Level != 2 {
+		t.Fatalf("level = %d, want 2", s.Snapshot().Level)
+	}
+}
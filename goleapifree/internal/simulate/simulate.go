@@ -0,0 +1,176 @@
+// Package simulate injects production-shaped constraints into staging
+// providers: an artificial latency distribution and a concurrency
+// ceiling mirroring the free-tier limits production traffic runs into.
+// With constraints mirroring production, load tests in staging exercise
+// the same routing decisions (failover on 429, latency-driven
+// reordering) that production would make.
+package simulate
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Constraint is one provider's simulated environment. The zero value
+// imposes nothing.
+type Constraint struct {
+	// LatencyMs is the mean artificial delay added per request.
+	LatencyMs float64 `json:"latency_ms"`
+	// JitterMs spreads the delay uniformly around the mean, so the
+	// simulated distribution has the width of a real upstream rather
+	// than a fixed offset.
+	JitterMs float64 `json:"jitter_ms,omitempty"`
+	// MaxConcurrent caps in-flight requests; requests beyond it receive
+	// a synthetic 429 immediately, like a free-tier limit would.
+	// 0 = unlimited.
+	MaxConcurrent int `json:"max_concurrent,omitempty"`
+}
+
+// Injector holds per-provider constraints and wraps provider transports
+// with them. Constraints are editable at runtime through the admin API,
+// so a load test can tighten the screws without a restart.
+type Injector struct {
+	mu          sync.RWMutex
+	constraints map[string]Constraint
+	slots       map[string]chan struct{}
+
+	// test seams
+	randFloat func() float64
+	sleep     func(time.Duration)
+}
+
+// NewInjector returns an injector with no constraints configured.
+func NewInjector() *Injector {
+	return &Injector{
+		constraints: make(map[string]Constraint),
+		slots:       make(map[string]chan struct{}),
+		randFloat:   rand.Float64,
+		sleep:       time.Sleep,
+	}
+}
+
+// Set replaces a provider's constraint; a zero constraint clears it.
+func (i *Injector) Set(providerID string, c Constraint) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if c == (Constraint{}) {
+		delete(i.constraints, providerID)
+		delete(i.slots, providerID)
+		return
+	}
+	i.constraints[providerID] = c
+	if c.MaxConcurrent > 0 {
+		i.slots[providerID] = make(chan struct{}, c.MaxConcurrent)
+	} else {
+		delete(i.slots, providerID)
+	}
+}
+
+// Constraints returns the configured constraints by provider ID.
+func (i *Injector) Constraints() map[string]Constraint {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	out := make(map[string]Constraint, len(i.constraints))
+	for id, c := range i.constraints {
+		out[id] = c
+	}
+	return out
+}
+
+// Wrap returns a RoundTripper applying the provider's constraint around
+// base. A nil base uses http.DefaultTransport. Providers without a
+// constraint pass through untouched, so wrapping everything is cheap.
+func (i *Injector) Wrap(providerID string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &simTransport{injector: i, provider: providerID, next: base}
+}
+
+type simTransport struct {
+	injector *Injector
+	provider string
+	next     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *simTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	i := t.injector
+	i.mu.RLock()
+	c, ok := i.constraints[t.provider]
+	slots := i.slots[t.provider]
+	i.mu.RUnlock()
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	if slots != nil {
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+		default:
+			// The ceiling is hit: answer as the free tier would, without
+			// touching the upstream, so the router sees a realistic 429.
+			return tooManyRequests(req), nil
+		}
+	}
+
+	if delay := c.delay(i.randFloat); delay > 0 {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-wait(delay, i.sleep):
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// delay samples one artificial latency from the constraint's
+// distribution: uniform jitter around the mean, floored at zero.
+func (c Constraint) delay(randFloat func() float64) time.Duration {
+	ms := c.LatencyMs
+	if c.JitterMs > 0 {
+		ms += (randFloat()*2 - 1) * c.JitterMs
+	}
+	if ms <= 0 {
+		return 0
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// wait runs the (injectable) sleep off-goroutine so the delay can race
+// request cancellation.
+func wait(d time.Duration, sleep func(time.Duration)) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		sleep(d)
+		close(done)
+	}()
+	return done
+}
+
+// tooManyRequests fabricates the 429 a saturated free-tier endpoint
+// returns, Retry-After included.
+func tooManyRequests(req *http.Request) *http.Response {
+	body := []byte(`{"error":{"message":"simulated concurrency ceiling reached","type":"rate_limit_error"}}`)
+	return &http.Response{
+		Status:     http.StatusText(http.StatusTooManyRequests),
+		StatusCode: http.StatusTooManyRequests,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Content-Type":   []string{"application/json"},
+			"Retry-After":    []string{"1"},
+			"Content-Length": []string{strconv.Itoa(len(body))},
+		},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,101 @@
+package simulate
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWrapPassesThroughWithoutConstraint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	i := NewInjector()
+	i.sleep = func(time.Duration) { t.Fatal("slept without a constraint") }
+	client := &http.Client{Transport: i.Wrap("openai", nil)}
+	resp, err := client.Get(srv.URL)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("resp = %v, %v", resp, err)
+	}
+	resp.Body.Close()
+}
+
+func TestWrapInjectsLatency(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer srv.Close()
+
+	i := NewInjector()
+	var slept time.Duration
+	i.sleep = func(d time.Duration) { slept = d }
+	i.randFloat = func() float64 { return 1 } // jitter at the top of the range
+	i.Set("openai", Constraint{LatencyMs: 100, JitterMs: 50})
+
+	client := &http.Client{Transport: i.Wrap("openai", nil)}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if slept != 150*time.Millisecond {
+		t.Fatalf("slept %v, want 150ms", slept)
+	}
+}
+
+func TestWrapEnforcesConcurrencyCeiling(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		entered <- struct{}{}
+		<-release
+	}))
+	defer srv.Close()
+
+	i := NewInjector()
+	i.Set("openai", Constraint{MaxConcurrent: 1})
+	client := &http.Client{Transport: i.Wrap("openai", nil)}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp.Body.Close()
+	}()
+	<-entered // the only slot is now held
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") == "" {
+		t.Error("synthetic 429 missing Retry-After")
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if len(body) == 0 {
+		t.Error("synthetic 429 has no body")
+	}
+	close(release)
+	wg.Wait()
+}
+
+func TestSetZeroClearsConstraint(t *testing.T) {
+	i := NewInjector()
+	i.Set("openai", Constraint{LatencyMs: 100})
+	i.Set("openai", Constraint{})
+	if got := i.Constraints(); len(got) != 0 {
+		t.Fatalf("constraints = %+v", got)
+	}
+}
@@ -0,0 +1,202 @@
+// Package slo tracks availability and latency service level objectives
+// per provider or model alias, computes error-budget burn rates over
+// multiple windows and exposes the results for the WebUI and Prometheus.
+package slo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Objective defines the target for one provider or alias.
+type Objective struct {
+	// Availability is the target success ratio, e.g. 0.999.
+	Availability float64
+	// P95Latency is the target p95; requests slower than this count
+	// against the budget even when they succeed. 0 disables the
+	// latency SLI.
+	P95Latency time.Duration
+}
+
+// Burn-rate alert windows, per the multi-window multi-burn-rate pattern:
+// a fast window catches sharp outages, a slow one catches steady bleed.
+var alertWindows = []struct {
+	Short, Long time.Duration
+	Threshold   float64
+	Severity    string
+}{
+	{5 * time.Minute, time.Hour, 14.4, "page"},
+	{30 * time.Minute, 6 * time.Hour, 6, "page"},
+	{2 * time.Hour, 24 * time.Hour, 3, "ticket"},
+}
+
+// minuteBucket accumulates one minute of events for one target.
+type minuteBucket struct {
+	start time.Time
+	total int64
+	bad   int64
+}
+
+// Tracker records request outcomes against configured objectives.
+type Tracker struct {
+	now func() time.Time
+
+	mu         sync.Mutex
+	objectives map[string]Objective
+	buckets    map[string][]minuteBucket
+}
+
+// NewTracker returns an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		now:        time.Now,
+		objectives: make(map[string]Objective),
+		buckets:    make(map[string][]minuteBucket),
+	}
+}
+
+// SetObjective configures the SLO for a target (provider ID or alias).
+func (t *Tracker) SetObjective(target string, o Objective) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.objectives[target] = o
+}
+
+// Observe records one request. A request is budget-burning when it
+// failed or exceeded the latency target.
+func (t *Tracker) Observe(target string, latency time.Duration, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	o := t.objectives[target]
+	bad := failed || (o.P95Latency > 0 && latency > o.P95Latency)
+
+	minute := t.now().Truncate(time.Minute)
+	buckets := t.buckets[target]
+	if n := len(buckets); n == 0 || !buckets[n-1].start.Equal(minute) {
+		buckets = append(buckets, minuteBucket{start: minute})
+	}
+	buckets[len(buckets)-1].total++
+	if bad {
+		buckets[len(buckets)-1].bad++
+	}
+	// Retain the longest alert window plus slack.
+	cutoff := minute.Add(-25 * time.Hour)
+	for len(buckets) > 0 && buckets[0].start.Before(cutoff) {
+		buckets = buckets[1:]
+	}
+	t.buckets[target] = buckets
+}
+
+// badRatio is the budget-burning fraction over the trailing window.
+// Callers must hold t.mu.
+func (t *Tracker) badRatio(target string, window time.Duration) float64 {
+	cutoff := t.now().Add(-window)
+	var total, bad int64
+	for _, b := range t.buckets[target] {
+		if b.start.Before(cutoff) {
+			continue
+		}
+		total += b.total
+		bad += b.bad
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(bad) / float64(total)
+}
+
+// burnRate is the bad ratio divided by the budget; 1 means budget is
+// being consumed exactly at the sustainable pace.
+func (t *Tracker) burnRate(target string, window time.Duration) float64 {
+	budget := 1 - t.objectives[target].Availability
+	if budget <= 0 {
+		return 0
+	}
+	return t.badRatio(target, window) / budget
+}
+
+// Alert is one firing burn-rate alert.
+type Alert struct {
+	Target   string        `json:"target"`
+	Severity string        `json:"severity"`
+	Window   time.Duration `json:"window"`
+	BurnRate float64       `json:"burn_rate"`
+}
+
+// Alerts evaluates every configured target against the alert windows.
+// An alert fires only when both the short and long window exceed the
+// threshold, which filters out already-recovered spikes.
+func (t *Tracker) Alerts() []Alert {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var out []Alert
+	for target, o := range t.objectives {
+		if o.Availability <= 0 {
+			continue
+		}
+		for _, w := range alertWindows {
+			short := t.burnRate(target, w.Short)
+			long := t.burnRate(target, w.Long)
+			if short >= w.Threshold && long >= w.Threshold {
+				out = append(out, Alert{Target: target, Severity: w.Severity, Window: w.Long, BurnRate: long})
+				break // report the most urgent window only
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Target < out[j].Target })
+	return out
+}
+
+// Status is the dashboard row for one target.
+type Status struct {
+	Target          string  `json:"target"`
+	Availability    float64 `json:"availability_target"`
+	Compliance      float64 `json:"compliance_24h"`
+	BudgetRemaining float64 `json:"budget_remaining"`
+	BurnRate1h      float64 `json:"burn_rate_1h"`
+}
+
+// Statuses returns every configured target's current standing.
+func (t *Tracker) Statuses() []Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]Status, 0, len(t.objectives))
+	for target, o := range t.objectives {
+		bad := t.badRatio(target, 24*time.Hour)
+		s := Status{
+			Target:       target,
+			Availability: o.Availability,
+			Compliance:   1 - bad,
+			BurnRate1h:   t.burnRate(target, time.Hour),
+		}
+		if budget := 1 - o.Availability; budget > 0 {
+			s.BudgetRemaining = 1 - bad/budget
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Target < out[j].Target })
+	return out
+}
+
+// PrometheusText renders the SLO metrics in the Prometheus exposition
+// format, ready to serve from a /metrics handler.
+func (t *Tracker) PrometheusText() string {
+	statuses := t.Statuses()
+	var b strings.Builder
+	b.WriteString("# TYPE goleapai_slo_compliance gauge\n")
+	for _, s := range statuses {
+		fmt.Fprintf(&b, "goleapai_slo_compliance{target=%q} %g\n", s.Target, s.Compliance)
+	}
+	b.WriteString("# TYPE goleapai_slo_burn_rate gauge\n")
+	for _, s := range statuses {
+		fmt.Fprintf(&b, "goleapai_slo_burn_rate{target=%q,window=\"1h\"} %g\n", s.Target, s.BurnRate1h)
+	}
+	b.WriteString("# TYPE goleapai_slo_budget_remaining gauge\n")
+	for _, s := range statuses {
+		fmt.Fprintf(&b, "goleapai_slo_budget_remaining{target=%q} %g\n", s.Target, s.BudgetRemaining)
+	}
+	return b.String()
+}
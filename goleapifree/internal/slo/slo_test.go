@@ -0,0 +1,63 @@
+package slo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestTracker() (*Tracker, *time.Time) {
+	tr := NewTracker()
+	now := time.Unix(1_700_000_000, 0)
+	tr.now = func() time.Time { return now }
+	return tr, &now
+}
+
+func TestHealthyTargetDoesNotAlert(t *testing.T) {
+	tr, now := newTestTracker()
+	tr.SetObjective("openrouter", Objective{Availability: 0.99})
+	for i := 0; i < 600; i++ {
+		tr.Observe("openrouter", 50*time.Millisecond, false)
+		*now = now.Add(6 * time.Second)
+	}
+	if alerts := tr.Alerts(); len(alerts) != 0 {
+		t.Fatalf("alerts = %+v, want none", alerts)
+	}
+}
+
+func TestOutageFiresFastBurnAlert(t *testing.T) {
+	tr, now := newTestTracker()
+	tr.SetObjective("vertex", Objective{Availability: 0.99})
+	// Total outage: every request burns budget in both windows.
+	for i := 0; i < 600; i++ {
+		tr.Observe("vertex", 50*time.Millisecond, true)
+		*now = now.Add(6 * time.Second)
+	}
+	alerts := tr.Alerts()
+	if len(alerts) != 1 || alerts[0].Severity != "page" {
+		t.Fatalf("alerts = %+v, want one page", alerts)
+	}
+}
+
+func TestSlowRequestsBurnLatencyBudget(t *testing.T) {
+	tr, _ := newTestTracker()
+	tr.SetObjective("bedrock", Objective{Availability: 0.99, P95Latency: 100 * time.Millisecond})
+	tr.Observe("bedrock", 2*time.Second, false)
+	statuses := tr.Statuses()
+	if len(statuses) != 1 || statuses[0].Compliance != 0 {
+		t.Fatalf("statuses = %+v, want zero compliance", statuses)
+	}
+}
+
+func TestPrometheusText(t *testing.T) {
+	tr, _ := newTestTracker()
+	tr.SetObjective("openrouter", Objective{Availability: 0.999})
+	tr.Observe("openrouter", 10*time.Millisecond, false)
+	text := tr.PrometheusText()
+	if !strings.Contains(text, `goleapai_slo_compliance{target="openrouter"} 1`) {
+		t.Fatalf("exposition missing compliance gauge:\n%s", text)
+	}
+	if !strings.Contains(text, "# TYPE goleapai_slo_burn_rate gauge") {
+		t.Fatalf("exposition missing burn rate type line:\n%s", text)
+	}
+}
@@ -0,0 +1,204 @@
+// Package speculative emulates speculative decoding across providers for
+// latency-critical aliases: a small fast model drafts the full answer and
+// a larger model verifies it, accepting the draft verbatim or rewriting
+// it. Verification of a good draft emits far fewer tokens than generating
+// from scratch, which is where the latency savings come from; the package
+// tracks how much was actually saved versus the verifier's own
+// full-generation baseline.
+package speculative
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// acceptToken is the verbatim verifier reply that accepts a draft.
+const acceptToken = "ACCEPT"
+
+// baselineAlpha is the EMA smoothing factor for the verifier's
+// full-generation latency baseline.
+const baselineAlpha = 0.3
+
+// ErrNotEnabled is returned for aliases without a speculative config; the
+// caller falls back to the normal routing path.
+var ErrNotEnabled = errors.New("speculative: not enabled for alias")
+
+// ChatFunc issues one chat completion, mirroring the agents' seam so the
+// router, retries and tests can all slot in.
+type ChatFunc func(ctx context.Context, req providers.ChatRequest) (*providers.ChatResponse, error)
+
+// Config enables speculative decoding for one alias.
+type Config struct {
+	// DraftModel is the small fast model that drafts the answer.
+	DraftModel string `json:"draft_model"`
+	// VerifyModel is the larger model that accepts or rewrites drafts.
+	VerifyModel string `json:"verify_model"`
+}
+
+// Outcome describes one speculative completion for telemetry.
+type Outcome struct {
+	Alias    string `json:"alias"`
+	Accepted bool   `json:"accepted"`
+	// DraftMs and VerifyMs are the realized latencies of the two calls.
+	DraftMs  float64 `json:"draft_ms"`
+	VerifyMs float64 `json:"verify_ms"`
+	// SavedMs is the estimated latency saved versus the verifier
+	// generating alone; 0 until a full-generation baseline exists.
+	SavedMs float64 `json:"saved_ms"`
+}
+
+// Stats aggregates outcomes per alias for the stats API.
+type Stats struct {
+	Runs     int64 `json:"runs"`
+	Accepted int64 `json:"accepted"`
+	// BaselineMs is the EMA of the verifier's full-generation latency,
+	// learned from rejected drafts (where the verifier did generate).
+	BaselineMs   float64 `json:"baseline_ms"`
+	TotalSavedMs float64 `json:"total_saved_ms"`
+}
+
+// AcceptRate is the fraction of drafts accepted verbatim.
+func (s Stats) AcceptRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Accepted) / float64(s.Runs)
+}
+
+// Speculator holds per-alias configs and their accumulated stats.
+type Speculator struct {
+	Chat ChatFunc
+
+	now func() time.Time
+
+	mu      sync.Mutex
+	configs map[string]Config
+	stats   map[string]*Stats
+}
+
+// New creates a speculator issuing completions through chat.
+func New(chat ChatFunc) *Speculator {
+	return &Speculator{
+		Chat:    chat,
+		now:     time.Now,
+		configs: make(map[string]Config),
+		stats:   make(map[string]*Stats),
+	}
+}
+
+// Enable turns on speculative decoding for an alias.
+func (s *Speculator) Enable(alias string, cfg Config) error {
+	if cfg.DraftModel == "" || cfg.VerifyModel == "" {
+		return errors.New("speculative: config needs draft and verify models")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configs[alias] = cfg
+	return nil
+}
+
+// Disable removes an alias's speculative config; its stats are kept.
+func (s *Speculator) Disable(alias string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.configs, alias)
+}
+
+// Complete runs the draft/verify pipeline for an alias. The returned
+// response carries the accepted draft or the verifier's rewrite; the
+// outcome reports where the time went.
+func (s *Speculator) Complete(ctx context.Context, alias string, req providers.ChatRequest) (*providers.ChatResponse, *Outcome, error) {
+	s.mu.Lock()
+	cfg, ok := s.configs[alias]
+	s.mu.Unlock()
+	if !ok {
+		return nil, nil, ErrNotEnabled
+	}
+
+	draftReq := req
+	draftReq.Model = cfg.DraftModel
+	start := s.now()
+	draft, err := s.Chat(ctx, draftReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	draftMs := float64(s.now().Sub(start).Milliseconds())
+
+	start = s.now()
+	verdict, err := s.Chat(ctx, verifyRequest(cfg.VerifyModel, req, draft.Content))
+	if err != nil {
+		return nil, nil, err
+	}
+	verifyMs := float64(s.now().Sub(start).Milliseconds())
+
+	out := Outcome{Alias: alias, DraftMs: draftMs, VerifyMs: verifyMs}
+	resp := verdict
+	if strings.TrimSpace(verdict.Content) == acceptToken {
+		out.Accepted = true
+		resp = draft
+	}
+	s.record(&out)
+	return resp, &out, nil
+}
+
+// verifyRequest wraps the original request and the draft for the
+// verifier. A good draft costs it one token to approve.
+func verifyRequest(model string, req providers.ChatRequest, draft string) providers.ChatRequest {
+	messages := make([]providers.Message, 0, len(req.Messages)+2)
+	messages = append(messages, providers.Message{
+		Role: "system",
+		Content: "A draft answer to the conversation follows after the user messages. " +
+			"If it is correct and complete, reply with exactly " + acceptToken + ". " +
+			"Otherwise reply with the corrected or extended answer and nothing else.",
+	})
+	messages = append(messages, req.Messages...)
+	messages = append(messages, providers.Message{Role: "user", Content: "Draft answer:\n" + draft})
+	out := req
+	out.Model = model
+	out.Messages = messages
+	return out
+}
+
+// record folds one outcome into the alias stats. Rejected runs teach the
+// baseline (the verifier generated in full); accepted runs realize
+// savings against it.
+func (s *Speculator) record(out *Outcome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.stats[out.Alias]
+	if !ok {
+		st = &Stats{}
+		s.stats[out.Alias] = st
+	}
+	st.Runs++
+	if !out.Accepted {
+		if st.BaselineMs == 0 {
+			st.BaselineMs = out.VerifyMs
+		} else {
+			st.BaselineMs = baselineAlpha*out.VerifyMs + (1-baselineAlpha)*st.BaselineMs
+		}
+		return
+	}
+	st.Accepted++
+	if st.BaselineMs > 0 {
+		if saved := st.BaselineMs - (out.DraftMs + out.VerifyMs); saved > 0 {
+			out.SavedMs = saved
+			st.TotalSavedMs += saved
+		}
+	}
+}
+
+// StatsFor returns the accumulated stats for one alias.
+func (s *Speculator) StatsFor(alias string) Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.stats[alias]; ok {
+		return *st
+	}
+	return Stats{}
+}
@@ -0,0 +1,126 @@
+package speculative
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/providers"
+)
+
+// specChat plays both roles with configurable latency per model, driven
+// by the speculator's injected clock.
+type specChat struct {
+	clock   *time.Time
+	latency map[string]time.Duration
+	verdict string // verifier reply
+}
+
+func (c *specChat) fn(_ context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+	*c.clock = c.clock.Add(c.latency[req.Model])
+	if req.Model == "big" {
+		return &providers.ChatResponse{Model: req.Model, Content: c.verdict}, nil
+	}
+	return &providers.ChatResponse{Model: req.Model, Content: "draft answer"}, nil
+}
+
+func newSpeculator(t *testing.T, chat *specChat) *Speculator {
+	t.Helper()
+	clock := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	chat.clock = &clock
+	s := New(chat.fn)
+	s.now = func() time.Time { return clock }
+	if err := s.Enable("fast-chat", Config{DraftModel: "small", VerifyModel: "big"}); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestAcceptedDraftRealizesSavings(t *testing.T) {
+	chat := &specChat{
+		latency: map[string]time.Duration{"small": 100 * time.Millisecond, "big": 2 * time.Second},
+		verdict: "a better answer",
+	}
+	s := newSpeculator(t, chat)
+
+	// First run rejects: the verifier generates in full and teaches the
+	// baseline.
+	resp, out, err := s.Complete(context.Background(), "fast-chat", providers.ChatRequest{
+		Messages: []providers.Message{{Role: "user", Content: "q"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Accepted || resp.Content != "a better answer" || out.SavedMs != 0 {
+		t.Fatalf("rejected run = %+v, resp = %q", out, resp.Content)
+	}
+
+	// Approving a good draft is fast; savings measure against the learned
+	// baseline.
+	chat.verdict = " ACCEPT\n"
+	chat.latency["big"] = 300 * time.Millisecond
+	resp, out, err = s.Complete(context.Background(), "fast-chat", providers.ChatRequest{
+		Messages: []providers.Message{{Role: "user", Content: "q"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.Accepted || resp.Content != "draft answer" {
+		t.Fatalf("accepted run = %+v, resp = %q", out, resp.Content)
+	}
+	if out.SavedMs != 2000-(100+300) {
+		t.Fatalf("saved = %f", out.SavedMs)
+	}
+
+	st := s.StatsFor("fast-chat")
+	if st.Runs != 2 || st.Accepted != 1 || st.AcceptRate() != 0.5 || st.TotalSavedMs != 1600 {
+		t.Fatalf("stats = %+v", st)
+	}
+}
+
+func TestVerifierSeesConversationAndDraft(t *testing.T) {
+	var verifyReq providers.ChatRequest
+	clock := time.Now()
+	s := New(func(_ context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+		if req.Model == "big" {
+			verifyReq = req
+		}
+		return &providers.ChatResponse{Content: "draft answer"}, nil
+	})
+	s.now = func() time.Time { return clock }
+	s.Enable("a", Config{DraftModel: "small", VerifyModel: "big"})
+	if _, _, err := s.Complete(context.Background(), "a", providers.ChatRequest{
+		Messages: []providers.Message{{Role: "user", Content: "the question"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(verifyReq.Messages) != 3 || verifyReq.Messages[1].Content != "the question" {
+		t.Fatalf("verify messages = %+v", verifyReq.Messages)
+	}
+	if last := verifyReq.Messages[2].Content; !strings.Contains(last, "draft answer") {
+		t.Fatalf("draft missing from verify request: %q", last)
+	}
+}
+
+func TestCompleteGuards(t *testing.T) {
+	s := New(nil)
+	if _, _, err := s.Complete(context.Background(), "off", providers.ChatRequest{}); !errors.Is(err, ErrNotEnabled) {
+		t.Fatalf("err = %v", err)
+	}
+	if err := s.Enable("a", Config{DraftModel: "small"}); err == nil {
+		t.Fatal("config without verify model accepted")
+	}
+	s.Chat = func(context.Context, providers.ChatRequest) (*providers.ChatResponse, error) {
+		return nil, errors.New("boom")
+	}
+	s.Enable("a", Config{DraftModel: "small", VerifyModel: "big"})
+	if _, _, err := s.Complete(context.Background(), "a", providers.ChatRequest{}); err == nil {
+		t.Fatal("chat error swallowed")
+	}
+	s.Disable("a")
+	if _, _, err := s.Complete(context.Background(), "a", providers.ChatRequest{}); !errors.Is(err, ErrNotEnabled) {
+		t.Fatalf("after disable: %v", err)
+	}
+}
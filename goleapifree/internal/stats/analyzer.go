@@ -0,0 +1,189 @@
+// Package stats aggregates per-request usage into time buckets and derives
+// reports (trends, forecasts, capacity) from them.
+package stats
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyReservoir caps how many latency samples one bucket retains for
+// percentile estimation; beyond it, samples are replaced at random
+// (reservoir sampling) so percentiles stay representative.
+const latencyReservoir = 256
+
+// Bucket is one hour of aggregated usage for a (provider, tenant) pair.
+type Bucket struct {
+	Start    time.Time `json:"start"`
+	Requests int64     `json:"requests"`
+	Errors   int64     `json:"errors"`
+	Tokens   int64     `json:"tokens"`
+	Cost     float64   `json:"cost"`
+
+	latencies []float64 // reservoir of request latencies in ms
+	seen      int64     // total latency samples offered to the reservoir
+}
+
+// LatencyPercentile estimates the p-th (0..100) latency percentile of the
+// bucket in milliseconds; 0 when the bucket has no latency samples.
+func (b *Bucket) LatencyPercentile(p float64) float64 {
+	if len(b.latencies) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), b.latencies...)
+	sort.Float64s(sorted)
+	idx := int(p / 100 * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+// Sample is a single completed request as reported by the proxy.
+type Sample struct {
+	Provider  string
+	Tenant    string
+	Tokens    int64
+	Cost      float64
+	LatencyMs float64
+	// Failed marks a request that ended in an upstream error.
+	Failed bool
+	At     time.Time
+	// Metadata carries the client-supplied tags (feature, ticket,
+	// environment, ...); each pair becomes its own bucket dimension so
+	// cost can be attributed per tag value.
+	Metadata map[string]string
+}
+
+// Analyzer maintains hourly usage buckets keyed by provider and by tenant.
+// It keeps a bounded history window and is safe for concurrent use.
+type Analyzer struct {
+	mu        sync.RWMutex
+	byKey     map[string]map[int64]*Bucket // dimension key -> hour unix -> bucket
+	retention time.Duration
+	now       func() time.Time
+	// randInt63n drives reservoir sampling; injectable for reproducible
+	// latency percentiles in tests.
+	randInt63n func(int64) int64
+}
+
+// NewAnalyzer creates an analyzer keeping retention worth of hourly buckets.
+func NewAnalyzer(retention time.Duration) *Analyzer {
+	return &Analyzer{
+		byKey:      make(map[string]map[int64]*Bucket),
+		retention:  retention,
+		now:        time.Now,
+		randInt63n: rand.Int63n,
+	}
+}
+
+// Record folds one sample into the provider and tenant bucket series.
+func (a *Analyzer) Record(s Sample) {
+	if s.At.IsZero() {
+		s.At = a.now()
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.add("provider:"+s.Provider, s)
+	if s.Tenant != "" {
+		a.add("tenant:"+s.Tenant, s)
+	}
+	for k, v := range s.Metadata {
+		a.add("meta:"+k+"="+v, s)
+	}
+}
+
+// add folds s into one dimension's bucket. Caller holds a.mu.
+func (a *Analyzer) add(key string, s Sample) {
+	hour := s.At.Truncate(time.Hour).Unix()
+	m, ok := a.byKey[key]
+	if !ok {
+		m = make(map[int64]*Bucket)
+		a.byKey[key] = m
+	}
+	b, ok := m[hour]
+	if !ok {
+		b = &Bucket{Start: time.Unix(hour, 0).UTC()}
+		m[hour] = b
+		a.prune(m)
+	}
+	b.Requests++
+	if s.Failed {
+		b.Errors++
+	}
+	b.Tokens += s.Tokens
+	b.Cost += s.Cost
+	if s.LatencyMs > 0 {
+		b.seen++
+		if len(b.latencies) < latencyReservoir {
+			b.latencies = append(b.latencies, s.LatencyMs)
+		} else if j := a.randInt63n(b.seen); j < latencyReservoir {
+			b.latencies[j] = s.LatencyMs
+		}
+	}
+}
+
+// prune drops buckets older than the retention window. Caller holds a.mu.
+func (a *Analyzer) prune(m map[int64]*Bucket) {
+	cutoff := a.now().Add(-a.retention).Unix()
+	for hour := range m {
+		if hour < cutoff {
+			delete(m, hour)
+		}
+	}
+}
+
+// ProviderBuckets returns the hourly series for a provider, oldest first.
+func (a *Analyzer) ProviderBuckets(provider string) []Bucket {
+	return a.series("provider:" + provider)
+}
+
+// TenantBuckets returns the hourly series for a tenant, oldest first.
+func (a *Analyzer) TenantBuckets(tenant string) []Bucket {
+	return a.series("tenant:" + tenant)
+}
+
+// Providers lists every provider that has recorded usage.
+func (a *Analyzer) Providers() []string {
+	return a.keys("provider:")
+}
+
+// Tenants lists every tenant that has recorded usage.
+func (a *Analyzer) Tenants() []string {
+	return a.keys("tenant:")
+}
+
+// TagBuckets returns the hourly series for one metadata tag value, e.g.
+// TagBuckets("feature", "checkout"), oldest first.
+func (a *Analyzer) TagBuckets(key, value string) []Bucket {
+	return a.series("meta:" + key + "=" + value)
+}
+
+// TagValues lists the recorded values of one metadata key.
+func (a *Analyzer) TagValues(key string) []string {
+	return a.keys("meta:" + key + "=")
+}
+
+func (a *Analyzer) keys(prefix string) []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var out []string
+	for k := range a.byKey {
+		if len(k) > len(prefix) && k[:len(prefix)] == prefix {
+			out = append(out, k[len(prefix):])
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (a *Analyzer) series(key string) []Bucket {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	m := a.byKey[key]
+	out := make([]Bucket, 0, len(m))
+	for _, b := range m {
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out
+}
@@ -0,0 +1,34 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetadataTagsBecomeDimensions(t *testing.T) {
+	a := NewAnalyzer(24 * time.Hour)
+	at := time.Now().Truncate(time.Hour)
+	a.Record(Sample{
+		Provider: "openrouter", Tokens: 100, Cost: 0.01, At: at,
+		Metadata: map[string]string{"feature": "checkout", "env": "prod"},
+	})
+	a.Record(Sample{
+		Provider: "openrouter", Tokens: 50, Cost: 0.005, At: at,
+		Metadata: map[string]string{"feature": "search", "env": "prod"},
+	})
+
+	if got := a.TagValues("feature"); len(got) != 2 || got[0] != "checkout" {
+		t.Fatalf("TagValues(feature) = %v", got)
+	}
+	buckets := a.TagBuckets("feature", "checkout")
+	if len(buckets) != 1 || buckets[0].Tokens != 100 {
+		t.Fatalf("checkout buckets = %+v", buckets)
+	}
+	env := a.TagBuckets("env", "prod")
+	if len(env) != 1 || env[0].Requests != 2 {
+		t.Fatalf("prod buckets = %+v", env)
+	}
+	if got := a.TagBuckets("feature", "missing"); len(got) != 0 {
+		t.Fatalf("missing tag buckets = %+v", got)
+	}
+}
@@ -0,0 +1,178 @@
+package stats
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+// LogWriter persists request log rows in batches.
+type LogWriter interface {
+	WriteBatch(ctx context.Context, logs []models.RequestLog) error
+}
+
+// DropPolicy decides what happens when the batcher's queue is full.
+type DropPolicy string
+
+// Drop policies. DropNewest sheds the incoming row (cheapest, default),
+// DropOldest keeps the freshest data, Sample keeps one in sampleKeep
+// rows so aggregate shapes stay roughly representative under pressure.
+const (
+	DropNewest          DropPolicy = "drop_newest"
+	DropOldest          DropPolicy = "drop_oldest"
+	SampleUnderPressure DropPolicy = "sample"
+)
+
+// sampleKeep is the keep ratio for the Sample policy: one row kept per
+// sampleKeep offered while the queue is full.
+const sampleKeep = 8
+
+// BatchConfig tunes the batcher.
+type BatchConfig struct {
+	// QueueSize bounds buffered rows; defaults to 4096.
+	QueueSize int
+	// BatchSize is the max rows per write; defaults to 256.
+	BatchSize int
+	// FlushInterval bounds how long a partial batch waits; defaults
+	// to one second.
+	FlushInterval time.Duration
+	// Policy applies when the queue is full; defaults to DropNewest.
+	Policy DropPolicy
+}
+
+func (c BatchConfig) withDefaults() BatchConfig {
+	if c.QueueSize <= 0 {
+		c.QueueSize = 4096
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 256
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = time.Second
+	}
+	if c.Policy == "" {
+		c.Policy = DropNewest
+	}
+	return c
+}
+
+// Batcher decouples the request hot path from log persistence: Enqueue
+// never blocks and never touches the database; a single background
+// writer drains the queue in batches.
+type Batcher struct {
+	cfg    BatchConfig
+	writer LogWriter
+
+	queue   chan models.RequestLog
+	dropped atomic.Int64
+	offered atomic.Int64 // rows offered while full, for the sample policy
+	done    chan struct{}
+}
+
+// NewBatcher creates a batcher writing through w.
+func NewBatcher(w LogWriter, cfg BatchConfig) *Batcher {
+	cfg = cfg.withDefaults()
+	return &Batcher{
+		cfg:    cfg,
+		writer: w,
+		queue:  make(chan models.RequestLog, cfg.QueueSize),
+		done:   make(chan struct{}),
+	}
+}
+
+// Enqueue offers one row without blocking. It reports whether the row
+// was accepted; rejected rows are counted in Dropped.
+func (b *Batcher) Enqueue(row models.RequestLog) bool {
+	select {
+	case b.queue <- row:
+		return true
+	default:
+	}
+	// Queue full: apply the pressure policy.
+	switch b.cfg.Policy {
+	case DropOldest:
+		select {
+		case <-b.queue:
+			b.dropped.Add(1)
+		default:
+		}
+		select {
+		case b.queue <- row:
+			return true
+		default:
+		}
+	case SampleUnderPressure:
+		if b.offered.Add(1)%sampleKeep == 0 {
+			select {
+			case <-b.queue:
+				b.dropped.Add(1)
+			default:
+			}
+			select {
+			case b.queue <- row:
+				return true
+			default:
+			}
+		}
+	}
+	b.dropped.Add(1)
+	return false
+}
+
+// Dropped returns how many rows were shed under pressure.
+func (b *Batcher) Dropped() int64 { return b.dropped.Load() }
+
+// Start launches the background writer. On ctx cancellation the
+// remaining queue is flushed synchronously before Wait unblocks.
+func (b *Batcher) Start(ctx context.Context) {
+	go func() {
+		defer close(b.done)
+		ticker := time.NewTicker(b.cfg.FlushInterval)
+		defer ticker.Stop()
+		batch := make([]models.RequestLog, 0, b.cfg.BatchSize)
+		for {
+			select {
+			case row := <-b.queue:
+				batch = append(batch, row)
+				if len(batch) >= b.cfg.BatchSize {
+					batch = b.flush(ctx, batch)
+				}
+			case <-ticker.C:
+				batch = b.flush(ctx, batch)
+			case <-ctx.Done():
+				for {
+					select {
+					case row := <-b.queue:
+						batch = append(batch, row)
+						if len(batch) >= b.cfg.BatchSize {
+							batch = b.flush(context.Background(), batch)
+						}
+					default:
+						b.flush(context.Background(), batch)
+						return
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Wait blocks until the final flush after Start's context ends.
+func (b *Batcher) Wait() { <-b.done }
+
+// flush writes one batch, returning a reusable empty slice. A failed
+// write drops the batch and counts it — retrying in the hot loop would
+// only grow the backlog the batcher exists to bound.
+func (b *Batcher) flush(ctx context.Context, batch []models.RequestLog) []models.RequestLog {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := b.writer.WriteBatch(ctx, batch); err != nil {
+		log.Printf("stats: batch write of %d rows failed: %v", len(batch), err)
+		b.dropped.Add(int64(len(batch)))
+	}
+	return batch[:0]
+}
@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+type fakeLogWriter struct {
+	mu      sync.Mutex
+	batches [][]models.RequestLog
+}
+
+func (w *fakeLogWriter) WriteBatch(_ context.Context, logs []models.RequestLog) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	batch := make([]models.RequestLog, len(logs))
+	copy(batch, logs)
+	w.batches = append(w.batches, batch)
+	return nil
+}
+
+func (w *fakeLogWriter) rows() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := 0
+	for _, b := range w.batches {
+		n += len(b)
+	}
+	return n
+}
+
+func TestBatcherFlushesOnBatchSize(t *testing.T) {
+	w := &fakeLogWriter{}
+	b := NewBatcher(w, BatchConfig{BatchSize: 4, FlushInterval: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	b.Start(ctx)
+
+	for i := 0; i < 4; i++ {
+		if !b.Enqueue(models.RequestLog{ProviderID: "p1"}) {
+			t.Fatal("enqueue rejected")
+		}
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for w.rows() < 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("rows = %d, want 4", w.rows())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	b.Wait()
+}
+
+func TestBatcherFlushesOnInterval(t *testing.T) {
+	w := &fakeLogWriter{}
+	b := NewBatcher(w, BatchConfig{BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	b.Start(ctx)
+
+	b.Enqueue(models.RequestLog{ProviderID: "p1"})
+	deadline := time.Now().Add(2 * time.Second)
+	for w.rows() < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("partial batch never flushed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	b.Wait()
+}
+
+func TestBatcherFlushesRemainderOnShutdown(t *testing.T) {
+	w := &fakeLogWriter{}
+	b := NewBatcher(w, BatchConfig{BatchSize: 100, FlushInterval: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	b.Start(ctx)
+
+	for i := 0; i < 7; i++ {
+		b.Enqueue(models.RequestLog{ProviderID: "p1"})
+	}
+	cancel()
+	b.Wait()
+	if got := w.rows(); got != 7 {
+		t.Fatalf("rows after shutdown = %d, want 7", got)
+	}
+}
+
+func TestEnqueueNeverBlocksAndCountsDrops(t *testing.T) {
+	// No Start: the queue fills and stays full.
+	b := NewBatcher(&fakeLogWriter{}, BatchConfig{QueueSize: 2})
+	for i := 0; i < 5; i++ {
+		b.Enqueue(models.RequestLog{})
+	}
+	if got := b.Dropped(); got != 3 {
+		t.Fatalf("dropped = %d, want 3", got)
+	}
+}
+
+func TestDropOldestKeepsFreshestRows(t *testing.T) {
+	b := NewBatcher(&fakeLogWriter{}, BatchConfig{QueueSize: 2, Policy: DropOldest})
+	for i := 0; i < 4; i++ {
+		b.Enqueue(models.RequestLog{ID: string(rune('a' + i))})
+	}
+	if got := b.Dropped(); got != 2 {
+		t.Fatalf("dropped = %d, want 2", got)
+	}
+	// The two freshest rows must still be queued.
+	first := <-b.queue
+	second := <-b.queue
+	if first.ID != "c" || second.ID != "d" {
+		t.Fatalf("queued = %q, %q, want c, d", first.ID, second.ID)
+	}
+}
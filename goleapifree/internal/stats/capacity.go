@@ -0,0 +1,128 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+// ProviderCapacity is the month-to-date standing of one provider's free
+// tier: what the quota calendar allows, what has been consumed, and when
+// the forecast says the tier runs out.
+type ProviderCapacity struct {
+	Provider       string     `json:"provider"`
+	MonthlyQuota   int64      `json:"monthly_quota"`
+	UsedThisMonth  int64      `json:"used_this_month"`
+	DailyRate      float64    `json:"daily_rate"`
+	ForecastMonth  float64    `json:"forecast_month_total"`
+	ExhaustedAt    *time.Time `json:"exhausted_at,omitempty"`
+	WillExhaust    bool       `json:"will_exhaust"`
+	PercentOfQuota float64    `json:"percent_of_quota"`
+	// Saturation is the provider host's most constrained resource in
+	// [0,1], filled for local providers with resource telemetry: a
+	// provider can be resource-bound long before its quota runs out.
+	Saturation float64 `json:"saturation,omitempty"`
+}
+
+// CapacityReport combines provider standings with the model aliases whose
+// targets are all at risk of exhaustion this month.
+type CapacityReport struct {
+	GeneratedAt   time.Time          `json:"generated_at"`
+	Providers     []ProviderCapacity `json:"providers"`
+	AliasesAtRisk []string           `json:"aliases_at_risk"`
+}
+
+// CapacityPlanner derives capacity reports from the analyzer's history,
+// the forecaster's projections and the providers' quota configuration.
+type CapacityPlanner struct {
+	Analyzer *Forecaster
+	// Saturation reports a provider host's resource saturation; the
+	// wiring binds it to the resource monitor. nil leaves the report's
+	// saturation column empty.
+	Saturation func(provider string) float64
+	now        func() time.Time
+}
+
+// NewCapacityPlanner wraps a forecaster for report generation.
+func NewCapacityPlanner(f *Forecaster) *CapacityPlanner {
+	return &CapacityPlanner{Analyzer: f, now: time.Now}
+}
+
+// Report builds the capacity report. quotas maps provider name to its
+// summed monthly free-tier request quota (daily quota × days in month for
+// per-day tiers); aliases is consulted to flag aliases whose every target
+// provider is forecast to exhaust.
+func (cp *CapacityPlanner) Report(quotas map[string]int64, aliases []models.ModelAlias, providerNames map[string]string) CapacityReport {
+	now := cp.now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	hoursLeft := int(monthEnd.Sub(now).Hours())
+
+	report := CapacityReport{GeneratedAt: now}
+	exhausting := make(map[string]bool)
+
+	for provider, quota := range quotas {
+		buckets := cp.Analyzer.Analyzer.ProviderBuckets(provider)
+		var used int64
+		var last24 float64
+		for _, b := range buckets {
+			if !b.Start.Before(monthStart) {
+				used += b.Requests
+			}
+			if b.Start.After(now.Add(-24 * time.Hour)) {
+				last24 += float64(b.Requests)
+			}
+		}
+
+		fc := cp.Analyzer.Provider(provider, hoursLeft)
+		pc := ProviderCapacity{
+			Provider:      provider,
+			MonthlyQuota:  quota,
+			UsedThisMonth: used,
+			DailyRate:     last24,
+			ForecastMonth: float64(used) + fc.TotalRequests(),
+		}
+		if quota > 0 {
+			pc.PercentOfQuota = 100 * float64(used) / float64(quota)
+			// Walk the forecast until cumulative usage crosses the quota.
+			cum := float64(used)
+			for h, v := range fc.Requests {
+				cum += v
+				if cum >= float64(quota) {
+					at := now.Truncate(time.Hour).Add(time.Duration(h+1) * time.Hour)
+					pc.ExhaustedAt = &at
+					pc.WillExhaust = true
+					break
+				}
+			}
+		}
+		if cp.Saturation != nil {
+			pc.Saturation = cp.Saturation(provider)
+		}
+		if pc.WillExhaust {
+			exhausting[provider] = true
+		}
+		report.Providers = append(report.Providers, pc)
+	}
+
+	for _, alias := range aliases {
+		if len(alias.Targets) == 0 {
+			continue
+		}
+		atRisk := true
+		for _, t := range alias.Targets {
+			name := providerNames[t.ProviderID]
+			if name == "" {
+				name = t.ProviderID
+			}
+			if !exhausting[name] {
+				atRisk = false
+				break
+			}
+		}
+		if atRisk {
+			report.AliasesAtRisk = append(report.AliasesAtRisk, alias.Alias)
+		}
+	}
+	return report
+}
@@ -0,0 +1,187 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// Totals are the collector's cumulative counters. They must survive
+// restarts so dashboards agree with the request log.
+type Totals struct {
+	TotalRequests int64   `json:"total_requests"`
+	TotalErrors   int64   `json:"total_errors"`
+	TotalTokens   int64   `json:"total_tokens"`
+	TotalCost     float64 `json:"total_cost"`
+}
+
+// SnapshotStore persists collector totals between restarts.
+type SnapshotStore interface {
+	Save(ctx context.Context, t Totals) error
+	Load(ctx context.Context) (Totals, error)
+}
+
+// ErrNoSnapshot is returned by Load on a fresh store.
+var ErrNoSnapshot = errors.New("stats: no snapshot")
+
+// Collector accumulates request metrics in memory, feeds the analyzer, and
+// periodically snapshots its totals through a write-behind queue so a crash
+// loses at most one flush interval of counting.
+type Collector struct {
+	mu       sync.RWMutex
+	totals   Totals
+	analyzer *Analyzer
+
+	store   SnapshotStore
+	pending chan Totals
+	done    chan struct{}
+}
+
+// NewCollector creates a collector. store may be nil for ephemeral use.
+func NewCollector(analyzer *Analyzer, store SnapshotStore) *Collector {
+	return &Collector{
+		analyzer: analyzer,
+		store:    store,
+		pending:  make(chan Totals, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+// Recover loads the last snapshot so counters continue where the previous
+// process stopped. Call once before Start.
+func (c *Collector) Recover(ctx context.Context) error {
+	if c.store == nil {
+		return nil
+	}
+	t, err := c.store.Load(ctx)
+	if errors.Is(err, ErrNoSnapshot) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.totals = t
+	c.mu.Unlock()
+	return nil
+}
+
+// Start launches the write-behind flusher snapshotting every interval.
+func (c *Collector) Start(ctx context.Context, interval time.Duration) {
+	if c.store == nil {
+		return
+	}
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.enqueueSnapshot()
+			case t := <-c.pending:
+				if err := c.store.Save(ctx, t); err != nil {
+					log.Printf("stats: snapshot: %v", err)
+				}
+			case <-ctx.Done():
+				// Final synchronous flush on shutdown.
+				if err := c.store.Save(context.Background(), c.Totals()); err != nil {
+					log.Printf("stats: final snapshot: %v", err)
+				}
+				return
+			}
+		}
+	}()
+}
+
+// Wait blocks until the flusher has written its final snapshot.
+func (c *Collector) Wait() { <-c.done }
+
+// enqueueSnapshot hands the current totals to the flusher without
+// blocking the caller; a still-pending older snapshot is replaced.
+func (c *Collector) enqueueSnapshot() {
+	t := c.Totals()
+	select {
+	case c.pending <- t:
+	default:
+		select {
+		case <-c.pending:
+		default:
+		}
+		c.pending <- t
+	}
+}
+
+// Observe records one completed request.
+func (c *Collector) Observe(s Sample, failed bool) {
+	s.Failed = failed
+	c.mu.Lock()
+	c.totals.TotalRequests++
+	if failed {
+		c.totals.TotalErrors++
+	}
+	c.totals.TotalTokens += s.Tokens
+	c.totals.TotalCost += s.Cost
+	c.mu.Unlock()
+	if c.analyzer != nil {
+		c.analyzer.Record(s)
+	}
+}
+
+// Totals returns a copy of the cumulative counters.
+func (c *Collector) Totals() Totals {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.totals
+}
+
+// SQLSnapshotStore keeps the snapshot as a single JSON row, which is
+// enough durability for counters that are reconciled against RequestLog.
+type SQLSnapshotStore struct {
+	db *sql.DB
+}
+
+// NewSQLSnapshotStore prepares the snapshot table.
+func NewSQLSnapshotStore(ctx context.Context, db *sql.DB) (*SQLSnapshotStore, error) {
+	_, err := db.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS stats_snapshot (
+    id         INT PRIMARY KEY,
+    totals     TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+)`)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLSnapshotStore{db: db}, nil
+}
+
+// Save implements SnapshotStore.
+func (s *SQLSnapshotStore) Save(ctx context.Context, t Totals) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+INSERT INTO stats_snapshot (id, totals, updated_at) VALUES (1, $1, $2)
+ON CONFLICT (id) DO UPDATE SET totals = $1, updated_at = $2`,
+		string(data), time.Now().UTC())
+	return err
+}
+
+// Load implements SnapshotStore.
+func (s *SQLSnapshotStore) Load(ctx context.Context) (Totals, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT totals FROM stats_snapshot WHERE id = 1`).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Totals{}, ErrNoSnapshot
+	}
+	if err != nil {
+		return Totals{}, err
+	}
+	var t Totals
+	return t, json.Unmarshal([]byte(data), &t)
+}
@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memSnapshotStore is an in-memory SnapshotStore for tests.
+type memSnapshotStore struct {
+	mu    sync.Mutex
+	t     Totals
+	saved bool
+}
+
+func (m *memSnapshotStore) Save(_ context.Context, t Totals) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.t, m.saved = t, true
+	return nil
+}
+
+func (m *memSnapshotStore) Load(context.Context) (Totals, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.saved {
+		return Totals{}, ErrNoSnapshot
+	}
+	return m.t, nil
+}
+
+func TestCollectorRecoverContinuesCounting(t *testing.T) {
+	store := &memSnapshotStore{}
+	store.t, store.saved = Totals{TotalRequests: 40, TotalCost: 1.5}, true
+
+	c := NewCollector(nil, store)
+	if err := c.Recover(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	c.Observe(Sample{Provider: "p", Cost: 0.5, Tokens: 10}, false)
+	c.Observe(Sample{Provider: "p"}, true)
+
+	got := c.Totals()
+	if got.TotalRequests != 42 || got.TotalErrors != 1 || got.TotalCost != 2.0 {
+		t.Fatalf("totals after recover = %+v", got)
+	}
+}
+
+func TestCollectorFinalFlushOnShutdown(t *testing.T) {
+	store := &memSnapshotStore{}
+	c := NewCollector(nil, store)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Start(ctx, time.Hour)
+	c.Observe(Sample{Provider: "p", Cost: 1}, false)
+	cancel()
+	c.Wait()
+
+	saved, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.TotalRequests != 1 || saved.TotalCost != 1 {
+		t.Fatalf("final snapshot = %+v", saved)
+	}
+}
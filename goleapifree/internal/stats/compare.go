@@ -0,0 +1,155 @@
+package stats
+
+import (
+	"sort"
+	"time"
+)
+
+// Range comparison: "is this week worse than last week?" should be one
+// query with computed deltas, not two charts to eyeball. The analyzer
+// summarizes both ranges per provider and reports absolute and percent
+// change for every metric.
+
+// Range is a half-open [From, To) time range.
+type Range struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// contains reports whether a bucket start falls inside the range.
+func (r Range) contains(t time.Time) bool {
+	return !t.Before(r.From) && t.Before(r.To)
+}
+
+// RangeSummary aggregates one series over one range.
+type RangeSummary struct {
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"`
+	ErrorRate    float64 `json:"error_rate"`
+	Tokens       int64   `json:"tokens"`
+	Cost         float64 `json:"cost"`
+	P95LatencyMs float64 `json:"p95_latency_ms"`
+}
+
+// Delta is the change from range A to range B. Pct is relative to A and
+// reported as 0 when A had nothing to compare against.
+type Delta struct {
+	Abs float64 `json:"abs"`
+	Pct float64 `json:"pct"`
+}
+
+func delta(a, b float64) Delta {
+	d := Delta{Abs: b - a}
+	if a != 0 {
+		d.Pct = (b - a) / a * 100
+	}
+	return d
+}
+
+// Deltas collects the per-metric changes of one comparison row.
+type Deltas struct {
+	Requests     Delta `json:"requests"`
+	Tokens       Delta `json:"tokens"`
+	Cost         Delta `json:"cost"`
+	P95LatencyMs Delta `json:"p95_latency_ms"`
+	ErrorRate    Delta `json:"error_rate"`
+}
+
+// KeyComparison is one row of the report: a provider (or the overall
+// total) summarized over both ranges with computed deltas.
+type KeyComparison struct {
+	Key    string       `json:"key"`
+	A      RangeSummary `json:"a"`
+	B      RangeSummary `json:"b"`
+	Deltas Deltas       `json:"deltas"`
+}
+
+// CompareReport contrasts two time ranges across every provider.
+type CompareReport struct {
+	RangeA    Range           `json:"range_a"`
+	RangeB    Range           `json:"range_b"`
+	Total     KeyComparison   `json:"total"`
+	Providers []KeyComparison `json:"providers"`
+}
+
+// Compare summarizes both ranges for every provider and the overall
+// total. Providers with no traffic in either range are omitted.
+func (a *Analyzer) Compare(ra, rb Range) CompareReport {
+	report := CompareReport{RangeA: ra, RangeB: rb}
+	var totalA, totalB aggregate
+	for _, provider := range a.Providers() {
+		aggA := a.summarize("provider:"+provider, ra)
+		aggB := a.summarize("provider:"+provider, rb)
+		if aggA.requests == 0 && aggB.requests == 0 {
+			continue
+		}
+		report.Providers = append(report.Providers, compareRow(provider, aggA, aggB))
+		totalA.fold(aggA)
+		totalB.fold(aggB)
+	}
+	report.Total = compareRow("total", totalA, totalB)
+	return report
+}
+
+// aggregate is the mutable working state behind a RangeSummary.
+type aggregate struct {
+	requests, errors, tokens int64
+	cost                     float64
+	latencies                []float64
+}
+
+func (g *aggregate) fold(other aggregate) {
+	g.requests += other.requests
+	g.errors += other.errors
+	g.tokens += other.tokens
+	g.cost += other.cost
+	g.latencies = append(g.latencies, other.latencies...)
+}
+
+func (g aggregate) summary() RangeSummary {
+	s := RangeSummary{
+		Requests: g.requests, Errors: g.errors,
+		Tokens: g.tokens, Cost: g.cost,
+	}
+	if g.requests > 0 {
+		s.ErrorRate = float64(g.errors) / float64(g.requests)
+	}
+	if len(g.latencies) > 0 {
+		sorted := append([]float64(nil), g.latencies...)
+		sort.Float64s(sorted)
+		s.P95LatencyMs = sorted[int(0.95*float64(len(sorted)-1))]
+	}
+	return s
+}
+
+// summarize folds one series' buckets inside the range.
+func (a *Analyzer) summarize(key string, r Range) aggregate {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var g aggregate
+	for _, b := range a.byKey[key] {
+		if !r.contains(b.Start) {
+			continue
+		}
+		g.requests += b.Requests
+		g.errors += b.Errors
+		g.tokens += b.Tokens
+		g.cost += b.Cost
+		g.latencies = append(g.latencies, b.latencies...)
+	}
+	return g
+}
+
+func compareRow(key string, aggA, aggB aggregate) KeyComparison {
+	sa, sb := aggA.summary(), aggB.summary()
+	return KeyComparison{
+		Key: key, A: sa, B: sb,
+		Deltas: Deltas{
+			Requests:     delta(float64(sa.Requests), float64(sb.Requests)),
+			Tokens:       delta(float64(sa.Tokens), float64(sb.Tokens)),
+			Cost:         delta(sa.Cost, sb.Cost),
+			P95LatencyMs: delta(sa.P95LatencyMs, sb.P95LatencyMs),
+			ErrorRate:    delta(sa.ErrorRate, sb.ErrorRate),
+		},
+	}
+}
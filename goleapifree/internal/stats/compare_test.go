@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompareComputesDeltasPerProvider(t *testing.T) {
+	a := NewAnalyzer(30 * 24 * time.Hour)
+	a.now = func() time.Time { return time.Date(2026, 9, 14, 0, 0, 0, 0, time.UTC) }
+
+	lastWeek := time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)
+	thisWeek := lastWeek.Add(7 * 24 * time.Hour)
+	for i := 0; i < 10; i++ {
+		a.Record(Sample{Provider: "p1", Cost: 1, LatencyMs: 100, At: lastWeek})
+	}
+	for i := 0; i < 20; i++ {
+		a.Record(Sample{Provider: "p1", Cost: 1, LatencyMs: 200, Failed: i < 2, At: thisWeek})
+	}
+	a.Record(Sample{Provider: "p2", Cost: 5, At: thisWeek})
+
+	ra := Range{From: lastWeek.Add(-time.Hour), To: lastWeek.Add(24 * time.Hour)}
+	rb := Range{From: thisWeek.Add(-time.Hour), To: thisWeek.Add(24 * time.Hour)}
+	report := a.Compare(ra, rb)
+
+	if len(report.Providers) != 2 {
+		t.Fatalf("providers = %+v", report.Providers)
+	}
+	p1 := report.Providers[0]
+	if p1.Key != "p1" || p1.A.Requests != 10 || p1.B.Requests != 20 {
+		t.Fatalf("p1 = %+v", p1)
+	}
+	if p1.Deltas.Requests.Abs != 10 || p1.Deltas.Requests.Pct != 100 {
+		t.Fatalf("p1 request delta = %+v", p1.Deltas.Requests)
+	}
+	if p1.B.ErrorRate != 0.1 || p1.Deltas.ErrorRate.Abs != 0.1 {
+		t.Fatalf("p1 error rate = %+v", p1.Deltas.ErrorRate)
+	}
+	if p1.A.P95LatencyMs != 100 || p1.B.P95LatencyMs != 200 {
+		t.Fatalf("p1 p95 = %+v / %+v", p1.A, p1.B)
+	}
+
+	// p2 only appears in range B: the percent delta has no baseline.
+	p2 := report.Providers[1]
+	if p2.A.Requests != 0 || p2.B.Cost != 5 || p2.Deltas.Cost.Pct != 0 {
+		t.Fatalf("p2 = %+v", p2)
+	}
+
+	total := report.Total
+	if total.A.Requests != 10 || total.B.Requests != 21 {
+		t.Fatalf("total = %+v", total)
+	}
+}
+
+func TestCompareSkipsIdleProviders(t *testing.T) {
+	a := NewAnalyzer(30 * 24 * time.Hour)
+	old := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return old.Add(24 * time.Hour) }
+	a.Record(Sample{Provider: "idle", At: old})
+
+	recent := Range{From: old.Add(12 * time.Hour), To: old.Add(14 * time.Hour)}
+	report := a.Compare(recent, recent)
+	if len(report.Providers) != 0 {
+		t.Fatalf("idle provider reported: %+v", report.Providers)
+	}
+}
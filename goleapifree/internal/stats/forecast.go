@@ -0,0 +1,149 @@
+package stats
+
+import "time"
+
+// seasonLen is the seasonal period of the forecast model in buckets.
+// Usage has a strong daily rhythm, so one season = 24 hourly buckets.
+const seasonLen = 24
+
+// Forecast is a projection of usage over a future horizon for one
+// provider or tenant dimension.
+type Forecast struct {
+	Key      string    `json:"key"`
+	From     time.Time `json:"from"`
+	Horizon  int       `json:"horizon_hours"`
+	Requests []float64 `json:"requests"`
+	Cost     []float64 `json:"cost"`
+}
+
+// TotalRequests sums the projected request volume over the horizon.
+func (f Forecast) TotalRequests() float64 { return sum(f.Requests) }
+
+// TotalCost sums the projected cost over the horizon.
+func (f Forecast) TotalCost() float64 { return sum(f.Cost) }
+
+// Forecaster fits an additive Holt-Winters model over an Analyzer's hourly
+// buckets and projects future volume and spend. Alpha/beta/gamma are the
+// usual level/trend/seasonality smoothing factors.
+type Forecaster struct {
+	Analyzer *Analyzer
+	Alpha    float64
+	Beta     float64
+	Gamma    float64
+}
+
+// NewForecaster returns a forecaster with smoothing defaults that favour
+// the daily seasonal component, which dominates LLM traffic.
+func NewForecaster(a *Analyzer) *Forecaster {
+	return &Forecaster{Analyzer: a, Alpha: 0.3, Beta: 0.05, Gamma: 0.4}
+}
+
+// Provider projects the next horizon hours of usage for a provider.
+func (f *Forecaster) Provider(provider string, horizon int) Forecast {
+	return f.project("provider:"+provider, f.Analyzer.ProviderBuckets(provider), horizon)
+}
+
+// Tenant projects the next horizon hours of usage for a tenant.
+func (f *Forecaster) Tenant(tenant string, horizon int) Forecast {
+	return f.project("tenant:"+tenant, f.Analyzer.TenantBuckets(tenant), horizon)
+}
+
+func (f *Forecaster) project(key string, buckets []Bucket, horizon int) Forecast {
+	reqs := make([]float64, len(buckets))
+	cost := make([]float64, len(buckets))
+	for i, b := range buckets {
+		reqs[i] = float64(b.Requests)
+		cost[i] = b.Cost
+	}
+	out := Forecast{Key: key, Horizon: horizon}
+	if len(buckets) > 0 {
+		out.From = buckets[len(buckets)-1].Start.Add(time.Hour)
+	}
+	out.Requests = f.holtWinters(reqs, horizon)
+	out.Cost = f.holtWinters(cost, horizon)
+	return out
+}
+
+// holtWinters runs additive Holt-Winters over the series and returns the
+// next horizon values. With less than two full seasons of history it falls
+// back to projecting the series mean, which is the honest answer when
+// there is no seasonality to learn yet.
+func (f *Forecaster) holtWinters(series []float64, horizon int) []float64 {
+	out := make([]float64, horizon)
+	if len(series) < 2*seasonLen {
+		mean := 0.0
+		if len(series) > 0 {
+			mean = sum(series) / float64(len(series))
+		}
+		for i := range out {
+			out[i] = mean
+		}
+		return out
+	}
+
+	// Initial level/trend from the first two seasons, seasonal indices
+	// from the first season relative to its mean.
+	level := sum(series[:seasonLen]) / seasonLen
+	second := sum(series[seasonLen:2*seasonLen]) / seasonLen
+	trend := (second - level) / seasonLen
+	season := make([]float64, seasonLen)
+	for i := 0; i < seasonLen; i++ {
+		season[i] = series[i] - level
+	}
+
+	for i := seasonLen; i < len(series); i++ {
+		s := i % seasonLen
+		prevLevel := level
+		level = f.Alpha*(series[i]-season[s]) + (1-f.Alpha)*(level+trend)
+		trend = f.Beta*(level-prevLevel) + (1-f.Beta)*trend
+		season[s] = f.Gamma*(series[i]-level) + (1-f.Gamma)*season[s]
+	}
+
+	for h := 0; h < horizon; h++ {
+		s := (len(series) + h) % seasonLen
+		v := level + float64(h+1)*trend + season[s]
+		if v < 0 {
+			v = 0
+		}
+		out[h] = v
+	}
+	return out
+}
+
+// BudgetAlert flags a dimension whose projected spend exceeds its budget.
+type BudgetAlert struct {
+	Key       string  `json:"key"`
+	Budget    float64 `json:"budget"`
+	Projected float64 `json:"projected"`
+}
+
+// CheckBudgets projects the next week for each keyed budget and returns an
+// alert for every dimension forecast to overrun. Keys use the analyzer's
+// dimension form, e.g. "provider:openai" or "tenant:acme".
+func (f *Forecaster) CheckBudgets(budgets map[string]float64) []BudgetAlert {
+	const week = 7 * 24
+	var alerts []BudgetAlert
+	for key, budget := range budgets {
+		var fc Forecast
+		switch {
+		case len(key) > 9 && key[:9] == "provider:":
+			fc = f.Provider(key[9:], week)
+		case len(key) > 7 && key[:7] == "tenant:":
+			fc = f.Tenant(key[7:], week)
+		default:
+			continue
+		}
+		if projected := fc.TotalCost(); projected > budget {
+			alerts = append(alerts, BudgetAlert{Key: key, Budget: budget, Projected: projected})
+		}
+	}
+	return alerts
+}
+
+func sum(xs []float64) float64 {
+	t := 0.0
+	for _, x := range xs {
+		t += x
+	}
+	return t
+}
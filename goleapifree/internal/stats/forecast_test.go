@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+// seedDaily records days of synthetic traffic with a fixed daily shape.
+func seedDaily(a *Analyzer, provider string, days int, shape func(hour int) int) {
+	start := time.Now().UTC().Truncate(time.Hour).Add(-time.Duration(days*24) * time.Hour)
+	for d := 0; d < days; d++ {
+		for h := 0; h < 24; h++ {
+			at := start.Add(time.Duration(d*24+h) * time.Hour)
+			for i := 0; i < shape(h); i++ {
+				a.Record(Sample{Provider: provider, Cost: 0.01, At: at})
+			}
+		}
+	}
+}
+
+func TestForecastLearnsDailyShape(t *testing.T) {
+	a := NewAnalyzer(14 * 24 * time.Hour)
+	// Busy at hour 12, quiet at hour 0.
+	seedDaily(a, "groq", 7, func(h int) int {
+		if h == 12 {
+			return 20
+		}
+		return 2
+	})
+
+	fc := NewForecaster(a).Provider("groq", 24)
+	if len(fc.Requests) != 24 {
+		t.Fatalf("horizon = %d, want 24", len(fc.Requests))
+	}
+	// The seeded series peaks at offset 12 within each 24-bucket season;
+	// the forecast continues that pattern by position.
+	peakAt, peak, trough := 0, 0.0, fc.Requests[0]
+	for h, v := range fc.Requests {
+		if v > peak {
+			peakAt, peak = h, v
+		}
+		if v < trough {
+			trough = v
+		}
+	}
+	if peakAt%24 != 12 {
+		t.Fatalf("forecast peak at offset %d, want 12:\n%v", peakAt, fc.Requests)
+	}
+	if peak < 2*trough {
+		t.Fatalf("forecast flattened daily shape: peak %.1f, trough %.1f", peak, trough)
+	}
+}
+
+func TestCheckBudgets(t *testing.T) {
+	a := NewAnalyzer(14 * 24 * time.Hour)
+	seedDaily(a, "openai", 7, func(int) int { return 5 })
+
+	f := NewForecaster(a)
+	alerts := f.CheckBudgets(map[string]float64{"provider:openai": 0.01})
+	if len(alerts) != 1 || alerts[0].Key != "provider:openai" {
+		t.Fatalf("expected one openai budget alert, got %+v", alerts)
+	}
+	if alerts := f.CheckBudgets(map[string]float64{"provider:openai": 1e9}); len(alerts) != 0 {
+		t.Fatalf("generous budget should not alert, got %+v", alerts)
+	}
+}
@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// PrivateAggregator produces noise-injected aggregate usage figures for
+// the public dashboard. Counts get Laplace noise calibrated to Epsilon,
+// and dimensions with fewer than MinGroupSize contributors are suppressed
+// entirely, so individual tenants cannot be reverse-engineered from the
+// published numbers.
+type PrivateAggregator struct {
+	Analyzer *Analyzer
+	// Epsilon is the differential privacy budget per published figure;
+	// smaller means noisier. 1.0 is a reasonable dashboard default.
+	Epsilon float64
+	// MinGroupSize suppresses aggregates built from fewer tenants.
+	MinGroupSize int
+
+	noise func(scale float64) float64
+}
+
+// NewPrivateAggregator wraps an analyzer with DP defaults.
+func NewPrivateAggregator(a *Analyzer) *PrivateAggregator {
+	p := &PrivateAggregator{Analyzer: a, Epsilon: 1.0, MinGroupSize: 5}
+	p.noise = p.laplace
+	return p
+}
+
+// PublicUsage is an aggregate safe to publish.
+type PublicUsage struct {
+	Window     string  `json:"window"`
+	Requests   int64   `json:"requests"`
+	Tokens     int64   `json:"tokens"`
+	Suppressed bool    `json:"suppressed"`
+	Epsilon    float64 `json:"epsilon"`
+}
+
+// Usage returns the noisy total usage across all tenants for the last
+// window. When fewer than MinGroupSize tenants contributed, the figure is
+// suppressed rather than published with noise that could be averaged out.
+func (p *PrivateAggregator) Usage(window time.Duration) PublicUsage {
+	out := PublicUsage{Window: window.String(), Epsilon: p.Epsilon}
+	tenants := p.Analyzer.Tenants()
+	if len(tenants) < p.MinGroupSize {
+		out.Suppressed = true
+		return out
+	}
+	cutoff := time.Now().Add(-window)
+	var requests, tokens int64
+	for _, t := range tenants {
+		for _, b := range p.Analyzer.TenantBuckets(t) {
+			if b.Start.Before(cutoff) {
+				continue
+			}
+			requests += b.Requests
+			tokens += b.Tokens
+		}
+	}
+	// Sensitivity 1 for request counts; token counts use a coarse
+	// per-request bound so one tenant's removal stays masked.
+	out.Requests = clampNonNegative(requests + int64(p.noise(1/p.Epsilon)))
+	out.Tokens = clampNonNegative(tokens + int64(p.noise(4096/p.Epsilon)))
+	return out
+}
+
+// laplace draws from the Laplace distribution with the given scale.
+func (p *PrivateAggregator) laplace(scale float64) float64 {
+	u := rand.Float64() - 0.5
+	return -scale * sign(u) * math.Log(1-2*math.Abs(u))
+}
+
+func clampNonNegative(v int64) int64 {
+	if v < 0 {
+		return 0
+	}
+	return v
+}
+
+func sign(f float64) float64 {
+	if f < 0 {
+		return -1
+	}
+	return 1
+}
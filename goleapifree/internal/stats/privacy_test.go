@@ -0,0 +1,46 @@
+package stats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPrivateAggregatorSuppressesSmallGroups(t *testing.T) {
+	a := NewAnalyzer(48 * time.Hour)
+	a.Record(Sample{Provider: "p", Tenant: "only-tenant", Tokens: 100})
+
+	p := NewPrivateAggregator(a)
+	out := p.Usage(24 * time.Hour)
+	if !out.Suppressed || out.Requests != 0 {
+		t.Fatalf("small group not suppressed: %+v", out)
+	}
+}
+
+func TestPrivateAggregatorAddsNoise(t *testing.T) {
+	a := NewAnalyzer(48 * time.Hour)
+	for i := 0; i < 10; i++ {
+		tenant := fmt.Sprintf("tenant-%d", i)
+		for j := 0; j < 100; j++ {
+			a.Record(Sample{Provider: "p", Tenant: tenant, Tokens: 10})
+		}
+	}
+
+	p := NewPrivateAggregator(a)
+	var injected float64 = 123
+	p.noise = func(scale float64) float64 { return injected }
+
+	out := p.Usage(24 * time.Hour)
+	if out.Suppressed {
+		t.Fatalf("large group suppressed: %+v", out)
+	}
+	if out.Requests != 1000+123 {
+		t.Fatalf("requests = %d, want exact noise application 1123", out.Requests)
+	}
+
+	// Negative noise must clamp at zero, never publish negatives.
+	injected = -1e9
+	if out := p.Usage(24 * time.Hour); out.Requests != 0 || out.Tokens != 0 {
+		t.Fatalf("negative aggregate published: %+v", out)
+	}
+}
@@ -0,0 +1,193 @@
+package stats
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+// ErrBadCursor is returned for cursors the gateway did not issue.
+var ErrBadCursor = errors.New("stats: invalid usage cursor")
+
+// Usage pagination bounds.
+const (
+	defaultUsagePageSize = 100
+	maxUsagePageSize     = 1000
+	// defaultUsageEntries caps the in-memory usage window.
+	defaultUsageEntries = 100_000
+)
+
+// UsageEntry is one request as exposed on /v1/usage: accounting fields
+// only, never prompt or response bodies.
+type UsageEntry struct {
+	ID       string `json:"id"`
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	Tokens   int64  `json:"tokens"`
+	// ReasoningTokens splits out the thinking share of Tokens for
+	// reasoning models; 0 otherwise.
+	ReasoningTokens int64     `json:"reasoning_tokens,omitempty"`
+	Cost            float64   `json:"cost"`
+	LatencyMs       float64   `json:"latency_ms"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// UsageQuery filters and positions one usage page.
+type UsageQuery struct {
+	// Tenant scopes the listing; empty lists everything, which single
+	// tenant deployments use.
+	Tenant string
+	// From/To bound CreatedAt (inclusive from, exclusive to); zero
+	// values leave that side open.
+	From, To time.Time
+	// Limit caps the page size; 0 applies the default.
+	Limit int
+	// Cursor resumes after the last entry of the previous page.
+	Cursor string
+}
+
+// UsagePage is one page of entries in stable (created_at, id) ascending
+// order, so SDKs can sync incrementally and never see an entry twice.
+type UsagePage struct {
+	Data       []UsageEntry `json:"data"`
+	HasMore    bool         `json:"has_more"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+// UsageLog is the bounded in-memory window of request logs behind the
+// public usage endpoint. It satisfies the batcher's LogWriter, so it
+// tees off the same batches the durable store receives.
+type UsageLog struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries []models.RequestLog
+}
+
+// NewUsageLog creates a usage log retaining at most maxEntries recent
+// requests; 0 applies the default cap.
+func NewUsageLog(maxEntries int) *UsageLog {
+	if maxEntries <= 0 {
+		maxEntries = defaultUsageEntries
+	}
+	return &UsageLog{maxEntries: maxEntries}
+}
+
+// WriteBatch implements the batcher's LogWriter.
+func (u *UsageLog) WriteBatch(_ context.Context, logs []models.RequestLog) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.entries = append(u.entries, logs...)
+	if excess := len(u.entries) - u.maxEntries; excess > 0 {
+		u.entries = append(u.entries[:0:0], u.entries[excess:]...)
+	}
+	return nil
+}
+
+// Page returns one page of matching entries. Ordering is ascending by
+// (created_at, id): ties on the timestamp are broken by ID, which makes
+// the cursor position unambiguous even for batched writes sharing a
+// timestamp.
+func (u *UsageLog) Page(q UsageQuery) (UsagePage, error) {
+	limit := q.Limit
+	switch {
+	case limit <= 0:
+		limit = defaultUsagePageSize
+	case limit > maxUsagePageSize:
+		limit = maxUsagePageSize
+	}
+	afterAt, afterID, err := decodeUsageCursor(q.Cursor)
+	if err != nil {
+		return UsagePage{}, err
+	}
+
+	u.mu.Lock()
+	matched := make([]models.RequestLog, 0, limit+1)
+	for _, row := range u.entries {
+		if q.Tenant != "" && row.Tenant != q.Tenant {
+			continue
+		}
+		if !q.From.IsZero() && row.CreatedAt.Before(q.From) {
+			continue
+		}
+		if !q.To.IsZero() && !row.CreatedAt.Before(q.To) {
+			continue
+		}
+		if q.Cursor != "" && !afterCursor(row, afterAt, afterID) {
+			continue
+		}
+		matched = append(matched, row)
+	}
+	u.mu.Unlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].CreatedAt.Before(matched[j].CreatedAt)
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	page := UsagePage{Data: make([]UsageEntry, 0, limit)}
+	for _, row := range matched {
+		if len(page.Data) == limit {
+			page.HasMore = true
+			break
+		}
+		page.Data = append(page.Data, UsageEntry{
+			ID:              row.ID,
+			Provider:        row.ProviderID,
+			Model:           row.Model,
+			Tokens:          row.Tokens,
+			ReasoningTokens: row.ReasoningTokens,
+			Cost:            row.Cost,
+			LatencyMs:       row.LatencyMs,
+			CreatedAt:       row.CreatedAt,
+		})
+	}
+	if page.HasMore {
+		last := page.Data[len(page.Data)-1]
+		page.NextCursor = encodeUsageCursor(last.CreatedAt, last.ID)
+	}
+	return page, nil
+}
+
+// afterCursor reports whether row sorts strictly after the cursor
+// position in (created_at, id) order.
+func afterCursor(row models.RequestLog, at time.Time, id string) bool {
+	if row.CreatedAt.After(at) {
+		return true
+	}
+	return row.CreatedAt.Equal(at) && row.ID > id
+}
+
+// Usage cursors are opaque to clients: base64 over the sort key, so a
+// cursor survives restarts and entries expiring out of the window.
+func encodeUsageCursor(at time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", at.UnixNano(), id)))
+}
+
+func decodeUsageCursor(cursor string) (time.Time, string, error) {
+	if cursor == "" {
+		return time.Time{}, "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrBadCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, "", ErrBadCursor
+	}
+	var ts int64
+	if _, err := fmt.Sscanf(nanos, "%d", &ts); err != nil {
+		return time.Time{}, "", ErrBadCursor
+	}
+	return time.Unix(0, ts), id, nil
+}
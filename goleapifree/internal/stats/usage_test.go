@@ -0,0 +1,105 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+)
+
+func seedUsage(t *testing.T, u *UsageLog, n int) time.Time {
+	t.Helper()
+	base := time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)
+	logs := make([]models.RequestLog, 0, n)
+	for i := 0; i < n; i++ {
+		logs = append(logs, models.RequestLog{
+			ID:         fmt.Sprintf("req-%03d", i),
+			ProviderID: "openai",
+			Tenant:     "acme",
+			Model:      "fast",
+			Tokens:     10,
+			CreatedAt:  base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	if err := u.WriteBatch(context.Background(), logs); err != nil {
+		t.Fatal(err)
+	}
+	return base
+}
+
+func TestUsagePagination(t *testing.T) {
+	u := NewUsageLog(0)
+	seedUsage(t, u, 25)
+
+	var seen []string
+	cursor := ""
+	pages := 0
+	for {
+		page, err := u.Page(UsageQuery{Tenant: "acme", Limit: 10, Cursor: cursor})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range page.Data {
+			seen = append(seen, e.ID)
+		}
+		pages++
+		if !page.HasMore {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if pages != 3 || len(seen) != 25 {
+		t.Fatalf("pages = %d, entries = %d", pages, len(seen))
+	}
+	for i := 1; i < len(seen); i++ {
+		if seen[i] <= seen[i-1] {
+			t.Fatalf("ordering unstable at %d: %s after %s", i, seen[i], seen[i-1])
+		}
+	}
+}
+
+func TestUsageDateFilters(t *testing.T) {
+	u := NewUsageLog(0)
+	base := seedUsage(t, u, 10)
+
+	page, err := u.Page(UsageQuery{From: base.Add(3 * time.Minute), To: base.Add(7 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Data) != 4 || page.HasMore {
+		t.Fatalf("page = %+v", page)
+	}
+	if page.Data[0].ID != "req-003" || page.Data[3].ID != "req-006" {
+		t.Fatalf("bounds wrong: %s .. %s", page.Data[0].ID, page.Data[3].ID)
+	}
+	if page.Data[0].CreatedAt.IsZero() || page.Data[0].Provider != "openai" {
+		t.Fatalf("entry = %+v", page.Data[0])
+	}
+
+	other, _ := u.Page(UsageQuery{Tenant: "globex"})
+	if len(other.Data) != 0 {
+		t.Fatal("tenant filter leaked")
+	}
+}
+
+func TestUsageBadCursor(t *testing.T) {
+	u := NewUsageLog(0)
+	if _, err := u.Page(UsageQuery{Cursor: "not-a-cursor"}); !errors.Is(err, ErrBadCursor) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestUsageWindowBounded(t *testing.T) {
+	u := NewUsageLog(5)
+	seedUsage(t, u, 10)
+	page, err := u.Page(UsageQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Data) != 5 || page.Data[0].ID != "req-005" {
+		t.Fatalf("window = %+v", page.Data)
+	}
+}
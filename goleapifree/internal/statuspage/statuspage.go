@@ -0,0 +1,168 @@
+// Package statuspage renders the public, unauthenticated status page:
+// gateway uptime, per-provider state and recent incident history, for
+// teams exposing the gateway to internal customers. Data comes in
+// through hooks so the page never reaches into authenticated
+// subsystems; the wiring serves HTML from a separate route or exports
+// it as a static file on a schedule.
+package statuspage
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxHistory bounds how many past events the page shows.
+const maxHistory = 20
+
+// Provider states shown on the page.
+const (
+	StateOperational = "operational"
+	StateDegraded    = "degraded"
+	StateDown        = "down"
+)
+
+// ProviderStatus is one provider's row on the status page. Names are
+// display names — the page is public, so internal IDs stay internal.
+type ProviderStatus struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	Note  string `json:"note,omitempty"`
+}
+
+// Event is one entry in the incident history, newest first.
+type Event struct {
+	At       time.Time  `json:"at"`
+	Title    string     `json:"title"`
+	Severity string     `json:"severity,omitempty"`
+	Resolved *time.Time `json:"resolved,omitempty"`
+}
+
+// Snapshot is the page's data, also served as JSON for tooling.
+type Snapshot struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	Overall     string           `json:"overall"`
+	Uptime      float64          `json:"uptime_24h"`
+	Providers   []ProviderStatus `json:"providers"`
+	History     []Event          `json:"history,omitempty"`
+}
+
+// Page assembles the status page from its sources.
+type Page struct {
+	// Title heads the page; empty uses the default.
+	Title string
+	// Uptime reports 24h availability in [0,1]; the wiring binds it to
+	// the SLO tracker's compliance.
+	Uptime func() float64
+	// Providers lists current provider states.
+	Providers func() []ProviderStatus
+	// History lists past incidents, newest first; the wiring binds it
+	// to the alerting subsystem.
+	History func() []Event
+
+	now func() time.Time
+}
+
+// New creates a page with the given sources; any may be nil and its
+// section is simply omitted.
+func New() *Page {
+	return &Page{now: time.Now}
+}
+
+// Snapshot gathers the current page data.
+func (p *Page) Snapshot() Snapshot {
+	s := Snapshot{GeneratedAt: p.now(), Overall: StateOperational}
+	if p.Uptime != nil {
+		s.Uptime = p.Uptime()
+	}
+	if p.Providers != nil {
+		s.Providers = p.Providers()
+	}
+	if p.History != nil {
+		s.History = p.History()
+		if len(s.History) > maxHistory {
+			s.History = s.History[:maxHistory]
+		}
+	}
+	for _, prov := range s.Providers {
+		switch prov.State {
+		case StateDown:
+			s.Overall = StateDown
+		case StateDegraded:
+			if s.Overall == StateOperational {
+				s.Overall = StateDegraded
+			}
+		}
+	}
+	// An unresolved incident keeps the page from claiming all-clear.
+	if s.Overall == StateOperational {
+		for _, e := range s.History {
+			if e.Resolved == nil {
+				s.Overall = StateDegraded
+				break
+			}
+		}
+	}
+	return s
+}
+
+// HTML renders the page as one self-contained document: inline styles,
+// no scripts, nothing to fetch — it survives being served from a file.
+func (p *Page) HTML() []byte {
+	s := p.Snapshot()
+	title := p.Title
+	if title == "" {
+		title = "goleapai status"
+	}
+
+	var b strings.Builder
+	b.WriteString("<!doctype html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(title))
+	b.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	b.WriteString("<style>body{font-family:sans-serif;max-width:40em;margin:2em auto;padding:0 1em}" +
+		".operational{color:#2a7}.degraded{color:#b80}.down{color:#c33}" +
+		"table{border-collapse:collapse;width:100%}td,th{text-align:left;padding:.3em .6em;border-bottom:1px solid #ddd}" +
+		"footer{color:#888;font-size:.8em;margin-top:2em}</style>\n</head>\n<body>\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(title))
+	fmt.Fprintf(&b, "<p>Current state: <strong class=%q>%s</strong>", s.Overall, html.EscapeString(s.Overall))
+	if p.Uptime != nil {
+		fmt.Fprintf(&b, " &middot; 24h uptime %.2f%%", s.Uptime*100)
+	}
+	b.WriteString("</p>\n")
+
+	if len(s.Providers) > 0 {
+		b.WriteString("<h2>Providers</h2>\n<table>\n")
+		for _, prov := range s.Providers {
+			fmt.Fprintf(&b, "<tr><td>%s</td><td class=%q>%s</td><td>%s</td></tr>\n",
+				html.EscapeString(prov.Name), prov.State,
+				html.EscapeString(prov.State), html.EscapeString(prov.Note))
+		}
+		b.WriteString("</table>\n")
+	}
+
+	if len(s.History) > 0 {
+		b.WriteString("<h2>Incident history</h2>\n<table>\n")
+		for _, e := range s.History {
+			state := "resolved"
+			if e.Resolved == nil {
+				state = "investigating"
+			}
+			fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+				e.At.UTC().Format("2006-01-02 15:04"),
+				html.EscapeString(e.Title), html.EscapeString(state))
+		}
+		b.WriteString("</table>\n")
+	}
+
+	fmt.Fprintf(&b, "<footer>Generated %s</footer>\n</body>\n</html>\n",
+		s.GeneratedAt.UTC().Format(time.RFC3339))
+	return []byte(b.String())
+}
+
+// Export writes the rendered page to path, for deployments that serve
+// the status page as a static file from a separate host.
+func (p *Page) Export(path string) error {
+	return os.WriteFile(path, p.HTML(), 0o644)
+}
@@ -0,0 +1,80 @@
+package statuspage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testPage() *Page {
+	p := New()
+	p.now = func() time.Time { return time.Date(2026, 9, 1, 15, 0, 0, 0, time.UTC) }
+	return p
+}
+
+func TestSnapshotOverallState(t *testing.T) {
+	p := testPage()
+	p.Uptime = func() float64 { return 0.9991 }
+	providers := []ProviderStatus{
+		{Name: "OpenAI", State: StateOperational},
+		{Name: "Local Ollama", State: StateOperational},
+	}
+	p.Providers = func() []ProviderStatus { return providers }
+
+	if s := p.Snapshot(); s.Overall != StateOperational || s.Uptime != 0.9991 {
+		t.Fatalf("snapshot = %+v", s)
+	}
+	providers[1].State = StateDegraded
+	if s := p.Snapshot(); s.Overall != StateDegraded {
+		t.Fatalf("overall = %s", p.Snapshot().Overall)
+	}
+	providers[0].State = StateDown
+	if s := p.Snapshot(); s.Overall != StateDown {
+		t.Fatalf("overall = %s", s.Overall)
+	}
+}
+
+func TestOpenIncidentDegradesOverall(t *testing.T) {
+	p := testPage()
+	p.History = func() []Event {
+		return []Event{{At: p.now().Add(-time.Hour), Title: "elevated latency"}}
+	}
+	if s := p.Snapshot(); s.Overall != StateDegraded {
+		t.Fatalf("overall = %s", s.Overall)
+	}
+}
+
+func TestHTMLEscapesAndExports(t *testing.T) {
+	p := testPage()
+	p.Providers = func() []ProviderStatus {
+		return []ProviderStatus{{Name: "<script>alert(1)</script>", State: StateOperational}}
+	}
+	resolved := p.now().Add(-time.Hour)
+	p.History = func() []Event {
+		return []Event{{At: p.now().Add(-2 * time.Hour), Title: "provider outage", Resolved: &resolved}}
+	}
+
+	out := string(p.HTML())
+	if strings.Contains(out, "<script>") {
+		t.Fatal("provider name not escaped")
+	}
+	for _, want := range []string{"goleapai status", "provider outage", "resolved"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("page missing %q:\n%s", want, out)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "status.html")
+	if err := p.Export(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != out {
+		t.Fatal("exported file differs from rendered page")
+	}
+}
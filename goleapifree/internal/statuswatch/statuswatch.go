@@ -0,0 +1,287 @@
+// Package statuswatch tracks provider status pages (OpenAI, Anthropic
+// and other Statuspage-hosted pages) through their webhooks and RSS
+// feeds. While a provider has a declared incident the watcher lowers its
+// routing prior — or excludes it outright for critical impact — so the
+// gateway backs off before its own error budget notices, and the
+// dashboard can link to the provider's incident page.
+package statuswatch
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxIncidentAge expires incidents whose page never sent a resolution,
+// so a missed webhook cannot sideline a provider forever.
+const maxIncidentAge = 24 * time.Hour
+
+// Impact levels, in Statuspage vocabulary.
+const (
+	ImpactMinor    = "minor"
+	ImpactMajor    = "major"
+	ImpactCritical = "critical"
+)
+
+// ErrBadPayload is returned for webhook bodies that do not look like a
+// status-page incident notification.
+var ErrBadPayload = errors.New("statuswatch: unrecognized webhook payload")
+
+// Incident is one declared provider incident.
+type Incident struct {
+	Provider  string    `json:"provider"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Impact    string    `json:"impact"`
+	Status    string    `json:"status"`
+	Link      string    `json:"link,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Feed is one RSS/Atom status feed to poll, for pages that do not offer
+// webhooks.
+type Feed struct {
+	Provider string `json:"provider"`
+	URL      string `json:"url"`
+}
+
+// Watcher holds the active incidents per provider.
+type Watcher struct {
+	// Feeds are polled by Poll; empty when only webhooks are used.
+	Feeds []Feed
+	// Client fetches feeds; nil uses http.DefaultClient.
+	Client *http.Client
+	// OnOpen is called once when an incident opens, e.g. to annotate the
+	// incident timeline. It runs on the delivering goroutine; keep it fast.
+	OnOpen func(providerID, text string)
+
+	mu     sync.Mutex
+	active map[string]map[string]*Incident // provider -> incident ID
+	now    func() time.Time
+}
+
+// NewWatcher returns a watcher with no incidents.
+func NewWatcher() *Watcher {
+	return &Watcher{active: make(map[string]map[string]*Incident), now: time.Now}
+}
+
+// statuspagePayload is the Statuspage.io webhook shape both OpenAI and
+// Anthropic status pages deliver.
+type statuspagePayload struct {
+	Incident struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		Status    string `json:"status"`
+		Impact    string `json:"impact"`
+		Shortlink string `json:"shortlink"`
+	} `json:"incident"`
+}
+
+// HandleWebhook ingests one status-page webhook delivery for a provider.
+// A resolved or postmortem status closes the incident; anything else
+// opens or refreshes it.
+func (w *Watcher) HandleWebhook(providerID string, payload []byte) error {
+	var p statuspagePayload
+	if err := json.Unmarshal(payload, &p); err != nil || p.Incident.ID == "" {
+		return ErrBadPayload
+	}
+	w.record(Incident{
+		Provider:  providerID,
+		ID:        p.Incident.ID,
+		Title:     p.Incident.Name,
+		Impact:    p.Incident.Impact,
+		Status:    p.Incident.Status,
+		Link:      p.Incident.Shortlink,
+		UpdatedAt: w.now(),
+	})
+	return nil
+}
+
+// rssFeed covers the subset of RSS that status-page feeds emit.
+type rssFeed struct {
+	Items []struct {
+		Title string `xml:"title"`
+		Link  string `xml:"link"`
+		GUID  string `xml:"guid"`
+	} `xml:"channel>item"`
+}
+
+// Poll fetches every configured feed once. The newest item per feed is
+// treated as the page's current word: an incident unless its title says
+// resolved. Poll matches scheduler.JobFunc. Fetch failures are joined,
+// and one broken feed does not stop the others.
+func (w *Watcher) Poll(ctx context.Context) error {
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var errs []error
+	for _, feed := range w.Feeds {
+		if err := w.pollOne(ctx, client, feed); err != nil {
+			errs = append(errs, fmt.Errorf("feed %s: %w", feed.Provider, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *Watcher) pollOne(ctx context.Context, client *http.Client, feed Feed) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	var parsed rssFeed
+	if err := xml.Unmarshal(body, &parsed); err != nil {
+		return err
+	}
+	if len(parsed.Items) == 0 {
+		return nil
+	}
+	item := parsed.Items[0]
+	id := item.GUID
+	if id == "" {
+		id = item.Link
+	}
+	status := "investigating"
+	if strings.Contains(strings.ToLower(item.Title), "resolved") {
+		status = "resolved"
+	}
+	// Feeds do not carry impact; assume major so the prior reacts, and
+	// let the operator's own error budget refine it.
+	w.record(Incident{
+		Provider:  feed.Provider,
+		ID:        id,
+		Title:     item.Title,
+		Impact:    ImpactMajor,
+		Status:    status,
+		Link:      item.Link,
+		UpdatedAt: w.now(),
+	})
+	return nil
+}
+
+func (w *Watcher) record(inc Incident) {
+	closed := inc.Status == "resolved" || inc.Status == "postmortem"
+	w.mu.Lock()
+	byID := w.active[inc.Provider]
+	_, known := byID[inc.ID]
+	if closed {
+		delete(byID, inc.ID)
+		w.mu.Unlock()
+		return
+	}
+	if byID == nil {
+		byID = make(map[string]*Incident)
+		w.active[inc.Provider] = byID
+	}
+	byID[inc.ID] = &inc
+	w.mu.Unlock()
+
+	if !known && w.OnOpen != nil {
+		w.OnOpen(inc.Provider, fmt.Sprintf("status page: %s (%s) %s", inc.Title, inc.Impact, inc.Link))
+	}
+}
+
+// Exclude is a router exclusion hook: true while the provider has an
+// active critical incident.
+func (w *Watcher) Exclude(providerID string) bool {
+	worst := w.worst(providerID)
+	return worst == ImpactCritical
+}
+
+// Prior is a router prior hook in [0,1]: 1 with a clean status page,
+// scaled down with the worst active incident's impact.
+func (w *Watcher) Prior(providerID string) float64 {
+	switch w.worst(providerID) {
+	case ImpactCritical:
+		return 0.05
+	case ImpactMajor:
+		return 0.25
+	case ImpactMinor:
+		return 0.6
+	case "":
+		return 1
+	default:
+		// Unknown impact vocabulary: treat as minor rather than ignoring
+		// a declared incident.
+		return 0.6
+	}
+}
+
+// worst returns the most severe active impact for a provider, expiring
+// stale incidents on the way.
+func (w *Watcher) worst(providerID string) string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	cutoff := w.now().Add(-maxIncidentAge)
+	worst := ""
+	for id, inc := range w.active[providerID] {
+		if inc.UpdatedAt.Before(cutoff) {
+			delete(w.active[providerID], id)
+			continue
+		}
+		if impactRank(inc.Impact) > impactRank(worst) {
+			worst = inc.Impact
+		}
+	}
+	return worst
+}
+
+func impactRank(impact string) int {
+	switch impact {
+	case ImpactCritical:
+		return 3
+	case ImpactMajor:
+		return 2
+	case ImpactMinor:
+		return 1
+	case "":
+		return 0
+	default:
+		return 1
+	}
+}
+
+// Active returns every live incident sorted by provider then ID, for
+// the dashboard's incident annotations.
+func (w *Watcher) Active() []Incident {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	cutoff := w.now().Add(-maxIncidentAge)
+	var out []Incident
+	for provider, byID := range w.active {
+		for id, inc := range byID {
+			if inc.UpdatedAt.Before(cutoff) {
+				delete(w.active[provider], id)
+				continue
+			}
+			out = append(out, *inc)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Provider != out[j].Provider {
+			return out[i].Provider < out[j].Provider
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
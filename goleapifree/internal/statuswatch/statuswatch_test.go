@@ -0,0 +1,125 @@
+package statuswatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func webhook(id, status, impact string) []byte {
+	return []byte(fmt.Sprintf(
+		`{"incident":{"id":%q,"name":"Elevated errors","status":%q,"impact":%q,"shortlink":"https://stspg.io/abc"}}`,
+		id, status, impact))
+}
+
+func TestWebhookOpensAndResolvesIncident(t *testing.T) {
+	w := NewWatcher()
+	var opened string
+	w.OnOpen = func(provider, text string) { opened = provider + ": " + text }
+
+	if err := w.HandleWebhook("openai", webhook("inc1", "investigating", ImpactMajor)); err != nil {
+		t.Fatal(err)
+	}
+	if w.Prior("openai") != 0.25 || w.Exclude("openai") {
+		t.Fatalf("prior = %v, exclude = %v", w.Prior("openai"), w.Exclude("openai"))
+	}
+	if opened == "" || w.Prior("anthropic") != 1 {
+		t.Fatalf("opened = %q, other prior = %v", opened, w.Prior("anthropic"))
+	}
+	active := w.Active()
+	if len(active) != 1 || active[0].Link != "https://stspg.io/abc" {
+		t.Fatalf("active = %+v", active)
+	}
+
+	// A second update for the same incident must not re-announce it.
+	opened = ""
+	if err := w.HandleWebhook("openai", webhook("inc1", "monitoring", ImpactMajor)); err != nil {
+		t.Fatal(err)
+	}
+	if opened != "" {
+		t.Fatalf("re-announced: %q", opened)
+	}
+
+	if err := w.HandleWebhook("openai", webhook("inc1", "resolved", ImpactMajor)); err != nil {
+		t.Fatal(err)
+	}
+	if w.Prior("openai") != 1 || len(w.Active()) != 0 {
+		t.Fatalf("prior = %v after resolve", w.Prior("openai"))
+	}
+}
+
+func TestCriticalIncidentExcludes(t *testing.T) {
+	w := NewWatcher()
+	if err := w.HandleWebhook("anthropic", webhook("inc2", "identified", ImpactCritical)); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Exclude("anthropic") || w.Prior("anthropic") != 0.05 {
+		t.Fatalf("exclude = %v, prior = %v", w.Exclude("anthropic"), w.Prior("anthropic"))
+	}
+}
+
+func TestBadPayloadRejected(t *testing.T) {
+	w := NewWatcher()
+	if err := w.HandleWebhook("openai", []byte(`{"ping":"ok"}`)); !errors.Is(err, ErrBadPayload) {
+		t.Fatalf("err = %v", err)
+	}
+	if err := w.HandleWebhook("openai", []byte("not json")); !errors.Is(err, ErrBadPayload) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestStaleIncidentExpires(t *testing.T) {
+	now := time.Now()
+	w := NewWatcher()
+	w.now = func() time.Time { return now }
+	if err := w.HandleWebhook("openai", webhook("inc3", "investigating", ImpactMinor)); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(25 * time.Hour)
+	if w.Prior("openai") != 1 || len(w.Active()) != 0 {
+		t.Fatal("stale incident still active")
+	}
+}
+
+func TestPollFeed(t *testing.T) {
+	rss := `<?xml version="1.0"?><rss><channel>
+		<item><title>Degraded performance on API</title><link>https://status.example.com/inc</link><guid>g1</guid></item>
+		<item><title>Resolved: earlier outage</title><link>https://status.example.com/old</link><guid>g0</guid></item>
+	</channel></rss>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, rss)
+	}))
+	defer srv.Close()
+
+	w := NewWatcher()
+	w.Feeds = []Feed{{Provider: "openai", URL: srv.URL}}
+	if err := w.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if w.Prior("openai") != 0.25 {
+		t.Fatalf("prior = %v", w.Prior("openai"))
+	}
+
+	// The same feed reporting resolution closes the incident.
+	rss = `<?xml version="1.0"?><rss><channel>
+		<item><title>Resolved: degraded performance</title><link>https://status.example.com/inc</link><guid>g1</guid></item>
+	</channel></rss>`
+	if err := w.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if w.Prior("openai") != 1 {
+		t.Fatalf("prior = %v after feed resolution", w.Prior("openai"))
+	}
+}
+
+func TestPollJoinsFeedErrors(t *testing.T) {
+	w := NewWatcher()
+	w.Feeds = []Feed{{Provider: "down", URL: "http://127.0.0.1:0/feed"}}
+	if err := w.Poll(context.Background()); err == nil {
+		t.Fatal("unreachable feed reported no error")
+	}
+}
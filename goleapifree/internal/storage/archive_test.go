@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileArchiveRoundTrip(t *testing.T) {
+	a, err := NewFileArchive(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Put("images/2026/01/x.png", strings.NewReader("png-bytes")); err != nil {
+		t.Fatal(err)
+	}
+	r, err := a.Get("images/2026/01/x.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	data, _ := io.ReadAll(r)
+	if string(data) != "png-bytes" {
+		t.Fatalf("got %q", data)
+	}
+	if err := a.Delete("images/2026/01/x.png"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Get("images/2026/01/x.png"); err == nil {
+		t.Fatal("expected error after delete")
+	}
+}
+
+func TestURLSignerVerify(t *testing.T) {
+	s := &URLSigner{BaseURL: "http://gw/archive", Secret: []byte("secret")}
+	exp := time.Now().Add(time.Hour)
+	u := s.Sign("images/a.png", exp)
+	if !strings.HasPrefix(u, "http://gw/archive/images/a.png?") {
+		t.Fatalf("url = %q", u)
+	}
+
+	expStr := u[strings.Index(u, "exp=")+4:]
+	expStr = expStr[:strings.Index(expStr, "&")]
+	sig := u[strings.Index(u, "sig=")+4:]
+
+	if err := s.Verify("images/a.png", expStr, sig, time.Now()); err != nil {
+		t.Fatalf("valid url rejected: %v", err)
+	}
+	if err := s.Verify("images/b.png", expStr, sig, time.Now()); err == nil {
+		t.Fatal("signature for wrong key accepted")
+	}
+	if err := s.Verify("images/a.png", expStr, sig, exp.Add(time.Minute)); err == nil {
+		t.Fatal("expired url accepted")
+	}
+}
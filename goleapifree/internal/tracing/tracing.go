@@ -0,0 +1,115 @@
+// Package tracing captures per-request decision points (routing choices,
+// limiter verdicts, provider fallbacks) with configurable sampling, since
+// tracing every request is too heavy for steady-state traffic.
+package tracing
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DebugHeader forces full decision-point capture for a single request,
+// regardless of sampling configuration.
+const DebugHeader = "X-Debug-Trace"
+
+// Event is one decision point inside a traced request.
+type Event struct {
+	At      time.Time      `json:"at"`
+	Stage   string         `json:"stage"`
+	Message string         `json:"message"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// Trace accumulates events for one request. A nil *Trace is valid and
+// records nothing, so call sites never need sampling checks.
+type Trace struct {
+	ID     string  `json:"id"`
+	Forced bool    `json:"forced"`
+	Events []Event `json:"events"`
+	// speculative traces exist only for the error-capture path and are
+	// discarded after successful requests.
+	speculative bool
+	mu          sync.Mutex
+}
+
+// Add records one decision point. Safe on a nil trace.
+func (t *Trace) Add(stage, message string, fields map[string]any) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Events = append(t.Events, Event{At: time.Now(), Stage: stage, Message: message, Fields: fields})
+}
+
+// Sampled reports whether this request is being traced.
+func (t *Trace) Sampled() bool { return t != nil }
+
+// Config tunes the sampler.
+type Config struct {
+	// Rate is the base sampling probability in [0,1].
+	Rate float64
+	// AlwaysOnError retroactively keeps traces for failed requests; the
+	// proxy records into a trace speculatively and calls KeepOnError.
+	AlwaysOnError bool
+	// KeyRates overrides Rate per API key.
+	KeyRates map[string]float64
+}
+
+// Sampler decides which requests get a trace.
+type Sampler struct {
+	mu  sync.RWMutex
+	cfg Config
+}
+
+// NewSampler creates a sampler with the given configuration.
+func NewSampler(cfg Config) *Sampler {
+	return &Sampler{cfg: cfg}
+}
+
+// SetConfig swaps the sampling configuration at runtime.
+func (s *Sampler) SetConfig(cfg Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+}
+
+// Sample returns a trace for the request, or nil when it is not sampled.
+// debugHeader is the value of X-Debug-Trace; any non-empty value forces
+// full capture and marks the trace as forced.
+func (s *Sampler) Sample(requestID, apiKey, debugHeader string) *Trace {
+	if debugHeader != "" {
+		return &Trace{ID: requestID, Forced: true}
+	}
+	s.mu.RLock()
+	rate := s.cfg.Rate
+	if override, ok := s.cfg.KeyRates[apiKey]; ok {
+		rate = override
+	}
+	alwaysOnError := s.cfg.AlwaysOnError
+	s.mu.RUnlock()
+
+	if rate > 0 && rand.Float64() < rate {
+		return &Trace{ID: requestID}
+	}
+	if alwaysOnError {
+		// Speculative trace: recorded in full but persisted only when
+		// the request fails (see Keep).
+		return &Trace{ID: requestID, speculative: true}
+	}
+	return nil
+}
+
+// Keep decides whether a trace should be persisted after the request
+// finished. Forced and rate-sampled traces are always kept; speculative
+// error-capture traces only when the request actually failed.
+func (s *Sampler) Keep(t *Trace, failed bool) bool {
+	if t == nil {
+		return false
+	}
+	if !t.speculative {
+		return true
+	}
+	return failed
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import "testing"
+
+func TestDebugHeaderForcesTrace(t *testing.T) {
+	s := NewSampler(Config{Rate: 0})
+	tr := s.Sample("req1", "key", "1")
+	if tr == nil || !tr.Forced {
+		t.Fatalf("debug header did not force trace: %+v", tr)
+	}
+	if !s.Keep(tr, false) {
+		t.Fatal("forced trace must always be kept")
+	}
+}
+
+func TestErrorOnlyTraces(t *testing.T) {
+	s := NewSampler(Config{Rate: 0, AlwaysOnError: true})
+	tr := s.Sample("req1", "key", "")
+	if tr == nil {
+		t.Fatal("error-capture path should produce a speculative trace")
+	}
+	if s.Keep(tr, false) {
+		t.Fatal("speculative trace kept for successful request")
+	}
+	if !s.Keep(tr, true) {
+		t.Fatal("speculative trace dropped for failed request")
+	}
+}
+
+func TestPerKeyOverride(t *testing.T) {
+	s := NewSampler(Config{Rate: 0, KeyRates: map[string]float64{"vip": 1}})
+	if tr := s.Sample("req1", "vip", ""); tr == nil {
+		t.Fatal("override rate 1 should always sample")
+	}
+	if tr := s.Sample("req2", "other", ""); tr != nil {
+		t.Fatalf("rate 0 should not sample, got %+v", tr)
+	}
+}
+
+func TestNilTraceIsSafe(t *testing.T) {
+	var tr *Trace
+	tr.Add("router", "picked candidate", nil) // must not panic
+	if tr.Sampled() {
+		t.Fatal("nil trace reports sampled")
+	}
+}
@@ -0,0 +1,260 @@
+// Package trial runs new providers through a scoring sandbox before they
+// take real traffic. A trial provider is invisible to normal routing; it
+// receives synthetic probes plus a small deterministic shadow of real
+// requests, and is auto-promoted to active once it has accumulated enough
+// clean data over the trial period.
+package trial
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/router"
+)
+
+// Trial states.
+const (
+	StateTrial    = "trial"
+	StatePromoted = "promoted"
+	StateFailed   = "failed"
+)
+
+// Errors returned by the manager.
+var (
+	ErrNotFound = errors.New("trial: trial not found")
+	ErrDecided  = errors.New("trial: trial already decided")
+)
+
+// Thresholds a trial must clear before promotion.
+type Thresholds struct {
+	// MinSamples is the combined probe+shadow sample count required
+	// before any decision; defaults to 50.
+	MinSamples int64
+	// MaxErrorRate fails the trial when exceeded with minimum data;
+	// defaults to 0.05.
+	MaxErrorRate float64
+}
+
+// tally counts outcomes for one traffic source.
+type tally struct {
+	Samples int64 `json:"samples"`
+	Errors  int64 `json:"errors"`
+}
+
+// Trial is one provider's sandbox run.
+type Trial struct {
+	ProviderID string `json:"provider_id"`
+	// ShadowPercent of real requests mirrored to the provider, in (0, 100).
+	ShadowPercent float64       `json:"shadow_percent"`
+	Duration      time.Duration `json:"duration"`
+	StartedAt     time.Time     `json:"started_at"`
+	State         string        `json:"state"`
+	Reason        string        `json:"reason,omitempty"`
+	DecidedAt     time.Time     `json:"decided_at,omitempty"`
+	Probes        tally         `json:"probes"`
+	Shadow        tally         `json:"shadow"`
+}
+
+func (t *Trial) samples() int64 { return t.Probes.Samples + t.Shadow.Samples }
+func (t *Trial) errorRate() float64 {
+	total := t.samples()
+	if total == 0 {
+		return 0
+	}
+	return float64(t.Probes.Errors+t.Shadow.Errors) / float64(total)
+}
+
+// Manager owns active trials and applies promotion decisions to the
+// router.
+type Manager struct {
+	Router *router.Router
+	// Probe sends one synthetic request to a trial provider; RunProbes
+	// calls it for every active trial. Nil means shadow traffic only.
+	Probe func(ctx context.Context, p *models.Provider) error
+
+	cfg Thresholds
+	now func() time.Time
+
+	mu     sync.Mutex
+	trials map[string]*Trial
+}
+
+// NewManager creates a manager with defaulted thresholds, applying
+// decisions to r.
+func NewManager(r *router.Router, cfg Thresholds) *Manager {
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = 50
+	}
+	if cfg.MaxErrorRate <= 0 {
+		cfg.MaxErrorRate = 0.05
+	}
+	return &Manager{Router: r, cfg: cfg, now: time.Now, trials: make(map[string]*Trial)}
+}
+
+// Start puts a registered provider into trial status. The provider stops
+// receiving real traffic immediately; only probes and the shadow slice
+// reach it until the trial decides.
+func (m *Manager) Start(providerID string, shadowPercent float64, duration time.Duration) (*Trial, error) {
+	if shadowPercent <= 0 || shadowPercent >= 100 {
+		return nil, errors.New("trial: shadow percent must be in (0, 100)")
+	}
+	if duration <= 0 {
+		return nil, errors.New("trial: duration must be positive")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if tr, exists := m.trials[providerID]; exists && tr.State == StateTrial {
+		return nil, fmt.Errorf("trial: provider %q already on trial", providerID)
+	}
+	if err := m.Router.SetProviderStatus(providerID, models.ProviderStatusTrial); err != nil {
+		return nil, err
+	}
+	tr := &Trial{
+		ProviderID:    providerID,
+		ShadowPercent: shadowPercent,
+		Duration:      duration,
+		StartedAt:     m.now(),
+		State:         StateTrial,
+	}
+	m.trials[providerID] = tr
+	copied := *tr
+	return &copied, nil
+}
+
+// ShadowSample reports whether one real request (identified by key)
+// should be mirrored to the trial provider. The hash is deterministic so
+// the same caller lands in the same slice, mirroring the canary bucketing.
+func (m *Manager) ShadowSample(providerID, key string) bool {
+	m.mu.Lock()
+	tr, ok := m.trials[providerID]
+	m.mu.Unlock()
+	if !ok || tr.State != StateTrial {
+		return false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(providerID))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return float64(h.Sum32()%10000) < tr.ShadowPercent*100
+}
+
+// ObserveShadow records the outcome of one mirrored request and
+// re-evaluates the trial.
+func (m *Manager) ObserveShadow(providerID string, errored bool) (string, error) {
+	return m.observe(providerID, errored, false)
+}
+
+func (m *Manager) observe(providerID string, errored, probe bool) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tr, ok := m.trials[providerID]
+	if !ok {
+		return "", ErrNotFound
+	}
+	if tr.State != StateTrial {
+		return tr.State, ErrDecided
+	}
+	t := &tr.Shadow
+	if probe {
+		t = &tr.Probes
+	}
+	t.Samples++
+	if errored {
+		t.Errors++
+	}
+	m.evaluateLocked(tr)
+	return tr.State, nil
+}
+
+// RunProbes sends one synthetic probe to every active trial provider.
+// Its signature matches the scheduler's JobFunc.
+func (m *Manager) RunProbes(ctx context.Context) error {
+	if m.Probe == nil {
+		return nil
+	}
+	m.mu.Lock()
+	active := make([]string, 0, len(m.trials))
+	for id, tr := range m.trials {
+		if tr.State == StateTrial {
+			active = append(active, id)
+		}
+	}
+	m.mu.Unlock()
+	sort.Strings(active)
+	for _, id := range active {
+		p := m.Router.GetProvider(id)
+		if p == nil {
+			continue
+		}
+		err := m.Probe(ctx, p)
+		if _, obsErr := m.observe(id, err != nil, true); obsErr != nil && !errors.Is(obsErr, ErrDecided) {
+			return obsErr
+		}
+	}
+	return nil
+}
+
+// Trials returns every trial, sorted by provider ID.
+func (m *Manager) Trials() []Trial {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Trial, 0, len(m.trials))
+	for _, tr := range m.trials {
+		out = append(out, *tr)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ProviderID < out[j].ProviderID })
+	return out
+}
+
+// Status returns one provider's trial.
+func (m *Manager) Status(providerID string) (*Trial, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tr, ok := m.trials[providerID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *tr
+	return &copied, nil
+}
+
+// evaluateLocked applies the decision rules. Caller holds m.mu.
+//
+// A trial fails as soon as the error rate exceeds the threshold with
+// minimum data — no point burning the full period on a broken provider.
+// Promotion waits for the trial period to elapse with enough samples and
+// a clean error rate; a period that ends without enough data fails the
+// trial rather than promoting on a hunch.
+func (m *Manager) evaluateLocked(tr *Trial) {
+	enough := tr.samples() >= m.cfg.MinSamples
+	if enough && tr.errorRate() > m.cfg.MaxErrorRate {
+		m.decideLocked(tr, StateFailed, "error rate above threshold")
+		return
+	}
+	if m.now().Before(tr.StartedAt.Add(tr.Duration)) {
+		return
+	}
+	if !enough {
+		m.decideLocked(tr, StateFailed, "trial period ended without enough samples")
+		return
+	}
+	m.decideLocked(tr, StatePromoted, "error rate within threshold over full trial period")
+}
+
+// decideLocked finalizes a trial; promotion flips the provider to active
+// so it joins normal routing. Failed providers stay in trial status, out
+// of real traffic, until an operator intervenes. Caller holds m.mu.
+func (m *Manager) decideLocked(tr *Trial, state, reason string) {
+	tr.State = state
+	tr.Reason = reason
+	tr.DecidedAt = m.now()
+	if state == StatePromoted {
+		m.Router.SetProviderStatus(tr.ProviderID, models.ProviderStatusActive)
+	}
+}
@@ -0,0 +1,148 @@
+package trial
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/models"
+	"github.com/biodoia/goleapifree/internal/router"
+)
+
+func newFixture(t *testing.T) *router.Router {
+	t.Helper()
+	r := router.New()
+	r.AddProvider(&models.Provider{ID: "newbie", Enabled: true})
+	r.AddAccount(&models.Account{ID: "a1", ProviderID: "newbie", Enabled: true})
+	r.AddProvider(&models.Provider{ID: "veteran", Enabled: true})
+	r.AddAccount(&models.Account{ID: "a2", ProviderID: "veteran", Enabled: true})
+	r.SetAlias(&models.ModelAlias{Alias: "gpt-4", Targets: []models.AliasTarget{
+		{ProviderID: "newbie", Model: "gpt-4"},
+		{ProviderID: "veteran", Model: "gpt-4"},
+	}})
+	return r
+}
+
+func TestTrialProviderLeavesRealRouting(t *testing.T) {
+	r := newFixture(t)
+	m := NewManager(r, Thresholds{})
+	if _, err := m.Start("newbie", 5, 24*time.Hour); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	for _, c := range r.Candidates("gpt-4") {
+		if c.Provider.ID == "newbie" {
+			t.Fatal("trial provider offered as routing candidate")
+		}
+	}
+}
+
+func TestShadowSampleIsDeterministicAndBounded(t *testing.T) {
+	r := newFixture(t)
+	m := NewManager(r, Thresholds{})
+	if _, err := m.Start("newbie", 10, 24*time.Hour); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if m.ShadowSample("newbie", "req-1") != m.ShadowSample("newbie", "req-1") {
+		t.Fatal("same key landed in different slices")
+	}
+	hits := 0
+	for i := 0; i < 10000; i++ {
+		if m.ShadowSample("newbie", string(rune(i))+"-key") {
+			hits++
+		}
+	}
+	if hits < 500 || hits > 1500 {
+		t.Fatalf("10%% shadow slice caught %d of 10000", hits)
+	}
+	if m.ShadowSample("veteran", "req-1") {
+		t.Fatal("provider without a trial got shadow traffic")
+	}
+}
+
+func TestTrialPromotesAfterCleanPeriod(t *testing.T) {
+	r := newFixture(t)
+	m := NewManager(r, Thresholds{MinSamples: 10})
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return start }
+	if _, err := m.Start("newbie", 5, 7*24*time.Hour); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	m.Probe = func(context.Context, *models.Provider) error { return nil }
+
+	// A week of clean probes and shadow requests.
+	for day := 0; day < 8; day++ {
+		m.now = func() time.Time { return start.Add(time.Duration(day) * 24 * time.Hour) }
+		if err := m.RunProbes(context.Background()); err != nil {
+			t.Fatalf("RunProbes: %v", err)
+		}
+		if state, err := m.ObserveShadow("newbie", false); day < 7 && (err != nil || state != StateTrial) {
+			t.Fatalf("day %d: state=%s err=%v", day, state, err)
+		}
+	}
+	tr, err := m.Status("newbie")
+	if err != nil || tr.State != StatePromoted {
+		t.Fatalf("trial = %+v, err = %v", tr, err)
+	}
+	if got := r.GetProvider("newbie").Status; got != models.ProviderStatusActive {
+		t.Fatalf("provider status after promotion = %q", got)
+	}
+	if len(r.Candidates("gpt-4")) != 2 {
+		t.Fatal("promoted provider still out of routing")
+	}
+}
+
+func TestTrialFailsFastOnErrors(t *testing.T) {
+	r := newFixture(t)
+	m := NewManager(r, Thresholds{MinSamples: 10, MaxErrorRate: 0.1})
+	if _, err := m.Start("newbie", 5, 7*24*time.Hour); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	var state string
+	for i := 0; i < 10; i++ {
+		state, _ = m.ObserveShadow("newbie", i%2 == 0)
+	}
+	if state != StateFailed {
+		t.Fatalf("state after 50%% errors = %s", state)
+	}
+	if got := r.GetProvider("newbie").Status; got != models.ProviderStatusTrial {
+		t.Fatalf("failed provider status = %q, want still trial", got)
+	}
+	if _, err := m.ObserveShadow("newbie", false); !errors.Is(err, ErrDecided) {
+		t.Fatalf("observe after decision: %v", err)
+	}
+}
+
+func TestTrialFailsWithoutEnoughSamples(t *testing.T) {
+	r := newFixture(t)
+	m := NewManager(r, Thresholds{MinSamples: 100})
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return start }
+	if _, err := m.Start("newbie", 5, 24*time.Hour); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	m.now = func() time.Time { return start.Add(25 * time.Hour) }
+	if state, _ := m.ObserveShadow("newbie", false); state != StateFailed {
+		t.Fatalf("state after thin trial period = %s", state)
+	}
+}
+
+func TestStartGuards(t *testing.T) {
+	r := newFixture(t)
+	m := NewManager(r, Thresholds{})
+	if _, err := m.Start("ghost", 5, time.Hour); !errors.Is(err, router.ErrProviderNotFound) {
+		t.Fatalf("unknown provider: %v", err)
+	}
+	if _, err := m.Start("newbie", 0, time.Hour); err == nil {
+		t.Fatal("zero shadow percent accepted")
+	}
+	if _, err := m.Start("newbie", 5, 0); err == nil {
+		t.Fatal("zero duration accepted")
+	}
+	if _, err := m.Start("newbie", 5, time.Hour); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := m.Start("newbie", 5, time.Hour); err == nil {
+		t.Fatal("double start accepted")
+	}
+}
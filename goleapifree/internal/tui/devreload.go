@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DevReloadView shows the most recent hot-reload failures so a broken
+// chain or template edit is visible in the terminal instead of buried
+// in logs. Plug its Report method into the watcher's error callback.
+type DevReloadView struct {
+	// Keep bounds how many failures are retained; defaults to 5.
+	Keep int
+
+	mu       sync.Mutex
+	failures []reloadFailure
+}
+
+type reloadFailure struct {
+	file string
+	err  string
+	at   time.Time
+}
+
+// Report records one failure, dropping the oldest beyond Keep.
+func (v *DevReloadView) Report(path string, err error) {
+	keep := v.Keep
+	if keep <= 0 {
+		keep = 5
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.failures = append(v.failures, reloadFailure{file: filepath.Base(path), err: err.Error(), at: time.Now()})
+	if len(v.failures) > keep {
+		v.failures = v.failures[len(v.failures)-keep:]
+	}
+}
+
+// Render implements Component.
+func (v *DevReloadView) Render() string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.failures) == 0 {
+		return "reload: all definitions valid"
+	}
+	var b strings.Builder
+	b.WriteString("reload errors:\n")
+	for _, f := range v.failures {
+		fmt.Fprintf(&b, "  %s  %s: %s\n", f.at.Format("15:04:05"), f.file, f.err)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,100 @@
+//go:build !edge
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/biodoia/goleapifree/internal/experiments"
+)
+
+// ExperimentsView lists experiments with their statistics and lets the
+// operator drive the lifecycle with single-key bindings:
+//
+//	j/k  move selection   s  start   p  pause   x  stop   r  rollout
+type ExperimentsView struct {
+	Manager  *experiments.Manager
+	selected int
+	// status line shown under the table after an action.
+	message string
+}
+
+// NewExperimentsView creates the view over a manager.
+func NewExperimentsView(m *experiments.Manager) *ExperimentsView {
+	return &ExperimentsView{Manager: m}
+}
+
+// HandleKey processes one keypress and reports whether the view consumed it.
+func (v *ExperimentsView) HandleKey(key rune) bool {
+	exps := v.Manager.List()
+	switch key {
+	case 'j':
+		if v.selected < len(exps)-1 {
+			v.selected++
+		}
+	case 'k':
+		if v.selected > 0 {
+			v.selected--
+		}
+	case 's':
+		v.act(exps, "started", v.Manager.Start)
+	case 'p':
+		v.act(exps, "paused", v.Manager.Pause)
+	case 'x':
+		v.act(exps, "stopped", v.Manager.Stop)
+	case 'r':
+		v.act(exps, "rolled out", v.Manager.Rollout)
+	default:
+		return false
+	}
+	return true
+}
+
+func (v *ExperimentsView) act(exps []*experiments.Experiment, verb string, fn func(string) error) {
+	if v.selected >= len(exps) {
+		return
+	}
+	e := exps[v.selected]
+	if err := fn(e.ID); err != nil {
+		v.message = err.Error()
+		return
+	}
+	v.message = fmt.Sprintf("%s %s", e.Name, verb)
+}
+
+// Render implements Component.
+func (v *ExperimentsView) Render() string {
+	exps := v.Manager.List()
+	if len(exps) == 0 {
+		return "no experiments\n"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "  %-20s %-10s %8s %10s %8s %s\n",
+		"EXPERIMENT", "STATUS", "SAMPLES", "BEST RATE", "P-VALUE", "WINNER")
+	for i, e := range exps {
+		cursor := "  "
+		if i == v.selected {
+			cursor = "> "
+		}
+		var samples int64
+		bestRate := 0.0
+		for _, vr := range e.Variants {
+			samples += vr.Samples
+			if r := vr.Rate(); r > bestRate {
+				bestRate = r
+			}
+		}
+		winner := e.RecommendedWinner()
+		if winner == "" {
+			winner = "-"
+		}
+		fmt.Fprintf(&sb, "%s%-20s %-10s %8d %9.1f%% %8.3f %s\n",
+			cursor, e.Name, e.Status, samples, 100*bestRate, e.PValue(), winner)
+	}
+	sb.WriteString("\n  j/k move  s start  p pause  x stop  r rollout\n")
+	if v.message != "" {
+		fmt.Fprintf(&sb, "  %s\n", v.message)
+	}
+	return sb.String()
+}
@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/stats"
+)
+
+// heatShades maps intensity quartiles to block characters; index 0 is an
+// empty cell (no traffic in that hour).
+var heatShades = []rune{'·', '░', '▒', '▓', '█'}
+
+// Heatmap renders per-provider latency over the last 24 hours as one row
+// of block characters per provider, one column per hour. Shade intensity
+// is the hour's p95 latency relative to the hottest cell on the board, so
+// time-of-day degradation stands out at a glance.
+type Heatmap struct {
+	Analyzer *stats.Analyzer
+	// Percentile used for cell intensity; defaults to 95 in NewHeatmap.
+	Percentile float64
+	// Themes, when set, supplies the active theme's shade glyphs.
+	Themes *Themes
+	now    func() time.Time
+}
+
+// NewHeatmap creates a p95 latency heatmap over the analyzer's buckets.
+func NewHeatmap(a *stats.Analyzer) *Heatmap {
+	return &Heatmap{Analyzer: a, Percentile: 95, now: time.Now}
+}
+
+// Render implements Component.
+func (h *Heatmap) Render() string {
+	providers := h.Analyzer.Providers()
+	if len(providers) == 0 {
+		return "no provider traffic in the last 24h\n"
+	}
+
+	nameWidth := 0
+	for _, p := range providers {
+		if len(p) > nameWidth {
+			nameWidth = len(p)
+		}
+	}
+
+	end := h.now().UTC().Truncate(time.Hour)
+	start := end.Add(-23 * time.Hour)
+
+	// First pass: collect the p95 grid and the board maximum.
+	grid := make(map[string][24]float64)
+	var max float64
+	for _, p := range providers {
+		var row [24]float64
+		for _, b := range h.Analyzer.ProviderBuckets(p) {
+			if b.Start.Before(start) || b.Start.After(end) {
+				continue
+			}
+			col := int(b.Start.Sub(start).Hours())
+			row[col] = b.LatencyPercentile(h.Percentile)
+			if row[col] > max {
+				max = row[col]
+			}
+		}
+		grid[p] = row
+	}
+
+	shades := heatShades
+	if h.Themes != nil {
+		if ts := h.Themes.Active().HeatShades; len(ts) >= 2 {
+			shades = ts
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%*s  %s\n", nameWidth, "", hourRuler(start))
+	for _, p := range providers {
+		fmt.Fprintf(&sb, "%*s  ", nameWidth, p)
+		for _, v := range grid[p] {
+			sb.WriteRune(shade(v, max, shades))
+		}
+		sb.WriteByte('\n')
+	}
+	fmt.Fprintf(&sb, "%*s  p%.0f latency  %c low  %c high\n",
+		nameWidth, "", h.Percentile, shades[1], shades[len(shades)-1])
+	return sb.String()
+}
+
+// shade picks the block character for a cell value against the board max.
+func shade(v, max float64, shades []rune) rune {
+	if v == 0 || max == 0 {
+		return shades[0]
+	}
+	idx := 1 + int(v/max*float64(len(shades)-2))
+	if idx >= len(shades) {
+		idx = len(shades) - 1
+	}
+	return shades[idx]
+}
+
+// hourRuler labels every sixth column with its UTC hour.
+func hourRuler(start time.Time) string {
+	var sb strings.Builder
+	for i := 0; i < 24; i++ {
+		if i%6 == 0 {
+			sb.WriteString(fmt.Sprintf("%-6s", start.Add(time.Duration(i)*time.Hour).Format("15h")))
+		}
+	}
+	return strings.TrimRight(sb.String(), " ")
+}
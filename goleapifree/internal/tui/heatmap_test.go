@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/stats"
+)
+
+func TestHeatmapShadesHotHours(t *testing.T) {
+	a := stats.NewAnalyzer(48 * time.Hour)
+	now := time.Now().UTC().Truncate(time.Hour)
+	// Slow traffic three hours ago, fast traffic ten hours ago.
+	for i := 0; i < 10; i++ {
+		a.Record(stats.Sample{Provider: "groq", LatencyMs: 2000, At: now.Add(-3 * time.Hour)})
+		a.Record(stats.Sample{Provider: "groq", LatencyMs: 50, At: now.Add(-10 * time.Hour)})
+	}
+
+	h := NewHeatmap(a)
+	out := h.Render()
+	if !strings.Contains(out, "groq") {
+		t.Fatalf("missing provider row:\n%s", out)
+	}
+	if !strings.ContainsRune(out, '█') {
+		t.Fatalf("expected a high-intensity cell for the slow hour:\n%s", out)
+	}
+	if !strings.ContainsRune(out, '·') {
+		t.Fatalf("expected empty cells for idle hours:\n%s", out)
+	}
+}
+
+func TestHeatmapNoTraffic(t *testing.T) {
+	h := NewHeatmap(stats.NewAnalyzer(time.Hour))
+	if out := h.Render(); !strings.Contains(out, "no provider traffic") {
+		t.Fatalf("unexpected render for empty analyzer: %q", out)
+	}
+}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Theme defines the TUI palette. Colors are ANSI SGR sequences applied
+// around rendered text; HeatShades replaces the heatmap block characters
+// so monochrome terminals still get usable output.
+type Theme struct {
+	Name string `yaml:"name"`
+	// SGR sequences; empty means no styling (plain terminal output).
+	Accent string `yaml:"accent"`
+	Text   string `yaml:"text"`
+	Dim    string `yaml:"dim"`
+	Alert  string `yaml:"alert"`
+	Reset  string `yaml:"reset"`
+	// HeatShades are the heatmap intensity glyphs, lowest first; index 0
+	// is the empty cell.
+	HeatShades []rune `yaml:"-"`
+	// ColorblindSafe marks palettes that avoid red/green distinctions.
+	ColorblindSafe bool `yaml:"colorblind_safe"`
+}
+
+// Builtin themes. Cyberpunk stays the default for continuity.
+var builtinThemes = []Theme{
+	{
+		Name:       "cyberpunk",
+		Accent:     "\x1b[38;5;201m", // magenta
+		Text:       "\x1b[38;5;51m",  // cyan
+		Dim:        "\x1b[38;5;240m",
+		Alert:      "\x1b[38;5;226m", // yellow, not red: colorblind-safe
+		Reset:      "\x1b[0m",
+		HeatShades: []rune{'·', '░', '▒', '▓', '█'},
+	},
+	{
+		Name:           "light",
+		Accent:         "\x1b[34m",
+		Text:           "\x1b[30m",
+		Dim:            "\x1b[37m",
+		Alert:          "\x1b[35m",
+		Reset:          "\x1b[0m",
+		HeatShades:     []rune{'·', '░', '▒', '▓', '█'},
+		ColorblindSafe: true,
+	},
+	{
+		Name:           "high-contrast",
+		Accent:         "\x1b[1;97m",
+		Text:           "\x1b[97m",
+		Dim:            "\x1b[90m",
+		Alert:          "\x1b[1;93m",
+		Reset:          "\x1b[0m",
+		HeatShades:     []rune{' ', '░', '▒', '▓', '█'},
+		ColorblindSafe: true,
+	},
+	{
+		// CP437 monochrome: no color at all, density carried entirely by
+		// the glyph ramp, safe for dumb terminals and screen captures.
+		Name:           "mono-cp437",
+		HeatShades:     []rune{'.', '-', '+', '*', '#'},
+		ColorblindSafe: true,
+	},
+}
+
+// Themes holds the registered palettes and the active selection, and is
+// safe for concurrent use (the keybinding handler and renderers race).
+type Themes struct {
+	mu     sync.RWMutex
+	byName map[string]Theme
+	order  []string
+	active string
+}
+
+// NewThemes returns the registry with builtin themes, cyberpunk active.
+func NewThemes() *Themes {
+	t := &Themes{byName: make(map[string]Theme)}
+	for _, th := range builtinThemes {
+		t.byName[th.Name] = th
+		t.order = append(t.order, th.Name)
+	}
+	t.active = builtinThemes[0].Name
+	return t
+}
+
+// Register adds or replaces a theme (e.g. custom palettes from
+// config.yaml).
+func (t *Themes) Register(th Theme) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, exists := t.byName[th.Name]; !exists {
+		t.order = append(t.order, th.Name)
+	}
+	t.byName[th.Name] = th
+}
+
+// Set activates a theme by name.
+func (t *Themes) Set(name string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.byName[name]; !ok {
+		return fmt.Errorf("tui: unknown theme %q", name)
+	}
+	t.active = name
+	return nil
+}
+
+// Active returns the current theme.
+func (t *Themes) Active() Theme {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.byName[t.active]
+}
+
+// Cycle advances to the next registered theme and returns it; bound to
+// the theme keybinding.
+func (t *Themes) Cycle() Theme {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, name := range t.order {
+		if name == t.active {
+			t.active = t.order[(i+1)%len(t.order)]
+			break
+		}
+	}
+	return t.byName[t.active]
+}
+
+// Names lists registered themes for the selection menu, sorted.
+func (t *Themes) Names() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	out := make([]string, len(t.order))
+	copy(out, t.order)
+	sort.Strings(out)
+	return out
+}
+
+// Paint wraps s in the given SGR color, if any.
+func (th Theme) Paint(color, s string) string {
+	if color == "" || th.Reset == "" {
+		return s
+	}
+	return color + s + th.Reset
+}
@@ -0,0 +1,34 @@
+package tui
+
+import "testing"
+
+func TestThemesCycleAndSet(t *testing.T) {
+	th := NewThemes()
+	if th.Active().Name != "cyberpunk" {
+		t.Fatalf("default theme = %q", th.Active().Name)
+	}
+	if got := th.Cycle().Name; got != "light" {
+		t.Fatalf("cycle = %q, want light", got)
+	}
+	if err := th.Set("mono-cp437"); err != nil {
+		t.Fatal(err)
+	}
+	if th.Active().Accent != "" {
+		t.Fatal("mono theme must not emit color codes")
+	}
+	if err := th.Set("nope"); err == nil {
+		t.Fatal("expected error for unknown theme")
+	}
+}
+
+func TestPaint(t *testing.T) {
+	th := NewThemes().Active()
+	painted := th.Paint(th.Accent, "hi")
+	if painted == "hi" {
+		t.Fatal("cyberpunk accent should wrap the text")
+	}
+	mono := Theme{Name: "plain"}
+	if mono.Paint(mono.Accent, "hi") != "hi" {
+		t.Fatal("unstyled theme must pass text through")
+	}
+}
@@ -0,0 +1,9 @@
+// Package tui contains the terminal UI views and the small components they
+// are assembled from. Components render to plain strings so views can be
+// composed, tested, and printed without a live terminal.
+package tui
+
+// Component is anything that can render itself to a block of text.
+type Component interface {
+	Render() string
+}
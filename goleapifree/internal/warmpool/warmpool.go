@@ -0,0 +1,266 @@
+// Package warmpool manages model load state on local providers such as
+// Ollama: preloading the configured models, evicting the least-recently
+// used one when VRAM runs out, and exposing per-model load state with a
+// load-time estimate. The wiring binds the router's Exclude hook to
+// Hot, so requests for cold models route elsewhere, and uses ETA as the
+// Retry-After when nothing else can serve them.
+package warmpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultLoadEstimate seeds the load-time estimate before any load has
+// been measured; typical for a mid-size model from page cache.
+const defaultLoadEstimate = 30 * time.Second
+
+// Status is one model's load state.
+type Status string
+
+const (
+	StatusCold    Status = "cold"
+	StatusLoading Status = "loading"
+	StatusLoaded  Status = "loaded"
+)
+
+// Errors surfaced to the routing path.
+var (
+	// ErrUnknownModel means the model was never registered with the pool.
+	ErrUnknownModel = errors.New("warmpool: unknown model")
+	// ErrModelLoading means another caller is already loading the model;
+	// route elsewhere or queue with the ETA.
+	ErrModelLoading = errors.New("warmpool: model is still loading")
+)
+
+// ModelInfo is one model's state for the admin API.
+type ModelInfo struct {
+	Name     string    `json:"name"`
+	SizeMB   int       `json:"size_mb"`
+	Preload  bool      `json:"preload"`
+	Status   Status    `json:"status"`
+	LastUsed time.Time `json:"last_used,omitempty"`
+}
+
+type modelState struct {
+	sizeMB      int
+	preload     bool
+	status      Status
+	lastUsed    time.Time
+	loadStarted time.Time
+}
+
+// Pool tracks which models are resident on one local provider host.
+type Pool struct {
+	// VRAMBudgetMB caps the combined size of loaded models; 0 = no cap.
+	VRAMBudgetMB int
+	// Load instructs the runtime to load a model (for Ollama, a request
+	// with keep_alive pinned); nil treats loads as instant, which keeps
+	// dry wiring and tests simple.
+	Load func(ctx context.Context, model string) error
+	// Unload releases a model's VRAM.
+	Unload func(ctx context.Context, model string) error
+
+	now func() time.Time
+
+	mu           sync.Mutex
+	models       map[string]*modelState
+	loadEstimate time.Duration
+}
+
+// NewPool creates a pool with the given VRAM budget in MB.
+func NewPool(vramBudgetMB int) *Pool {
+	return &Pool{
+		VRAMBudgetMB: vramBudgetMB,
+		now:          time.Now,
+		models:       make(map[string]*modelState),
+		loadEstimate: defaultLoadEstimate,
+	}
+}
+
+// Register declares a model the pool may manage. preload marks it for
+// PreloadAll; sizeMB drives eviction against the VRAM budget.
+func (p *Pool) Register(model string, sizeMB int, preload bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if st, ok := p.models[model]; ok {
+		st.sizeMB, st.preload = sizeMB, preload
+		return
+	}
+	p.models[model] = &modelState{sizeMB: sizeMB, preload: preload, status: StatusCold}
+}
+
+// EnsureLoaded brings a model resident, evicting least-recently-used
+// models as needed, and blocks until the load finishes. The routing hot
+// path should consult Hot instead and only call this off-path.
+func (p *Pool) EnsureLoaded(ctx context.Context, model string) error {
+	p.mu.Lock()
+	st, ok := p.models[model]
+	if !ok {
+		p.mu.Unlock()
+		return ErrUnknownModel
+	}
+	switch st.status {
+	case StatusLoaded:
+		st.lastUsed = p.now()
+		p.mu.Unlock()
+		return nil
+	case StatusLoading:
+		p.mu.Unlock()
+		return ErrModelLoading
+	}
+	victims, err := p.evictForLocked(st)
+	if err != nil {
+		p.mu.Unlock()
+		return err
+	}
+	st.status = StatusLoading
+	st.loadStarted = p.now()
+	p.mu.Unlock()
+
+	for _, victim := range victims {
+		if p.Unload != nil {
+			if err := p.Unload(ctx, victim); err != nil {
+				p.setStatus(model, StatusCold)
+				return fmt.Errorf("warmpool: evicting %s: %w", victim, err)
+			}
+		}
+	}
+	if p.Load != nil {
+		if err := p.Load(ctx, model); err != nil {
+			p.setStatus(model, StatusCold)
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	elapsed := p.now().Sub(st.loadStarted)
+	if elapsed > 0 {
+		// EMA keeps the estimate tracking the host's real load times.
+		p.loadEstimate = (p.loadEstimate + elapsed) / 2
+	}
+	st.status = StatusLoaded
+	st.lastUsed = p.now()
+	p.mu.Unlock()
+	return nil
+}
+
+// evictForLocked marks enough least-recently-used loaded models cold to
+// fit st under the budget, returning their names for the actual unload.
+// Callers must hold p.mu.
+func (p *Pool) evictForLocked(st *modelState) ([]string, error) {
+	if p.VRAMBudgetMB <= 0 {
+		return nil, nil
+	}
+	if st.sizeMB > p.VRAMBudgetMB {
+		return nil, fmt.Errorf("warmpool: model larger than the VRAM budget (%d > %d MB)", st.sizeMB, p.VRAMBudgetMB)
+	}
+	var victims []string
+	for {
+		used := st.sizeMB
+		oldest, oldestName := time.Time{}, ""
+		for name, other := range p.models {
+			if other == st || other.status != StatusLoaded {
+				continue
+			}
+			used += other.sizeMB
+			if oldestName == "" || other.lastUsed.Before(oldest) {
+				oldest, oldestName = other.lastUsed, name
+			}
+		}
+		if used <= p.VRAMBudgetMB {
+			return victims, nil
+		}
+		if oldestName == "" {
+			return nil, fmt.Errorf("warmpool: cannot fit %d MB under the VRAM budget", st.sizeMB)
+		}
+		p.models[oldestName].status = StatusCold
+		victims = append(victims, oldestName)
+	}
+}
+
+func (p *Pool) setStatus(model string, status Status) {
+	p.mu.Lock()
+	if st, ok := p.models[model]; ok {
+		st.status = status
+	}
+	p.mu.Unlock()
+}
+
+// PreloadAll loads every model registered with preload set. Its
+// signature matches the scheduler's JobFunc; one failed load does not
+// keep the rest cold.
+func (p *Pool) PreloadAll(ctx context.Context) error {
+	p.mu.Lock()
+	var names []string
+	for name, st := range p.models {
+		if st.preload {
+			names = append(names, name)
+		}
+	}
+	p.mu.Unlock()
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if err := p.EnsureLoaded(ctx, name); err != nil && !errors.Is(err, ErrModelLoading) {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Touch records a use of a loaded model so eviction stays accurate.
+func (p *Pool) Touch(model string) {
+	p.mu.Lock()
+	if st, ok := p.models[model]; ok && st.status == StatusLoaded {
+		st.lastUsed = p.now()
+	}
+	p.mu.Unlock()
+}
+
+// Hot reports whether a model can serve immediately. Unknown models are
+// hot: the pool only speaks for models it manages.
+func (p *Pool) Hot(model string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	st, ok := p.models[model]
+	return !ok || st.status == StatusLoaded
+}
+
+// ETA estimates how long until a model can serve: zero when loaded, the
+// remaining load time while loading, and a full load from cold.
+func (p *Pool) ETA(model string) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	st, ok := p.models[model]
+	if !ok || st.status == StatusLoaded {
+		return 0
+	}
+	if st.status == StatusLoading {
+		if remaining := p.loadEstimate - p.now().Sub(st.loadStarted); remaining > time.Second {
+			return remaining
+		}
+		return time.Second
+	}
+	return p.loadEstimate
+}
+
+// Models lists every registered model, name order.
+func (p *Pool) Models() []ModelInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]ModelInfo, 0, len(p.models))
+	for name, st := range p.models {
+		out = append(out, ModelInfo{
+			Name: name, SizeMB: st.sizeMB, Preload: st.preload,
+			Status: st.status, LastUsed: st.lastUsed,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
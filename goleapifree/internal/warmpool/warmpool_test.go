@@ -0,0 +1,105 @@
+package warmpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testPool returns a pool with a stepped clock and recorded load calls.
+func testPool(budgetMB int) (*Pool, *[]string, *[]string) {
+	p := NewPool(budgetMB)
+	at := time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC)
+	p.now = func() time.Time {
+		at = at.Add(time.Second)
+		return at
+	}
+	var loads, unloads []string
+	p.Load = func(_ context.Context, model string) error {
+		loads = append(loads, model)
+		return nil
+	}
+	p.Unload = func(_ context.Context, model string) error {
+		unloads = append(unloads, model)
+		return nil
+	}
+	return p, &loads, &unloads
+}
+
+func TestPreloadAndStatus(t *testing.T) {
+	p, loads, _ := testPool(0)
+	p.Register("llama3:8b", 5000, true)
+	p.Register("qwen:14b", 9000, false)
+	if err := p.PreloadAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(*loads) != 1 || (*loads)[0] != "llama3:8b" {
+		t.Fatalf("loads = %v", *loads)
+	}
+	if !p.Hot("llama3:8b") || p.Hot("qwen:14b") {
+		t.Fatal("load state not reflected")
+	}
+	if p.ETA("llama3:8b") != 0 {
+		t.Fatalf("loaded ETA = %v", p.ETA("llama3:8b"))
+	}
+	if p.ETA("qwen:14b") == 0 {
+		t.Fatal("cold model has no ETA")
+	}
+	// Unmanaged models are the pool's business only when registered.
+	if !p.Hot("gpt-4o") {
+		t.Fatal("unknown model reported cold")
+	}
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	p, _, unloads := testPool(10000)
+	p.Register("a", 4000, false)
+	p.Register("b", 4000, false)
+	p.Register("c", 4000, false)
+	ctx := context.Background()
+	if err := p.EnsureLoaded(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.EnsureLoaded(ctx, "b"); err != nil {
+		t.Fatal(err)
+	}
+	p.Touch("a") // b is now the least recently used
+
+	if err := p.EnsureLoaded(ctx, "c"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*unloads) != 1 || (*unloads)[0] != "b" {
+		t.Fatalf("unloads = %v", *unloads)
+	}
+	if p.Hot("b") || !p.Hot("a") || !p.Hot("c") {
+		t.Fatal("eviction state wrong")
+	}
+}
+
+func TestModelLargerThanBudget(t *testing.T) {
+	p, _, _ := testPool(4000)
+	p.Register("huge", 9000, false)
+	if err := p.EnsureLoaded(context.Background(), "huge"); err == nil {
+		t.Fatal("oversized model loaded")
+	}
+}
+
+func TestLoadFailureStaysCold(t *testing.T) {
+	p, _, _ := testPool(0)
+	p.Load = func(context.Context, string) error { return errors.New("runtime down") }
+	p.Register("a", 1000, false)
+	if err := p.EnsureLoaded(context.Background(), "a"); err == nil {
+		t.Fatal("failed load reported success")
+	}
+	if p.Hot("a") {
+		t.Fatal("failed load left model marked hot")
+	}
+}
+
+func TestUnknownModel(t *testing.T) {
+	p, _, _ := testPool(0)
+	if err := p.EnsureLoaded(context.Background(), "ghost"); !errors.Is(err, ErrUnknownModel) {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Package warmup persists periodic snapshots of in-memory routing state
+// (reliability scores, quarantine status, anything else that learns from
+// traffic) so a restarted instance resumes with yesterday's knowledge
+// instead of routing blind until the caches refill.
+package warmup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Snapshotter is any subsystem whose learned state can be serialized and
+// loaded back. The reliability scorer and quarantine tracker implement
+// it; new subsystems opt in by registering.
+type Snapshotter interface {
+	Snapshot() ([]byte, error)
+	Restore(data []byte) error
+}
+
+// file is the on-disk shape: one payload per registered subsystem, keyed
+// by name so subsystems can come and go between versions.
+type file struct {
+	TakenAt    time.Time                  `json:"taken_at"`
+	Subsystems map[string]json.RawMessage `json:"subsystems"`
+}
+
+// Store writes all registered subsystems to a single JSON file and
+// restores them at startup.
+type Store struct {
+	path string
+	now  func() time.Time
+
+	mu         sync.Mutex
+	subsystems map[string]Snapshotter
+	takenAt    time.Time
+}
+
+// NewStore creates a store persisting to path. Nothing is read until
+// Load is called, after every subsystem has registered.
+func NewStore(path string) *Store {
+	return &Store{
+		path:       path,
+		now:        time.Now,
+		subsystems: make(map[string]Snapshotter),
+	}
+}
+
+// Register adds a named subsystem. Names must be stable across restarts;
+// they key the payloads in the snapshot file.
+func (s *Store) Register(name string, sub Snapshotter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subsystems[name] = sub
+}
+
+// Load restores every registered subsystem from the last snapshot. Call
+// it at startup before accepting traffic. A missing file is a fresh
+// install, not an error; a subsystem that fails to restore is logged and
+// skipped so one bad payload does not block startup.
+func (s *Store) Load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("warmup: read snapshot: %w", err)
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("warmup: parse snapshot: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.takenAt = f.TakenAt
+	for name, sub := range s.subsystems {
+		payload, ok := f.Subsystems[name]
+		if !ok {
+			continue
+		}
+		if err := sub.Restore(payload); err != nil {
+			log.Printf("warmup: restoring %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// Save snapshots every registered subsystem to disk. Its signature
+// matches the scheduler's JobFunc so it can run periodically.
+func (s *Store) Save(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := file{TakenAt: s.now(), Subsystems: make(map[string]json.RawMessage, len(s.subsystems))}
+	for name, sub := range s.subsystems {
+		payload, err := sub.Snapshot()
+		if err != nil {
+			return fmt.Errorf("warmup: snapshotting %s: %w", name, err)
+		}
+		f.Subsystems[name] = payload
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("warmup: write snapshot: %w", err)
+	}
+	s.takenAt = f.TakenAt
+	return nil
+}
+
+// TakenAt reports when the current snapshot was taken: the loaded one at
+// startup, the last saved one after. Zero when no snapshot exists yet.
+func (s *Store) TakenAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.takenAt
+}
@@ -0,0 +1,95 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/quarantine"
+	"github.com/biodoia/goleapifree/internal/reliability"
+)
+
+func TestSaveAndLoadRestoresSubsystems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "warmup.json")
+
+	scorer := reliability.NewScorer()
+	for i := 0; i < 8; i++ {
+		scorer.RecordSuccess("good")
+		scorer.RecordFailure("bad", "http_5xx")
+	}
+	tracker := quarantine.NewTracker(quarantine.Config{Cooldown: time.Hour})
+	for i := 0; i < 3; i++ {
+		tracker.Record("bad", quarantine.Verdict{Score: 0, Reason: "empty body"})
+	}
+
+	store := NewStore(path)
+	store.Register("reliability", scorer)
+	store.Register("quarantine", tracker)
+	if err := store.Save(context.Background()); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if store.TakenAt().IsZero() {
+		t.Fatal("TakenAt not recorded after save")
+	}
+
+	// A fresh process registers empty subsystems and loads the snapshot
+	// before taking traffic.
+	scorer2 := reliability.NewScorer()
+	tracker2 := quarantine.NewTracker(quarantine.Config{Cooldown: time.Hour})
+	store2 := NewStore(path)
+	store2.Register("reliability", scorer2)
+	store2.Register("quarantine", tracker2)
+	if err := store2.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if good, bad := scorer2.Score("good"), scorer2.Score("bad"); good <= bad {
+		t.Fatalf("restored scores not warm: good=%f bad=%f", good, bad)
+	}
+	if !tracker2.Quarantined("bad") {
+		t.Fatal("quarantine not restored")
+	}
+	if store2.TakenAt().IsZero() {
+		t.Fatal("TakenAt not loaded from snapshot")
+	}
+}
+
+func TestLoadMissingFileIsFreshInstall(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "missing.json"))
+	store.Register("reliability", reliability.NewScorer())
+	if err := store.Load(); err != nil {
+		t.Fatalf("Load on fresh install: %v", err)
+	}
+	if !store.TakenAt().IsZero() {
+		t.Fatal("TakenAt set without a snapshot")
+	}
+}
+
+// failingSnapshotter always errors, to exercise both failure paths.
+type failingSnapshotter struct{}
+
+func (failingSnapshotter) Snapshot() ([]byte, error) { return nil, errors.New("boom") }
+func (failingSnapshotter) Restore([]byte) error      { return errors.New("boom") }
+
+func TestSaveSurfacesSubsystemErrors(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "warmup.json"))
+	store.Register("broken", failingSnapshotter{})
+	if err := store.Save(context.Background()); err == nil {
+		t.Fatal("Save swallowed a snapshot error")
+	}
+}
+
+func TestLoadSkipsBadSubsystemPayload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "warmup.json")
+	payload := `{"taken_at":"2026-09-01T00:00:00Z","subsystems":{"reliability":"not json for this type","other":{}}}`
+	if err := os.WriteFile(path, []byte(payload), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := NewStore(path)
+	store.Register("reliability", reliability.NewScorer())
+	if err := store.Load(); err != nil {
+		t.Fatalf("Load should skip a bad subsystem payload, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Package watermark optionally embeds attribution markers in responses so
+// downstream teams can trace which alias and request produced an
+// artifact. Markers are additive and never alter the model's own text
+// outside of appended comments.
+package watermark
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Modes select where the marker goes.
+const (
+	// ModeMetadata adds a metadata field to the response envelope only.
+	ModeMetadata = "metadata"
+	// ModeComment additionally appends an attribution comment at the end
+	// of each fenced code block.
+	ModeComment = "comment"
+)
+
+// Config enables watermarking per deployment or tenant.
+type Config struct {
+	Enabled bool   `yaml:"enabled"`
+	Mode    string `yaml:"mode"` // metadata | comment
+	// Label prefixes the marker; defaults to "goleapai".
+	Label string `yaml:"label"`
+}
+
+// Metadata is the envelope field ModeMetadata injects, keyed under
+// "x_attribution" in the response JSON.
+type Metadata struct {
+	Alias     string `json:"alias"`
+	RequestID string `json:"request_id"`
+}
+
+// commentSyntax maps fence languages to single-line comment prefixes.
+var commentSyntax = map[string]string{
+	"go": "//", "js": "//", "javascript": "//", "ts": "//", "typescript": "//",
+	"java": "//", "c": "//", "cpp": "//", "rust": "//",
+	"py": "#", "python": "#", "sh": "#", "bash": "#", "ruby": "#", "yaml": "#",
+	"sql": "--", "lua": "--",
+	"html": "<!--", "xml": "<!--",
+}
+
+var fenceRe = regexp.MustCompile("(?s)```([a-zA-Z0-9]*)\n(.*?)```")
+
+// Marker renders the attribution string embedded in comments.
+func (c Config) Marker(alias, requestID string) string {
+	label := c.Label
+	if label == "" {
+		label = "goleapai"
+	}
+	return fmt.Sprintf("%s: %s %s", label, alias, requestID)
+}
+
+// Annotate applies the configured watermark to completion text, returning
+// it unchanged when disabled or in metadata-only mode.
+func (c Config) Annotate(content, alias, requestID string) string {
+	if !c.Enabled || c.Mode != ModeComment {
+		return content
+	}
+	marker := c.Marker(alias, requestID)
+	return fenceRe.ReplaceAllStringFunc(content, func(block string) string {
+		m := fenceRe.FindStringSubmatch(block)
+		lang, body := strings.ToLower(m[1]), m[2]
+		prefix, ok := commentSyntax[lang]
+		if !ok {
+			// Unknown language: skip rather than risk breaking syntax.
+			return block
+		}
+		comment := prefix + " " + marker
+		if prefix == "<!--" {
+			comment = "<!-- " + marker + " -->"
+		}
+		if !strings.HasSuffix(body, "\n") {
+			body += "\n"
+		}
+		return "```" + m[1] + "\n" + body + comment + "\n```"
+	})
+}
+
+// MetadataFor returns the envelope metadata, or nil when disabled.
+func (c Config) MetadataFor(alias, requestID string) *Metadata {
+	if !c.Enabled {
+		return nil
+	}
+	return &Metadata{Alias: alias, RequestID: requestID}
+}
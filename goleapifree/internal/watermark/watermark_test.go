@@ -0,0 +1,48 @@
+package watermark
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnnotateCodeBlocks(t *testing.T) {
+	c := Config{Enabled: true, Mode: ModeComment}
+	in := "Here you go:\n```go\nfunc main() {}\n```\nand python:\n```python\nprint(1)\n```"
+	out := c.Annotate(in, "fast", "req-1")
+	if !strings.Contains(out, "// goleapai: fast req-1\n```") {
+		t.Fatalf("go block not annotated:\n%s", out)
+	}
+	if !strings.Contains(out, "# goleapai: fast req-1") {
+		t.Fatalf("python block not annotated:\n%s", out)
+	}
+	// Prose outside fences must be untouched.
+	if !strings.HasPrefix(out, "Here you go:\n") {
+		t.Fatalf("prose altered:\n%s", out)
+	}
+}
+
+func TestAnnotateSkipsUnknownLanguages(t *testing.T) {
+	c := Config{Enabled: true, Mode: ModeComment}
+	in := "```brainfuck\n+++\n```"
+	if out := c.Annotate(in, "fast", "req-1"); out != in {
+		t.Fatalf("unknown language block altered:\n%s", out)
+	}
+}
+
+func TestDisabledAndMetadataModes(t *testing.T) {
+	in := "```go\nx\n```"
+	if out := (Config{}).Annotate(in, "a", "r"); out != in {
+		t.Fatal("disabled config altered content")
+	}
+	meta := Config{Enabled: true, Mode: ModeMetadata}
+	if out := meta.Annotate(in, "a", "r"); out != in {
+		t.Fatal("metadata mode altered content")
+	}
+	m := meta.MetadataFor("fast", "req-1")
+	if m == nil || m.Alias != "fast" || m.RequestID != "req-1" {
+		t.Fatalf("metadata = %+v", m)
+	}
+	if (Config{}).MetadataFor("a", "r") != nil {
+		t.Fatal("disabled config produced metadata")
+	}
+}
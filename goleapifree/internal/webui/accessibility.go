@@ -0,0 +1,136 @@
+//go:build !edge
+
+// Package webui renders the gateway's web pages. The default look wraps
+// TUI-style CP437 output in <pre> blocks, which screen readers cannot
+// navigate; this file adds an accessible mode that renders the same data
+// as semantic HTML instead.
+package webui
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Preferences are the per-user accessibility settings, persisted in the
+// "a11y" cookie and toggleable from the settings menu.
+type Preferences struct {
+	// Accessible switches from the CP437 <pre> rendering to semantic
+	// HTML with ARIA labels and keyboard-focusable rows.
+	Accessible bool
+	// ReducedMotion disables the refresh animations and marquee-style
+	// tickers.
+	ReducedMotion bool
+}
+
+// ParsePreferences decodes the a11y cookie value, a comma-separated flag
+// list (e.g. "accessible,reduced-motion"). Unknown flags are ignored so
+// old cookies survive upgrades.
+func ParsePreferences(cookie string) Preferences {
+	var p Preferences
+	for _, flag := range strings.Split(cookie, ",") {
+		switch strings.TrimSpace(flag) {
+		case "accessible":
+			p.Accessible = true
+		case "reduced-motion":
+			p.ReducedMotion = true
+		}
+	}
+	return p
+}
+
+// CookieValue serializes the preferences back into the a11y cookie.
+func (p Preferences) CookieValue() string {
+	var flags []string
+	if p.Accessible {
+		flags = append(flags, "accessible")
+	}
+	if p.ReducedMotion {
+		flags = append(flags, "reduced-motion")
+	}
+	return strings.Join(flags, ",")
+}
+
+// BodyClass returns the CSS classes the page body should carry; the
+// stylesheet keys reduced-motion off this to drop animations.
+func (p Preferences) BodyClass() string {
+	var classes []string
+	if p.Accessible {
+		classes = append(classes, "accessible")
+	}
+	if p.ReducedMotion {
+		classes = append(classes, "reduced-motion")
+	}
+	return strings.Join(classes, " ")
+}
+
+// Table is a data table to render in either mode.
+type Table struct {
+	// Caption describes the table for assistive technology.
+	Caption string
+	Headers []string
+	Rows    [][]string
+}
+
+// Render produces the table in the mode the preferences select: a
+// semantic <table> with scoped headers and a caption for screen readers,
+// or the legacy monospace block for the CP437 look.
+func (t Table) Render(p Preferences) string {
+	if p.Accessible {
+		return t.renderSemantic()
+	}
+	return t.renderMonospace()
+}
+
+func (t Table) renderSemantic() string {
+	var b strings.Builder
+	b.WriteString(`<table role="table">`)
+	if t.Caption != "" {
+		fmt.Fprintf(&b, "<caption>%s</caption>", html.EscapeString(t.Caption))
+	}
+	b.WriteString("<thead><tr>")
+	for _, h := range t.Headers {
+		fmt.Fprintf(&b, `<th scope="col">%s</th>`, html.EscapeString(h))
+	}
+	b.WriteString("</tr></thead><tbody>")
+	for _, row := range t.Rows {
+		// tabindex makes rows reachable with keyboard navigation.
+		b.WriteString(`<tr tabindex="0">`)
+		for _, cell := range row {
+			fmt.Fprintf(&b, "<td>%s</td>", html.EscapeString(cell))
+		}
+		b.WriteString("</tr>")
+	}
+	b.WriteString("</tbody></table>")
+	return b.String()
+}
+
+func (t Table) renderMonospace() string {
+	widths := make([]int, len(t.Headers))
+	for i, h := range t.Headers {
+		widths[i] = len(h)
+	}
+	for _, row := range t.Rows {
+		for i, cell := range row {
+			if i < len(widths) && len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	var b strings.Builder
+	b.WriteString("<pre aria-hidden=\"true\">")
+	writeRow := func(cells []string) {
+		for i, cell := range cells {
+			if i < len(widths) {
+				fmt.Fprintf(&b, "%-*s  ", widths[i], html.EscapeString(cell))
+			}
+		}
+		b.WriteString("\n")
+	}
+	writeRow(t.Headers)
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+	b.WriteString("</pre>")
+	return b.String()
+}
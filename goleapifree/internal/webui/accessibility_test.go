@@ -0,0 +1,46 @@
+//go:build !edge
+
+package webui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePreferencesRoundTrip(t *testing.T) {
+	p := ParsePreferences("accessible, reduced-motion, future-flag")
+	if !p.Accessible || !p.ReducedMotion {
+		t.Fatalf("parsed = %+v", p)
+	}
+	if got := p.CookieValue(); got != "accessible,reduced-motion" {
+		t.Fatalf("cookie = %q", got)
+	}
+	if got := ParsePreferences("").BodyClass(); got != "" {
+		t.Fatalf("empty prefs body class = %q", got)
+	}
+}
+
+func TestRenderSemanticTable(t *testing.T) {
+	tbl := Table{
+		Caption: "Providers",
+		Headers: []string{"Name", "Status"},
+		Rows:    [][]string{{"openrouter", "healthy"}, {"<script>", "down"}},
+	}
+	out := tbl.Render(Preferences{Accessible: true})
+	for _, want := range []string{"<caption>Providers</caption>", `<th scope="col">Name</th>`, `<tr tabindex="0">`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("semantic output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<script>") {
+		t.Fatal("cell content not escaped")
+	}
+}
+
+func TestRenderMonospaceFallback(t *testing.T) {
+	tbl := Table{Headers: []string{"Name"}, Rows: [][]string{{"openrouter"}}}
+	out := tbl.Render(Preferences{})
+	if !strings.HasPrefix(out, `<pre aria-hidden="true">`) {
+		t.Fatalf("monospace output = %q", out)
+	}
+}
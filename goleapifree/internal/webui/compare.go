@@ -0,0 +1,55 @@
+//go:build !edge
+
+package webui
+
+import (
+	"fmt"
+
+	"github.com/biodoia/goleapifree/internal/stats"
+)
+
+// CompareRow is one rendered row of the range comparison page: both
+// values side by side with the computed change, so nobody eyeballs two
+// charts.
+type CompareRow struct {
+	Key       string `json:"key"`
+	Requests  string `json:"requests"`
+	Cost      string `json:"cost"`
+	P95       string `json:"p95"`
+	ErrorRate string `json:"error_rate"`
+	// Worse flags rows whose error rate or p95 latency regressed, for
+	// highlighting.
+	Worse bool `json:"worse"`
+}
+
+// CompareView renders a comparison report as table rows, the overall
+// total first.
+func CompareView(report stats.CompareReport) []CompareRow {
+	rows := make([]CompareRow, 0, len(report.Providers)+1)
+	rows = append(rows, compareRow(report.Total))
+	for _, kc := range report.Providers {
+		rows = append(rows, compareRow(kc))
+	}
+	return rows
+}
+
+func compareRow(kc stats.KeyComparison) CompareRow {
+	return CompareRow{
+		Key:       kc.Key,
+		Requests:  changeCell(float64(kc.A.Requests), float64(kc.B.Requests), kc.Deltas.Requests, "%.0f"),
+		Cost:      changeCell(kc.A.Cost, kc.B.Cost, kc.Deltas.Cost, "$%.2f"),
+		P95:       changeCell(kc.A.P95LatencyMs, kc.B.P95LatencyMs, kc.Deltas.P95LatencyMs, "%.0fms"),
+		ErrorRate: changeCell(kc.A.ErrorRate*100, kc.B.ErrorRate*100, kc.Deltas.ErrorRate, "%.1f%%"),
+		Worse:     kc.Deltas.ErrorRate.Abs > 0 || kc.Deltas.P95LatencyMs.Abs > 0,
+	}
+}
+
+// changeCell renders "a → b (+pct%)"; the percent part is dropped when
+// range A has no baseline to compare against.
+func changeCell(a, b float64, d stats.Delta, valueFormat string) string {
+	cell := fmt.Sprintf(valueFormat+" → "+valueFormat, a, b)
+	if d.Pct != 0 {
+		cell += fmt.Sprintf(" (%+.1f%%)", d.Pct)
+	}
+	return cell
+}
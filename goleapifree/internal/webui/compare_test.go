@@ -0,0 +1,60 @@
+//go:build !edge
+
+package webui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/biodoia/goleapifree/internal/stats"
+)
+
+func TestCompareViewRendersDeltas(t *testing.T) {
+	report := stats.CompareReport{
+		Total: stats.KeyComparison{
+			Key: "total",
+			A:   stats.RangeSummary{Requests: 10, Cost: 1},
+			B:   stats.RangeSummary{Requests: 20, Cost: 1.5},
+			Deltas: stats.Deltas{
+				Requests: stats.Delta{Abs: 10, Pct: 100},
+				Cost:     stats.Delta{Abs: 0.5, Pct: 50},
+			},
+		},
+		Providers: []stats.KeyComparison{{
+			Key:    "p1",
+			A:      stats.RangeSummary{ErrorRate: 0.01},
+			B:      stats.RangeSummary{ErrorRate: 0.05},
+			Deltas: stats.Deltas{ErrorRate: stats.Delta{Abs: 0.04, Pct: 400}},
+		}},
+	}
+
+	rows := CompareView(report)
+	if len(rows) != 2 || rows[0].Key != "total" {
+		t.Fatalf("rows = %+v", rows)
+	}
+	if rows[0].Requests != "10 → 20 (+100.0%)" {
+		t.Fatalf("requests cell = %q", rows[0].Requests)
+	}
+	if !strings.HasPrefix(rows[0].Cost, "$1.00 → $1.50") {
+		t.Fatalf("cost cell = %q", rows[0].Cost)
+	}
+	if rows[0].Worse {
+		t.Fatal("total row flagged worse without a regression")
+	}
+	if !rows[1].Worse {
+		t.Fatal("error-rate regression not flagged")
+	}
+}
+
+func TestCompareViewOmitsPctWithoutBaseline(t *testing.T) {
+	rows := CompareView(stats.CompareReport{
+		Total: stats.KeyComparison{
+			Key:    "total",
+			B:      stats.RangeSummary{Requests: 5},
+			Deltas: stats.Deltas{Requests: stats.Delta{Abs: 5}},
+		},
+	})
+	if rows[0].Requests != "0 → 5" {
+		t.Fatalf("requests cell = %q", rows[0].Requests)
+	}
+}
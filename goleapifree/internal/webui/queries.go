@@ -0,0 +1,194 @@
+//go:build !edge
+
+package webui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/stats"
+)
+
+// ErrQueryNotFound is returned for unknown saved query IDs.
+var ErrQueryNotFound = errors.New("webui: saved query not found")
+
+// Chart types a saved query can render as.
+const (
+	ChartLine    = "line"
+	ChartBar     = "bar"
+	ChartHeatmap = "heatmap"
+)
+
+// SavedQuery is one operator-defined stat query pinned to the custom
+// dashboard page.
+type SavedQuery struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Dimension selects the series: "provider", "tenant" or "tag".
+	Dimension string `json:"dimension"`
+	// Filter is the dimension value; for tags it is "key=value".
+	Filter string `json:"filter"`
+	// Metric is "requests", "tokens", "cost" or "p95".
+	Metric string `json:"metric"`
+	Chart  string `json:"chart"`
+	Pinned bool   `json:"pinned"`
+}
+
+// validate rejects queries the dashboard cannot execute.
+func (q SavedQuery) validate() error {
+	switch q.Dimension {
+	case "provider", "tenant", "tag":
+	default:
+		return fmt.Errorf("webui: unknown dimension %q", q.Dimension)
+	}
+	switch q.Metric {
+	case "requests", "tokens", "cost", "p95":
+	default:
+		return fmt.Errorf("webui: unknown metric %q", q.Metric)
+	}
+	switch q.Chart {
+	case ChartLine, ChartBar, ChartHeatmap:
+	default:
+		return fmt.Errorf("webui: unknown chart type %q", q.Chart)
+	}
+	return nil
+}
+
+// QueryStore persists saved queries as a JSON file, mirroring the
+// snapshot pattern the stats collector uses.
+type QueryStore struct {
+	Path string
+
+	mu      sync.Mutex
+	queries map[string]SavedQuery
+}
+
+// NewQueryStore loads existing queries from path; a missing file is an
+// empty store.
+func NewQueryStore(path string) (*QueryStore, error) {
+	s := &QueryStore{Path: path, queries: make(map[string]SavedQuery)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.queries); err != nil {
+		return nil, fmt.Errorf("webui: corrupt saved queries file: %w", err)
+	}
+	return s, nil
+}
+
+// Save upserts a query and persists the store. Empty IDs get one derived
+// from the name.
+func (s *QueryStore) Save(q SavedQuery) (SavedQuery, error) {
+	if err := q.validate(); err != nil {
+		return SavedQuery{}, err
+	}
+	if q.ID == "" {
+		q.ID = fmt.Sprintf("q-%d", time.Now().UnixNano())
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries[q.ID] = q
+	return q, s.flush()
+}
+
+// Delete removes a query and persists the store.
+func (s *QueryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.queries[id]; !ok {
+		return ErrQueryNotFound
+	}
+	delete(s.queries, id)
+	return s.flush()
+}
+
+// List returns all saved queries, pinned first, then by name.
+func (s *QueryStore) List() []SavedQuery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]SavedQuery, 0, len(s.queries))
+	for _, q := range s.queries {
+		out = append(out, q)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Pinned != out[j].Pinned {
+			return out[i].Pinned
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// flush writes the store to disk. Caller holds s.mu.
+func (s *QueryStore) flush() error {
+	if s.Path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.queries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0o644)
+}
+
+// Point is one chart data point.
+type Point struct {
+	At    time.Time `json:"at"`
+	Value float64   `json:"value"`
+}
+
+// Run executes a saved query against the analyzer and returns the chart
+// series.
+func Run(q SavedQuery, a *stats.Analyzer) ([]Point, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	var buckets []stats.Bucket
+	switch q.Dimension {
+	case "provider":
+		buckets = a.ProviderBuckets(q.Filter)
+	case "tenant":
+		buckets = a.TenantBuckets(q.Filter)
+	case "tag":
+		key, value, ok := cutTag(q.Filter)
+		if !ok {
+			return nil, fmt.Errorf(`webui: tag filter must be "key=value", got %q`, q.Filter)
+		}
+		buckets = a.TagBuckets(key, value)
+	}
+	out := make([]Point, 0, len(buckets))
+	for i := range buckets {
+		b := &buckets[i]
+		p := Point{At: b.Start}
+		switch q.Metric {
+		case "requests":
+			p.Value = float64(b.Requests)
+		case "tokens":
+			p.Value = float64(b.Tokens)
+		case "cost":
+			p.Value = b.Cost
+		case "p95":
+			p.Value = b.LatencyPercentile(95)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func cutTag(filter string) (key, value string, ok bool) {
+	for i := 0; i < len(filter); i++ {
+		if filter[i] == '=' {
+			return filter[:i], filter[i+1:], i > 0
+		}
+	}
+	return "", "", false
+}
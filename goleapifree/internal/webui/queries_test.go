@@ -0,0 +1,71 @@
+//go:build !edge
+
+package webui
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/biodoia/goleapifree/internal/stats"
+)
+
+func TestQueryStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queries.json")
+	s, err := NewQueryStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := s.Save(SavedQuery{Name: "checkout cost", Dimension: "tag", Filter: "feature=checkout", Metric: "cost", Chart: ChartLine, Pinned: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Save(SavedQuery{Name: "all", Dimension: "provider", Filter: "openrouter", Metric: "requests", Chart: ChartBar}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewQueryStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := reloaded.List()
+	if len(list) != 2 || list[0].ID != q.ID {
+		t.Fatalf("list = %+v, want pinned query first", list)
+	}
+	if err := reloaded.Delete("nope"); !errors.Is(err, ErrQueryNotFound) {
+		t.Fatalf("err = %v, want ErrQueryNotFound", err)
+	}
+	if err := reloaded.Delete(q.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSaveRejectsInvalidQueries(t *testing.T) {
+	s, _ := NewQueryStore("")
+	bad := []SavedQuery{
+		{Dimension: "galaxy", Metric: "cost", Chart: ChartLine},
+		{Dimension: "provider", Metric: "vibes", Chart: ChartLine},
+		{Dimension: "provider", Metric: "cost", Chart: "pie"},
+	}
+	for _, q := range bad {
+		if _, err := s.Save(q); err == nil {
+			t.Fatalf("Save(%+v) accepted invalid query", q)
+		}
+	}
+}
+
+func TestRunTagQuery(t *testing.T) {
+	a := stats.NewAnalyzer(24 * time.Hour)
+	a.Record(stats.Sample{Provider: "p", Tokens: 10, Cost: 0.5, Metadata: map[string]string{"feature": "search"}})
+	points, err := Run(SavedQuery{Dimension: "tag", Filter: "feature=search", Metric: "cost", Chart: ChartLine}, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 1 || points[0].Value != 0.5 {
+		t.Fatalf("points = %+v", points)
+	}
+	if _, err := Run(SavedQuery{Dimension: "tag", Filter: "malformed", Metric: "cost", Chart: ChartLine}, a); err == nil {
+		t.Fatal("malformed tag filter accepted")
+	}
+}